@@ -1,5 +1,12 @@
 package httpexpect
 
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
 // AssertionType defines type of performed assertion.
 type AssertionType uint64
 
@@ -84,6 +91,37 @@ const (
 	AssertNotBelongs
 )
 
+// AssertionSeverity defines how failure of assertion affects the test.
+//
+// Assertions have SeverityError by default. Assertions made via instances
+// returned by AsWarning methods (e.g. Response.AsWarning) have
+// SeverityWarning: their failures are passed to AssertionHandler with
+// IsFatal set to false, so they're logged and counted by
+// DefaultAssertionHandler, but don't fail the test.
+//
+// Failed warning assertion still stops the chain it was made on, but
+// doesn't affect the instance on which AsWarning was called.
+type AssertionSeverity uint
+
+const (
+	// Failure fails the test
+	SeverityError AssertionSeverity = iota
+
+	// Failure is logged and counted, but doesn't fail the test
+	SeverityWarning
+)
+
+// String returns name of severity level.
+func (s AssertionSeverity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	}
+	return "AssertionSeverity(" + strconv.FormatUint(uint64(s), 10) + ")"
+}
+
 // AssertionContext provides context where the assetion happened.
 type AssertionContext struct {
 	// Name of the running test
@@ -107,6 +145,15 @@ type AssertionContext struct {
 	// May be nil if response was not yet received
 	Response *Response
 
+	// Round-trip time of the response being matched
+	// May be nil if response was not yet received or if
+	// round-trip time is unknown
+	RoundTripTime *time.Duration
+
+	// WebSocket connection being matched
+	// May be nil if assertion is not related to WebSocket connection
+	Websocket *Websocket
+
 	// Environment shared between tests
 	// Comes from Expect instance
 	Environment *Environment
@@ -147,6 +194,10 @@ type AssertionFailure struct {
 	// Defines if failure should be reported as fatal
 	IsFatal bool
 
+	// Severity of failed assertion
+	// If it's SeverityWarning, IsFatal is always false
+	Severity AssertionSeverity
+
 	// List of error messages
 	Errors []error
 
@@ -200,10 +251,26 @@ type AssertionHandler interface {
 //
 // Formatter and Reporter are required. Logger is optional.
 // By default httpexpect creates DefaultAssertionHandler without Logger.
+//
+// Failures with SeverityWarning are never reported. They're printed to
+// Logger, or to Reporter if Logger is nil and Reporter implements Logger
+// (e.g. testing.T).
+//
+// DefaultAssertionHandler counts failures of every severity; counts are
+// available via Errors and Warnings methods.
 type DefaultAssertionHandler struct {
 	Formatter Formatter
 	Reporter  Reporter
 	Logger    Logger
+
+	mu     sync.Mutex
+	counts *assertionCounts
+}
+
+// shared between DefaultAssertionHandler and its forks
+type assertionCounts struct {
+	errors   int64
+	warnings int64
 }
 
 // Success implements AssertionHandler.Success.
@@ -229,7 +296,22 @@ func (h *DefaultAssertionHandler) Failure(
 		panic("DefaultAssertionHandler.Formatter is nil")
 	}
 
-	if failure.IsFatal {
+	h.count(failure.Severity)
+
+	if failure.Severity == SeverityWarning {
+		logger := h.Logger
+		if logger == nil {
+			logger, _ = h.Reporter.(Logger)
+		}
+
+		if logger == nil {
+			return
+		}
+
+		msg := h.Formatter.FormatFailure(ctx, failure)
+
+		logger.Logf("%s", msg)
+	} else if failure.IsFatal {
 		if h.Reporter == nil {
 			panic("DefaultAssertionHandler.Reporter is nil")
 		}
@@ -247,3 +329,105 @@ func (h *DefaultAssertionHandler) Failure(
 		h.Logger.Logf("%s", msg)
 	}
 }
+
+// Errors returns number of failures with SeverityError, both fatal and
+// non-fatal, handled so far.
+func (h *DefaultAssertionHandler) Errors() int {
+	return int(atomic.LoadInt64(&h.getCounts().errors))
+}
+
+// Warnings returns number of failures with SeverityWarning handled so far.
+func (h *DefaultAssertionHandler) Warnings() int {
+	return int(atomic.LoadInt64(&h.getCounts().warnings))
+}
+
+func (h *DefaultAssertionHandler) count(severity AssertionSeverity) {
+	if severity == SeverityWarning {
+		atomic.AddInt64(&h.getCounts().warnings, 1)
+	} else {
+		atomic.AddInt64(&h.getCounts().errors, 1)
+	}
+}
+
+func (h *DefaultAssertionHandler) getCounts() *assertionCounts {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.counts == nil {
+		h.counts = &assertionCounts{}
+	}
+
+	return h.counts
+}
+
+// returns copy of handler with given reporter, sharing failure counts
+func (h *DefaultAssertionHandler) fork(reporter Reporter) *DefaultAssertionHandler {
+	return &DefaultAssertionHandler{
+		Formatter: h.Formatter,
+		Reporter:  reporter,
+		Logger:    h.Logger,
+		counts:    h.getCounts(),
+	}
+}
+
+// TeeAssertionHandler is AssertionHandler that passes every assertion to
+// multiple handlers.
+//
+// Handlers are invoked in order of appearance, with the same context and
+// failure. Every handler is invoked even if a previous one panics or stops
+// the test via FailNow (e.g. when it uses RequireReporter); in this case,
+// panic or test stop happens after all handlers are invoked.
+//
+// Usually you don't need to construct it manually and can use
+// Config.AssertionHandlers instead.
+//
+// Example:
+//
+//	handler := &httpexpect.TeeAssertionHandler{
+//	    Handlers: []httpexpect.AssertionHandler{
+//	        &httpexpect.JSONAssertionHandler{Writer: logFile},
+//	        &httpexpect.DefaultAssertionHandler{
+//	            Formatter: &httpexpect.DefaultFormatter{},
+//	            Reporter:  httpexpect.NewAssertReporter(t),
+//	        },
+//	    },
+//	}
+type TeeAssertionHandler struct {
+	Handlers []AssertionHandler
+}
+
+// Success implements AssertionHandler.Success.
+func (h *TeeAssertionHandler) Success(ctx *AssertionContext) {
+	teeSuccess(h.Handlers, ctx)
+}
+
+// Failure implements AssertionHandler.Failure.
+func (h *TeeAssertionHandler) Failure(
+	ctx *AssertionContext, failure *AssertionFailure,
+) {
+	teeFailure(h.Handlers, ctx, failure)
+}
+
+// remaining handlers are invoked from defer, so that they're invoked even
+// if current handler panics or calls runtime.Goexit
+func teeSuccess(handlers []AssertionHandler, ctx *AssertionContext) {
+	if len(handlers) == 0 {
+		return
+	}
+
+	defer teeSuccess(handlers[1:], ctx)
+
+	handlers[0].Success(ctx)
+}
+
+func teeFailure(
+	handlers []AssertionHandler, ctx *AssertionContext, failure *AssertionFailure,
+) {
+	if len(handlers) == 0 {
+		return
+	}
+
+	defer teeFailure(handlers[1:], ctx, failure)
+
+	handlers[0].Failure(ctx, failure)
+}
@@ -0,0 +1,178 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AssertionFunc is a custom assertion, used by Value.Assert and Value.Check.
+//
+// It receives raw value being checked and returns nil if the value satisfies
+// the assertion, or error describing the problem. Value has the same types as
+// Value.Raw, i.e. nil, bool, float64, string, []interface{}, or
+// map[string]interface{}.
+//
+// By default, failure has AssertValid type, and error is reported after
+// a message with assertion name. To report different AssertionType or an
+// expected value, return *AssertionError.
+//
+// Reusable assertions can be registered by name in Config.Assertions.
+//
+// Example:
+//
+//	func IsCountryCode(value interface{}) error {
+//	    s, ok := value.(string)
+//	    if !ok {
+//	        return errors.New("value is not a string")
+//	    }
+//	    if !countries[s] {
+//	        return fmt.Errorf("unknown country code %q", s)
+//	    }
+//	    return nil
+//	}
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:  "http://example.com",
+//	    Reporter: httpexpect.NewAssertReporter(t),
+//	    Assertions: map[string]httpexpect.AssertionFunc{
+//	        "IsCountryCode": IsCountryCode,
+//	    },
+//	})
+//
+//	e.GET("/user").Expect().
+//	    JSON().Path("$.country").Check("IsCountryCode")
+type AssertionFunc func(value interface{}) error
+
+// AssertionError may be returned by AssertionFunc to customize reported
+// failure.
+type AssertionError struct {
+	// Type of failed assertion
+	// If zero (AssertUsage), AssertValid is used
+	Type AssertionType
+
+	// Expected value, optional
+	Expected *AssertionValue
+
+	// Error message
+	Err error
+}
+
+// Error implements error.Error.
+func (e *AssertionError) Error() string {
+	if e.Err == nil {
+		return "assertion failed"
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns underlying error.
+func (e *AssertionError) Unwrap() error {
+	return e.Err
+}
+
+// Assert succeeds if given function returns nil for the value.
+//
+// Name is used in assertion path and failure message. See AssertionFunc
+// for details.
+//
+// Example:
+//
+//	value := NewValue(t, "fr")
+//	value.Assert("IsCountryCode", func(value interface{}) error {
+//	    if s, _ := value.(string); !countries[s] {
+//	        return errors.New("unknown country code")
+//	    }
+//	    return nil
+//	})
+func (v *Value) Assert(name string, fn AssertionFunc) *Value {
+	v.chain.enter("Assert(%q)", name)
+	defer v.chain.leave()
+
+	if v.chain.failed() {
+		return v
+	}
+
+	if fn == nil {
+		v.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil function argument"),
+			},
+		})
+		return v
+	}
+
+	v.checkFunc(name, fn)
+
+	return v
+}
+
+// Check succeeds if assertion registered under given name in
+// Config.Assertions succeeds for the value.
+//
+// Fails with AssertUsage if there is no such assertion. See AssertionFunc
+// for details.
+//
+// Example:
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:  "http://example.com",
+//	    Reporter: httpexpect.NewAssertReporter(t),
+//	    Assertions: map[string]httpexpect.AssertionFunc{
+//	        "IsCountryCode": IsCountryCode,
+//	    },
+//	})
+//
+//	e.Value("fr").Check("IsCountryCode")
+func (v *Value) Check(name string) *Value {
+	v.chain.enter("Check(%q)", name)
+	defer v.chain.leave()
+
+	if v.chain.failed() {
+		return v
+	}
+
+	fn := v.chain.assertions[name]
+	if fn == nil {
+		v.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{name},
+			Errors: []error{
+				fmt.Errorf("assertion %q is not registered in Config.Assertions", name),
+			},
+		})
+		return v
+	}
+
+	v.checkFunc(name, fn)
+
+	return v
+}
+
+func (v *Value) checkFunc(name string, fn AssertionFunc) {
+	if err := fn(v.value); err != nil {
+		failCustomAssertion(v.chain, name, v.value, err)
+	}
+}
+
+// reports error returned by custom assertion
+func failCustomAssertion(chain *chain, name string, actual interface{}, err error) {
+	failure := AssertionFailure{
+		Type:   AssertValid,
+		Actual: &AssertionValue{actual},
+		Errors: []error{
+			fmt.Errorf("expected: value satisfies %q", name),
+			err,
+		},
+	}
+
+	var assertionErr *AssertionError
+	if errors.As(err, &assertionErr) {
+		if assertionErr.Type != AssertUsage {
+			failure.Type = assertionErr.Type
+		}
+		failure.Expected = assertionErr.Expected
+	}
+
+	chain.fail(failure)
+}
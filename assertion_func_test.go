@@ -0,0 +1,127 @@
+package httpexpect
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func isCountryCode(value interface{}) error {
+	s, ok := value.(string)
+	if !ok {
+		return errors.New("value is not a string")
+	}
+	if s != "fr" && s != "de" {
+		return errors.New("unknown country code")
+	}
+	return nil
+}
+
+func TestValueAssert(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		var got interface{}
+
+		value := NewValue(reporter, "fr")
+		value.Assert("IsCountryCode", func(v interface{}) error {
+			got = v
+			return isCountryCode(v)
+		})
+
+		value.chain.assertOK(t)
+		assert.Equal(t, "fr", got)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		e := WithConfig(Config{
+			AssertionHandler: handler,
+		})
+
+		value := e.Value(123)
+		value.Assert("IsCountryCode", isCountryCode)
+
+		value.chain.assertFailed(t)
+
+		require.NotNil(t, handler.failure)
+		assert.Equal(t, AssertValid, handler.failure.Type)
+		assert.Equal(t, &AssertionValue{123.0}, handler.failure.Actual)
+		assert.Nil(t, handler.failure.Expected)
+
+		if assert.Equal(t, 2, len(handler.failure.Errors)) {
+			assert.Equal(t, `expected: value satisfies "IsCountryCode"`,
+				handler.failure.Errors[0].Error())
+			assert.Equal(t, "value is not a string",
+				handler.failure.Errors[1].Error())
+		}
+	})
+
+	t.Run("assertion error", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		e := WithConfig(Config{
+			AssertionHandler: handler,
+		})
+
+		value := e.Value(5)
+		value.Assert("IsEven", func(v interface{}) error {
+			return &AssertionError{
+				Type:     AssertBelongs,
+				Expected: &AssertionValue{AssertionList{2, 4, 6}},
+				Err:      errors.New("number is odd"),
+			}
+		})
+
+		value.chain.assertFailed(t)
+
+		require.NotNil(t, handler.failure)
+		assert.Equal(t, AssertBelongs, handler.failure.Type)
+		assert.Equal(t, &AssertionValue{AssertionList{2, 4, 6}},
+			handler.failure.Expected)
+		assert.Equal(t, "number is odd", handler.failure.Errors[1].Error())
+	})
+
+	t.Run("nil func", func(t *testing.T) {
+		reporter := newMockReporter(t)
+
+		value := NewValue(reporter, "fr")
+		value.Assert("IsCountryCode", nil)
+
+		value.chain.assertFailed(t)
+	})
+}
+
+func TestValueCheck(t *testing.T) {
+	handler := &mockAssertionHandler{}
+
+	e := WithConfig(Config{
+		AssertionHandler: handler,
+		Assertions: map[string]AssertionFunc{
+			"IsCountryCode": isCountryCode,
+		},
+	})
+
+	e.Value("de").Check("IsCountryCode").chain.assertOK(t)
+	assert.Nil(t, handler.failure)
+
+	e.Value(map[string]interface{}{"country": "fr"}).
+		Path("$.country").Check("IsCountryCode").chain.assertOK(t)
+	assert.Nil(t, handler.failure)
+
+	e.Value("xx").Check("IsCountryCode").chain.assertFailed(t)
+	require.NotNil(t, handler.failure)
+	assert.Equal(t, AssertValid, handler.failure.Type)
+
+	handler.failure = nil
+
+	e.Value("fr").Check("IsUnknown").chain.assertFailed(t)
+	require.NotNil(t, handler.failure)
+	assert.Equal(t, AssertUsage, handler.failure.Type)
+
+	NewValue(newMockReporter(t), "fr").Check("IsCountryCode").
+		chain.assertFailed(t)
+}
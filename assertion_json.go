@@ -0,0 +1,227 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// JSONAssertionHandler implements AssertionHandler by writing every
+// assertion as a JSON record to Writer, one record per line (JSON Lines).
+//
+// Records are described by JSONAssertionRecord. They are intended for
+// downstream tooling, e.g. to aggregate flaky assertions across CI runs.
+//
+// If Handler is set, every assertion is also passed to it after writing
+// the record; usually it's DefaultAssertionHandler, so that failures are
+// still reported to the test.
+//
+// JSONAssertionHandler is safe for concurrent use.
+//
+// Example:
+//
+//	f, _ := os.Create("assertions.jsonl")
+//	defer f.Close()
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    AssertionHandler: &httpexpect.JSONAssertionHandler{
+//	        Writer: f,
+//	        Handler: &httpexpect.DefaultAssertionHandler{
+//	            Formatter: &httpexpect.DefaultFormatter{},
+//	            Reporter:  httpexpect.NewAssertReporter(t),
+//	        },
+//	    },
+//	})
+type JSONAssertionHandler struct {
+	// Writer receives JSON records. Required.
+	Writer io.Writer
+
+	// Handler receives every assertion after it was written. Optional.
+	Handler AssertionHandler
+
+	// If true, only failed assertions are written.
+	FailuresOnly bool
+
+	mu sync.Mutex
+}
+
+// JSONAssertionRecord defines JSON record written by JSONAssertionHandler.
+type JSONAssertionRecord struct {
+	// Time when assertion was handled
+	Time time.Time `json:"time"`
+
+	// Name of the running test and request, see AssertionContext
+	TestName    string `json:"test_name,omitempty"`
+	RequestName string `json:"request_name,omitempty"`
+
+	// Chain of nested assertion names, see AssertionContext.Path
+	Path []string `json:"path"`
+
+	// Assertion result; fields below are set only for failures
+	Success bool `json:"success"`
+
+	// Failure type name (e.g. "AssertEqual"), severity ("error" or
+	// "warning"), and fatality
+	Type     string `json:"type,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	IsFatal  bool   `json:"is_fatal,omitempty"`
+
+	// Failure error messages
+	Errors []string `json:"errors,omitempty"`
+
+	// Failure values; values that can't be marshaled to JSON are
+	// written as formatted strings
+	Actual    *json.RawMessage `json:"actual,omitempty"`
+	Expected  *json.RawMessage `json:"expected,omitempty"`
+	Reference *json.RawMessage `json:"reference,omitempty"`
+	Delta     *json.RawMessage `json:"delta,omitempty"`
+
+	// Diff between expected and actual objects or arrays, if available
+	Diff string `json:"diff,omitempty"`
+
+	// Snapshot of request and response, if available
+	Request  *JSONAssertionRequest  `json:"request,omitempty"`
+	Response *JSONAssertionResponse `json:"response,omitempty"`
+
+	// Round-trip time in milliseconds, if available
+	RoundTripTime *float64 `json:"rtt_ms,omitempty"`
+}
+
+// JSONAssertionRequest defines request snapshot in JSONAssertionRecord.
+//
+// Headers are sanitized using Config.Sanitizers.
+type JSONAssertionRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+}
+
+// JSONAssertionResponse defines response snapshot in JSONAssertionRecord.
+//
+// Body is truncated to a reasonable length.
+type JSONAssertionResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Success implements AssertionHandler.Success.
+func (h *JSONAssertionHandler) Success(ctx *AssertionContext) {
+	if !h.FailuresOnly {
+		h.write(h.buildRecord(ctx, nil))
+	}
+
+	if h.Handler != nil {
+		h.Handler.Success(ctx)
+	}
+}
+
+// Failure implements AssertionHandler.Failure.
+func (h *JSONAssertionHandler) Failure(
+	ctx *AssertionContext, failure *AssertionFailure,
+) {
+	h.write(h.buildRecord(ctx, failure))
+
+	if h.Handler != nil {
+		h.Handler.Failure(ctx, failure)
+	}
+}
+
+func (h *JSONAssertionHandler) write(record *JSONAssertionRecord) {
+	if h.Writer == nil {
+		panic("JSONAssertionHandler.Writer is nil")
+	}
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		panic(err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, _ = h.Writer.Write(append(b, '\n'))
+}
+
+func (h *JSONAssertionHandler) buildRecord(
+	ctx *AssertionContext, failure *AssertionFailure,
+) *JSONAssertionRecord {
+	record := &JSONAssertionRecord{
+		Time:        time.Now(),
+		TestName:    ctx.TestName,
+		RequestName: ctx.RequestName,
+		Path:        append([]string{}, ctx.Path...),
+		Success:     failure == nil,
+	}
+
+	if failure != nil {
+		record.Type = failure.Type.String()
+		record.Severity = failure.Severity.String()
+		record.IsFatal = failure.IsFatal
+
+		for _, err := range failure.Errors {
+			if err != nil {
+				record.Errors = append(record.Errors, err.Error())
+			}
+		}
+
+		record.Actual = jsonAssertionValue(failure.Actual)
+		record.Expected = jsonAssertionValue(failure.Expected)
+		record.Reference = jsonAssertionValue(failure.Reference)
+		record.Delta = jsonAssertionValue(failure.Delta)
+
+		if failure.Actual != nil && failure.Expected != nil {
+			if diff, ok := formatDiff(
+				failure.Expected.Value, failure.Actual.Value); ok {
+				record.Diff = diff
+			}
+		}
+	}
+
+	if ctx.Request != nil && ctx.Request.httpReq != nil {
+		httpReq := ctx.Request.httpReq
+
+		record.Request = &JSONAssertionRequest{
+			Method: httpReq.Method,
+			URL:    httpReq.URL.String(),
+			Header: sanitizeHeader(ctx.Request.config.Sanitizers, httpReq.Header),
+		}
+	}
+
+	if ctx.Response != nil && ctx.Response.httpResp != nil {
+		content := ctx.Response.content
+		if len(content) > bodySnippetLimit {
+			content = content[:bodySnippetLimit]
+		}
+
+		record.Response = &JSONAssertionResponse{
+			StatusCode: ctx.Response.httpResp.StatusCode,
+			Header:     ctx.Response.httpResp.Header,
+			Body:       string(content),
+		}
+	}
+
+	if ctx.RoundTripTime != nil {
+		ms := float64(*ctx.RoundTripTime) / float64(time.Millisecond)
+		record.RoundTripTime = &ms
+	}
+
+	return record
+}
+
+func jsonAssertionValue(value *AssertionValue) *json.RawMessage {
+	if value == nil {
+		return nil
+	}
+
+	b, err := json.Marshal(value.Value)
+	if err != nil {
+		b, _ = json.Marshal(formatValue(value.Value))
+	}
+
+	raw := json.RawMessage(b)
+
+	return &raw
+}
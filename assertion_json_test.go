@@ -0,0 +1,149 @@
+package httpexpect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readJSONAssertionRecords(t *testing.T, buf *bytes.Buffer) []JSONAssertionRecord {
+	var records []JSONAssertionRecord
+
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var record JSONAssertionRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestJSONAssertionHandlerRecords(t *testing.T) {
+	buf := &bytes.Buffer{}
+	next := &mockAssertionHandler{}
+
+	handler := &JSONAssertionHandler{
+		Writer:  buf,
+		Handler: next,
+	}
+
+	ctx := &AssertionContext{
+		TestName:    "TestFoo",
+		RequestName: "create user",
+		Path:        []string{`Request("GET")`, "Expect()", "JSON()"},
+	}
+
+	handler.Success(ctx)
+	assert.Same(t, ctx, next.ctx)
+	assert.Nil(t, next.failure)
+
+	failure := &AssertionFailure{
+		Type:     AssertEqual,
+		IsFatal:  true,
+		Errors:   []error{errors.New("values are not equal")},
+		Actual:   &AssertionValue{map[string]interface{}{"a": 1.0}},
+		Expected: &AssertionValue{map[string]interface{}{"a": 2.0}},
+		Delta:    &AssertionValue{func() {}},
+	}
+
+	handler.Failure(ctx, failure)
+	assert.Same(t, failure, next.failure)
+
+	records := readJSONAssertionRecords(t, buf)
+	require.Len(t, records, 2)
+
+	assert.True(t, records[0].Success)
+	assert.Equal(t, "TestFoo", records[0].TestName)
+	assert.Equal(t, "create user", records[0].RequestName)
+	assert.Equal(t, ctx.Path, records[0].Path)
+	assert.Empty(t, records[0].Type)
+	assert.Nil(t, records[0].Actual)
+	assert.WithinDuration(t, time.Now(), records[0].Time, time.Minute)
+
+	assert.False(t, records[1].Success)
+	assert.Equal(t, "AssertEqual", records[1].Type)
+	assert.True(t, records[1].IsFatal)
+	assert.Equal(t, []string{"values are not equal"}, records[1].Errors)
+	require.NotNil(t, records[1].Actual)
+	assert.JSONEq(t, `{"a":1}`, string(*records[1].Actual))
+	require.NotNil(t, records[1].Expected)
+	assert.JSONEq(t, `{"a":2}`, string(*records[1].Expected))
+	assert.Nil(t, records[1].Reference)
+	require.NotNil(t, records[1].Delta)
+	assert.Contains(t, string(*records[1].Delta), "func()")
+	assert.Contains(t, records[1].Diff, "--- expected")
+}
+
+func TestJSONAssertionHandlerFailuresOnly(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	handler := &JSONAssertionHandler{
+		Writer:       buf,
+		FailuresOnly: true,
+	}
+
+	handler.Success(&AssertionContext{})
+	handler.Failure(&AssertionContext{}, &AssertionFailure{Type: AssertValid})
+
+	records := readJSONAssertionRecords(t, buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "AssertValid", records[0].Type)
+
+	assert.Panics(t, func() {
+		(&JSONAssertionHandler{}).Success(&AssertionContext{})
+	})
+}
+
+func TestJSONAssertionHandlerExpect(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name": "` + strings.Repeat("x", 300) + `"}`))
+	})
+
+	e := WithConfig(Config{
+		BaseURL: "http://example.com",
+		AssertionHandler: &JSONAssertionHandler{
+			Writer: buf,
+		},
+		Sanitizers: []Sanitizer{MaskHeaders("Authorization")},
+		Client: &http.Client{
+			Transport: NewBinder(handler),
+		},
+	})
+
+	e.GET("/users/1").
+		WithHeader("Authorization", "Bearer secret").
+		Expect().
+		Status(http.StatusNotFound)
+
+	records := readJSONAssertionRecords(t, buf)
+	require.NotEmpty(t, records)
+
+	last := records[len(records)-1]
+
+	assert.False(t, last.Success)
+	assert.Equal(t, "AssertEqual", last.Type)
+
+	require.NotNil(t, last.Request)
+	assert.Equal(t, "GET", last.Request.Method)
+	assert.Equal(t, "http://example.com/users/1", last.Request.URL)
+	assert.NotContains(t, last.Request.Header.Get("Authorization"), "secret")
+
+	require.NotNil(t, last.Response)
+	assert.Equal(t, http.StatusOK, last.Response.StatusCode)
+	assert.Equal(t, "application/json", last.Response.Header.Get("Content-Type"))
+	assert.Len(t, last.Response.Body, bodySnippetLimit)
+
+	assert.NotNil(t, last.RoundTripTime)
+}
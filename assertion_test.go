@@ -1,6 +1,7 @@
 package httpexpect
 
 import (
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -137,4 +138,239 @@ func TestDefaultAssertionHandler(t *testing.T) {
 		assert.Nil(t, test.logger)
 		assert.True(t, test.reporter.reported)
 	})
+
+	t.Run("failure_warning", func(t *testing.T) {
+		test := createTest(t, true)
+
+		test.handler.Failure(
+			&AssertionContext{
+				TestName: t.Name(),
+			},
+			&AssertionFailure{
+				Type:     AssertValid,
+				Severity: SeverityWarning,
+			})
+
+		assert.Equal(t, 0, test.formatter.formattedSuccess)
+		assert.Equal(t, 1, test.formatter.formattedFailure)
+
+		assert.True(t, test.logger.logged)
+		assert.False(t, test.reporter.reported)
+	})
+
+	t.Run("failure_warning_nologger", func(t *testing.T) {
+		test := createTest(t, false)
+
+		test.handler.Failure(
+			&AssertionContext{
+				TestName: t.Name(),
+			},
+			&AssertionFailure{
+				Type:     AssertValid,
+				Severity: SeverityWarning,
+			})
+
+		assert.Equal(t, 0, test.formatter.formattedSuccess)
+		assert.Equal(t, 0, test.formatter.formattedFailure)
+
+		assert.Nil(t, test.logger)
+		assert.False(t, test.reporter.reported)
+	})
+
+	t.Run("failure_warning_reporter_logger", func(t *testing.T) {
+		logger := newMockLogger(t)
+
+		handler := &DefaultAssertionHandler{
+			Formatter: newMockFormatter(t),
+			Reporter: struct {
+				Reporter
+				Logger
+			}{newMockReporter(t), logger},
+		}
+
+		handler.Failure(
+			&AssertionContext{
+				TestName: t.Name(),
+			},
+			&AssertionFailure{
+				Type:     AssertValid,
+				Severity: SeverityWarning,
+			})
+
+		assert.True(t, logger.logged)
+	})
+
+	t.Run("counts", func(t *testing.T) {
+		test := createTest(t, false)
+
+		assert.Equal(t, 0, test.handler.Errors())
+		assert.Equal(t, 0, test.handler.Warnings())
+
+		for _, failure := range []AssertionFailure{
+			{Type: AssertValid, IsFatal: true},
+			{Type: AssertValid, IsFatal: false},
+			{Type: AssertValid, Severity: SeverityWarning},
+		} {
+			failure := failure
+			test.handler.Failure(&AssertionContext{}, &failure)
+		}
+
+		assert.Equal(t, 2, test.handler.Errors())
+		assert.Equal(t, 1, test.handler.Warnings())
+
+		forked := test.handler.fork(newMockReporter(t))
+
+		forked.Failure(&AssertionContext{},
+			&AssertionFailure{Type: AssertValid, Severity: SeverityWarning})
+
+		assert.Equal(t, 2, test.handler.Warnings())
+		assert.Equal(t, 2, forked.Warnings())
+	})
+}
+
+type orderedAssertionHandler struct {
+	name  string
+	calls *[]string
+	fn    func()
+}
+
+func (h *orderedAssertionHandler) Success(ctx *AssertionContext) {
+	*h.calls = append(*h.calls, h.name+" success")
+	if h.fn != nil {
+		h.fn()
+	}
+}
+
+func (h *orderedAssertionHandler) Failure(
+	ctx *AssertionContext, failure *AssertionFailure,
+) {
+	*h.calls = append(*h.calls, h.name+" failure")
+	if h.fn != nil {
+		h.fn()
+	}
+}
+
+func TestTeeAssertionHandler(t *testing.T) {
+	ctx := &AssertionContext{}
+	failure := &AssertionFailure{}
+
+	t.Run("order", func(t *testing.T) {
+		var calls []string
+
+		handler := &TeeAssertionHandler{
+			Handlers: []AssertionHandler{
+				&orderedAssertionHandler{name: "a", calls: &calls},
+				&orderedAssertionHandler{name: "b", calls: &calls},
+				&orderedAssertionHandler{name: "c", calls: &calls},
+			},
+		}
+
+		handler.Success(ctx)
+		handler.Failure(ctx, failure)
+
+		assert.Equal(t, []string{
+			"a success", "b success", "c success",
+			"a failure", "b failure", "c failure",
+		}, calls)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		handler := &TeeAssertionHandler{}
+
+		handler.Success(ctx)
+		handler.Failure(ctx, failure)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		var calls []string
+
+		handler := &TeeAssertionHandler{
+			Handlers: []AssertionHandler{
+				&orderedAssertionHandler{name: "a", calls: &calls},
+				&orderedAssertionHandler{name: "b", calls: &calls, fn: func() {
+					panic("test")
+				}},
+				&orderedAssertionHandler{name: "c", calls: &calls},
+			},
+		}
+
+		assert.PanicsWithValue(t, "test", func() {
+			handler.Failure(ctx, failure)
+		})
+
+		assert.Equal(t, []string{"a failure", "b failure", "c failure"}, calls)
+	})
+
+	t.Run("goexit", func(t *testing.T) {
+		var calls []string
+
+		handler := &TeeAssertionHandler{
+			Handlers: []AssertionHandler{
+				&orderedAssertionHandler{name: "a", calls: &calls, fn: func() {
+					runtime.Goexit()
+				}},
+				&orderedAssertionHandler{name: "b", calls: &calls},
+			},
+		}
+
+		done := make(chan struct{})
+		returned := false
+
+		go func() {
+			defer close(done)
+			handler.Failure(ctx, failure)
+			returned = true
+		}()
+
+		<-done
+
+		assert.False(t, returned)
+		assert.Equal(t, []string{"a failure", "b failure"}, calls)
+	})
+}
+
+func TestAssertionHandlersConfig(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	var calls []string
+
+	e := WithConfig(Config{
+		Reporter: reporter,
+		AssertionHandlers: []AssertionHandler{
+			&orderedAssertionHandler{name: "a", calls: &calls},
+			&orderedAssertionHandler{name: "b", calls: &calls},
+		},
+	})
+
+	e.Value(1).Number().Equal(2)
+
+	assert.True(t, reporter.reported)
+	assert.Equal(t, []string{
+		"a success", "b success", // Value()
+		"a success", "b success", // Number()
+		"a failure", "b failure", // Equal()
+	}, calls)
+
+	calls = nil
+
+	e.Clone(Config{}).Value(1).Number().Equal(2)
+
+	assert.Contains(t, calls, "a failure")
+	assert.Contains(t, calls, "b failure")
+
+	calls = nil
+
+	handler := &mockAssertionHandler{}
+
+	e = WithConfig(Config{
+		AssertionHandler: handler,
+		AssertionHandlers: []AssertionHandler{
+			&orderedAssertionHandler{name: "a", calls: &calls},
+		},
+	})
+
+	e.Value(1).String()
+
+	assert.NotNil(t, handler.failure)
+	assert.Equal(t, []string{"a success", "a failure"}, calls)
 }
@@ -0,0 +1,370 @@
+package httpexpect
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+// Token holds OAuth2 access token.
+//
+// The fields mirror oauth2.Token from golang.org/x/oauth2, so that
+// token sources from that package can be easily adapted to TokenSource.
+type Token struct {
+	// AccessToken is the token that authorizes and authenticates requests.
+	AccessToken string
+
+	// TokenType is the type of token.
+	// If empty, "Bearer" is used.
+	TokenType string
+
+	// Expiry is the optional expiration time of the access token.
+	// If zero, the token never expires.
+	Expiry time.Time
+}
+
+// Type returns token type, "Bearer" by default.
+func (t *Token) Type() string {
+	if t.TokenType == "" {
+		return "Bearer"
+	}
+	return t.TokenType
+}
+
+// TokenSource is anything that can return a token.
+//
+// TokenSource is used by Request.WithOAuth2. It is invoked before
+// every attempt to send request, including retries, so that an
+// implementation can refresh expired tokens.
+//
+// oauth2.TokenSource can be adapted using TokenSourceFunc:
+//
+//	ts := conf.TokenSource(ctx, tok) // oauth2.TokenSource
+//
+//	req.WithOAuth2(httpexpect.TokenSourceFunc(func() (*httpexpect.Token, error) {
+//	    t, err := ts.Token()
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return &httpexpect.Token{
+//	        AccessToken: t.AccessToken,
+//	        TokenType:   t.Type(),
+//	        Expiry:      t.Expiry,
+//	    }, nil
+//	}))
+type TokenSource interface {
+	// Token returns a token or an error.
+	// Returned token must not be modified.
+	Token() (*Token, error)
+}
+
+// TokenSourceFunc is an adapter that allows a function to be used
+// as the TokenSource.
+type TokenSourceFunc func() (*Token, error)
+
+// Token implements TokenSource.Token.
+func (f TokenSourceFunc) Token() (*Token, error) {
+	return f()
+}
+
+// StaticTokenSource returns a TokenSource that always returns the same token.
+func StaticTokenSource(token *Token) TokenSource {
+	return TokenSourceFunc(func() (*Token, error) {
+		return token, nil
+	})
+}
+
+func tokenAuthorization(ts TokenSource) (string, error) {
+	token, err := ts.Token()
+	if err != nil {
+		return "", err
+	}
+
+	if token == nil || token.AccessToken == "" {
+		return "", errors.New("token source returned empty token")
+	}
+
+	if !token.Expiry.IsZero() && time.Now().After(token.Expiry) {
+		return "", fmt.Errorf("token source returned token expired at %s",
+			token.Expiry.Format(time.RFC3339))
+	}
+
+	return token.Type() + " " + token.AccessToken, nil
+}
+
+// Credentials holds authentication credentials obtained from
+// CredentialsProvider.
+//
+// If Token is non-empty, it is sent as bearer token. Otherwise, Username
+// and Password are sent using HTTP Basic Authentication.
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// CredentialsProvider is anything that can return credentials.
+//
+// CredentialsProvider is used by Request.WithAuth and Config.DefaultAuth.
+// It is invoked before every attempt to send request, including retries,
+// so that credentials can be rotated without changing tests.
+//
+// EnvCredentials, FileCredentials, MapCredentials, and StaticCredentials
+// implement this interface. Secret stores can be integrated by
+// implementing it or using CredentialsProviderFunc.
+type CredentialsProvider interface {
+	// Credentials returns credentials or an error.
+	// Returned credentials must not be modified.
+	Credentials() (*Credentials, error)
+}
+
+// CredentialsProviderFunc is an adapter that allows a function to be used
+// as the CredentialsProvider.
+type CredentialsProviderFunc func() (*Credentials, error)
+
+// Credentials implements CredentialsProvider.Credentials.
+func (f CredentialsProviderFunc) Credentials() (*Credentials, error) {
+	return f()
+}
+
+// StaticCredentials returns a CredentialsProvider that always returns
+// the same credentials.
+func StaticCredentials(credentials *Credentials) CredentialsProvider {
+	return CredentialsProviderFunc(func() (*Credentials, error) {
+		return credentials, nil
+	})
+}
+
+// MapCredentials returns a CredentialsProvider that reads credentials from
+// given map, using "username", "password", and "token" keys.
+//
+// Map is copied, so later modifications don't affect provider.
+func MapCredentials(secrets map[string]string) CredentialsProvider {
+	credentials := &Credentials{
+		Username: secrets["username"],
+		Password: secrets["password"],
+		Token:    secrets["token"],
+	}
+
+	return StaticCredentials(credentials)
+}
+
+// EnvCredentials returns a CredentialsProvider that reads credentials from
+// environment variables with given prefix, using "_USERNAME", "_PASSWORD",
+// and "_TOKEN" suffixes.
+//
+// Variables are read every time credentials are requested.
+//
+// Example:
+//
+//	// reads API_USERNAME, API_PASSWORD, and API_TOKEN
+//	req.WithAuth(httpexpect.EnvCredentials("API"))
+func EnvCredentials(prefix string) CredentialsProvider {
+	return CredentialsProviderFunc(func() (*Credentials, error) {
+		credentials := &Credentials{
+			Username: os.Getenv(prefix + "_USERNAME"),
+			Password: os.Getenv(prefix + "_PASSWORD"),
+			Token:    os.Getenv(prefix + "_TOKEN"),
+		}
+
+		if credentials.Username == "" && credentials.Token == "" {
+			return nil, fmt.Errorf(
+				"neither %s_USERNAME nor %s_TOKEN environment variable is set",
+				prefix, prefix)
+		}
+
+		return credentials, nil
+	})
+}
+
+// FileCredentials returns a CredentialsProvider that reads credentials from
+// given file.
+//
+// If file content has form "username:password", it is used for HTTP Basic
+// Authentication. Otherwise, whole content is used as bearer token.
+// Leading and trailing whitespace is ignored.
+//
+// File is read every time credentials are requested, so that it can be
+// updated by secret rotation.
+func FileCredentials(path string) CredentialsProvider {
+	return CredentialsProviderFunc(func() (*Credentials, error) {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		content := strings.TrimSpace(string(b))
+
+		if content == "" {
+			return nil, fmt.Errorf("credentials file %q is empty", path)
+		}
+
+		if i := strings.IndexByte(content, ':'); i >= 0 {
+			return &Credentials{
+				Username: content[:i],
+				Password: content[i+1:],
+			}, nil
+		}
+
+		return &Credentials{Token: content}, nil
+	})
+}
+
+func credentialsAuthorization(cp CredentialsProvider) (string, error) {
+	credentials, err := cp.Credentials()
+	if err != nil {
+		return "", err
+	}
+
+	if credentials == nil {
+		return "", errors.New("credentials provider returned nil credentials")
+	}
+
+	if credentials.Token != "" {
+		return "Bearer " + credentials.Token, nil
+	}
+
+	if credentials.Username == "" {
+		return "", errors.New("credentials provider returned empty credentials")
+	}
+
+	return basicAuthorization(credentials.Username, credentials.Password), nil
+}
+
+func basicAuthorization(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString(
+		[]byte(username+":"+password))
+}
+
+// JWT signing algorithm is selected by key type:
+//   - []byte or string: HS256
+//   - *rsa.PrivateKey: RS256
+//   - *ecdsa.PrivateKey: ES256, ES384, or ES512, depending on curve
+func jwtAlgorithm(key interface{}) (string, error) {
+	switch k := key.(type) {
+	case []byte, string:
+		return "HS256", nil
+
+	case *rsa.PrivateKey:
+		return "RS256", nil
+
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return "ES256", nil
+		case elliptic.P384():
+			return "ES384", nil
+		case elliptic.P521():
+			return "ES512", nil
+		}
+		return "", errors.New("unsupported ecdsa curve")
+
+	default:
+		return "", fmt.Errorf("unsupported signing key type %T", key)
+	}
+}
+
+func signJWT(claims interface{}, key interface{}) (string, error) {
+	alg, err := jwtAlgorithm(key)
+	if err != nil {
+		return "", err
+	}
+
+	header, err := json.Marshal(map[string]string{
+		"alg": alg,
+		"typ": "JWT",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+
+	signingInput := enc.EncodeToString(header) + "." + enc.EncodeToString(payload)
+
+	var sig []byte
+
+	switch k := key.(type) {
+	case []byte:
+		sig = hmacSign(sha256.New, k, signingInput)
+
+	case string:
+		sig = hmacSign(sha256.New, []byte(k), signingInput)
+
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signingInput))
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		if err != nil {
+			return "", err
+		}
+
+	case *ecdsa.PrivateKey:
+		sig, err = ecdsaSign(k, signingInput)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return signingInput + "." + enc.EncodeToString(sig), nil
+}
+
+func hmacSign(h func() hash.Hash, key []byte, input string) []byte {
+	mac := hmac.New(h, key)
+	_, _ = mac.Write([]byte(input))
+	return mac.Sum(nil)
+}
+
+func ecdsaSign(key *ecdsa.PrivateKey, input string) ([]byte, error) {
+	var digest []byte
+
+	switch key.Curve {
+	case elliptic.P256():
+		d := sha256.Sum256([]byte(input))
+		digest = d[:]
+	case elliptic.P384():
+		d := sha512.Sum384([]byte(input))
+		digest = d[:]
+	default:
+		d := sha512.Sum512([]byte(input))
+		digest = d[:]
+	}
+
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	// JWS uses fixed-size big-endian concatenation of r and s
+	size := (key.Curve.Params().BitSize + 7) / 8
+
+	sig := make([]byte, 2*size)
+	fillBigInt(sig[:size], r)
+	fillBigInt(sig[size:], s)
+
+	return sig, nil
+}
+
+func fillBigInt(buf []byte, n *big.Int) {
+	b := n.Bytes()
+	copy(buf[len(buf)-len(b):], b)
+}
@@ -0,0 +1,286 @@
+package httpexpect
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthTokenAuthorization(t *testing.T) {
+	cases := []struct {
+		name    string
+		token   *Token
+		result  string
+		wantErr bool
+	}{
+		{
+			name:   "bearer",
+			token:  &Token{AccessToken: "foo"},
+			result: "Bearer foo",
+		},
+		{
+			name:   "custom type",
+			token:  &Token{AccessToken: "foo", TokenType: "MAC"},
+			result: "MAC foo",
+		},
+		{
+			name:   "not expired",
+			token:  &Token{AccessToken: "foo", Expiry: time.Now().Add(time.Hour)},
+			result: "Bearer foo",
+		},
+		{
+			name:    "expired",
+			token:   &Token{AccessToken: "foo", Expiry: time.Now().Add(-time.Hour)},
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			token:   &Token{},
+			wantErr: true,
+		},
+		{
+			name:    "nil",
+			token:   nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := tokenAuthorization(StaticTokenSource(tc.token))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.result, result)
+			}
+		})
+	}
+}
+
+func TestAuthCredentialsAuthorization(t *testing.T) {
+	cases := []struct {
+		name        string
+		credentials *Credentials
+		result      string
+		wantErr     bool
+	}{
+		{
+			name:        "basic",
+			credentials: &Credentials{Username: "john", Password: "secret"},
+			result:      "Basic am9objpzZWNyZXQ=",
+		},
+		{
+			name:        "token",
+			credentials: &Credentials{Token: "foo"},
+			result:      "Bearer foo",
+		},
+		{
+			name:        "token wins",
+			credentials: &Credentials{Username: "john", Token: "foo"},
+			result:      "Bearer foo",
+		},
+		{
+			name:        "empty",
+			credentials: &Credentials{},
+			wantErr:     true,
+		},
+		{
+			name:        "nil",
+			credentials: nil,
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := credentialsAuthorization(StaticCredentials(tc.credentials))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.result, result)
+			}
+		})
+	}
+}
+
+func TestAuthCredentialsProviders(t *testing.T) {
+	t.Run("map", func(t *testing.T) {
+		secrets := map[string]string{
+			"username": "john",
+			"password": "secret",
+		}
+
+		cp := MapCredentials(secrets)
+		secrets["username"] = "bob"
+
+		credentials, err := cp.Credentials()
+		require.NoError(t, err)
+		assert.Equal(t, &Credentials{Username: "john", Password: "secret"}, credentials)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		const prefix = "HTTPEXPECT_TEST_AUTH"
+
+		cp := EnvCredentials(prefix)
+
+		_, err := cp.Credentials()
+		assert.Error(t, err)
+
+		require.NoError(t, os.Setenv(prefix+"_TOKEN", "foo"))
+		defer os.Unsetenv(prefix + "_TOKEN")
+
+		credentials, err := cp.Credentials()
+		require.NoError(t, err)
+		assert.Equal(t, &Credentials{Token: "foo"}, credentials)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "httpexpect")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "credentials")
+		cp := FileCredentials(path)
+
+		_, err = cp.Credentials()
+		assert.Error(t, err)
+
+		require.NoError(t, ioutil.WriteFile(path, []byte(" john:se:cret\n"), 0600))
+
+		credentials, err := cp.Credentials()
+		require.NoError(t, err)
+		assert.Equal(t, &Credentials{Username: "john", Password: "se:cret"}, credentials)
+
+		require.NoError(t, ioutil.WriteFile(path, []byte("foo\n"), 0600))
+
+		credentials, err = cp.Credentials()
+		require.NoError(t, err)
+		assert.Equal(t, &Credentials{Token: "foo"}, credentials)
+
+		require.NoError(t, ioutil.WriteFile(path, []byte("\n"), 0600))
+
+		_, err = cp.Credentials()
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthSignJWT(t *testing.T) {
+	claims := map[string]interface{}{
+		"sub": "john",
+	}
+
+	decode := func(t *testing.T, token string) (map[string]string, []byte, string) {
+		parts := strings.Split(token, ".")
+		require.Equal(t, 3, len(parts))
+
+		headerData, err := base64.RawURLEncoding.DecodeString(parts[0])
+		require.NoError(t, err)
+
+		var header map[string]string
+		require.NoError(t, json.Unmarshal(headerData, &header))
+
+		payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"sub":"john"}`, string(payload))
+
+		sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+
+		return header, sig, parts[0] + "." + parts[1]
+	}
+
+	t.Run("HS256", func(t *testing.T) {
+		token, err := signJWT(claims, []byte("secret"))
+		require.NoError(t, err)
+
+		header, sig, input := decode(t, token)
+		assert.Equal(t, "HS256", header["alg"])
+		assert.Equal(t, "JWT", header["typ"])
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		_, _ = mac.Write([]byte(input))
+		assert.Equal(t, mac.Sum(nil), sig)
+
+		token2, err := signJWT(claims, "secret")
+		require.NoError(t, err)
+		assert.Equal(t, token, token2)
+	})
+
+	t.Run("RS256", func(t *testing.T) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		token, err := signJWT(claims, key)
+		require.NoError(t, err)
+
+		header, sig, input := decode(t, token)
+		assert.Equal(t, "RS256", header["alg"])
+
+		digest := sha256.Sum256([]byte(input))
+		assert.NoError(t,
+			rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig))
+	})
+
+	t.Run("ES256", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		token, err := signJWT(claims, key)
+		require.NoError(t, err)
+
+		header, sig, input := decode(t, token)
+		assert.Equal(t, "ES256", header["alg"])
+		require.Equal(t, 64, len(sig))
+
+		digest := sha256.Sum256([]byte(input))
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		assert.True(t, ecdsa.Verify(&key.PublicKey, digest[:], r, s))
+	})
+
+	t.Run("ES384", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		require.NoError(t, err)
+
+		token, err := signJWT(claims, key)
+		require.NoError(t, err)
+
+		header, sig, input := decode(t, token)
+		assert.Equal(t, "ES384", header["alg"])
+		require.Equal(t, 96, len(sig))
+
+		digest := sha512.Sum384([]byte(input))
+		r := new(big.Int).SetBytes(sig[:48])
+		s := new(big.Int).SetBytes(sig[48:])
+		assert.True(t, ecdsa.Verify(&key.PublicKey, digest[:], r, s))
+	})
+
+	t.Run("unsupported key", func(t *testing.T) {
+		_, err := signJWT(claims, 123)
+		assert.Error(t, err)
+	})
+
+	t.Run("bad claims", func(t *testing.T) {
+		_, err := signJWT(make(chan int), []byte("secret"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,305 @@
+package httpexpect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BodyStream provides methods to consume response body incrementally.
+//
+// It's useful for chunked and streaming endpoints (NDJSON, long-polling,
+// server-sent events) that keep connection open and send data piece by
+// piece. See Request.WithResponseStream() and Response.BodyStream().
+//
+// Body is read in background; stream should be closed using Close()
+// to release connection and background reader.
+type BodyStream struct {
+	chain  *chain
+	reader io.ReadCloser
+
+	chunks chan bodyStreamChunk
+	done   chan struct{}
+
+	pending []byte
+	err     error
+
+	closeOnce sync.Once
+}
+
+type bodyStreamChunk struct {
+	data []byte
+	err  error
+}
+
+const bodyStreamBufSize = 32 * 1024
+
+// NewBodyStream returns a new BodyStream instance.
+//
+// reporter should not be nil. If reader is nil, failure is reported.
+//
+// Example:
+//
+//	stream := NewBodyStream(t, resp.Body)
+//	defer stream.Close()
+//
+//	stream.NextChunk().Equal("hello")
+func NewBodyStream(reporter Reporter, reader io.ReadCloser) *BodyStream {
+	chain := newChainWithDefaults("BodyStream()", reporter)
+
+	if reader == nil {
+		chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil argument"),
+			},
+		})
+	}
+
+	return newBodyStream(chain, reader)
+}
+
+func newBodyStream(parent *chain, reader io.ReadCloser) *BodyStream {
+	s := &BodyStream{
+		chain:  parent.clone(),
+		reader: reader,
+	}
+
+	if reader != nil {
+		s.chunks = make(chan bodyStreamChunk)
+		s.done = make(chan struct{})
+
+		go s.readLoop()
+	}
+
+	return s
+}
+
+func (s *BodyStream) readLoop() {
+	for {
+		buf := make([]byte, bodyStreamBufSize)
+
+		n, err := s.reader.Read(buf)
+
+		if n > 0 {
+			select {
+			case s.chunks <- bodyStreamChunk{data: buf[:n]}:
+			case <-s.done:
+				return
+			}
+		}
+
+		if err != nil {
+			select {
+			case s.chunks <- bodyStreamChunk{err: err}:
+			case <-s.done:
+			}
+			return
+		}
+	}
+}
+
+var errBodyStreamTimeout = errors.New("timeout")
+
+// returns next portion of data, either pending or received from reader;
+// returns errBodyStreamTimeout if timer fires first
+func (s *BodyStream) receive(timer <-chan time.Time) ([]byte, error) {
+	if len(s.pending) != 0 {
+		data := s.pending
+		s.pending = nil
+		return data, nil
+	}
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	select {
+	case chunk := <-s.chunks:
+		if chunk.err != nil {
+			s.err = chunk.err
+			return nil, s.err
+		}
+		return chunk.data, nil
+
+	case <-timer:
+		return nil, errBodyStreamTimeout
+	}
+}
+
+// NextChunk returns a new String instance with next portion of body.
+//
+// NextChunk blocks until some data is available. Chunk is whatever was
+// returned by a single read from connection, so its boundaries depend on
+// how server flushes data, and are not guaranteed to match boundaries of
+// chunks in chunked transfer encoding.
+//
+// If body ends or read fails, NextChunk reports failure. Request
+// timeout and deadline, if set, limit time spent waiting.
+//
+// Example:
+//
+//	stream := resp.BodyStream()
+//	stream.NextChunk().Contains(`"event":"started"`)
+func (s *BodyStream) NextChunk() *String {
+	s.chain.enter("NextChunk()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newString(s.chain, "")
+	}
+
+	data, err := s.receive(nil)
+	if err != nil {
+		failStreamRead(s.chain, err)
+		return newString(s.chain, "")
+	}
+
+	return newString(s.chain, string(data))
+}
+
+// EventuallyContains succeeds if given sub-string appears in body within
+// given timeout.
+//
+// EventuallyContains consumes body until the end of the first occurrence
+// of sub-string; remaining data is kept for subsequent calls.
+//
+// If timeout expires, body ends, or read fails before sub-string is found,
+// failure is reported.
+//
+// Example:
+//
+//	stream := resp.BodyStream()
+//	stream.EventuallyContains(`"status":"done"`, 5*time.Second)
+func (s *BodyStream) EventuallyContains(
+	value string, timeout time.Duration,
+) *BodyStream {
+	s.chain.enter("EventuallyContains()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	if timeout <= 0 {
+		s.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{timeout},
+			Errors: []error{
+				errors.New("expected: positive timeout"),
+			},
+		})
+		return s
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var buf []byte
+
+	for {
+		if idx := strings.Index(string(buf), value); idx >= 0 {
+			if end := idx + len(value); end < len(buf) {
+				s.pending = append(buf[end:], s.pending...)
+			}
+			return s
+		}
+
+		data, err := s.receive(timer.C)
+		if err != nil {
+			// keep consumed data for subsequent calls
+			s.pending = append(buf, s.pending...)
+
+			if err == errBodyStreamTimeout {
+				s.chain.fail(AssertionFailure{
+					Type:     AssertContainsSubset,
+					Actual:   &AssertionValue{string(buf)},
+					Expected: &AssertionValue{value},
+					Errors: []error{
+						fmt.Errorf(
+							"expected: body stream contains sub-string within %s",
+							timeout),
+					},
+				})
+			} else {
+				failStreamRead(s.chain, err)
+			}
+			return s
+		}
+
+		buf = append(buf, data...)
+	}
+}
+
+// Close stops reading body and closes underlying reader.
+//
+// Close can be called multiple times, and should be called even if
+// previous assertions failed.
+//
+// Example:
+//
+//	stream := resp.BodyStream()
+//	defer stream.Close()
+func (s *BodyStream) Close() *BodyStream {
+	s.chain.enter("Close()")
+	defer s.chain.leave()
+
+	if s.reader == nil {
+		return s
+	}
+
+	s.closeOnce.Do(func() {
+		close(s.done)
+
+		// error from closing partially read body is expected
+		_ = s.reader.Close()
+
+		if s.err == nil {
+			s.err = errors.New("body stream is closed")
+		}
+	})
+
+	return s
+}
+
+func failStreamRead(chain *chain, err error) {
+	if err == io.EOF {
+		chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("unexpected end of response body"),
+			},
+		})
+		return
+	}
+
+	chain.fail(AssertionFailure{
+		Type: AssertOperation,
+		Errors: []error{
+			errors.New("failed to read response body"),
+			err,
+		},
+	})
+}
+
+// Wrapper for streamed response body
+// Releases request context when body is closed
+type streamBody struct {
+	io.ReadCloser
+
+	cancelFunc context.CancelFunc
+}
+
+func (b *streamBody) Close() error {
+	err := b.ReadCloser.Close()
+
+	if b.cancelFunc != nil {
+		b.cancelFunc()
+	}
+
+	return err
+}
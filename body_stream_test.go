@@ -0,0 +1,143 @@
+package httpexpect
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyStreamFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	reader := ioutil.NopCloser(strings.NewReader("data"))
+
+	stream := newBodyStream(chain, reader)
+	defer stream.Close()
+
+	stream.chain.assertFailed(t)
+
+	assert.NotNil(t, stream.NextChunk())
+
+	stream.EventuallyContains("data", time.Second)
+	stream.Close()
+}
+
+func TestBodyStreamNil(t *testing.T) {
+	stream := NewBodyStream(newMockReporter(t), nil)
+
+	stream.chain.assertFailed(t)
+
+	assert.NotNil(t, stream.NextChunk())
+
+	stream.EventuallyContains("data", time.Second)
+	stream.Close()
+}
+
+func TestBodyStreamNextChunk(t *testing.T) {
+	reader, writer := io.Pipe()
+
+	stream := NewBodyStream(newMockReporter(t), reader)
+	defer stream.Close()
+
+	go func() {
+		_, _ = writer.Write([]byte("foo"))
+		_, _ = writer.Write([]byte("bar"))
+		_ = writer.Close()
+	}()
+
+	stream.NextChunk().Equal("foo").chain.assertOK(t)
+	stream.NextChunk().Equal("bar").chain.assertOK(t)
+	stream.chain.assertOK(t)
+
+	stream.NextChunk().chain.assertFailed(t)
+	stream.chain.assertFailed(t)
+}
+
+func TestBodyStreamEventuallyContains(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		reader, writer := io.Pipe()
+
+		stream := NewBodyStream(newMockReporter(t), reader)
+		defer stream.Close()
+
+		go func() {
+			_, _ = writer.Write([]byte(`{"id":1}` + "\n" + `{"id"`))
+			_, _ = writer.Write([]byte(`:2}` + "\n" + `{"id":3}`))
+		}()
+
+		stream.EventuallyContains(`{"id":2}`, time.Second)
+		stream.chain.assertOK(t)
+
+		stream.NextChunk().Equal("\n" + `{"id":3}`).chain.assertOK(t)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		reader, writer := io.Pipe()
+
+		stream := NewBodyStream(newMockReporter(t), reader)
+		defer stream.Close()
+
+		go func() {
+			_, _ = writer.Write([]byte("foo"))
+		}()
+
+		stream.EventuallyContains("bar", 50*time.Millisecond)
+		stream.chain.assertFailed(t)
+
+		assert.Equal(t, []byte("foo"), stream.pending)
+	})
+
+	t.Run("eof", func(t *testing.T) {
+		stream := NewBodyStream(newMockReporter(t),
+			ioutil.NopCloser(strings.NewReader("foo")))
+		defer stream.Close()
+
+		stream.EventuallyContains("bar", time.Second)
+		stream.chain.assertFailed(t)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		reader, writer := io.Pipe()
+
+		stream := NewBodyStream(newMockReporter(t), reader)
+		defer stream.Close()
+
+		_ = writer.CloseWithError(errors.New("test error"))
+
+		stream.EventuallyContains("bar", time.Second)
+		stream.chain.assertFailed(t)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		stream := NewBodyStream(newMockReporter(t),
+			ioutil.NopCloser(strings.NewReader("foo")))
+		defer stream.Close()
+
+		stream.EventuallyContains("foo", 0)
+		stream.chain.assertFailed(t)
+	})
+}
+
+func TestBodyStreamClose(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	stream := NewBodyStream(newMockReporter(t), reader)
+
+	stream.Close()
+	stream.chain.assertOK(t)
+
+	stream.Close()
+	stream.chain.assertOK(t)
+
+	_, err := writer.Write([]byte("foo"))
+	assert.Error(t, err)
+
+	stream.NextChunk().chain.assertFailed(t)
+}
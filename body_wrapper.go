@@ -126,6 +126,14 @@ func (bw *bodyWrapper) GetBody() (io.ReadCloser, error) {
 	return ioutil.NopCloser(bytes.NewReader(bw.origBytes)), nil
 }
 
+// Number of bytes read from original reader, including when reading failed
+func (bw *bodyWrapper) readLen() int {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+
+	return len(bw.origBytes)
+}
+
 func (bw *bodyWrapper) initialize() error {
 	if !bw.isInitialized {
 		bw.isInitialized = true
@@ -0,0 +1,379 @@
+package httpexpect
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Bytes provides methods to inspect attached []byte value, like binary
+// response body (image, PDF, archive, etc).
+type Bytes struct {
+	chain *chain
+	value []byte
+}
+
+// NewBytes returns a new Bytes instance.
+//
+// reporter should not be nil.
+//
+// Example:
+//
+//	b := NewBytes(t, []byte{0x89, 'P', 'N', 'G'})
+//	b.HasPrefix([]byte("\x89PNG"))
+func NewBytes(reporter Reporter, value []byte) *Bytes {
+	return newBytes(newChainWithDefaults("Bytes()", reporter), value)
+}
+
+func newBytes(parent *chain, val []byte) *Bytes {
+	if val == nil {
+		val = []byte{}
+	}
+	return &Bytes{parent.clone(), val}
+}
+
+// Raw returns underlying value attached to Bytes.
+// This is the value originally passed to NewBytes.
+//
+// Example:
+//
+//	b := NewBytes(t, data)
+//	assert.Equal(t, data, b.Raw())
+func (b *Bytes) Raw() []byte {
+	return b.value
+}
+
+// Length returns a new Number instance with number of bytes.
+//
+// Example:
+//
+//	b := NewBytes(t, []byte("abc"))
+//	b.Length().Equal(3)
+func (b *Bytes) Length() *Number {
+	b.chain.enter("Length()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return newNumber(b.chain, 0)
+	}
+
+	return newNumber(b.chain, float64(len(b.value)))
+}
+
+// Empty succeeds if there are no bytes.
+//
+// Example:
+//
+//	b := NewBytes(t, nil)
+//	b.Empty()
+func (b *Bytes) Empty() *Bytes {
+	b.chain.enter("Empty()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return b
+	}
+
+	if !(len(b.value) == 0) {
+		b.chain.fail(AssertionFailure{
+			Type:   AssertEmpty,
+			Actual: &AssertionValue{bytesSummary(b.value)},
+			Errors: []error{
+				errors.New("expected: bytes are empty"),
+			},
+		})
+	}
+
+	return b
+}
+
+// NotEmpty succeeds if there is at least one byte.
+//
+// Example:
+//
+//	b := NewBytes(t, []byte("abc"))
+//	b.NotEmpty()
+func (b *Bytes) NotEmpty() *Bytes {
+	b.chain.enter("NotEmpty()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return b
+	}
+
+	if !(len(b.value) != 0) {
+		b.chain.fail(AssertionFailure{
+			Type:   AssertNotEmpty,
+			Actual: &AssertionValue{bytesSummary(b.value)},
+			Errors: []error{
+				errors.New("expected: bytes are non-empty"),
+			},
+		})
+	}
+
+	return b
+}
+
+// Equal succeeds if bytes are equal to given value.
+//
+// Example:
+//
+//	b := NewBytes(t, []byte("abc"))
+//	b.Equal([]byte("abc"))
+func (b *Bytes) Equal(value []byte) *Bytes {
+	b.chain.enter("Equal()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return b
+	}
+
+	if !bytes.Equal(b.value, value) {
+		b.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{bytesSummary(b.value)},
+			Expected: &AssertionValue{bytesSummary(value)},
+			Errors: []error{
+				errors.New("expected: bytes are equal"),
+			},
+		})
+	}
+
+	return b
+}
+
+// NotEqual succeeds if bytes are not equal to given value.
+//
+// Example:
+//
+//	b := NewBytes(t, []byte("abc"))
+//	b.NotEqual([]byte("xyz"))
+func (b *Bytes) NotEqual(value []byte) *Bytes {
+	b.chain.enter("NotEqual()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return b
+	}
+
+	if bytes.Equal(b.value, value) {
+		b.chain.fail(AssertionFailure{
+			Type:     AssertNotEqual,
+			Actual:   &AssertionValue{bytesSummary(b.value)},
+			Expected: &AssertionValue{bytesSummary(value)},
+			Errors: []error{
+				errors.New("expected: bytes are non-equal"),
+			},
+		})
+	}
+
+	return b
+}
+
+// HasPrefix succeeds if bytes start with given prefix, e.g. with file
+// format signature ("magic number").
+//
+// Example:
+//
+//	b := NewBytes(t, data)
+//	b.HasPrefix([]byte("%PDF-"))
+func (b *Bytes) HasPrefix(prefix []byte) *Bytes {
+	b.chain.enter("HasPrefix()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return b
+	}
+
+	if !bytes.HasPrefix(b.value, prefix) {
+		b.chain.fail(AssertionFailure{
+			Type:     AssertContainsSubset,
+			Actual:   &AssertionValue{bytesSummary(b.value)},
+			Expected: &AssertionValue{bytesSummary(prefix)},
+			Errors: []error{
+				errors.New("expected: bytes have given prefix"),
+			},
+		})
+	}
+
+	return b
+}
+
+// HasSuffix succeeds if bytes end with given suffix.
+//
+// Example:
+//
+//	b := NewBytes(t, data)
+//	b.HasSuffix([]byte("%%EOF\n"))
+func (b *Bytes) HasSuffix(suffix []byte) *Bytes {
+	b.chain.enter("HasSuffix()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return b
+	}
+
+	if !bytes.HasSuffix(b.value, suffix) {
+		b.chain.fail(AssertionFailure{
+			Type:     AssertContainsSubset,
+			Actual:   &AssertionValue{bytesSummary(b.value)},
+			Expected: &AssertionValue{bytesSummary(suffix)},
+			Errors: []error{
+				errors.New("expected: bytes have given suffix"),
+			},
+		})
+	}
+
+	return b
+}
+
+// SHA256 succeeds if SHA-256 digest of bytes is equal to given
+// hex-encoded digest. Case of hex digits is ignored.
+//
+// Example:
+//
+//	b := NewBytes(t, []byte("abc"))
+//	b.SHA256("ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad")
+func (b *Bytes) SHA256(digest string) *Bytes {
+	b.chain.enter("SHA256()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return b
+	}
+
+	expected, err := hex.DecodeString(digest)
+	if err != nil || len(expected) != sha256.Size {
+		b.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{digest},
+			Errors: []error{
+				errors.New("expected: hex-encoded SHA-256 digest"),
+			},
+		})
+		return b
+	}
+
+	actual := sha256.Sum256(b.value)
+
+	if !bytes.Equal(actual[:], expected) {
+		b.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{hex.EncodeToString(actual[:])},
+			Expected: &AssertionValue{strings.ToLower(digest)},
+			Errors: []error{
+				errors.New("expected: bytes have given SHA-256 digest"),
+			},
+		})
+	}
+
+	return b
+}
+
+// EqualFile succeeds if bytes are equal to contents of given file.
+//
+// EqualFile supports golden files: if test binary is run with "-update"
+// flag set to true (the flag should be defined by test package, e.g.
+// using flag.Bool("update", false, "update golden files")), file is
+// overwritten with current bytes instead of comparing, creating parent
+// directories if needed.
+//
+// Example:
+//
+//	var update = flag.Bool("update", false, "update golden files")
+//
+//	func TestReport(t *testing.T) {
+//	    e.GET("/report.pdf").Expect().Bytes().
+//	        EqualFile("testdata/report.pdf.golden")
+//	}
+func (b *Bytes) EqualFile(path string) *Bytes {
+	b.chain.enter("EqualFile(%q)", path)
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return b
+	}
+
+	if goldenUpdate() {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(path, b.value, 0644)
+		}
+		if err != nil {
+			b.chain.fail(AssertionFailure{
+				Type: AssertOperation,
+				Errors: []error{
+					errors.New("failed to update golden file"),
+					err,
+				},
+			})
+		}
+		return b
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if err != nil {
+		b.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to read golden file"),
+				err,
+			},
+		})
+		return b
+	}
+
+	if !bytes.Equal(b.value, expected) {
+		b.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{bytesSummary(b.value)},
+			Expected: &AssertionValue{bytesSummary(expected)},
+			Errors: []error{
+				fmt.Errorf("expected: bytes are equal to contents of %q", path),
+			},
+		})
+	}
+
+	return b
+}
+
+// name of flag that enables golden files update
+var goldenUpdateFlag = "update"
+
+func goldenUpdate() bool {
+	f := flag.Lookup(goldenUpdateFlag)
+	if f == nil {
+		return false
+	}
+
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+
+	update, _ := getter.Get().(bool)
+
+	return update
+}
+
+// large binary values are reported by length and digest instead of
+// full dump
+const bytesSummaryLimit = 64
+
+func bytesSummary(value []byte) interface{} {
+	if len(value) <= bytesSummaryLimit {
+		return value
+	}
+
+	digest := sha256.Sum256(value)
+
+	return fmt.Sprintf("%d bytes, sha256 %s, prefix %q",
+		len(value), hex.EncodeToString(digest[:]), value[:bytesSummaryLimit/2])
+}
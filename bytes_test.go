@@ -0,0 +1,143 @@
+package httpexpect
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBytesFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newBytes(chain, []byte("abc"))
+
+	value.chain.assertFailed(t)
+
+	assert.NotNil(t, value.Length())
+
+	value.Empty()
+	value.NotEmpty()
+	value.Equal([]byte("abc"))
+	value.NotEqual([]byte("abc"))
+	value.HasPrefix(nil)
+	value.HasSuffix(nil)
+	value.SHA256("")
+	value.EqualFile("")
+}
+
+func TestBytesGetters(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewBytes(reporter, []byte("abc"))
+
+	assert.Equal(t, []byte("abc"), value.Raw())
+	value.Length().Equal(3).chain.assertOK(t)
+
+	assert.Equal(t, []byte{}, NewBytes(reporter, nil).Raw())
+}
+
+func TestBytesEmpty(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	NewBytes(reporter, nil).Empty().chain.assertOK(t)
+	NewBytes(reporter, nil).NotEmpty().chain.assertFailed(t)
+
+	NewBytes(reporter, []byte("a")).Empty().chain.assertFailed(t)
+	NewBytes(reporter, []byte("a")).NotEmpty().chain.assertOK(t)
+}
+
+func TestBytesEqual(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	NewBytes(reporter, []byte("abc")).Equal([]byte("abc")).chain.assertOK(t)
+	NewBytes(reporter, []byte("abc")).Equal([]byte("abd")).chain.assertFailed(t)
+
+	NewBytes(reporter, []byte("abc")).NotEqual([]byte("abd")).chain.assertOK(t)
+	NewBytes(reporter, []byte("abc")).NotEqual([]byte("abc")).chain.assertFailed(t)
+}
+
+func TestBytesPrefixSuffix(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := []byte("%PDF-1.4 ... %%EOF")
+
+	NewBytes(reporter, value).HasPrefix([]byte("%PDF-")).chain.assertOK(t)
+	NewBytes(reporter, value).HasPrefix([]byte("PK")).chain.assertFailed(t)
+
+	NewBytes(reporter, value).HasSuffix([]byte("%%EOF")).chain.assertOK(t)
+	NewBytes(reporter, value).HasSuffix([]byte("%PDF")).chain.assertFailed(t)
+}
+
+func TestBytesSHA256(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	const digest = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+
+	NewBytes(reporter, []byte("abc")).SHA256(digest).chain.assertOK(t)
+	NewBytes(reporter, []byte("abc")).
+		SHA256("BA7816BF8F01CFEA414140DE5DAE2223B00361A396177A9CB410FF61F20015AD").
+		chain.assertOK(t)
+
+	NewBytes(reporter, []byte("abd")).SHA256(digest).chain.assertFailed(t)
+
+	NewBytes(reporter, []byte("abc")).SHA256("xyz").chain.assertFailed(t)
+	NewBytes(reporter, []byte("abc")).SHA256("abcd").chain.assertFailed(t)
+}
+
+var testGoldenUpdate = flag.Bool("httpexpect-test-update", false, "")
+
+func TestBytesEqualFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "testdata", "file.golden")
+
+	reporter := newMockReporter(t)
+
+	t.Run("missing", func(t *testing.T) {
+		NewBytes(reporter, []byte("abc")).EqualFile(path).chain.assertFailed(t)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		prevFlag := goldenUpdateFlag
+		goldenUpdateFlag = "httpexpect-test-update"
+		defer func() {
+			goldenUpdateFlag = prevFlag
+		}()
+
+		require.NoError(t, flag.Set("httpexpect-test-update", "true"))
+		defer func() {
+			*testGoldenUpdate = false
+		}()
+
+		NewBytes(reporter, []byte("abc")).EqualFile(path).chain.assertOK(t)
+
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("abc"), data)
+	})
+
+	t.Run("compare", func(t *testing.T) {
+		NewBytes(reporter, []byte("abc")).EqualFile(path).chain.assertOK(t)
+		NewBytes(reporter, []byte("abd")).EqualFile(path).chain.assertFailed(t)
+	})
+}
+
+func TestBytesSummary(t *testing.T) {
+	small := []byte("abc")
+	assert.Equal(t, small, bytesSummary(small))
+
+	large := bytes.Repeat([]byte("x"), 1000)
+	summary, ok := bytesSummary(large).(string)
+	require.True(t, ok)
+	assert.Contains(t, summary, "1000 bytes")
+	assert.Contains(t, summary, "sha256")
+}
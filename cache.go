@@ -0,0 +1,417 @@
+package httpexpect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStore is used to store responses when client-side caching is enabled.
+//
+// Caching is enabled by setting Config.CacheStore or by calling
+// Request.WithCache. When enabled, GET responses are stored and reused
+// according to RFC 7234 rules, and Response.FromCache can be used to check
+// whether response was served from cache.
+//
+// MemoryCacheStore implements this interface.
+type CacheStore interface {
+	// Get returns entry for given key, if present.
+	Get(key string) (*CacheEntry, bool)
+
+	// Set stores entry for given key, replacing existing one.
+	Set(key string, entry *CacheEntry)
+
+	// Delete removes entry for given key, if present.
+	Delete(key string)
+}
+
+// CacheEntry holds stored response.
+type CacheEntry struct {
+	// Response status code.
+	StatusCode int
+
+	// Response header.
+	Header http.Header
+
+	// Response body.
+	Body []byte
+
+	// Request header values selected by response Vary header.
+	VaryHeader http.Header
+
+	// Time when response was received or last revalidated.
+	StoredAt time.Time
+}
+
+// MemoryCacheStore is a CacheStore that keeps entries in memory.
+//
+// MemoryCacheStore is safe for concurrent use.
+//
+// Example:
+//
+//	store := httpexpect.NewMemoryCacheStore()
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:    "http://example.com",
+//	    Reporter:   httpexpect.NewAssertReporter(t),
+//	    CacheStore: store,
+//	})
+//
+//	e.GET("/path").Expect().NotFromCache()
+//	e.GET("/path").Expect().FromCache()
+type MemoryCacheStore struct {
+	mu      sync.Mutex
+	entries map[string]*CacheEntry
+}
+
+// NewMemoryCacheStore returns a new empty MemoryCacheStore.
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return &MemoryCacheStore{
+		entries: make(map[string]*CacheEntry),
+	}
+}
+
+// Get implements CacheStore.Get.
+func (s *MemoryCacheStore) Get(key string) (*CacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	return entry, ok
+}
+
+// Set implements CacheStore.Set.
+func (s *MemoryCacheStore) Set(key string, entry *CacheEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = entry
+}
+
+// Delete implements CacheStore.Delete.
+func (s *MemoryCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+}
+
+// Len returns number of stored entries.
+func (s *MemoryCacheStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.entries)
+}
+
+// Reset removes all stored entries.
+func (s *MemoryCacheStore) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = make(map[string]*CacheEntry)
+}
+
+// status codes that are cacheable by default, see RFC 7231, section 6.1
+var cacheableStatuses = map[int]bool{
+	http.StatusOK:                   true,
+	http.StatusNonAuthoritativeInfo: true,
+	http.StatusNoContent:            true,
+	http.StatusMultipleChoices:      true,
+	http.StatusMovedPermanently:     true,
+	http.StatusNotFound:             true,
+	http.StatusMethodNotAllowed:     true,
+	http.StatusGone:                 true,
+	http.StatusRequestURITooLong:    true,
+	http.StatusNotImplemented:       true,
+}
+
+// checks whether method is safe, see RFC 7231, section 4.2.1
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+func cacheKey(req *http.Request) string {
+	return req.URL.String()
+}
+
+// parses Cache-Control header into map of directives
+func parseCacheControl(header http.Header) map[string]string {
+	cc := make(map[string]string)
+
+	for _, line := range header.Values("Cache-Control") {
+		for _, part := range strings.Split(line, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			if i := strings.IndexByte(part, '='); i >= 0 {
+				cc[strings.ToLower(part[:i])] = strings.Trim(part[i+1:], `"`)
+			} else {
+				cc[strings.ToLower(part)] = ""
+			}
+		}
+	}
+
+	return cc
+}
+
+// checks whether response may be stored, see RFC 7234, section 3
+func isCacheableResponse(resp *http.Response) bool {
+	if !cacheableStatuses[resp.StatusCode] {
+		return false
+	}
+
+	cc := parseCacheControl(resp.Header)
+	if _, ok := cc["no-store"]; ok {
+		return false
+	}
+
+	if resp.Header.Get("Vary") == "*" {
+		return false
+	}
+
+	// without explicit freshness or validators, entry would never be used
+	if _, ok := cc["max-age"]; ok {
+		return true
+	}
+
+	return resp.Header.Get("Expires") != "" ||
+		resp.Header.Get("ETag") != "" ||
+		resp.Header.Get("Last-Modified") != ""
+}
+
+func newCacheEntry(req *http.Request, resp *http.Response, body []byte) *CacheEntry {
+	entry := &CacheEntry{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Body:       body,
+		VaryHeader: http.Header{},
+		StoredAt:   time.Now(),
+	}
+
+	for _, line := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(line, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name != "" {
+				entry.VaryHeader[name] = req.Header.Values(name)
+			}
+		}
+	}
+
+	return entry
+}
+
+// checks that request has same values of headers selected by Vary
+func (e *CacheEntry) matches(req *http.Request) bool {
+	for name, values := range e.VaryHeader {
+		if strings.Join(values, ",") != strings.Join(req.Header.Values(name), ",") {
+			return false
+		}
+	}
+	return true
+}
+
+// returns current age of entry, see RFC 7234, section 4.2.3
+func (e *CacheEntry) age(now time.Time) time.Duration {
+	age := now.Sub(e.StoredAt)
+
+	if secs, err := strconv.Atoi(e.Header.Get("Age")); err == nil && secs > 0 {
+		age += time.Duration(secs) * time.Second
+	}
+
+	if age < 0 {
+		age = 0
+	}
+
+	return age
+}
+
+// returns freshness lifetime of entry, see RFC 7234, section 4.2.1
+func (e *CacheEntry) lifetime() time.Duration {
+	cc := parseCacheControl(e.Header)
+
+	if _, ok := cc["no-cache"]; ok {
+		return 0
+	}
+
+	lifetime, _, err := freshnessLifetime(e.Header, cc, false, e.StoredAt)
+	if err != nil {
+		return 0
+	}
+
+	return lifetime
+}
+
+// returns freshness lifetime of response and its source, see RFC 7234,
+// section 4.2.1; source is empty if response has no explicit freshness
+// information; "s-maxage" is taken into account only by shared caches;
+// if response has Expires but no valid Date, defaultDate is used, or
+// error is returned if defaultDate is zero
+func freshnessLifetime(
+	header http.Header, cc map[string]string, shared bool, defaultDate time.Time,
+) (time.Duration, string, error) {
+	names := []string{"max-age"}
+	if shared {
+		names = []string{"s-maxage", "max-age"}
+	}
+
+	for _, name := range names {
+		v, ok := cc[name]
+		if !ok {
+			continue
+		}
+		secs, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid %q directive value %q", name, v)
+		}
+		return time.Duration(secs) * time.Second,
+			fmt.Sprintf("%s=%d", name, secs), nil
+	}
+
+	v := header.Get("Expires")
+	if v == "" {
+		return 0, "", nil
+	}
+
+	expires, err := http.ParseTime(v)
+	if err != nil {
+		// RFC 7234, section 5.3: invalid date means already expired
+		return 0, fmt.Sprintf("Expires %q", v), nil
+	}
+
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		if defaultDate.IsZero() {
+			return 0, "", fmt.Errorf(
+				"invalid or missing Date %q, required with Expires", header.Get("Date"))
+		}
+		date = defaultDate
+	}
+
+	lifetime := expires.Sub(date)
+	if lifetime < 0 {
+		lifetime = 0
+	}
+
+	return lifetime, "Expires - Date", nil
+}
+
+// sets conditional request headers to validators of previously received
+// response, see RFC 7232, section 3
+func (r *Request) withValidators(etag, lastModified string) *Request {
+	if etag != "" {
+		r.WithIfNoneMatch(etag)
+	}
+
+	if lastModified != "" {
+		if t, err := http.ParseTime(lastModified); err == nil {
+			r.WithIfModifiedSince(t)
+		} else {
+			r.WithHeader("If-Modified-Since", lastModified)
+		}
+	}
+
+	return r
+}
+
+// checks that response to conditional request is 304 response for the
+// same representation as 200 response with given header, see RFC 7232,
+// section 4.1; returns list of problems
+func checkNotModified(header http.Header, resp *Response) []error {
+	if resp.httpResp.StatusCode != http.StatusNotModified {
+		return []error{
+			fmt.Errorf("response status is %d, expected 304", resp.httpResp.StatusCode),
+		}
+	}
+
+	var errs []error
+
+	if len(resp.content) != 0 {
+		errs = append(errs, errors.New("304 response has non-empty body"))
+	}
+
+	etag := header.Get("ETag")
+	if v := resp.httpResp.Header.Get("ETag"); etag != "" && v != "" && v != etag {
+		errs = append(errs, fmt.Errorf("304 response has ETag %q, expected %q", v, etag))
+	}
+
+	// 304 response repeats Cache-Control and Expires
+	for _, name := range []string{"Cache-Control", "Expires"} {
+		if header.Get(name) != "" && resp.httpResp.Header.Get(name) == "" {
+			errs = append(errs,
+				fmt.Errorf("304 response has no %s, but 200 response has", name))
+		}
+	}
+
+	return errs
+}
+
+// checks whether entry can be served without revalidation
+func (e *CacheEntry) isFresh(req *http.Request, now time.Time) bool {
+	reqCC := parseCacheControl(req.Header)
+
+	if _, ok := reqCC["no-cache"]; ok {
+		return false
+	}
+
+	if req.Header.Get("Pragma") == "no-cache" && req.Header.Get("Cache-Control") == "" {
+		return false
+	}
+
+	age := e.age(now)
+
+	if v, ok := reqCC["max-age"]; ok {
+		if secs, err := strconv.Atoi(v); err != nil || age > time.Duration(secs)*time.Second {
+			return false
+		}
+	}
+
+	return e.lifetime() > age
+}
+
+// returns copy of entry updated using headers from 304 response,
+// see RFC 7234, section 4.3.4
+func (e *CacheEntry) revalidate(resp *http.Response) *CacheEntry {
+	ret := *e
+
+	ret.Header = e.Header.Clone()
+	for name, values := range resp.Header {
+		if name == "Content-Length" {
+			continue
+		}
+		ret.Header[name] = values
+	}
+
+	ret.StoredAt = time.Now()
+
+	return &ret
+}
+
+// constructs response from entry
+func (e *CacheEntry) response(req *http.Request, now time.Time) *http.Response {
+	header := e.Header.Clone()
+	header.Set("Age", strconv.Itoa(int(e.age(now)/time.Second)))
+
+	return &http.Response{
+		Status:        statusCodeText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          newBodyWrapper(ioutil.NopCloser(bytes.NewReader(e.Body)), nil),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,256 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheControl provides methods to inspect directives of parsed
+// Cache-Control header, see RFC 7234, section 5.2.
+//
+// Directive names are case-insensitive; quotes around values are removed.
+type CacheControl struct {
+	chain *chain
+	value map[string]string
+}
+
+// NewCacheControl returns a new CacheControl instance.
+//
+// reporter should not be nil. value is Cache-Control header value, e.g.
+// "public, max-age=3600".
+//
+// Example:
+//
+//	cc := NewCacheControl(t, "public, max-age=3600")
+//	cc.Public()
+//	cc.MaxAge().Equal(time.Hour)
+func NewCacheControl(reporter Reporter, value string) *CacheControl {
+	return newCacheControl(newChainWithDefaults("CacheControl()", reporter),
+		http.Header{"Cache-Control": {value}})
+}
+
+func newCacheControl(parent *chain, header http.Header) *CacheControl {
+	return &CacheControl{parent.clone(), parseCacheControl(header)}
+}
+
+// Raw returns map of directives, with lower-case names as keys.
+// Directives without value have empty string as value.
+//
+// Example:
+//
+//	cc := NewCacheControl(t, "no-cache, max-age=0")
+//	assert.Equal(t, map[string]string{"no-cache": "", "max-age": "0"}, cc.Raw())
+func (c *CacheControl) Raw() map[string]string {
+	return c.value
+}
+
+// ContainsDirective succeeds if header contains given directive.
+//
+// Example:
+//
+//	cc := NewCacheControl(t, "private, proxy-revalidate")
+//	cc.ContainsDirective("proxy-revalidate")
+func (c *CacheControl) ContainsDirective(name string) *CacheControl {
+	c.chain.enter("ContainsDirective(%q)", name)
+	defer c.chain.leave()
+
+	c.checkDirective(name)
+
+	return c
+}
+
+// NotContainsDirective succeeds if header doesn't contain given directive.
+//
+// Example:
+//
+//	cc := NewCacheControl(t, "public")
+//	cc.NotContainsDirective("no-store")
+func (c *CacheControl) NotContainsDirective(name string) *CacheControl {
+	c.chain.enter("NotContainsDirective(%q)", name)
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	if _, ok := c.value[strings.ToLower(name)]; ok {
+		c.chain.fail(AssertionFailure{
+			Type:     AssertNotContainsKey,
+			Actual:   &AssertionValue{c.value},
+			Expected: &AssertionValue{name},
+			Errors: []error{
+				fmt.Errorf("expected: Cache-Control has no %q directive", name),
+			},
+		})
+	}
+
+	return c
+}
+
+// Directive returns a new String instance with value of given directive.
+//
+// Directive fails if header doesn't contain directive.
+//
+// Example:
+//
+//	cc := NewCacheControl(t, `private="Set-Cookie"`)
+//	cc.Directive("private").Equal("Set-Cookie")
+func (c *CacheControl) Directive(name string) *String {
+	c.chain.enter("Directive(%q)", name)
+	defer c.chain.leave()
+
+	value, ok := c.checkDirective(name)
+	if !ok {
+		return newString(c.chain, "")
+	}
+
+	return newString(c.chain, value)
+}
+
+// NoStore succeeds if header contains "no-store" directive.
+func (c *CacheControl) NoStore() *CacheControl {
+	c.chain.enter("NoStore()")
+	defer c.chain.leave()
+
+	c.checkDirective("no-store")
+
+	return c
+}
+
+// NoCache succeeds if header contains "no-cache" directive.
+func (c *CacheControl) NoCache() *CacheControl {
+	c.chain.enter("NoCache()")
+	defer c.chain.leave()
+
+	c.checkDirective("no-cache")
+
+	return c
+}
+
+// Public succeeds if header contains "public" directive.
+func (c *CacheControl) Public() *CacheControl {
+	c.chain.enter("Public()")
+	defer c.chain.leave()
+
+	c.checkDirective("public")
+
+	return c
+}
+
+// Private succeeds if header contains "private" directive.
+func (c *CacheControl) Private() *CacheControl {
+	c.chain.enter("Private()")
+	defer c.chain.leave()
+
+	c.checkDirective("private")
+
+	return c
+}
+
+// MustRevalidate succeeds if header contains "must-revalidate" directive.
+func (c *CacheControl) MustRevalidate() *CacheControl {
+	c.chain.enter("MustRevalidate()")
+	defer c.chain.leave()
+
+	c.checkDirective("must-revalidate")
+
+	return c
+}
+
+// Immutable succeeds if header contains "immutable" directive (RFC 8246).
+func (c *CacheControl) Immutable() *CacheControl {
+	c.chain.enter("Immutable()")
+	defer c.chain.leave()
+
+	c.checkDirective("immutable")
+
+	return c
+}
+
+// MaxAge returns a new Duration instance with value of "max-age" directive.
+//
+// MaxAge fails if directive is missing or is not a non-negative integer.
+//
+// Example:
+//
+//	cc := NewCacheControl(t, "max-age=3600")
+//	cc.MaxAge().Ge(time.Minute)
+func (c *CacheControl) MaxAge() *Duration {
+	c.chain.enter("MaxAge()")
+	defer c.chain.leave()
+
+	return c.seconds("max-age")
+}
+
+// SMaxAge returns a new Duration instance with value of "s-maxage"
+// directive.
+//
+// SMaxAge fails if directive is missing or is not a non-negative integer.
+//
+// Example:
+//
+//	cc := NewCacheControl(t, "s-maxage=600")
+//	cc.SMaxAge().Equal(10 * time.Minute)
+func (c *CacheControl) SMaxAge() *Duration {
+	c.chain.enter("SMaxAge()")
+	defer c.chain.leave()
+
+	return c.seconds("s-maxage")
+}
+
+func (c *CacheControl) checkDirective(name string) (string, bool) {
+	if c.chain.failed() {
+		return "", false
+	}
+
+	value, ok := c.value[strings.ToLower(name)]
+	if !ok {
+		c.chain.fail(AssertionFailure{
+			Type:     AssertContainsKey,
+			Actual:   &AssertionValue{c.value},
+			Expected: &AssertionValue{name},
+			Errors: []error{
+				fmt.Errorf("expected: Cache-Control has %q directive", name),
+			},
+		})
+		return "", false
+	}
+
+	return value, true
+}
+
+func (c *CacheControl) seconds(name string) *Duration {
+	value, ok := c.checkDirective(name)
+	if !ok {
+		return newDuration(c.chain, nil)
+	}
+
+	secs, err := strconv.ParseUint(value, 10, 64)
+	if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+		secs, err = 1<<31, nil
+	}
+	if err != nil {
+		c.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{value},
+			Errors: []error{
+				fmt.Errorf("invalid %q directive value", name),
+				errors.New("expected: non-negative integer number of seconds"),
+			},
+		})
+		return newDuration(c.chain, nil)
+	}
+
+	// RFC 7234, section 1.2.1: greater values are treated as 2^31
+	if secs > 1<<31 {
+		secs = 1 << 31
+	}
+
+	d := time.Duration(secs) * time.Second
+
+	return newDuration(c.chain, &d)
+}
@@ -0,0 +1,125 @@
+package httpexpect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControlFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newCacheControl(chain, nil)
+
+	value.chain.assertFailed(t)
+
+	assert.NotNil(t, value.Directive("foo"))
+	assert.NotNil(t, value.MaxAge())
+	assert.NotNil(t, value.SMaxAge())
+
+	value.ContainsDirective("foo")
+	value.NotContainsDirective("foo")
+	value.NoStore()
+	value.NoCache()
+	value.Public()
+	value.Private()
+	value.MustRevalidate()
+	value.Immutable()
+}
+
+func TestCacheControlRaw(t *testing.T) {
+	value := NewCacheControl(newMockReporter(t),
+		`No-Cache, max-age=0, private="Set-Cookie"`)
+
+	assert.Equal(t, map[string]string{
+		"no-cache": "",
+		"max-age":  "0",
+		"private":  "Set-Cookie",
+	}, value.Raw())
+}
+
+func TestCacheControlDirectives(t *testing.T) {
+	cases := []struct {
+		name  string
+		check func(c *CacheControl)
+	}{
+		{"no-store", func(c *CacheControl) { c.NoStore() }},
+		{"no-cache", func(c *CacheControl) { c.NoCache() }},
+		{"public", func(c *CacheControl) { c.Public() }},
+		{"private", func(c *CacheControl) { c.Private() }},
+		{"must-revalidate", func(c *CacheControl) { c.MustRevalidate() }},
+		{"immutable", func(c *CacheControl) { c.Immutable() }},
+		{"proxy-revalidate", func(c *CacheControl) {
+			c.ContainsDirective("Proxy-Revalidate")
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			value := NewCacheControl(newMockReporter(t), "max-age=1, "+tc.name)
+			tc.check(value)
+			value.chain.assertOK(t)
+
+			value = NewCacheControl(newMockReporter(t), "max-age=1")
+			tc.check(value)
+			value.chain.assertFailed(t)
+
+			value = NewCacheControl(newMockReporter(t), "max-age=1")
+			value.NotContainsDirective(tc.name)
+			value.chain.assertOK(t)
+
+			value = NewCacheControl(newMockReporter(t), tc.name)
+			value.NotContainsDirective(tc.name)
+			value.chain.assertFailed(t)
+		})
+	}
+}
+
+func TestCacheControlDirective(t *testing.T) {
+	value := NewCacheControl(newMockReporter(t), `private="Set-Cookie"`)
+
+	value.Directive("private").Equal("Set-Cookie").chain.assertOK(t)
+	value.chain.assertOK(t)
+
+	value.Directive("no-cache").chain.assertFailed(t)
+	value.chain.assertFailed(t)
+}
+
+func TestCacheControlMaxAge(t *testing.T) {
+	cases := []struct {
+		header string
+		maxAge time.Duration
+		fail   bool
+	}{
+		{header: "max-age=3600", maxAge: time.Hour},
+		{header: `max-age="60"`, maxAge: time.Minute},
+		{header: "max-age=0", maxAge: 0},
+		{header: "max-age=99999999999999999999", maxAge: (1 << 31) * time.Second},
+		{header: "max-age=-1", fail: true},
+		{header: "max-age=abc", fail: true},
+		{header: "no-cache", fail: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.header, func(t *testing.T) {
+			value := NewCacheControl(newMockReporter(t), tc.header)
+
+			d := value.MaxAge()
+
+			if tc.fail {
+				d.chain.assertFailed(t)
+			} else {
+				d.chain.assertOK(t)
+				assert.Equal(t, tc.maxAge, d.Raw())
+			}
+		})
+	}
+
+	t.Run("s-maxage", func(t *testing.T) {
+		value := NewCacheControl(newMockReporter(t), "max-age=60, s-maxage=600")
+
+		value.SMaxAge().Equal(10 * time.Minute).chain.assertOK(t)
+	})
+}
@@ -0,0 +1,270 @@
+package httpexpect
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheMemoryStore(t *testing.T) {
+	store := NewMemoryCacheStore()
+
+	_, ok := store.Get("key")
+	assert.False(t, ok)
+
+	entry := &CacheEntry{StatusCode: http.StatusOK}
+
+	store.Set("key", entry)
+	assert.Equal(t, 1, store.Len())
+
+	got, ok := store.Get("key")
+	assert.True(t, ok)
+	assert.Same(t, entry, got)
+
+	store.Delete("key")
+	assert.Equal(t, 0, store.Len())
+
+	store.Set("key", entry)
+	store.Reset()
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestCacheControl(t *testing.T) {
+	cc := parseCacheControl(http.Header{
+		"Cache-Control": {`max-age=60, No-Cache`, `private="Set-Cookie"`},
+	})
+
+	assert.Equal(t, map[string]string{
+		"max-age":  "60",
+		"no-cache": "",
+		"private":  "Set-Cookie",
+	}, cc)
+}
+
+func TestCacheCacheable(t *testing.T) {
+	cases := []struct {
+		name      string
+		status    int
+		header    http.Header
+		cacheable bool
+	}{
+		{
+			name:      "max-age",
+			status:    http.StatusOK,
+			header:    http.Header{"Cache-Control": {"max-age=60"}},
+			cacheable: true,
+		},
+		{
+			name:      "etag",
+			status:    http.StatusOK,
+			header:    http.Header{"Etag": {`"v1"`}},
+			cacheable: true,
+		},
+		{
+			name:      "no freshness",
+			status:    http.StatusOK,
+			header:    http.Header{},
+			cacheable: false,
+		},
+		{
+			name:      "no-store",
+			status:    http.StatusOK,
+			header:    http.Header{"Cache-Control": {"max-age=60, no-store"}},
+			cacheable: false,
+		},
+		{
+			name:   "vary star",
+			status: http.StatusOK,
+			header: http.Header{
+				"Cache-Control": {"max-age=60"},
+				"Vary":          {"*"},
+			},
+			cacheable: false,
+		},
+		{
+			name:      "status",
+			status:    http.StatusInternalServerError,
+			header:    http.Header{"Cache-Control": {"max-age=60"}},
+			cacheable: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tc.status,
+				Header:     tc.header,
+			}
+			assert.Equal(t, tc.cacheable, isCacheableResponse(resp))
+		})
+	}
+}
+
+func TestCacheFreshness(t *testing.T) {
+	now := time.Now()
+
+	req := func(header http.Header) *http.Request {
+		return &http.Request{Header: header}
+	}
+
+	t.Run("max-age", func(t *testing.T) {
+		entry := &CacheEntry{
+			Header:   http.Header{"Cache-Control": {"max-age=60"}},
+			StoredAt: now.Add(-time.Second * 30),
+		}
+
+		assert.True(t, entry.isFresh(req(http.Header{}), now))
+		assert.False(t, entry.isFresh(req(http.Header{}), now.Add(time.Minute)))
+
+		assert.False(t, entry.isFresh(req(http.Header{
+			"Cache-Control": {"no-cache"},
+		}), now))
+		assert.False(t, entry.isFresh(req(http.Header{
+			"Cache-Control": {"max-age=10"},
+		}), now))
+	})
+
+	t.Run("age", func(t *testing.T) {
+		entry := &CacheEntry{
+			Header: http.Header{
+				"Cache-Control": {"max-age=60"},
+				"Age":           {"50"},
+			},
+			StoredAt: now.Add(-time.Second * 30),
+		}
+
+		assert.False(t, entry.isFresh(req(http.Header{}), now))
+	})
+
+	t.Run("expires", func(t *testing.T) {
+		entry := &CacheEntry{
+			Header: http.Header{
+				"Date":    {now.UTC().Format(http.TimeFormat)},
+				"Expires": {now.Add(time.Minute).UTC().Format(http.TimeFormat)},
+			},
+			StoredAt: now,
+		}
+
+		assert.True(t, entry.isFresh(req(http.Header{}), now))
+		assert.False(t, entry.isFresh(req(http.Header{}), now.Add(time.Hour)))
+	})
+
+	t.Run("no-cache", func(t *testing.T) {
+		entry := &CacheEntry{
+			Header:   http.Header{"Cache-Control": {"max-age=60, no-cache"}},
+			StoredAt: now,
+		}
+
+		assert.False(t, entry.isFresh(req(http.Header{}), now))
+	})
+}
+
+func TestCacheFreshnessLifetime(t *testing.T) {
+	date := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		header   http.Header
+		shared   bool
+		lifetime time.Duration
+		source   string
+		err      bool
+	}{
+		{
+			name:     "max-age",
+			header:   http.Header{"Cache-Control": {"max-age=60"}},
+			lifetime: time.Minute,
+			source:   "max-age=60",
+		},
+		{
+			name:     "s-maxage private",
+			header:   http.Header{"Cache-Control": {"s-maxage=10, max-age=60"}},
+			lifetime: time.Minute,
+			source:   "max-age=60",
+		},
+		{
+			name:     "s-maxage shared",
+			header:   http.Header{"Cache-Control": {"s-maxage=10, max-age=60"}},
+			shared:   true,
+			lifetime: 10 * time.Second,
+			source:   "s-maxage=10",
+		},
+		{
+			name:   "invalid max-age",
+			header: http.Header{"Cache-Control": {"max-age=-1"}},
+			err:    true,
+		},
+		{
+			name: "expires",
+			header: http.Header{
+				"Date":    {date.Format(http.TimeFormat)},
+				"Expires": {date.Add(time.Hour).Format(http.TimeFormat)},
+			},
+			lifetime: time.Hour,
+			source:   "Expires - Date",
+		},
+		{
+			name: "invalid expires",
+			header: http.Header{
+				"Expires": {"0"},
+			},
+			source: `Expires "0"`,
+		},
+		{
+			name: "expires without date",
+			header: http.Header{
+				"Expires": {date.Add(time.Hour).Format(http.TimeFormat)},
+			},
+			shared: true,
+			err:    true,
+		},
+		{
+			name:   "none",
+			header: http.Header{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var defaultDate time.Time
+			if !tc.shared {
+				defaultDate = date
+			}
+
+			lifetime, source, err := freshnessLifetime(
+				tc.header, parseCacheControl(tc.header), tc.shared, defaultDate)
+
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.lifetime, lifetime)
+			assert.Equal(t, tc.source, source)
+		})
+	}
+}
+
+func TestCacheVary(t *testing.T) {
+	req := &http.Request{
+		Header: http.Header{"Accept": {"application/json"}},
+	}
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			"Cache-Control": {"max-age=60"},
+			"Vary":          {"accept"},
+		},
+	}
+
+	entry := newCacheEntry(req, resp, []byte("body"))
+
+	assert.True(t, entry.matches(req))
+	assert.False(t, entry.matches(&http.Request{
+		Header: http.Header{"Accept": {"text/plain"}},
+	}))
+}
@@ -0,0 +1,319 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheableOpts defines caching requirements checked by
+// Expect.AssertCacheable.
+//
+// Zero value requires a response that can be stored by shared caches,
+// has positive freshness lifetime, and has at least one validator
+// (ETag or Last-Modified) that is honored by the server.
+type CacheableOpts struct {
+	// AllowPrivate allows "private" Cache-Control directive, i.e. responses
+	// that may be stored only by browser cache.
+	AllowPrivate bool
+
+	// AllowNoCache allows responses without freshness lifetime, e.g. with
+	// "no-cache" directive or "max-age=0", which caches should revalidate
+	// on every use.
+	AllowNoCache bool
+
+	// MinMaxAge is minimum allowed freshness lifetime.
+	// Ignored if zero.
+	MinMaxAge time.Duration
+
+	// RequireETag requires ETag validator. By default, either ETag or
+	// Last-Modified is enough.
+	RequireETag bool
+
+	// RequireLastModified requires Last-Modified validator. By default,
+	// either ETag or Last-Modified is enough.
+	RequireLastModified bool
+
+	// ExpiryWait enables check of expiry behavior: if freshness lifetime
+	// doesn't exceed ExpiryWait, AssertCacheable waits until response
+	// becomes stale, repeats request, and checks that a fresh response
+	// is served, i.e. it's not a stale copy from intermediate cache.
+	// Ignored if zero.
+	ExpiryWait time.Duration
+}
+
+// names of requirements checked by AssertCacheable, used in failure message
+const (
+	cacheReqStorable       = "storable"
+	cacheReqFreshness      = "freshness"
+	cacheReqValidators     = "validators"
+	cacheReqIfNoneMatch    = "revalidation with If-None-Match"
+	cacheReqIfModSince     = "revalidation with If-Modified-Since"
+	cacheReqChangedETag    = "revalidation with changed ETag"
+	cacheReqExpiry         = "expiry"
+	cacheMismatchedETag    = `"httpexpect-mismatch"`
+	cacheExpiryGranularity = time.Second
+)
+
+// AssertCacheable checks that resource at given path can be cached and
+// revalidated according to HTTP caching rules (RFC 7232, RFC 7234).
+//
+// It performs the following sequence:
+//   - initial GET, which should return 200 response that may be stored
+//     (no "no-store", "private", or "Vary: *") and has positive freshness
+//     lifetime from "max-age", "s-maxage", or Expires header
+//   - if ETag is present, GET with matching If-None-Match, which should
+//     return 304 with empty body and the same ETag, and GET with different
+//     If-None-Match, which should return 200
+//   - if Last-Modified is present, GET with If-Modified-Since, which should
+//     return 304 with empty body
+//   - if enabled by opts.ExpiryWait, GET after response becomes stale,
+//     which should return fresh 200 response
+//
+// All requirements are checked, and failure lists every failed requirement.
+// Returns the initial response.
+//
+// Example:
+//
+//	e := httpexpect.Default(t, "http://example.com")
+//
+//	e.AssertCacheable("/static/app.js", httpexpect.CacheableOpts{
+//	    MinMaxAge:   time.Hour,
+//	    RequireETag: true,
+//	})
+func (e *Expect) AssertCacheable(path string, opts CacheableOpts) *Response {
+	e.chain.enter("AssertCacheable()")
+	defer e.chain.leave()
+
+	resp := e.Request(http.MethodGet, path).Expect()
+	resp.Status(http.StatusOK)
+
+	if resp.chain.failed() {
+		return resp
+	}
+
+	c := cacheableChecker{
+		expect: e,
+		path:   path,
+		opts:   opts,
+		resp:   resp,
+		header: resp.httpResp.Header,
+	}
+
+	c.checkStorable()
+	c.checkFreshness()
+	c.checkValidators()
+	c.checkETag()
+	c.checkLastModified()
+	c.checkExpiry()
+
+	if len(c.violations) != 0 {
+		resp.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{resp.httpResp.Header},
+			Errors: append([]error{
+				errors.New("expected: resource is cacheable"),
+			}, c.violations...),
+		})
+	}
+
+	return resp
+}
+
+type cacheableChecker struct {
+	expect *Expect
+	path   string
+	opts   CacheableOpts
+
+	resp   *Response
+	header http.Header
+
+	lifetime   time.Duration
+	violations []error
+}
+
+func (c *cacheableChecker) fail(requirement string, format string, args ...interface{}) {
+	c.violations = append(c.violations,
+		fmt.Errorf("requirement %q failed: %s", requirement, fmt.Sprintf(format, args...)))
+}
+
+// performs request with given validators; returns nil if request failed,
+// in which case failure is already reported
+func (c *cacheableChecker) request(etag, lastModified string) *Response {
+	resp := c.expect.Request(http.MethodGet, c.path).
+		withValidators(etag, lastModified).
+		Expect()
+	if resp.chain.failed() {
+		return nil
+	}
+
+	return resp
+}
+
+func (c *cacheableChecker) checkStorable() {
+	cc := parseCacheControl(c.header)
+
+	if _, ok := cc["no-store"]; ok {
+		c.fail(cacheReqStorable, `Cache-Control has "no-store" directive`)
+	}
+
+	if _, ok := cc["private"]; ok && !c.opts.AllowPrivate {
+		c.fail(cacheReqStorable, `Cache-Control has "private" directive`)
+	}
+
+	if strings.TrimSpace(c.header.Get("Vary")) == "*" {
+		c.fail(cacheReqStorable, `Vary is "*"`)
+	}
+}
+
+func (c *cacheableChecker) checkFreshness() {
+	cc := parseCacheControl(c.header)
+
+	lifetime, source, err := freshnessLifetime(c.header, cc, true, time.Time{})
+	if err != nil {
+		c.fail(cacheReqFreshness, "%s", err)
+		return
+	}
+
+	_, noCache := cc["no-cache"]
+
+	switch {
+	case source == "":
+		if !c.opts.AllowNoCache {
+			c.fail(cacheReqFreshness,
+				`response has no "max-age", "s-maxage", or Expires`)
+		}
+		return
+
+	case noCache || lifetime == 0:
+		if !c.opts.AllowNoCache {
+			c.fail(cacheReqFreshness,
+				"response has zero freshness lifetime (%s)", source)
+		}
+		return
+
+	case lifetime < c.opts.MinMaxAge:
+		c.fail(cacheReqFreshness, "freshness lifetime is %s (%s), expected at least %s",
+			lifetime, source, c.opts.MinMaxAge)
+	}
+
+	if v := c.header.Get("Age"); v != "" {
+		age, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			c.fail(cacheReqFreshness, "invalid Age %q", v)
+		} else if time.Duration(age)*time.Second >= lifetime {
+			c.fail(cacheReqFreshness, "response is already stale: Age is %ds, "+
+				"freshness lifetime is %s", age, lifetime)
+		}
+	}
+
+	c.lifetime = lifetime
+}
+
+func (c *cacheableChecker) checkValidators() {
+	etag := c.header.Get("ETag")
+	lastModified := c.header.Get("Last-Modified")
+
+	if etag == "" && lastModified == "" {
+		c.fail(cacheReqValidators, "response has neither ETag nor Last-Modified")
+		return
+	}
+
+	if etag == "" && c.opts.RequireETag {
+		c.fail(cacheReqValidators, "response has no ETag")
+	}
+
+	if lastModified == "" && c.opts.RequireLastModified {
+		c.fail(cacheReqValidators, "response has no Last-Modified")
+	}
+
+	if etag != "" && !isValidETag(etag) {
+		c.fail(cacheReqValidators, "invalid ETag %q, expected quoted string", etag)
+	}
+
+	if lastModified != "" {
+		if _, err := http.ParseTime(lastModified); err != nil {
+			c.fail(cacheReqValidators, "invalid Last-Modified %q", lastModified)
+		}
+	}
+}
+
+func isValidETag(etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	return len(etag) >= 2 && etag[0] == '"' && etag[len(etag)-1] == '"' &&
+		!strings.Contains(etag[1:len(etag)-1], `"`)
+}
+
+func (c *cacheableChecker) checkETag() {
+	etag := c.header.Get("ETag")
+	if etag == "" {
+		return
+	}
+
+	if resp := c.request(etag, ""); resp != nil {
+		c.checkNotModified(cacheReqIfNoneMatch, resp)
+	}
+
+	if resp := c.request(cacheMismatchedETag, ""); resp != nil {
+		if resp.httpResp.StatusCode != http.StatusOK {
+			c.fail(cacheReqChangedETag,
+				"response status is %d for non-matching If-None-Match, expected 200",
+				resp.httpResp.StatusCode)
+		}
+	}
+}
+
+func (c *cacheableChecker) checkLastModified() {
+	lastModified := c.header.Get("Last-Modified")
+	if lastModified == "" {
+		return
+	}
+
+	if resp := c.request("", lastModified); resp != nil {
+		c.checkNotModified(cacheReqIfModSince, resp)
+	}
+}
+
+func (c *cacheableChecker) checkNotModified(requirement string, resp *Response) {
+	for _, err := range checkNotModified(c.header, resp) {
+		c.fail(requirement, "%s", err)
+	}
+}
+
+func (c *cacheableChecker) checkExpiry() {
+	if c.opts.ExpiryWait <= 0 || c.lifetime <= 0 || c.lifetime > c.opts.ExpiryWait {
+		return
+	}
+
+	time.Sleep(c.lifetime + cacheExpiryGranularity)
+
+	resp := c.request("", "")
+	if resp == nil {
+		return
+	}
+
+	if resp.httpResp.StatusCode != http.StatusOK {
+		c.fail(cacheReqExpiry, "response status after expiry is %d, expected 200",
+			resp.httpResp.StatusCode)
+		return
+	}
+
+	if v := resp.httpResp.Header.Get("Age"); v != "" {
+		if age, err := strconv.ParseUint(v, 10, 32); err == nil &&
+			time.Duration(age)*time.Second >= c.lifetime {
+			c.fail(cacheReqExpiry, "stale response served after expiry: Age is %ds, "+
+				"freshness lifetime is %s", age, c.lifetime)
+		}
+	}
+
+	before, err1 := http.ParseTime(c.header.Get("Date"))
+	after, err2 := http.ParseTime(resp.httpResp.Header.Get("Date"))
+
+	if err1 == nil && err2 == nil && !after.After(before) {
+		c.fail(cacheReqExpiry, "Date didn't change after expiry (%s), "+
+			"response may be a stale copy", resp.httpResp.Header.Get("Date"))
+	}
+}
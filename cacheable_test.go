@@ -0,0 +1,419 @@
+package httpexpect
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpectAssertCacheable(t *testing.T) {
+	const lastModified = "Sat, 01 Jan 2022 00:00:00 GMT"
+
+	type server struct {
+		cacheControl string
+		expires      string
+		date         string
+		age          string
+		vary         string
+		etag         string
+		lastModified string
+		ignoreINM    bool
+		ignoreIMS    bool
+		alwaysNotMod bool
+		bodyOn304    bool
+		strip304CC   bool
+	}
+
+	handler := func(s server) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			if s.cacheControl != "" {
+				h.Set("Cache-Control", s.cacheControl)
+			}
+			if s.expires != "" {
+				h.Set("Expires", s.expires)
+			}
+			if s.date != "" {
+				h.Set("Date", s.date)
+			} else {
+				h.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+			}
+			if s.age != "" {
+				h.Set("Age", s.age)
+			}
+			if s.vary != "" {
+				h.Set("Vary", s.vary)
+			}
+			if s.etag != "" {
+				h.Set("ETag", s.etag)
+			}
+			if s.lastModified != "" {
+				h.Set("Last-Modified", s.lastModified)
+			}
+
+			inm := r.Header.Get("If-None-Match")
+			ims := r.Header.Get("If-Modified-Since")
+
+			notModified := s.alwaysNotMod && (inm != "" || ims != "") ||
+				!s.ignoreINM && inm != "" && inm == s.etag ||
+				!s.ignoreIMS && ims != "" && ims == s.lastModified
+
+			if notModified {
+				if s.strip304CC {
+					h.Del("Cache-Control")
+				}
+				w.WriteHeader(http.StatusNotModified)
+				if s.bodyOn304 {
+					_, _ = w.Write([]byte("hello"))
+				}
+				return
+			}
+
+			_, _ = w.Write([]byte("hello"))
+		}
+	}
+
+	newExpect := func(
+		s server, assertionHandler AssertionHandler,
+	) *Expect {
+		return WithConfig(Config{
+			BaseURL:          "http://example.com",
+			Client:           &http.Client{Transport: NewBinder(handler(s))},
+			AssertionHandler: assertionHandler,
+		})
+	}
+
+	good := server{
+		cacheControl: "public, max-age=3600",
+		etag:         `"v1"`,
+		lastModified: lastModified,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		cases := []struct {
+			name   string
+			server server
+			opts   CacheableOpts
+		}{
+			{
+				name:   "etag and last-modified",
+				server: good,
+				opts: CacheableOpts{
+					MinMaxAge:           time.Hour,
+					RequireETag:         true,
+					RequireLastModified: true,
+				},
+			},
+			{
+				name: "etag only",
+				server: server{
+					cacheControl: "max-age=60",
+					etag:         `W/"v1"`,
+				},
+			},
+			{
+				name: "last-modified only",
+				server: server{
+					cacheControl: "s-maxage=60",
+					lastModified: lastModified,
+				},
+			},
+			{
+				name: "expires",
+				server: server{
+					date:    "Sat, 01 Jan 2022 00:00:00 GMT",
+					expires: "Sat, 01 Jan 2022 01:00:00 GMT",
+					etag:    `"v1"`,
+				},
+				opts: CacheableOpts{MinMaxAge: time.Hour},
+			},
+			{
+				name: "allowed private",
+				server: server{
+					cacheControl: "private, max-age=60",
+					etag:         `"v1"`,
+				},
+				opts: CacheableOpts{AllowPrivate: true},
+			},
+			{
+				name: "allowed no-cache",
+				server: server{
+					cacheControl: "no-cache",
+					etag:         `"v1"`,
+				},
+				opts: CacheableOpts{AllowNoCache: true},
+			},
+			{
+				name: "fresh age",
+				server: server{
+					cacheControl: "max-age=60",
+					age:          "10",
+					etag:         `"v1"`,
+				},
+			},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				handler := &mockAssertionHandler{}
+				e := newExpect(tc.server, handler)
+
+				resp := e.AssertCacheable("/path", tc.opts)
+				resp.chain.assertOK(t)
+
+				assert.Nil(t, handler.failure)
+				assert.Equal(t, http.StatusOK, resp.Raw().StatusCode)
+			})
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		cases := []struct {
+			name   string
+			server server
+			opts   CacheableOpts
+			errors []string
+		}{
+			{
+				name: "no-store",
+				server: server{
+					cacheControl: "no-store, max-age=60",
+					etag:         `"v1"`,
+				},
+				errors: []string{`"storable"`, `"no-store"`},
+			},
+			{
+				name: "private",
+				server: server{
+					cacheControl: "private, max-age=60",
+					etag:         `"v1"`,
+				},
+				errors: []string{`"storable"`, `"private"`},
+			},
+			{
+				name: "vary star",
+				server: server{
+					cacheControl: "max-age=60",
+					vary:         "*",
+					etag:         `"v1"`,
+				},
+				errors: []string{`"storable"`, `Vary`},
+			},
+			{
+				name: "no freshness",
+				server: server{
+					etag: `"v1"`,
+				},
+				errors: []string{`"freshness"`},
+			},
+			{
+				name: "no-cache",
+				server: server{
+					cacheControl: "no-cache, max-age=60",
+					etag:         `"v1"`,
+				},
+				errors: []string{`"freshness"`, "zero freshness lifetime"},
+			},
+			{
+				name: "short max-age",
+				server: server{
+					cacheControl: "max-age=60",
+					etag:         `"v1"`,
+				},
+				opts:   CacheableOpts{MinMaxAge: time.Hour},
+				errors: []string{`"freshness"`, "expected at least 1h0m0s"},
+			},
+			{
+				name: "invalid max-age",
+				server: server{
+					cacheControl: "max-age=abc",
+					etag:         `"v1"`,
+				},
+				errors: []string{`"freshness"`, "invalid"},
+			},
+			{
+				name: "stale age",
+				server: server{
+					cacheControl: "max-age=60",
+					age:          "60",
+					etag:         `"v1"`,
+				},
+				errors: []string{`"freshness"`, "already stale"},
+			},
+			{
+				name: "expires without date",
+				server: server{
+					date:    "invalid",
+					expires: "Sat, 01 Jan 2022 01:00:00 GMT",
+					etag:    `"v1"`,
+				},
+				errors: []string{`"freshness"`, "Date"},
+			},
+			{
+				name: "no validators",
+				server: server{
+					cacheControl: "max-age=60",
+				},
+				errors: []string{`"validators"`, "neither ETag nor Last-Modified"},
+			},
+			{
+				name: "required etag",
+				server: server{
+					cacheControl: "max-age=60",
+					lastModified: lastModified,
+				},
+				opts:   CacheableOpts{RequireETag: true},
+				errors: []string{`"validators"`, "no ETag"},
+			},
+			{
+				name: "required last-modified",
+				server: server{
+					cacheControl: "max-age=60",
+					etag:         `"v1"`,
+				},
+				opts:   CacheableOpts{RequireLastModified: true},
+				errors: []string{`"validators"`, "no Last-Modified"},
+			},
+			{
+				name: "unquoted etag",
+				server: server{
+					cacheControl: "max-age=60",
+					etag:         "v1",
+				},
+				errors: []string{`"validators"`, "invalid ETag"},
+			},
+			{
+				name: "if-none-match ignored",
+				server: server{
+					cacheControl: "max-age=60",
+					etag:         `"v1"`,
+					ignoreINM:    true,
+				},
+				errors: []string{`"revalidation with If-None-Match"`, "expected 304"},
+			},
+			{
+				name: "if-modified-since ignored",
+				server: server{
+					cacheControl: "max-age=60",
+					lastModified: lastModified,
+					ignoreIMS:    true,
+				},
+				errors: []string{`"revalidation with If-Modified-Since"`, "expected 304"},
+			},
+			{
+				name: "changed etag not honored",
+				server: server{
+					cacheControl: "max-age=60",
+					etag:         `"v1"`,
+					alwaysNotMod: true,
+				},
+				errors: []string{`"revalidation with changed ETag"`, "expected 200"},
+			},
+			{
+				name: "304 without cache-control",
+				server: server{
+					cacheControl: "max-age=60",
+					etag:         `"v1"`,
+					strip304CC:   true,
+				},
+				errors: []string{`"revalidation with If-None-Match"`, "no Cache-Control"},
+			},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				handler := &mockAssertionHandler{}
+				e := newExpect(tc.server, handler)
+
+				resp := e.AssertCacheable("/path", tc.opts)
+				resp.chain.assertFailed(t)
+
+				require.NotNil(t, handler.failure)
+				assert.Equal(t, AssertValid, handler.failure.Type)
+
+				text := ""
+				for _, err := range handler.failure.Errors {
+					text += err.Error() + "\n"
+				}
+				assert.Contains(t, text, "expected: resource is cacheable")
+				for _, s := range tc.errors {
+					assert.Contains(t, text, s)
+				}
+			})
+		}
+	})
+
+	t.Run("all violations reported", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+		e := newExpect(server{
+			cacheControl: "no-store",
+		}, handler)
+
+		e.AssertCacheable("/path", CacheableOpts{}).chain.assertFailed(t)
+
+		require.NotNil(t, handler.failure)
+		assert.Len(t, handler.failure.Errors, 4)
+	})
+
+	t.Run("bad status", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  "http://example.com",
+			Reporter: newMockReporter(t),
+			Client: &http.Client{
+				Transport: NewBinder(http.NotFoundHandler()),
+			},
+		})
+
+		e.AssertCacheable("/path", CacheableOpts{}).chain.assertFailed(t)
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("waits for response expiry")
+		}
+
+		t.Run("success", func(t *testing.T) {
+			handler := &mockAssertionHandler{}
+			e := newExpect(server{
+				cacheControl: "max-age=1",
+				etag:         `"v1"`,
+			}, handler)
+
+			e.AssertCacheable("/path", CacheableOpts{
+				ExpiryWait: time.Second,
+			}).chain.assertOK(t)
+		})
+
+		t.Run("stale copy", func(t *testing.T) {
+			handler := &mockAssertionHandler{}
+			e := newExpect(server{
+				cacheControl: "max-age=1",
+				date:         "Sat, 01 Jan 2022 00:00:00 GMT",
+				etag:         `"v1"`,
+			}, handler)
+
+			e.AssertCacheable("/path", CacheableOpts{
+				ExpiryWait: time.Second,
+			}).chain.assertFailed(t)
+
+			require.NotNil(t, handler.failure)
+			assert.Contains(t,
+				handler.failure.Errors[len(handler.failure.Errors)-1].Error(),
+				`"expiry"`)
+		})
+
+		t.Run("long lifetime skipped", func(t *testing.T) {
+			handler := &mockAssertionHandler{}
+			e := newExpect(good, handler)
+
+			start := time.Now()
+			e.AssertCacheable("/path", CacheableOpts{
+				ExpiryWait: time.Second,
+			}).chain.assertOK(t)
+			assert.Less(t, int64(time.Since(start)), int64(time.Second))
+		})
+	})
+}
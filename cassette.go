@@ -0,0 +1,459 @@
+package httpexpect
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CassetteMode defines whether Cassette records or replays interactions.
+type CassetteMode int
+
+const (
+	// CassetteAuto replays interactions if cassette file exists, and
+	// records them otherwise.
+	CassetteAuto CassetteMode = iota
+
+	// CassetteRecord sends requests to real transport and records
+	// interactions, overwriting existing cassette file.
+	CassetteRecord
+
+	// CassetteReplay serves requests from cassette file without network.
+	// Requests that don't match any recorded interaction fail.
+	CassetteReplay
+)
+
+// CassetteRequest holds recorded request, passed to CassetteMatcher.
+type CassetteRequest struct {
+	Method     string
+	URL        string
+	Header     http.Header
+	Body       []byte
+	BodySHA256 string
+}
+
+// CassetteMatcher reports whether request matches recorded request.
+// body is the body of request, nil if request has no body.
+type CassetteMatcher func(req *http.Request, body []byte, recorded *CassetteRequest) bool
+
+// CassetteMatchMethod matches requests with equal methods.
+func CassetteMatchMethod(req *http.Request, _ []byte, recorded *CassetteRequest) bool {
+	return req.Method == recorded.Method
+}
+
+// CassetteMatchURL matches requests with equal URLs, including query string.
+func CassetteMatchURL(req *http.Request, _ []byte, recorded *CassetteRequest) bool {
+	return req.URL.String() == recorded.URL
+}
+
+// CassetteMatchPath matches requests with equal URL paths.
+func CassetteMatchPath(req *http.Request, _ []byte, recorded *CassetteRequest) bool {
+	u, err := url.Parse(recorded.URL)
+	return err == nil && req.URL.Path == u.Path
+}
+
+// CassetteMatchQuery matches requests with equal query parameters,
+// regardless of their order.
+func CassetteMatchQuery(req *http.Request, _ []byte, recorded *CassetteRequest) bool {
+	u, err := url.Parse(recorded.URL)
+	return err == nil && reflect.DeepEqual(req.URL.Query(), u.Query())
+}
+
+// CassetteMatchBody matches requests with equal SHA-256 hashes of bodies.
+func CassetteMatchBody(_ *http.Request, body []byte, recorded *CassetteRequest) bool {
+	return cassetteHash(body) == recorded.BodySHA256
+}
+
+// CassetteMatchHeaders returns matcher that matches requests with equal
+// values of given headers.
+func CassetteMatchHeaders(names ...string) CassetteMatcher {
+	return func(req *http.Request, _ []byte, recorded *CassetteRequest) bool {
+		for _, name := range names {
+			if !reflect.DeepEqual(req.Header.Values(name), recorded.Header.Values(name)) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// CassetteOpts defines additional options for Cassette.
+type CassetteOpts struct {
+	// Mode defines whether cassette records or replays interactions.
+	// Default is CassetteAuto.
+	Mode CassetteMode
+
+	// Transport is used to send requests in record mode.
+	// Default is http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// Matchers define which recorded interaction is used for request
+	// in replay mode. Request should match all matchers.
+	// Default is CassetteMatchMethod and CassetteMatchURL.
+	Matchers []CassetteMatcher
+
+	// SkipHeaders is a list of request headers that are not recorded.
+	// Default is Authorization and Cookie, to keep secrets out of
+	// cassette files.
+	SkipHeaders []string
+}
+
+// Cassette implements record-and-replay http.RoundTripper.
+//
+// In record mode, requests are sent using real transport, and every
+// interaction (request and response) is saved to cassette file.
+// In replay mode, requests are matched against recorded interactions and
+// served from cassette file, without network.
+//
+// If cassette file has ".yaml" or ".yml" extension, it is stored in YAML
+// format, otherwise in JSON format.
+//
+// When several recorded interactions match request, they are replayed in
+// order of recording; after all of them were used, the last one is reused.
+//
+// Cassette is loaded on first request. Errors, like missing or malformed
+// cassette file in replay mode, are returned from RoundTrip.
+//
+// Cassette is safe for concurrent use.
+//
+// Example:
+//
+//	cassette := httpexpect.NewCassette("testdata/users.yaml")
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:  "https://api.example.com",
+//	    Reporter: httpexpect.NewAssertReporter(t),
+//	    Client: &http.Client{
+//	        Transport: cassette,
+//	    },
+//	})
+type Cassette struct {
+	path string
+	opts CassetteOpts
+
+	mu           sync.Mutex
+	loaded       bool
+	loadErr      error
+	mode         CassetteMode
+	interactions []cassetteInteraction
+	used         []bool
+}
+
+// NewCassette returns a new Cassette for given file.
+func NewCassette(path string, opts ...CassetteOpts) *Cassette {
+	c := &Cassette{
+		path: path,
+	}
+
+	if len(opts) != 0 {
+		c.opts = opts[0]
+	}
+
+	if c.opts.Transport == nil {
+		c.opts.Transport = http.DefaultTransport
+	}
+
+	if c.opts.Matchers == nil {
+		c.opts.Matchers = []CassetteMatcher{
+			CassetteMatchMethod,
+			CassetteMatchURL,
+		}
+	}
+
+	if c.opts.SkipHeaders == nil {
+		c.opts.SkipHeaders = []string{"Authorization", "Cookie"}
+	}
+
+	return c
+}
+
+// Len returns number of interactions in cassette.
+func (c *Cassette) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.interactions)
+}
+
+// RoundTrip implements http.RoundTripper.RoundTrip.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	c.mu.Lock()
+	err := c.load()
+	mode := c.mode
+	c.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == CassetteReplay {
+		return c.replay(req, body)
+	}
+
+	return c.record(req, body)
+}
+
+func (c *Cassette) load() error {
+	if c.loaded {
+		return c.loadErr
+	}
+	c.loaded = true
+
+	c.mode = c.opts.Mode
+
+	if c.mode == CassetteAuto {
+		if _, err := os.Stat(c.path); err == nil {
+			c.mode = CassetteReplay
+		} else {
+			c.mode = CassetteRecord
+		}
+	}
+
+	if c.mode == CassetteRecord {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		c.loadErr = fmt.Errorf("cassette %q: %s", c.path, err)
+		return c.loadErr
+	}
+
+	var file cassetteFile
+	if c.isYAML() {
+		err = yaml.Unmarshal(data, &file)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		c.loadErr = fmt.Errorf("cassette %q: %s", c.path, err)
+		return c.loadErr
+	}
+
+	c.interactions = file.Interactions
+	c.used = make([]bool, len(file.Interactions))
+
+	return nil
+}
+
+func (c *Cassette) isYAML() bool {
+	ext := strings.ToLower(filepath.Ext(c.path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func (c *Cassette) replay(req *http.Request, body []byte) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	matched := -1
+
+	for i := range c.interactions {
+		recorded := c.interactions[i].Request.decode()
+
+		ok := true
+		for _, matcher := range c.opts.Matchers {
+			if !matcher(req, body, recorded) {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		matched = i
+		if !c.used[i] {
+			break
+		}
+	}
+
+	if matched < 0 {
+		return nil, fmt.Errorf("cassette %q: no recorded interaction matches %s %s",
+			c.path, req.Method, req.URL.String())
+	}
+
+	c.used[matched] = true
+
+	recorded := c.interactions[matched].Response
+	respBody := decodeCassetteBody(recorded.Body, recorded.BodyEncoding)
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header(recorded.Headers).Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+	}, nil
+}
+
+func (c *Cassette) record(req *http.Request, body []byte) (*http.Response, error) {
+	sendReq := req.Clone(req.Context())
+	if body != nil {
+		sendReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+		sendReq.ContentLength = int64(len(body))
+	}
+
+	resp, err := c.opts.Transport.RoundTrip(sendReq)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	header := req.Header.Clone()
+	for _, name := range c.opts.SkipHeaders {
+		header.Del(name)
+	}
+
+	interaction := cassetteInteraction{
+		Request: cassetteRequest{
+			Method:     req.Method,
+			URL:        req.URL.String(),
+			Headers:    header,
+			BodySHA256: cassetteHash(body),
+		},
+		Response: cassetteResponse{
+			Status:  resp.StatusCode,
+			Headers: resp.Header.Clone(),
+		},
+	}
+
+	interaction.Request.Body, interaction.Request.BodyEncoding =
+		encodeCassetteBody(body)
+	interaction.Response.Body, interaction.Response.BodyEncoding =
+		encodeCassetteBody(respBody)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.interactions = append(c.interactions, interaction)
+	c.used = append(c.used, true)
+
+	if err := c.save(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Cassette) save() error {
+	file := cassetteFile{
+		Interactions: c.interactions,
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if c.isYAML() {
+		data, err = yaml.Marshal(file)
+	} else {
+		data, err = json.MarshalIndent(file, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("cassette %q: %s", c.path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("cassette %q: %s", c.path, err)
+	}
+
+	if err := ioutil.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("cassette %q: %s", c.path, err)
+	}
+
+	return nil
+}
+
+type cassetteFile struct {
+	Interactions []cassetteInteraction `json:"interactions" yaml:"interactions"`
+}
+
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request" yaml:"request"`
+	Response cassetteResponse `json:"response" yaml:"response"`
+}
+
+type cassetteRequest struct {
+	Method       string              `json:"method" yaml:"method"`
+	URL          string              `json:"url" yaml:"url"`
+	Headers      map[string][]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body         string              `json:"body,omitempty" yaml:"body,omitempty"`
+	BodyEncoding string              `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+	BodySHA256   string              `json:"body_sha256" yaml:"body_sha256"`
+}
+
+type cassetteResponse struct {
+	Status       int                 `json:"status" yaml:"status"`
+	Headers      map[string][]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Body         string              `json:"body,omitempty" yaml:"body,omitempty"`
+	BodyEncoding string              `json:"encoding,omitempty" yaml:"encoding,omitempty"`
+}
+
+func (r *cassetteRequest) decode() *CassetteRequest {
+	return &CassetteRequest{
+		Method:     r.Method,
+		URL:        r.URL,
+		Header:     http.Header(r.Headers),
+		Body:       decodeCassetteBody(r.Body, r.BodyEncoding),
+		BodySHA256: r.BodySHA256,
+	}
+}
+
+// binary bodies are base64-encoded
+func encodeCassetteBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func decodeCassetteBody(body, encoding string) []byte {
+	if encoding == "base64" {
+		b, err := base64.StdEncoding.DecodeString(body)
+		if err == nil {
+			return b
+		}
+	}
+	return []byte(body)
+}
+
+func cassetteHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,229 @@
+package httpexpect
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("network is not available")
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	for _, ext := range []string{".json", ".yaml"} {
+		t.Run(ext, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "httpexpect")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "cassettes", "test"+ext)
+
+			counter := 0
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				counter++
+				body, _ := ioutil.ReadAll(r.Body)
+				switch r.URL.Path {
+				case "/counter":
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write([]byte(`{"counter": ` + string(rune('0'+counter)) + `}`))
+				case "/echo":
+					_, _ = w.Write(body)
+				case "/binary":
+					_, _ = w.Write([]byte{0xff, 0x00, 0xfe})
+				}
+			})
+
+			recorder := NewCassette(path, CassetteOpts{
+				Transport: NewBinder(handler),
+			})
+
+			e := WithConfig(Config{
+				BaseURL:  "http://example.com",
+				Client:   &http.Client{Transport: recorder},
+				Reporter: newMockReporter(t),
+			})
+
+			e.GET("/counter").WithHeader("Authorization", "secret").
+				Expect().JSON().Object().ValueEqual("counter", 1)
+			e.GET("/counter").
+				Expect().JSON().Object().ValueEqual("counter", 2)
+			e.POST("/echo").WithText("hello").
+				Expect().Body().Equal("hello")
+			e.GET("/binary").
+				Expect().Body().Equal("\xff\x00\xfe")
+
+			assert.Equal(t, 4, recorder.Len())
+			assert.Equal(t, 4, counter)
+
+			data, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			assert.False(t, strings.Contains(string(data), "secret"))
+
+			replayer := NewCassette(path, CassetteOpts{
+				Transport: failingTransport{},
+			})
+
+			e = WithConfig(Config{
+				BaseURL:  "http://example.com",
+				Client:   &http.Client{Transport: replayer},
+				Reporter: newMockReporter(t),
+			})
+
+			e.GET("/counter").
+				Expect().JSON().Object().ValueEqual("counter", 1)
+			e.GET("/counter").
+				Expect().JSON().Object().ValueEqual("counter", 2)
+			e.GET("/counter").
+				Expect().JSON().Object().ValueEqual("counter", 2)
+			e.POST("/echo").WithText("hello").
+				Expect().Body().Equal("hello")
+			e.GET("/binary").
+				Expect().Body().Equal("\xff\x00\xfe")
+
+			assert.Equal(t, 4, counter)
+
+			resp := e.GET("/missing").Expect()
+			resp.chain.assertFailed(t)
+		})
+	}
+}
+
+func TestCassetteMatchers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "test.json")
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		_, _ = w.Write([]byte(r.URL.RawQuery + ":" + r.Header.Get("X-Tenant") + ":" +
+			string(body)))
+	})
+
+	e := WithConfig(Config{
+		BaseURL: "http://example.com",
+		Client: &http.Client{Transport: NewCassette(path, CassetteOpts{
+			Mode:      CassetteRecord,
+			Transport: NewBinder(handler),
+		})},
+		Reporter: newMockReporter(t),
+	})
+
+	e.POST("/a").WithQueryString("x=1&y=2").WithHeader("X-Tenant", "t1").
+		WithText("body1").Expect()
+	e.POST("/a").WithQueryString("x=2").WithHeader("X-Tenant", "t2").
+		WithText("body2").Expect()
+
+	cases := []struct {
+		name     string
+		matchers []CassetteMatcher
+		request  func(e *Expect) *Request
+		result   string
+	}{
+		{
+			name: "query order",
+			matchers: []CassetteMatcher{
+				CassetteMatchMethod, CassetteMatchPath, CassetteMatchQuery,
+			},
+			request: func(e *Expect) *Request {
+				return e.POST("/a").WithQueryString("y=2&x=1")
+			},
+			result: "x=1&y=2:t1:body1",
+		},
+		{
+			name:     "body",
+			matchers: []CassetteMatcher{CassetteMatchPath, CassetteMatchBody},
+			request: func(e *Expect) *Request {
+				return e.POST("/a").WithText("body2")
+			},
+			result: "x=2:t2:body2",
+		},
+		{
+			name:     "header",
+			matchers: []CassetteMatcher{CassetteMatchHeaders("X-Tenant")},
+			request: func(e *Expect) *Request {
+				return e.GET("/b").WithHeader("X-Tenant", "t2")
+			},
+			result: "x=2:t2:body2",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := WithConfig(Config{
+				BaseURL: "http://example.com",
+				Client: &http.Client{Transport: NewCassette(path, CassetteOpts{
+					Mode:      CassetteReplay,
+					Transport: failingTransport{},
+					Matchers:  tc.matchers,
+				})},
+				Reporter: newMockReporter(t),
+			})
+
+			tc.request(e).Expect().Body().Equal(tc.result)
+		})
+	}
+
+	t.Run("no match", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL: "http://example.com",
+			Client: &http.Client{Transport: NewCassette(path, CassetteOpts{
+				Mode:     CassetteReplay,
+				Matchers: []CassetteMatcher{CassetteMatchBody},
+			})},
+			Reporter: newMockReporter(t),
+		})
+
+		e.POST("/a").WithText("body3").Expect().chain.assertFailed(t)
+	})
+}
+
+func TestCassetteErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	req, err := http.NewRequest("GET", "http://example.com", nil)
+	require.NoError(t, err)
+
+	t.Run("missing", func(t *testing.T) {
+		cassette := NewCassette(filepath.Join(dir, "missing.json"), CassetteOpts{
+			Mode: CassetteReplay,
+		})
+
+		_, err := cassette.RoundTrip(req)
+		assert.Error(t, err)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		path := filepath.Join(dir, "bad.yaml")
+		require.NoError(t, ioutil.WriteFile(path, []byte("\tbad: ["), 0644))
+
+		cassette := NewCassette(path)
+
+		_, err := cassette.RoundTrip(req)
+		assert.Error(t, err)
+	})
+
+	t.Run("transport", func(t *testing.T) {
+		cassette := NewCassette(filepath.Join(dir, "new.json"), CassetteOpts{
+			Transport: failingTransport{},
+		})
+
+		_, err := cassette.RoundTrip(req)
+		assert.Error(t, err)
+		assert.Equal(t, 0, cassette.Len())
+	})
+}
@@ -2,22 +2,65 @@ package httpexpect
 
 import (
 	"fmt"
+	"time"
 )
 
 type chain struct {
+	context   AssertionContext
+	handler   AssertionHandler
+	onFailure func(*AssertionContext, *AssertionFailure)
+	isFatal   bool
+	severity  AssertionSeverity
+	failCb    func()
+	failbit   bool
+	soft      *softCollector
+
+	assertions map[string]AssertionFunc
+}
+
+// collects failures of chain in soft mode and its children
+type softCollector struct {
+	root     *chain
+	depth    int
+	failures []softFailure
+}
+
+type softFailure struct {
 	context AssertionContext
-	handler AssertionHandler
-	isFatal bool
-	failCb  func()
-	failbit bool
+	failure AssertionFailure
 }
 
 func newChainWithConfig(name string, config Config) *chain {
 	c := &chain{
-		context: AssertionContext{},
-		handler: config.AssertionHandler,
-		isFatal: true,
-		failbit: false,
+		context:   AssertionContext{},
+		handler:   config.AssertionHandler,
+		onFailure: config.OnFailure,
+		isFatal:   true,
+		failbit:   false,
+
+		assertions: config.Assertions,
+	}
+
+	if len(config.AssertionHandlers) != 0 {
+		c.handler = &TeeAssertionHandler{
+			Handlers: append([]AssertionHandler{config.AssertionHandler},
+				config.AssertionHandlers...),
+		}
+	}
+
+	if config.Inspector != nil {
+		c.handler = &inspectorHandler{config.Inspector, c.handler}
+	}
+
+	if config.Metrics != nil {
+		metrics, onFailure := config.Metrics, config.OnFailure
+
+		c.onFailure = func(ctx *AssertionContext, failure *AssertionFailure) {
+			metrics.ObserveFailure(failure)
+			if onFailure != nil {
+				onFailure(ctx, failure)
+			}
+		}
 	}
 
 	c.context.TestName = config.TestName
@@ -67,6 +110,10 @@ func (c *chain) setFatal(isFatal bool) {
 	c.isFatal = isFatal
 }
 
+func (c *chain) setSeverity(severity AssertionSeverity) {
+	c.severity = severity
+}
+
 func (c *chain) setFailCallback(failCb func()) {
 	c.failCb = failCb
 }
@@ -83,6 +130,14 @@ func (c *chain) setResponse(resp *Response) {
 	c.context.Response = resp
 }
 
+func (c *chain) setRoundTripTime(rtt *time.Duration) {
+	c.context.RoundTripTime = rtt
+}
+
+func (c *chain) setWebsocket(ws *Websocket) {
+	c.context.Websocket = ws
+}
+
 func (c *chain) clone() *chain {
 	ret := *c
 
@@ -110,6 +165,24 @@ func (c *chain) leave() {
 	}
 
 	c.context.Path = c.context.Path[:len(c.context.Path)-1]
+
+	// in soft mode, failure of one assertion doesn't prevent next
+	// assertions on the same chain; children of failed assertion
+	// remain failed
+	if c.soft != nil && c.soft.root == c && len(c.context.Path) == c.soft.depth {
+		c.failbit = false
+	}
+}
+
+// enables soft mode for chain; failures of chain and its children are
+// collected instead of being reported
+func (c *chain) setSoft() *softCollector {
+	c.soft = &softCollector{
+		root:  c,
+		depth: len(c.context.Path),
+	}
+
+	return c.soft
 }
 
 func (c *chain) fail(failure AssertionFailure) {
@@ -118,10 +191,40 @@ func (c *chain) fail(failure AssertionFailure) {
 	}
 	c.failbit = true
 
+	// warnings are reported immediately even in soft mode, and don't
+	// affect parent chains
+	if c.severity == SeverityWarning {
+		failure.Severity = SeverityWarning
+		failure.IsFatal = false
+
+		if c.onFailure != nil {
+			c.onFailure(&c.context, &failure)
+		}
+
+		c.handler.Failure(&c.context, &failure)
+		return
+	}
+
+	if c.soft != nil {
+		ctx := c.context
+		ctx.Path = append([]string(nil), c.context.Path...)
+
+		c.soft.failures = append(c.soft.failures, softFailure{ctx, failure})
+
+		if c.failCb != nil {
+			c.failCb()
+		}
+		return
+	}
+
 	if c.isFatal {
 		failure.IsFatal = true
 	}
 
+	if c.onFailure != nil {
+		c.onFailure(&c.context, &failure)
+	}
+
 	c.handler.Failure(&c.context, &failure)
 
 	if c.failCb != nil {
@@ -0,0 +1,235 @@
+package httpexpect
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChaosOpts defines behavior of Chaos.
+type ChaosOpts struct {
+	// Seed of random generator. Same seed and same sequence of requests
+	// produce same decisions. If zero, seed is generated from current time.
+	// Seed is included in failure output, so failed run can be reproduced.
+	Seed int64
+
+	// DuplicateRate is a probability in range [0; 1] that request is sent
+	// twice. Response to the first copy is discarded.
+	DuplicateRate float64
+
+	// DelayRate is a probability in range [0; 1] that request is delayed
+	// before sending.
+	DelayRate float64
+
+	// MaxDelay is the upper bound of delay; actual delay is chosen
+	// randomly in range [0; MaxDelay].
+	MaxDelay time.Duration
+
+	// Methods that may be duplicated. Default is idempotent methods, as
+	// defined by RFC 7231: GET, HEAD, PUT, DELETE, OPTIONS, and TRACE.
+	Methods []string
+
+	// Check is invoked after every request affected by chaos, i.e. delayed
+	// or duplicated, and should verify that state of the system is still
+	// consistent, e.g. that duplicated PUT didn't create a second resource.
+	// If it returns error, response assertion fails.
+	//
+	// Check receives a copy of Expect instance that was used to create
+	// request. Requests created using this copy are not affected by chaos,
+	// while other requests, including concurrent ones, are.
+	Check func(e *Expect, event ChaosEvent) error
+}
+
+// ChaosEvent describes chaos applied to a request.
+type ChaosEvent struct {
+	// Seed of Chaos that generated event.
+	Seed int64
+
+	// Request method and URL.
+	Method string
+	URL    string
+
+	// True if request was sent twice.
+	Duplicated bool
+
+	// Delay added before sending request.
+	Delay time.Duration
+}
+
+// String returns human-readable description of event.
+func (ev ChaosEvent) String() string {
+	var effects []string
+
+	if ev.Duplicated {
+		effects = append(effects, "duplicated")
+	}
+	if ev.Delay > 0 {
+		effects = append(effects, fmt.Sprintf("delayed by %s", ev.Delay))
+	}
+	if len(effects) == 0 {
+		effects = append(effects, "not affected")
+	}
+
+	return fmt.Sprintf("chaos seed %d: %s %s %s",
+		ev.Seed, ev.Method, ev.URL, strings.Join(effects, ", "))
+}
+
+func (ev ChaosEvent) affected() bool {
+	return ev.Duplicated || ev.Delay > 0
+}
+
+// Chaos randomly duplicates and delays requests, to verify that endpoints
+// are idempotent and tolerate retries and timeouts.
+//
+// Chaos is enabled by setting Config.Chaos. Randomness is seeded, so a
+// failed run can be reproduced by setting ChaosOpts.Seed to the value
+// printed in failure output.
+//
+// Chaos is safe for concurrent use, but decisions are deterministic only
+// if requests are sent sequentially.
+//
+// Example:
+//
+//	chaos := httpexpect.NewChaos(httpexpect.ChaosOpts{
+//	    DuplicateRate: 0.3,
+//	    DelayRate:     0.3,
+//	    MaxDelay:      200 * time.Millisecond,
+//	    Check: func(e *httpexpect.Expect, event httpexpect.ChaosEvent) error {
+//	        if n := db.CountOrders(); n != 1 {
+//	            return fmt.Errorf("expected 1 order, got %d", n)
+//	        }
+//	        return nil
+//	    },
+//	})
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:  server.URL,
+//	    Reporter: httpexpect.NewAssertReporter(t),
+//	    Chaos:    chaos,
+//	})
+type Chaos struct {
+	opts    ChaosOpts
+	seed    int64
+	methods map[string]bool
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewChaos returns a new Chaos with given options.
+func NewChaos(opts ChaosOpts) *Chaos {
+	c := &Chaos{
+		opts:    opts,
+		seed:    opts.Seed,
+		methods: map[string]bool{},
+	}
+
+	if c.seed == 0 {
+		c.seed = time.Now().UnixNano()
+	}
+
+	c.rand = rand.New(rand.NewSource(c.seed))
+
+	methods := opts.Methods
+	if methods == nil {
+		methods = []string{
+			http.MethodGet, http.MethodHead, http.MethodPut,
+			http.MethodDelete, http.MethodOptions, http.MethodTrace,
+		}
+	}
+
+	for _, m := range methods {
+		c.methods[strings.ToUpper(m)] = true
+	}
+
+	return c
+}
+
+// Seed returns seed of random generator.
+func (c *Chaos) Seed() int64 {
+	return c.seed
+}
+
+// decides what to do with request
+func (c *Chaos) next(req *http.Request) *ChaosEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// always consume the same amount of random numbers per request,
+	// so that decisions don't depend on options of previous requests
+	dup := c.rand.Float64()
+	delay := c.rand.Float64()
+	delayValue := c.rand.Int63()
+
+	event := &ChaosEvent{
+		Seed:   c.seed,
+		Method: req.Method,
+		URL:    req.URL.String(),
+	}
+
+	if dup < c.opts.DuplicateRate && c.methods[req.Method] {
+		event.Duplicated = true
+	}
+
+	if delay < c.opts.DelayRate && c.opts.MaxDelay > 0 {
+		event.Delay = time.Duration(delayValue % (int64(c.opts.MaxDelay) + 1))
+	}
+
+	return event
+}
+
+// sends request via client, applying chaos to it
+func (c *Chaos) do(
+	client Client, req *http.Request,
+) (*http.Response, *ChaosEvent, error) {
+	event := c.next(req)
+
+	if event.Delay > 0 {
+		timer := time.NewTimer(event.Delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, event, req.Context().Err()
+		}
+	}
+
+	body, rewindable := req.Body.(*bodyWrapper)
+
+	if event.Duplicated && (req.Body == nil || req.Body == http.NoBody || rewindable) {
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, event, err
+		}
+		if resp.Body != nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		if rewindable {
+			body.Rewind()
+		}
+	} else {
+		event.Duplicated = false
+	}
+
+	resp, err := client.Do(req)
+
+	return resp, event, err
+}
+
+// invokes user check; requests made by check using given Expect are not
+// affected by chaos
+func (c *Chaos) check(e *Expect, event ChaosEvent) error {
+	if c.opts.Check == nil || !event.affected() {
+		return nil
+	}
+
+	return c.opts.Check(e.Builder(func(req *Request) {
+		req.skipChaos = true
+	}), event)
+}
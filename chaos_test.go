@@ -0,0 +1,226 @@
+package httpexpect
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChaosDeterministic(t *testing.T) {
+	opts := ChaosOpts{
+		Seed:          42,
+		DuplicateRate: 0.5,
+		DelayRate:     0.5,
+		MaxDelay:      time.Second,
+	}
+
+	req, err := http.NewRequest("PUT", "http://example.com/path", nil)
+	require.NoError(t, err)
+
+	sequence := func() []ChaosEvent {
+		c := NewChaos(opts)
+
+		var events []ChaosEvent
+		for i := 0; i < 20; i++ {
+			events = append(events, *c.next(req))
+		}
+		return events
+	}
+
+	events := sequence()
+	assert.Equal(t, events, sequence())
+
+	duplicated, delayed := 0, 0
+	for _, ev := range events {
+		assert.Equal(t, int64(42), ev.Seed)
+		if ev.Duplicated {
+			duplicated++
+		}
+		if ev.Delay > 0 {
+			delayed++
+			assert.True(t, ev.Delay <= time.Second)
+		}
+	}
+
+	assert.NotEqual(t, 0, duplicated)
+	assert.NotEqual(t, 20, duplicated)
+	assert.NotEqual(t, 0, delayed)
+	assert.NotEqual(t, 20, delayed)
+
+	assert.NotEqual(t, int64(0), NewChaos(ChaosOpts{}).Seed())
+}
+
+func TestChaosDuplicate(t *testing.T) {
+	var bodies []string
+	orders := 0
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, r.Method+" "+string(b))
+
+		switch r.Method {
+		case "POST":
+			orders++
+			w.WriteHeader(http.StatusCreated)
+		case "PUT":
+			orders = 1
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+
+	var checked []ChaosEvent
+
+	chaos := NewChaos(ChaosOpts{
+		Seed:          1,
+		DuplicateRate: 1,
+		Methods:       []string{"PUT", "POST", "GET"},
+		Check: func(e *Expect, event ChaosEvent) error {
+			checked = append(checked, event)
+			if orders != 1 {
+				return errors.New("expected exactly one order")
+			}
+			return nil
+		},
+	})
+
+	assertionHandler := &mockAssertionHandler{}
+
+	e := WithConfig(Config{
+		BaseURL:          "http://example.com",
+		Client:           &http.Client{Transport: NewBinder(handler)},
+		AssertionHandler: assertionHandler,
+		Chaos:            chaos,
+	})
+
+	e.PUT("/orders/1").WithText("order").
+		Expect().
+		Status(http.StatusOK)
+
+	assert.Nil(t, assertionHandler.failure)
+	assert.Equal(t, []string{"PUT order", "PUT order"}, bodies)
+	require.Equal(t, 1, len(checked))
+	assert.True(t, checked[0].Duplicated)
+	assert.Equal(t, "http://example.com/orders/1", checked[0].URL)
+
+	e.POST("/orders").WithText("order").
+		Expect().
+		Status(http.StatusCreated)
+
+	require.NotNil(t, assertionHandler.failure)
+	assert.Equal(t, AssertValid, assertionHandler.failure.Type)
+
+	errs := assertionHandler.failure.Errors
+	require.Equal(t, 3, len(errs))
+	assert.Equal(t, "expected exactly one order", errs[1].Error())
+	assert.Equal(t,
+		"chaos seed 1: POST http://example.com/orders duplicated", errs[2].Error())
+}
+
+func TestChaosAnnotation(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	assertionHandler := &mockAssertionHandler{}
+
+	e := WithConfig(Config{
+		BaseURL:          "http://example.com",
+		Client:           &http.Client{Transport: NewBinder(handler)},
+		AssertionHandler: assertionHandler,
+		Chaos: NewChaos(ChaosOpts{
+			Seed: 7,
+		}),
+	})
+
+	e.POST("/").Expect().Status(http.StatusOK)
+	assert.Nil(t, assertionHandler.failure)
+
+	e.POST("/").Expect().Status(http.StatusTeapot)
+
+	require.NotNil(t, assertionHandler.failure)
+
+	errs := assertionHandler.failure.Errors
+	assert.Equal(t,
+		"chaos seed 7: POST http://example.com/ not affected", errs[len(errs)-1].Error())
+}
+
+func TestChaosAnnotationRepeat(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	assertionHandler := &mockAssertionHandler{}
+
+	e := WithConfig(Config{
+		BaseURL:          "http://example.com",
+		Client:           &http.Client{Transport: NewBinder(handler)},
+		AssertionHandler: assertionHandler,
+		Chaos: NewChaos(ChaosOpts{
+			Seed: 7,
+		}),
+	})
+
+	resps := e.GET("/").Repeat(2)
+	require.Equal(t, 2, len(resps))
+	assert.Nil(t, assertionHandler.failure)
+
+	resps[1].Status(http.StatusTeapot)
+
+	require.NotNil(t, assertionHandler.failure)
+
+	errs := assertionHandler.failure.Errors
+	assert.Equal(t,
+		"chaos seed 7: GET http://example.com/ not affected", errs[len(errs)-1].Error())
+}
+
+func TestChaosCheckRequests(t *testing.T) {
+	counts := map[string]int{}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counts[r.URL.Path]++
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var outer *Expect
+
+	chaos := NewChaos(ChaosOpts{
+		Seed:          1,
+		DuplicateRate: 1,
+		Methods:       []string{"GET"},
+		Check: func(e *Expect, event ChaosEvent) error {
+			if event.URL != "http://example.com/orders" {
+				return nil
+			}
+
+			// sent by check, not affected
+			e.GET("/check").Expect().Status(http.StatusOK)
+
+			// sent by other code while check is running, affected
+			outer.GET("/other").Expect().Status(http.StatusOK)
+
+			return nil
+		},
+	})
+
+	outer = WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   &http.Client{Transport: NewBinder(handler)},
+		Reporter: newMockReporter(t),
+		Chaos:    chaos,
+	})
+
+	outer.GET("/orders").Expect().Status(http.StatusOK)
+
+	assert.Equal(t, map[string]int{
+		"/orders": 2,
+		"/check":  1,
+		"/other":  2,
+	}, counts)
+}
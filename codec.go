@@ -0,0 +1,796 @@
+package httpexpect
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// BodyCodec is used to encode and decode request and response bodies
+// of specific media type.
+//
+// BodyCodec is used by Request.WithEncoded and Response.Decoded. Codecs
+// are looked up by media type in Config.Codecs, and then in built-in
+// codecs:
+//
+//	"application/json"        - JSONCodec
+//	"application/msgpack"     - MsgpackCodec
+//	"application/x-msgpack"   - MsgpackCodec
+//	"application/vnd.msgpack" - MsgpackCodec
+//	"application/cbor"        - CBORCodec
+//
+// Media types with "+json", "+msgpack", and "+cbor" structured syntax
+// suffixes (e.g. "application/problem+json") fall back to the codec of
+// the suffix.
+type BodyCodec interface {
+	// Encode encodes value into body.
+	Encode(value interface{}) ([]byte, error)
+
+	// Decode decodes body into value. Value should consist of the same
+	// types as a value decoded by json.Unmarshal into interface{}, i.e.
+	// nil, bool, numbers, string, []interface{}, and map[string]interface{}.
+	// Other types are converted to these types using json.Marshal.
+	Decode(data []byte) (interface{}, error)
+}
+
+var builtinCodecs = map[string]BodyCodec{
+	"application/json":        JSONCodec{},
+	"application/msgpack":     MsgpackCodec{},
+	"application/x-msgpack":   MsgpackCodec{},
+	"application/vnd.msgpack": MsgpackCodec{},
+	"application/cbor":        CBORCodec{},
+}
+
+var codecSuffixes = map[string]string{
+	"+json":    "application/json",
+	"+msgpack": "application/msgpack",
+	"+cbor":    "application/cbor",
+}
+
+func lookupCodec(codecs map[string]BodyCodec, mediaType string) BodyCodec {
+	if codec := codecs[mediaType]; codec != nil {
+		return codec
+	}
+
+	if codec := builtinCodecs[mediaType]; codec != nil {
+		return codec
+	}
+
+	for suffix, baseType := range codecSuffixes {
+		if strings.HasSuffix(mediaType, suffix) {
+			return lookupCodec(codecs, baseType)
+		}
+	}
+
+	return nil
+}
+
+// JSONCodec is BodyCodec implementation for JSON, which uses
+// encoding/json.
+type JSONCodec struct{}
+
+// Encode implements BodyCodec.Encode.
+func (JSONCodec) Encode(value interface{}) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Decode implements BodyCodec.Decode.
+func (JSONCodec) Decode(data []byte) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// MsgpackCodec is BodyCodec implementation for MessagePack.
+//
+// Values are encoded the same way as by json.Marshal, except that []byte
+// is encoded as binary, and integral numbers are encoded as integers.
+// Binary values are decoded as []byte, which then becomes base64 string,
+// like with JSON. Extension types are not supported.
+type MsgpackCodec struct{}
+
+// Encode implements BodyCodec.Encode.
+func (MsgpackCodec) Encode(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeCodecValue(&buf, value, msgpackWriter{}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode implements BodyCodec.Decode.
+func (MsgpackCodec) Decode(data []byte) (interface{}, error) {
+	d := &msgpackDecoder{data: data}
+
+	value, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("unexpected %d trailing bytes", len(d.data)-d.pos)
+	}
+
+	return value, nil
+}
+
+// CBORCodec is BodyCodec implementation for CBOR (RFC 8949).
+//
+// Values are encoded the same way as by json.Marshal, except that []byte
+// is encoded as byte string, and integral numbers are encoded as integers.
+// Byte strings are decoded as []byte, which then becomes base64 string,
+// like with JSON. Tags are ignored, i.e. tagged item is decoded as is.
+type CBORCodec struct{}
+
+// Encode implements BodyCodec.Encode.
+func (CBORCodec) Encode(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeCodecValue(&buf, value, cborWriter{}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode implements BodyCodec.Decode.
+func (CBORCodec) Decode(data []byte) (interface{}, error) {
+	d := &cborDecoder{data: data}
+
+	value, err := d.decode(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if value == cborBreak {
+		return nil, errors.New("unexpected break")
+	}
+
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("unexpected %d trailing bytes", len(d.data)-d.pos)
+	}
+
+	return value, nil
+}
+
+// Max nesting depth of decoded values
+const codecMaxDepth = 1000
+
+// Binary format writer used by encodeCodecValue
+type codecWriter interface {
+	writeNil(buf *bytes.Buffer)
+	writeBool(buf *bytes.Buffer, v bool)
+	writeInt(buf *bytes.Buffer, v int64)
+	writeFloat(buf *bytes.Buffer, v float64)
+	writeString(buf *bytes.Buffer, v string)
+	writeBytes(buf *bytes.Buffer, v []byte)
+	writeArrayHeader(buf *bytes.Buffer, n int)
+	writeMapHeader(buf *bytes.Buffer, n int)
+}
+
+func encodeCodecValue(buf *bytes.Buffer, value interface{}, w codecWriter) error {
+	switch v := value.(type) {
+	case nil:
+		w.writeNil(buf)
+
+	case bool:
+		w.writeBool(buf, v)
+
+	case string:
+		w.writeString(buf, v)
+
+	case []byte:
+		w.writeBytes(buf, v)
+
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<63 {
+			w.writeInt(buf, int64(v))
+		} else {
+			w.writeFloat(buf, v)
+		}
+
+	case []interface{}:
+		w.writeArrayHeader(buf, len(v))
+		for _, elem := range v {
+			if err := encodeCodecValue(buf, elem, w); err != nil {
+				return err
+			}
+		}
+
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		w.writeMapHeader(buf, len(v))
+		for _, key := range keys {
+			w.writeString(buf, key)
+			if err := encodeCodecValue(buf, v[key], w); err != nil {
+				return err
+			}
+		}
+
+	default:
+		// convert structs, typed slices and maps, and other numeric
+		// types, to the types handled above
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		var canon interface{}
+		if err := json.Unmarshal(b, &canon); err != nil {
+			return err
+		}
+
+		return encodeCodecValue(buf, canon, w)
+	}
+
+	return nil
+}
+
+type msgpackWriter struct{}
+
+func (msgpackWriter) writeNil(buf *bytes.Buffer) {
+	buf.WriteByte(0xc0)
+}
+
+func (msgpackWriter) writeBool(buf *bytes.Buffer, v bool) {
+	if v {
+		buf.WriteByte(0xc3)
+	} else {
+		buf.WriteByte(0xc2)
+	}
+}
+
+func (msgpackWriter) writeInt(buf *bytes.Buffer, v int64) {
+	switch {
+	case v >= 0 && v <= 0x7f:
+		buf.WriteByte(byte(v))
+	case v >= -32 && v < 0:
+		buf.WriteByte(byte(v))
+	case v >= math.MinInt8 && v <= math.MaxInt8:
+		buf.Write([]byte{0xd0, byte(v)})
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		buf.WriteByte(0xd1)
+		writeBigEndian(buf, uint64(v), 2)
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		buf.WriteByte(0xd2)
+		writeBigEndian(buf, uint64(v), 4)
+	default:
+		buf.WriteByte(0xd3)
+		writeBigEndian(buf, uint64(v), 8)
+	}
+}
+
+func (msgpackWriter) writeFloat(buf *bytes.Buffer, v float64) {
+	buf.WriteByte(0xcb)
+	writeBigEndian(buf, math.Float64bits(v), 8)
+}
+
+func (msgpackWriter) writeString(buf *bytes.Buffer, v string) {
+	n := len(v)
+	switch {
+	case n <= 31:
+		buf.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		buf.Write([]byte{0xd9, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xda)
+		writeBigEndian(buf, uint64(n), 2)
+	default:
+		buf.WriteByte(0xdb)
+		writeBigEndian(buf, uint64(n), 4)
+	}
+	buf.WriteString(v)
+}
+
+func (msgpackWriter) writeBytes(buf *bytes.Buffer, v []byte) {
+	n := len(v)
+	switch {
+	case n <= math.MaxUint8:
+		buf.Write([]byte{0xc4, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xc5)
+		writeBigEndian(buf, uint64(n), 2)
+	default:
+		buf.WriteByte(0xc6)
+		writeBigEndian(buf, uint64(n), 4)
+	}
+	buf.Write(v)
+}
+
+func (msgpackWriter) writeArrayHeader(buf *bytes.Buffer, n int) {
+	switch {
+	case n <= 15:
+		buf.WriteByte(0x90 | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xdc)
+		writeBigEndian(buf, uint64(n), 2)
+	default:
+		buf.WriteByte(0xdd)
+		writeBigEndian(buf, uint64(n), 4)
+	}
+}
+
+func (msgpackWriter) writeMapHeader(buf *bytes.Buffer, n int) {
+	switch {
+	case n <= 15:
+		buf.WriteByte(0x80 | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xde)
+		writeBigEndian(buf, uint64(n), 2)
+	default:
+		buf.WriteByte(0xdf)
+		writeBigEndian(buf, uint64(n), 4)
+	}
+}
+
+type cborWriter struct{}
+
+func (cborWriter) writeHeader(buf *bytes.Buffer, major byte, n uint64) {
+	switch {
+	case n < 24:
+		buf.WriteByte(major<<5 | byte(n))
+	case n <= math.MaxUint8:
+		buf.Write([]byte{major<<5 | 24, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(major<<5 | 25)
+		writeBigEndian(buf, n, 2)
+	case n <= math.MaxUint32:
+		buf.WriteByte(major<<5 | 26)
+		writeBigEndian(buf, n, 4)
+	default:
+		buf.WriteByte(major<<5 | 27)
+		writeBigEndian(buf, n, 8)
+	}
+}
+
+func (cborWriter) writeNil(buf *bytes.Buffer) {
+	buf.WriteByte(0xf6)
+}
+
+func (cborWriter) writeBool(buf *bytes.Buffer, v bool) {
+	if v {
+		buf.WriteByte(0xf5)
+	} else {
+		buf.WriteByte(0xf4)
+	}
+}
+
+func (w cborWriter) writeInt(buf *bytes.Buffer, v int64) {
+	if v >= 0 {
+		w.writeHeader(buf, 0, uint64(v))
+	} else {
+		w.writeHeader(buf, 1, uint64(-1-v))
+	}
+}
+
+func (cborWriter) writeFloat(buf *bytes.Buffer, v float64) {
+	buf.WriteByte(0xfb)
+	writeBigEndian(buf, math.Float64bits(v), 8)
+}
+
+func (w cborWriter) writeString(buf *bytes.Buffer, v string) {
+	w.writeHeader(buf, 3, uint64(len(v)))
+	buf.WriteString(v)
+}
+
+func (w cborWriter) writeBytes(buf *bytes.Buffer, v []byte) {
+	w.writeHeader(buf, 2, uint64(len(v)))
+	buf.Write(v)
+}
+
+func (w cborWriter) writeArrayHeader(buf *bytes.Buffer, n int) {
+	w.writeHeader(buf, 4, uint64(n))
+}
+
+func (w cborWriter) writeMapHeader(buf *bytes.Buffer, n int) {
+	w.writeHeader(buf, 5, uint64(n))
+}
+
+func writeBigEndian(buf *bytes.Buffer, v uint64, size int) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	buf.Write(b[8-size:])
+}
+
+var errCodecTruncated = errors.New("unexpected end of data")
+
+type msgpackDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *msgpackDecoder) read(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, errCodecTruncated
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *msgpackDecoder) readUint(size int) (uint64, error) {
+	b, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+func (d *msgpackDecoder) decode(depth int) (interface{}, error) {
+	if depth > codecMaxDepth {
+		return nil, errors.New("max nesting depth exceeded")
+	}
+
+	b, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+
+	switch {
+	case c <= 0x7f:
+		return float64(c), nil
+	case c >= 0xe0:
+		return float64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.decodeMap(int(c&0x0f), depth)
+	case c&0xf0 == 0x90:
+		return d.decodeArray(int(c&0x0f), depth)
+	case c&0xe0 == 0xa0:
+		return d.decodeString(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.readUint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.read(int(n))
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), v...), nil
+
+	case 0xca:
+		v, err := d.readUint(4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := d.readUint(8)
+		return math.Float64frombits(v), err
+
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		v, err := d.readUint(1 << (c - 0xcc))
+		return float64(v), err
+
+	case 0xd0:
+		v, err := d.readUint(1)
+		return float64(int8(v)), err
+	case 0xd1:
+		v, err := d.readUint(2)
+		return float64(int16(v)), err
+	case 0xd2:
+		v, err := d.readUint(4)
+		return float64(int32(v)), err
+	case 0xd3:
+		v, err := d.readUint(8)
+		return float64(int64(v)), err
+
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.readUint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeString(int(n))
+
+	case 0xdc, 0xdd:
+		n, err := d.readUint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(int(n), depth)
+
+	case 0xde, 0xdf:
+		n, err := d.readUint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(int(n), depth)
+	}
+
+	return nil, fmt.Errorf("unsupported msgpack format 0x%02x at offset %d", c, d.pos-1)
+}
+
+func (d *msgpackDecoder) decodeString(n int) (interface{}, error) {
+	v, err := d.read(n)
+	if err != nil {
+		return nil, err
+	}
+	return string(v), nil
+}
+
+func (d *msgpackDecoder) decodeArray(n int, depth int) (interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errCodecTruncated
+	}
+	arr := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		elem, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, elem)
+	}
+	return arr, nil
+}
+
+func (d *msgpackDecoder) decodeMap(n int, depth int) (interface{}, error) {
+	if n > len(d.data)-d.pos {
+		return nil, errCodecTruncated
+	}
+	obj := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		key, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		skey, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("unsupported non-string map key %v", key)
+		}
+		val, err := d.decode(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		obj[skey] = val
+	}
+	return obj, nil
+}
+
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+// Marker returned by cborDecoder.decode for "break" stop code
+var cborBreak = &struct{}{}
+
+func (d *cborDecoder) read(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errCodecTruncated
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+// reads argument of data item header; indefinite is true for
+// additional information 31, which means indefinite length
+func (d *cborDecoder) readArg(info byte) (arg uint64, indefinite bool, err error) {
+	switch {
+	case info < 24:
+		return uint64(info), false, nil
+	case info <= 27:
+		b, err := d.read(1 << (info - 24))
+		if err != nil {
+			return 0, false, err
+		}
+		for _, c := range b {
+			arg = arg<<8 | uint64(c)
+		}
+		return arg, false, nil
+	case info == 31:
+		return 0, true, nil
+	}
+	return 0, false, fmt.Errorf(
+		"unsupported cbor additional information %d at offset %d", info, d.pos-1)
+}
+
+func (d *cborDecoder) decode(depth int) (interface{}, error) {
+	if depth > codecMaxDepth {
+		return nil, errors.New("max nesting depth exceeded")
+	}
+
+	b, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+
+	major, info := b[0]>>5, b[0]&0x1f
+
+	if major == 7 {
+		return d.decodeSimple(info)
+	}
+
+	arg, indefinite, err := d.readArg(info)
+	if err != nil {
+		return nil, err
+	}
+
+	if indefinite && major != 2 && major != 3 && major != 4 && major != 5 {
+		return nil, fmt.Errorf(
+			"unexpected indefinite length for major type %d at offset %d",
+			major, d.pos-1)
+	}
+
+	switch major {
+	case 0:
+		return float64(arg), nil
+
+	case 1:
+		return -1 - float64(arg), nil
+
+	case 2, 3:
+		var v []byte
+		if indefinite {
+			v, err = d.decodeChunks(major)
+		} else {
+			v, err = d.read(arg)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if major == 3 {
+			return string(v), nil
+		}
+		return append([]byte(nil), v...), nil
+
+	case 4:
+		arr := []interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			elem, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if elem == cborBreak {
+				if !indefinite {
+					return nil, errors.New("unexpected break")
+				}
+				break
+			}
+			arr = append(arr, elem)
+		}
+		return arr, nil
+
+	case 5:
+		obj := map[string]interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			key, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if key == cborBreak {
+				if !indefinite {
+					return nil, errors.New("unexpected break")
+				}
+				break
+			}
+			skey, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("unsupported non-string map key %v", key)
+			}
+			val, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if val == cborBreak {
+				return nil, errors.New("unexpected break")
+			}
+			obj[skey] = val
+		}
+		return obj, nil
+
+	default: // 6, tag
+		val, err := d.decode(depth + 1)
+		if err == nil && val == cborBreak {
+			err = errors.New("unexpected break")
+		}
+		return val, err
+	}
+}
+
+func (d *cborDecoder) decodeChunks(major byte) ([]byte, error) {
+	var v []byte
+	for {
+		b, err := d.read(1)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] == 0xff {
+			return v, nil
+		}
+		if b[0]>>5 != major {
+			return nil, fmt.Errorf(
+				"unexpected chunk of major type %d at offset %d", b[0]>>5, d.pos-1)
+		}
+		n, indefinite, err := d.readArg(b[0] & 0x1f)
+		if err != nil {
+			return nil, err
+		}
+		if indefinite {
+			return nil, errors.New("unexpected nested indefinite length chunk")
+		}
+		chunk, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		v = append(v, chunk...)
+	}
+}
+
+func (d *cborDecoder) decodeSimple(info byte) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23:
+		return nil, nil
+	case 25:
+		b, err := d.read(2)
+		if err != nil {
+			return nil, err
+		}
+		return decodeHalfFloat(binary.BigEndian.Uint16(b)), nil
+	case 26:
+		b, err := d.read(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case 27:
+		b, err := d.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case 31:
+		return cborBreak, nil
+	}
+	return nil, fmt.Errorf("unsupported cbor simple value %d at offset %d", info, d.pos-1)
+}
+
+func decodeHalfFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+
+	var v float64
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, -24)
+	case 0x1f:
+		if mant == 0 {
+			v = math.Inf(1)
+		} else {
+			v = math.NaN()
+		}
+	default:
+		v = math.Ldexp(mant+1024, exp-25)
+	}
+
+	if h&0x8000 != 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,260 @@
+package httpexpect
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBodyCodec struct{}
+
+func (mockBodyCodec) Encode(value interface{}) ([]byte, error) {
+	return []byte("mock"), nil
+}
+
+func (mockBodyCodec) Decode(data []byte) (interface{}, error) {
+	return "mock", nil
+}
+
+func TestCodecLookup(t *testing.T) {
+	codecs := map[string]BodyCodec{
+		"application/x-mock": mockBodyCodec{},
+		"application/cbor":   mockBodyCodec{},
+	}
+
+	cases := []struct {
+		mediaType string
+		codec     BodyCodec
+	}{
+		{"application/json", JSONCodec{}},
+		{"application/problem+json", JSONCodec{}},
+		{"application/msgpack", MsgpackCodec{}},
+		{"application/x-msgpack", MsgpackCodec{}},
+		{"application/vnd.msgpack", MsgpackCodec{}},
+		{"application/vnd.api+msgpack", MsgpackCodec{}},
+		{"application/cbor", mockBodyCodec{}},
+		{"application/vnd.api+cbor", mockBodyCodec{}},
+		{"application/x-mock", mockBodyCodec{}},
+		{"text/plain", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.mediaType, func(t *testing.T) {
+			assert.Equal(t, tc.codec, lookupCodec(codecs, tc.mediaType))
+		})
+	}
+
+	assert.Equal(t, CBORCodec{}, lookupCodec(nil, "application/cbor"))
+}
+
+func TestCodecMsgpack(t *testing.T) {
+	cases := []struct {
+		hex   string
+		value interface{}
+	}{
+		{"c0", nil},
+		{"c2", false},
+		{"c3", true},
+		{"00", 0.0},
+		{"7f", 127.0},
+		{"e0", -32.0},
+		{"ff", -1.0},
+		{"cc80", 128.0},
+		{"cd0100", 256.0},
+		{"ce00010000", 65536.0},
+		{"cf0000000100000000", 4294967296.0},
+		{"d0df", -33.0},
+		{"d1ff7f", -129.0},
+		{"d2ffff7fff", -32769.0},
+		{"d3ffffffff7fffffff", -2147483649.0},
+		{"ca3fc00000", 1.5},
+		{"cb3ff8000000000000", 1.5},
+		{"a3666f6f", "foo"},
+		{"d903666f6f", "foo"},
+		{"da0003666f6f", "foo"},
+		{"c403010203", []byte{1, 2, 3}},
+		{"93010203", []interface{}{1.0, 2.0, 3.0}},
+		{"dc0001c0", []interface{}{nil}},
+		{"81a3666f6f01", map[string]interface{}{"foo": 1.0}},
+		{"de0001a161c3", map[string]interface{}{"a": true}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.hex, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.hex)
+			require.NoError(t, err)
+
+			value, err := MsgpackCodec{}.Decode(data)
+			require.NoError(t, err)
+			assert.Equal(t, tc.value, value)
+
+			// encoding produces canonical representation
+			encoded, err := MsgpackCodec{}.Encode(tc.value)
+			require.NoError(t, err)
+
+			value, err = MsgpackCodec{}.Decode(encoded)
+			require.NoError(t, err)
+			assert.Equal(t, tc.value, value)
+		})
+	}
+
+	t.Run("encode", func(t *testing.T) {
+		encoded, err := MsgpackCodec{}.Encode(struct {
+			B string `json:"b"`
+			A int    `json:"a"`
+		}{"x", -200})
+		require.NoError(t, err)
+		assert.Equal(t, "82a161d1ff38a162a178", hex.EncodeToString(encoded))
+
+		_, err = MsgpackCodec{}.Encode(func() {})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tc := range []string{
+			"",
+			"c1",
+			"d4",
+			"a3666f",
+			"9301",
+			"8101",
+			"81a161",
+			"c0c0",
+			"ddffffffff",
+		} {
+			data, err := hex.DecodeString(tc)
+			require.NoError(t, err)
+
+			_, err = MsgpackCodec{}.Decode(data)
+			assert.Error(t, err, tc)
+		}
+	})
+}
+
+func TestCodecCBOR(t *testing.T) {
+	// examples from RFC 8949, Appendix A
+	cases := []struct {
+		hex   string
+		value interface{}
+	}{
+		{"00", 0.0},
+		{"17", 23.0},
+		{"1818", 24.0},
+		{"1903e8", 1000.0},
+		{"1a000f4240", 1000000.0},
+		{"1b000000e8d4a51000", 1000000000000.0},
+		{"20", -1.0},
+		{"3863", -100.0},
+		{"3903e7", -1000.0},
+		{"f90000", 0.0},
+		{"f93c00", 1.0},
+		{"f93e00", 1.5},
+		{"f9c400", -4.0},
+		{"f90001", 5.960464477539063e-8},
+		{"fa47c35000", 100000.0},
+		{"fb3ff199999999999a", 1.1},
+		{"f4", false},
+		{"f5", true},
+		{"f6", nil},
+		{"f7", nil},
+		{"c074323031332d30332d32315432303a30343a30305a", "2013-03-21T20:04:00Z"},
+		{"4401020304", []byte{1, 2, 3, 4}},
+		{"6449455446", "IETF"},
+		{"62c3bc", "ü"},
+		{"80", []interface{}{}},
+		{"83010203", []interface{}{1.0, 2.0, 3.0}},
+		{"a0", map[string]interface{}{}},
+		{"a26161016162820203", map[string]interface{}{
+			"a": 1.0, "b": []interface{}{2.0, 3.0},
+		}},
+		{"5f42010243030405ff", []byte{1, 2, 3, 4, 5}},
+		{"7f657374726561646d696e67ff", "streaming"},
+		{"9f018202039f0405ffff", []interface{}{
+			1.0, []interface{}{2.0, 3.0}, []interface{}{4.0, 5.0},
+		}},
+		{"bf61610161629f0203ffff", map[string]interface{}{
+			"a": 1.0, "b": []interface{}{2.0, 3.0},
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.hex, func(t *testing.T) {
+			data, err := hex.DecodeString(tc.hex)
+			require.NoError(t, err)
+
+			value, err := CBORCodec{}.Decode(data)
+			require.NoError(t, err)
+			assert.Equal(t, tc.value, value)
+
+			encoded, err := CBORCodec{}.Encode(tc.value)
+			require.NoError(t, err)
+
+			value, err = CBORCodec{}.Decode(encoded)
+			require.NoError(t, err)
+			assert.Equal(t, tc.value, value)
+		})
+	}
+
+	t.Run("special floats", func(t *testing.T) {
+		value, err := CBORCodec{}.Decode([]byte{0xf9, 0x7c, 0x00})
+		require.NoError(t, err)
+		assert.Equal(t, math.Inf(1), value)
+
+		value, err = CBORCodec{}.Decode([]byte{0xf9, 0x7e, 0x00})
+		require.NoError(t, err)
+		assert.True(t, math.IsNaN(value.(float64)))
+	})
+
+	t.Run("encode", func(t *testing.T) {
+		encoded, err := CBORCodec{}.Encode(map[string]interface{}{
+			"b": []int{-1, 1000},
+			"a": 1.5,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "a26161fb3ff8000000000000616282201903e8",
+			hex.EncodeToString(encoded))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tc := range []string{
+			"",
+			"ff",
+			"1c",
+			"1f",
+			"19",
+			"6449",
+			"8301",
+			"a10101",
+			"a161",
+			"a16161ff",
+			"83ff",
+			"5f6161ff",
+			"5f5f",
+			"f8",
+			"c0ff",
+			"0000",
+		} {
+			data, err := hex.DecodeString(tc)
+			require.NoError(t, err)
+
+			_, err = CBORCodec{}.Decode(data)
+			assert.Error(t, err, tc)
+		}
+	})
+}
+
+func TestCodecJSON(t *testing.T) {
+	encoded, err := JSONCodec{}.Encode(map[string]interface{}{"foo": 1})
+	require.NoError(t, err)
+	assert.Equal(t, `{"foo":1}`, string(encoded))
+
+	value, err := JSONCodec{}.Decode(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"foo": 1.0}, value)
+
+	_, err = JSONCodec{}.Decode([]byte(`{`))
+	assert.Error(t, err)
+}
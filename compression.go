@@ -0,0 +1,127 @@
+package httpexpect
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Supported content encodings.
+const (
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+	EncodingBrotli  = "br"
+	EncodingZstd    = "zstd"
+)
+
+func newCompressor(encoding string, w io.Writer) (io.WriteCloser, error) {
+	switch encoding {
+	case EncodingGzip:
+		return gzip.NewWriter(w), nil
+
+	case EncodingDeflate:
+		// "deflate" content coding is zlib format, see RFC 7230
+		return zlib.NewWriter(w), nil
+
+	case EncodingBrotli:
+		return brotli.NewWriter(w), nil
+
+	case EncodingZstd:
+		return zstd.NewWriter(w)
+
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+}
+
+func isSupportedEncoding(encoding string) bool {
+	switch encoding {
+	case EncodingGzip, EncodingDeflate, EncodingBrotli, EncodingZstd:
+		return true
+	}
+	return false
+}
+
+func newDecompressor(encoding string, r io.Reader) (io.Reader, error) {
+	switch encoding {
+	case EncodingGzip, "x-gzip":
+		return gzip.NewReader(r)
+
+	case EncodingDeflate:
+		return zlib.NewReader(r)
+
+	case EncodingBrotli:
+		return brotli.NewReader(r), nil
+
+	case EncodingZstd:
+		return zstd.NewReader(r)
+
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+}
+
+// returns content codings from Content-Encoding header, in order they
+// were applied; "identity" is skipped
+func contentEncodings(header http.Header) []string {
+	var encodings []string
+
+	for _, value := range header["Content-Encoding"] {
+		for _, enc := range strings.Split(value, ",") {
+			enc = strings.ToLower(strings.TrimSpace(enc))
+			if enc != "" && enc != "identity" {
+				encodings = append(encodings, enc)
+			}
+		}
+	}
+
+	return encodings
+}
+
+// decodes content according to Content-Encoding header; returns false if
+// content is not encoded or has encoding that is not supported
+func decodeContent(header http.Header, content []byte) ([]byte, bool, error) {
+	encodings := contentEncodings(header)
+
+	if len(encodings) == 0 || len(content) == 0 {
+		return content, false, nil
+	}
+
+	for _, enc := range encodings {
+		if enc != "x-gzip" && !isSupportedEncoding(enc) {
+			return content, false, nil
+		}
+	}
+
+	// codings are listed in order they were applied, so decode in reverse
+	for i := len(encodings) - 1; i >= 0; i-- {
+		rd, err := newDecompressor(encodings[i], bytes.NewReader(content))
+		if err != nil {
+			return nil, false, err
+		}
+
+		decoded, err := ioutil.ReadAll(rd)
+
+		if c, ok := rd.(io.Closer); ok {
+			_ = c.Close()
+		} else if d, ok := rd.(*zstd.Decoder); ok {
+			d.Close()
+		}
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		content = decoded
+	}
+
+	return content, true, nil
+}
@@ -0,0 +1,158 @@
+package httpexpect
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decompressForTest(t *testing.T, encoding string, data []byte) string {
+	var (
+		r   io.Reader
+		err error
+	)
+
+	switch encoding {
+	case EncodingGzip:
+		r, err = gzip.NewReader(bytes.NewReader(data))
+	case EncodingDeflate:
+		r, err = zlib.NewReader(bytes.NewReader(data))
+	case EncodingBrotli:
+		r = brotli.NewReader(bytes.NewReader(data))
+	case EncodingZstd:
+		var d *zstd.Decoder
+		d, err = zstd.NewReader(bytes.NewReader(data))
+		if err == nil {
+			defer d.Close()
+			r = d
+		}
+	}
+	require.NoError(t, err)
+
+	b, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+func TestCompressionRoundTrip(t *testing.T) {
+	encodings := []string{
+		EncodingGzip,
+		EncodingDeflate,
+		EncodingBrotli,
+		EncodingZstd,
+	}
+
+	for _, encoding := range encodings {
+		t.Run(encoding, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			w, err := newCompressor(encoding, &buf)
+			require.NoError(t, err)
+
+			_, err = w.Write([]byte("hello, world"))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+
+			assert.Equal(t, "hello, world", decompressForTest(t, encoding, buf.Bytes()))
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := newCompressor("compress", &bytes.Buffer{})
+		assert.Error(t, err)
+		assert.False(t, isSupportedEncoding("compress"))
+	})
+}
+
+func compressForTest(t *testing.T, encoding string, data []byte) []byte {
+	var buf bytes.Buffer
+
+	w, err := newCompressor(encoding, &buf)
+	require.NoError(t, err)
+
+	_, err = w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func TestCompressionDecodeContent(t *testing.T) {
+	const text = "hello, world"
+
+	encodings := []string{
+		EncodingGzip,
+		EncodingDeflate,
+		EncodingBrotli,
+		EncodingZstd,
+	}
+
+	for _, encoding := range encodings {
+		t.Run(encoding, func(t *testing.T) {
+			data := compressForTest(t, encoding, []byte(text))
+
+			decoded, ok, err := decodeContent(http.Header{
+				"Content-Encoding": {strings.ToUpper(encoding)},
+			}, data)
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, text, string(decoded))
+		})
+	}
+
+	t.Run("multiple", func(t *testing.T) {
+		data := compressForTest(t, EncodingBrotli,
+			compressForTest(t, EncodingGzip, []byte(text)))
+
+		for _, header := range []http.Header{
+			{"Content-Encoding": {"gzip, br"}},
+			{"Content-Encoding": {"gzip", "identity", "br"}},
+		} {
+			decoded, ok, err := decodeContent(header, data)
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, text, string(decoded))
+		}
+	})
+
+	t.Run("not encoded", func(t *testing.T) {
+		for _, header := range []http.Header{
+			{},
+			{"Content-Encoding": {"identity"}},
+			{"Content-Encoding": {"compress"}},
+			{"Content-Encoding": {"gzip, compress"}},
+		} {
+			decoded, ok, err := decodeContent(header, []byte(text))
+			require.NoError(t, err)
+			assert.False(t, ok)
+			assert.Equal(t, text, string(decoded))
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		decoded, ok, err := decodeContent(http.Header{
+			"Content-Encoding": {"gzip"},
+		}, []byte{})
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Empty(t, decoded)
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		_, _, err := decodeContent(http.Header{
+			"Content-Encoding": {"gzip"},
+		}, []byte(text))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,231 @@
+package httpexpect
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http/httptrace"
+	"sync"
+)
+
+// Connection provides methods to inspect connection that was used to send
+// request and receive response, e.g. whether it was reused from pool of
+// idle keep-alive connections.
+//
+// Connection info is collected using httptrace, so it's available only if
+// request was sent using http.Transport (or another transport that supports
+// httptrace), i.e. not when using Binder or FastBinder.
+type Connection struct {
+	chain *chain
+	value httptrace.GotConnInfo
+}
+
+// NewConnection returns a new Connection instance.
+//
+// reporter should not be nil.
+//
+// Example:
+//
+//	var info httptrace.GotConnInfo
+//	trace := &httptrace.ClientTrace{
+//	    GotConn: func(i httptrace.GotConnInfo) { info = i },
+//	}
+//	...
+//	conn := NewConnection(t, info)
+//	conn.Reused().True()
+func NewConnection(reporter Reporter, info httptrace.GotConnInfo) *Connection {
+	return newConnection(newChainWithDefaults("Connection()", reporter), info)
+}
+
+func newConnection(parent *chain, info httptrace.GotConnInfo) *Connection {
+	return &Connection{parent.clone(), info}
+}
+
+// Raw returns underlying httptrace.GotConnInfo.
+func (c *Connection) Raw() httptrace.GotConnInfo {
+	return c.value
+}
+
+// Reused returns a new Boolean instance that is true if connection was
+// previously used for another request, i.e. was taken from pool of
+// keep-alive connections instead of being dialed.
+//
+// Example:
+//
+//	e.GET("/path").Expect()
+//	e.GET("/path").Expect().Connection().Reused().True()
+func (c *Connection) Reused() *Boolean {
+	c.chain.enter("Reused()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newBoolean(c.chain, false)
+	}
+
+	return newBoolean(c.chain, c.value.Reused)
+}
+
+// WasIdle returns a new Boolean instance that is true if connection was
+// taken from pool of idle connections.
+//
+// Example:
+//
+//	conn := resp.Connection()
+//	conn.WasIdle().True()
+func (c *Connection) WasIdle() *Boolean {
+	c.chain.enter("WasIdle()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newBoolean(c.chain, false)
+	}
+
+	return newBoolean(c.chain, c.value.WasIdle)
+}
+
+// IdleTime returns a new Duration instance with time that connection
+// spent in pool of idle connections, or zero if it wasn't idle.
+//
+// Example:
+//
+//	conn := resp.Connection()
+//	conn.IdleTime().Lt(time.Minute)
+func (c *Connection) IdleTime() *Duration {
+	c.chain.enter("IdleTime()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newDuration(c.chain, nil)
+	}
+
+	idleTime := c.value.IdleTime
+
+	return newDuration(c.chain, &idleTime)
+}
+
+// LocalAddr returns a new String instance with local address of
+// connection, e.g. "127.0.0.1:54321".
+//
+// Example:
+//
+//	conn := resp.Connection()
+//	conn.LocalAddr().Contains("127.0.0.1:")
+func (c *Connection) LocalAddr() *String {
+	c.chain.enter("LocalAddr()")
+	defer c.chain.leave()
+
+	if !c.checkConn() {
+		return newString(c.chain, "")
+	}
+
+	return newString(c.chain, c.value.Conn.LocalAddr().String())
+}
+
+// RemoteAddr returns a new String instance with remote address of
+// connection, e.g. "127.0.0.1:8080". If request was sent via proxy,
+// this is address of proxy.
+//
+// Example:
+//
+//	conn := resp.Connection()
+//	conn.RemoteAddr().Equal(server.Listener.Addr().String())
+func (c *Connection) RemoteAddr() *String {
+	c.chain.enter("RemoteAddr()")
+	defer c.chain.leave()
+
+	if !c.checkConn() {
+		return newString(c.chain, "")
+	}
+
+	return newString(c.chain, c.value.Conn.RemoteAddr().String())
+}
+
+// TLSResumed returns a new Boolean instance that is true if TLS session
+// of connection was resumed from previous connection, using session
+// ticket or session ID. Requires tls.Config.ClientSessionCache to be set.
+//
+// Note that reused connection has the same TLS session as before, and it
+// is reported as resumed only if it was resumed when connection was dialed.
+//
+// TLSResumed fails if connection doesn't use TLS.
+//
+// Example:
+//
+//	client := &http.Client{
+//	    Transport: &http.Transport{
+//	        TLSClientConfig: &tls.Config{
+//	            ClientSessionCache: tls.NewLRUClientSessionCache(0),
+//	        },
+//	        DisableKeepAlives: true,
+//	    },
+//	}
+//	...
+//	e.GET("/path").Expect()
+//	e.GET("/path").Expect().Connection().TLSResumed().True()
+func (c *Connection) TLSResumed() *Boolean {
+	c.chain.enter("TLSResumed()")
+	defer c.chain.leave()
+
+	if !c.checkConn() {
+		return newBoolean(c.chain, false)
+	}
+
+	tlsConn, ok := c.value.Conn.(interface {
+		ConnectionState() tls.ConnectionState
+	})
+	if !ok {
+		c.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{c.value.Conn},
+			Errors: []error{
+				errors.New("expected: connection uses TLS"),
+			},
+		})
+		return newBoolean(c.chain, false)
+	}
+
+	return newBoolean(c.chain, tlsConn.ConnectionState().DidResume)
+}
+
+func (c *Connection) checkConn() bool {
+	if c.chain.failed() {
+		return false
+	}
+
+	if c.value.Conn == nil {
+		c.chain.fail(AssertionFailure{
+			Type:   AssertNotNil,
+			Actual: &AssertionValue{c.value.Conn},
+			Errors: []error{
+				errors.New("expected: non-nil connection"),
+			},
+		})
+		return false
+	}
+
+	return true
+}
+
+// records connection obtained by transport for request; if request
+// was retried or redirected, the last connection is recorded
+type connUsage struct {
+	mu   sync.Mutex
+	info *httptrace.GotConnInfo
+}
+
+func (u *connUsage) trace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			u.mu.Lock()
+			defer u.mu.Unlock()
+
+			u.info = &info
+		},
+	}
+}
+
+func (u *connUsage) get() *httptrace.GotConnInfo {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.info
+}
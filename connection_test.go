@@ -0,0 +1,95 @@
+package httpexpect
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http/httptrace"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newConnection(chain, httptrace.GotConnInfo{})
+
+	value.chain.assertFailed(t)
+
+	assert.Nil(t, value.Raw().Conn)
+
+	value.Reused().chain.assertFailed(t)
+	value.WasIdle().chain.assertFailed(t)
+	value.IdleTime().chain.assertFailed(t)
+	value.LocalAddr().chain.assertFailed(t)
+	value.RemoteAddr().chain.assertFailed(t)
+	value.TLSResumed().chain.assertFailed(t)
+}
+
+func TestConnectionInfo(t *testing.T) {
+	conn1, conn2 := net.Pipe()
+	defer conn1.Close()
+	defer conn2.Close()
+
+	reporter := newMockReporter(t)
+
+	t.Run("new", func(t *testing.T) {
+		value := NewConnection(reporter, httptrace.GotConnInfo{
+			Conn: conn1,
+		})
+
+		assert.Equal(t, conn1, value.Raw().Conn)
+
+		value.Reused().False().chain.assertOK(t)
+		value.WasIdle().False().chain.assertOK(t)
+		value.IdleTime().Equal(0).chain.assertOK(t)
+		value.LocalAddr().Equal("pipe").chain.assertOK(t)
+		value.RemoteAddr().Equal("pipe").chain.assertOK(t)
+	})
+
+	t.Run("reused", func(t *testing.T) {
+		value := NewConnection(reporter, httptrace.GotConnInfo{
+			Conn:     conn1,
+			Reused:   true,
+			WasIdle:  true,
+			IdleTime: time.Second,
+		})
+
+		value.Reused().True().chain.assertOK(t)
+		value.WasIdle().True().chain.assertOK(t)
+		value.IdleTime().Equal(time.Second).chain.assertOK(t)
+	})
+
+	t.Run("no tls", func(t *testing.T) {
+		value := NewConnection(reporter, httptrace.GotConnInfo{
+			Conn: conn1,
+		})
+
+		value.TLSResumed().chain.assertFailed(t)
+	})
+
+	t.Run("tls", func(t *testing.T) {
+		value := NewConnection(reporter, httptrace.GotConnInfo{
+			Conn: tls.Client(conn1, &tls.Config{}),
+		})
+
+		value.TLSResumed().False().chain.assertOK(t)
+	})
+
+	t.Run("no conn", func(t *testing.T) {
+		value := NewConnection(reporter, httptrace.GotConnInfo{})
+
+		value.Reused().chain.assertOK(t)
+
+		value.LocalAddr().chain.assertFailed(t)
+		value.chain.reset()
+
+		value.RemoteAddr().chain.assertFailed(t)
+		value.chain.reset()
+
+		value.TLSResumed().chain.assertFailed(t)
+		value.chain.reset()
+	})
+}
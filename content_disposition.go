@@ -0,0 +1,279 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// ContentDisposition provides methods to inspect parsed Content-Disposition
+// header, see RFC 6266.
+//
+// Non-ASCII file names are sent as RFC 5987 extended values, which are
+// decoded and take precedence over plain parameters with the same name:
+//
+//	attachment; filename="EUR rates.pdf"; filename*=UTF-8''%E2%82%AC%20rates.pdf
+type ContentDisposition struct {
+	chain *chain
+	value contentDisposition
+}
+
+type contentDisposition struct {
+	raw    string
+	typ    string
+	params map[string]string
+}
+
+// NewContentDisposition returns a new ContentDisposition instance.
+//
+// reporter should not be nil. value is Content-Disposition header value,
+// e.g. `attachment; filename="report.pdf"`. If value can't be parsed,
+// failure is reported.
+//
+// Example:
+//
+//	cd := NewContentDisposition(t, `attachment; filename="report.pdf"`)
+//	cd.Attachment()
+//	cd.Filename().Equal("report.pdf")
+func NewContentDisposition(reporter Reporter, value string) *ContentDisposition {
+	chain := newChainWithDefaults("ContentDisposition()", reporter)
+
+	cd, err := parseContentDisposition(value)
+	if err != nil {
+		chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{value},
+			Errors: []error{
+				errors.New(`expected: valid "Content-Disposition" value`),
+				err,
+			},
+		})
+	}
+
+	return newContentDisposition(chain, cd)
+}
+
+func newContentDisposition(
+	parent *chain, value contentDisposition,
+) *ContentDisposition {
+	return &ContentDisposition{parent.clone(), value}
+}
+
+// Raw returns Content-Disposition header value.
+//
+// Example:
+//
+//	cd := NewContentDisposition(t, "inline")
+//	assert.Equal(t, "inline", cd.Raw())
+func (c *ContentDisposition) Raw() string {
+	return c.value.raw
+}
+
+// Type returns a new String instance with lower-case disposition type,
+// usually "inline" or "attachment".
+//
+// Example:
+//
+//	cd := NewContentDisposition(t, `Attachment; filename="report.pdf"`)
+//	cd.Type().Equal("attachment")
+func (c *ContentDisposition) Type() *String {
+	c.chain.enter("Type()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newString(c.chain, "")
+	}
+
+	return newString(c.chain, c.value.typ)
+}
+
+// Attachment succeeds if disposition type is "attachment", i.e. client
+// should save content as file instead of displaying it.
+//
+// Example:
+//
+//	resp := e.GET("/reports/{id}", 1).Expect()
+//	resp.ContentDisposition().Attachment()
+func (c *ContentDisposition) Attachment() *ContentDisposition {
+	c.chain.enter("Attachment()")
+	defer c.chain.leave()
+
+	c.checkType("attachment")
+
+	return c
+}
+
+// Inline succeeds if disposition type is "inline", i.e. client should
+// display content.
+//
+// Example:
+//
+//	resp := e.GET("/images/{id}", 1).Expect()
+//	resp.ContentDisposition().Inline()
+func (c *ContentDisposition) Inline() *ContentDisposition {
+	c.chain.enter("Inline()")
+	defer c.chain.leave()
+
+	c.checkType("inline")
+
+	return c
+}
+
+// Filename returns a new String instance with file name suggested to
+// client.
+//
+// If header has both "filename*" and "filename" parameters, "filename*"
+// is used, like clients do; its RFC 5987 encoding is decoded.
+//
+// Filename fails if header has no file name.
+//
+// Example:
+//
+//	cd := NewContentDisposition(t,
+//	    `attachment; filename="rates.txt"; filename*=UTF-8''%E2%82%AC%20rates.txt`)
+//	cd.Filename().Equal("€ rates.txt")
+func (c *ContentDisposition) Filename() *String {
+	c.chain.enter("Filename()")
+	defer c.chain.leave()
+
+	value, ok := c.checkParam("filename")
+	if !ok {
+		return newString(c.chain, "")
+	}
+
+	return newString(c.chain, value)
+}
+
+// Param returns a new String instance with value of given parameter.
+// Parameter name is case-insensitive; extended value is used and decoded
+// if present, see Filename.
+//
+// Param fails if header has no such parameter.
+//
+// Example:
+//
+//	cd := NewContentDisposition(t, `form-data; name="file"; filename="a.txt"`)
+//	cd.Param("name").Equal("file")
+func (c *ContentDisposition) Param(name string) *String {
+	c.chain.enter("Param(%q)", name)
+	defer c.chain.leave()
+
+	value, ok := c.checkParam(name)
+	if !ok {
+		return newString(c.chain, "")
+	}
+
+	return newString(c.chain, value)
+}
+
+func (c *ContentDisposition) checkType(typ string) {
+	if c.chain.failed() {
+		return
+	}
+
+	if c.value.typ != typ {
+		c.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{c.value.typ},
+			Expected: &AssertionValue{typ},
+			Errors: []error{
+				fmt.Errorf(`expected: "Content-Disposition" type is %q`, typ),
+			},
+		})
+	}
+}
+
+func (c *ContentDisposition) checkParam(name string) (string, bool) {
+	if c.chain.failed() {
+		return "", false
+	}
+
+	value, ok := c.value.params[strings.ToLower(name)]
+	if !ok {
+		c.chain.fail(AssertionFailure{
+			Type:     AssertContainsKey,
+			Actual:   &AssertionValue{c.value.params},
+			Expected: &AssertionValue{name},
+			Errors: []error{
+				fmt.Errorf(`expected: "Content-Disposition" has %q parameter`, name),
+			},
+		})
+		return "", false
+	}
+
+	return value, true
+}
+
+// parses Content-Disposition header value
+func parseContentDisposition(value string) (contentDisposition, error) {
+	cd := contentDisposition{raw: value, params: map[string]string{}}
+
+	typ, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return cd, err
+	}
+
+	cd.typ = typ
+	for k, v := range params {
+		cd.params[k] = v
+	}
+
+	// mime package silently drops extended values with charset other than
+	// UTF-8 and malformed encoding, so decode them here
+	for _, param := range splitUnquoted(value, ';')[1:] {
+		i := strings.IndexByte(param, '=')
+		if i < 0 {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimSpace(param[:i]))
+		if !strings.HasSuffix(name, "*") || strings.Count(name, "*") != 1 {
+			continue // regular value or RFC 2231 continuation
+		}
+
+		decoded, err := decodeExtValue(strings.TrimSpace(param[i+1:]))
+		if err != nil {
+			return cd, fmt.Errorf("invalid %q parameter: %s", name, err)
+		}
+
+		cd.params[strings.TrimSuffix(name, "*")] = decoded
+	}
+
+	return cd, nil
+}
+
+// decodes RFC 5987 ext-value, e.g. "UTF-8'en'%E2%82%AC%20rates"
+func decodeExtValue(s string) (string, error) {
+	parts := strings.SplitN(s, "'", 3)
+	if len(parts) != 3 {
+		return "", errors.New(`expected charset'language'value`)
+	}
+
+	charset, encoded := strings.ToLower(parts[0]), parts[2]
+
+	decoded, err := url.PathUnescape(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	switch charset {
+	case "utf-8":
+		if !utf8.ValidString(decoded) {
+			return "", errors.New("invalid UTF-8 sequence")
+		}
+		return decoded, nil
+
+	case "iso-8859-1":
+		runes := make([]rune, len(decoded))
+		for i := 0; i < len(decoded); i++ {
+			runes[i] = rune(decoded[i])
+		}
+		return string(runes), nil
+
+	default:
+		return "", fmt.Errorf("unsupported charset %q", parts[0])
+	}
+}
@@ -0,0 +1,161 @@
+package httpexpect
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentDispositionFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newContentDisposition(chain, contentDisposition{})
+
+	value.chain.assertFailed(t)
+
+	assert.NotNil(t, value.Type())
+	assert.NotNil(t, value.Filename())
+	assert.NotNil(t, value.Param("foo"))
+
+	value.Attachment()
+	value.Inline()
+}
+
+func TestContentDispositionParse(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		typ      string
+		filename string
+	}{
+		{
+			name:     "quoted",
+			value:    `attachment; filename="report.pdf"`,
+			typ:      "attachment",
+			filename: "report.pdf",
+		},
+		{
+			name:     "token",
+			value:    `attachment; filename=report.pdf`,
+			typ:      "attachment",
+			filename: "report.pdf",
+		},
+		{
+			name:     "case-insensitive",
+			value:    `ATTACHMENT; FileName="Report.pdf"`,
+			typ:      "attachment",
+			filename: "Report.pdf",
+		},
+		{
+			name:     "utf-8 extended",
+			value:    `attachment; filename*=UTF-8''%E2%82%AC%20rates.txt`,
+			typ:      "attachment",
+			filename: "€ rates.txt",
+		},
+		{
+			name: "extended takes precedence",
+			value: `attachment; filename="EUR rates.txt"; ` +
+				`filename*=utf-8'en'%E2%82%AC%20rates.txt`,
+			typ:      "attachment",
+			filename: "€ rates.txt",
+		},
+		{
+			name:     "extended before plain",
+			value:    `attachment; filename*=UTF-8''%C3%A9t%C3%A9.txt; filename="ete.txt"`,
+			typ:      "attachment",
+			filename: "été.txt",
+		},
+		{
+			name:     "iso-8859-1 extended",
+			value:    `attachment; filename*=iso-8859-1'en'%A3%20rates.txt`,
+			typ:      "attachment",
+			filename: "£ rates.txt",
+		},
+		{
+			name:     "inline",
+			value:    `inline; filename="image.png"`,
+			typ:      "inline",
+			filename: "image.png",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			value := NewContentDisposition(newMockReporter(t), tc.value)
+			value.chain.assertOK(t)
+
+			assert.Equal(t, tc.value, value.Raw())
+
+			value.Type().Equal(tc.typ).chain.assertOK(t)
+			value.Filename().Equal(tc.filename).chain.assertOK(t)
+		})
+	}
+}
+
+func TestContentDispositionInvalid(t *testing.T) {
+	cases := []string{
+		``,
+		`attachment; filename=a b`,
+		`attachment; filename*=UTF-8''bad%ZZ`,
+		`attachment; filename*=UTF-8''%FF%FE`,
+		`attachment; filename*=koi8-r''%C1`,
+		`attachment; filename*=no-quotes`,
+	}
+
+	for _, value := range cases {
+		t.Run(value, func(t *testing.T) {
+			NewContentDisposition(newMockReporter(t), value).chain.assertFailed(t)
+		})
+	}
+}
+
+func TestContentDispositionType(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	NewContentDisposition(reporter, "attachment").Attachment().chain.assertOK(t)
+	NewContentDisposition(reporter, "attachment").Inline().chain.assertFailed(t)
+
+	NewContentDisposition(reporter, "inline").Inline().chain.assertOK(t)
+	NewContentDisposition(reporter, "inline").Attachment().chain.assertFailed(t)
+}
+
+func TestContentDispositionParam(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewContentDisposition(reporter,
+		`form-data; name="file"; filename="a.txt"; title*=UTF-8''%C3%A9t%C3%A9`)
+
+	value.Param("name").Equal("file").chain.assertOK(t)
+	value.Param("Title").Equal("été").chain.assertOK(t)
+	value.Param("missing").chain.assertFailed(t)
+
+	NewContentDisposition(reporter, "attachment").Filename().chain.assertFailed(t)
+}
+
+func TestResponseContentDisposition(t *testing.T) {
+	newResp := func(t *testing.T, header http.Header) *Response {
+		return NewResponse(newMockReporter(t), &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		})
+	}
+
+	resp := newResp(t, http.Header{
+		"Content-Disposition": {`attachment; filename*=UTF-8''%E2%82%AC.txt`},
+	})
+	resp.ContentDisposition().Attachment().Filename().Equal("€.txt").
+		chain.assertOK(t)
+
+	resp = newResp(t, http.Header{})
+	resp.ContentDisposition().chain.assertFailed(t)
+
+	resp = newResp(t, http.Header{
+		"Content-Disposition": {`attachment; filename=a b`},
+	})
+	resp.ContentDisposition().chain.assertFailed(t)
+}
@@ -0,0 +1,661 @@
+package httpexpect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ContentRange provides methods to inspect parsed Content-Range header,
+// see RFC 7233, section 4.2.
+//
+// Header has form "bytes 0-99/1000", "bytes 0-99/*" (unknown total length),
+// or "bytes */1000" (unsatisfied range, used in 416 responses).
+type ContentRange struct {
+	chain *chain
+	value contentRange
+}
+
+type contentRange struct {
+	raw   string
+	unit  string
+	start int64 // -1 if unsatisfied
+	end   int64 // -1 if unsatisfied
+	total int64 // -1 if unknown
+}
+
+// NewContentRange returns a new ContentRange instance.
+//
+// reporter should not be nil. value is Content-Range header value, e.g.
+// "bytes 0-99/1000". If value can't be parsed, failure is reported.
+//
+// Example:
+//
+//	cr := NewContentRange(t, "bytes 0-99/1000")
+//	cr.Start().Equal(0)
+//	cr.End().Equal(99)
+//	cr.Total().Equal(1000)
+func NewContentRange(reporter Reporter, value string) *ContentRange {
+	chain := newChainWithDefaults("ContentRange()", reporter)
+
+	rng, err := parseContentRange(value)
+	if err != nil {
+		chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{value},
+			Errors: []error{
+				errors.New(`expected: valid "Content-Range" value`),
+				err,
+			},
+		})
+	}
+
+	return newContentRange(chain, rng)
+}
+
+func newContentRange(parent *chain, value contentRange) *ContentRange {
+	return &ContentRange{parent.clone(), value}
+}
+
+// Raw returns Content-Range header value.
+//
+// Example:
+//
+//	cr := NewContentRange(t, "bytes 0-99/1000")
+//	assert.Equal(t, "bytes 0-99/1000", cr.Raw())
+func (c *ContentRange) Raw() string {
+	return c.value.raw
+}
+
+// Unit returns a new String instance with range unit, usually "bytes".
+//
+// Example:
+//
+//	cr := NewContentRange(t, "bytes 0-99/1000")
+//	cr.Unit().Equal("bytes")
+func (c *ContentRange) Unit() *String {
+	c.chain.enter("Unit()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newString(c.chain, "")
+	}
+
+	return newString(c.chain, c.value.unit)
+}
+
+// Start returns a new Number instance with position of the first byte
+// of range.
+//
+// Start fails if range is unsatisfied ("*").
+//
+// Example:
+//
+//	cr := NewContentRange(t, "bytes 100-199/1000")
+//	cr.Start().Equal(100)
+func (c *ContentRange) Start() *Number {
+	c.chain.enter("Start()")
+	defer c.chain.leave()
+
+	if !c.checkSatisfied() {
+		return newNumber(c.chain, 0)
+	}
+
+	return newNumber(c.chain, float64(c.value.start))
+}
+
+// End returns a new Number instance with position of the last byte
+// of range (inclusive).
+//
+// End fails if range is unsatisfied ("*").
+//
+// Example:
+//
+//	cr := NewContentRange(t, "bytes 100-199/1000")
+//	cr.End().Equal(199)
+func (c *ContentRange) End() *Number {
+	c.chain.enter("End()")
+	defer c.chain.leave()
+
+	if !c.checkSatisfied() {
+		return newNumber(c.chain, 0)
+	}
+
+	return newNumber(c.chain, float64(c.value.end))
+}
+
+// Length returns a new Number instance with number of bytes in range,
+// i.e. End - Start + 1.
+//
+// Length fails if range is unsatisfied ("*").
+//
+// Example:
+//
+//	cr := NewContentRange(t, "bytes 100-199/1000")
+//	cr.Length().Equal(100)
+func (c *ContentRange) Length() *Number {
+	c.chain.enter("Length()")
+	defer c.chain.leave()
+
+	if !c.checkSatisfied() {
+		return newNumber(c.chain, 0)
+	}
+
+	return newNumber(c.chain, float64(c.value.end-c.value.start+1))
+}
+
+// Total returns a new Number instance with complete length of
+// representation.
+//
+// Total fails if complete length is unknown ("*").
+//
+// Example:
+//
+//	cr := NewContentRange(t, "bytes 100-199/1000")
+//	cr.Total().Equal(1000)
+func (c *ContentRange) Total() *Number {
+	c.chain.enter("Total()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newNumber(c.chain, 0)
+	}
+
+	if c.value.total < 0 {
+		c.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{c.value.raw},
+			Errors: []error{
+				errors.New(`expected: "Content-Range" with known complete length`),
+			},
+		})
+		return newNumber(c.chain, 0)
+	}
+
+	return newNumber(c.chain, float64(c.value.total))
+}
+
+// Unsatisfied succeeds if range is unsatisfied, i.e. header has form
+// "bytes */1000". Servers send it with 416 Range Not Satisfiable status.
+//
+// Example:
+//
+//	cr := NewContentRange(t, "bytes */1000")
+//	cr.Unsatisfied()
+func (c *ContentRange) Unsatisfied() *ContentRange {
+	c.chain.enter("Unsatisfied()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	if c.value.start >= 0 {
+		c.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{c.value.raw},
+			Errors: []error{
+				errors.New(`expected: unsatisfied "Content-Range"`),
+			},
+		})
+	}
+
+	return c
+}
+
+func (c *ContentRange) checkSatisfied() bool {
+	if c.chain.failed() {
+		return false
+	}
+
+	if c.value.start < 0 {
+		c.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{c.value.raw},
+			Errors: []error{
+				errors.New(`expected: satisfied "Content-Range"`),
+			},
+		})
+		return false
+	}
+
+	return true
+}
+
+// parses Content-Range header value
+func parseContentRange(value string) (contentRange, error) {
+	rng := contentRange{raw: value, start: -1, end: -1, total: -1}
+
+	sp := strings.IndexByte(value, ' ')
+	if sp <= 0 {
+		return rng, errors.New("missing range unit")
+	}
+
+	rng.unit = value[:sp]
+
+	spec := strings.TrimSpace(value[sp+1:])
+
+	slash := strings.IndexByte(spec, '/')
+	if slash < 0 {
+		return rng, errors.New("missing complete length")
+	}
+
+	resp, total := spec[:slash], spec[slash+1:]
+
+	if total != "*" {
+		n, err := strconv.ParseInt(total, 10, 64)
+		if err != nil || n < 0 {
+			return rng, fmt.Errorf("invalid complete length %q", total)
+		}
+		rng.total = n
+	}
+
+	if resp == "*" {
+		if rng.total < 0 {
+			return rng, errors.New("unsatisfied range without complete length")
+		}
+		return rng, nil
+	}
+
+	dash := strings.IndexByte(resp, '-')
+	if dash < 0 {
+		return rng, fmt.Errorf("invalid range %q", resp)
+	}
+
+	start, err1 := strconv.ParseInt(resp[:dash], 10, 64)
+	end, err2 := strconv.ParseInt(resp[dash+1:], 10, 64)
+
+	if err1 != nil || err2 != nil || start < 0 || end < start {
+		return rng, fmt.Errorf("invalid range %q", resp)
+	}
+
+	if rng.total >= 0 && end >= rng.total {
+		return rng, fmt.Errorf("range %q exceeds complete length %d",
+			resp, rng.total)
+	}
+
+	rng.start, rng.end = start, end
+
+	return rng, nil
+}
+
+// ByteRanges provides methods to inspect parts of partial content response
+// (206 Partial Content).
+//
+// Response to single range request has one part: the whole body with
+// range from Content-Range header. Response to multi-range request has
+// multipart/byteranges body, where every part has its own Content-Range.
+type ByteRanges struct {
+	chain *chain
+	parts []byteRangePart
+}
+
+// ByteRange provides methods to inspect single part of partial content
+// response.
+type ByteRange struct {
+	chain *chain
+	part  byteRangePart
+}
+
+type byteRangePart struct {
+	header http.Header
+	rng    contentRange
+	body   []byte
+}
+
+// ByteRanges returns a new ByteRanges instance with parts of partial
+// content response.
+//
+// ByteRanges fails if response has neither Content-Range header nor
+// multipart/byteranges body, or if any part is malformed.
+//
+// Example:
+//
+//	resp := e.GET("/file").WithRange(0, 9).WithRange(100, 109).Expect()
+//
+//	ranges := resp.Status(http.StatusPartialContent).ByteRanges()
+//	ranges.Length().Equal(2)
+//	ranges.Part(1).ContentRange().Start().Equal(100)
+func (r *Response) ByteRanges() *ByteRanges {
+	r.chain.enter("ByteRanges()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newByteRanges(r.chain, nil)
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(r.httpResp.Header.Get("Content-Type"))
+
+	if strings.EqualFold(mediaType, "multipart/byteranges") {
+		parts, err := parseByteRanges(r.content, params["boundary"])
+		if err != nil {
+			r.chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{string(r.content)},
+				Errors: []error{
+					errors.New("expected: valid multipart/byteranges body"),
+					err,
+				},
+			})
+			return newByteRanges(r.chain, nil)
+		}
+		return newByteRanges(r.chain, parts)
+	}
+
+	rng, ok := r.parseContentRange()
+	if !ok {
+		return newByteRanges(r.chain, nil)
+	}
+
+	return newByteRanges(r.chain, []byteRangePart{
+		{
+			header: r.httpResp.Header,
+			rng:    rng,
+			body:   r.content,
+		},
+	})
+}
+
+// ContentRange returns a new ContentRange instance with parsed
+// Content-Range header.
+//
+// ContentRange fails if header is missing or malformed.
+//
+// Example:
+//
+//	resp := e.GET("/file").WithRange(100, -1).Expect()
+//	resp.Status(http.StatusPartialContent).ContentRange().Start().Equal(100)
+func (r *Response) ContentRange() *ContentRange {
+	r.chain.enter("ContentRange()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newContentRange(r.chain, contentRange{})
+	}
+
+	rng, ok := r.parseContentRange()
+	if !ok {
+		return newContentRange(r.chain, contentRange{})
+	}
+
+	return newContentRange(r.chain, rng)
+}
+
+func (r *Response) parseContentRange() (contentRange, bool) {
+	value := r.httpResp.Header.Get("Content-Range")
+	if value == "" {
+		r.chain.fail(AssertionFailure{
+			Type: AssertContainsKey,
+			Actual: &AssertionValue{
+				r.httpResp.Header,
+			},
+			Expected: &AssertionValue{"Content-Range"},
+			Errors: []error{
+				errors.New(`expected: response has "Content-Range" header`),
+			},
+		})
+		return contentRange{}, false
+	}
+
+	rng, err := parseContentRange(value)
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{value},
+			Errors: []error{
+				errors.New(`expected: valid "Content-Range" header`),
+				err,
+			},
+		})
+		return contentRange{}, false
+	}
+
+	return rng, true
+}
+
+// parses multipart/byteranges body
+func parseByteRanges(body []byte, boundary string) ([]byteRangePart, error) {
+	if boundary == "" {
+		return nil, errors.New("missing boundary parameter in Content-Type")
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+
+	var parts []byteRangePart
+
+	for n := 1; ; n++ {
+		part, err := reader.NextPart()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("part %d: %w", n, err)
+		}
+
+		partBody, err := ioutil.ReadAll(part)
+		if err != nil {
+			return nil, fmt.Errorf("part %d: %w", n, err)
+		}
+
+		header := http.Header(part.Header)
+
+		rng, err := parseContentRange(header.Get("Content-Range"))
+		if err != nil {
+			return nil, fmt.Errorf("part %d: invalid Content-Range: %w", n, err)
+		}
+
+		if rng.start < 0 {
+			return nil, fmt.Errorf("part %d: unsatisfied Content-Range", n)
+		}
+
+		if int64(len(partBody)) != rng.end-rng.start+1 {
+			return nil, fmt.Errorf(
+				"part %d: body has %d bytes, Content-Range %q declares %d",
+				n, len(partBody), rng.raw, rng.end-rng.start+1)
+		}
+
+		parts = append(parts, byteRangePart{
+			header: header,
+			rng:    rng,
+			body:   partBody,
+		})
+	}
+
+	if len(parts) == 0 {
+		return nil, errors.New("no parts")
+	}
+
+	return parts, nil
+}
+
+func newByteRanges(parent *chain, parts []byteRangePart) *ByteRanges {
+	return &ByteRanges{parent.clone(), parts}
+}
+
+// Length returns a new Number instance with number of parts.
+//
+// Example:
+//
+//	ranges := resp.ByteRanges()
+//	ranges.Length().Equal(2)
+func (b *ByteRanges) Length() *Number {
+	b.chain.enter("Length()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return newNumber(b.chain, 0)
+	}
+
+	return newNumber(b.chain, float64(len(b.parts)))
+}
+
+// Part returns a new ByteRange instance for part with given index.
+//
+// Part fails if index is out of range.
+//
+// Example:
+//
+//	ranges := resp.ByteRanges()
+//	ranges.Part(0).Body().Equal("hello")
+func (b *ByteRanges) Part(index int) *ByteRange {
+	b.chain.enter("Part(%d)", index)
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return newByteRange(b.chain, byteRangePart{})
+	}
+
+	if index < 0 || index >= len(b.parts) {
+		b.chain.fail(AssertionFailure{
+			Type:   AssertInRange,
+			Actual: &AssertionValue{index},
+			Expected: &AssertionValue{AssertionRange{
+				Min: 0,
+				Max: len(b.parts) - 1,
+			}},
+			Errors: []error{
+				errors.New("expected: valid part index"),
+			},
+		})
+		return newByteRange(b.chain, byteRangePart{})
+	}
+
+	return newByteRange(b.chain, b.parts[index])
+}
+
+// MatchContent succeeds if every part is equal to corresponding range of
+// given full content, and complete length of every part (if known) is
+// equal to content length.
+//
+// Useful to check that resumed or segmented download produces original
+// file.
+//
+// Example:
+//
+//	e.GET("/file").WithRange(1024, -1).Expect().
+//	    Status(http.StatusPartialContent).
+//	    ByteRanges().MatchContent(original)
+func (b *ByteRanges) MatchContent(content []byte) *ByteRanges {
+	b.chain.enter("MatchContent()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return b
+	}
+
+	var errs []error
+
+	for n, part := range b.parts {
+		rng := part.rng
+
+		if rng.total >= 0 && rng.total != int64(len(content)) {
+			errs = append(errs, fmt.Errorf(
+				"part %d: complete length is %d, expected %d",
+				n, rng.total, len(content)))
+			continue
+		}
+
+		if rng.end >= int64(len(content)) {
+			errs = append(errs, fmt.Errorf(
+				"part %d: range %d-%d exceeds content length %d",
+				n, rng.start, rng.end, len(content)))
+			continue
+		}
+
+		if !bytes.Equal(part.body, content[rng.start:rng.end+1]) {
+			errs = append(errs, fmt.Errorf(
+				"part %d: body differs from content range %d-%d",
+				n, rng.start, rng.end))
+		}
+	}
+
+	if len(errs) != 0 {
+		b.chain.fail(AssertionFailure{
+			Type: AssertEqual,
+			Errors: append([]error{
+				errors.New("expected: parts match content"),
+			}, errs...),
+		})
+	}
+
+	return b
+}
+
+func newByteRange(parent *chain, part byteRangePart) *ByteRange {
+	return &ByteRange{parent.clone(), part}
+}
+
+// ContentRange returns a new ContentRange instance with range of the part.
+//
+// Example:
+//
+//	part := resp.ByteRanges().Part(0)
+//	part.ContentRange().Start().Equal(0)
+func (b *ByteRange) ContentRange() *ContentRange {
+	b.chain.enter("ContentRange()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return newContentRange(b.chain, contentRange{})
+	}
+
+	return newContentRange(b.chain, b.part.rng)
+}
+
+// ContentType returns a new String instance with Content-Type of the part.
+//
+// Example:
+//
+//	part := resp.ByteRanges().Part(0)
+//	part.ContentType().Equal("text/plain")
+func (b *ByteRange) ContentType() *String {
+	b.chain.enter("ContentType()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return newString(b.chain, "")
+	}
+
+	return newString(b.chain, b.part.header.Get("Content-Type"))
+}
+
+// Body returns a new String instance with body of the part.
+//
+// Example:
+//
+//	part := resp.ByteRanges().Part(0)
+//	part.Body().Equal("hello")
+func (b *ByteRange) Body() *String {
+	b.chain.enter("Body()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return newString(b.chain, "")
+	}
+
+	return newString(b.chain, string(b.part.body))
+}
+
+// Bytes returns a new Bytes instance with body of the part.
+//
+// Example:
+//
+//	part := resp.ByteRanges().Part(0)
+//	part.Bytes().HasPrefix([]byte("\x89PNG"))
+func (b *ByteRange) Bytes() *Bytes {
+	b.chain.enter("Bytes()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return newBytes(b.chain, nil)
+	}
+
+	return newBytes(b.chain, b.part.body)
+}
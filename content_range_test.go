@@ -0,0 +1,234 @@
+package httpexpect
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentRangeFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	cr := newContentRange(chain, contentRange{})
+
+	cr.Unit().chain.assertFailed(t)
+	cr.Start().chain.assertFailed(t)
+	cr.End().chain.assertFailed(t)
+	cr.Length().chain.assertFailed(t)
+	cr.Total().chain.assertFailed(t)
+	cr.Unsatisfied().chain.assertFailed(t)
+
+	ranges := newByteRanges(chain, nil)
+
+	ranges.Length().chain.assertFailed(t)
+	ranges.MatchContent(nil).chain.assertFailed(t)
+
+	part := ranges.Part(0)
+	part.chain.assertFailed(t)
+
+	part.ContentRange().chain.assertFailed(t)
+	part.ContentType().chain.assertFailed(t)
+	part.Body().chain.assertFailed(t)
+	part.Bytes().chain.assertFailed(t)
+}
+
+func TestContentRangeParse(t *testing.T) {
+	cases := []struct {
+		value string
+		ok    bool
+		unit  string
+		start int64
+		end   int64
+		total int64
+	}{
+		{value: "bytes 0-99/1000", ok: true, unit: "bytes", start: 0, end: 99, total: 1000},
+		{value: "bytes 5-5/*", ok: true, unit: "bytes", start: 5, end: 5, total: -1},
+		{value: "bytes */1000", ok: true, unit: "bytes", start: -1, end: -1, total: 1000},
+		{value: "items 1-2/3", ok: true, unit: "items", start: 1, end: 2, total: 3},
+		{value: ""},
+		{value: "bytes"},
+		{value: "bytes 0-99"},
+		{value: "bytes */*"},
+		{value: "bytes 10-5/100"},
+		{value: "bytes 0-100/100"},
+		{value: "bytes a-b/100"},
+		{value: "bytes 0-1/x"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			rng, err := parseContentRange(tc.value)
+
+			if !tc.ok {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, contentRange{
+				raw:   tc.value,
+				unit:  tc.unit,
+				start: tc.start,
+				end:   tc.end,
+				total: tc.total,
+			}, rng)
+		})
+	}
+}
+
+func TestContentRangeMethods(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	cr := NewContentRange(reporter, "bytes 100-199/1000")
+	cr.chain.assertOK(t)
+
+	assert.Equal(t, "bytes 100-199/1000", cr.Raw())
+
+	cr.Unit().Equal("bytes").chain.assertOK(t)
+	cr.Start().Equal(100).chain.assertOK(t)
+	cr.End().Equal(199).chain.assertOK(t)
+	cr.Length().Equal(100).chain.assertOK(t)
+	cr.Total().Equal(1000).chain.assertOK(t)
+
+	cr.Unsatisfied().chain.assertFailed(t)
+	cr.chain.reset()
+
+	cr = NewContentRange(reporter, "bytes */1000")
+	cr.chain.assertOK(t)
+
+	cr.Unsatisfied().chain.assertOK(t)
+	cr.Total().Equal(1000).chain.assertOK(t)
+
+	cr.Start().chain.assertFailed(t)
+	cr.chain.reset()
+
+	cr.End().chain.assertFailed(t)
+	cr.chain.reset()
+
+	cr.Length().chain.assertFailed(t)
+	cr.chain.reset()
+
+	cr = NewContentRange(reporter, "bytes 0-9/*")
+	cr.Total().chain.assertFailed(t)
+
+	cr = NewContentRange(reporter, "bad")
+	cr.chain.assertFailed(t)
+}
+
+func TestContentRangeResponse(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 10))
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		http.ServeContent(w, r, "file.txt", time.Time{}, bytes.NewReader(content))
+	})
+
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   &http.Client{Transport: NewBinder(handler)},
+		Reporter: newMockReporter(t),
+	})
+
+	t.Run("single range", func(t *testing.T) {
+		resp := e.GET("/file").WithRange(10, 14).Expect()
+		resp.Status(http.StatusPartialContent)
+
+		cr := resp.ContentRange()
+		cr.Start().Equal(10)
+		cr.End().Equal(14)
+		cr.Total().Equal(100)
+		cr.chain.assertOK(t)
+
+		ranges := resp.ByteRanges()
+		ranges.Length().Equal(1)
+		ranges.Part(0).Body().Equal("01234")
+		ranges.MatchContent(content)
+		ranges.chain.assertOK(t)
+
+		ranges.MatchContent(content[1:]).chain.assertFailed(t)
+	})
+
+	t.Run("open range", func(t *testing.T) {
+		resp := e.GET("/file").WithRange(95, -1).Expect()
+		resp.Status(http.StatusPartialContent)
+
+		resp.ContentRange().Length().Equal(5).chain.assertOK(t)
+		resp.ByteRanges().MatchContent(content).chain.assertOK(t)
+	})
+
+	t.Run("multiple ranges", func(t *testing.T) {
+		resp := e.GET("/file").WithRange(0, 2).WithRange(50, 54).Expect()
+		resp.Status(http.StatusPartialContent)
+		resp.ContentType("multipart/byteranges")
+
+		resp.ContentRange().chain.assertFailed(t)
+		resp.chain.reset()
+
+		ranges := resp.ByteRanges()
+		ranges.chain.assertOK(t)
+
+		ranges.Length().Equal(2).chain.assertOK(t)
+
+		first := ranges.Part(0)
+		first.ContentType().Equal("text/plain").chain.assertOK(t)
+		first.ContentRange().Start().Equal(0).chain.assertOK(t)
+		first.Body().Equal("012").chain.assertOK(t)
+
+		second := ranges.Part(1)
+		second.ContentRange().Start().Equal(50).chain.assertOK(t)
+		second.Bytes().Equal([]byte("01234")).chain.assertOK(t)
+
+		ranges.MatchContent(content).chain.assertOK(t)
+
+		ranges.Part(2).chain.assertFailed(t)
+	})
+
+	t.Run("unsatisfied", func(t *testing.T) {
+		resp := e.GET("/file").WithRange(200, -1).Expect()
+		resp.Status(http.StatusRequestedRangeNotSatisfiable)
+
+		resp.ContentRange().Unsatisfied().Total().Equal(100).chain.assertOK(t)
+	})
+
+	t.Run("full content", func(t *testing.T) {
+		resp := e.GET("/file").Expect()
+		resp.Status(http.StatusOK)
+
+		resp.ByteRanges().chain.assertFailed(t)
+	})
+}
+
+func TestContentRangeMultipart(t *testing.T) {
+	newResp := func(t *testing.T, contentType, body string) *Response {
+		return NewResponse(newMockReporter(t), &http.Response{
+			StatusCode: http.StatusPartialContent,
+			Header:     http.Header{"Content-Type": {contentType}},
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		})
+	}
+
+	body := "--B\r\nContent-Range: bytes 0-2/10\r\n\r\nabc\r\n--B--\r\n"
+
+	resp := newResp(t, "multipart/byteranges; boundary=B", body)
+	resp.ByteRanges().Length().Equal(1).chain.assertOK(t)
+
+	resp = newResp(t, "multipart/byteranges", body)
+	resp.ByteRanges().chain.assertFailed(t)
+
+	resp = newResp(t, "multipart/byteranges; boundary=B",
+		"--B\r\n\r\nabc\r\n--B--\r\n")
+	resp.ByteRanges().chain.assertFailed(t)
+
+	resp = newResp(t, "multipart/byteranges; boundary=B",
+		"--B\r\nContent-Range: bytes 0-5/10\r\n\r\nabc\r\n--B--\r\n")
+	resp.ByteRanges().chain.assertFailed(t)
+
+	resp = newResp(t, "multipart/byteranges; boundary=B", "--B--\r\n")
+	resp.ByteRanges().chain.assertFailed(t)
+}
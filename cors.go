@@ -0,0 +1,387 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS provides methods to inspect CORS response headers, i.e.
+// Access-Control-* headers of preflight or actual response.
+//
+// Checks follow Fetch Standard rules used by browsers: e.g. wildcard
+// values are not honored for requests with credentials.
+type CORS struct {
+	chain  *chain
+	header http.Header
+}
+
+// NewCORS returns a new CORS instance.
+//
+// reporter should not be nil. header is response header.
+//
+// Example:
+//
+//	cors := NewCORS(t, http.Header{
+//	    "Access-Control-Allow-Origin":  {"https://example.com"},
+//	    "Access-Control-Allow-Methods": {"GET, PUT"},
+//	})
+//	cors.AllowsOrigin("https://example.com").AllowsMethod("PUT")
+func NewCORS(reporter Reporter, header http.Header) *CORS {
+	return newCORS(newChainWithDefaults("CORS()", reporter), header)
+}
+
+func newCORS(parent *chain, header http.Header) *CORS {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &CORS{parent.clone(), header}
+}
+
+// Raw returns response header.
+func (c *CORS) Raw() http.Header {
+	return c.header
+}
+
+// AllowsOrigin succeeds if Access-Control-Allow-Origin is either equal to
+// given origin, or is "*" and credentials are not allowed.
+//
+// Example:
+//
+//	resp := e.OPTIONS("/users").
+//	    WithCORSPreflight("https://example.com", "PUT").
+//	    Expect()
+//	resp.CORS().AllowsOrigin("https://example.com")
+func (c *CORS) AllowsOrigin(origin string) *CORS {
+	c.chain.enter("AllowsOrigin(%q)", origin)
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	if err := c.checkOrigin(origin); err != nil {
+		c.chain.fail(AssertionFailure{
+			Type:     AssertValid,
+			Actual:   &AssertionValue{c.header.Get("Access-Control-Allow-Origin")},
+			Expected: &AssertionValue{origin},
+			Errors: []error{
+				errors.New("expected: CORS allows origin"),
+				err,
+			},
+		})
+	}
+
+	return c
+}
+
+// NotAllowsOrigin succeeds if AllowsOrigin would fail for given origin.
+//
+// Example:
+//
+//	resp := e.OPTIONS("/users").
+//	    WithCORSPreflight("https://evil.com", "PUT").
+//	    Expect()
+//	resp.CORS().NotAllowsOrigin("https://evil.com")
+func (c *CORS) NotAllowsOrigin(origin string) *CORS {
+	c.chain.enter("NotAllowsOrigin(%q)", origin)
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	if err := c.checkOrigin(origin); err == nil {
+		c.chain.fail(AssertionFailure{
+			Type:     AssertNotValid,
+			Actual:   &AssertionValue{c.header.Get("Access-Control-Allow-Origin")},
+			Expected: &AssertionValue{origin},
+			Errors: []error{
+				errors.New("expected: CORS does not allow origin"),
+			},
+		})
+	}
+
+	return c
+}
+
+// AllowsMethod succeeds if Access-Control-Allow-Methods contains given
+// method, or contains "*" and credentials are not allowed.
+//
+// Method comparison is case-sensitive. CORS-safelisted methods (GET, HEAD,
+// and POST) are always allowed.
+//
+// Example:
+//
+//	resp := e.OPTIONS("/users").
+//	    WithCORSPreflight("https://example.com", "DELETE").
+//	    Expect()
+//	resp.CORS().AllowsMethod("DELETE")
+func (c *CORS) AllowsMethod(method string) *CORS {
+	c.chain.enter("AllowsMethod(%q)", method)
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost:
+		return c
+	}
+
+	methods := c.list("Access-Control-Allow-Methods")
+
+	for _, m := range methods {
+		if m == method || (m == "*" && !c.credentials()) {
+			return c
+		}
+	}
+
+	c.chain.fail(AssertionFailure{
+		Type:     AssertContainsElement,
+		Actual:   &AssertionValue{methods},
+		Expected: &AssertionValue{method},
+		Errors: []error{
+			errors.New("expected: Access-Control-Allow-Methods contains method"),
+		},
+	})
+
+	return c
+}
+
+// AllowsHeader succeeds if Access-Control-Allow-Headers contains given
+// request header name, or contains "*" and credentials are not allowed.
+//
+// Header name comparison is case-insensitive. Wildcard doesn't cover
+// Authorization header, which should always be listed explicitly.
+//
+// Example:
+//
+//	resp := e.OPTIONS("/users").
+//	    WithCORSPreflight("https://example.com", "PUT", "X-Request-Id").
+//	    Expect()
+//	resp.CORS().AllowsHeader("X-Request-Id")
+func (c *CORS) AllowsHeader(name string) *CORS {
+	c.chain.enter("AllowsHeader(%q)", name)
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	headers := c.list("Access-Control-Allow-Headers")
+
+	for _, h := range headers {
+		if strings.EqualFold(h, name) {
+			return c
+		}
+		if h == "*" && !c.credentials() && !strings.EqualFold(name, "Authorization") {
+			return c
+		}
+	}
+
+	c.chain.fail(AssertionFailure{
+		Type:     AssertContainsElement,
+		Actual:   &AssertionValue{headers},
+		Expected: &AssertionValue{name},
+		Errors: []error{
+			errors.New("expected: Access-Control-Allow-Headers contains header"),
+		},
+	})
+
+	return c
+}
+
+// ExposesHeader succeeds if Access-Control-Expose-Headers contains given
+// response header name, or contains "*" and credentials are not allowed.
+//
+// Header name comparison is case-insensitive.
+//
+// Example:
+//
+//	resp := e.GET("/users").
+//	    WithHeader("Origin", "https://example.com").
+//	    Expect()
+//	resp.CORS().ExposesHeader("X-Total-Count")
+func (c *CORS) ExposesHeader(name string) *CORS {
+	c.chain.enter("ExposesHeader(%q)", name)
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	headers := c.list("Access-Control-Expose-Headers")
+
+	for _, h := range headers {
+		if strings.EqualFold(h, name) || (h == "*" && !c.credentials()) {
+			return c
+		}
+	}
+
+	c.chain.fail(AssertionFailure{
+		Type:     AssertContainsElement,
+		Actual:   &AssertionValue{headers},
+		Expected: &AssertionValue{name},
+		Errors: []error{
+			errors.New("expected: Access-Control-Expose-Headers contains header"),
+		},
+	})
+
+	return c
+}
+
+// AllowsCredentials succeeds if Access-Control-Allow-Credentials is "true".
+//
+// Example:
+//
+//	resp := e.OPTIONS("/users").
+//	    WithCORSPreflight("https://example.com", "PUT").
+//	    Expect()
+//	resp.CORS().AllowsOrigin("https://example.com").AllowsCredentials()
+func (c *CORS) AllowsCredentials() *CORS {
+	c.chain.enter("AllowsCredentials()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	if !c.credentials() {
+		c.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{c.header.Get("Access-Control-Allow-Credentials")},
+			Expected: &AssertionValue{"true"},
+			Errors: []error{
+				errors.New(
+					`expected: Access-Control-Allow-Credentials is "true"`),
+			},
+		})
+	}
+
+	return c
+}
+
+// NotAllowsCredentials succeeds if Access-Control-Allow-Credentials is
+// missing or is not "true".
+func (c *CORS) NotAllowsCredentials() *CORS {
+	c.chain.enter("NotAllowsCredentials()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	if c.credentials() {
+		c.chain.fail(AssertionFailure{
+			Type:     AssertNotEqual,
+			Actual:   &AssertionValue{c.header.Get("Access-Control-Allow-Credentials")},
+			Expected: &AssertionValue{"true"},
+			Errors: []error{
+				errors.New(
+					`expected: Access-Control-Allow-Credentials is not "true"`),
+			},
+		})
+	}
+
+	return c
+}
+
+// MaxAge returns a new Duration instance with value of
+// Access-Control-Max-Age header, i.e. how long preflight result
+// can be cached.
+//
+// MaxAge fails if header is missing or is not a non-negative integer.
+//
+// Example:
+//
+//	resp := e.OPTIONS("/users").
+//	    WithCORSPreflight("https://example.com", "PUT").
+//	    Expect()
+//	resp.CORS().MaxAge().Ge(10 * time.Minute)
+func (c *CORS) MaxAge() *Duration {
+	c.chain.enter("MaxAge()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newDuration(c.chain, nil)
+	}
+
+	value := c.header.Get("Access-Control-Max-Age")
+
+	if value == "" {
+		c.chain.fail(AssertionFailure{
+			Type:     AssertContainsKey,
+			Actual:   &AssertionValue{c.header},
+			Expected: &AssertionValue{"Access-Control-Max-Age"},
+			Errors: []error{
+				errors.New("expected: response contains Access-Control-Max-Age header"),
+			},
+		})
+		return newDuration(c.chain, nil)
+	}
+
+	secs, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+	if err != nil {
+		c.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{value},
+			Errors: []error{
+				errors.New("invalid Access-Control-Max-Age header value"),
+				errors.New("expected: non-negative integer number of seconds"),
+			},
+		})
+		return newDuration(c.chain, nil)
+	}
+
+	d := time.Duration(secs) * time.Second
+
+	return newDuration(c.chain, &d)
+}
+
+func (c *CORS) checkOrigin(origin string) error {
+	allowed := headerValues(c.header, "Access-Control-Allow-Origin")
+
+	switch {
+	case len(allowed) == 0:
+		return errors.New("response has no Access-Control-Allow-Origin header")
+
+	case len(allowed) > 1:
+		return fmt.Errorf(
+			"response has %d Access-Control-Allow-Origin headers, expected one",
+			len(allowed))
+
+	case allowed[0] == "*":
+		if c.credentials() {
+			return errors.New(
+				`wildcard "*" origin is not allowed with credentials`)
+		}
+		return nil
+
+	case allowed[0] != origin:
+		return fmt.Errorf("allowed origin is %q", allowed[0])
+	}
+
+	return nil
+}
+
+func (c *CORS) credentials() bool {
+	return c.header.Get("Access-Control-Allow-Credentials") == "true"
+}
+
+// returns comma-separated list from all header values, with spaces trimmed
+func (c *CORS) list(name string) []string {
+	items := []string{}
+	for _, value := range headerValues(c.header, name) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
@@ -0,0 +1,244 @@
+package httpexpect
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newCORS(chain, nil)
+
+	value.chain.assertFailed(t)
+
+	assert.NotNil(t, value.Raw())
+	assert.NotNil(t, value.MaxAge())
+
+	value.AllowsOrigin("foo")
+	value.NotAllowsOrigin("foo")
+	value.AllowsMethod("PUT")
+	value.AllowsHeader("foo")
+	value.ExposesHeader("foo")
+	value.AllowsCredentials()
+	value.NotAllowsCredentials()
+}
+
+func TestCORSOrigin(t *testing.T) {
+	cases := []struct {
+		name    string
+		header  http.Header
+		allowed bool
+	}{
+		{
+			name: "exact",
+			header: http.Header{
+				"Access-Control-Allow-Origin": {"https://example.com"},
+			},
+			allowed: true,
+		},
+		{
+			name: "wildcard",
+			header: http.Header{
+				"Access-Control-Allow-Origin": {"*"},
+			},
+			allowed: true,
+		},
+		{
+			name: "wildcard with credentials",
+			header: http.Header{
+				"Access-Control-Allow-Origin":      {"*"},
+				"Access-Control-Allow-Credentials": {"true"},
+			},
+			allowed: false,
+		},
+		{
+			name: "other origin",
+			header: http.Header{
+				"Access-Control-Allow-Origin": {"https://other.com"},
+			},
+			allowed: false,
+		},
+		{
+			name: "trailing slash",
+			header: http.Header{
+				"Access-Control-Allow-Origin": {"https://example.com/"},
+			},
+			allowed: false,
+		},
+		{
+			name: "multiple values",
+			header: http.Header{
+				"Access-Control-Allow-Origin": {"https://example.com", "*"},
+			},
+			allowed: false,
+		},
+		{
+			name:    "missing",
+			header:  http.Header{},
+			allowed: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			if tc.allowed {
+				NewCORS(reporter, tc.header).AllowsOrigin("https://example.com").
+					chain.assertOK(t)
+				NewCORS(reporter, tc.header).NotAllowsOrigin("https://example.com").
+					chain.assertFailed(t)
+			} else {
+				NewCORS(reporter, tc.header).AllowsOrigin("https://example.com").
+					chain.assertFailed(t)
+				NewCORS(reporter, tc.header).NotAllowsOrigin("https://example.com").
+					chain.assertOK(t)
+			}
+		})
+	}
+}
+
+func TestCORSMethod(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewCORS(reporter, http.Header{
+		"Access-Control-Allow-Methods": {"PUT, PATCH", "DELETE"},
+	})
+
+	value.AllowsMethod("PUT").AllowsMethod("PATCH").AllowsMethod("DELETE").
+		chain.assertOK(t)
+
+	// safelisted
+	value.AllowsMethod("GET").AllowsMethod("POST").chain.assertOK(t)
+
+	NewCORS(reporter, value.Raw()).AllowsMethod("put").chain.assertFailed(t)
+	NewCORS(reporter, value.Raw()).AllowsMethod("OPTIONS").chain.assertFailed(t)
+
+	wildcard := http.Header{
+		"Access-Control-Allow-Methods": {"*"},
+	}
+	NewCORS(reporter, wildcard).AllowsMethod("PUT").chain.assertOK(t)
+
+	wildcard.Set("Access-Control-Allow-Credentials", "true")
+	NewCORS(reporter, wildcard).AllowsMethod("PUT").chain.assertFailed(t)
+}
+
+func TestCORSHeader(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	header := http.Header{
+		"Access-Control-Allow-Headers":  {"Content-Type,x-request-id"},
+		"Access-Control-Expose-Headers": {"X-Total-Count"},
+	}
+
+	NewCORS(reporter, header).
+		AllowsHeader("content-type").
+		AllowsHeader("X-Request-Id").
+		ExposesHeader("x-total-count").
+		chain.assertOK(t)
+
+	NewCORS(reporter, header).AllowsHeader("Authorization").chain.assertFailed(t)
+	NewCORS(reporter, header).ExposesHeader("X-Request-Id").chain.assertFailed(t)
+
+	wildcard := http.Header{
+		"Access-Control-Allow-Headers":  {"*"},
+		"Access-Control-Expose-Headers": {"*"},
+	}
+
+	NewCORS(reporter, wildcard).
+		AllowsHeader("X-Request-Id").
+		ExposesHeader("X-Total-Count").
+		chain.assertOK(t)
+
+	// wildcard doesn't cover Authorization
+	NewCORS(reporter, wildcard).AllowsHeader("Authorization").chain.assertFailed(t)
+
+	wildcard.Set("Access-Control-Allow-Credentials", "true")
+	NewCORS(reporter, wildcard).AllowsHeader("X-Request-Id").chain.assertFailed(t)
+	NewCORS(reporter, wildcard).ExposesHeader("X-Total-Count").chain.assertFailed(t)
+}
+
+func TestCORSCredentials(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	header := http.Header{"Access-Control-Allow-Credentials": {"true"}}
+
+	NewCORS(reporter, header).AllowsCredentials().chain.assertOK(t)
+	NewCORS(reporter, header).NotAllowsCredentials().chain.assertFailed(t)
+
+	for _, value := range []string{"", "false", "True"} {
+		header := http.Header{}
+		if value != "" {
+			header.Set("Access-Control-Allow-Credentials", value)
+		}
+
+		NewCORS(reporter, header).AllowsCredentials().chain.assertFailed(t)
+		NewCORS(reporter, header).NotAllowsCredentials().chain.assertOK(t)
+	}
+}
+
+func TestCORSMaxAge(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewCORS(reporter, http.Header{
+		"Access-Control-Max-Age": {"600"},
+	})
+	value.MaxAge().Equal(10 * time.Minute).chain.assertOK(t)
+
+	NewCORS(reporter, http.Header{}).MaxAge().chain.assertFailed(t)
+
+	for _, bad := range []string{"-1", "abc", "1.5"} {
+		NewCORS(reporter, http.Header{
+			"Access-Control-Max-Age": {bad},
+		}).MaxAge().chain.assertFailed(t)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if r.Method != http.MethodOptions || origin != "https://example.com" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		assert.Equal(t, "PUT", r.Header.Get("Access-Control-Request-Method"))
+		assert.Equal(t, "content-type,x-request-id",
+			r.Header.Get("Access-Control-Request-Headers"))
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PUT, DELETE")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, X-Request-Id")
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Set("Access-Control-Max-Age", "3600")
+		w.Header().Set("Vary", "Origin")
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   &http.Client{Transport: NewBinder(http.HandlerFunc(handler))},
+		Reporter: newMockReporter(t),
+	})
+
+	cors := e.OPTIONS("/users").
+		WithCORSPreflight("https://example.com", "PUT", "X-Request-Id", "Content-Type").
+		Expect().
+		Status(http.StatusNoContent).
+		CORS()
+
+	cors.AllowsOrigin("https://example.com").
+		AllowsMethod("PUT").
+		AllowsHeader("X-Request-Id").
+		AllowsCredentials().
+		chain.assertOK(t)
+
+	cors.MaxAge().Equal(time.Hour).chain.assertOK(t)
+
+	cors.AllowsMethod("PATCH").chain.assertFailed(t)
+}
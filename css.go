@@ -0,0 +1,466 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// CSS selectors subset:
+//   - type, universal, id, and class selectors: div, *, #main, .error
+//   - attribute selectors: [href], [type=text], [class~=a], [lang|=en],
+//     [href^=https], [href$=".pdf"], [title*=foo]
+//   - pseudo-classes: :first-child, :last-child, :only-child,
+//     :nth-child(n), :empty
+//   - combinators: descendant (space), child (>), adjacent sibling (+),
+//     general sibling (~)
+//   - selector groups: h1, h2
+type cssSelector []cssComplex
+
+// compound selectors and combinators between them, e.g. "div > p a"
+type cssComplex struct {
+	compounds   []cssCompound
+	combinators []byte
+}
+
+type cssCompound struct {
+	tag     string
+	filters []cssFilter
+}
+
+type cssFilter struct {
+	kind  byte // '#', '.', '[', ':'
+	name  string
+	op    string
+	value string
+	index int
+}
+
+type cssParser struct {
+	s   string
+	pos int
+}
+
+func parseCSS(s string) (cssSelector, error) {
+	p := &cssParser{s: strings.TrimSpace(s)}
+
+	if p.s == "" {
+		return nil, errors.New("empty selector")
+	}
+
+	var sel cssSelector
+
+	for {
+		complex, err := p.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		sel = append(sel, complex)
+
+		p.skipSpaces()
+		if p.pos == len(p.s) {
+			return sel, nil
+		}
+		if p.s[p.pos] != ',' {
+			return nil, p.errorf("unexpected character %q", p.s[p.pos])
+		}
+		p.pos++
+		p.skipSpaces()
+	}
+}
+
+func (p *cssParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("at position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *cssParser) skipSpaces() bool {
+	start := p.pos
+	for p.pos < len(p.s) && isCSSSpace(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func isCSSSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isCSSNameChar(c byte) bool {
+	return c == '_' || c == '-' || c >= 0x80 ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (p *cssParser) parseName() string {
+	start := p.pos
+	for p.pos < len(p.s) && isCSSNameChar(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *cssParser) parseComplex() (cssComplex, error) {
+	var complex cssComplex
+
+	for {
+		compound, err := p.parseCompound()
+		if err != nil {
+			return complex, err
+		}
+		complex.compounds = append(complex.compounds, compound)
+
+		hasSpace := p.skipSpaces()
+
+		if p.pos == len(p.s) || p.s[p.pos] == ',' {
+			return complex, nil
+		}
+
+		switch c := p.s[p.pos]; c {
+		case '>', '+', '~':
+			p.pos++
+			p.skipSpaces()
+			complex.combinators = append(complex.combinators, c)
+
+		default:
+			if !hasSpace {
+				return complex, p.errorf("unexpected character %q", c)
+			}
+			complex.combinators = append(complex.combinators, ' ')
+		}
+	}
+}
+
+func (p *cssParser) parseCompound() (cssCompound, error) {
+	var compound cssCompound
+
+	if p.pos < len(p.s) && p.s[p.pos] == '*' {
+		p.pos++
+		compound.tag = "*"
+	} else {
+		compound.tag = strings.ToLower(p.parseName())
+	}
+
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+
+		switch c {
+		case '#', '.':
+			p.pos++
+			name := p.parseName()
+			if name == "" {
+				return compound, p.errorf("expected name after %q", c)
+			}
+			compound.filters = append(compound.filters, cssFilter{kind: c, name: name})
+
+		case '[':
+			p.pos++
+			filter, err := p.parseAttribute()
+			if err != nil {
+				return compound, err
+			}
+			compound.filters = append(compound.filters, filter)
+
+		case ':':
+			p.pos++
+			filter, err := p.parsePseudo()
+			if err != nil {
+				return compound, err
+			}
+			compound.filters = append(compound.filters, filter)
+
+		default:
+			if compound.tag == "" && len(compound.filters) == 0 {
+				return compound, p.errorf("unexpected character %q", c)
+			}
+			return compound, nil
+		}
+	}
+
+	if compound.tag == "" && len(compound.filters) == 0 {
+		return compound, p.errorf("unexpected end of selector")
+	}
+
+	return compound, nil
+}
+
+func (p *cssParser) parseAttribute() (cssFilter, error) {
+	filter := cssFilter{kind: '['}
+
+	p.skipSpaces()
+	filter.name = strings.ToLower(p.parseName())
+	if filter.name == "" {
+		return filter, p.errorf("expected attribute name")
+	}
+	p.skipSpaces()
+
+	if p.pos < len(p.s) && p.s[p.pos] == ']' {
+		p.pos++
+		return filter, nil
+	}
+
+	for _, op := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+		if strings.HasPrefix(p.s[p.pos:], op) {
+			filter.op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if filter.op == "" {
+		return filter, p.errorf("expected attribute operator")
+	}
+	p.skipSpaces()
+
+	if p.pos < len(p.s) && (p.s[p.pos] == '\'' || p.s[p.pos] == '"') {
+		quote := p.s[p.pos]
+		end := strings.IndexByte(p.s[p.pos+1:], quote)
+		if end < 0 {
+			return filter, p.errorf("unterminated string")
+		}
+		filter.value = p.s[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+	} else {
+		filter.value = p.parseName()
+		if filter.value == "" {
+			return filter, p.errorf("expected attribute value")
+		}
+	}
+	p.skipSpaces()
+
+	if p.pos == len(p.s) || p.s[p.pos] != ']' {
+		return filter, p.errorf("expected ']'")
+	}
+	p.pos++
+
+	return filter, nil
+}
+
+func (p *cssParser) parsePseudo() (cssFilter, error) {
+	filter := cssFilter{kind: ':'}
+
+	filter.name = strings.ToLower(p.parseName())
+
+	switch filter.name {
+	case "first-child", "last-child", "only-child", "empty":
+		return filter, nil
+
+	case "nth-child":
+		if p.pos == len(p.s) || p.s[p.pos] != '(' {
+			return filter, p.errorf("expected '('")
+		}
+		end := strings.IndexByte(p.s[p.pos:], ')')
+		if end < 0 {
+			return filter, p.errorf("expected ')'")
+		}
+		arg := strings.TrimSpace(p.s[p.pos+1 : p.pos+end])
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 {
+			return filter, p.errorf("unsupported :nth-child() argument %q", arg)
+		}
+		filter.index = n
+		p.pos += end + 1
+		return filter, nil
+	}
+
+	return filter, p.errorf("unsupported pseudo-class :%s", filter.name)
+}
+
+// returns element descendants of given nodes matching selector,
+// in document order and without duplicates
+func (sel cssSelector) selectFrom(nodes []*html.Node) []*html.Node {
+	seen := map[*html.Node]bool{}
+
+	var result []*html.Node
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && !seen[c] && sel.matches(c) {
+				seen[c] = true
+				result = append(result, c)
+			}
+			walk(c)
+		}
+	}
+
+	for _, n := range nodes {
+		walk(n)
+	}
+
+	return result
+}
+
+func (sel cssSelector) matches(n *html.Node) bool {
+	for _, complex := range sel {
+		if complex.matches(n, len(complex.compounds)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches node against compounds[0:i+1], right to left
+func (c *cssComplex) matches(n *html.Node, i int) bool {
+	if !c.compounds[i].matches(n) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	switch c.combinators[i-1] {
+	case ' ':
+		for p := n.Parent; p != nil && p.Type == html.ElementNode; p = p.Parent {
+			if c.matches(p, i-1) {
+				return true
+			}
+		}
+
+	case '>':
+		if p := n.Parent; p != nil && p.Type == html.ElementNode {
+			return c.matches(p, i-1)
+		}
+
+	case '+':
+		if s := prevElementSibling(n); s != nil {
+			return c.matches(s, i-1)
+		}
+
+	case '~':
+		for s := prevElementSibling(n); s != nil; s = prevElementSibling(s) {
+			if c.matches(s, i-1) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (c *cssCompound) matches(n *html.Node) bool {
+	if c.tag != "" && c.tag != "*" && c.tag != n.Data {
+		return false
+	}
+
+	for _, f := range c.filters {
+		if !f.matches(n) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *cssFilter) matches(n *html.Node) bool {
+	switch f.kind {
+	case '#':
+		id, _ := htmlAttr(n, "id")
+		return id == f.name
+
+	case '.':
+		class, _ := htmlAttr(n, "class")
+		for _, c := range strings.Fields(class) {
+			if c == f.name {
+				return true
+			}
+		}
+		return false
+
+	case '[':
+		value, ok := htmlAttr(n, f.name)
+		if !ok {
+			return false
+		}
+		switch f.op {
+		case "":
+			return true
+		case "=":
+			return value == f.value
+		case "~=":
+			for _, v := range strings.Fields(value) {
+				if v == f.value {
+					return true
+				}
+			}
+			return false
+		case "|=":
+			return value == f.value || strings.HasPrefix(value, f.value+"-")
+		case "^=":
+			return f.value != "" && strings.HasPrefix(value, f.value)
+		case "$=":
+			return f.value != "" && strings.HasSuffix(value, f.value)
+		case "*=":
+			return f.value != "" && strings.Contains(value, f.value)
+		}
+
+	case ':':
+		switch f.name {
+		case "first-child":
+			return prevElementSibling(n) == nil
+		case "last-child":
+			return nextElementSibling(n) == nil
+		case "only-child":
+			return prevElementSibling(n) == nil && nextElementSibling(n) == nil
+		case "nth-child":
+			pos := 1
+			for s := prevElementSibling(n); s != nil; s = prevElementSibling(s) {
+				pos++
+			}
+			return pos == f.index
+		case "empty":
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				if c.Type == html.ElementNode || c.Type == html.TextNode {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	return false
+}
+
+func prevElementSibling(n *html.Node) *html.Node {
+	for s := n.PrevSibling; s != nil; s = s.PrevSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+func nextElementSibling(n *html.Node) *html.Node {
+	for s := n.NextSibling; s != nil; s = s.NextSibling {
+		if s.Type == html.ElementNode {
+			return s
+		}
+	}
+	return nil
+}
+
+func htmlAttr(n *html.Node, name string) (string, bool) {
+	for _, attr := range n.Attr {
+		if attr.Namespace == "" && attr.Key == name {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+func htmlText(n *html.Node) string {
+	var buf strings.Builder
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			buf.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+
+	return buf.String()
+}
@@ -0,0 +1,110 @@
+package httpexpect
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+)
+
+const cssTestDocument = `<!DOCTYPE html>
+<html lang="en-US">
+<head><title> Shop </title></head>
+<body>
+  <div id="main" class="page wide">
+    <h1>Products</h1>
+    <ul class="items">
+      <li data-sku="A-1" class="item">apple</li>
+      <li data-sku="B-2" class="item sale">banana</li>
+      <li data-sku="C-3" class="item">cherry</li>
+    </ul>
+    <p></p>
+    <a href="https://example.com/doc.pdf" title="user guide">guide</a>
+    <form><input type="text" name="q"><input type="submit"></form>
+  </div>
+</body>
+</html>`
+
+func TestCSSParseErrors(t *testing.T) {
+	cases := []string{
+		"",
+		"div,",
+		"div >",
+		"#",
+		".",
+		"[",
+		"[href",
+		"[href=]",
+		"[href=\"x]",
+		"[href!=x]",
+		":hover",
+		":nth-child(2n+1)",
+		":nth-child(1",
+		"div!",
+	}
+
+	for _, sel := range cases {
+		t.Run(sel, func(t *testing.T) {
+			_, err := parseCSS(sel)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCSSSelect(t *testing.T) {
+	root, err := html.Parse(strings.NewReader(cssTestDocument))
+	require.NoError(t, err)
+
+	cases := []struct {
+		sel    string
+		result []string
+	}{
+		{"li", []string{"apple", "banana", "cherry"}},
+		{"LI", []string{"apple", "banana", "cherry"}},
+		{"li.sale", []string{"banana"}},
+		{".item.sale", []string{"banana"}},
+		{"#main > h1", []string{"Products"}},
+		{"body > h1", nil},
+		{"body h1", []string{"Products"}},
+		{"div#main.wide ul li:first-child", []string{"apple"}},
+		{"li:last-child", []string{"cherry"}},
+		{"li:nth-child(2)", []string{"banana"}},
+		{"h1:only-child", nil},
+		{"title:only-child", []string{" Shop "}},
+		{"p:empty", []string{""}},
+		{"li + li", []string{"banana", "cherry"}},
+		{"h1 ~ a", []string{"guide"}},
+		{"h1 + a", nil},
+		{"[data-sku]", []string{"apple", "banana", "cherry"}},
+		{"[data-sku=B-2]", []string{"banana"}},
+		{`[data-sku="C-3"]`, []string{"cherry"}},
+		{"[class~=sale]", []string{"banana"}},
+		{"[lang|=en]", []string{""}},
+		{"[href^=https]", []string{"guide"}},
+		{"[href$='.pdf']", []string{"guide"}},
+		{"[title*=guide]", []string{"guide"}},
+		{"input[type=submit]", []string{""}},
+		{"h1, li.sale", []string{"Products", "banana"}},
+		{"*.sale", []string{"banana"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.sel, func(t *testing.T) {
+			sel, err := parseCSS(tc.sel)
+			require.NoError(t, err)
+
+			var result []string
+			for _, node := range sel.selectFrom([]*html.Node{root}) {
+				if node.Data == "html" {
+					result = append(result, "")
+					continue
+				}
+				result = append(result, htmlText(node))
+			}
+
+			assert.Equal(t, tc.result, result)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package httpexpect
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// Dialer is used to establish network connections.
+//
+// net.Dialer and UnixDialer implement this interface.
+//
+// Dialer is used only if Client is *http.Client with nil Transport or
+// *http.Transport, and if WebsocketDialer is *websocket.Dialer.
+type Dialer interface {
+	// DialContext connects to the address on the named network.
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// UnixDialer is a Dialer that connects to Unix domain socket,
+// regardless of requested network and address.
+//
+// Example:
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:  "http://localhost",
+//	    Reporter: httpexpect.NewAssertReporter(t),
+//	    Dialer:   httpexpect.NewUnixDialer("/var/run/app.sock"),
+//	})
+type UnixDialer struct {
+	// Path to socket.
+	Path string
+}
+
+// NewUnixDialer returns a new UnixDialer given a socket path.
+func NewUnixDialer(path string) *UnixDialer {
+	return &UnixDialer{Path: path}
+}
+
+// DialContext implements Dialer.DialContext.
+func (d *UnixDialer) DialContext(
+	ctx context.Context, _, _ string,
+) (net.Conn, error) {
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, "unix", d.Path)
+}
+
+const unixScheme = "unix://"
+
+// if url has form "unix:///path/to/socket", returns "http://localhost"
+// and socket path
+func parseUnixURL(baseURL string) (string, string, bool) {
+	if !strings.HasPrefix(baseURL, unixScheme) {
+		return baseURL, "", false
+	}
+
+	return "http://localhost", strings.TrimPrefix(baseURL, unixScheme), true
+}
+
+// returns copy of client that establishes connections using given dialer;
+// client is returned as is if it doesn't dial by itself
+func dialerClient(client Client, dialer Dialer) Client {
+	clientCopy, transport := cloneTransport(client)
+	if transport == nil {
+		return client
+	}
+
+	transport.DialContext = dialer.DialContext
+
+	return clientCopy
+}
+
+// returns copy of websocket dialer that establishes connections using
+// given dialer; websocket dialer is returned as is if it already has custom
+// dial function, e.g. if it was created by NewWebsocketDialer
+func dialerWebsocketDialer(wsDialer WebsocketDialer, dialer Dialer) WebsocketDialer {
+	gorillaDialer, ok := wsDialer.(*websocket.Dialer)
+	if !ok || gorillaDialer.NetDial != nil || gorillaDialer.NetDialContext != nil {
+		return wsDialer
+	}
+
+	return websocketNetDialer(gorillaDialer, dialer)
+}
+
+// returns copy of websocket dialer that establishes connections using
+// given dialer, replacing its dial function, if any
+func websocketNetDialer(wsDialer *websocket.Dialer, dialer Dialer) *websocket.Dialer {
+	dialerCopy := *wsDialer
+	dialerCopy.NetDial = nil
+	dialerCopy.NetDialContext = dialer.DialContext
+
+	return &dialerCopy
+}
+
+// returns copy of client with copy of its transport, which can be modified
+// without affecting original client; returns nil transport if client is not
+// *http.Client or its transport is not *http.Transport
+func cloneTransport(client Client) (*http.Client, *http.Transport) {
+	httpClient, ok := client.(*http.Client)
+	if !ok {
+		return nil, nil
+	}
+
+	var transport *http.Transport
+
+	switch t := httpClient.Transport.(type) {
+	case nil:
+		defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+		if !ok {
+			return nil, nil
+		}
+		transport = defaultTransport.Clone()
+
+	case *http.Transport:
+		transport = t.Clone()
+
+	default:
+		return nil, nil
+	}
+
+	clientCopy := *httpClient
+	clientCopy.Transport = transport
+
+	return &clientCopy, transport
+}
@@ -0,0 +1,114 @@
+package httpexpect
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialerUnixURL(t *testing.T) {
+	baseURL, socket, ok := parseUnixURL("unix:///var/run/app.sock")
+	assert.True(t, ok)
+	assert.Equal(t, "http://localhost", baseURL)
+	assert.Equal(t, "/var/run/app.sock", socket)
+
+	baseURL, socket, ok = parseUnixURL("http://example.com")
+	assert.False(t, ok)
+	assert.Equal(t, "http://example.com", baseURL)
+	assert.Equal(t, "", socket)
+}
+
+func TestDialerClient(t *testing.T) {
+	dialer := &net.Dialer{}
+
+	client := &http.Client{}
+	assert.True(t, client != dialerClient(client, dialer))
+	assert.Nil(t, client.Transport)
+
+	binderClient := &http.Client{Transport: NewBinder(http.NotFoundHandler())}
+	assert.Same(t, binderClient, dialerClient(binderClient, dialer))
+
+	mock := &mockClient{}
+	assert.Same(t, mock, dialerClient(mock, dialer))
+}
+
+func TestDialerWebsocketDialer(t *testing.T) {
+	dialer := &net.Dialer{}
+
+	wsDialer := &websocket.Dialer{}
+	wsDialerCopy := dialerWebsocketDialer(wsDialer, dialer)
+	assert.True(t, wsDialer != wsDialerCopy)
+	assert.Nil(t, wsDialer.NetDialContext)
+	assert.NotNil(t, wsDialerCopy.(*websocket.Dialer).NetDialContext)
+
+	handlerDialer := NewWebsocketDialer(http.NotFoundHandler())
+	assert.Same(t, handlerDialer, dialerWebsocketDialer(handlerDialer, dialer))
+
+	netDialer := &websocket.Dialer{
+		NetDial: func(network, addr string) (net.Conn, error) {
+			return nil, errors.New("not expected")
+		},
+	}
+	netDialerCopy := websocketNetDialer(netDialer, dialer)
+	assert.NotNil(t, netDialer.NetDial)
+	assert.Nil(t, netDialerCopy.NetDial)
+	assert.NotNil(t, netDialerCopy.NetDialContext)
+}
+
+func TestDialerRequest(t *testing.T) {
+	config := Config{
+		Client:   &mockClient{},
+		Reporter: newMockReporter(t),
+	}
+
+	req := NewRequest(config, "GET", "/path").WithDialer(nil)
+	req.chain.assertFailed(t)
+
+	req = NewRequest(config, "GET", "/path").WithDialer(&net.Dialer{})
+	req.chain.assertOK(t)
+
+	req.Expect().chain.assertFailed(t)
+
+	t.Run("websocket", func(t *testing.T) {
+		dialer := &mockDialer{}
+
+		config := Config{
+			Client:          &mockClient{},
+			WebsocketDialer: NewWebsocketDialer(http.NotFoundHandler()),
+			Reporter:        newMockReporter(t),
+		}
+
+		req := NewRequest(config, "GET", "ws://example.com/path").
+			WithWebsocketUpgrade().
+			WithDialer(dialer)
+		req.chain.assertOK(t)
+
+		req.Expect().chain.assertFailed(t)
+		assert.Equal(t, 1, dialer.dialed)
+	})
+
+	t.Run("websocket custom dialer", func(t *testing.T) {
+		config := Config{
+			Client:          &mockClient{},
+			WebsocketDialer: &mockCustomWebsocketDialer{},
+			Reporter:        newMockReporter(t),
+		}
+
+		assertionHandler := &mockAssertionHandler{}
+
+		req := NewRequest(config, "GET", "ws://example.com/path").
+			WithWebsocketUpgrade().
+			WithDialer(&mockDialer{})
+		req.chain.handler = assertionHandler
+
+		req.Expect().chain.assertFailed(t)
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertUsage, assertionHandler.failure.Type)
+	})
+}
@@ -0,0 +1,119 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writes raw response and closes connection
+func createRawHandler(raw string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			panic(err)
+		}
+		defer conn.Close()
+
+		_, _ = buf.WriteString(raw)
+		_ = buf.Flush()
+	})
+}
+
+func TestE2EBodyDefects(t *testing.T) {
+	cases := []struct {
+		name  string
+		raw   string
+		error string
+	}{
+		{
+			name: "truncated",
+			raw: "HTTP/1.1 200 OK\r\n" +
+				"Content-Length: 10\r\n" +
+				"\r\n" +
+				"hello",
+			error: "connection closed after 5 of 10 bytes declared in Content-Length",
+		},
+		{
+			name: "unterminated chunked",
+			raw: "HTTP/1.1 200 OK\r\n" +
+				"Transfer-Encoding: chunked\r\n" +
+				"\r\n" +
+				"5\r\nhello\r\n",
+			error: "chunked body is not terminated with last chunk",
+		},
+		{
+			name: "malformed chunked",
+			raw: "HTTP/1.1 200 OK\r\n" +
+				"Transfer-Encoding: chunked\r\n" +
+				"\r\n" +
+				"zz\r\nhello\r\n0\r\n\r\n",
+			error: "malformed chunked encoding",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(createRawHandler(tc.raw))
+			defer server.Close()
+
+			handler := &mockAssertionHandler{}
+
+			e := WithConfig(Config{
+				BaseURL:          server.URL,
+				AssertionHandler: handler,
+			})
+
+			e.GET("/").Expect().chain.assertFailed(t)
+
+			require.NotNil(t, handler.failure)
+			assert.Equal(t, AssertValid, handler.failure.Type)
+
+			if assert.Equal(t, 3, len(handler.failure.Errors)) {
+				assert.Equal(t, tc.error, handler.failure.Errors[1].Error())
+			}
+		})
+	}
+}
+
+func TestE2EBodyValid(t *testing.T) {
+	raw := "HTTP/1.1 200 OK\r\n" +
+		"Transfer-Encoding: chunked\r\n" +
+		"\r\n" +
+		"5\r\nhello\r\n0\r\n\r\n"
+
+	server := httptest.NewServer(createRawHandler(raw))
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	})
+
+	resp := e.GET("/").Expect()
+
+	resp.chain.assertOK(t)
+	resp.Body().Equal("hello")
+	resp.TransferEncoding("chunked")
+
+	resp.chain.assertOK(t)
+}
+
+func TestE2EBodyHead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "100")
+			w.WriteHeader(http.StatusOK)
+		}))
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	})
+
+	e.HEAD("/").Expect().chain.assertOK(t)
+}
@@ -2,10 +2,15 @@ package httpexpect
 
 import (
 	"bufio"
+	"errors"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
@@ -97,3 +102,114 @@ func TestE2EChunkedBinderFast(t *testing.T) {
 		},
 	}))
 }
+
+func createStreamHandler(received chan<- string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TransferEncoding) != 1 || r.TransferEncoding[0] != "chunked" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var total int
+		buf := make([]byte, 64)
+
+		for {
+			n, err := r.Body.Read(buf)
+			if n > 0 {
+				total += n
+				received <- string(buf[:n])
+			}
+			if err != nil {
+				break
+			}
+		}
+
+		close(received)
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(strconv.Itoa(total)))
+	})
+
+	return mux
+}
+
+func testStreamHandler(t *testing.T, e *Expect, received <-chan string) {
+	pr, pw := io.Pipe()
+
+	go func() {
+		// next chunk is written only after the server received the previous
+		// one, which would deadlock if the body was buffered before sending
+		for _, chunk := range []string{"foo", "bar", "baz"} {
+			_, _ = pw.Write([]byte(chunk))
+			select {
+			case <-received:
+			case <-time.After(10 * time.Second):
+				_ = pw.CloseWithError(errors.New("chunk was not received"))
+				return
+			}
+		}
+		_ = pw.Close()
+	}()
+
+	resp := e.PUT("/").
+		WithHeader("Content-Type", "application/octet-stream").
+		WithBodyStream(pr).
+		Expect()
+
+	resp.Status(http.StatusOK).
+		Body().Equal("9")
+
+	progress := resp.UploadProgress()
+
+	progress.Progressive()
+	progress.Chunks().Equal(3)
+	progress.Bytes().Equal(9)
+}
+
+func TestE2EChunkedStreamLive(t *testing.T) {
+	received := make(chan string)
+
+	server := httptest.NewServer(createStreamHandler(received))
+	defer server.Close()
+
+	testStreamHandler(t, Default(t, server.URL), received)
+}
+
+func TestE2EChunkedStreamBinderStandard(t *testing.T) {
+	received := make(chan string)
+
+	testStreamHandler(t, WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Reporter: NewAssertReporter(t),
+		Client: &http.Client{
+			Transport: NewBinder(createStreamHandler(received)),
+		},
+	}), received)
+}
+
+func TestE2EChunkedTrailersLive(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+
+		w.Header().Set("Trailer", "X-Received")
+		_, _ = w.Write(b)
+
+		w.Header().Set("X-Received", r.Trailer.Get("X-Checksum"))
+	})
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	e := New(t, server.URL)
+
+	resp := e.PUT("/").
+		WithText("hello").
+		WithTrailer("X-Checksum", "123").
+		Expect()
+
+	resp.Status(http.StatusOK)
+	resp.Body().Equal("hello")
+	resp.Trailer("X-Received").Equal("123")
+}
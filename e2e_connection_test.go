@@ -0,0 +1,127 @@
+package httpexpect
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func createConnectionHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/keepalive", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux.HandleFunc("/close", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Connection", "close")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return mux
+}
+
+func TestE2EConnectionReuse(t *testing.T) {
+	server := httptest.NewServer(createConnectionHandler())
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Client: &http.Client{
+			Transport: &http.Transport{},
+		},
+	})
+
+	serverAddr := server.Listener.Addr().String()
+
+	resp1 := e.GET("/keepalive").Expect()
+
+	resp1.KeepAlive()
+	resp1.ConnectionReused().False()
+	resp1.Connection().RemoteAddr().Equal(serverAddr)
+
+	resp2 := e.GET("/keepalive").Expect()
+
+	resp2.KeepAlive()
+	resp2.ConnectionReused().True()
+	resp2.Connection().WasIdle().True()
+	resp2.Connection().LocalAddr().
+		Equal(resp1.Connection().LocalAddr().Raw())
+
+	resp3 := e.GET("/close").Expect()
+
+	resp3.NotKeepAlive()
+	resp3.ConnectionReused().True()
+
+	resp4 := e.GET("/keepalive").Expect()
+
+	resp4.ConnectionReused().False()
+	resp4.Connection().LocalAddr().
+		NotEqual(resp1.Connection().LocalAddr().Raw())
+}
+
+func TestE2EConnectionNoKeepAlive(t *testing.T) {
+	server := httptest.NewServer(createConnectionHandler())
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Client: &http.Client{
+			Transport: &http.Transport{
+				DisableKeepAlives: true,
+			},
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		e.GET("/keepalive").Expect().
+			ConnectionReused().False()
+	}
+}
+
+func TestE2EConnectionTLSResumption(t *testing.T) {
+	server := httptest.NewTLSServer(createConnectionHandler())
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: true,
+					ClientSessionCache: tls.NewLRUClientSessionCache(0),
+				},
+				DisableKeepAlives: true,
+			},
+		},
+	})
+
+	e.GET("/keepalive").Expect().
+		Connection().TLSResumed().False()
+
+	e.GET("/keepalive").Expect().
+		Connection().TLSResumed().True()
+}
+
+func TestE2EConnectionBinder(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Reporter: reporter,
+		Client: &http.Client{
+			Transport: NewBinder(createConnectionHandler()),
+		},
+	})
+
+	resp := e.GET("/close").Expect()
+
+	resp.NotKeepAlive()
+	resp.chain.assertOK(t)
+
+	resp.Connection().chain.assertFailed(t)
+}
@@ -393,3 +393,75 @@ func TestContextPerRequestWithTimeoutCancelledByContext(t *testing.T) {
 	// expected error should occur
 	assert.True(t, suppressor.expErrorOccurred)
 }
+
+func TestContextPerRequestWithDeadline(t *testing.T) {
+	handler := newWaitHandler(0)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// config with context deadline expected error
+	suppressor := newExpErrorSuppressor(t,
+		func(err error) bool {
+			return strings.Contains(err.Error(), "context deadline exceeded")
+		})
+	e := WithConfig(Config{
+		BaseURL:          server.URL,
+		AssertionHandler: suppressor,
+	})
+
+	// deadline is shared by all attempts, so retries don't help
+	e.GET("/WaitForPerRequestTimeout").
+		WithDeadline(time.Now().Add(TimeOutDuration)).
+		WithMaxRetries(3).
+		WithRetryDelay(0, 0).
+		WithRetryPolicy(RetryAllErrors).
+		Expect()
+
+	// expected error should occur
+	assert.True(t, suppressor.expErrorOccurred)
+}
+
+func TestContextPerRequestCancelledDuringRetryDelay(t *testing.T) {
+	maxRetries := 3
+	retriesToFail := 3
+	handler := newWaitHandler(retriesToFail)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TimeOutDuration)
+	defer cancel()
+
+	// config with context deadline expected error
+	suppressor := newExpErrorSuppressor(t,
+		func(err error) bool {
+			return strings.Contains(err.Error(), "context deadline exceeded")
+		})
+	e := WithConfig(Config{
+		BaseURL:          server.URL,
+		AssertionHandler: suppressor,
+	})
+
+	start := time.Now()
+
+	e.GET("/WaitForContextCancellation").
+		WithContext(ctx).
+		WithMaxRetries(maxRetries).
+		WithRetryDelay(time.Hour, time.Hour).
+		Expect()
+
+	// waiting for retry should be interrupted by context
+	assert.True(t, time.Since(start) < time.Hour)
+	assert.True(t, suppressor.expErrorOccurred)
+	assert.Equal(t, 1, handler.GetCallCount())
+}
+
+func TestContextPerRequestWithZeroDeadline(t *testing.T) {
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Reporter: newMockReporter(t),
+	})
+
+	e.GET("/").WithDeadline(time.Time{}).chain.assertFailed(t)
+}
@@ -0,0 +1,93 @@
+package httpexpect
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func createProtocolHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	})
+}
+
+func newProtocolClient() *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				// accept any certificate; for testing only!
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+}
+
+func TestE2EProtocolTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(createProtocolHandler())
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Client:   newProtocolClient(),
+	})
+
+	e.GET("/").
+		WithProtocol(ProtocolHTTP1).
+		Expect().
+		Status(http.StatusOK).
+		ProtocolVersion().Equal("HTTP/1.1")
+
+	e.GET("/").
+		WithProtocol(ProtocolHTTP2).
+		Expect().
+		Status(http.StatusOK).
+		ProtocolVersion().Equal("HTTP/2.0")
+}
+
+func TestE2EProtocolTLSNoHTTP2(t *testing.T) {
+	server := httptest.NewTLSServer(createProtocolHandler())
+	defer server.Close()
+
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: reporter,
+		Client:   newProtocolClient(),
+		Protocol: ProtocolHTTP2,
+	})
+
+	e.GET("/").
+		Expect().
+		chain.assertFailed(t)
+}
+
+func TestE2EProtocolH2C(t *testing.T) {
+	server := httptest.NewServer(h2c.NewHandler(createProtocolHandler(), &http2.Server{}))
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Protocol: ProtocolH2C,
+	})
+
+	e.GET("/").
+		Expect().
+		Status(http.StatusOK).
+		ProtocolVersion().Equal("HTTP/2.0")
+
+	e.GET("/").
+		WithProtocol(ProtocolAuto).
+		Expect().
+		Status(http.StatusOK).
+		ProtocolVersion().Equal("HTTP/1.1")
+}
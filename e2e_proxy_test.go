@@ -0,0 +1,398 @@
+package httpexpect
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+type proxyTestServer struct {
+	listener net.Listener
+	requests int32
+}
+
+func (p *proxyTestServer) url(scheme string) string {
+	return scheme + "://user:pass@" + p.listener.Addr().String()
+}
+
+func (p *proxyTestServer) count() int {
+	return int(atomic.LoadInt32(&p.requests))
+}
+
+func (p *proxyTestServer) close() {
+	_ = p.listener.Close()
+}
+
+func newProxyTestServer(t *testing.T) *proxyTestServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	return &proxyTestServer{listener: listener}
+}
+
+func proxyTestPipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+
+	go func() {
+		_, _ = io.Copy(a, b)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(b, a)
+		done <- struct{}{}
+	}()
+
+	<-done
+
+	_ = a.Close()
+	_ = b.Close()
+}
+
+// HTTP proxy with basic auth, supporting absolute-form requests and CONNECT
+func createHTTPProxy(t *testing.T) *proxyTestServer {
+	proxy := newProxyTestServer(t)
+
+	transport := &http.Transport{}
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth := r.Header.Get("Proxy-Authorization")
+			if auth != "Basic dXNlcjpwYXNz" {
+				w.WriteHeader(http.StatusProxyAuthRequired)
+				return
+			}
+
+			atomic.AddInt32(&proxy.requests, 1)
+
+			if r.Method == http.MethodConnect {
+				upstream, err := net.Dial("tcp", r.Host)
+				if err != nil {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err != nil {
+					_ = upstream.Close()
+					return
+				}
+
+				_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+
+				proxyTestPipe(conn, upstream)
+				return
+			}
+
+			outReq := r.Clone(r.Context())
+			outReq.RequestURI = ""
+			outReq.Header.Del("Proxy-Authorization")
+
+			resp, err := transport.RoundTrip(outReq)
+			if err != nil {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			defer resp.Body.Close()
+
+			for k, v := range resp.Header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(resp.StatusCode)
+			_, _ = io.Copy(w, resp.Body)
+		}),
+	}
+
+	go func() {
+		_ = server.Serve(proxy.listener)
+	}()
+
+	t.Cleanup(func() {
+		_ = server.Close()
+		transport.CloseIdleConnections()
+	})
+
+	return proxy
+}
+
+// SOCKS5 proxy with username/password auth, supporting CONNECT command
+func createSOCKS5Proxy(t *testing.T) *proxyTestServer {
+	proxy := newProxyTestServer(t)
+
+	handshake := func(conn net.Conn) (string, error) {
+		r := bufio.NewReader(conn)
+
+		var hdr [2]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return "", err
+		}
+		if _, err := io.ReadFull(r, make([]byte, hdr[1])); err != nil {
+			return "", err
+		}
+
+		// username/password auth
+		if _, err := conn.Write([]byte{5, 2}); err != nil {
+			return "", err
+		}
+
+		readString := func() (string, error) {
+			n, err := r.ReadByte()
+			if err != nil {
+				return "", err
+			}
+			buf := make([]byte, n)
+			_, err = io.ReadFull(r, buf)
+			return string(buf), err
+		}
+
+		if _, err := r.ReadByte(); err != nil {
+			return "", err
+		}
+		user, err := readString()
+		if err != nil {
+			return "", err
+		}
+		pass, err := readString()
+		if err != nil {
+			return "", err
+		}
+		if user != "user" || pass != "pass" {
+			_, _ = conn.Write([]byte{1, 1})
+			return "", errors.New("bad credentials")
+		}
+		if _, err := conn.Write([]byte{1, 0}); err != nil {
+			return "", err
+		}
+
+		var req [4]byte
+		if _, err := io.ReadFull(r, req[:]); err != nil {
+			return "", err
+		}
+
+		var host string
+		switch req[3] {
+		case 1:
+			ip := make([]byte, 4)
+			if _, err := io.ReadFull(r, ip); err != nil {
+				return "", err
+			}
+			host = net.IP(ip).String()
+		case 3:
+			if host, err = readString(); err != nil {
+				return "", err
+			}
+		case 4:
+			ip := make([]byte, 16)
+			if _, err := io.ReadFull(r, ip); err != nil {
+				return "", err
+			}
+			host = net.IP(ip).String()
+		default:
+			return "", errors.New("bad address type")
+		}
+
+		var port [2]byte
+		if _, err := io.ReadFull(r, port[:]); err != nil {
+			return "", err
+		}
+
+		return net.JoinHostPort(host,
+			strconv.Itoa(int(binary.BigEndian.Uint16(port[:])))), nil
+	}
+
+	go func() {
+		for {
+			conn, err := proxy.listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				addr, err := handshake(conn)
+				if err != nil {
+					_ = conn.Close()
+					return
+				}
+
+				upstream, err := net.Dial("tcp", addr)
+				if err != nil {
+					_, _ = conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+					_ = conn.Close()
+					return
+				}
+
+				atomic.AddInt32(&proxy.requests, 1)
+
+				_, _ = conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+
+				proxyTestPipe(conn, upstream)
+			}()
+		}
+	}()
+
+	t.Cleanup(proxy.close)
+
+	return proxy
+}
+
+func proxyTestHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello " + r.URL.Path))
+	})
+}
+
+func TestE2EProxyHTTP(t *testing.T) {
+	server := httptest.NewServer(proxyTestHandler())
+	defer server.Close()
+
+	proxy := createHTTPProxy(t)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+	})
+
+	e.GET("/path").
+		WithProxy(proxy.url("http")).
+		Expect().
+		ViaProxy().
+		Status(http.StatusOK).
+		Body().Equal("hello /path")
+
+	require.Equal(t, 1, proxy.count())
+
+	e.GET("/path").
+		Expect().
+		NotViaProxy().
+		Status(http.StatusOK)
+
+	require.Equal(t, 1, proxy.count())
+
+	e = WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Proxy:    proxy.url("http"),
+	})
+
+	e.GET("/path").
+		Expect().
+		ViaProxy().
+		Status(http.StatusOK).
+		Body().Equal("hello /path")
+
+	require.Equal(t, 2, proxy.count())
+}
+
+func TestE2EProxyHTTPAuthFailure(t *testing.T) {
+	server := httptest.NewServer(proxyTestHandler())
+	defer server.Close()
+
+	proxy := createHTTPProxy(t)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Proxy:    "http://" + proxy.listener.Addr().String(),
+	})
+
+	e.GET("/path").
+		Expect().
+		ViaProxy().
+		Status(http.StatusProxyAuthRequired)
+
+	require.Equal(t, 0, proxy.count())
+}
+
+func TestE2EProxyConnect(t *testing.T) {
+	server := httptest.NewTLSServer(proxyTestHandler())
+	defer server.Close()
+
+	proxy := createHTTPProxy(t)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Client:   server.Client(),
+		Proxy:    proxy.url("http"),
+	})
+
+	e.GET("/path").
+		Expect().
+		ViaProxy().
+		Status(http.StatusOK).
+		Body().Equal("hello /path")
+
+	require.Equal(t, 1, proxy.count())
+}
+
+func TestE2EProxySOCKS5(t *testing.T) {
+	server := httptest.NewServer(proxyTestHandler())
+	defer server.Close()
+
+	proxy := createSOCKS5Proxy(t)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Proxy:    proxy.url("socks5"),
+	})
+
+	e.GET("/path").
+		Expect().
+		ViaProxy().
+		Status(http.StatusOK).
+		Body().Equal("hello /path")
+
+	require.Equal(t, 1, proxy.count())
+}
+
+func TestE2EProxyWebsocket(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			upgrader := websocket.Upgrader{}
+			conn, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			mt, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			_ = conn.WriteMessage(mt, msg)
+		}))
+	defer server.Close()
+
+	proxy := createHTTPProxy(t)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Proxy:    proxy.url("http"),
+	})
+
+	resp := e.GET("/ws").
+		WithWebsocketUpgrade().
+		Expect().
+		ViaProxy().
+		Status(http.StatusSwitchingProtocols)
+
+	ws := resp.Websocket()
+	defer ws.Disconnect()
+
+	ws.WriteText("hi").
+		Expect().
+		TextMessage().Body().Equal("hi")
+
+	require.Equal(t, 1, proxy.count())
+}
@@ -0,0 +1,78 @@
+package httpexpect
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func createSSEHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+
+		_, _ = w.Write([]byte("retry: 3000\n\n"))
+		w.(http.Flusher).Flush()
+
+		for i := 1; i <= 3; i++ {
+			_, _ = fmt.Fprintf(w, "event: tick\nid: %d\ndata: {\"n\":%d}\n\n", i, i)
+			w.(http.Flusher).Flush()
+		}
+
+		// keep connection open until client disconnects
+		<-r.Context().Done()
+	})
+
+	mux.HandleFunc("/plain", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("data: 1\n\n"))
+	})
+
+	return mux
+}
+
+func TestE2ESSE(t *testing.T) {
+	server := httptest.NewServer(createSSEHandler())
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	})
+
+	t.Run("events", func(t *testing.T) {
+		sse := e.GET("/events").
+			WithResponseStream().
+			Expect().
+			Status(http.StatusOK).
+			SSE()
+		defer sse.Disconnect()
+
+		sse.WithReadTimeout(5 * time.Second)
+
+		event := sse.NextEvent()
+		event.Name().Equal("tick")
+		event.ID().Equal("1")
+		event.JSON().Object().ValueEqual("n", 1)
+		event.chain.assertOK(t)
+
+		events := sse.NextEvents(2, 5*time.Second)
+		events[0].ID().Equal("2").chain.assertOK(t)
+		events[1].ID().Equal("3").chain.assertOK(t)
+
+		sse.WithReadTimeout(50 * time.Millisecond)
+		sse.NextEvent().chain.assertFailed(t)
+	})
+
+	t.Run("content type", func(t *testing.T) {
+		resp := e.GET("/plain").Expect()
+
+		resp.SSE().chain.assertFailed(t)
+		resp.chain.assertFailed(t)
+	})
+}
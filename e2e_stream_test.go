@@ -0,0 +1,127 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func createResponseStreamHandler(next <-chan struct{}) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/stream", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+
+		_, _ = w.Write([]byte(`{"event":1}` + "\n"))
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-next:
+		case <-r.Context().Done():
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"event":2}` + "\n"))
+		w.(http.Flusher).Flush()
+
+		<-r.Context().Done()
+	})
+
+	mux.HandleFunc("/static", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"event":1}` + "\n" + `{"event":2}` + "\n"))
+	})
+
+	return mux
+}
+
+func TestE2EStreamResponse(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(req *Request)
+	}{
+		{
+			name:  "default",
+			setup: func(req *Request) {},
+		},
+		{
+			name: "timeout",
+			setup: func(req *Request) {
+				req.WithTimeout(time.Minute)
+			},
+		},
+		{
+			name: "deadline",
+			setup: func(req *Request) {
+				req.WithDeadline(time.Now().Add(time.Minute))
+			},
+		},
+		{
+			name: "cache",
+			setup: func(req *Request) {
+				req.WithCache(NewMemoryCacheStore())
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			next := make(chan struct{})
+
+			server := httptest.NewServer(createResponseStreamHandler(next))
+			defer server.Close()
+
+			e := WithConfig(Config{
+				BaseURL:  server.URL,
+				Reporter: newMockReporter(t),
+				Printers: []Printer{
+					NewDebugPrinter(t, true),
+				},
+			})
+
+			req := e.GET("/stream").WithResponseStream()
+			tc.setup(req)
+
+			resp := req.Expect()
+			resp.Status(http.StatusOK).chain.assertOK(t)
+			resp.Body().Empty().chain.assertOK(t)
+
+			stream := resp.BodyStream()
+			defer stream.Close()
+
+			stream.NextChunk().Equal(`{"event":1}` + "\n").chain.assertOK(t)
+
+			close(next)
+
+			stream.EventuallyContains(`{"event":2}`, 5*time.Second)
+			stream.chain.assertOK(t)
+
+			stream.Close()
+			stream.chain.assertOK(t)
+		})
+	}
+}
+
+func TestE2EStreamBuffered(t *testing.T) {
+	server := httptest.NewServer(createResponseStreamHandler(nil))
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+	})
+
+	resp := e.GET("/static").Expect()
+	resp.Body().Contains(`{"event":2}`).chain.assertOK(t)
+
+	stream := resp.BodyStream()
+	defer stream.Close()
+
+	stream.EventuallyContains(`{"event":1}`, time.Second).chain.assertOK(t)
+	stream.EventuallyContains(`{"event":2}`, time.Second).chain.assertOK(t)
+
+	stream.NextChunk().Equal("\n").chain.assertOK(t)
+
+	stream.NextChunk().chain.assertFailed(t)
+}
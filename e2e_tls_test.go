@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
@@ -138,3 +139,91 @@ func TestE2EAutoTLSBinderFast(t *testing.T) {
 		})
 	}
 }
+
+func TestE2ETLSResolveAndHost(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host + " " + r.TLS.ServerName))
+	})
+
+	server := httptest.NewUnstartedServer(handler)
+	server.StartTLS()
+	defer server.Close()
+
+	addr := server.Listener.Addr().String()
+
+	newClient := func() *http.Client {
+		return &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					// accept any certificate; for testing only!
+					InsecureSkipVerify: true,
+				},
+			},
+		}
+	}
+
+	t.Run("resolve", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  "https://example.com:8443",
+			Reporter: NewAssertReporter(t),
+			Client:   newClient(),
+			Resolve: map[string]string{
+				"example.com:8443": addr,
+			},
+		})
+
+		e.GET("/").
+			Expect().
+			Status(http.StatusOK).
+			Body().Equal("example.com:8443 example.com")
+	})
+
+	t.Run("host", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: NewAssertReporter(t),
+			Client:   newClient(),
+		})
+
+		e.GET("/").
+			WithHost("example.com").
+			Expect().
+			Status(http.StatusOK).
+			Body().Equal("example.com example.com")
+	})
+}
+
+func TestE2ETLSState(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: NewAssertReporter(t),
+		Client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					// accept any certificate; for testing only!
+					InsecureSkipVerify: true,
+				},
+			},
+		},
+	})
+
+	state := e.GET("/").
+		Expect().
+		Status(http.StatusOK).
+		TLS()
+
+	state.Version().Ge(tls.VersionTLS12)
+	state.CipherSuiteName().NotEmpty()
+	state.NotExpiredWithin(30 * 24 * time.Hour)
+
+	cert := state.PeerCertificate()
+
+	cert.Subject().Contains("O=Acme Co")
+	cert.DNSNames().Contains("example.com")
+}
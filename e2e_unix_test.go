@@ -0,0 +1,79 @@
+package httpexpect
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createUnixServer(t *testing.T) (*httptest.Server, string) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	socket := filepath.Join(dir, "app.sock")
+
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.Host + r.URL.Path))
+		}))
+
+	server.Listener = listener
+	server.Start()
+
+	return server, socket
+}
+
+func TestE2EUnixBaseURL(t *testing.T) {
+	server, socket := createUnixServer(t)
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  "unix://" + socket,
+		Reporter: NewAssertReporter(t),
+	})
+
+	e.GET("/path").
+		Expect().
+		Status(http.StatusOK).
+		Body().Equal("localhost/path")
+}
+
+func TestE2EUnixDialer(t *testing.T) {
+	server, socket := createUnixServer(t)
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Reporter: NewAssertReporter(t),
+	})
+
+	e.GET("/path").
+		WithDialer(NewUnixDialer(socket)).
+		Expect().
+		Status(http.StatusOK).
+		Body().Equal("example.com/path")
+
+	e = WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Reporter: NewAssertReporter(t),
+		Dialer:   NewUnixDialer(socket),
+	})
+
+	e.GET("/path").
+		Expect().
+		Status(http.StatusOK).
+		Body().Equal("example.com/path")
+}
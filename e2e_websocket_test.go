@@ -1,8 +1,10 @@
 package httpexpect
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -461,3 +463,249 @@ func TestE2EWebsocketInvalid(t *testing.T) {
 		resp.chain.assertFailed(t)
 	})
 }
+
+func TestE2EWebsocketPingPong(t *testing.T) {
+	server := httptest.NewServer(createWebsocketHandler(wsHandlerOpts{}))
+	defer server.Close()
+
+	e := Default(t, server.URL)
+
+	ws := e.GET("/test").WithWebsocketUpgrade().
+		Expect().
+		Status(http.StatusSwitchingProtocols).
+		Websocket()
+	defer ws.Disconnect()
+
+	ws.Ping([]byte("hello")).
+		ExpectPong(5 * time.Second).
+		Body().Equal("hello")
+
+	ws.WriteText("hi").
+		Expect().
+		TextMessage().Body().Equal("hi")
+
+	ws.CloseWithText("bye").
+		ExpectClose(websocket.CloseNormalClosure)
+}
+
+func TestE2EWebsocketSubprotocols(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			upgrader := &websocket.Upgrader{
+				Subprotocols: []string{"v2.chat"},
+			}
+			c, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer c.Close()
+			_, _, _ = c.ReadMessage()
+		}))
+	defer server.Close()
+
+	e := Default(t, server.URL)
+
+	t.Run("negotiated", func(t *testing.T) {
+		ws := e.GET("/").WithWebsocketUpgrade().
+			WithWebsocketSubprotocols("v3.chat", "v2.chat").
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		defer ws.Disconnect()
+
+		ws.Subprotocol().Equal("v2.chat")
+		ws.Header("Sec-WebSocket-Protocol").Equal("v2.chat")
+		ws.Headers().ContainsKey("Sec-Websocket-Accept")
+		ws.Extensions().Empty()
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		ws := e.GET("/").WithWebsocketUpgrade().
+			WithWebsocketSubprotocols("v1.chat").
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		defer ws.Disconnect()
+
+		ws.Subprotocol().Empty()
+		ws.Headers().NotContainsKey("Sec-Websocket-Protocol")
+	})
+}
+
+func TestE2EWebsocketReconnect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			c, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer c.Close()
+
+			// send events after resume token, or first event
+			seq := 1
+			if token := r.URL.Query().Get("resume"); token != "" {
+				fmt.Sscan(token, &seq)
+				seq++
+			}
+
+			_ = c.WriteJSON(map[string]interface{}{
+				"seq":    seq,
+				"cursor": fmt.Sprint(seq),
+				"header": r.Header.Get("X-Resume"),
+			})
+
+			_, _, _ = c.ReadMessage()
+		}))
+	defer server.Close()
+
+	e := Default(t, server.URL)
+
+	ws := e.GET("/").WithWebsocketUpgrade().
+		Expect().
+		Status(http.StatusSwitchingProtocols).
+		Websocket()
+	defer ws.Disconnect()
+
+	ws.Expect().JSON().Object().ValueEqual("seq", 1)
+
+	ws.CloseAndReconnect(WebsocketReconnectOpts{
+		CloseCode:       websocket.CloseGoingAway,
+		ResumeTokenPath: "$.cursor",
+		ResumeParam:     "resume",
+	})
+
+	ws.Expect().JSON().Object().ValueEqual("seq", 2)
+
+	ws.CloseAndReconnect(WebsocketReconnectOpts{
+		ResumeToken:  "5",
+		ResumeParam:  "resume",
+		ResumeHeader: "X-Resume",
+	})
+
+	ws.Expect().JSON().Object().
+		ValueEqual("seq", 6).
+		ValueEqual("header", "5")
+
+	ws.CloseAndReconnect()
+
+	ws.Expect().JSON().Object().ValueEqual("seq", 1)
+
+	ws.Transcript().Length().Equal(5)
+}
+
+func TestE2EWebsocketCompression(t *testing.T) {
+	payload := strings.Repeat("compressible ", 100)
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			upgrader := &websocket.Upgrader{
+				EnableCompression: true,
+			}
+			c, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer c.Close()
+
+			_ = c.WriteMessage(websocket.TextMessage, []byte(payload))
+
+			_, _, _ = c.ReadMessage()
+		}))
+	defer server.Close()
+
+	e := Default(t, server.URL)
+
+	t.Run("enabled", func(t *testing.T) {
+		ws := e.GET("/").WithWebsocketUpgrade().
+			WithWebsocketCompression(true).
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		defer ws.Disconnect()
+
+		ws.Extensions().Contains("permessage-deflate")
+
+		msg := ws.Expect()
+		msg.Body().Equal(payload)
+		msg.Compressed()
+		msg.WireSize().Lt(len(payload))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		ws := e.GET("/").WithWebsocketUpgrade().
+			WithWebsocketCompression(false).
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		defer ws.Disconnect()
+
+		ws.Extensions().Empty()
+
+		msg := ws.Expect()
+		msg.Body().Equal(payload)
+		msg.NotCompressed()
+		msg.WireSize().Equal(len(payload))
+	})
+
+	t.Run("after reconnect", func(t *testing.T) {
+		ws := e.GET("/").WithWebsocketUpgrade().
+			WithWebsocketCompression(true).
+			Expect().
+			Status(http.StatusSwitchingProtocols).
+			Websocket()
+		defer ws.Disconnect()
+
+		ws.Expect().Compressed()
+
+		ws.CloseAndReconnect()
+		ws.Expect().Compressed()
+	})
+}
+
+func TestE2EWebsocketFragments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			upgrader := &websocket.Upgrader{}
+			c, err := upgrader.Upgrade(w, r, nil)
+			if err != nil {
+				return
+			}
+			defer c.Close()
+
+			// echo message, sending it back in two frames
+			_, msg, err := c.ReadMessage()
+			if err != nil || len(msg) < 2 {
+				return
+			}
+
+			var frames []byte
+			frames = append(frames, websocket.TextMessage, byte(2))
+			frames = append(frames, msg[:2]...)
+			frames = append(frames, 0x80, byte(len(msg)-2))
+			frames = append(frames, msg[2:]...)
+
+			_, _ = c.UnderlyingConn().Write(frames)
+
+			_, _, _ = c.ReadMessage()
+		}))
+	defer server.Close()
+
+	e := Default(t, server.URL)
+
+	ws := e.GET("/").WithWebsocketUpgrade().
+		Expect().
+		Status(http.StatusSwitchingProtocols).
+		Websocket()
+	defer ws.Disconnect()
+
+	ws.WriteFragmented(websocket.TextMessage, []byte("hel"), []byte("lo"))
+	ws.chain.assertOK(t)
+
+	msg := ws.Expect()
+	msg.TextMessage().Body().Equal("hello")
+	msg.Fragmented()
+	msg.FragmentCount().Equal(2)
+	msg.Fragment(0).Equal([]byte("he"))
+	msg.Fragment(1).Equal([]byte("llo"))
+	msg.chain.assertOK(t)
+}
@@ -1,20 +1,41 @@
 package httpexpect
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
 // Environment provides a container for arbitrary data shared between tests.
 //
+// Environment may be split into scopes (see Scope), saved to disk and
+// loaded back (see Save and Load), and filled with values captured from
+// responses (see Response.StoreJSON and Response.StoreHeader).
+//
+// Values are protected by a mutex, so different scopes of the same
+// environment, as well as Response.StoreJSON and Response.StoreHeader,
+// may be used from concurrent subtests.
+//
 // Example:
 //
 //	env := NewEnvironment(t)
 //	env.Put("key", "value")
 //	value := env.GetString("key")
 type Environment struct {
-	chain *chain
-	data  map[string]interface{}
+	chain  *chain
+	store  *environmentStore
+	prefix string
+}
+
+// data shared by environment and all its scopes
+type environmentStore struct {
+	mu   sync.RWMutex
+	data map[string]interface{}
 }
 
 // NewEnvironment returns a new Environment given a reporter.
@@ -31,7 +52,9 @@ func NewEnvironment(reporter Reporter) *Environment {
 func newEnvironment(parent *chain) *Environment {
 	return &Environment{
 		chain: parent.clone(),
-		data:  make(map[string]interface{}),
+		store: &environmentStore{
+			data: make(map[string]interface{}),
+		},
 	}
 }
 
@@ -46,7 +69,10 @@ func (e *Environment) Put(key string, value interface{}) {
 	e.chain.enter("Put(%q)", key)
 	defer e.chain.leave()
 
-	e.data[key] = value
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	e.store.data[e.prefix+key] = value
 }
 
 // Has returns true if value exists in the environment.
@@ -60,7 +86,10 @@ func (e *Environment) Has(key string) bool {
 	e.chain.enter("Has(%q)", key)
 	defer e.chain.leave()
 
-	_, ok := e.data[key]
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+
+	_, ok := e.store.data[e.prefix+key]
 	return ok
 }
 
@@ -116,6 +145,7 @@ func (e *Environment) GetBool(key string) bool {
 //
 // If value does not exist, or is not signed or unsigned integer that can be
 // represented as int without overflow, reports failure and returns zero.
+// Integer json.Number, like one stored by Response.StoreJSON, is accepted.
 //
 // Example:
 //
@@ -170,6 +200,21 @@ func (e *Environment) GetInt(key string) int {
 		casted = int(num)
 		ok = (uint64(num) <= maxInt)
 
+	case json.Number:
+		n, err := num.Int64()
+		if err != nil {
+			e.chain.fail(AssertionFailure{
+				Type:   AssertType,
+				Actual: &AssertionValue{value},
+				Errors: []error{
+					errors.New("expected: signed or unsigned integer"),
+				},
+			})
+			return 0
+		}
+		casted = int(n)
+		ok = (n >= minInt) && (n <= maxInt)
+
 	default:
 		e.chain.fail(AssertionFailure{
 			Type:   AssertType,
@@ -200,7 +245,8 @@ func (e *Environment) GetInt(key string) int {
 // GetFloat returns value stored in the environment, casted to float64.
 //
 // If value does not exist, or is not floating point value, reports failure
-// and returns zero value.
+// and returns zero value. json.Number, like one stored by Response.StoreJSON,
+// is accepted.
 //
 // Example:
 //
@@ -223,6 +269,21 @@ func (e *Environment) GetFloat(key string) float64 {
 	case float64:
 		casted = num
 
+	case json.Number:
+		f, err := num.Float64()
+		if err != nil {
+			e.chain.fail(AssertionFailure{
+				Type:   AssertType,
+				Actual: &AssertionValue{value},
+				Errors: []error{
+					errors.New("expected: float32 or float64"),
+					err,
+				},
+			})
+			return 0
+		}
+		casted = f
+
 	default:
 		e.chain.fail(AssertionFailure{
 			Type:   AssertType,
@@ -364,13 +425,278 @@ func (e *Environment) GetTime(key string) time.Time {
 	return casted
 }
 
+// GetObject returns value stored in the environment, casted to
+// map[string]interface{}.
+//
+// If value is a struct or a map, it is converted to map[string]interface{}
+// in the same way as JSON values, i.e. using json.Marshal.
+//
+// If value does not exist, or is not an object, reports failure and
+// returns nil.
+//
+// Example:
+//
+//	resp.StoreJSON("$.user", env, "user")
+//	user := env.GetObject("user")
+func (e *Environment) GetObject(key string) map[string]interface{} {
+	e.chain.enter("GetObject(%q)", key)
+	defer e.chain.leave()
+
+	value, ok := e.getValue(key)
+	if !ok {
+		return nil
+	}
+
+	if casted, ok := value.(map[string]interface{}); ok {
+		return casted
+	}
+
+	data, err := json.Marshal(value)
+	if err == nil {
+		var casted map[string]interface{}
+		if err := json.Unmarshal(data, &casted); err == nil && casted != nil {
+			return casted
+		}
+	}
+
+	e.chain.fail(AssertionFailure{
+		Type:   AssertType,
+		Actual: &AssertionValue{value},
+		Errors: []error{
+			errors.New("expected: object value"),
+		},
+	})
+
+	return nil
+}
+
+// Delete removes value from the environment, if it exists.
+//
+// Example:
+//
+//	env.Delete("token")
+func (e *Environment) Delete(key string) {
+	e.chain.enter("Delete(%q)", key)
+	defer e.chain.leave()
+
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	delete(e.store.data, e.prefix+key)
+}
+
+// Keys returns sorted list of keys stored in the environment.
+//
+// For scoped environment, only keys of the scope are returned, without
+// scope prefix.
+//
+// Example:
+//
+//	for _, key := range env.Keys() {
+//	    ...
+//	}
+func (e *Environment) Keys() []string {
+	e.chain.enter("Keys()")
+	defer e.chain.leave()
+
+	return e.keys()
+}
+
+// Scope returns a view of the environment with all keys prefixed by name,
+// e.g. Scope("users").Put("id", 1) stores value with key "users.id".
+//
+// Scopes share data with parent environment, so values put into a scope
+// are visible from other views of the same environment. Scopes may be
+// nested. Scope is useful to isolate data of different suites or steps.
+//
+// Example:
+//
+//	users := e.Env().Scope("users")
+//	users.Put("id", 123)
+//
+//	id := e.Env().GetInt("users.id")
+func (e *Environment) Scope(name string) *Environment {
+	e.chain.enter("Scope(%q)", name)
+	defer e.chain.leave()
+
+	return &Environment{
+		chain:  e.chain.clone(),
+		store:  e.store,
+		prefix: e.prefix + name + ".",
+	}
+}
+
+// Save writes all values of the environment to a file in JSON format,
+// so that they can be loaded back by Load in another test run.
+//
+// Values of types supported by typed getters (bool, integers, floats,
+// string, []byte, time.Duration, time.Time, json.Number) are restored
+// with the same type. Values of other types are saved as JSON and restored
+// in the same form as values stored by Response.StoreJSON.
+//
+// For scoped environment, only values of the scope are saved, without
+// scope prefix.
+//
+// If values can't be encoded or file can't be written, reports failure.
+//
+// Example:
+//
+//	env.Save("testdata/env.json")
+func (e *Environment) Save(path string) {
+	e.chain.enter("Save(%q)", path)
+	defer e.chain.leave()
+
+	if e.chain.failed() {
+		return
+	}
+
+	entries := make(map[string]environmentEntry)
+
+	for _, key := range e.keys() {
+		e.store.mu.RLock()
+		value := e.store.data[e.prefix+key]
+		e.store.mu.RUnlock()
+
+		entry, err := encodeEnvironmentEntry(value)
+		if err != nil {
+			e.chain.fail(AssertionFailure{
+				Type:   AssertOperation,
+				Actual: &AssertionValue{value},
+				Errors: []error{
+					fmt.Errorf("failed to encode environment value %q", key),
+					err,
+				},
+			})
+			return
+		}
+
+		entries[key] = entry
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(path, append(data, '\n'), 0644)
+	}
+
+	if err != nil {
+		e.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to save environment"),
+				err,
+			},
+		})
+	}
+}
+
+// Load reads values from a file written by Save and puts them into the
+// environment, overwriting existing values with the same keys.
+//
+// For scoped environment, values are put into the scope.
+//
+// If file can't be read or decoded, reports failure.
+//
+// Example:
+//
+//	env.Load("testdata/env.json")
+func (e *Environment) Load(path string) {
+	e.chain.enter("Load(%q)", path)
+	defer e.chain.leave()
+
+	if e.chain.failed() {
+		return
+	}
+
+	var entries map[string]environmentEntry
+
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(data, &entries)
+	}
+
+	if err != nil {
+		e.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to load environment"),
+				err,
+			},
+		})
+		return
+	}
+
+	values := make(map[string]interface{}, len(entries))
+
+	for key, entry := range entries {
+		value, err := decodeEnvironmentEntry(entry)
+		if err != nil {
+			e.chain.fail(AssertionFailure{
+				Type: AssertOperation,
+				Errors: []error{
+					fmt.Errorf("failed to decode environment value %q", key),
+					err,
+				},
+			})
+			return
+		}
+		values[key] = value
+	}
+
+	e.store.mu.Lock()
+	defer e.store.mu.Unlock()
+
+	for key, value := range values {
+		e.store.data[e.prefix+key] = value
+	}
+}
+
+func (e *Environment) keys() []string {
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+
+	var keys []string
+	for key := range e.store.data {
+		if strings.HasPrefix(key, e.prefix) {
+			keys = append(keys, strings.TrimPrefix(key, e.prefix))
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// returns values of the scope, for failure reports
+func (e *Environment) scopeData() map[string]interface{} {
+	e.store.mu.RLock()
+	defer e.store.mu.RUnlock()
+
+	if e.prefix == "" {
+		data := make(map[string]interface{}, len(e.store.data))
+		for key, value := range e.store.data {
+			data[key] = value
+		}
+		return data
+	}
+
+	data := make(map[string]interface{})
+	for key, value := range e.store.data {
+		if strings.HasPrefix(key, e.prefix) {
+			data[strings.TrimPrefix(key, e.prefix)] = value
+		}
+	}
+	return data
+}
+
 func (e *Environment) getValue(key string) (interface{}, bool) {
-	v, ok := e.data[key]
+	e.store.mu.RLock()
+	v, ok := e.store.data[e.prefix+key]
+	e.store.mu.RUnlock()
 
 	if !ok {
 		e.chain.fail(AssertionFailure{
 			Type:     AssertContainsKey,
-			Actual:   &AssertionValue{e.data},
+			Actual:   &AssertionValue{e.scopeData()},
 			Expected: &AssertionValue{key},
 			Errors: []error{
 				errors.New("expected: environment contains key"),
@@ -381,3 +707,113 @@ func (e *Environment) getValue(key string) (interface{}, bool) {
 
 	return v, true
 }
+
+// value stored in file written by Environment.Save
+type environmentEntry struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+func encodeEnvironmentEntry(value interface{}) (environmentEntry, error) {
+	var typ string
+
+	switch v := value.(type) {
+	case bool:
+		typ = "bool"
+	case int, int8, int16, int32, int64:
+		typ = "int"
+	case uint, uint8, uint16, uint32, uint64:
+		typ = "uint"
+	case float32, float64:
+		typ = "float"
+	case json.Number:
+		typ = "number"
+		value = v.String()
+	case string:
+		typ = "string"
+	case []byte:
+		typ = "bytes"
+	case time.Duration:
+		typ = "duration"
+		value = v.String()
+	case time.Time:
+		typ = "time"
+		value = v.Format(time.RFC3339Nano)
+	default:
+		typ = "json"
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return environmentEntry{}, err
+	}
+
+	return environmentEntry{Type: typ, Value: data}, nil
+}
+
+func decodeEnvironmentEntry(entry environmentEntry) (interface{}, error) {
+	var (
+		value interface{}
+		err   error
+	)
+
+	switch entry.Type {
+	case "bool":
+		var v bool
+		err = json.Unmarshal(entry.Value, &v)
+		value = v
+	case "int":
+		var v int64
+		err = json.Unmarshal(entry.Value, &v)
+		value = v
+	case "uint":
+		var v uint64
+		err = json.Unmarshal(entry.Value, &v)
+		value = v
+	case "float":
+		var v float64
+		err = json.Unmarshal(entry.Value, &v)
+		value = v
+	case "number":
+		var v string
+		err = json.Unmarshal(entry.Value, &v)
+		value = json.Number(v)
+	case "string":
+		var v string
+		err = json.Unmarshal(entry.Value, &v)
+		value = v
+	case "bytes":
+		var v []byte
+		err = json.Unmarshal(entry.Value, &v)
+		value = v
+	case "duration":
+		var v string
+		if err = json.Unmarshal(entry.Value, &v); err == nil {
+			value, err = time.ParseDuration(v)
+		}
+	case "time":
+		var v string
+		if err = json.Unmarshal(entry.Value, &v); err == nil {
+			value, err = time.Parse(time.RFC3339Nano, v)
+		}
+	case "json":
+		value, err = decodeJSONNumbers(entry.Value)
+	default:
+		err = fmt.Errorf("unknown value type %q", entry.Type)
+	}
+
+	return value, err
+}
+
+// decodes JSON keeping numbers as json.Number
+func decodeJSONNumbers(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.UseNumber()
+
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
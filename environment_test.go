@@ -1,8 +1,13 @@
 package httpexpect
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"math"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -429,3 +434,234 @@ func TestEnvironmentTime(t *testing.T) {
 			})
 	}
 }
+
+func TestEnvironmentNumber(t *testing.T) {
+	env := newEnvironment(newMockChain(t))
+
+	env.Put("int", json.Number("123"))
+	env.Put("float", json.Number("1.5"))
+
+	assert.Equal(t, 123, env.GetInt("int"))
+	assert.Equal(t, 123.0, env.GetFloat("int"))
+	assert.Equal(t, 1.5, env.GetFloat("float"))
+	env.chain.assertOK(t)
+
+	assert.Equal(t, 0, env.GetInt("float"))
+	env.chain.assertFailed(t)
+	env.chain.reset()
+
+	env.Put("bad", json.Number("x"))
+
+	assert.Equal(t, 0.0, env.GetFloat("bad"))
+	env.chain.assertFailed(t)
+}
+
+func TestEnvironmentObject(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		put interface{}
+		get map[string]interface{}
+		ok  bool
+	}{
+		{
+			put: map[string]interface{}{"name": "john"},
+			get: map[string]interface{}{"name": "john"},
+			ok:  true,
+		},
+		{
+			put: user{Name: "john"},
+			get: map[string]interface{}{"name": "john"},
+			ok:  true,
+		},
+		{
+			put: map[string]int{"id": 1},
+			get: map[string]interface{}{"id": 1.0},
+			ok:  true,
+		},
+		{
+			put: []interface{}{"john"},
+			get: nil,
+			ok:  false,
+		},
+		{
+			put: "john",
+			get: nil,
+			ok:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%T-%v", tt.put, tt.put),
+			func(t *testing.T) {
+				env := newEnvironment(newMockChain(t))
+
+				env.Put("key", tt.put)
+				env.chain.assertOK(t)
+
+				val := env.GetObject("key")
+				assert.Equal(t, tt.get, val)
+
+				if tt.ok {
+					env.chain.assertOK(t)
+				} else {
+					env.chain.assertFailed(t)
+				}
+			})
+	}
+}
+
+func TestEnvironmentScope(t *testing.T) {
+	env := newEnvironment(newMockChain(t))
+
+	users := env.Scope("users")
+	admins := users.Scope("admins")
+
+	env.Put("id", 1)
+	users.Put("id", 2)
+	admins.Put("id", 3)
+	admins.Put("name", "root")
+
+	assert.Equal(t, 1, env.GetInt("id"))
+	assert.Equal(t, 2, users.GetInt("id"))
+	assert.Equal(t, 3, admins.GetInt("id"))
+
+	assert.Equal(t, 2, env.GetInt("users.id"))
+	assert.Equal(t, 3, users.GetInt("admins.id"))
+
+	assert.Equal(t,
+		[]string{"id", "users.admins.id", "users.admins.name", "users.id"},
+		env.Keys())
+	assert.Equal(t, []string{"admins.id", "admins.name", "id"}, users.Keys())
+	assert.Equal(t, []string{"id", "name"}, admins.Keys())
+
+	env.chain.assertOK(t)
+	users.chain.assertOK(t)
+	admins.chain.assertOK(t)
+
+	admins.Delete("name")
+	assert.False(t, admins.Has("name"))
+	assert.False(t, env.Has("users.admins.name"))
+
+	assert.Nil(t, users.Get("name"))
+	users.chain.assertFailed(t)
+	env.chain.assertOK(t)
+}
+
+func TestEnvironmentSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "env.json")
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+
+	env := newEnvironment(newMockChain(t))
+
+	env.Put("bool", true)
+	env.Put("int", int8(-5))
+	env.Put("uint", uint(5))
+	env.Put("float", 1.5)
+	env.Put("number", json.Number("10"))
+	env.Put("string", "str")
+	env.Put("bytes", []byte("bytes"))
+	env.Put("duration", 5*time.Second)
+	env.Put("time", now)
+	env.Put("object", map[string]interface{}{"id": 1})
+	env.Scope("scope").Put("key", "value")
+
+	env.Save(path)
+	env.chain.assertOK(t)
+
+	t.Run("load", func(t *testing.T) {
+		loaded := newEnvironment(newMockChain(t))
+
+		loaded.Load(path)
+		loaded.chain.assertOK(t)
+
+		assert.Equal(t, env.Keys(), loaded.Keys())
+
+		assert.Equal(t, true, loaded.GetBool("bool"))
+		assert.Equal(t, -5, loaded.GetInt("int"))
+		assert.Equal(t, 5, loaded.GetInt("uint"))
+		assert.Equal(t, 1.5, loaded.GetFloat("float"))
+		assert.Equal(t, 10, loaded.GetInt("number"))
+		assert.Equal(t, "str", loaded.GetString("string"))
+		assert.Equal(t, []byte("bytes"), loaded.GetBytes("bytes"))
+		assert.Equal(t, 5*time.Second, loaded.GetDuration("duration"))
+		assert.True(t, now.Equal(loaded.GetTime("time")))
+		assert.Equal(t, map[string]interface{}{"id": json.Number("1")},
+			loaded.GetObject("object"))
+		assert.Equal(t, "value", loaded.GetString("scope.key"))
+
+		loaded.chain.assertOK(t)
+	})
+
+	t.Run("scope", func(t *testing.T) {
+		scopePath := filepath.Join(dir, "scope.json")
+
+		env.Scope("scope").Save(scopePath)
+		env.chain.assertOK(t)
+
+		loaded := newEnvironment(newMockChain(t))
+
+		loaded.Scope("other").Load(scopePath)
+		loaded.chain.assertOK(t)
+
+		assert.Equal(t, []string{"other.key"}, loaded.Keys())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		bad := newEnvironment(newMockChain(t))
+
+		bad.Put("func", func() {})
+		bad.Save(filepath.Join(dir, "bad.json"))
+		bad.chain.assertFailed(t)
+
+		missing := newEnvironment(newMockChain(t))
+
+		missing.Load(filepath.Join(dir, "missing.json"))
+		missing.chain.assertFailed(t)
+
+		invalidPath := filepath.Join(dir, "invalid.json")
+		err := ioutil.WriteFile(invalidPath,
+			[]byte(`{"key": {"type": "foo", "value": 1}}`), 0644)
+		assert.NoError(t, err)
+
+		invalid := newEnvironment(newMockChain(t))
+
+		invalid.Load(invalidPath)
+		invalid.chain.assertFailed(t)
+	})
+}
+
+func TestEnvironmentConcurrent(t *testing.T) {
+	env := newEnvironment(newMockChain(t))
+
+	var scopes []*Environment
+	for i := 0; i < 10; i++ {
+		scopes = append(scopes, env.Scope(fmt.Sprint(i)))
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			scope := scopes[i]
+			scope.Put("key", i)
+			assert.Equal(t, i, scope.GetInt("key"))
+			assert.Equal(t, []string{"key"}, scope.Keys())
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 10, len(env.Keys()))
+}
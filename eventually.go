@@ -0,0 +1,278 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Eventually invokes given function repeatedly, with given interval, until
+// all assertions made inside it succeed, or until timeout expires.
+//
+// Function receives a copy of Expect instance, and should make all requests
+// and assertions using it. Failures inside function are not reported while
+// there is time for another attempt. If timeout expires, the last failure
+// is reported, together with the number of attempts.
+//
+// It is useful for eventually-consistent APIs and asynchronous jobs,
+// when you need to poll an endpoint until it reaches expected state.
+//
+// Example:
+//
+//	e := httpexpect.Default(t, "http://example.com")
+//
+//	e.POST("/jobs").Expect().Status(http.StatusAccepted)
+//
+//	e.Eventually(time.Second, time.Minute, func(e *httpexpect.Expect) {
+//	    e.GET("/jobs/{id}", id).
+//	        Expect().
+//	        Status(http.StatusOK).
+//	        JSON().Object().ValueEqual("status", "done")
+//	})
+func (e *Expect) Eventually(
+	interval, timeout time.Duration, fn func(e *Expect),
+) {
+	e.chain.enter("Eventually()")
+	defer e.chain.leave()
+
+	if fn == nil {
+		chain := e.chain.clone()
+		chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil argument"),
+			},
+		})
+		return
+	}
+
+	if interval <= 0 || timeout <= 0 {
+		chain := e.chain.clone()
+		chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{[]time.Duration{interval, timeout}},
+			Errors: []error{
+				errors.New("unexpected non-positive interval or timeout"),
+			},
+		})
+		return
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for attempt := 1; ; attempt++ {
+		handler := &eventuallyHandler{}
+
+		fn(e.withHandler(handler))
+
+		if handler.failure == nil {
+			return
+		}
+
+		if time.Now().Add(interval).After(deadline) {
+			handler.report(e.chain,
+				fmt.Errorf("condition not satisfied within %s after %d attempt(s)",
+					timeout, attempt))
+			return
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// returns copy of Expect that sends all assertion results to given handler
+func (e *Expect) withHandler(handler AssertionHandler) *Expect {
+	ret := e.clone()
+
+	ret.config.AssertionHandler = handler
+	ret.config.Environment = e.chain.getEnv()
+
+	ret.chain = e.chain.clone()
+	ret.chain.handler = handler
+	ret.chain.onFailure = nil
+	ret.chain.failCb = nil
+
+	return ret
+}
+
+// WithRetries invokes given function with response, and if an assertion
+// made inside it fails, re-sends the request and invokes function again
+// with the new response, up to n more times, with given delay between
+// attempts.
+//
+// Failures inside function are not reported while there are attempts left.
+// If all attempts fail, the last failure is reported, together with the
+// number of attempts. WithRetries returns the last received response.
+//
+// It is useful to mark individual known eventually-consistent reads as
+// tolerant, without wrapping the whole test into Expect.Eventually.
+//
+// Response should be created by Request.Expect. Requests with streamed body
+// and WebSocket requests can't be re-sent.
+//
+// Example:
+//
+//	e.GET("/users/{id}", id).
+//	    Expect().
+//	    Status(http.StatusOK).
+//	    WithRetries(3, time.Second, func(resp *httpexpect.Response) {
+//	        resp.JSON().Object().ValueEqual("status", "active")
+//	    })
+func (r *Response) WithRetries(
+	n int, delay time.Duration, fn func(resp *Response),
+) *Response {
+	r.chain.enter("WithRetries(%d)", n)
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	if fn == nil {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil argument"),
+			},
+		})
+		return r
+	}
+
+	if n < 0 || delay < 0 {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{[]interface{}{n, delay}},
+			Errors: []error{
+				errors.New("unexpected negative number of retries or delay"),
+			},
+		})
+		return r
+	}
+
+	req := r.chain.context.Request
+
+	if req == nil || req.httpReq == nil || req.streamer != "" || req.wsUpgrade {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected WithRetries() on response that can't be re-fetched"),
+			},
+		})
+		return r
+	}
+
+	resp := r
+	handler := &eventuallyHandler{}
+
+	for attempt := 1; ; attempt++ {
+		// if re-sending failed, handler already has the failure
+		fn(resp.withHandler(handler))
+
+		if handler.failure == nil {
+			return resp
+		}
+
+		if attempt > n {
+			failure := *handler.failure
+
+			failure.Errors = append([]error{
+				fmt.Errorf("assertion not satisfied after %d attempt(s)", attempt),
+				fmt.Errorf("last failed assertion: %s",
+					strings.Join(handler.context.Path, ".")),
+			}, failure.Errors...)
+
+			r.chain.fail(failure)
+
+			resp.chain.setFailed()
+			return resp
+		}
+
+		time.Sleep(delay)
+
+		handler = &eventuallyHandler{}
+
+		resp = r.refetch(req, attempt, handler)
+		if resp == nil {
+			r.chain.setFailed()
+			return r
+		}
+	}
+}
+
+// re-sends request of response; failures of request are sent to handler
+func (r *Response) refetch(
+	req *Request, attempt int, handler AssertionHandler,
+) *Response {
+	rc := req.repeatCopy(attempt)
+	if rc == nil {
+		return nil
+	}
+
+	rc.chain.context.Path = append(
+		append([]string(nil), r.chain.context.Path...), fmt.Sprintf("Attempt(%d)", attempt))
+
+	rc.chain.handler = handler
+	rc.chain.onFailure = nil
+	rc.chain.failCb = nil
+	rc.chain.soft = nil
+
+	resp := rc.expectCopy()
+
+	resp.chain.handler = r.chain.handler
+	resp.chain.onFailure = r.chain.onFailure
+	resp.chain.failCb = r.chain.failCb
+	resp.chain.soft = r.chain.soft
+
+	return resp
+}
+
+// returns copy of Response that sends all assertion results to given handler
+func (r *Response) withHandler(handler AssertionHandler) *Response {
+	ret := *r
+
+	ret.chain = r.chain.clone()
+	ret.chain.handler = handler
+	ret.chain.onFailure = nil
+	ret.chain.failCb = nil
+	ret.chain.soft = nil
+
+	return &ret
+}
+
+// remembers the first failure of a single attempt
+type eventuallyHandler struct {
+	context AssertionContext
+	failure *AssertionFailure
+}
+
+func (h *eventuallyHandler) Success(*AssertionContext) {
+}
+
+func (h *eventuallyHandler) Failure(
+	ctx *AssertionContext, failure *AssertionFailure,
+) {
+	if h.failure != nil {
+		return
+	}
+
+	h.context = *ctx
+	h.context.Path = append([]string(nil), ctx.Path...)
+
+	f := *failure
+	h.failure = &f
+}
+
+// reports remembered failure using a clone of given chain, with given error
+// prepended to failure errors; failure keeps context of the assertion that
+// produced it, and is subject to soft mode, severity, and fatal flag of chain
+func (h *eventuallyHandler) report(parent *chain, err error) {
+	failure := *h.failure
+
+	failure.IsFatal = false
+	failure.Errors = append([]error{err}, failure.Errors...)
+
+	chain := parent.clone()
+	chain.context = h.context
+	chain.fail(failure)
+}
@@ -0,0 +1,364 @@
+package httpexpect
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createEventuallyHandler(readyAfter int32) (http.Handler, *int32) {
+	var counter int32
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&counter, 1) >= readyAfter {
+			_, _ = w.Write([]byte(`{"status": "done"}`))
+		} else {
+			_, _ = w.Write([]byte(`{"status": "pending"}`))
+		}
+	})
+
+	return handler, &counter
+}
+
+func TestEventuallySuccess(t *testing.T) {
+	handler, counter := createEventuallyHandler(3)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: reporter,
+	})
+
+	e.Eventually(time.Millisecond, time.Minute, func(e *Expect) {
+		e.GET("/job").
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().ValueEqual("status", "done")
+	})
+
+	assert.False(t, reporter.reported)
+	assert.Equal(t, int32(3), atomic.LoadInt32(counter))
+
+	e.chain.assertOK(t)
+}
+
+func TestEventuallyTimeout(t *testing.T) {
+	handler, counter := createEventuallyHandler(1000)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	assertionHandler := &eventuallyHandler{}
+
+	e := WithConfig(Config{
+		BaseURL:          server.URL,
+		Reporter:         newMockReporter(t),
+		AssertionHandler: assertionHandler,
+	})
+
+	e.Eventually(10*time.Millisecond, 50*time.Millisecond, func(e *Expect) {
+		e.GET("/job").
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().ValueEqual("status", "done")
+	})
+
+	require.NotNil(t, assertionHandler.failure)
+	assert.Equal(t, AssertEqual, assertionHandler.failure.Type)
+	assert.True(t, assertionHandler.failure.IsFatal)
+	assert.Contains(t, assertionHandler.failure.Errors[0].Error(),
+		"condition not satisfied")
+
+	assert.Equal(t, "Eventually()", assertionHandler.context.Path[0])
+	assert.Contains(t, strings.Join(assertionHandler.context.Path, "."), "ValueEqual")
+	assert.NotNil(t, assertionHandler.context.Response)
+
+	n := atomic.LoadInt32(counter)
+	assert.True(t, n >= 1 && n <= 6)
+
+	// subsequent requests are not affected
+	e.chain.assertOK(t)
+}
+
+func TestEventuallyUsage(t *testing.T) {
+	cases := []struct {
+		name     string
+		interval time.Duration
+		timeout  time.Duration
+		fn       func(*Expect)
+	}{
+		{"nil func", time.Second, time.Second, nil},
+		{"zero interval", 0, time.Second, func(*Expect) {}},
+		{"negative timeout", time.Second, -1, func(*Expect) {}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter := newMockReporter(t)
+
+			e := WithConfig(Config{
+				Client:   &mockClient{},
+				Reporter: reporter,
+			})
+
+			e.Eventually(tc.interval, tc.timeout, tc.fn)
+
+			assert.True(t, reporter.reported)
+			e.chain.assertOK(t)
+		})
+	}
+}
+
+func TestEventuallyEnvironment(t *testing.T) {
+	e := WithConfig(Config{
+		Client:   &mockClient{},
+		Reporter: newMockReporter(t),
+	})
+
+	e.Env().Put("key", "value")
+
+	e.Eventually(time.Millisecond, time.Second, func(inner *Expect) {
+		inner.Env().Put("key2", inner.Env().GetString("key"))
+	})
+
+	assert.Equal(t, "value", e.Env().GetString("key2"))
+}
+
+func TestEventuallyHandlerReport(t *testing.T) {
+	newHandler := func() *eventuallyHandler {
+		handler := &eventuallyHandler{}
+
+		handler.Failure(&AssertionContext{
+			Path: []string{"Eventually()", "Status()"},
+		}, &AssertionFailure{
+			Type:    AssertEqual,
+			IsFatal: true,
+			Errors:  []error{errors.New("inner")},
+		})
+
+		return handler
+	}
+
+	t.Run("fatal", func(t *testing.T) {
+		assertionHandler := &mockAssertionHandler{}
+
+		parent := newMockChain(t)
+		parent.handler = assertionHandler
+
+		newHandler().report(parent, errors.New("outer"))
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.True(t, assertionHandler.failure.IsFatal)
+		assert.Equal(t, []error{errors.New("outer"), errors.New("inner")},
+			assertionHandler.failure.Errors)
+		assert.Equal(t, []string{"Eventually()", "Status()"},
+			assertionHandler.ctx.Path)
+
+		parent.assertOK(t)
+	})
+
+	t.Run("non-fatal", func(t *testing.T) {
+		assertionHandler := &mockAssertionHandler{}
+
+		parent := newMockChain(t)
+		parent.handler = assertionHandler
+		parent.setFatal(false)
+
+		newHandler().report(parent, errors.New("outer"))
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.False(t, assertionHandler.failure.IsFatal)
+	})
+
+	t.Run("soft", func(t *testing.T) {
+		assertionHandler := &mockAssertionHandler{}
+
+		parent := newMockChain(t)
+		parent.handler = assertionHandler
+
+		collector := parent.setSoft()
+
+		newHandler().report(parent, errors.New("outer"))
+
+		assert.Nil(t, assertionHandler.failure)
+		require.Equal(t, 1, len(collector.failures))
+		assert.Equal(t, []string{"Eventually()", "Status()"},
+			collector.failures[0].context.Path)
+	})
+}
+
+func TestResponseWithRetriesSuccess(t *testing.T) {
+	handler, counter := createEventuallyHandler(3)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: reporter,
+	})
+
+	resp := e.POST("/job").WithText("body").
+		Expect().
+		WithRetries(5, time.Millisecond, func(resp *Response) {
+			resp.JSON().Object().ValueEqual("status", "done")
+		})
+
+	assert.False(t, reporter.reported)
+	assert.Equal(t, int32(3), atomic.LoadInt32(counter))
+
+	resp.chain.assertOK(t)
+	resp.JSON().Object().ValueEqual("status", "done")
+
+	assert.Equal(t,
+		[]string{`Request("POST")`, "Expect()", "WithRetries(5)", "Attempt(2)"},
+		resp.chain.context.Path)
+
+	resp.chain.assertOK(t)
+	assert.False(t, reporter.reported)
+}
+
+func TestResponseWithRetriesExhausted(t *testing.T) {
+	handler, counter := createEventuallyHandler(1000)
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	assertionHandler := &eventuallyHandler{}
+
+	e := WithConfig(Config{
+		BaseURL:          server.URL,
+		AssertionHandler: assertionHandler,
+	})
+
+	orig := e.GET("/job").Expect()
+
+	resp := orig.WithRetries(2, time.Millisecond, func(resp *Response) {
+		resp.JSON().Object().ValueEqual("status", "done")
+	})
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(counter))
+
+	orig.chain.assertFailed(t)
+	resp.chain.assertFailed(t)
+
+	require.NotNil(t, assertionHandler.failure)
+	assert.Equal(t, AssertEqual, assertionHandler.failure.Type)
+	assert.True(t, assertionHandler.failure.IsFatal)
+
+	assert.Equal(t,
+		[]string{`Request("GET")`, "Expect()", "WithRetries(2)"},
+		assertionHandler.context.Path)
+
+	if assert.True(t, len(assertionHandler.failure.Errors) > 2) {
+		assert.Equal(t, "assertion not satisfied after 3 attempt(s)",
+			assertionHandler.failure.Errors[0].Error())
+		assert.Contains(t, assertionHandler.failure.Errors[1].Error(),
+			`WithRetries(2).Attempt(2).JSON().Object().ValueEqual("status")`)
+	}
+}
+
+type retriesClient struct {
+	calls int
+}
+
+func (c *retriesClient) Do(req *http.Request) (*http.Response, error) {
+	c.calls++
+	if c.calls > 1 {
+		return nil, errors.New("connection refused")
+	}
+	return &http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       http.NoBody,
+	}, nil
+}
+
+func TestResponseWithRetriesRequestFailure(t *testing.T) {
+	client := &retriesClient{}
+
+	assertionHandler := &eventuallyHandler{}
+
+	e := WithConfig(Config{
+		BaseURL:          "http://example.com",
+		Client:           client,
+		AssertionHandler: assertionHandler,
+	})
+
+	e.GET("/job").
+		Expect().
+		WithRetries(1, time.Millisecond, func(resp *Response) {
+			resp.Status(http.StatusOK)
+		})
+
+	assert.Equal(t, 2, client.calls)
+
+	require.NotNil(t, assertionHandler.failure)
+	assert.Equal(t, AssertOperation, assertionHandler.failure.Type)
+	assert.Contains(t, assertionHandler.failure.Errors[1].Error(),
+		"WithRetries(1).Attempt(1)")
+}
+
+func TestResponseWithRetriesUsage(t *testing.T) {
+	t.Run("nil func", func(t *testing.T) {
+		e := WithConfig(Config{
+			Client:   &mockClient{},
+			Reporter: newMockReporter(t),
+		})
+
+		resp := e.GET("/").Expect()
+		resp.WithRetries(1, 0, nil)
+
+		resp.chain.assertFailed(t)
+	})
+
+	t.Run("negative retries", func(t *testing.T) {
+		e := WithConfig(Config{
+			Client:   &mockClient{},
+			Reporter: newMockReporter(t),
+		})
+
+		resp := e.GET("/").Expect()
+		resp.WithRetries(-1, 0, func(*Response) {})
+
+		resp.chain.assertFailed(t)
+	})
+
+	t.Run("no request", func(t *testing.T) {
+		resp := NewResponse(newMockReporter(t), &http.Response{
+			StatusCode: http.StatusOK,
+		})
+
+		called := false
+		resp.WithRetries(1, 0, func(*Response) {
+			called = true
+		})
+
+		assert.False(t, called)
+		resp.chain.assertFailed(t)
+	})
+
+	t.Run("failed response", func(t *testing.T) {
+		resp := NewResponse(newMockReporter(t), &http.Response{
+			StatusCode: http.StatusOK,
+		})
+		resp.chain.setFailed()
+
+		resp.WithRetries(1, 0, func(*Response) {
+			t.Fatal("function should not be called")
+		})
+	})
+}
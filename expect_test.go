@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -76,9 +77,9 @@ func TestExpectBuilders(t *testing.T) {
 	assert.Equal(t, 2, len(reqs1))
 	assert.Equal(t, 1, len(reqs2))
 
-	assert.Equal(t, r1, reqs1[0])
-	assert.Equal(t, r2, reqs1[1])
-	assert.Equal(t, r1, reqs2[0])
+	assert.Same(t, r1, reqs1[0])
+	assert.Same(t, r2, reqs1[1])
+	assert.Same(t, r2, reqs2[0])
 }
 
 func TestExpectBuildersCopying(t *testing.T) {
@@ -137,6 +138,236 @@ func TestExpectBuildersCopying(t *testing.T) {
 	assert.Equal(t, 1, counter2b)
 }
 
+func TestExpectTemplate(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := NewAssertReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	e := WithConfig(config)
+
+	counter := 0
+
+	tmpl := NewRequestTemplate(func(r *Request) {
+		counter++
+	})
+
+	e1 := e.Template(tmpl)
+	e2 := e1.Template(tmpl.WithHeader("X-Foo", "foo"))
+
+	e.Request("METHOD", "/url")
+	assert.Equal(t, 0, counter)
+
+	e1.Request("METHOD", "/url").Expect()
+	assert.Equal(t, 1, counter)
+	assert.Equal(t, "", client.req.Header.Get("X-Foo"))
+
+	e2.Request("METHOD", "/url").Expect()
+	assert.Equal(t, 3, counter)
+	assert.Equal(t, "foo", client.req.Header.Get("X-Foo"))
+}
+
+func TestExpectAuth(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := NewAssertReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	e := WithConfig(config)
+
+	e.OAuth2(StaticTokenSource(&Token{AccessToken: "foo"})).
+		Request("METHOD", "/url").Expect()
+	assert.Equal(t, "Bearer foo", client.req.Header.Get("Authorization"))
+
+	e.JWT(map[string]interface{}{"sub": "foo"}, []byte("secret")).
+		Request("METHOD", "/url").Expect()
+	assert.True(t,
+		strings.HasPrefix(client.req.Header.Get("Authorization"), "Bearer ey"))
+
+	e.Auth(StaticCredentials(&Credentials{Token: "bar"})).
+		Request("METHOD", "/url").Expect()
+	assert.Equal(t, "Bearer bar", client.req.Header.Get("Authorization"))
+
+	e.Auth(StaticCredentials(&Credentials{Token: "bar"})).
+		Request("METHOD", "/url").WithBasicAuth("john", "secret").Expect()
+	assert.True(t,
+		strings.HasPrefix(client.req.Header.Get("Authorization"), "Basic "))
+
+	e.Auth(StaticCredentials(&Credentials{Token: "bar"})).
+		Request("METHOD", "/url").WithHeader("Authorization", "Bearer baz").Expect()
+	assert.Equal(t, "Bearer baz", client.req.Header.Get("Authorization"))
+
+	e.Request("METHOD", "/url").Expect()
+	assert.Equal(t, "", client.req.Header.Get("Authorization"))
+}
+
+func TestExpectResponseTransformers(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := NewAssertReporter(t)
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+	}
+
+	e := WithConfig(config)
+
+	var order []string
+
+	e1 := e.ResponseTransformer(func(r *http.Response) {
+		order = append(order, "e1")
+	})
+
+	e2 := e1.ResponseTransformer(func(r *http.Response) {
+		order = append(order, "e2")
+	})
+
+	e.Request("METHOD", "/url").Expect()
+	assert.Equal(t, []string(nil), order)
+
+	e1.Request("METHOD", "/url").Expect()
+	assert.Equal(t, []string{"e1"}, order)
+
+	order = nil
+
+	e2.Request("METHOD", "/url").
+		WithResponseTransformer(func(r *http.Response) {
+			order = append(order, "req")
+		}).
+		Expect()
+	assert.Equal(t, []string{"e1", "e2", "req"}, order)
+
+	order = nil
+
+	e3 := e2.ResponseTransformerPriority(-1, func(r *http.Response) {
+		order = append(order, "e3")
+	})
+
+	e3.Request("METHOD", "/url").
+		WithResponseTransformerPriority(-2, func(r *http.Response) {
+			order = append(order, "req")
+		}).
+		Expect()
+	assert.Equal(t, []string{"req", "e3", "e1", "e2"}, order)
+}
+
+func TestExpectConditionalGET(t *testing.T) {
+	newExpect := func(t *testing.T, handler http.HandlerFunc) *Expect {
+		return WithConfig(Config{
+			BaseURL:  "http://example.com",
+			Client:   &http.Client{Transport: NewBinder(handler)},
+			Reporter: newMockReporter(t),
+		})
+	}
+
+	lastModified := "Sat, 01 Jan 2022 00:00:00 GMT"
+
+	t.Run("etag", func(t *testing.T) {
+		e := newExpect(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			_, _ = w.Write([]byte("hello"))
+		})
+
+		resp := e.ConditionalGET("/path")
+		resp.chain.assertOK(t)
+		assert.Equal(t, http.StatusNotModified, resp.Raw().StatusCode)
+	})
+
+	t.Run("last-modified", func(t *testing.T) {
+		e := newExpect(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Last-Modified", lastModified)
+			if r.Header.Get("If-Modified-Since") == lastModified {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			_, _ = w.Write([]byte("hello"))
+		})
+
+		e.ConditionalGET("/path").chain.assertOK(t)
+	})
+
+	t.Run("no validators", func(t *testing.T) {
+		e := newExpect(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("hello"))
+		})
+
+		e.ConditionalGET("/path").chain.assertFailed(t)
+	})
+
+	t.Run("not modified ignored", func(t *testing.T) {
+		e := newExpect(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte("hello"))
+		})
+
+		e.ConditionalGET("/path").chain.assertFailed(t)
+	})
+
+	t.Run("etag mismatch", func(t *testing.T) {
+		e := newExpect(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") != "" {
+				w.Header().Set("ETag", `"v2"`)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte("hello"))
+		})
+
+		e.ConditionalGET("/path").chain.assertFailed(t)
+	})
+
+	t.Run("cache-control not repeated", func(t *testing.T) {
+		e := newExpect(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("Cache-Control", "max-age=60")
+			_, _ = w.Write([]byte("hello"))
+		})
+
+		e.ConditionalGET("/path").chain.assertFailed(t)
+	})
+
+	t.Run("weak etag and last-modified", func(t *testing.T) {
+		e := newExpect(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `W/"v1"`)
+			w.Header().Set("Last-Modified", lastModified)
+			if r.Header.Get("If-None-Match") == `W/"v1"` &&
+				r.Header.Get("If-Modified-Since") == lastModified {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			_, _ = w.Write([]byte("hello"))
+		})
+
+		e.ConditionalGET("/path").chain.assertOK(t)
+	})
+
+	t.Run("bad status", func(t *testing.T) {
+		e := newExpect(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		e.ConditionalGET("/path").chain.assertFailed(t)
+	})
+}
+
 func TestExpectMatchers(t *testing.T) {
 	client := &mockClient{}
 
@@ -180,6 +411,185 @@ func TestExpectMatchers(t *testing.T) {
 	assert.Equal(t, resp2, resps2[0])
 }
 
+func TestExpectConfigMatchers(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := NewAssertReporter(t)
+
+	var calls []string
+
+	config := Config{
+		Client:   client,
+		Reporter: reporter,
+		Matchers: []func(*Response){
+			func(r *Response) {
+				calls = append(calls, "config1")
+			},
+			func(r *Response) {
+				calls = append(calls, "config2")
+			},
+		},
+	}
+
+	e := WithConfig(config).Matcher(func(r *Response) {
+		calls = append(calls, "expect")
+	})
+
+	e.Request("METHOD", "/url").
+		WithMatcher(func(r *Response) {
+			calls = append(calls, "request")
+		}).
+		Expect()
+
+	assert.Equal(t, []string{"config1", "config2", "expect", "request"}, calls)
+
+	calls = nil
+
+	NewRequest(config, "METHOD", "/url").Expect()
+
+	assert.Equal(t, []string{"config1", "config2"}, calls)
+}
+
+func TestExpectConfigMatchersNil(t *testing.T) {
+	config := Config{
+		Client: &mockClient{},
+		Matchers: []func(*Response){
+			func(r *Response) {},
+			nil,
+		},
+	}
+
+	t.Run("with config", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		config := config
+		config.AssertionHandler = handler
+
+		e := WithConfig(config)
+
+		if assert.NotNil(t, handler.failure) {
+			assert.Equal(t, AssertUsage, handler.failure.Type)
+		}
+		e.chain.assertFailed(t)
+
+		handler.failure = nil
+
+		e.GET("/url").Expect().chain.assertFailed(t)
+		assert.Nil(t, handler.failure)
+	})
+
+	t.Run("clone", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		e := WithConfig(Config{
+			Client:           &mockClient{},
+			AssertionHandler: handler,
+		})
+
+		e.chain.assertOK(t)
+
+		e2 := e.Clone(Config{Matchers: config.Matchers})
+
+		if assert.NotNil(t, handler.failure) {
+			assert.Equal(t, AssertUsage, handler.failure.Type)
+		}
+		e2.chain.assertFailed(t)
+	})
+
+	t.Run("new request", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		config := config
+		config.AssertionHandler = handler
+		config.fillDefaults()
+
+		req := NewRequest(config, "GET", "/url")
+
+		if assert.NotNil(t, handler.failure) {
+			assert.Equal(t, AssertUsage, handler.failure.Type)
+		}
+		req.chain.assertFailed(t)
+
+		req.Expect().chain.assertFailed(t)
+	})
+}
+
+func TestExpectHooks(t *testing.T) {
+	client := &mockClient{
+		resp: http.Response{
+			StatusCode: http.StatusOK,
+		},
+	}
+
+	var calls []string
+
+	handler := &mockAssertionHandler{}
+
+	config := Config{
+		Client:           client,
+		AssertionHandler: handler,
+		Matchers: []func(*Response){
+			func(r *Response) {
+				calls = append(calls, "matcher")
+			},
+		},
+		BeforeRequest: func(req *http.Request) {
+			calls = append(calls, "before")
+			req.Header.Set("X-Trace-Id", "trace")
+		},
+		AfterResponse: func(req *http.Request, resp *Response) {
+			calls = append(calls, "after")
+			assert.Equal(t, "trace", req.Header.Get("X-Trace-Id"))
+			assert.NotNil(t, resp.Raw())
+		},
+		OnFailure: func(ctx *AssertionContext, failure *AssertionFailure) {
+			calls = append(calls, "failure")
+			assert.NotNil(t, ctx.Request)
+			assert.NotNil(t, ctx.Response)
+			assert.Nil(t, handler.failure)
+		},
+	}
+
+	e := WithConfig(config)
+
+	t.Run("success", func(t *testing.T) {
+		calls = nil
+
+		e.GET("/url").Expect().Status(http.StatusOK)
+
+		assert.Equal(t, []string{"before", "after", "matcher"}, calls)
+		assert.Equal(t, "trace", client.req.Header.Get("X-Trace-Id"))
+		assert.Nil(t, handler.failure)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		calls = nil
+
+		e.GET("/url").Expect().Status(http.StatusNotFound)
+
+		assert.Equal(t, []string{"before", "after", "matcher", "failure"}, calls)
+		assert.NotNil(t, handler.failure)
+	})
+
+	t.Run("repeat", func(t *testing.T) {
+		calls = nil
+		handler.failure = nil
+
+		e.GET("/url").Repeat(2)
+
+		assert.Equal(t,
+			[]string{"before", "after", "matcher", "before", "after", "matcher"}, calls)
+	})
+
+	t.Run("clone", func(t *testing.T) {
+		calls = nil
+
+		e.Clone(Config{TestName: "test"}).GET("/url").Expect()
+
+		assert.Equal(t, []string{"before", "after", "matcher"}, calls)
+	})
+}
+
 func TestExpectMatchersCopying(t *testing.T) {
 	client := &mockClient{}
 
@@ -236,6 +646,254 @@ func TestExpectMatchersCopying(t *testing.T) {
 	assert.Equal(t, 1, counter2b)
 }
 
+func TestExpectClone(t *testing.T) {
+	client := &mockClient{}
+
+	reporter := NewAssertReporter(t)
+
+	e := WithConfig(Config{
+		BaseURL:  "http://parent.com",
+		Client:   client,
+		Reporter: reporter,
+		Headers: http.Header{
+			"X-Parent": {"parent"},
+			"X-Common": {"parent"},
+		},
+	})
+
+	e.Env().Put("key", "value")
+
+	var built int
+	e = e.Builder(func(r *Request) {
+		built++
+	})
+
+	child := e.Clone(Config{
+		BaseURL: "http://child.com",
+		Headers: http.Header{
+			"x-common": {"child"},
+			"X-Child":  {"child"},
+		},
+	})
+
+	t.Run("overridden", func(t *testing.T) {
+		req := child.GET("/path")
+		req.chain.assertOK(t)
+
+		assert.Equal(t, "http://child.com", req.config.BaseURL)
+		assert.Equal(t, http.Header{
+			"X-Parent": {"parent"},
+			"X-Common": {"child"},
+			"X-Child":  {"child"},
+		}, req.httpReq.Header)
+	})
+
+	t.Run("inherited", func(t *testing.T) {
+		assert.Same(t, client, child.config.Client)
+		assert.Same(t, e.config.AssertionHandler, child.config.AssertionHandler)
+		assert.Same(t, e.Env(), child.Env())
+		assert.Equal(t, "value", child.Env().GetString("key"))
+
+		built = 0
+		child.GET("/path")
+		assert.Equal(t, 1, built)
+	})
+
+	t.Run("parent not affected", func(t *testing.T) {
+		req := e.GET("/path")
+		req.chain.assertOK(t)
+
+		assert.Equal(t, "http://parent.com", req.config.BaseURL)
+		assert.Equal(t, http.Header{
+			"X-Parent": {"parent"},
+			"X-Common": {"parent"},
+		}, req.httpReq.Header)
+	})
+
+	t.Run("builders copied", func(t *testing.T) {
+		var childBuilt int
+		child.Builder(func(r *Request) {
+			childBuilt++
+		})
+
+		e.GET("/path")
+		child.GET("/path")
+		assert.Equal(t, 0, childBuilt)
+	})
+}
+
+func TestExpectCloneReporter(t *testing.T) {
+	parentReporter := newMockReporter(t)
+	childReporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		Client:   &mockClient{},
+		Reporter: parentReporter,
+	})
+
+	child := e.Clone(Config{
+		Reporter: childReporter,
+	})
+
+	child.Value(1).Equal(2)
+
+	assert.False(t, parentReporter.reported)
+	assert.True(t, childReporter.reported)
+}
+
+func TestExpectCloneDialer(t *testing.T) {
+	parentDialer := &mockDialer{}
+	childDialer := &mockDialer{}
+
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Reporter: newMockReporter(t),
+		Dialer:   parentDialer,
+	})
+
+	e.Clone(Config{TestName: "test"}).GET("/").Expect().chain.assertFailed(t)
+	assert.Equal(t, 1, parentDialer.dialed)
+	assert.Equal(t, 0, childDialer.dialed)
+
+	e.Clone(Config{Dialer: childDialer}).GET("/").Expect().chain.assertFailed(t)
+	assert.Equal(t, 1, parentDialer.dialed)
+	assert.Equal(t, 1, childDialer.dialed)
+}
+
+func TestExpectFork(t *testing.T) {
+	client := &mockClient{
+		resp: http.Response{
+			StatusCode: http.StatusOK,
+		},
+	}
+
+	t.Run("reporter", func(t *testing.T) {
+		parentT := &mockTestingTB{name: "parent"}
+		childT := &mockTestingTB{name: "parent/child"}
+
+		e := Default(parentT, "http://example.com")
+		e = e.Clone(Config{Client: client})
+
+		child := e.Fork(childT)
+
+		assert.Equal(t, "parent/child", child.config.TestName)
+		assert.Same(t, e.Env(), child.Env())
+
+		child.GET("/path").Expect().Status(http.StatusNotFound)
+
+		assert.Empty(t, parentT.errors)
+		assert.Equal(t, 1, len(childT.errors))
+		assert.False(t, childT.failed)
+	})
+
+	t.Run("require", func(t *testing.T) {
+		parentT := &mockTestingTB{name: "parent"}
+		childT := &mockTestingTB{name: "parent/child"}
+
+		e := WithConfig(Config{
+			Client:   client,
+			Reporter: NewRequireReporter(parentT),
+		})
+
+		e.Fork(childT).GET("/path").Expect().Status(http.StatusNotFound)
+
+		assert.False(t, parentT.failed)
+		assert.True(t, childT.failed)
+	})
+
+	t.Run("aggregate", func(t *testing.T) {
+		parentT := &mockTestingTB{name: "parent"}
+		childT := &mockTestingTB{name: "parent/child"}
+
+		e := WithConfig(Config{
+			Client:            client,
+			Reporter:          NewRequireReporter(parentT),
+			AggregateFailures: true,
+		})
+
+		child := e.Fork(childT)
+
+		child.GET("/path").Expect().Status(http.StatusNotFound)
+		child.GET("/path").Expect().Status(http.StatusBadRequest)
+		child.GET("/path").Expect().Status(http.StatusOK)
+
+		assert.Empty(t, childT.errors)
+
+		childT.cleanup()
+
+		assert.Empty(t, parentT.errors)
+		assert.Equal(t, 2, len(childT.errors))
+		assert.False(t, childT.failed)
+	})
+
+	t.Run("printers", func(t *testing.T) {
+		parentT := &mockTestingTB{name: "parent"}
+		childT := &mockTestingTB{name: "parent/child"}
+
+		logger := newMockLogger(t)
+		printer := &mockPrinter{}
+
+		e := WithConfig(Config{
+			Client: client,
+			AssertionHandler: &DefaultAssertionHandler{
+				Formatter: &DefaultFormatter{},
+				Reporter:  NewAssertReporter(parentT),
+				Logger:    parentT,
+			},
+			Printers: []Printer{
+				NewCompactPrinter(parentT),
+				NewDebugPrinter(logger, true),
+				printer,
+			},
+		})
+
+		child := e.Fork(childT)
+
+		handler := child.config.AssertionHandler.(*DefaultAssertionHandler)
+		assert.Same(t, childT, handler.Logger)
+
+		assert.Equal(t, NewCompactPrinter(childT), child.config.Printers[0])
+		assert.Equal(t, NewDebugPrinter(logger, true), child.config.Printers[1])
+		assert.Same(t, printer, child.config.Printers[2])
+
+		assert.Same(t, parentT, e.config.Printers[0].(CompactPrinter).logger)
+	})
+
+	t.Run("custom handler", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		e := WithConfig(Config{
+			Client:           client,
+			AssertionHandler: handler,
+		})
+
+		child := e.Fork(&mockTestingTB{name: "child"})
+
+		assert.Same(t, handler, child.config.AssertionHandler)
+	})
+
+	t.Run("parallel", func(t *testing.T) {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+
+		e := WithConfig(Config{
+			Client: &http.Client{
+				Transport: NewBinder(handler),
+			},
+			Reporter: NewAssertReporter(t),
+		})
+
+		for i := 0; i < 3; i++ {
+			t.Run("sub", func(t *testing.T) {
+				t.Parallel()
+
+				e.Fork(t).GET("/path").Expect().Status(http.StatusOK)
+			})
+		}
+	})
+}
+
 func TestExpectValues(t *testing.T) {
 	client := &mockClient{}
 
@@ -0,0 +1,300 @@
+package httpexpect
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Fault defines a failure injected by FaultInjectingClient.
+//
+// Method, Path, and Match select requests affected by fault; all of them
+// should match. Skip and Times define which of the selected requests are
+// affected, which allows to inject deterministic bursts of failures.
+type Fault struct {
+	// Method of affected requests. Empty string matches any method.
+	Method string
+
+	// Path of affected requests. May contain parameters in braces, like
+	// "/users/{id}", matching a single path segment.
+	// Empty string matches any path.
+	Path string
+
+	// Match is an optional function to select affected requests.
+	Match func(*http.Request) bool
+
+	// Skip is the number of selected requests passed through before
+	// fault is injected.
+	Skip int
+
+	// Times is the number of selected requests affected by fault, after
+	// skipped ones. Zero means all of them.
+	Times int
+
+	// Latency is a delay added before request is sent.
+	Latency time.Duration
+
+	// ConnectionReset makes client return "connection reset by peer"
+	// error instead of sending request.
+	ConnectionReset bool
+
+	// Status, if non-zero, makes client return response with given status
+	// code and empty body instead of sending request.
+	Status int
+
+	// PartialBody, if positive, truncates response body to given number
+	// of bytes, after which reading body fails with io.ErrUnexpectedEOF.
+	PartialBody int
+
+	// MalformedHeaders replaces Content-Type, Content-Length, and Date
+	// headers of response with invalid values.
+	MalformedHeaders bool
+}
+
+// FaultInjectingClient is a Client decorator that injects faults, like
+// latency, connection resets, 5xx responses, partial bodies, and malformed
+// headers, into requests sent to a real server.
+//
+// It is intended for resilience tests of retries, timeouts, and circuit
+// breakers. Faults are injected deterministically: for every request, the
+// first fault that selects it and is active is applied.
+//
+// FaultInjectingClient is safe for concurrent use.
+//
+// Example:
+//
+//	client := httpexpect.NewFaultInjectingClient(nil,
+//	    // first two requests to /orders fail with 503
+//	    httpexpect.Fault{
+//	        Path:   "/orders",
+//	        Times:  2,
+//	        Status: http.StatusServiceUnavailable,
+//	    },
+//	    // every request to /users is delayed
+//	    httpexpect.Fault{
+//	        Path:    "/users/{id}",
+//	        Latency: 200 * time.Millisecond,
+//	    })
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:  server.URL,
+//	    Reporter: httpexpect.NewAssertReporter(t),
+//	    Client:   client,
+//	})
+//
+//	e.GET("/orders").WithMaxRetries(3).
+//	    Expect().
+//	    Status(http.StatusOK)
+type FaultInjectingClient struct {
+	client Client
+
+	mu       sync.Mutex
+	faults   []*faultState
+	injected int
+}
+
+type faultState struct {
+	fault    Fault
+	segments []string
+	selected int
+}
+
+// NewFaultInjectingClient returns a new FaultInjectingClient that wraps
+// given client and injects given faults.
+//
+// If client is nil, a new http.Client is used.
+func NewFaultInjectingClient(client Client, faults ...Fault) *FaultInjectingClient {
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	c := &FaultInjectingClient{
+		client: client,
+	}
+
+	for _, fault := range faults {
+		c.Add(fault)
+	}
+
+	return c
+}
+
+// Add appends fault to the list of injected faults.
+func (c *FaultInjectingClient) Add(fault Fault) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state := &faultState{
+		fault: fault,
+	}
+
+	if fault.Path != "" {
+		state.segments = strings.Split(strings.Trim(fault.Path, "/"), "/")
+	}
+
+	c.faults = append(c.faults, state)
+}
+
+// Reset removes all faults and resets counter of injected faults.
+func (c *FaultInjectingClient) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.faults = nil
+	c.injected = 0
+}
+
+// Injected returns number of requests affected by faults.
+func (c *FaultInjectingClient) Injected() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.injected
+}
+
+// Do implements Client.Do.
+func (c *FaultInjectingClient) Do(req *http.Request) (*http.Response, error) {
+	fault := c.pick(req)
+	if fault == nil {
+		return c.client.Do(req)
+	}
+
+	if fault.Latency > 0 {
+		timer := time.NewTimer(fault.Latency)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+
+	if fault.ConnectionReset {
+		return nil, &net.OpError{
+			Op:   "read",
+			Net:  "tcp",
+			Addr: faultAddr(req.URL.Host),
+			Err:  os.NewSyscallError("read", syscall.ECONNRESET),
+		}
+	}
+
+	if fault.Status != 0 {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", fault.Status, http.StatusText(fault.Status)),
+			StatusCode: fault.Status,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+			Request:    req,
+		}, nil
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if fault.PartialBody > 0 && resp.Body != nil {
+		resp.Body = &faultPartialBody{
+			body:  resp.Body,
+			limit: fault.PartialBody,
+		}
+		resp.ContentLength = -1
+	}
+
+	if fault.MalformedHeaders {
+		resp.Header.Set("Content-Type", "invalid/;;")
+		resp.Header.Set("Content-Length", "-1")
+		resp.Header.Set("Date", "not a date")
+	}
+
+	return resp, nil
+}
+
+func (c *FaultInjectingClient) pick(req *http.Request) *Fault {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, state := range c.faults {
+		fault := &state.fault
+
+		if fault.Method != "" && !strings.EqualFold(fault.Method, req.Method) {
+			continue
+		}
+
+		if state.segments != nil {
+			segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+			if _, ok := matchOpenAPIPath(state.segments, segments); !ok {
+				continue
+			}
+		}
+
+		if fault.Match != nil && !fault.Match(req) {
+			continue
+		}
+
+		state.selected++
+
+		if state.selected <= fault.Skip {
+			continue
+		}
+		if fault.Times > 0 && state.selected > fault.Skip+fault.Times {
+			continue
+		}
+
+		c.injected++
+
+		ret := *fault
+		return &ret
+	}
+
+	return nil
+}
+
+type faultAddr string
+
+func (a faultAddr) Network() string {
+	return "tcp"
+}
+
+func (a faultAddr) String() string {
+	return string(a)
+}
+
+// returns at most limit bytes, then fails as if connection was dropped
+type faultPartialBody struct {
+	body  io.ReadCloser
+	limit int
+}
+
+func (b *faultPartialBody) Read(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	if len(p) > b.limit {
+		p = p[:b.limit]
+	}
+
+	n, err := b.body.Read(p)
+	b.limit -= n
+
+	return n, err
+}
+
+func (b *faultPartialBody) Close() error {
+	return b.body.Close()
+}
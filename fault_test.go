@@ -0,0 +1,157 @@
+package httpexpect
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaultInjectingClient(t *testing.T) {
+	counter := 0
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		counter++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})
+
+	newClient := func(faults ...Fault) *FaultInjectingClient {
+		return NewFaultInjectingClient(
+			&http.Client{Transport: NewBinder(handler)}, faults...)
+	}
+
+	newRequest := func(method, path string) *http.Request {
+		req, err := http.NewRequest(method, "http://example.com"+path, nil)
+		require.NoError(t, err)
+		return req
+	}
+
+	t.Run("burst", func(t *testing.T) {
+		counter = 0
+
+		client := newClient(Fault{
+			Method: "GET",
+			Path:   "/orders/{id}",
+			Skip:   1,
+			Times:  2,
+			Status: http.StatusServiceUnavailable,
+		})
+
+		var statuses []int
+		for i := 0; i < 4; i++ {
+			resp, err := client.Do(newRequest("GET", "/orders/1"))
+			require.NoError(t, err)
+			statuses = append(statuses, resp.StatusCode)
+		}
+
+		resp, err := client.Do(newRequest("POST", "/orders/1"))
+		require.NoError(t, err)
+		statuses = append(statuses, resp.StatusCode)
+
+		resp, err = client.Do(newRequest("GET", "/users/1"))
+		require.NoError(t, err)
+		statuses = append(statuses, resp.StatusCode)
+
+		assert.Equal(t, []int{200, 503, 503, 200, 200, 200}, statuses)
+		assert.Equal(t, 4, counter)
+		assert.Equal(t, 2, client.Injected())
+
+		client.Reset()
+		assert.Equal(t, 0, client.Injected())
+	})
+
+	t.Run("connection reset", func(t *testing.T) {
+		counter = 0
+
+		client := newClient(Fault{
+			ConnectionReset: true,
+			Match: func(req *http.Request) bool {
+				return req.Header.Get("X-Fail") != ""
+			},
+		})
+
+		req := newRequest("GET", "/")
+		req.Header.Set("X-Fail", "1")
+
+		_, err := client.Do(req)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, syscall.ECONNRESET))
+
+		_, err = client.Do(newRequest("GET", "/"))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, counter)
+	})
+
+	t.Run("partial body", func(t *testing.T) {
+		client := newClient(Fault{
+			PartialBody: 5,
+		})
+
+		resp, err := client.Do(newRequest("GET", "/"))
+		require.NoError(t, err)
+
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.Equal(t, io.ErrUnexpectedEOF, err)
+		assert.Equal(t, `{"ok"`, string(body))
+	})
+
+	t.Run("malformed headers", func(t *testing.T) {
+		client := newClient(Fault{
+			MalformedHeaders: true,
+		})
+
+		resp, err := client.Do(newRequest("GET", "/"))
+		require.NoError(t, err)
+		assert.Equal(t, "invalid/;;", resp.Header.Get("Content-Type"))
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		client := newClient(Fault{
+			Latency: 20 * time.Millisecond,
+		})
+
+		start := time.Now()
+		_, err := client.Do(newRequest("GET", "/"))
+		require.NoError(t, err)
+		assert.True(t, time.Since(start) >= 20*time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		client.Reset()
+		client.Add(Fault{Latency: time.Hour})
+
+		_, err = client.Do(newRequest("GET", "/").WithContext(ctx))
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		client := newClient(Fault{
+			Times:  2,
+			Status: http.StatusBadGateway,
+		})
+
+		e := WithConfig(Config{
+			BaseURL:  "http://example.com",
+			Client:   client,
+			Reporter: newMockReporter(t),
+		})
+
+		e.GET("/").
+			WithMaxRetries(3).
+			WithRetryDelay(0, 0).
+			Expect().
+			Status(http.StatusOK).
+			JSON().Object().ValueEqual("ok", true)
+
+		assert.Equal(t, 2, client.Injected())
+	})
+}
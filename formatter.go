@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"reflect"
 	"strings"
 	"text/template"
+	"unicode/utf8"
 
 	"github.com/mitchellh/go-wordwrap"
 	"github.com/sanity-io/litter"
@@ -31,7 +33,9 @@ type Formatter interface {
 // several public fields.
 //
 // If desired, you can provide custom templates and function map. This may
-// be easier than creating your own formatter from scratch.
+// be easier than creating your own formatter from scratch. Default templates
+// are available as DefaultSuccessTemplate and DefaultFailureTemplate, and
+// can be used as a starting point.
 type DefaultFormatter struct {
 	// Exclude test name and request name from failure report.
 	DisableNames bool
@@ -55,19 +59,79 @@ type DefaultFormatter struct {
 	FailureTemplate string
 
 	// When SuccessTemplate or FailureTemplate is set, this field
-	// defines the function map passed to template engine.
+	// defines additional functions passed to template engine.
 	// May be nil.
+	//
+	// Custom templates can always use functions of default templates
+	// (see DefaultTemplateFuncs); functions from this map with the same
+	// name override them.
 	TemplateFuncs template.FuncMap
+
+	// Colors, verbosity, and truncation limits.
+	// Zero value keeps default behavior.
+	Config FormatterConfig
+}
+
+// FormatterConfig defines optional settings of DefaultFormatter.
+//
+// Zero value corresponds to default behavior: no colors, normal verbosity,
+// and no truncation.
+type FormatterConfig struct {
+	// Defines when to use ANSI colors in messages.
+	// Default is ColorModeNever.
+	ColorMode ColorMode
+
+	// Defines amount of details included into failure messages.
+	// Default is VerbosityNormal.
+	Verbosity Verbosity
+
+	// If positive, formatted actual, expected, and reference values longer
+	// than given number of bytes are truncated.
+	MaxValueLength int
+
+	// If positive, diffs longer than given number of lines are truncated.
+	MaxDiffLines int
 }
 
+// ColorMode defines when DefaultFormatter uses ANSI colors.
+type ColorMode int
+
+const (
+	// Never use colors.
+	ColorModeNever ColorMode = iota
+
+	// Always use colors.
+	ColorModeAlways
+
+	// Use colors if stdout is a terminal, NO_COLOR environment variable
+	// is not set, and TERM environment variable is not "dumb".
+	ColorModeAuto
+)
+
+// Verbosity defines amount of details in failure messages of
+// DefaultFormatter.
+type Verbosity int
+
+const (
+	// Include errors, names, assertion path, values, and diffs.
+	VerbosityNormal Verbosity = iota
+
+	// Include only errors, names, and assertion path.
+	VerbosityCompact
+
+	// Include everything from VerbosityNormal, and additionally dump
+	// request and response being matched.
+	VerbosityVerbose
+)
+
 // FormatSuccess implements Formatter.FormatSuccess.
 func (f *DefaultFormatter) FormatSuccess(ctx *AssertionContext) string {
 	if f.SuccessTemplate != "" {
 		return f.formatTemplate("SuccessTemplate",
-			f.SuccessTemplate, f.TemplateFuncs, ctx, nil)
+			f.SuccessTemplate, f.customFuncs(), ctx, nil)
 	} else {
 		return f.formatTemplate("SuccessTemplate",
-			defaultSuccessTemplate, defaultTemplateFuncs, ctx, nil)
+			DefaultSuccessTemplate, f.defaultFuncs(), ctx, nil)
 	}
 }
 
@@ -77,42 +141,86 @@ func (f *DefaultFormatter) FormatFailure(
 ) string {
 	if f.FailureTemplate != "" {
 		return f.formatTemplate("FailureTemplate",
-			f.FailureTemplate, f.TemplateFuncs, ctx, failure)
+			f.FailureTemplate, f.customFuncs(), ctx, failure)
 	} else {
 		return f.formatTemplate("FailureTemplate",
-			defaultFailureTemplate, defaultTemplateFuncs, ctx, failure)
+			DefaultFailureTemplate, f.defaultFuncs(), ctx, failure)
 	}
 }
 
 // FormatData defines data passed to template engine when DefaultFormatter
 // formats assertion. You can use these fields in your custom templates.
+//
+// FormatData is part of public API: existing fields keep their names and
+// meaning, and new fields may be added.
+//
+// All values are already converted to strings. Fields with "Have" prefix
+// tell whether corresponding field is set; they are false in success
+// messages, and may be false in failure messages depending on assertion
+// type and DefaultFormatter settings.
 type FormatData struct {
+	// Test name and request name from AssertionContext.
+	// Empty if DefaultFormatter.DisableNames is true.
 	TestName    string
 	RequestName string
 
+	// Round-trip time of response, e.g. "123ms".
+	HaveRoundTripTime bool
+	RoundTripTime     string
+
+	// Chain of nested assertion names, e.g. {`Request("GET")`, "Expect()"}.
+	// Empty if DefaultFormatter.DisablePaths is true.
 	AssertPath []string
+
+	// Name of failed assertion type, e.g. "AssertEqual".
+	// Empty in success messages.
 	AssertType string
 
+	// Name of failed assertion severity, "error" or "warning".
+	// Empty in success messages.
+	AssertSeverity string
+
+	// Error messages of failure.
 	Errors []string
 
+	// Formatted actual value.
 	HaveActual bool
 	Actual     string
 
+	// Formatted expected values; usually has one element, but may have
+	// more, e.g. for ranges and lists of allowed values.
+	//
+	// ExpectedKind describes what is expected, e.g. "value", "range",
+	// "regexp", "schema", "key", or "values". IsNegation is true for
+	// negated assertions (e.g. NotEqual), and IsComparison is true for
+	// comparisons (e.g. Lt).
 	HaveExpected bool
 	IsNegation   bool
 	IsComparison bool
 	ExpectedKind string
 	Expected     []string
 
+	// Formatted reference value, see AssertionFailure.Reference.
 	HaveReference bool
 	Reference     string
 
+	// Formatted allowed delta, see AssertionFailure.Delta.
 	HaveDelta bool
 	Delta     string
 
+	// Diff between expected and actual values, in unified format.
 	HaveDiff bool
 	Diff     string
 
+	// Recent WebSocket messages, one per element.
+	HaveTranscript bool
+	Transcript     []string
+
+	// Dump of request and response, set with VerbosityVerbose.
+	HaveExchange bool
+	Exchange     string
+
+	// Line width to be used with "wrap" and "join" functions.
 	LineWidth int
 }
 
@@ -163,9 +271,20 @@ func (f *DefaultFormatter) buildFormatData(
 
 	if failure != nil {
 		data.AssertType = failure.Type.String()
+		data.AssertSeverity = failure.Severity.String()
 
 		f.fillErrors(&data, ctx, failure)
 
+		if f.Config.Verbosity == VerbosityCompact {
+			data.HaveTranscript = false
+			data.Transcript = nil
+			return &data
+		}
+
+		if f.Config.Verbosity == VerbosityVerbose {
+			f.fillExchange(&data, ctx)
+		}
+
 		if failure.Actual != nil {
 			f.fillActual(&data, ctx, failure)
 		}
@@ -183,6 +302,8 @@ func (f *DefaultFormatter) buildFormatData(
 		if failure.Delta != nil {
 			f.fillDelta(&data, ctx, failure)
 		}
+
+		f.fillLimits(&data)
 	}
 
 	return &data
@@ -200,6 +321,16 @@ func (f *DefaultFormatter) fillDescription(
 		data.AssertPath = ctx.Path
 	}
 
+	if ctx.RoundTripTime != nil {
+		data.HaveRoundTripTime = true
+		data.RoundTripTime = ctx.RoundTripTime.String()
+	}
+
+	if ctx.Websocket != nil {
+		data.Transcript = ctx.Websocket.transcript.format(maxFormattedTranscript)
+		data.HaveTranscript = len(data.Transcript) != 0
+	}
+
 	if f.LineWidth != 0 {
 		data.LineWidth = f.LineWidth
 	} else {
@@ -257,6 +388,11 @@ func (f *DefaultFormatter) fillExpected(
 		if !f.DisableDiffs && failure.Actual != nil && failure.Expected != nil {
 			data.Diff, data.HaveDiff = formatDiff(
 				failure.Expected.Value, failure.Actual.Value)
+
+			if !data.HaveDiff {
+				data.Diff, data.HaveDiff = formatStringDiff(
+					failure.Expected.Value, failure.Actual.Value)
+			}
 		}
 
 	case AssertLt, AssertLe, AssertGt, AssertGe:
@@ -392,6 +528,152 @@ func (f *DefaultFormatter) fillDelta(
 	data.Delta = formatFloat(failure.Delta.Value)
 }
 
+func (f *DefaultFormatter) fillExchange(
+	data *FormatData, ctx *AssertionContext,
+) {
+	if dump := dumpAssertionExchange(ctx); dump != "" {
+		data.HaveExchange = true
+		data.Exchange = strings.TrimSuffix(dump, "\n")
+	}
+}
+
+func (f *DefaultFormatter) fillLimits(data *FormatData) {
+	if max := f.Config.MaxValueLength; max > 0 {
+		data.Actual = truncateValue(data.Actual, max)
+		data.Reference = truncateValue(data.Reference, max)
+		for n := range data.Expected {
+			data.Expected[n] = truncateValue(data.Expected[n], max)
+		}
+	}
+
+	if max := f.Config.MaxDiffLines; max > 0 && data.HaveDiff {
+		lines := strings.Split(data.Diff, "\n")
+		if len(lines) > max {
+			data.Diff = strings.Join(lines[:max], "\n") +
+				fmt.Sprintf("\n... (%d more lines)", len(lines)-max)
+		}
+	}
+}
+
+// returns template funcs for custom templates
+func (f *DefaultFormatter) customFuncs() template.FuncMap {
+	funcs := f.defaultFuncs()
+
+	for k, v := range f.TemplateFuncs {
+		funcs[k] = v
+	}
+
+	return funcs
+}
+
+// returns template funcs for default templates, with colors enabled
+// or disabled according to formatter config
+func (f *DefaultFormatter) defaultFuncs() template.FuncMap {
+	enabled := false
+
+	switch f.Config.ColorMode {
+	case ColorModeNever:
+	case ColorModeAlways:
+		enabled = true
+	case ColorModeAuto:
+		enabled = isColorTerminal()
+	}
+
+	funcs := DefaultTemplateFuncs()
+
+	funcs["color"] = func(color string, s string) string {
+		if !enabled {
+			return s
+		}
+		return colorize(color, s)
+	}
+
+	funcs["colordiff"] = func(s string) string {
+		if !enabled {
+			return s
+		}
+		return colorizeDiff(s)
+	}
+
+	return funcs
+}
+
+func isColorTerminal() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	stat, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+var ansiColors = map[string]string{
+	"bold":  "\x1b[1m",
+	"red":   "\x1b[31m",
+	"green": "\x1b[32m",
+	"cyan":  "\x1b[36m",
+}
+
+const ansiReset = "\x1b[0m"
+
+// colorizes every line separately, so that colors survive indentation
+// and wrapping by other template funcs
+func colorize(color string, s string) string {
+	code, ok := ansiColors[color]
+	if !ok {
+		panic(fmt.Sprintf("unknown color %q", color))
+	}
+
+	lines := strings.Split(s, "\n")
+	for n, line := range lines {
+		if line != "" {
+			lines[n] = code + line + ansiReset
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func colorizeDiff(s string) string {
+	lines := strings.Split(s, "\n")
+
+	for n, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"):
+			lines[n] = colorize("bold", line)
+		case strings.HasPrefix(line, "@@"):
+			lines[n] = colorize("cyan", line)
+		case strings.HasPrefix(line, "-"):
+			lines[n] = colorize("red", line)
+		case strings.HasPrefix(line, "+"):
+			lines[n] = colorize("green", line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func truncateValue(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+
+	return s[:cut] + fmt.Sprintf("... (%d more bytes)", len(s)-cut)
+}
+
 func formatTyped(value interface{}) string {
 	return fmt.Sprintf("%T(%#v)", value, value)
 }
@@ -510,6 +792,143 @@ func formatDiff(expected, actual interface{}) (string, bool) {
 	return diffText, true
 }
 
+// Max number of compared line pairs in string diff
+const maxStringDiffCost = 1000000
+
+// Number of context lines around changes in string diff
+const stringDiffContext = 3
+
+// formats unified line diff of two multi-line strings
+func formatStringDiff(expected, actual interface{}) (string, bool) {
+	se, ok := expected.(string)
+	if !ok {
+		return "", false
+	}
+
+	sa, ok := actual.(string)
+	if !ok {
+		return "", false
+	}
+
+	if se == sa || (!strings.Contains(se, "\n") && !strings.Contains(sa, "\n")) {
+		return "", false
+	}
+
+	a := strings.Split(se, "\n")
+	b := strings.Split(sa, "\n")
+
+	if len(a)*len(b) > maxStringDiffCost {
+		return "", false
+	}
+
+	// lcs[i][j] is length of longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type diffLine struct {
+		op   byte
+		text string
+		ai   int
+		bi   int
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i], i, j})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			lines = append(lines, diffLine{'+', b[j], i, j})
+			j++
+		default:
+			lines = append(lines, diffLine{'-', a[i], i, j})
+			i++
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- expected\n+++ actual\n")
+
+	for start := 0; start < len(lines); {
+		// find next change
+		for start < len(lines) && lines[start].op == ' ' {
+			start++
+		}
+		if start == len(lines) {
+			break
+		}
+
+		// extend hunk while changes are close enough
+		end := start
+		for n := start; n < len(lines); n++ {
+			if lines[n].op != ' ' {
+				end = n + 1
+			} else if n-end >= 2*stringDiffContext {
+				break
+			}
+		}
+
+		from := start - stringDiffContext
+		if from < 0 {
+			from = 0
+		}
+		to := end + stringDiffContext
+		if to > len(lines) {
+			to = len(lines)
+		}
+
+		aCount, bCount := 0, 0
+		for _, l := range lines[from:to] {
+			if l.op != '+' {
+				aCount++
+			}
+			if l.op != '-' {
+				bCount++
+			}
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			formatHunkRange(lines[from].ai, aCount),
+			formatHunkRange(lines[from].bi, bCount))
+
+		for _, l := range lines[from:to] {
+			sb.WriteByte(l.op)
+			sb.WriteString(l.text)
+			sb.WriteByte('\n')
+		}
+
+		start = to
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n"), true
+}
+
+func formatHunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
 func exctractRange(value interface{}) *AssertionRange {
 	switch rng := value.(type) {
 	case AssertionRange:
@@ -566,6 +985,36 @@ const (
 	defaultLineWidth = 60
 )
 
+// DefaultTemplateFuncs returns a new map with functions available in
+// default templates of DefaultFormatter:
+//
+//	indent              - indent every line of string
+//	wrap STR WIDTH      - wrap string to keep lines below given width
+//	join LIST WIDTH     - join assertion path, wrapping it to given width
+//	color NAME STR      - colorize string ("bold", "red", "green", "cyan")
+//	colordiff STR       - colorize diff lines
+//
+// color and colordiff return string as is, unless colors are enabled in
+// FormatterConfig.ColorMode. These functions are also available in custom
+// templates.
+func DefaultTemplateFuncs() template.FuncMap {
+	funcs := template.FuncMap{}
+
+	for k, v := range defaultTemplateFuncs {
+		funcs[k] = v
+	}
+
+	funcs["color"] = func(color string, s string) string {
+		return s
+	}
+
+	funcs["colordiff"] = func(s string) string {
+		return s
+	}
+
+	return funcs
+}
+
 var defaultTemplateFuncs = template.FuncMap{
 	"indent": func(s string) string {
 		var sb strings.Builder
@@ -624,15 +1073,27 @@ var defaultTemplateFuncs = template.FuncMap{
 	},
 }
 
-var defaultSuccessTemplate = `[OK] {{ join .AssertPath .LineWidth }}`
+// DefaultSuccessTemplate is the template used by DefaultFormatter to format
+// success messages, unless DefaultFormatter.SuccessTemplate is set.
+//
+// Template is executed with FormatData and DefaultTemplateFuncs.
+const DefaultSuccessTemplate = `[OK] {{ join .AssertPath .LineWidth }}`
 
-var defaultFailureTemplate = `
+// DefaultFailureTemplate is the template used by DefaultFormatter to format
+// failure messages, unless DefaultFormatter.FailureTemplate is set.
+//
+// Template is executed with FormatData and DefaultTemplateFuncs.
+const DefaultFailureTemplate = `
 {{- range $n, $err := .Errors }}
 {{ if eq $n 0 -}}
-{{ wrap $err $.LineWidth }}
+{{ wrap $err $.LineWidth | color "red" }}
 {{- else -}}
-{{ wrap $err $.LineWidth | indent }}
+{{ wrap $err $.LineWidth | indent | color "red" }}
+{{- end -}}
 {{- end -}}
+{{- if eq .AssertSeverity "warning" }}
+
+severity: warning (doesn't fail test)
 {{- end -}}
 {{- if .TestName }}
 
@@ -642,6 +1103,10 @@ test name: {{ .TestName }}
 
 request name: {{ .RequestName }}
 {{- end -}}
+{{- if .HaveRoundTripTime }}
+
+round-trip time: {{ .RoundTripTime }}
+{{- end -}}
 {{- if .AssertPath }}
 
 assertion:
@@ -654,13 +1119,13 @@ assertion:
 {{- else }}expected
 {{- end }} {{ .ExpectedKind }}:
 {{- range $n, $exp := .Expected }}
-{{ $exp | indent }}
+{{ $exp | indent | color "green" }}
 {{- end -}}
 {{- end -}}
 {{- if .HaveActual }}
 
 actual value:
-{{ .Actual | indent }}
+{{ .Actual | indent | color "red" }}
 {{- end -}}
 {{- if .HaveReference }}
 
@@ -675,6 +1140,18 @@ allowed delta:
 {{- if .HaveDiff }}
 
 diff:
-{{ .Diff | indent }}
+{{ .Diff | colordiff | indent }}
+{{- end -}}
+{{- if .HaveTranscript }}
+
+websocket transcript:
+{{- range .Transcript }}
+{{ . | indent }}
+{{- end -}}
+{{- end -}}
+{{- if .HaveExchange }}
+
+request and response:
+{{ .Exchange | indent }}
 {{- end -}}
 `
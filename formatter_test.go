@@ -1,11 +1,14 @@
 package httpexpect
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"text/template"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -93,3 +96,300 @@ func TestFormatDiff(t *testing.T) {
 	checkOK(map[string]interface{}{"a": 1}, map[string]interface{}{})
 	checkOK([]interface{}{"a"}, []interface{}{})
 }
+
+func TestFormatRoundTripTime(t *testing.T) {
+	formatter := &DefaultFormatter{}
+
+	rtt := 123 * time.Millisecond
+
+	data := formatter.buildFormatData(&AssertionContext{}, nil)
+	assert.False(t, data.HaveRoundTripTime)
+	assert.Equal(t, "", data.RoundTripTime)
+
+	data = formatter.buildFormatData(&AssertionContext{RoundTripTime: &rtt}, nil)
+	assert.True(t, data.HaveRoundTripTime)
+	assert.Equal(t, "123ms", data.RoundTripTime)
+
+	failure := &AssertionFailure{
+		Type:   AssertOperation,
+		Errors: []error{errors.New("test")},
+	}
+
+	msg := formatter.FormatFailure(&AssertionContext{}, failure)
+	assert.NotContains(t, msg, "round-trip time:")
+
+	msg = formatter.FormatFailure(&AssertionContext{RoundTripTime: &rtt}, failure)
+	assert.Contains(t, msg, "\nround-trip time: 123ms")
+}
+
+func TestFormatWebsocketTranscript(t *testing.T) {
+	formatter := &DefaultFormatter{}
+
+	data := formatter.buildFormatData(&AssertionContext{}, nil)
+	assert.False(t, data.HaveTranscript)
+
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, newMockWebsocketConn())
+
+	data = formatter.buildFormatData(&AssertionContext{Websocket: ws}, nil)
+	assert.False(t, data.HaveTranscript)
+
+	ws.transcript.add(true, websocket.TextMessage, []byte("hi"), 0)
+	ws.transcript.add(false, websocket.BinaryMessage, []byte{0xCA, 0xFE}, 0)
+	ws.transcript.add(false, websocket.CloseMessage, []byte("bye"), 1000)
+
+	data = formatter.buildFormatData(&AssertionContext{Websocket: ws}, nil)
+	assert.True(t, data.HaveTranscript)
+	assert.Equal(t, []string{
+		`> text "hi"`,
+		`< binary (2 bytes) cafe`,
+		`< close NormalClosure(1000) "bye"`,
+	}, data.Transcript)
+
+	msg := formatter.FormatFailure(&AssertionContext{Websocket: ws},
+		&AssertionFailure{
+			Type:   AssertOperation,
+			Errors: []error{errors.New("test")},
+		})
+	assert.Contains(t, msg, "websocket transcript:")
+	assert.Contains(t, msg, `> text "hi"`)
+}
+
+func TestFormatStringDiff(t *testing.T) {
+	cases := []struct {
+		name     string
+		expected interface{}
+		actual   interface{}
+		diff     string
+		ok       bool
+	}{
+		{
+			name:     "not strings",
+			expected: "foo\nbar",
+			actual:   123,
+			ok:       false,
+		},
+		{
+			name:     "single line",
+			expected: "foo",
+			actual:   "bar",
+			ok:       false,
+		},
+		{
+			name:     "equal",
+			expected: "foo\nbar",
+			actual:   "foo\nbar",
+			ok:       false,
+		},
+		{
+			name:     "changed line",
+			expected: "a\nb\nc",
+			actual:   "a\nB\nc",
+			diff: "--- expected\n+++ actual\n" +
+				"@@ -1,3 +1,3 @@\n a\n-b\n+B\n c",
+			ok: true,
+		},
+		{
+			name:     "added line",
+			expected: "a\nb",
+			actual:   "a\nb\nc",
+			diff: "--- expected\n+++ actual\n" +
+				"@@ -1,2 +1,3 @@\n a\n b\n+c",
+			ok: true,
+		},
+		{
+			name:     "separate hunks",
+			expected: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12",
+			actual:   "0\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n13",
+			diff: "--- expected\n+++ actual\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+0\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+13",
+			ok: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff, ok := formatStringDiff(tc.expected, tc.actual)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.diff, diff)
+		})
+	}
+}
+
+func TestFormatConfig(t *testing.T) {
+	ctx := &AssertionContext{
+		Path: []string{"Value()", "Equal()"},
+	}
+
+	failure := &AssertionFailure{
+		Type:     AssertEqual,
+		Errors:   []error{errors.New("values are not equal")},
+		Actual:   &AssertionValue{"line1\nline2\n" + strings.Repeat("x", 100)},
+		Expected: &AssertionValue{"line1\nLINE2\n" + strings.Repeat("x", 100)},
+	}
+
+	t.Run("default", func(t *testing.T) {
+		formatter := &DefaultFormatter{}
+
+		msg := formatter.FormatFailure(ctx, failure)
+
+		assert.NotContains(t, msg, "\x1b[")
+		assert.Contains(t, msg, "-LINE2")
+		assert.Contains(t, msg, "+line2")
+		assert.Contains(t, msg, strings.Repeat("x", 100))
+	})
+
+	t.Run("colors", func(t *testing.T) {
+		formatter := &DefaultFormatter{
+			Config: FormatterConfig{
+				ColorMode: ColorModeAlways,
+			},
+		}
+
+		msg := formatter.FormatFailure(ctx, failure)
+
+		assert.Contains(t, msg, "\x1b[31mvalues are not equal\x1b[0m")
+		assert.Contains(t, msg, "\x1b[31m-LINE2\x1b[0m")
+		assert.Contains(t, msg, "\x1b[32m+line2\x1b[0m")
+
+		formatter.Config.ColorMode = ColorModeNever
+
+		msg = formatter.FormatFailure(ctx, failure)
+		assert.NotContains(t, msg, "\x1b[")
+	})
+
+	t.Run("truncation", func(t *testing.T) {
+		formatter := &DefaultFormatter{
+			Config: FormatterConfig{
+				MaxValueLength: 20,
+				MaxDiffLines:   3,
+			},
+		}
+
+		data := formatter.buildFormatData(ctx, failure)
+
+		assert.Equal(t, `"line1\nline2\nxxxxx... (96 more bytes)`, data.Actual)
+		assert.Equal(t,
+			[]string{`"line1\nLINE2\nxxxxx... (96 more bytes)`}, data.Expected)
+		assert.Equal(t, "--- expected\n+++ actual\n@@ -1,3 +1,3 @@\n... (4 more lines)",
+			data.Diff)
+
+		assert.Equal(t, "abc", truncateValue("abc", 3))
+		assert.Equal(t, "ab... (1 more bytes)", truncateValue("abc", 2))
+		assert.Equal(t, "... (2 more bytes)", truncateValue("é", 1))
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		formatter := &DefaultFormatter{
+			Config: FormatterConfig{
+				Verbosity: VerbosityCompact,
+			},
+		}
+
+		data := formatter.buildFormatData(ctx, failure)
+
+		assert.Equal(t, []string{"values are not equal"}, data.Errors)
+		assert.Equal(t, ctx.Path, data.AssertPath)
+		assert.False(t, data.HaveActual)
+		assert.False(t, data.HaveExpected)
+		assert.False(t, data.HaveDiff)
+
+		msg := formatter.FormatFailure(ctx, failure)
+		assert.NotContains(t, msg, "line1")
+	})
+
+	t.Run("verbose", func(t *testing.T) {
+		formatter := &DefaultFormatter{
+			Config: FormatterConfig{
+				Verbosity: VerbosityVerbose,
+			},
+		}
+
+		data := formatter.buildFormatData(ctx, failure)
+		assert.False(t, data.HaveExchange)
+
+		req := NewRequest(Config{
+			BaseURL:  "http://example.com",
+			Reporter: newMockReporter(t),
+		}, "GET", "")
+
+		data = formatter.buildFormatData(&AssertionContext{Request: req}, failure)
+		assert.True(t, data.HaveExchange)
+		assert.Equal(t, "GET http://example.com", data.Exchange)
+
+		msg := formatter.FormatFailure(&AssertionContext{Request: req}, failure)
+		assert.Contains(t, msg, "request and response:\n  GET http://example.com")
+		assert.Contains(t, msg, "actual value:")
+	})
+}
+
+func TestFormatTemplates(t *testing.T) {
+	ctx := &AssertionContext{
+		TestName: "TestFoo",
+		Path:     []string{"Value()", "Equal()"},
+	}
+
+	failure := &AssertionFailure{
+		Type:     AssertEqual,
+		Errors:   []error{errors.New("values are not equal")},
+		Actual:   &AssertionValue{"foo"},
+		Expected: &AssertionValue{"bar"},
+	}
+
+	t.Run("default templates", func(t *testing.T) {
+		formatter := &DefaultFormatter{}
+
+		custom := &DefaultFormatter{
+			SuccessTemplate: DefaultSuccessTemplate,
+			FailureTemplate: DefaultFailureTemplate,
+		}
+
+		assert.Equal(t,
+			formatter.FormatSuccess(ctx), custom.FormatSuccess(ctx))
+		assert.Equal(t,
+			formatter.FormatFailure(ctx, failure), custom.FormatFailure(ctx, failure))
+	})
+
+	t.Run("default funcs", func(t *testing.T) {
+		formatter := &DefaultFormatter{
+			FailureTemplate: `{{ .TestName }}: {{ join .AssertPath -1 }}` +
+				`{{ range .Errors }}{{ color "red" . | indent }}{{ end }}`,
+			Config: FormatterConfig{
+				ColorMode: ColorModeAlways,
+			},
+		}
+
+		assert.Equal(t,
+			"TestFoo: Value().Equal()  \x1b[31mvalues are not equal\x1b[0m",
+			formatter.FormatFailure(ctx, failure))
+	})
+
+	t.Run("custom funcs", func(t *testing.T) {
+		formatter := &DefaultFormatter{
+			FailureTemplate: `{{ upper .AssertType }} {{ indent .Actual }}`,
+			TemplateFuncs: template.FuncMap{
+				"upper": strings.ToUpper,
+				"indent": func(s string) string {
+					return "> " + s
+				},
+			},
+		}
+
+		assert.Equal(t,
+			`ASSERTEQUAL > "foo"`,
+			formatter.FormatFailure(ctx, failure))
+	})
+
+	t.Run("func list", func(t *testing.T) {
+		funcs := DefaultTemplateFuncs()
+
+		for _, name := range []string{"indent", "wrap", "join", "color", "colordiff"} {
+			assert.Contains(t, funcs, name)
+		}
+
+		funcs["indent"] = nil
+		assert.NotNil(t, DefaultTemplateFuncs()["indent"])
+	})
+}
@@ -0,0 +1,590 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FuzzOpts defines inputs mutated by Expect.Fuzz.
+type FuzzOpts struct {
+	// Query parameters template. Every parameter is mutated in turn,
+	// while others keep their template values.
+	Query map[string]interface{}
+
+	// Headers template. Every header is mutated in turn, while others keep
+	// their template values. Mutations that can't be represented in
+	// header value, like control characters, are skipped.
+	Headers map[string]string
+
+	// JSON body template. Every node of the template, including nested
+	// objects and arrays, is mutated in turn. Additionally, body is
+	// replaced with malformed JSON.
+	JSON interface{}
+
+	// MaxCases limits number of generated cases. Zero means no limit.
+	MaxCases int
+
+	// LeakPatterns are regular expressions that should not match response
+	// body. Default patterns detect stack traces of Go, JVM, Python,
+	// Node.js, .NET, PHP, and Ruby.
+	LeakPatterns []*regexp.Regexp
+}
+
+// Fuzz sends requests with malformed and boundary inputs and checks that
+// server never responds with 5xx status and never leaks stack traces in
+// response body.
+//
+// Inputs are generated from templates in given options: every query
+// parameter, header, and JSON body node is in turn replaced with empty,
+// huge, and unicode edge case strings, negative and extreme numbers,
+// values of unexpected types, or removed. Cases are generated
+// deterministically, in the same order on every run.
+//
+// If opts are omitted or have no templates, and Config.OpenAPISpec is set,
+// templates are generated from parameters and JSON request body schema of
+// operation matching given method and path.
+//
+// Every failed case is reported as a separate failure; description of
+// the case is included into failure path. Fuzzed requests are not
+// validated against Config.OpenAPISpec.
+//
+// Example:
+//
+//	e.Fuzz("POST", "/users", httpexpect.FuzzOpts{
+//	    Query: map[string]interface{}{
+//	        "notify": true,
+//	    },
+//	    JSON: map[string]interface{}{
+//	        "name": "john",
+//	        "age":  30,
+//	    },
+//	})
+func (e *Expect) Fuzz(method, path string, opts ...FuzzOpts) {
+	e.chain.enter("Fuzz(%q, %q)", method, path)
+	defer e.chain.leave()
+
+	if len(opts) > 1 {
+		chain := e.chain.clone()
+		chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple opts arguments"),
+			},
+		})
+		return
+	}
+
+	var o FuzzOpts
+	if len(opts) == 1 {
+		o = opts[0]
+	}
+
+	if o.Query == nil && o.Headers == nil && o.JSON == nil &&
+		e.config.OpenAPISpec != "" {
+		if !e.fuzzTemplateFromSpec(method, path, &o) {
+			return
+		}
+	}
+
+	if o.JSON != nil {
+		// round-trip to get a copy that can be modified
+		b, err := json.Marshal(o.JSON)
+		if err == nil {
+			o.JSON = nil
+			err = json.Unmarshal(b, &o.JSON)
+		}
+		if err != nil {
+			chain := e.chain.clone()
+			chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{o.JSON},
+				Errors: []error{
+					errors.New("expected: value can be encoded to JSON"),
+					err,
+				},
+			})
+			return
+		}
+	}
+
+	if o.LeakPatterns == nil {
+		o.LeakPatterns = fuzzLeakPatterns
+	}
+
+	cases := fuzzCases(o)
+	if o.MaxCases > 0 && len(cases) > o.MaxCases {
+		cases = cases[:o.MaxCases]
+	}
+
+	for _, c := range cases {
+		e.fuzzCase(method, path, o, c)
+	}
+}
+
+func (e *Expect) fuzzCase(method, path string, opts FuzzOpts, c fuzzCase) {
+	e.chain.enter("Case(%q)", c.name)
+	defer e.chain.leave()
+
+	req := e.Request(method, path).WithoutSpecValidation()
+
+	for _, k := range fuzzSortedKeys(c.query) {
+		req.WithQuery(k, c.query[k])
+	}
+
+	for _, k := range fuzzSortedKeys(c.headers) {
+		req.WithHeader(k, fmt.Sprint(c.headers[k]))
+	}
+
+	switch {
+	case c.rawBody != nil:
+		req.WithHeader("Content-Type", "application/json").WithBytes(c.rawBody)
+	case c.hasBody:
+		req.WithJSON(c.body)
+	}
+
+	resp := req.Expect()
+
+	if resp.chain.failed() {
+		return
+	}
+
+	if resp.httpResp.StatusCode >= 500 {
+		errs := []error{
+			errors.New("expected: http status is not a server error"),
+		}
+
+		if len(resp.content) != 0 {
+			errs = append(errs,
+				fmt.Errorf("response body: %s", bodySnippet(resp.content)))
+		}
+
+		resp.chain.fail(AssertionFailure{
+			Type:   AssertNotBelongs,
+			Actual: &AssertionValue{statusCodeText(resp.httpResp.StatusCode)},
+			Expected: &AssertionValue{AssertionList{
+				statusRangeText(int(Status5xx)),
+			}},
+			Errors: errs,
+		})
+		return
+	}
+
+	for _, re := range opts.LeakPatterns {
+		if re.Match(resp.content) {
+			resp.chain.fail(AssertionFailure{
+				Type:     AssertNotMatchRegexp,
+				Expected: &AssertionValue{re.String()},
+				Errors: []error{
+					errors.New("expected: response body does not contain stack trace"),
+					fmt.Errorf("response body: %s", bodySnippet(resp.content)),
+				},
+			})
+			return
+		}
+	}
+}
+
+// builds templates from operation in OpenAPI spec
+func (e *Expect) fuzzTemplateFromSpec(method, path string, opts *FuzzOpts) bool {
+	spec, err := loadOpenAPISpec(e.config.Client, e.config.OpenAPISpec)
+	if err != nil {
+		chain := e.chain.clone()
+		chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{e.config.OpenAPISpec},
+			Errors: []error{
+				errors.New("expected: valid OpenAPI spec"),
+				err,
+			},
+		})
+		return false
+	}
+
+	op, _, _ := spec.findOperation(method, path)
+	if op == nil {
+		chain := e.chain.clone()
+		chain.fail(AssertionFailure{
+			Type:     AssertValid,
+			Actual:   &AssertionValue{strings.ToUpper(method) + " " + path},
+			Expected: &AssertionValue{e.config.OpenAPISpec},
+			Errors: []error{
+				errors.New("expected: operation is defined in OpenAPI spec"),
+			},
+		})
+		return false
+	}
+
+	opts.Query, opts.Headers, opts.JSON = spec.fuzzTemplate(op)
+
+	return true
+}
+
+// returns sample values of query and header parameters and JSON body
+func (s *openAPISpec) fuzzTemplate(
+	op *openAPIOperation,
+) (map[string]interface{}, map[string]string, interface{}) {
+	var (
+		query   map[string]interface{}
+		headers map[string]string
+		body    interface{}
+	)
+
+	for _, list := range []interface{}{
+		op.path.item["parameters"], op.operation["parameters"],
+	} {
+		items, _ := list.([]interface{})
+		for _, item := range items {
+			param, err := s.resolve(item)
+			if err != nil {
+				continue
+			}
+
+			name, _ := param["name"].(string)
+			value := s.sampleValue(param["schema"], 0)
+
+			switch param["in"] {
+			case "query":
+				if query == nil {
+					query = map[string]interface{}{}
+				}
+				query[name] = value
+
+			case "header":
+				if headers == nil {
+					headers = map[string]string{}
+				}
+				headers[name] = fmt.Sprint(value)
+			}
+		}
+	}
+
+	if reqBody, err := s.resolve(op.operation["requestBody"]); err == nil {
+		content, _ := reqBody["content"].(map[string]interface{})
+		for mediaType, media := range content {
+			mediaMap, ok := media.(map[string]interface{})
+			if !ok || !isJSONMediaType(mediaType) {
+				continue
+			}
+			body = s.sampleValue(mediaMap["schema"], 0)
+			break
+		}
+	}
+
+	return query, headers, body
+}
+
+// generates a valid value for schema, preferring examples and defaults
+func (s *openAPISpec) sampleValue(schema interface{}, depth int) interface{} {
+	m, err := s.resolve(schema)
+	if err != nil || depth > 8 {
+		return "string"
+	}
+
+	for _, key := range []string{"example", "default"} {
+		if v, ok := m[key]; ok {
+			return v
+		}
+	}
+
+	if enum, ok := m["enum"].([]interface{}); ok && len(enum) != 0 {
+		return enum[0]
+	}
+
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if list, ok := m[key].([]interface{}); ok && len(list) != 0 {
+			if key != "allOf" || len(list) == 1 {
+				return s.sampleValue(list[0], depth+1)
+			}
+			merged := map[string]interface{}{}
+			for _, item := range list {
+				if obj, ok := s.sampleValue(item, depth+1).(map[string]interface{}); ok {
+					for k, v := range obj {
+						merged[k] = v
+					}
+				}
+			}
+			return merged
+		}
+	}
+
+	props, hasProps := m["properties"].(map[string]interface{})
+
+	switch {
+	case m["type"] == "integer":
+		return 1
+	case m["type"] == "number":
+		return 1.5
+	case m["type"] == "boolean":
+		return true
+	case m["type"] == "array":
+		return []interface{}{s.sampleValue(m["items"], depth+1)}
+	case m["type"] == "object" || hasProps:
+		obj := map[string]interface{}{}
+		for k, v := range props {
+			obj[k] = s.sampleValue(v, depth+1)
+		}
+		return obj
+	}
+
+	switch m["format"] {
+	case "date-time":
+		return "2020-01-01T00:00:00Z"
+	case "date":
+		return "2020-01-01"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	}
+
+	return "string"
+}
+
+type fuzzValue struct {
+	name  string
+	value interface{}
+}
+
+// mutations applied to every input
+var fuzzScalars = []fuzzValue{
+	{"empty string", ""},
+	{"huge string", strings.Repeat("A", 64*1024)},
+	{"null byte", "a\x00b"},
+	{"newline", "a\r\nb"},
+	{"right-to-left override", "\u202egnp.exe"},
+	{"byte order mark", "\ufeffvalue"},
+	{"emoji", "\U0001F600\U0001F4A9"},
+	{"combining characters", "e\u0301\u0301\u0301\u0301"},
+	{"invalid utf-8", "\xff\xfe\xfd"},
+	{"sql injection", "' OR '1'='1' --"},
+	{"path traversal", "../../../../etc/passwd"},
+	{"format string", "%s%s%s%n%x"},
+	{"html", "<script>alert(1)</script>"},
+	{"zero", 0},
+	{"negative number", -1},
+	{"min int64", int64(math.MinInt64)},
+	{"max int64", int64(math.MaxInt64)},
+	{"max float", math.MaxFloat64},
+	{"smallest float", math.SmallestNonzeroFloat64},
+	{"fraction", 0.5},
+}
+
+// mutations applied to JSON nodes only
+var fuzzTypes = []fuzzValue{
+	{"null", nil},
+	{"boolean", true},
+	{"string", "string"},
+	{"number", 123},
+	{"array", []interface{}{}},
+	{"object", map[string]interface{}{}},
+}
+
+// malformed JSON bodies
+var fuzzMalformed = []fuzzValue{
+	{"empty body", ""},
+	{"unterminated object", `{"a":`},
+	{"unterminated array", "[1,2"},
+	{"trailing comma", `{"a":1,}`},
+	{"invalid literal", "nul"},
+	{"deep nesting", strings.Repeat("[", 10000) + strings.Repeat("]", 10000)},
+}
+
+var fuzzLeakPatterns = []*regexp.Regexp{
+	// Go
+	regexp.MustCompile(`goroutine \d+ \[[\w ]+\]:`),
+	regexp.MustCompile(`\.go:\d+ \+0x[0-9a-f]+`),
+	// JVM
+	regexp.MustCompile(`\bat [\w$.<>]+\([\w$]+\.(java|kt|scala):\d+\)`),
+	// Python
+	regexp.MustCompile(`Traceback \(most recent call last\)`),
+	// Node.js
+	regexp.MustCompile(`\bat .+ \(.+\.[cm]?js:\d+:\d+\)`),
+	// .NET
+	regexp.MustCompile(`\bat .+ in .+\.cs:line \d+`),
+	// PHP
+	regexp.MustCompile(`Stack trace:\s+#0 `),
+	// Ruby
+	regexp.MustCompile("\\.rb:\\d+:in [`']"),
+}
+
+type fuzzCase struct {
+	name    string
+	query   map[string]interface{}
+	headers map[string]interface{}
+	hasBody bool
+	body    interface{}
+	rawBody []byte
+}
+
+func fuzzCases(opts FuzzOpts) []fuzzCase {
+	headers := map[string]interface{}{}
+	for k, v := range opts.Headers {
+		headers[k] = v
+	}
+
+	base := fuzzCase{
+		query:   opts.Query,
+		headers: headers,
+		hasBody: opts.JSON != nil,
+		body:    opts.JSON,
+	}
+
+	var cases []fuzzCase
+
+	for _, k := range fuzzSortedKeys(opts.Query) {
+		for _, mut := range append(fuzzScalars, fuzzValue{"missing", fuzzMissing}) {
+			c := base
+			c.name = fmt.Sprintf("query %q: %s", k, mut.name)
+			c.query = fuzzReplaceKey(opts.Query, k, mut.value)
+			cases = append(cases, c)
+		}
+	}
+
+	for _, k := range fuzzSortedKeys(headers) {
+		for _, mut := range append(fuzzScalars, fuzzValue{"missing", fuzzMissing}) {
+			if s, ok := mut.value.(string); ok && !fuzzValidHeader(s) {
+				continue
+			}
+			c := base
+			c.name = fmt.Sprintf("header %q: %s", k, mut.name)
+			c.headers = fuzzReplaceKey(headers, k, mut.value)
+			cases = append(cases, c)
+		}
+	}
+
+	if opts.JSON != nil {
+		for _, node := range fuzzJSONNodes(opts.JSON, "$", nil) {
+			mutations := fuzzTypes
+			if _, ok := node.value.(map[string]interface{}); !ok {
+				if _, ok := node.value.([]interface{}); !ok {
+					mutations = append(append([]fuzzValue{}, fuzzScalars...), fuzzTypes...)
+				}
+			}
+			if node.removable {
+				mutations = append(mutations, fuzzValue{"missing", fuzzMissing})
+			}
+
+			for _, mut := range mutations {
+				c := base
+				c.name = fmt.Sprintf("json %s: %s", node.path, mut.name)
+				c.body = fuzzReplaceNode(opts.JSON, node.steps, mut.value)
+				cases = append(cases, c)
+			}
+		}
+
+		for _, mut := range fuzzMalformed {
+			c := base
+			c.name = fmt.Sprintf("json: %s", mut.name)
+			c.rawBody = []byte(mut.value.(string))
+			cases = append(cases, c)
+		}
+	}
+
+	return cases
+}
+
+// special value meaning that key should be removed
+var fuzzMissing = &struct{}{}
+
+func fuzzReplaceKey(
+	m map[string]interface{}, key string, value interface{},
+) map[string]interface{} {
+	ret := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if k != key {
+			ret[k] = v
+		}
+	}
+	if value != fuzzMissing {
+		ret[key] = value
+	}
+	return ret
+}
+
+func fuzzValidHeader(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+type fuzzJSONNode struct {
+	path      string
+	steps     []interface{}
+	value     interface{}
+	removable bool
+}
+
+// returns all nodes of JSON value in depth-first order
+func fuzzJSONNodes(value interface{}, path string, steps []interface{}) []fuzzJSONNode {
+	nodes := []fuzzJSONNode{{
+		path:  path,
+		steps: steps,
+		value: value,
+	}}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, k := range fuzzSortedKeys(v) {
+			child := fuzzJSONNodes(v[k], path+"."+k, fuzzAppendStep(steps, k))
+			child[0].removable = true
+			nodes = append(nodes, child...)
+		}
+
+	case []interface{}:
+		for i := range v {
+			nodes = append(nodes, fuzzJSONNodes(v[i],
+				fmt.Sprintf("%s[%d]", path, i), fuzzAppendStep(steps, i))...)
+		}
+	}
+
+	return nodes
+}
+
+func fuzzAppendStep(steps []interface{}, step interface{}) []interface{} {
+	return append(append([]interface{}(nil), steps...), step)
+}
+
+// returns copy of JSON value with node at given path replaced or removed
+func fuzzReplaceNode(
+	root interface{}, steps []interface{}, value interface{},
+) interface{} {
+	if len(steps) == 0 {
+		return value
+	}
+
+	switch v := root.(type) {
+	case map[string]interface{}:
+		key := steps[0].(string)
+		if len(steps) == 1 {
+			return fuzzReplaceKey(v, key, value)
+		}
+		ret := fuzzReplaceKey(v, key, nil)
+		ret[key] = fuzzReplaceNode(v[key], steps[1:], value)
+		return ret
+
+	case []interface{}:
+		idx := steps[0].(int)
+		ret := append([]interface{}(nil), v...)
+		ret[idx] = fuzzReplaceNode(v[idx], steps[1:], value)
+		return ret
+	}
+
+	return root
+}
+
+func fuzzSortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,324 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzCases(t *testing.T) {
+	opts := FuzzOpts{
+		Query: map[string]interface{}{
+			"limit": 10,
+		},
+		Headers: map[string]string{
+			"X-Token": "secret",
+		},
+		JSON: map[string]interface{}{
+			"name": "john",
+			"tags": []interface{}{"a"},
+		},
+	}
+
+	cases := fuzzCases(opts)
+
+	names := map[string]bool{}
+	for _, c := range cases {
+		assert.False(t, names[c.name], c.name)
+		names[c.name] = true
+	}
+
+	assert.True(t, names[`query "limit": negative number`])
+	assert.True(t, names[`query "limit": missing`])
+	assert.True(t, names[`header "X-Token": huge string`])
+	assert.True(t, names[`header "X-Token": missing`])
+	assert.True(t, names[`json $: null`])
+	assert.True(t, names[`json $.name: emoji`])
+	assert.True(t, names[`json $.name: missing`])
+	assert.True(t, names[`json $.tags: object`])
+	assert.True(t, names[`json $.tags[0]: max int64`])
+	assert.True(t, names[`json: deep nesting`])
+
+	// control characters can't be sent in headers
+	assert.False(t, names[`header "X-Token": newline`])
+	assert.False(t, names[`header "X-Token": null byte`])
+
+	// arrays and objects are not replaced with scalars
+	assert.False(t, names[`json $.tags: emoji`])
+
+	// array elements can't be removed
+	assert.False(t, names[`json $.tags[0]: missing`])
+
+	// template is not modified
+	for _, c := range cases {
+		if c.name == `json $.tags[0]: zero` {
+			assert.Equal(t, map[string]interface{}{
+				"name": "john",
+				"tags": []interface{}{0},
+			}, c.body)
+		}
+		if c.name == `query "limit": missing` {
+			assert.Equal(t, map[string]interface{}{}, c.query)
+		}
+	}
+	assert.Equal(t, map[string]interface{}{
+		"name": "john",
+		"tags": []interface{}{"a"},
+	}, opts.JSON)
+	assert.Equal(t, map[string]interface{}{"limit": 10}, opts.Query)
+
+	assert.Equal(t, cases, fuzzCases(opts))
+}
+
+func TestFuzzExpect(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		b, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch name := body["name"].(type) {
+		case string:
+			if name == "" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		case nil:
+			_, _ = w.Write([]byte("goroutine 1 [running]:\nmain.handler()"))
+			return
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		var paths []string
+
+		e := WithConfig(Config{
+			BaseURL:  "http://example.com",
+			Client:   &http.Client{Transport: NewBinder(handler)},
+			Reporter: newMockReporter(t),
+			OnFailure: func(ctx *AssertionContext, failure *AssertionFailure) {
+				paths = append(paths, strings.Join(ctx.Path, "."))
+			},
+		})
+
+		e.Fuzz("POST", "/users", FuzzOpts{
+			JSON: map[string]interface{}{
+				"name": "john",
+			},
+		})
+
+		prefix := `Fuzz("POST", "/users").Case(`
+		suffix := `).Request("POST").Expect()`
+
+		assert.Equal(t, []string{
+			prefix + `"json $: null"` + suffix,
+			prefix + `"json $: object"` + suffix,
+			prefix + `"json $.name: empty string"` + suffix,
+			prefix + `"json $.name: null"` + suffix,
+			prefix + `"json $.name: missing"` + suffix,
+		}, paths)
+	})
+
+	t.Run("max cases", func(t *testing.T) {
+		var count int
+
+		e := WithConfig(Config{
+			BaseURL: "http://example.com",
+			Client: &http.Client{Transport: NewBinder(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					count++
+				}))},
+			Reporter: newMockReporter(t),
+		})
+
+		e.Fuzz("GET", "/", FuzzOpts{
+			Query:    map[string]interface{}{"q": "x"},
+			MaxCases: 5,
+		})
+
+		assert.Equal(t, 5, count)
+		e.chain.assertOK(t)
+	})
+
+	t.Run("leak patterns", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  "http://example.com",
+			Client:   &http.Client{Transport: NewBinder(handler)},
+			Reporter: newMockReporter(t),
+		})
+
+		assertionHandler := &mockAssertionHandler{}
+		e = e.Clone(Config{AssertionHandler: assertionHandler})
+
+		e.Fuzz("POST", "/users", FuzzOpts{
+			JSON: map[string]interface{}{
+				"name": "john",
+			},
+			LeakPatterns: []*regexp.Regexp{
+				regexp.MustCompile(`goroutine`),
+			},
+			MaxCases: 30,
+		})
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertNotMatchRegexp, assertionHandler.failure.Type)
+		assert.Equal(t, "goroutine", assertionHandler.failure.Expected.Value)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		e := WithConfig(Config{
+			Client:   &mockClient{},
+			Reporter: newMockReporter(t),
+		})
+
+		var count int
+		e = e.Clone(Config{
+			OnFailure: func(*AssertionContext, *AssertionFailure) {
+				count++
+			},
+		})
+
+		e.Fuzz("GET", "/", FuzzOpts{}, FuzzOpts{})
+		e.Fuzz("GET", "/", FuzzOpts{JSON: func() {}})
+
+		assert.Equal(t, 2, count)
+		e.chain.assertOK(t)
+	})
+}
+
+func TestFuzzLeakPatterns(t *testing.T) {
+	leaks := []string{
+		"goroutine 1 [running]:\nmain.main()",
+		"main.go:12 +0x1d",
+		"at com.example.Foo.bar(Foo.java:42)",
+		"Traceback (most recent call last):\n  File \"app.py\"",
+		"at handler (/app/server.js:10:15)",
+		"at Foo.Bar() in C:\\src\\Foo.cs:line 42",
+		"Stack trace:\n#0 /var/www/index.php(3): foo()",
+		"app/models/user.rb:10:in `save'",
+	}
+
+	for _, leak := range leaks {
+		matched := false
+		for _, re := range fuzzLeakPatterns {
+			if re.MatchString(leak) {
+				matched = true
+			}
+		}
+		assert.True(t, matched, leak)
+	}
+
+	for _, ok := range []string{
+		`{"error": "invalid name"}`,
+		"Bad Request",
+		"<html>at the moment (not available)</html>",
+	} {
+		for _, re := range fuzzLeakPatterns {
+			assert.False(t, re.MatchString(ok), ok)
+		}
+	}
+}
+
+func TestFuzzOpenAPI(t *testing.T) {
+	spec := writeOpenAPISpec(t, `
+openapi: 3.0.0
+info:
+  title: test
+  version: "1"
+paths:
+  /users:
+    post:
+      parameters:
+        - name: notify
+          in: query
+          schema:
+            type: boolean
+        - name: X-Request-Id
+          in: header
+          schema:
+            type: string
+            format: uuid
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        "201":
+          description: created
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        name:
+          type: string
+          example: john
+        age:
+          type: integer
+        role:
+          enum: [admin, user]
+        tags:
+          type: array
+          items:
+            type: string
+`)
+
+	var requests []*http.Request
+	var bodies []string
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r)
+		bodies = append(bodies, string(b))
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	e := WithConfig(Config{
+		BaseURL:     "http://example.com",
+		Client:      &http.Client{Transport: NewBinder(handler)},
+		Reporter:    newMockReporter(t),
+		OpenAPISpec: spec,
+	})
+
+	e.Fuzz("POST", "/users", FuzzOpts{MaxCases: 1})
+
+	e.chain.assertOK(t)
+
+	require.Equal(t, 1, len(requests))
+
+	assert.Equal(t, "00000000-0000-0000-0000-000000000000",
+		requests[0].Header.Get("X-Request-Id"))
+
+	var body interface{}
+	require.NoError(t, json.Unmarshal([]byte(bodies[0]), &body))
+	assert.Equal(t, map[string]interface{}{
+		"name": "john",
+		"age":  float64(1),
+		"role": "admin",
+		"tags": []interface{}{"string"},
+	}, body)
+
+	t.Run("unknown operation", func(t *testing.T) {
+		assertionHandler := &mockAssertionHandler{}
+
+		e.Clone(Config{AssertionHandler: assertionHandler}).
+			Fuzz("DELETE", "/users")
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertValid, assertionHandler.failure.Type)
+	})
+}
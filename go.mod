@@ -4,11 +4,13 @@ go 1.14
 
 require (
 	github.com/ajg/form v1.5.1
+	github.com/andybalholm/brotli v1.0.4
 	github.com/fasthttp/websocket v1.4.3-rc.6
 	github.com/fatih/structs v1.1.0
 	github.com/google/go-querystring v1.1.0
 	github.com/gorilla/websocket v1.4.2
 	github.com/imkira/go-interpol v1.1.0
+	github.com/klauspost/compress v1.15.0
 	github.com/mitchellh/go-wordwrap v1.0.1
 	github.com/sanity-io/litter v1.5.5
 	github.com/stretchr/testify v1.4.0
@@ -17,6 +19,7 @@ require (
 	github.com/yalp/jsonpath v0.0.0-20180802001716-5cc68e5049a0
 	github.com/yudai/gojsondiff v1.0.0
 	golang.org/x/net v0.0.0-20220225172249-27dd8689420f
+	gopkg.in/yaml.v2 v2.2.2
 	moul.io/http2curl/v2 v2.3.0
 )
 
@@ -0,0 +1,232 @@
+package httpexpect
+
+import (
+	"errors"
+)
+
+// GraphQL provides methods to inspect GraphQL response, i.e. JSON object
+// with "data", "errors", and "extensions" fields.
+type GraphQL struct {
+	chain *chain
+	value map[string]interface{}
+}
+
+// NewGraphQL returns a new GraphQL instance.
+//
+// reporter should not be nil. value should be decoded GraphQL response,
+// i.e. an object with "data" or "errors" field. If it is not, failure
+// is reported.
+//
+// Example:
+//
+//	gql := NewGraphQL(t, map[string]interface{}{
+//	    "data": map[string]interface{}{"user": nil},
+//	})
+//	gql.NoErrors()
+func NewGraphQL(reporter Reporter, value interface{}) *GraphQL {
+	return newGraphQL(newChainWithDefaults("GraphQL()", reporter), value)
+}
+
+func newGraphQL(parent *chain, val interface{}) *GraphQL {
+	g := &GraphQL{parent.clone(), nil}
+
+	if g.chain.failed() {
+		return g
+	}
+
+	obj, ok := val.(map[string]interface{})
+	if ok {
+		_, hasData := obj["data"]
+		_, hasErrors := obj["errors"]
+		ok = hasData || hasErrors
+	}
+
+	if !ok {
+		g.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{val},
+			Errors: []error{
+				errors.New(
+					`expected: GraphQL response object with "data" or "errors" field`),
+			},
+		})
+		return g
+	}
+
+	if errs, ok := obj["errors"]; ok && errs != nil {
+		if _, ok := errs.([]interface{}); !ok {
+			g.chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{errs},
+				Errors: []error{
+					errors.New(`expected: GraphQL "errors" field is array`),
+				},
+			})
+			return g
+		}
+	}
+
+	g.value = obj
+
+	return g
+}
+
+// Raw returns underlying value attached to GraphQL.
+//
+// Example:
+//
+//	gql := NewGraphQL(t, value)
+//	assert.Equal(t, value, gql.Raw())
+func (g *GraphQL) Raw() map[string]interface{} {
+	return g.value
+}
+
+// Data returns a new Object instance with "data" field of response.
+//
+// Data fails if "data" field is missing, null, or is not an object.
+//
+// Example:
+//
+//	gql := NewGraphQL(t, value)
+//	gql.Data().Path("$.user.name").String().Equal("john")
+func (g *GraphQL) Data() *Object {
+	g.chain.enter("Data()")
+	defer g.chain.leave()
+
+	if g.chain.failed() {
+		return newObject(g.chain, nil)
+	}
+
+	data, ok := g.value["data"].(map[string]interface{})
+	if !ok {
+		g.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{g.value["data"]},
+			Errors: []error{
+				errors.New(`expected: GraphQL "data" field is object`),
+			},
+		})
+		return newObject(g.chain, nil)
+	}
+
+	return newObject(g.chain, data)
+}
+
+// Errors returns a new Array instance with "errors" field of response.
+//
+// If "errors" field is missing, empty array is returned.
+//
+// Example:
+//
+//	gql := NewGraphQL(t, value)
+//	gql.Errors().Element(0).Object().
+//	    Value("message").String().Contains("not found")
+func (g *GraphQL) Errors() *Array {
+	g.chain.enter("Errors()")
+	defer g.chain.leave()
+
+	if g.chain.failed() {
+		return newArray(g.chain, nil)
+	}
+
+	return newArray(g.chain, g.errors())
+}
+
+// Extensions returns a new Object instance with "extensions" field of
+// response.
+//
+// If "extensions" field is missing, empty object is returned.
+//
+// Example:
+//
+//	gql := NewGraphQL(t, value)
+//	gql.Extensions().ContainsKey("tracing")
+func (g *GraphQL) Extensions() *Object {
+	g.chain.enter("Extensions()")
+	defer g.chain.leave()
+
+	if g.chain.failed() {
+		return newObject(g.chain, nil)
+	}
+
+	ext, ok := g.value["extensions"]
+	if !ok || ext == nil {
+		return newObject(g.chain, map[string]interface{}{})
+	}
+
+	obj, ok := ext.(map[string]interface{})
+	if !ok {
+		g.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{ext},
+			Errors: []error{
+				errors.New(`expected: GraphQL "extensions" field is object`),
+			},
+		})
+		return newObject(g.chain, nil)
+	}
+
+	return newObject(g.chain, obj)
+}
+
+// HasErrors succeeds if response has non-empty "errors" field.
+//
+// Example:
+//
+//	gql := NewGraphQL(t, value)
+//	gql.HasErrors()
+func (g *GraphQL) HasErrors() *GraphQL {
+	g.chain.enter("HasErrors()")
+	defer g.chain.leave()
+
+	if g.chain.failed() {
+		return g
+	}
+
+	if len(g.errors()) == 0 {
+		g.chain.fail(AssertionFailure{
+			Type:   AssertNotEmpty,
+			Actual: &AssertionValue{g.value},
+			Errors: []error{
+				errors.New("expected: GraphQL response has errors"),
+			},
+		})
+	}
+
+	return g
+}
+
+// NoErrors succeeds if response has missing or empty "errors" field.
+//
+// Example:
+//
+//	gql := NewGraphQL(t, value)
+//	gql.NoErrors()
+func (g *GraphQL) NoErrors() *GraphQL {
+	g.chain.enter("NoErrors()")
+	defer g.chain.leave()
+
+	if g.chain.failed() {
+		return g
+	}
+
+	if errs := g.errors(); len(errs) != 0 {
+		g.chain.fail(AssertionFailure{
+			Type:   AssertEmpty,
+			Actual: &AssertionValue{errs},
+			Errors: []error{
+				errors.New("expected: GraphQL response has no errors"),
+			},
+		})
+	}
+
+	return g
+}
+
+func (g *GraphQL) errors() []interface{} {
+	errs, _ := g.value["errors"].([]interface{})
+	if errs == nil {
+		return []interface{}{}
+	}
+	return errs
+}
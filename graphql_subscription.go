@@ -0,0 +1,531 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gorilla/websocket"
+)
+
+// GraphQLSubscriptionOpts defines additional options for
+// Response.GraphQLSubscription.
+type GraphQLSubscriptionOpts struct {
+	// OperationName is sent with subscription query. May be empty.
+	OperationName string
+
+	// InitPayload is sent in "connection_init" message, e.g. to
+	// authenticate connection. May be nil.
+	InitPayload map[string]interface{}
+}
+
+// GraphQLSubscription provides methods to receive events of GraphQL
+// subscription over WebSocket connection.
+//
+// Both graphql-ws protocols are supported: "graphql-transport-ws" (used by
+// graphql-ws library) and legacy "graphql-ws" (used by
+// subscriptions-transport-ws library). Protocol is chosen by WebSocket
+// subprotocol negotiated during handshake; if none, "graphql-transport-ws"
+// is used.
+//
+// Keep-alive and ping messages are handled transparently.
+type GraphQLSubscription struct {
+	chain  *chain
+	ws     *Websocket
+	legacy bool
+	id     string
+	done   bool
+}
+
+const graphQLSubscriptionID = "1"
+
+// GraphQLSubscription is a shorthand for Websocket().GraphQLSubscription().
+//
+// Request should be sent with WithWebsocketUpgrade.
+//
+// Example:
+//
+//	resp := e.GET("/graphql").WithWebsocketUpgrade().
+//	    WithWebsocketSubprotocols("graphql-transport-ws").
+//	    Expect()
+//
+//	sub := resp.GraphQLSubscription(
+//	    `subscription { orderCreated { id total } }`, nil)
+//
+//	e.POST("/orders").WithJSON(order).Expect()
+//
+//	sub.ExpectData(map[string]interface{}{
+//	    "orderCreated": map[string]interface{}{"total": 100},
+//	})
+//	sub.Complete()
+func (r *Response) GraphQLSubscription(
+	query string, variables map[string]interface{}, opts ...GraphQLSubscriptionOpts,
+) *GraphQLSubscription {
+	return r.Websocket().GraphQLSubscription(query, variables, opts...)
+}
+
+// GraphQLSubscription performs graphql-ws handshake over WebSocket
+// connection, starts subscription with given query and variables, and
+// returns a new GraphQLSubscription instance.
+//
+// variables may be nil. GraphQLSubscription fails if server doesn't
+// acknowledge connection.
+//
+// Example:
+//
+//	ws := e.GET("/graphql").WithWebsocketUpgrade().
+//	    WithWebsocketSubprotocols("graphql-transport-ws").
+//	    Expect().
+//	    Websocket()
+//	defer ws.Disconnect()
+//
+//	sub := ws.GraphQLSubscription(`subscription { tick }`, nil,
+//	    httpexpect.GraphQLSubscriptionOpts{
+//	        InitPayload: map[string]interface{}{"token": token},
+//	    })
+//	sub.NextEvent().NoErrors()
+func (c *Websocket) GraphQLSubscription(
+	query string, variables map[string]interface{}, opts ...GraphQLSubscriptionOpts,
+) *GraphQLSubscription {
+	c.chain.enter("GraphQLSubscription()")
+	defer c.chain.leave()
+
+	s := &GraphQLSubscription{
+		chain: c.chain.clone(),
+		ws:    c,
+		id:    graphQLSubscriptionID,
+	}
+
+	if c.checkUnusable("GraphQLSubscription()") {
+		s.chain.setFailed()
+		return s
+	}
+
+	if len(opts) > 1 {
+		s.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple opts arguments"),
+			},
+		})
+		return s
+	}
+
+	if query == "" {
+		s.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected empty query"),
+			},
+		})
+		return s
+	}
+
+	var o GraphQLSubscriptionOpts
+	if len(opts) == 1 {
+		o = opts[0]
+	}
+
+	s.legacy = c.conn.Subprotocol() == "graphql-ws"
+
+	init := map[string]interface{}{
+		"type": "connection_init",
+	}
+	if o.InitPayload != nil {
+		init["payload"] = o.InitPayload
+	}
+
+	if !s.write(s.chain, init) {
+		return s
+	}
+
+	for {
+		msg, ok := s.read(s.chain)
+		if !ok {
+			return s
+		}
+
+		typ, _ := msg["type"].(string)
+
+		if typ == "connection_ack" {
+			break
+		}
+
+		if typ == "connection_error" || typ == "error" {
+			s.chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{msg},
+				Errors: []error{
+					errors.New("expected: GraphQL server acknowledges connection"),
+				},
+			})
+			return s
+		}
+	}
+
+	start := "subscribe"
+	if s.legacy {
+		start = "start"
+	}
+
+	s.write(s.chain, map[string]interface{}{
+		"id":   s.id,
+		"type": start,
+		"payload": graphQLRequest{
+			Query:         query,
+			Variables:     variables,
+			OperationName: o.OperationName,
+		},
+	})
+
+	return s
+}
+
+// NextEvent reads next event of subscription and returns a new GraphQL
+// instance with its payload, i.e. object with "data" and "errors" fields.
+//
+// NextEvent fails if server completes subscription or reports error
+// instead of sending event.
+//
+// Example:
+//
+//	sub := resp.GraphQLSubscription(`subscription { tick }`, nil)
+//	sub.NextEvent().NoErrors().Data().ContainsKey("tick")
+func (s *GraphQLSubscription) NextEvent() *GraphQL {
+	s.chain.enter("NextEvent()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newGraphQL(s.chain, nil)
+	}
+
+	payload, ok := s.nextEvent()
+	if !ok {
+		return newGraphQL(s.chain, nil)
+	}
+
+	return newGraphQL(s.chain, payload)
+}
+
+// ExpectData reads next event of subscription and checks that it has no
+// errors and its data contains given value as a subset, like in
+// Object.ContainsSubset. Returns a new GraphQL instance with event payload.
+//
+// Example:
+//
+//	sub := resp.GraphQLSubscription(`subscription { orderCreated { id } }`, nil)
+//	sub.ExpectData(map[string]interface{}{
+//	    "orderCreated": map[string]interface{}{"id": "1"},
+//	})
+func (s *GraphQLSubscription) ExpectData(partial interface{}) *GraphQL {
+	s.chain.enter("ExpectData()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newGraphQL(s.chain, nil)
+	}
+
+	expected, ok := canonValue(s.chain, partial)
+	if !ok {
+		return newGraphQL(s.chain, nil)
+	}
+
+	payload, ok := s.nextEvent()
+	if !ok {
+		return newGraphQL(s.chain, nil)
+	}
+
+	gql := newGraphQL(s.chain, payload)
+	if gql.chain.failed() {
+		s.chain.setFailed()
+		return gql
+	}
+
+	if errs := gql.errors(); len(errs) != 0 {
+		s.chain.fail(AssertionFailure{
+			Type:   AssertEmpty,
+			Actual: &AssertionValue{errs},
+			Errors: []error{
+				errors.New("expected: GraphQL event has no errors"),
+			},
+		})
+		return newGraphQL(s.chain, nil)
+	}
+
+	if !checkJSONMatch(gql.value["data"], expected) {
+		s.chain.fail(AssertionFailure{
+			Type:     AssertContainsSubset,
+			Actual:   &AssertionValue{gql.value["data"]},
+			Expected: &AssertionValue{expected},
+			Errors: []error{
+				errors.New("expected: GraphQL event data contains subset"),
+			},
+		})
+		return newGraphQL(s.chain, nil)
+	}
+
+	return gql
+}
+
+// ExpectError reads next message of subscription, checks that it is an
+// error message, and returns a new Array instance with GraphQL errors.
+//
+// Example:
+//
+//	sub := resp.GraphQLSubscription(`subscription { unknown }`, nil)
+//	sub.ExpectError().Length().Equal(1)
+func (s *GraphQLSubscription) ExpectError() *Array {
+	s.chain.enter("ExpectError()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newArray(s.chain, nil)
+	}
+
+	msg, ok := s.readOwn()
+	if !ok {
+		return newArray(s.chain, nil)
+	}
+
+	if msg["type"] != "error" {
+		s.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{msg["type"]},
+			Expected: &AssertionValue{"error"},
+			Errors: []error{
+				errors.New("expected: GraphQL subscription error"),
+			},
+		})
+		return newArray(s.chain, nil)
+	}
+
+	s.done = true
+
+	// legacy protocol sends single error object
+	var errs []interface{}
+	switch payload := msg["payload"].(type) {
+	case []interface{}:
+		errs = payload
+	case nil:
+		errs = []interface{}{}
+	default:
+		errs = []interface{}{payload}
+	}
+
+	return newArray(s.chain, errs)
+}
+
+// ExpectComplete reads next message of subscription and checks that
+// server completed subscription.
+//
+// Example:
+//
+//	sub := resp.GraphQLSubscription(`subscription { countdown(from: 1) }`, nil)
+//	sub.ExpectData(map[string]interface{}{"countdown": 1})
+//	sub.ExpectComplete()
+func (s *GraphQLSubscription) ExpectComplete() *GraphQLSubscription {
+	s.chain.enter("ExpectComplete()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	msg, ok := s.readOwn()
+	if !ok {
+		return s
+	}
+
+	if msg["type"] != "complete" {
+		s.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{msg["type"]},
+			Expected: &AssertionValue{"complete"},
+			Errors: []error{
+				errors.New("expected: GraphQL subscription is completed by server"),
+			},
+		})
+		return s
+	}
+
+	s.done = true
+
+	return s
+}
+
+// Complete stops subscription by sending "complete" message (or "stop"
+// message for legacy protocol). It doesn't close WebSocket connection.
+//
+// Example:
+//
+//	sub := resp.GraphQLSubscription(`subscription { tick }`, nil)
+//	sub.NextEvent()
+//	sub.Complete()
+func (s *GraphQLSubscription) Complete() *GraphQLSubscription {
+	s.chain.enter("Complete()")
+	defer s.chain.leave()
+
+	if s.chain.failed() || s.done {
+		return s
+	}
+
+	stop := "complete"
+	if s.legacy {
+		stop = "stop"
+	}
+
+	if s.write(s.chain, map[string]interface{}{
+		"id":   s.id,
+		"type": stop,
+	}) {
+		s.done = true
+	}
+
+	return s
+}
+
+// reads next event payload, failing on error and completion
+func (s *GraphQLSubscription) nextEvent() (interface{}, bool) {
+	msg, ok := s.readOwn()
+	if !ok {
+		return nil, false
+	}
+
+	switch msg["type"] {
+	case "next", "data":
+		return msg["payload"], true
+
+	case "error":
+		s.done = true
+		s.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{msg["payload"]},
+			Errors: []error{
+				errors.New("expected: GraphQL subscription event, got error"),
+			},
+		})
+
+	case "complete":
+		s.done = true
+		s.chain.fail(AssertionFailure{
+			Type: AssertValid,
+			Errors: []error{
+				errors.New("expected: GraphQL subscription event," +
+					" got completion of subscription"),
+			},
+		})
+
+	default:
+		s.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{msg},
+			Errors: []error{
+				errors.New("unexpected GraphQL subscription message"),
+			},
+		})
+	}
+
+	return nil, false
+}
+
+// reads next message of this subscription, skipping keep-alives
+func (s *GraphQLSubscription) readOwn() (map[string]interface{}, bool) {
+	if s.done {
+		s.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected read from completed GraphQL subscription"),
+			},
+		})
+		return nil, false
+	}
+
+	for {
+		msg, ok := s.read(s.chain)
+		if !ok {
+			return nil, false
+		}
+
+		if id, _ := msg["id"].(string); id == s.id {
+			return msg, true
+		}
+	}
+}
+
+// reads next message, skipping keep-alives and answering pings
+func (s *GraphQLSubscription) read(chain *chain) (map[string]interface{}, bool) {
+	for {
+		var m *WebsocketMessage
+
+		s.ws.withChain(chain, func() {
+			if !s.ws.checkUnusable("GraphQLSubscription()") {
+				m = s.ws.readMessage()
+			}
+		})
+
+		if m == nil || chain.failed() {
+			return nil, false
+		}
+
+		if m.typ == websocket.CloseMessage {
+			chain.fail(AssertionFailure{
+				Type: AssertOperation,
+				Errors: []error{
+					fmt.Errorf("connection closed by GraphQL server: %s %q",
+						wsCloseCode(m.closeCode), m.content),
+				},
+			})
+			return nil, false
+		}
+
+		var msg map[string]interface{}
+		if err := json.Unmarshal(m.content, &msg); err != nil {
+			chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{string(m.content)},
+				Errors: []error{
+					errors.New("invalid GraphQL subscription message"),
+					err,
+				},
+			})
+			return nil, false
+		}
+
+		switch msg["type"] {
+		case "ka", "pong":
+			continue
+
+		case "ping":
+			pong := map[string]interface{}{"type": "pong"}
+			if !s.write(chain, pong) {
+				return nil, false
+			}
+			continue
+		}
+
+		return msg, true
+	}
+}
+
+func (s *GraphQLSubscription) write(chain *chain, msg map[string]interface{}) bool {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{msg},
+			Errors: []error{
+				errors.New("invalid json object"),
+				err,
+			},
+		})
+		return false
+	}
+
+	s.ws.withChain(chain, func() {
+		if !s.ws.checkUnusable("GraphQLSubscription()") {
+			s.ws.writeMessage(websocket.TextMessage, b)
+		}
+	})
+
+	return !chain.failed()
+}
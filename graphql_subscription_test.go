@@ -0,0 +1,233 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGraphQLConn(subprotocol string, messages ...string) *mockWebsocketChanConn {
+	conn := newMockWebsocketChanConn(16)
+	conn.subprotocol = subprotocol
+	for _, m := range messages {
+		conn.in <- []byte(m)
+	}
+	close(conn.in)
+	return conn
+}
+
+func writtenGraphQLMessages(
+	t *testing.T, conn *mockWebsocketChanConn,
+) []map[string]interface{} {
+	var messages []map[string]interface{}
+	for len(conn.out) != 0 {
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal(<-conn.out, &m))
+		messages = append(messages, m)
+	}
+	return messages
+}
+
+func TestGraphQLSubscriptionFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	ws := newWebsocket(chain, Config{}, nil)
+
+	sub := ws.GraphQLSubscription(`subscription { tick }`, nil)
+	sub.chain.assertFailed(t)
+
+	sub.NextEvent().chain.assertFailed(t)
+	sub.ExpectData(nil).chain.assertFailed(t)
+	sub.ExpectError().chain.assertFailed(t)
+	sub.ExpectComplete()
+	sub.Complete()
+}
+
+func TestGraphQLSubscriptionTransportWS(t *testing.T) {
+	conn := newGraphQLConn("graphql-transport-ws",
+		`{"type":"ping"}`,
+		`{"type":"connection_ack"}`,
+		`{"id":"1","type":"next","payload":{"data":{"tick":1}}}`,
+		`{"id":"2","type":"next","payload":{"data":{"other":1}}}`,
+		`{"type":"ping"}`,
+		`{"id":"1","type":"next","payload":{"data":{"tick":2,"at":"now"}}}`,
+		`{"id":"1","type":"next","payload":{"data":{"tick":3}}}`,
+	)
+
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, conn)
+
+	sub := ws.GraphQLSubscription(`subscription Tick($n: Int) { tick(n: $n) }`,
+		map[string]interface{}{"n": 1},
+		GraphQLSubscriptionOpts{
+			OperationName: "Tick",
+			InitPayload:   map[string]interface{}{"token": "secret"},
+		})
+	sub.chain.assertOK(t)
+
+	sub.NextEvent().NoErrors().Data().ValueEqual("tick", 1)
+	sub.chain.assertOK(t)
+
+	sub.ExpectData(map[string]interface{}{"tick": 2})
+	sub.chain.assertOK(t)
+
+	sub.Complete()
+	sub.chain.assertOK(t)
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"type":    "connection_init",
+			"payload": map[string]interface{}{"token": "secret"},
+		},
+		{
+			"type": "pong",
+		},
+		{
+			"id":   "1",
+			"type": "subscribe",
+			"payload": map[string]interface{}{
+				"query":         `subscription Tick($n: Int) { tick(n: $n) }`,
+				"variables":     map[string]interface{}{"n": float64(1)},
+				"operationName": "Tick",
+			},
+		},
+		{
+			"type": "pong",
+		},
+		{
+			"id":   "1",
+			"type": "complete",
+		},
+	}, writtenGraphQLMessages(t, conn))
+
+	// reading after completion is a usage error
+	sub.NextEvent()
+	sub.chain.assertFailed(t)
+}
+
+func TestGraphQLSubscriptionLegacy(t *testing.T) {
+	conn := newGraphQLConn("graphql-ws",
+		`{"type":"connection_ack"}`,
+		`{"type":"ka"}`,
+		`{"id":"1","type":"data","payload":{"data":{"tick":1}}}`,
+		`{"type":"ka"}`,
+		`{"id":"1","type":"complete"}`,
+	)
+
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, conn)
+
+	sub := ws.GraphQLSubscription(`subscription { tick }`, nil)
+
+	sub.ExpectData(map[string]interface{}{"tick": 1})
+	sub.ExpectComplete()
+	sub.chain.assertOK(t)
+
+	// already completed by server
+	sub.Complete()
+	sub.chain.assertOK(t)
+
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"type": "connection_init",
+		},
+		{
+			"id":   "1",
+			"type": "start",
+			"payload": map[string]interface{}{
+				"query": `subscription { tick }`,
+			},
+		},
+	}, writtenGraphQLMessages(t, conn))
+}
+
+func TestGraphQLSubscriptionErrors(t *testing.T) {
+	t.Run("connection error", func(t *testing.T) {
+		conn := newGraphQLConn("graphql-ws",
+			`{"type":"connection_error","payload":{"message":"denied"}}`,
+		)
+
+		ws := NewWebsocket(Config{Reporter: newMockReporter(t)}, conn)
+
+		sub := ws.GraphQLSubscription(`subscription { tick }`, nil)
+		sub.chain.assertFailed(t)
+	})
+
+	t.Run("connection closed", func(t *testing.T) {
+		conn := newGraphQLConn("")
+
+		ws := NewWebsocket(Config{Reporter: newMockReporter(t)}, conn)
+
+		sub := ws.GraphQLSubscription(`subscription { tick }`, nil)
+		sub.chain.assertFailed(t)
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		conn := newGraphQLConn("", `not json`)
+
+		ws := NewWebsocket(Config{Reporter: newMockReporter(t)}, conn)
+
+		sub := ws.GraphQLSubscription(`subscription { tick }`, nil)
+		sub.chain.assertFailed(t)
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		conn := newGraphQLConn("")
+
+		ws := NewWebsocket(Config{Reporter: newMockReporter(t)}, conn)
+
+		sub := ws.GraphQLSubscription("", nil)
+		sub.chain.assertFailed(t)
+	})
+
+	t.Run("error message", func(t *testing.T) {
+		newSub := func(t *testing.T) *GraphQLSubscription {
+			conn := newGraphQLConn("",
+				`{"type":"connection_ack"}`,
+				`{"id":"1","type":"error","payload":[{"message":"unknown field"}]}`,
+			)
+			ws := NewWebsocket(Config{Reporter: newMockReporter(t)}, conn)
+			return ws.GraphQLSubscription(`subscription { unknown }`, nil)
+		}
+
+		sub := newSub(t)
+		sub.ExpectError().Equal([]interface{}{
+			map[string]interface{}{"message": "unknown field"},
+		})
+		sub.chain.assertOK(t)
+
+		sub = newSub(t)
+		sub.NextEvent().chain.assertFailed(t)
+		sub.chain.assertFailed(t)
+
+		sub = newSub(t)
+		sub.ExpectComplete()
+		sub.chain.assertFailed(t)
+	})
+
+	t.Run("data mismatch", func(t *testing.T) {
+		newSub := func(t *testing.T, event string) *GraphQLSubscription {
+			conn := newGraphQLConn("", `{"type":"connection_ack"}`, event)
+			ws := NewWebsocket(Config{Reporter: newMockReporter(t)}, conn)
+			return ws.GraphQLSubscription(`subscription { tick }`, nil)
+		}
+
+		sub := newSub(t, `{"id":"1","type":"next","payload":{"data":{"tick":1}}}`)
+		sub.ExpectData(map[string]interface{}{"tick": 2})
+		sub.chain.assertFailed(t)
+
+		sub = newSub(t,
+			`{"id":"1","type":"next","payload":{"data":null,"errors":[{"message":"x"}]}}`)
+		sub.ExpectData(map[string]interface{}{"tick": 1})
+		sub.chain.assertFailed(t)
+
+		sub = newSub(t, `{"id":"1","type":"complete"}`)
+		sub.ExpectData(map[string]interface{}{"tick": 1})
+		sub.chain.assertFailed(t)
+	})
+}
@@ -0,0 +1,94 @@
+package httpexpect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphQLFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newGraphQL(chain, map[string]interface{}{"data": nil})
+
+	value.chain.assertFailed(t)
+
+	assert.NotNil(t, value.Data())
+	assert.NotNil(t, value.Errors())
+	assert.NotNil(t, value.Extensions())
+
+	value.HasErrors()
+	value.NoErrors()
+}
+
+func TestGraphQLInvalid(t *testing.T) {
+	cases := []struct {
+		name  string
+		value interface{}
+	}{
+		{"nil", nil},
+		{"array", []interface{}{}},
+		{"no fields", map[string]interface{}{"foo": "bar"}},
+		{"bad errors", map[string]interface{}{"errors": "bad"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			NewGraphQL(newMockReporter(t), tc.value).chain.assertFailed(t)
+		})
+	}
+}
+
+func TestGraphQLData(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewGraphQL(reporter, map[string]interface{}{
+		"data": map[string]interface{}{
+			"user": map[string]interface{}{"name": "john"},
+		},
+		"extensions": map[string]interface{}{"cost": 1},
+	})
+
+	value.chain.assertOK(t)
+
+	value.Data().Value("user").Object().Value("name").
+		String().Equal("john").chain.assertOK(t)
+
+	value.Errors().Empty().chain.assertOK(t)
+	value.Extensions().ContainsKey("cost").chain.assertOK(t)
+
+	value.NoErrors()
+	value.chain.assertOK(t)
+
+	value.HasErrors()
+	value.chain.assertFailed(t)
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewGraphQL(reporter, map[string]interface{}{
+		"data": nil,
+		"errors": []interface{}{
+			map[string]interface{}{"message": "not found"},
+		},
+	})
+
+	value.chain.assertOK(t)
+
+	value.Errors().Length().Equal(1).chain.assertOK(t)
+	value.Errors().Element(0).Object().Value("message").
+		String().Equal("not found").chain.assertOK(t)
+
+	value.Extensions().Empty().chain.assertOK(t)
+
+	value.Data().chain.assertFailed(t)
+	value.chain.reset()
+
+	value.HasErrors()
+	value.chain.assertOK(t)
+
+	value.NoErrors()
+	value.chain.assertFailed(t)
+}
@@ -0,0 +1,629 @@
+package httpexpect
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GRPCCode is a gRPC status code.
+type GRPCCode int
+
+// gRPC status codes, as defined by gRPC specification.
+const (
+	GRPCCodeOK                 GRPCCode = 0
+	GRPCCodeCanceled           GRPCCode = 1
+	GRPCCodeUnknown            GRPCCode = 2
+	GRPCCodeInvalidArgument    GRPCCode = 3
+	GRPCCodeDeadlineExceeded   GRPCCode = 4
+	GRPCCodeNotFound           GRPCCode = 5
+	GRPCCodeAlreadyExists      GRPCCode = 6
+	GRPCCodePermissionDenied   GRPCCode = 7
+	GRPCCodeResourceExhausted  GRPCCode = 8
+	GRPCCodeFailedPrecondition GRPCCode = 9
+	GRPCCodeAborted            GRPCCode = 10
+	GRPCCodeOutOfRange         GRPCCode = 11
+	GRPCCodeUnimplemented      GRPCCode = 12
+	GRPCCodeInternal           GRPCCode = 13
+	GRPCCodeUnavailable        GRPCCode = 14
+	GRPCCodeDataLoss           GRPCCode = 15
+	GRPCCodeUnauthenticated    GRPCCode = 16
+)
+
+var grpcCodeNames = []string{
+	"OK",
+	"Canceled",
+	"Unknown",
+	"InvalidArgument",
+	"DeadlineExceeded",
+	"NotFound",
+	"AlreadyExists",
+	"PermissionDenied",
+	"ResourceExhausted",
+	"FailedPrecondition",
+	"Aborted",
+	"OutOfRange",
+	"Unimplemented",
+	"Internal",
+	"Unavailable",
+	"DataLoss",
+	"Unauthenticated",
+}
+
+func (c GRPCCode) String() string {
+	if c >= 0 && int(c) < len(grpcCodeNames) {
+		return grpcCodeNames[c]
+	}
+	return "Code(" + strconv.Itoa(int(c)) + ")"
+}
+
+// GRPCCall is a gRPC call builder.
+//
+// gRPC call is sent as HTTP/2 request through the same pipeline as other
+// requests, so it uses the same Client, Reporter, AssertionHandler,
+// printers, and other settings. Client should support HTTP/2, e.g. by
+// setting Config.Protocol to ProtocolH2C or ProtocolHTTP2.
+//
+// Messages are encoded and decoded using Config.ProtoCodec.
+//
+// Unary, server streaming, and client streaming calls are supported.
+// Bidirectional streaming calls are supported in half-duplex mode: all
+// request messages are sent before response messages are received.
+type GRPCCall struct {
+	chain    *chain
+	config   Config
+	req      *Request
+	messages [][]byte
+}
+
+// GRPC returns a new GRPCCall instance for given full method name,
+// e.g. "/helloworld.Greeter/SayHello". Request path is built from
+// Config.BaseURL and method name.
+//
+// Example:
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:    server.URL,
+//	    Reporter:   httpexpect.NewAssertReporter(t),
+//	    Protocol:   httpexpect.ProtocolH2C,
+//	    ProtoCodec: protoCodec{},
+//	})
+//
+//	var reply pb.HelloReply
+//
+//	e.GRPC("/helloworld.Greeter/SayHello").
+//	    WithMessage(&pb.HelloRequest{Name: "john"}).
+//	    Expect().
+//	    Status(httpexpect.GRPCCodeOK).
+//	    Message(&reply).Object().ValueEqual("message", "Hello john")
+func (e *Expect) GRPC(method string) *GRPCCall {
+	e.chain.enter("GRPC(%q)", method)
+	defer e.chain.leave()
+
+	if !strings.HasPrefix(method, "/") {
+		method = "/" + method
+	}
+
+	req := e.Request("POST", method).
+		WithoutSpecValidation().
+		WithHeader("Content-Type", "application/grpc").
+		WithHeader("TE", "trailers")
+
+	return &GRPCCall{
+		chain:  req.chain,
+		config: e.config,
+		req:    req,
+	}
+}
+
+// Request returns underlying Request instance, which may be used to
+// customize the call, e.g. to set context or client.
+func (c *GRPCCall) Request() *Request {
+	return c.req
+}
+
+// WithMessage encodes request message using Config.ProtoCodec and adds it
+// to the call. Unary call should have exactly one message; client streaming
+// call may have any number of messages.
+//
+// Example:
+//
+//	call := e.GRPC("/routeguide.RouteGuide/RecordRoute")
+//	call.WithMessage(&pb.Point{Latitude: 1, Longitude: 2})
+//	call.WithMessage(&pb.Point{Latitude: 3, Longitude: 4})
+func (c *GRPCCall) WithMessage(msg interface{}) *GRPCCall {
+	c.chain.enter("WithMessage()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	if msg == nil {
+		c.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil argument"),
+			},
+		})
+		return c
+	}
+
+	b, err := c.codec().Encode(msg)
+	if err != nil {
+		c.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{msg},
+			Errors: []error{
+				errors.New("invalid protobuf message"),
+				err,
+			},
+		})
+		return c
+	}
+
+	c.messages = append(c.messages, b)
+
+	return c
+}
+
+// WithMetadata adds metadata key and value to the call. Metadata is sent
+// as request header. Keys ending with "-bin" are base64-encoded by caller.
+//
+// Example:
+//
+//	call := e.GRPC("/helloworld.Greeter/SayHello")
+//	call.WithMetadata("authorization", "Bearer token")
+func (c *GRPCCall) WithMetadata(key, value string) *GRPCCall {
+	c.chain.enter("WithMetadata(%q)", key)
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	c.req.WithHeader(key, value)
+
+	return c
+}
+
+// WithTimeout sets call deadline, which is sent to server in
+// "grpc-timeout" header and is also used as request timeout.
+//
+// Example:
+//
+//	call := e.GRPC("/helloworld.Greeter/SayHello")
+//	call.WithTimeout(time.Second)
+func (c *GRPCCall) WithTimeout(timeout time.Duration) *GRPCCall {
+	c.chain.enter("WithTimeout()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	if timeout <= 0 {
+		c.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{timeout},
+			Errors: []error{
+				errors.New("unexpected non-positive timeout"),
+			},
+		})
+		return c
+	}
+
+	c.req.WithHeader("Grpc-Timeout", grpcTimeout(timeout))
+	c.req.WithTimeout(timeout)
+
+	return c
+}
+
+// at most 8 digits are allowed, so choose the most precise unit that fits
+func grpcTimeout(d time.Duration) string {
+	units := []struct {
+		unit time.Duration
+		name string
+	}{
+		{time.Nanosecond, "n"},
+		{time.Microsecond, "u"},
+		{time.Millisecond, "m"},
+		{time.Second, "S"},
+		{time.Minute, "M"},
+		{time.Hour, "H"},
+	}
+
+	for _, u := range units {
+		// round up, so that deadline is never shorter than requested
+		v := (d + u.unit - 1) / u.unit
+		if v < 100000000 {
+			return strconv.FormatInt(int64(v), 10) + u.name
+		}
+	}
+
+	return "99999999H"
+}
+
+// Expect sends the call and returns GRPCResponse instance.
+//
+// Expect fails if HTTP request fails, if HTTP response is not a valid
+// gRPC response, or if response messages can't be decoded from frames.
+// Non-OK gRPC status is not a failure; use GRPCResponse.Status to
+// check it.
+func (c *GRPCCall) Expect() *GRPCResponse {
+	var body bytes.Buffer
+	for _, msg := range c.messages {
+		var prefix [5]byte
+		binary.BigEndian.PutUint32(prefix[1:], uint32(len(msg)))
+		body.Write(prefix[:])
+		body.Write(msg)
+	}
+
+	resp := c.req.WithBytes(body.Bytes()).Expect()
+
+	r := newGRPCResponse(resp, c.codec())
+
+	if !r.chain.failed() {
+		r.parse()
+	}
+
+	return r
+}
+
+func (c *GRPCCall) codec() ProtoCodec {
+	if c.config.ProtoCodec == nil {
+		return DefaultProtoCodec{}
+	}
+	return c.config.ProtoCodec
+}
+
+// GRPCResponse provides methods to inspect result of gRPC call.
+type GRPCResponse struct {
+	chain    *chain
+	resp     *Response
+	codec    ProtoCodec
+	code     GRPCCode
+	message  string
+	messages [][]byte
+}
+
+func newGRPCResponse(resp *Response, codec ProtoCodec) *GRPCResponse {
+	return &GRPCResponse{
+		chain: resp.chain.clone(),
+		resp:  resp,
+		codec: codec,
+	}
+}
+
+// decodes status and message frames from HTTP response
+func (r *GRPCResponse) parse() {
+	httpResp := r.resp.httpResp
+
+	if httpResp.StatusCode != 200 {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{statusCodeText(httpResp.StatusCode)},
+			Expected: &AssertionValue{statusCodeText(200)},
+			Errors: []error{
+				errors.New("expected: gRPC response has HTTP status 200"),
+			},
+		})
+		return
+	}
+
+	// trailers-only responses carry status in headers
+	status := httpResp.Trailer.Get("Grpc-Status")
+	message := httpResp.Trailer.Get("Grpc-Message")
+	if status == "" {
+		status = httpResp.Header.Get("Grpc-Status")
+		message = httpResp.Header.Get("Grpc-Message")
+	}
+
+	code, err := strconv.Atoi(status)
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{status},
+			Errors: []error{
+				errors.New(`expected: valid "grpc-status" trailer`),
+			},
+		})
+		return
+	}
+
+	r.code = GRPCCode(code)
+
+	if unescaped, err := url.PathUnescape(message); err == nil {
+		message = unescaped
+	}
+	r.message = message
+
+	content := r.resp.content
+
+	for len(content) != 0 {
+		if len(content) < 5 {
+			r.failFrame(errors.New("truncated frame header"))
+			return
+		}
+
+		compressed := content[0] != 0
+		size := binary.BigEndian.Uint32(content[1:5])
+
+		if uint64(len(content)-5) < uint64(size) {
+			r.failFrame(fmt.Errorf("truncated frame: expected %d bytes, got %d",
+				size, len(content)-5))
+			return
+		}
+
+		msg := content[5 : 5+size]
+		content = content[5+size:]
+
+		if compressed {
+			encoding := httpResp.Header.Get("Grpc-Encoding")
+			if encoding != "gzip" {
+				r.failFrame(fmt.Errorf("unsupported grpc-encoding %q", encoding))
+				return
+			}
+
+			zr, err := gzip.NewReader(bytes.NewReader(msg))
+			if err == nil {
+				msg, err = ioutil.ReadAll(zr)
+			}
+			if err != nil {
+				r.failFrame(err)
+				return
+			}
+		}
+
+		r.messages = append(r.messages, msg)
+	}
+}
+
+func (r *GRPCResponse) failFrame(err error) {
+	r.chain.fail(AssertionFailure{
+		Type:   AssertValid,
+		Actual: &AssertionValue{r.resp.content},
+		Errors: []error{
+			errors.New("expected: valid gRPC message frames"),
+			err,
+		},
+	})
+}
+
+// Raw returns underlying encoded response messages.
+func (r *GRPCResponse) Raw() [][]byte {
+	return r.messages
+}
+
+// Response returns underlying HTTP Response instance.
+//
+// Example:
+//
+//	resp := e.GRPC("/helloworld.Greeter/SayHello").WithMessage(req).Expect()
+//	resp.Response().ProtocolVersion().Equal("HTTP/2.0")
+func (r *GRPCResponse) Response() *Response {
+	return r.resp
+}
+
+// Status succeeds if call has given gRPC status code.
+//
+// Example:
+//
+//	resp := e.GRPC("/helloworld.Greeter/SayHello").WithMessage(req).Expect()
+//	resp.Status(httpexpect.GRPCCodeNotFound)
+func (r *GRPCResponse) Status(code GRPCCode) *GRPCResponse {
+	r.chain.enter("Status()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	if r.code != code {
+		errs := []error{
+			errors.New("expected: gRPC status codes are equal"),
+		}
+		if r.message != "" {
+			errs = append(errs, fmt.Errorf("grpc-message: %s", r.message))
+		}
+
+		r.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{r.code.String()},
+			Expected: &AssertionValue{code.String()},
+			Errors:   errs,
+		})
+	}
+
+	return r
+}
+
+// StatusMessage returns a new String instance with gRPC status message.
+//
+// Example:
+//
+//	resp := e.GRPC("/helloworld.Greeter/SayHello").WithMessage(req).Expect()
+//	resp.StatusMessage().Contains("not found")
+func (r *GRPCResponse) StatusMessage() *String {
+	r.chain.enter("StatusMessage()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newString(r.chain, "")
+	}
+
+	return newString(r.chain, r.message)
+}
+
+// Header returns a new String instance with given header metadata.
+//
+// Example:
+//
+//	resp := e.GRPC("/helloworld.Greeter/SayHello").WithMessage(req).Expect()
+//	resp.Header("x-request-id").NotEmpty()
+func (r *GRPCResponse) Header(key string) *String {
+	r.chain.enter("Header(%q)", key)
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newString(r.chain, "")
+	}
+
+	return newString(r.chain, r.resp.httpResp.Header.Get(key))
+}
+
+// Trailer returns a new String instance with given trailer metadata.
+//
+// Example:
+//
+//	resp := e.GRPC("/helloworld.Greeter/SayHello").WithMessage(req).Expect()
+//	resp.Trailer("x-elapsed").NotEmpty()
+func (r *GRPCResponse) Trailer(key string) *String {
+	r.chain.enter("Trailer(%q)", key)
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newString(r.chain, "")
+	}
+
+	return newString(r.chain, r.resp.httpResp.Trailer.Get(key))
+}
+
+// Message decodes the single response message into target using
+// Config.ProtoCodec, and returns a new Value instance with JSON
+// representation of the message.
+//
+// Message fails if response has no messages or more than one message.
+//
+// Example:
+//
+//	var reply pb.HelloReply
+//
+//	resp := e.GRPC("/helloworld.Greeter/SayHello").WithMessage(req).Expect()
+//	resp.Message(&reply).Object().ValueEqual("message", "Hello john")
+func (r *GRPCResponse) Message(target interface{}) *Value {
+	r.chain.enter("Message()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newValue(r.chain, nil)
+	}
+
+	if target == nil {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil argument"),
+			},
+		})
+		return newValue(r.chain, nil)
+	}
+
+	if len(r.messages) != 1 {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{len(r.messages)},
+			Expected: &AssertionValue{1},
+			Errors: []error{
+				errors.New("expected: response has exactly one message"),
+			},
+		})
+		return newValue(r.chain, nil)
+	}
+
+	value, ok := r.decode(r.messages[0], target)
+	if !ok {
+		return newValue(r.chain, nil)
+	}
+
+	return newValue(r.chain, value)
+}
+
+// Messages decodes all response messages using Config.ProtoCodec, and
+// returns a new Array instance with JSON representation of the messages.
+// newTarget is invoked for every message and should return a new empty
+// message of expected type.
+//
+// Example:
+//
+//	resp := e.GRPC("/routeguide.RouteGuide/ListFeatures").WithMessage(rect).
+//	    Expect()
+//
+//	resp.Messages(func() interface{} {
+//	    return &pb.Feature{}
+//	}).Length().Equal(3)
+func (r *GRPCResponse) Messages(newTarget func() interface{}) *Array {
+	r.chain.enter("Messages()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newArray(r.chain, nil)
+	}
+
+	if newTarget == nil {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil argument"),
+			},
+		})
+		return newArray(r.chain, nil)
+	}
+
+	values := make([]interface{}, 0, len(r.messages))
+
+	for _, msg := range r.messages {
+		value, ok := r.decode(msg, newTarget())
+		if !ok {
+			return newArray(r.chain, nil)
+		}
+		values = append(values, value)
+	}
+
+	return newArray(r.chain, values)
+}
+
+// decodes message into target and converts it to JSON value
+func (r *GRPCResponse) decode(msg []byte, target interface{}) (interface{}, bool) {
+	err := r.codec.Decode(msg, target)
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{msg},
+			Errors: []error{
+				errors.New("failed to decode protobuf message"),
+				err,
+			},
+		})
+		return nil, false
+	}
+
+	b, err := r.codec.EncodeJSON(target)
+
+	var value interface{}
+	if err == nil {
+		err = json.Unmarshal(b, &value)
+	}
+
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{target},
+			Errors: []error{
+				errors.New("failed to convert protobuf message to JSON"),
+				err,
+			},
+		})
+		return nil, false
+	}
+
+	return value, true
+}
@@ -0,0 +1,272 @@
+package httpexpect
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func grpcFrame(msg []byte, compressed bool) []byte {
+	var prefix [5]byte
+	if compressed {
+		prefix[0] = 1
+	}
+	binary.BigEndian.PutUint32(prefix[1:], uint32(len(msg)))
+	return append(prefix[:], msg...)
+}
+
+func grpcMessage(name string) []byte {
+	b, _ := (&mockProtoMessage{Name: name}).Marshal()
+	return b
+}
+
+// minimal gRPC server, using mockProtoMessage for all messages
+func createGRPCHandler(t *testing.T) http.Handler {
+	return h2c.NewHandler(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "application/grpc", r.Header.Get("Content-Type"))
+			assert.Equal(t, "trailers", r.Header.Get("TE"))
+
+			body, _ := ioutil.ReadAll(r.Body)
+
+			var names []string
+			for len(body) >= 5 {
+				size := binary.BigEndian.Uint32(body[1:5])
+				var msg mockProtoMessage
+				require.NoError(t, msg.Unmarshal(body[5:5+size]))
+				names = append(names, msg.Name)
+				body = body[5+size:]
+			}
+
+			w.Header().Set("Content-Type", "application/grpc")
+
+			switch r.URL.Path {
+			case "/test.Greeter/SayHello":
+				w.Header().Set("Trailer", "Grpc-Status, Grpc-Message, X-Elapsed")
+				w.Header().Set("X-Request-Id", r.Header.Get("X-Request-Id"))
+				_, _ = w.Write(grpcFrame(grpcMessage("Hello "+names[0]), false))
+				w.Header().Set("Grpc-Status", "0")
+				w.Header().Set("X-Elapsed", "1ms")
+
+			case "/test.Greeter/SayHelloStream":
+				w.Header().Set("Trailer", "Grpc-Status")
+				for _, greeting := range []string{"Hello", "Hi", "Hey"} {
+					_, _ = w.Write(grpcFrame(grpcMessage(greeting+" "+names[0]), false))
+				}
+				w.Header().Set("Grpc-Status", "0")
+
+			case "/test.Greeter/CountNames":
+				w.Header().Set("Trailer", "Grpc-Status")
+				_, _ = w.Write(grpcFrame(grpcMessage(string(rune('0'+len(names)))), false))
+				w.Header().Set("Grpc-Status", "0")
+
+			case "/test.Greeter/SayHelloGzip":
+				var buf bytes.Buffer
+				zw := gzip.NewWriter(&buf)
+				_, _ = zw.Write(grpcMessage("Hello " + names[0]))
+				_ = zw.Close()
+
+				w.Header().Set("Grpc-Encoding", "gzip")
+				w.Header().Set("Trailer", "Grpc-Status")
+				_, _ = w.Write(grpcFrame(buf.Bytes(), true))
+				w.Header().Set("Grpc-Status", "0")
+
+			case "/test.Greeter/Deadline":
+				w.Header().Set("Grpc-Status", "0")
+				w.Header().Set("X-Timeout", r.Header.Get("Grpc-Timeout"))
+
+			case "/test.Greeter/Truncated":
+				w.Header().Set("Trailer", "Grpc-Status")
+				_, _ = w.Write(grpcFrame(grpcMessage("Hello"), false)[:4])
+				w.Header().Set("Grpc-Status", "0")
+
+			default:
+				// trailers-only response
+				w.Header().Set("Grpc-Status", "12")
+				w.Header().Set("Grpc-Message", "unknown%20method")
+			}
+		}), &http2.Server{})
+}
+
+func TestGRPCTimeout(t *testing.T) {
+	assert.Equal(t, "100000u", grpcTimeout(100*time.Millisecond))
+	assert.Equal(t, "1000n", grpcTimeout(time.Microsecond))
+	assert.Equal(t, "100001u", grpcTimeout(100*time.Millisecond+time.Nanosecond))
+	assert.Equal(t, "3600000m", grpcTimeout(time.Hour))
+	assert.Equal(t, "1000000S", grpcTimeout(1000000*time.Second))
+}
+
+func TestGRPCCode(t *testing.T) {
+	assert.Equal(t, "OK", GRPCCodeOK.String())
+	assert.Equal(t, "Unauthenticated", GRPCCodeUnauthenticated.String())
+	assert.Equal(t, "Code(42)", GRPCCode(42).String())
+}
+
+func TestGRPCExpect(t *testing.T) {
+	server := httptest.NewServer(createGRPCHandler(t))
+	defer server.Close()
+
+	e := WithConfig(Config{
+		BaseURL:  server.URL,
+		Reporter: newMockReporter(t),
+		Protocol: ProtocolH2C,
+	})
+
+	newMessage := func() interface{} {
+		return &mockProtoMessage{}
+	}
+
+	t.Run("unary", func(t *testing.T) {
+		var reply mockProtoMessage
+
+		resp := e.GRPC("/test.Greeter/SayHello").
+			WithMessage(&mockProtoMessage{Name: "john"}).
+			WithMetadata("X-Request-Id", "123").
+			Expect()
+
+		resp.Status(GRPCCodeOK)
+		resp.StatusMessage().Empty()
+		resp.Header("X-Request-Id").Equal("123")
+		resp.Trailer("X-Elapsed").Equal("1ms")
+		resp.Message(&reply).Object().ValueEqual("name", "Hello john")
+		resp.Response().ProtocolVersion().Equal("HTTP/2.0")
+
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "Hello john", reply.Name)
+		assert.Equal(t, [][]byte{grpcMessage("Hello john")}, resp.Raw())
+	})
+
+	t.Run("server streaming", func(t *testing.T) {
+		resp := e.GRPC("test.Greeter/SayHelloStream").
+			WithMessage(&mockProtoMessage{Name: "john"}).
+			Expect()
+
+		resp.Status(GRPCCodeOK)
+		resp.Messages(newMessage).Equal([]interface{}{
+			map[string]interface{}{"name": "Hello john"},
+			map[string]interface{}{"name": "Hi john"},
+			map[string]interface{}{"name": "Hey john"},
+		})
+		resp.chain.assertOK(t)
+
+		resp.Message(&mockProtoMessage{})
+		resp.chain.assertFailed(t)
+	})
+
+	t.Run("client streaming", func(t *testing.T) {
+		call := e.GRPC("/test.Greeter/CountNames")
+		for _, name := range []string{"a", "b", "c"} {
+			call.WithMessage(&mockProtoMessage{Name: name})
+		}
+
+		resp := call.Expect()
+
+		resp.Status(GRPCCodeOK)
+		resp.Message(&mockProtoMessage{}).Object().ValueEqual("name", "3")
+		resp.chain.assertOK(t)
+	})
+
+	t.Run("compressed", func(t *testing.T) {
+		resp := e.GRPC("/test.Greeter/SayHelloGzip").
+			WithMessage(&mockProtoMessage{Name: "john"}).
+			Expect()
+
+		resp.Message(&mockProtoMessage{}).Object().ValueEqual("name", "Hello john")
+		resp.chain.assertOK(t)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		resp := e.GRPC("/test.Greeter/Deadline").
+			WithTimeout(time.Second).
+			Expect()
+
+		resp.Status(GRPCCodeOK)
+		resp.Header("X-Timeout").Equal("1000000u")
+		resp.Messages(newMessage).Empty()
+		resp.chain.assertOK(t)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		resp := e.GRPC("/test.Greeter/Unknown").
+			WithMessage(&mockProtoMessage{Name: "john"}).
+			Expect()
+
+		resp.chain.assertOK(t)
+
+		resp.StatusMessage().Equal("unknown method")
+		resp.Status(GRPCCodeUnimplemented)
+		resp.chain.assertOK(t)
+
+		resp.Status(GRPCCodeOK)
+		resp.chain.assertFailed(t)
+	})
+
+	t.Run("truncated frame", func(t *testing.T) {
+		resp := e.GRPC("/test.Greeter/Truncated").Expect()
+
+		resp.chain.assertFailed(t)
+	})
+}
+
+func TestGRPCFailures(t *testing.T) {
+	handler := &mockAssertionHandler{}
+
+	e := WithConfig(Config{
+		BaseURL:          "http://example.com",
+		AssertionHandler: handler,
+		Client: &http.Client{Transport: NewBinder(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			}))},
+	})
+
+	t.Run("http status", func(t *testing.T) {
+		handler.failure = nil
+
+		resp := e.GRPC("/test.Greeter/SayHello").Expect()
+
+		resp.chain.assertFailed(t)
+		require.NotNil(t, handler.failure)
+		assert.Equal(t, AssertEqual, handler.failure.Type)
+	})
+
+	t.Run("nil message", func(t *testing.T) {
+		handler.failure = nil
+
+		call := e.GRPC("/test.Greeter/SayHello").WithMessage(nil)
+
+		call.chain.assertFailed(t)
+		require.NotNil(t, handler.failure)
+		assert.Equal(t, AssertUsage, handler.failure.Type)
+
+		call.Expect().chain.assertFailed(t)
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		handler.failure = nil
+
+		call := e.GRPC("/test.Greeter/SayHello").WithMessage("not a message")
+
+		call.chain.assertFailed(t)
+		require.NotNil(t, handler.failure)
+		assert.Equal(t, AssertValid, handler.failure.Type)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		call := e.GRPC("/test.Greeter/SayHello").WithTimeout(0)
+
+		call.chain.assertFailed(t)
+	})
+}
@@ -0,0 +1,330 @@
+package httpexpect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// HTML provides methods to inspect parsed HTML document.
+//
+// HTML instance holds a set of elements: initially it's the document
+// itself, and Select returns a new instance with elements matched by
+// CSS selector. Methods like Text and Attribute inspect the first element
+// of the set.
+type HTML struct {
+	chain *chain
+	root  *html.Node
+	nodes []*html.Node
+}
+
+// NewHTML returns a new HTML instance.
+//
+// reporter should not be nil. data is parsed as HTML5 document, which
+// never fails for malformed markup, like in a browser.
+//
+// Example:
+//
+//	doc := NewHTML(t, []byte(`<title>Home</title><div class="error"></div>`))
+//	doc.Title().Equal("Home")
+//	doc.Select("div.error").Count().Equal(1)
+func NewHTML(reporter Reporter, data []byte) *HTML {
+	return newHTML(newChainWithDefaults("HTML()", reporter), data)
+}
+
+func newHTML(parent *chain, data []byte) *HTML {
+	h := &HTML{chain: parent.clone()}
+
+	if h.chain.failed() {
+		return h
+	}
+
+	root, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		h.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{string(data)},
+			Errors: []error{
+				errors.New("failed to decode html"),
+				err,
+			},
+		})
+		return h
+	}
+
+	h.root = root
+	h.nodes = []*html.Node{root}
+
+	return h
+}
+
+func (h *HTML) derive(nodes []*html.Node) *HTML {
+	return &HTML{chain: h.chain.clone(), root: h.root, nodes: nodes}
+}
+
+// Raw returns text content of elements attached to HTML.
+//
+// Example:
+//
+//	doc := NewHTML(t, []byte(`<ul><li>a</li><li>b</li></ul>`))
+//	assert.Equal(t, []string{"a", "b"}, doc.Select("li").Raw())
+func (h *HTML) Raw() []string {
+	values := make([]string, 0, len(h.nodes))
+	for _, node := range h.nodes {
+		values = append(values, htmlText(node))
+	}
+	return values
+}
+
+// Select returns a new HTML instance with descendants of current elements
+// matched by given CSS selector.
+//
+// Supported subset of CSS selectors:
+//   - "div", "*", "#id", ".class"
+//   - "[attr]", "[attr=value]", "[attr~=value]", "[attr|=value]",
+//     "[attr^=value]", "[attr$=value]", "[attr*=value]"
+//   - ":first-child", ":last-child", ":only-child", ":nth-child(n)", ":empty"
+//   - combinators "a b", "a > b", "a + b", "a ~ b", and groups "a, b"
+//
+// Empty result is not a failure; use Exists or Count to check it.
+//
+// Example:
+//
+//	doc := NewHTML(t, data)
+//	doc.Select("form#login input[type=password]").Exists()
+func (h *HTML) Select(selector string) *HTML {
+	h.chain.enter("Select(%q)", selector)
+	defer h.chain.leave()
+
+	if h.chain.failed() {
+		return h.derive(nil)
+	}
+
+	sel, err := parseCSS(selector)
+	if err != nil {
+		h.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{selector},
+			Errors: []error{
+				errors.New("invalid css selector"),
+				err,
+			},
+		})
+		return h.derive(nil)
+	}
+
+	return h.derive(sel.selectFrom(h.nodes))
+}
+
+// Count returns a new Number instance with number of elements.
+//
+// Example:
+//
+//	doc := NewHTML(t, data)
+//	doc.Select("div.error").Count().Equal(0)
+func (h *HTML) Count() *Number {
+	h.chain.enter("Count()")
+	defer h.chain.leave()
+
+	if h.chain.failed() {
+		return newNumber(h.chain, 0)
+	}
+
+	return newNumber(h.chain, float64(len(h.nodes)))
+}
+
+// Element returns a new HTML instance with element at given index.
+//
+// If index is out of bounds, Element reports failure and returns empty
+// (but non-nil) instance.
+//
+// Example:
+//
+//	doc := NewHTML(t, data)
+//	doc.Select("li").Element(1).Text().Equal("second")
+func (h *HTML) Element(index int) *HTML {
+	h.chain.enter("Element(%d)", index)
+	defer h.chain.leave()
+
+	if h.chain.failed() {
+		return h.derive(nil)
+	}
+
+	if index < 0 || index >= len(h.nodes) {
+		h.chain.fail(AssertionFailure{
+			Type:   AssertInRange,
+			Actual: &AssertionValue{index},
+			Expected: &AssertionValue{AssertionRange{
+				Min: 0,
+				Max: len(h.nodes) - 1,
+			}},
+			Errors: []error{
+				errors.New("expected: valid element index"),
+			},
+		})
+		return h.derive(nil)
+	}
+
+	return h.derive(h.nodes[index : index+1])
+}
+
+// Exists succeeds if element set is not empty.
+//
+// Example:
+//
+//	doc := NewHTML(t, data)
+//	doc.Select("nav").Exists()
+func (h *HTML) Exists() *HTML {
+	h.chain.enter("Exists()")
+	defer h.chain.leave()
+
+	if h.chain.failed() {
+		return h
+	}
+
+	if len(h.nodes) == 0 {
+		h.chain.fail(AssertionFailure{
+			Type:   AssertNotEmpty,
+			Actual: &AssertionValue{h.Raw()},
+			Errors: []error{
+				errors.New("expected: html element exists"),
+			},
+		})
+	}
+
+	return h
+}
+
+// NotExists succeeds if element set is empty.
+//
+// Example:
+//
+//	doc := NewHTML(t, data)
+//	doc.Select("div.error").NotExists()
+func (h *HTML) NotExists() *HTML {
+	h.chain.enter("NotExists()")
+	defer h.chain.leave()
+
+	if h.chain.failed() {
+		return h
+	}
+
+	if len(h.nodes) != 0 {
+		h.chain.fail(AssertionFailure{
+			Type:   AssertEmpty,
+			Actual: &AssertionValue{h.Raw()},
+			Errors: []error{
+				errors.New("expected: html element does not exist"),
+			},
+		})
+	}
+
+	return h
+}
+
+// Title returns a new String instance with text of document <title>
+// element, with leading and trailing whitespace removed.
+//
+// Title fails if document has no title.
+//
+// Example:
+//
+//	doc := NewHTML(t, data)
+//	doc.Title().Equal("Sign in")
+func (h *HTML) Title() *String {
+	h.chain.enter("Title()")
+	defer h.chain.leave()
+
+	if h.chain.failed() {
+		return newString(h.chain, "")
+	}
+
+	sel := cssSelector{{compounds: []cssCompound{{tag: "title"}}}}
+
+	titles := sel.selectFrom([]*html.Node{h.root})
+	if len(titles) == 0 {
+		h.chain.fail(AssertionFailure{
+			Type: AssertValid,
+			Errors: []error{
+				errors.New("expected: html document has title"),
+			},
+		})
+		return newString(h.chain, "")
+	}
+
+	return newString(h.chain, strings.TrimSpace(htmlText(titles[0])))
+}
+
+// Text returns a new String instance with text content of the first
+// element, with leading and trailing whitespace removed.
+//
+// Example:
+//
+//	doc := NewHTML(t, data)
+//	doc.Select("h1").Text().Equal("Welcome")
+func (h *HTML) Text() *String {
+	h.chain.enter("Text()")
+	defer h.chain.leave()
+
+	node := h.first()
+	if node == nil {
+		return newString(h.chain, "")
+	}
+
+	return newString(h.chain, strings.TrimSpace(htmlText(node)))
+}
+
+// Attribute returns a new String instance with value of given attribute
+// of the first element.
+//
+// Example:
+//
+//	doc := NewHTML(t, data)
+//	doc.Select("a.next").Attribute("href").Equal("/page/2")
+func (h *HTML) Attribute(name string) *String {
+	h.chain.enter("Attribute(%q)", name)
+	defer h.chain.leave()
+
+	node := h.first()
+	if node == nil {
+		return newString(h.chain, "")
+	}
+
+	value, ok := htmlAttr(node, strings.ToLower(name))
+	if !ok {
+		h.chain.fail(AssertionFailure{
+			Type:     AssertContainsKey,
+			Actual:   &AssertionValue{node.Data},
+			Expected: &AssertionValue{name},
+			Errors: []error{
+				fmt.Errorf("expected: html element has attribute %q", name),
+			},
+		})
+		return newString(h.chain, "")
+	}
+
+	return newString(h.chain, value)
+}
+
+// returns first element or reports failure
+func (h *HTML) first() *html.Node {
+	if h.chain.failed() {
+		return nil
+	}
+
+	if len(h.nodes) == 0 {
+		h.chain.fail(AssertionFailure{
+			Type:   AssertNotEmpty,
+			Actual: &AssertionValue{h.Raw()},
+			Errors: []error{
+				errors.New("expected: css selector matched at least one element"),
+			},
+		})
+		return nil
+	}
+
+	return h.nodes[0]
+}
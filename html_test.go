@@ -0,0 +1,85 @@
+package httpexpect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTMLFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newHTML(chain, []byte(`<p>text</p>`))
+
+	value.chain.assertFailed(t)
+
+	assert.NotNil(t, value.Select("p"))
+	assert.NotNil(t, value.Count())
+	assert.NotNil(t, value.Element(0))
+	assert.NotNil(t, value.Title())
+	assert.NotNil(t, value.Text())
+	assert.NotNil(t, value.Attribute("id"))
+
+	value.Exists()
+	value.NotExists()
+}
+
+func TestHTMLGetters(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewHTML(reporter, []byte(cssTestDocument))
+	value.chain.assertOK(t)
+
+	value.Title().Equal("Shop").chain.assertOK(t)
+
+	items := value.Select("ul.items")
+	items.Exists().chain.assertOK(t)
+
+	li := items.Select("li")
+	li.Count().Equal(3).chain.assertOK(t)
+
+	assert.Equal(t, []string{"apple", "banana", "cherry"}, li.Raw())
+
+	li.Text().Equal("apple").chain.assertOK(t)
+	li.Element(1).Attribute("data-sku").Equal("B-2").chain.assertOK(t)
+	li.Element(1).Attribute("DATA-SKU").Equal("B-2").chain.assertOK(t)
+
+	value.Select("div.error").Count().Equal(0).chain.assertOK(t)
+	value.Select("div.error").NotExists().chain.assertOK(t)
+}
+
+func TestHTMLFailures(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewHTML(reporter, []byte(cssTestDocument))
+
+	check := func(name string, fn func(*HTML) *chain) {
+		t.Run(name, func(t *testing.T) {
+			fn(value.Select("html")).assertFailed(t)
+		})
+	}
+
+	check("selector", func(h *HTML) *chain {
+		return h.Select("li[").chain
+	})
+	check("exists", func(h *HTML) *chain {
+		return h.Select("div.error").Exists().chain
+	})
+	check("not exists", func(h *HTML) *chain {
+		return h.Select("li").NotExists().chain
+	})
+	check("element", func(h *HTML) *chain {
+		return h.Select("li").Element(3).chain
+	})
+	check("text", func(h *HTML) *chain {
+		return h.Select("div.error").Text().chain
+	})
+	check("attribute", func(h *HTML) *chain {
+		return h.Select("li").Attribute("missing").chain
+	})
+
+	t.Run("no title", func(t *testing.T) {
+		NewHTML(reporter, []byte(`<p>text</p>`)).Title().chain.assertFailed(t)
+	})
+}
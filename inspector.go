@@ -0,0 +1,480 @@
+package httpexpect
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Inspector collects executed requests and responses together with
+// assertions made on them, and shows them as HTML page.
+//
+// Every request gets an entry with request and response headers and bodies,
+// timings, and list of passed and failed assertions. Failed entries are
+// expanded and highlighted, which helps to quickly find what went wrong in
+// a flaky test.
+//
+// Inspector can be used in three ways:
+//   - write HTML report to a file using WriteFile, or set Path to rewrite
+//     the file automatically after every request and failure
+//   - serve live web UI using Start, or register Inspector as http.Handler;
+//     the page refreshes automatically while tests are running
+//   - set HTTPEXPECT_INSPECT environment variable, without changing code
+//
+// If HTTPEXPECT_INSPECT is set and Config.Inspector is nil, a process-wide
+// Inspector is used. If the value ends with ".html", it's used as Path;
+// otherwise, it's used as address for Start, and URL of the web UI is
+// printed to stderr:
+//
+//	HTTPEXPECT_INSPECT=report.html go test ./...
+//	HTTPEXPECT_INSPECT=localhost:7777 go test -run TestFlaky ./...
+//
+// Only assertions on requests and responses are collected. Headers and
+// bodies are sanitized using Config.Sanitizers.
+//
+// Inspector is safe for concurrent use.
+//
+// Example:
+//
+//	inspector := httpexpect.NewInspector()
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:   "http://example.com",
+//	    Reporter:  httpexpect.NewAssertReporter(t),
+//	    Inspector: inspector,
+//	})
+//
+//	defer func() {
+//	    if t.Failed() {
+//	        _ = inspector.WriteFile("failed.html")
+//	    }
+//	}()
+type Inspector struct {
+	// If non-empty, HTML report is rewritten to this file after every
+	// executed request and failed assertion.
+	Path string
+
+	// Maximum size of request and response body shown in report.
+	// If zero, 64 KiB is used.
+	MaxBodySize int
+
+	mu        sync.Mutex
+	entries   []*inspectorEntry
+	byRequest map[*Request]*inspectorEntry
+
+	fileMu sync.Mutex
+	server *http.Server
+}
+
+const defaultInspectorMaxBodySize = 64 << 10
+
+type inspectorEntry struct {
+	TestName    string
+	RequestName string
+
+	Method string
+	URL    string
+
+	HaveResponse bool
+	Status       string
+	StartedAt    time.Time
+	Duration     time.Duration
+
+	RequestHead  string
+	RequestBody  string
+	ResponseHead string
+	ResponseBody string
+
+	Assertions []inspectorAssertion
+	Failed     bool
+}
+
+type inspectorAssertion struct {
+	Path    string
+	Success bool
+	Message string
+}
+
+type inspectorPage struct {
+	Refresh int
+	Total   int
+	Failed  int
+	Entries []inspectorEntry
+}
+
+// NewInspector returns a new empty Inspector.
+func NewInspector() *Inspector {
+	return &Inspector{}
+}
+
+// Len returns number of collected entries.
+func (i *Inspector) Len() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return len(i.entries)
+}
+
+// Reset removes all collected entries.
+func (i *Inspector) Reset() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.entries = nil
+	i.byRequest = nil
+}
+
+// WriteHTML writes HTML report to given writer.
+func (i *Inspector) WriteHTML(w io.Writer) error {
+	return inspectorTemplate.Execute(w, i.page(0))
+}
+
+// WriteFile writes HTML report to given file.
+func (i *Inspector) WriteFile(path string) error {
+	var buf bytes.Buffer
+
+	if err := i.WriteHTML(&buf); err != nil {
+		return err
+	}
+
+	i.fileMu.Lock()
+	defer i.fileMu.Unlock()
+
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// ServeHTTP implements http.Handler.
+//
+// Serves HTML report which refreshes itself every few seconds.
+func (i *Inspector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	_ = inspectorTemplate.Execute(w, i.page(2))
+}
+
+// Start starts web UI in background on given address, e.g. "localhost:7777",
+// and returns its URL. Use Close to stop it.
+func (i *Inspector) Start(addr string) (string, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", err
+	}
+
+	server := &http.Server{Handler: i}
+
+	i.mu.Lock()
+	i.server = server
+	i.mu.Unlock()
+
+	go func() {
+		_ = server.Serve(ln)
+	}()
+
+	return "http://" + ln.Addr().String(), nil
+}
+
+// Close stops web UI started by Start.
+func (i *Inspector) Close() error {
+	i.mu.Lock()
+	server := i.server
+	i.server = nil
+	i.mu.Unlock()
+
+	if server == nil {
+		return nil
+	}
+
+	return server.Close()
+}
+
+// records executed request; called from Request.Expect
+func (i *Inspector) record(req *Request, exchange *RecordedExchange) {
+	sanitizers := req.config.Sanitizers
+
+	i.mu.Lock()
+
+	entry := i.entry(req)
+
+	entry.HaveResponse = true
+	entry.Status = statusCodeText(exchange.Response.StatusCode)
+	entry.StartedAt = exchange.StartedAt
+	entry.Duration = exchange.Duration
+
+	httpReq := exchange.Request
+
+	entry.RequestHead = inspectorHead(
+		fmt.Sprintf("%s %s %s", httpReq.Method, httpReq.URL.RequestURI(),
+			inspectorProto(httpReq.Proto)),
+		sanitizeHeader(sanitizers, httpReq.Header))
+	entry.RequestBody = i.body(sanitizeBody(sanitizers,
+		httpReq.Header.Get("Content-Type"), exchange.RequestBody))
+
+	httpResp := exchange.Response
+
+	entry.ResponseHead = inspectorHead(
+		fmt.Sprintf("%s %s", inspectorProto(httpResp.Proto), entry.Status),
+		sanitizeHeader(sanitizers, httpResp.Header))
+	entry.ResponseBody = i.body(exchange.ResponseBody)
+
+	i.mu.Unlock()
+
+	i.update()
+}
+
+// records assertion; called for every assertion on request or response
+func (i *Inspector) assertion(ctx *AssertionContext, failure *AssertionFailure) {
+	if ctx.Request == nil {
+		return
+	}
+
+	// successful assertions are recorded only after response is received,
+	// to skip request builders
+	if failure == nil && ctx.Response == nil {
+		return
+	}
+
+	i.mu.Lock()
+
+	entry := i.entry(ctx.Request)
+
+	assertion := inspectorAssertion{
+		Path:    strings.Join(ctx.Path, "."),
+		Success: failure == nil,
+	}
+
+	if failure != nil {
+		assertion.Message = (&DefaultFormatter{
+			DisableNames: true,
+			DisablePaths: true,
+		}).FormatFailure(ctx, failure)
+
+		entry.Failed = true
+	}
+
+	entry.Assertions = append(entry.Assertions, assertion)
+
+	i.mu.Unlock()
+
+	if failure != nil {
+		i.update()
+	}
+}
+
+// returns entry of request, creating it if needed; called under lock
+func (i *Inspector) entry(req *Request) *inspectorEntry {
+	if i.byRequest == nil {
+		i.byRequest = make(map[*Request]*inspectorEntry)
+	}
+
+	if entry := i.byRequest[req]; entry != nil {
+		return entry
+	}
+
+	entry := &inspectorEntry{
+		TestName:    req.chain.context.TestName,
+		RequestName: req.chain.context.RequestName,
+	}
+
+	if req.httpReq != nil {
+		entry.Method = req.httpReq.Method
+		entry.URL = req.httpReq.URL.String()
+	}
+
+	i.entries = append(i.entries, entry)
+	i.byRequest[req] = entry
+
+	return entry
+}
+
+func (i *Inspector) body(content []byte) string {
+	maxSize := i.MaxBodySize
+	if maxSize <= 0 {
+		maxSize = defaultInspectorMaxBodySize
+	}
+
+	return truncateValue(string(content), maxSize)
+}
+
+func (i *Inspector) update() {
+	if i.Path == "" {
+		return
+	}
+
+	if err := i.WriteFile(i.Path); err != nil {
+		panic(err)
+	}
+}
+
+func (i *Inspector) page(refresh int) *inspectorPage {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	page := &inspectorPage{
+		Refresh: refresh,
+		Total:   len(i.entries),
+	}
+
+	for _, entry := range i.entries {
+		if entry.Failed {
+			page.Failed++
+		}
+
+		e := *entry
+		e.Assertions = append([]inspectorAssertion(nil), entry.Assertions...)
+
+		page.Entries = append(page.Entries, e)
+	}
+
+	return page
+}
+
+func inspectorHead(line string, header http.Header) string {
+	var sb strings.Builder
+
+	sb.WriteString(line)
+	sb.WriteString("\n")
+
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(&sb, "%s: %s\n", key, value)
+		}
+	}
+
+	return sb.String()
+}
+
+func inspectorProto(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+// wraps assertion handler to record assertions to inspector
+type inspectorHandler struct {
+	inspector *Inspector
+	handler   AssertionHandler
+}
+
+func (h *inspectorHandler) Success(ctx *AssertionContext) {
+	h.inspector.assertion(ctx, nil)
+	h.handler.Success(ctx)
+}
+
+func (h *inspectorHandler) Failure(ctx *AssertionContext, failure *AssertionFailure) {
+	h.inspector.assertion(ctx, failure)
+	h.handler.Failure(ctx, failure)
+}
+
+var envInspector struct {
+	once      sync.Once
+	inspector *Inspector
+}
+
+// returns process-wide inspector configured via HTTPEXPECT_INSPECT,
+// or nil if it's not set
+func defaultInspector() *Inspector {
+	envInspector.once.Do(func() {
+		envInspector.inspector = newEnvInspector(os.Getenv("HTTPEXPECT_INSPECT"))
+	})
+
+	return envInspector.inspector
+}
+
+func newEnvInspector(value string) *Inspector {
+	if value == "" {
+		return nil
+	}
+
+	inspector := NewInspector()
+
+	if strings.HasSuffix(value, ".html") {
+		inspector.Path = value
+		return inspector
+	}
+
+	url, err := inspector.Start(value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr,
+			"httpexpect: can't start inspector on %s: %s\n", value, err)
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "httpexpect: inspector is available at %s\n", url)
+
+	return inspector
+}
+
+var inspectorTemplate = template.Must(template.New("inspector").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>httpexpect inspector</title>
+{{- if .Refresh }}
+<meta http-equiv="refresh" content="{{ .Refresh }}">
+{{- end }}
+<style>
+body { font-family: sans-serif; margin: 2em; }
+details { border: 1px solid #ccc; border-radius: 4px; margin: 0.5em 0; padding: 0.5em; }
+details.failed { border-color: #c00; background: #fff5f5; }
+summary { cursor: pointer; }
+pre { background: #f6f6f6; padding: 0.5em; overflow-x: auto; }
+.meta { color: #777; }
+.passed { color: #080; }
+.failed > summary, li.failed { color: #c00; }
+</style>
+</head>
+<body>
+<h1>httpexpect inspector</h1>
+<p>{{ .Total }} requests, {{ .Failed }} failed</p>
+{{- range .Entries }}
+<details class="{{ if .Failed }}failed{{ else }}passed{{ end }}"
+{{- if .Failed }} open{{ end }}>
+<summary>
+{{- if .HaveResponse }}<b>{{ .Status }}</b>{{ else }}<b>no response</b>{{ end }}
+{{ .Method }} {{ .URL }}
+<span class="meta">
+{{- if .HaveResponse }} {{ .Duration }}{{ end }}
+{{- if .TestName }} {{ .TestName }}{{ end }}
+{{- if .RequestName }} ({{ .RequestName }}){{ end }}</span>
+</summary>
+{{- if .HaveResponse }}
+<p class="meta">started at {{ .StartedAt.Format "2006-01-02 15:04:05.000" }}</p>
+{{- end }}
+<h3>Assertions</h3>
+<ul>
+{{- range .Assertions }}
+<li class="{{ if .Success }}passed{{ else }}failed{{ end }}">
+{{- if .Success }}&#10003;{{ else }}&#10007;{{ end }} {{ .Path }}
+{{- if .Message }}<pre>{{ .Message }}</pre>{{ end }}</li>
+{{- end }}
+</ul>
+{{- if .HaveResponse }}
+<h3>Request</h3>
+<pre>{{ .RequestHead }}{{ if .RequestBody }}
+{{ .RequestBody }}{{ end }}</pre>
+<h3>Response</h3>
+<pre>{{ .ResponseHead }}{{ if .ResponseBody }}
+{{ .ResponseBody }}{{ end }}</pre>
+{{- end }}
+</details>
+{{- end }}
+</body>
+</html>
+`))
@@ -0,0 +1,205 @@
+package httpexpect
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspectorExpect(t *testing.T) {
+	inspector := NewInspector()
+
+	e := WithConfig(Config{
+		TestName: "TestFoo",
+		BaseURL:  "http://example.com",
+		Client: &mockClient{
+			resp: http.Response{
+				StatusCode: http.StatusOK,
+			},
+		},
+		Reporter:   newMockReporter(t),
+		Inspector:  inspector,
+		Sanitizers: []Sanitizer{MaskHeaders("Authorization")},
+	})
+
+	e.POST("/users").
+		WithName("create user").
+		WithHeader("Authorization", "Bearer secret").
+		WithText("hello").
+		Expect().
+		Status(http.StatusOK)
+
+	e.GET("/users/{id}", 123).
+		Expect().
+		Status(http.StatusNotFound)
+
+	assert.Equal(t, 2, inspector.Len())
+
+	var buf bytes.Buffer
+	require.NoError(t, inspector.WriteHTML(&buf))
+
+	page := buf.String()
+
+	assert.Contains(t, page, "<p>2 requests, 1 failed</p>")
+	assert.NotContains(t, page, "http-equiv=\"refresh\"")
+
+	assert.Contains(t, page, "POST http://example.com/users")
+	assert.Contains(t, page, "TestFoo (create user)")
+	assert.Contains(t, page, "POST /users HTTP/1.1\nAuthorization: ***\n")
+	assert.NotContains(t, page, "secret")
+	assert.Contains(t, page, "\nhello</pre>")
+	assert.Contains(t, page, "HTTP/1.1 200 OK\n")
+	assert.Contains(t, page, "&#10003; Request(&#34;POST&#34;).Expect().Status()")
+
+	assert.Contains(t, page, `<details class="failed" open>`)
+	assert.Contains(t, page, "GET http://example.com/users/123")
+	assert.Contains(t, page, "&#10007; Request(&#34;GET&#34;).Expect().Status()")
+	assert.Contains(t, page, "&#34;404 Not Found&#34;")
+
+	inspector.Reset()
+
+	assert.Equal(t, 0, inspector.Len())
+}
+
+func TestInspectorNoResponse(t *testing.T) {
+	inspector := NewInspector()
+
+	e := WithConfig(Config{
+		BaseURL: "http://example.com",
+		Client: &mockClient{
+			err: errors.New("connection refused"),
+		},
+		Reporter:  newMockReporter(t),
+		Inspector: inspector,
+	})
+
+	e.GET("/path").Expect()
+
+	var buf bytes.Buffer
+	require.NoError(t, inspector.WriteHTML(&buf))
+
+	page := buf.String()
+
+	assert.Contains(t, page, "<p>1 requests, 1 failed</p>")
+	assert.Contains(t, page, "<b>no response</b>\nGET http://example.com/path")
+	assert.Contains(t, page, "&#10007; Request(&#34;GET&#34;).Expect()")
+	assert.Contains(t, page, "connection refused")
+	assert.NotContains(t, page, "<h3>Response</h3>")
+}
+
+func TestInspectorBodySize(t *testing.T) {
+	inspector := NewInspector()
+	inspector.MaxBodySize = 5
+
+	e := WithConfig(Config{
+		BaseURL: "http://example.com",
+		Client: &mockClient{
+			resp: http.Response{
+				StatusCode: http.StatusOK,
+			},
+		},
+		Reporter:  newMockReporter(t),
+		Inspector: inspector,
+	})
+
+	e.POST("/").WithText("0123456789").Expect()
+
+	var buf bytes.Buffer
+	require.NoError(t, inspector.WriteHTML(&buf))
+
+	assert.Contains(t, buf.String(), "\n01234... (5 more bytes)</pre>")
+	assert.NotContains(t, buf.String(), "0123456789")
+}
+
+func TestInspectorFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	inspector := NewInspector()
+	inspector.Path = filepath.Join(dir, "report.html")
+
+	e := WithConfig(Config{
+		BaseURL: "http://example.com",
+		Client: &mockClient{
+			resp: http.Response{
+				StatusCode: http.StatusOK,
+			},
+		},
+		Reporter:  newMockReporter(t),
+		Inspector: inspector,
+	})
+
+	e.GET("/path").Expect()
+
+	b, err := ioutil.ReadFile(inspector.Path)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(b), "<p>1 requests, 0 failed</p>")
+
+	path := filepath.Join(dir, "copy.html")
+	require.NoError(t, inspector.WriteFile(path))
+
+	b2, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(b), string(b2))
+}
+
+func TestInspectorServe(t *testing.T) {
+	inspector := NewInspector()
+
+	t.Run("handler", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		inspector.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Contains(t, rec.Body.String(),
+			`<meta http-equiv="refresh" content="2">`)
+	})
+
+	t.Run("start", func(t *testing.T) {
+		url, err := inspector.Start("127.0.0.1:0")
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(url, "http://127.0.0.1:"))
+
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		assert.Contains(t, string(body), "<h1>httpexpect inspector</h1>")
+
+		assert.NoError(t, inspector.Close())
+		assert.NoError(t, inspector.Close())
+	})
+}
+
+func TestInspectorEnv(t *testing.T) {
+	assert.Nil(t, newEnvInspector(""))
+
+	inspector := newEnvInspector("report.html")
+	if assert.NotNil(t, inspector) {
+		assert.Equal(t, "report.html", inspector.Path)
+	}
+
+	assert.Nil(t, newEnvInspector("bad address"))
+
+	inspector = newEnvInspector("127.0.0.1:0")
+	if assert.NotNil(t, inspector) {
+		assert.Equal(t, "", inspector.Path)
+		assert.NoError(t, inspector.Close())
+	}
+}
@@ -0,0 +1,474 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// JSONRPC provides methods to inspect JSON-RPC 2.0 response object,
+// i.e. JSON object with "jsonrpc", "id", and either "result" or "error"
+// fields.
+type JSONRPC struct {
+	chain *chain
+	value map[string]interface{}
+}
+
+// NewJSONRPC returns a new JSONRPC instance.
+//
+// reporter should not be nil. value should be decoded JSON-RPC 2.0
+// response object. If it is not, failure is reported.
+//
+// Example:
+//
+//	rpc := NewJSONRPC(t, map[string]interface{}{
+//	    "jsonrpc": "2.0",
+//	    "id":      1,
+//	    "result":  "0x0234c8a3397aab58",
+//	})
+//	rpc.NoError()
+func NewJSONRPC(reporter Reporter, value interface{}) *JSONRPC {
+	return newJSONRPC(newChainWithDefaults("JSONRPC()", reporter), value)
+}
+
+func newJSONRPC(parent *chain, val interface{}) *JSONRPC {
+	j := &JSONRPC{parent.clone(), nil}
+
+	if j.chain.failed() {
+		return j
+	}
+
+	if val, ok := canonValue(j.chain, val); ok {
+		if err := validateJSONRPC(val); err != nil {
+			j.chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{val},
+				Errors: []error{
+					errors.New("expected: valid JSON-RPC 2.0 response object"),
+					err,
+				},
+			})
+			return j
+		}
+
+		j.value = val.(map[string]interface{})
+	}
+
+	return j
+}
+
+func validateJSONRPC(val interface{}) error {
+	obj, ok := val.(map[string]interface{})
+	if !ok {
+		return errors.New("response is not an object")
+	}
+
+	if obj["jsonrpc"] != jsonrpcVersion {
+		return fmt.Errorf(`"jsonrpc" field is not %q`, jsonrpcVersion)
+	}
+
+	id, ok := obj["id"]
+	if !ok {
+		return errors.New(`missing "id" field`)
+	}
+
+	switch id.(type) {
+	case nil, string, float64:
+	default:
+		return errors.New(`"id" field is not string, number, or null`)
+	}
+
+	_, hasResult := obj["result"]
+	rpcErr, hasError := obj["error"]
+
+	if hasResult == hasError {
+		return errors.New(`expected exactly one of "result" and "error" fields`)
+	}
+
+	if !hasError {
+		if id == nil {
+			return errors.New(`"id" field is null in successful response`)
+		}
+		return nil
+	}
+
+	errObj, ok := rpcErr.(map[string]interface{})
+	if !ok {
+		return errors.New(`"error" field is not an object`)
+	}
+
+	code, ok := errObj["code"].(float64)
+	if !ok || code != math.Trunc(code) {
+		return errors.New(`"error.code" field is not an integer`)
+	}
+
+	if _, ok := errObj["message"].(string); !ok {
+		return errors.New(`"error.message" field is not a string`)
+	}
+
+	return nil
+}
+
+// Raw returns underlying value attached to JSONRPC.
+//
+// Example:
+//
+//	rpc := NewJSONRPC(t, value)
+//	assert.Equal(t, value, rpc.Raw())
+func (j *JSONRPC) Raw() map[string]interface{} {
+	return j.value
+}
+
+// ID returns a new Value instance with "id" field of response.
+//
+// Example:
+//
+//	rpc := NewJSONRPC(t, value)
+//	rpc.ID().Number().Equal(1)
+func (j *JSONRPC) ID() *Value {
+	j.chain.enter("ID()")
+	defer j.chain.leave()
+
+	if j.chain.failed() {
+		return newValue(j.chain, nil)
+	}
+
+	return newValue(j.chain, j.value["id"])
+}
+
+// Result returns a new Value instance with "result" field of response.
+//
+// Result fails if response is an error response.
+//
+// Example:
+//
+//	rpc := NewJSONRPC(t, value)
+//	rpc.Result().String().HasPrefix("0x")
+func (j *JSONRPC) Result() *Value {
+	j.chain.enter("Result()")
+	defer j.chain.leave()
+
+	if j.chain.failed() {
+		return newValue(j.chain, nil)
+	}
+
+	if !j.checkNoError() {
+		return newValue(j.chain, nil)
+	}
+
+	return newValue(j.chain, j.value["result"])
+}
+
+// Error returns a new Object instance with "error" field of response,
+// i.e. object with "code", "message", and optional "data" fields.
+//
+// Error fails if response is not an error response.
+//
+// Example:
+//
+//	rpc := NewJSONRPC(t, value)
+//	rpc.Error().Value("message").String().Contains("not found")
+func (j *JSONRPC) Error() *Object {
+	j.chain.enter("Error()")
+	defer j.chain.leave()
+
+	if j.chain.failed() {
+		return newObject(j.chain, nil)
+	}
+
+	if !j.checkHasError() {
+		return newObject(j.chain, nil)
+	}
+
+	return newObject(j.chain, j.value["error"].(map[string]interface{}))
+}
+
+// ErrorCode returns a new Number instance with "error.code" field of
+// response.
+//
+// ErrorCode fails if response is not an error response.
+//
+// Example:
+//
+//	rpc := NewJSONRPC(t, value)
+//	rpc.ErrorCode().Equal(-32601) // method not found
+func (j *JSONRPC) ErrorCode() *Number {
+	j.chain.enter("ErrorCode()")
+	defer j.chain.leave()
+
+	if j.chain.failed() {
+		return newNumber(j.chain, 0)
+	}
+
+	if !j.checkHasError() {
+		return newNumber(j.chain, 0)
+	}
+
+	code := j.value["error"].(map[string]interface{})["code"].(float64)
+
+	return newNumber(j.chain, code)
+}
+
+// HasError succeeds if response is an error response, i.e. has "error"
+// field.
+//
+// Example:
+//
+//	rpc := NewJSONRPC(t, value)
+//	rpc.HasError()
+func (j *JSONRPC) HasError() *JSONRPC {
+	j.chain.enter("HasError()")
+	defer j.chain.leave()
+
+	if j.chain.failed() {
+		return j
+	}
+
+	j.checkHasError()
+
+	return j
+}
+
+// NoError succeeds if response is a successful response, i.e. has
+// "result" field.
+//
+// Example:
+//
+//	rpc := NewJSONRPC(t, value)
+//	rpc.NoError()
+func (j *JSONRPC) NoError() *JSONRPC {
+	j.chain.enter("NoError()")
+	defer j.chain.leave()
+
+	if j.chain.failed() {
+		return j
+	}
+
+	j.checkNoError()
+
+	return j
+}
+
+func (j *JSONRPC) checkHasError() bool {
+	if _, ok := j.value["error"]; !ok {
+		j.chain.fail(AssertionFailure{
+			Type:   AssertContainsKey,
+			Actual: &AssertionValue{j.value},
+			Expected: &AssertionValue{
+				"error",
+			},
+			Errors: []error{
+				errors.New("expected: JSON-RPC error response"),
+			},
+		})
+		return false
+	}
+	return true
+}
+
+func (j *JSONRPC) checkNoError() bool {
+	if rpcErr, ok := j.value["error"]; ok {
+		j.chain.fail(AssertionFailure{
+			Type:   AssertNotContainsKey,
+			Actual: &AssertionValue{rpcErr},
+			Expected: &AssertionValue{
+				"error",
+			},
+			Errors: []error{
+				errors.New("expected: successful JSON-RPC response"),
+			},
+		})
+		return false
+	}
+	return true
+}
+
+// JSONRPCBatch provides methods to inspect JSON-RPC 2.0 batch response,
+// i.e. array of response objects.
+type JSONRPCBatch struct {
+	chain *chain
+	value []interface{}
+}
+
+// NewJSONRPCBatch returns a new JSONRPCBatch instance.
+//
+// reporter should not be nil. value should be decoded JSON-RPC 2.0
+// batch response, i.e. an array of response objects. If it is not,
+// failure is reported.
+//
+// Example:
+//
+//	batch := NewJSONRPCBatch(t, []interface{}{
+//	    map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": 3},
+//	    map[string]interface{}{"jsonrpc": "2.0", "id": 2, "result": 7},
+//	})
+//	batch.Length().Equal(2)
+func NewJSONRPCBatch(reporter Reporter, value interface{}) *JSONRPCBatch {
+	return newJSONRPCBatch(newChainWithDefaults("JSONRPCBatch()", reporter), value)
+}
+
+func newJSONRPCBatch(parent *chain, val interface{}) *JSONRPCBatch {
+	b := &JSONRPCBatch{parent.clone(), nil}
+
+	if b.chain.failed() {
+		return b
+	}
+
+	val, ok := canonValue(b.chain, val)
+	if !ok {
+		return b
+	}
+
+	arr, ok := val.([]interface{})
+	if !ok {
+		b.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{val},
+			Errors: []error{
+				errors.New("expected: JSON-RPC 2.0 batch response is array"),
+			},
+		})
+		return b
+	}
+
+	for n, elem := range arr {
+		if err := validateJSONRPC(elem); err != nil {
+			b.chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{elem},
+				Errors: []error{
+					fmt.Errorf(
+						"expected: valid JSON-RPC 2.0 response object at index %d", n),
+					err,
+				},
+			})
+			return b
+		}
+	}
+
+	b.value = arr
+
+	return b
+}
+
+// Raw returns underlying value attached to JSONRPCBatch.
+//
+// Example:
+//
+//	batch := NewJSONRPCBatch(t, value)
+//	assert.Equal(t, value, batch.Raw())
+func (b *JSONRPCBatch) Raw() []interface{} {
+	return b.value
+}
+
+// Length returns a new Number instance with number of responses in batch.
+//
+// Example:
+//
+//	batch := NewJSONRPCBatch(t, value)
+//	batch.Length().Equal(2)
+func (b *JSONRPCBatch) Length() *Number {
+	b.chain.enter("Length()")
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return newNumber(b.chain, 0)
+	}
+
+	return newNumber(b.chain, float64(len(b.value)))
+}
+
+// Response returns a new JSONRPC instance with response with given id.
+//
+// Responses in batch may be in any order, so they are looked up by id.
+// Response fails if there is no response with given id.
+//
+// Example:
+//
+//	batch := NewJSONRPCBatch(t, value)
+//	batch.Response(1).Result().Number().Equal(3)
+func (b *JSONRPCBatch) Response(id interface{}) *JSONRPC {
+	b.chain.enter("Response(%v)", id)
+	defer b.chain.leave()
+
+	if b.chain.failed() {
+		return newJSONRPC(b.chain, nil)
+	}
+
+	canonID, ok := canonValue(b.chain, id)
+	if !ok {
+		return newJSONRPC(b.chain, nil)
+	}
+
+	for _, elem := range b.value {
+		if elem.(map[string]interface{})["id"] == canonID {
+			return newJSONRPC(b.chain, elem)
+		}
+	}
+
+	b.chain.fail(AssertionFailure{
+		Type:   AssertContainsElement,
+		Actual: &AssertionValue{b.value},
+		Expected: &AssertionValue{
+			id,
+		},
+		Errors: []error{
+			errors.New("expected: batch contains response with given id"),
+		},
+	})
+
+	return newJSONRPC(b.chain, nil)
+}
+
+// checks that batch has exactly one response for every request id
+func (b *JSONRPCBatch) checkIDs(ids []interface{}) {
+	seen := map[interface{}]bool{}
+
+	for _, elem := range b.value {
+		id := elem.(map[string]interface{})["id"]
+		if id == nil {
+			continue
+		}
+
+		expected := false
+		for _, reqID := range ids {
+			if reqID == id {
+				expected = true
+				break
+			}
+		}
+
+		if !expected || seen[id] {
+			b.chain.fail(AssertionFailure{
+				Type:   AssertBelongs,
+				Actual: &AssertionValue{id},
+				Expected: &AssertionValue{
+					AssertionList(ids),
+				},
+				Errors: []error{
+					errors.New(
+						"expected: exactly one JSON-RPC response per request id"),
+				},
+			})
+			return
+		}
+
+		seen[id] = true
+	}
+
+	for _, id := range ids {
+		if !seen[id] {
+			b.chain.fail(AssertionFailure{
+				Type:   AssertContainsElement,
+				Actual: &AssertionValue{b.value},
+				Expected: &AssertionValue{
+					id,
+				},
+				Errors: []error{
+					errors.New("expected: JSON-RPC response for every request id"),
+				},
+			})
+			return
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package httpexpect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONRPCFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newJSONRPC(chain, map[string]interface{}{
+		"jsonrpc": "2.0", "id": 1, "result": nil,
+	})
+
+	value.chain.assertFailed(t)
+
+	assert.Nil(t, value.Raw())
+	assert.NotNil(t, value.ID())
+	assert.NotNil(t, value.Result())
+	assert.NotNil(t, value.Error())
+	assert.NotNil(t, value.ErrorCode())
+
+	value.HasError()
+	value.NoError()
+
+	batch := newJSONRPCBatch(chain, []interface{}{})
+
+	batch.chain.assertFailed(t)
+
+	assert.Nil(t, batch.Raw())
+	assert.NotNil(t, batch.Length())
+	assert.NotNil(t, batch.Response(1))
+}
+
+func TestJSONRPCInvalid(t *testing.T) {
+	cases := []interface{}{
+		nil,
+		"foo",
+		map[string]interface{}{
+			"id": 1, "result": 1,
+		},
+		map[string]interface{}{
+			"jsonrpc": "1.0", "id": 1, "result": 1,
+		},
+		map[string]interface{}{
+			"jsonrpc": "2.0", "result": 1,
+		},
+		map[string]interface{}{
+			"jsonrpc": "2.0", "id": true, "result": 1,
+		},
+		map[string]interface{}{
+			"jsonrpc": "2.0", "id": nil, "result": 1,
+		},
+		map[string]interface{}{
+			"jsonrpc": "2.0", "id": 1,
+		},
+		map[string]interface{}{
+			"jsonrpc": "2.0", "id": 1, "result": 1,
+			"error": map[string]interface{}{"code": 1, "message": "x"},
+		},
+		map[string]interface{}{
+			"jsonrpc": "2.0", "id": 1, "error": "x",
+		},
+		map[string]interface{}{
+			"jsonrpc": "2.0", "id": 1,
+			"error": map[string]interface{}{"code": 1.5, "message": "x"},
+		},
+		map[string]interface{}{
+			"jsonrpc": "2.0", "id": 1,
+			"error": map[string]interface{}{"code": 1},
+		},
+	}
+
+	for _, tc := range cases {
+		value := NewJSONRPC(newMockReporter(t), tc)
+		value.chain.assertFailed(t)
+
+		batch := NewJSONRPCBatch(newMockReporter(t), []interface{}{tc})
+		batch.chain.assertFailed(t)
+	}
+
+	batch := NewJSONRPCBatch(newMockReporter(t), map[string]interface{}{})
+	batch.chain.assertFailed(t)
+}
+
+func TestJSONRPCResult(t *testing.T) {
+	value := NewJSONRPC(newMockReporter(t), map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      "abc",
+		"result":  []interface{}{"foo"},
+	})
+
+	value.chain.assertOK(t)
+
+	assert.Equal(t, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      "abc",
+		"result":  []interface{}{"foo"},
+	}, value.Raw())
+
+	value.ID().String().Equal("abc").chain.assertOK(t)
+	value.Result().Array().Elements("foo").chain.assertOK(t)
+
+	value.NoError().chain.assertOK(t)
+	value.HasError().chain.assertFailed(t)
+
+	value = NewJSONRPC(newMockReporter(t), value.Raw())
+	value.Error().chain.assertFailed(t)
+	value.chain.assertFailed(t)
+
+	value = NewJSONRPC(newMockReporter(t), value.Raw())
+	value.ErrorCode().chain.assertFailed(t)
+	value.chain.assertFailed(t)
+}
+
+func TestJSONRPCError(t *testing.T) {
+	value := NewJSONRPC(newMockReporter(t), map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      nil,
+		"error": map[string]interface{}{
+			"code":    -32700,
+			"message": "Parse error",
+		},
+	})
+
+	value.chain.assertOK(t)
+
+	value.ID().Null().chain.assertOK(t)
+	value.ErrorCode().Equal(-32700).chain.assertOK(t)
+	value.Error().Value("message").String().Equal("Parse error").chain.assertOK(t)
+
+	value.HasError().chain.assertOK(t)
+	value.NoError().chain.assertFailed(t)
+
+	value = NewJSONRPC(newMockReporter(t), value.Raw())
+	value.Result().chain.assertFailed(t)
+	value.chain.assertFailed(t)
+}
+
+func TestJSONRPCBatch(t *testing.T) {
+	newBatch := func() *JSONRPCBatch {
+		return NewJSONRPCBatch(newMockReporter(t), []interface{}{
+			map[string]interface{}{"jsonrpc": "2.0", "id": 2, "result": 7},
+			map[string]interface{}{"jsonrpc": "2.0", "id": "1", "result": 3},
+		})
+	}
+
+	batch := newBatch()
+	batch.chain.assertOK(t)
+
+	batch.Length().Equal(2).chain.assertOK(t)
+
+	batch.Response("1").Result().Number().Equal(3).chain.assertOK(t)
+	batch.Response(2).Result().Number().Equal(7).chain.assertOK(t)
+	batch.chain.assertOK(t)
+
+	batch.Response(1).chain.assertFailed(t)
+	batch.chain.assertFailed(t)
+
+	t.Run("ids", func(t *testing.T) {
+		cases := []struct {
+			ids []interface{}
+			ok  bool
+		}{
+			{[]interface{}{"1", 2.0}, true},
+			{[]interface{}{2.0, "1"}, true},
+			{[]interface{}{"1"}, false},
+			{[]interface{}{"1", 2.0, 3.0}, false},
+			{[]interface{}{1.0, 2.0}, false},
+		}
+
+		for _, tc := range cases {
+			batch := newBatch()
+			batch.checkIDs(tc.ids)
+
+			if tc.ok {
+				batch.chain.assertOK(t)
+			} else {
+				batch.chain.assertFailed(t)
+			}
+		}
+
+		batch := NewJSONRPCBatch(newMockReporter(t), []interface{}{
+			map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": 1},
+			map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": 1},
+		})
+		batch.checkIDs([]interface{}{1.0})
+		batch.chain.assertFailed(t)
+	})
+}
@@ -0,0 +1,257 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LeakRule defines a kind of sensitive data detected by Response.HasNoLeaks.
+type LeakRule struct {
+	// Name of the rule used in failure message, e.g. "JWT".
+	Name string
+
+	// Pattern matches sensitive data.
+	Pattern *regexp.Regexp
+
+	// Check is optional additional check of matched string, used to filter
+	// false positives. If it returns false, match is ignored.
+	Check func(match string) bool
+}
+
+// LeakPolicy defines what sensitive data is detected by
+// Response.HasNoLeaks.
+//
+// Zero value doesn't detect anything; add rules you need, or start
+// from DefaultLeakPolicy.
+type LeakPolicy struct {
+	// Rules are patterns of sensitive data.
+	Rules []LeakRule
+
+	// Denylist contains literal strings that should never appear in
+	// responses, e.g. passwords and keys used by test fixtures.
+	Denylist []string
+
+	// Allowlist contains matched strings that are not reported, e.g.
+	// public support email. Comparison is exact.
+	Allowlist []string
+
+	// IgnoreHeaders lists names of headers that are not scanned, e.g.
+	// "Set-Cookie" if session cookie is a JWT.
+	IgnoreHeaders []string
+}
+
+var (
+	awsKeyRegexp     = regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)
+	jwtRegexp        = regexp.MustCompile(`\beyJ[\w-]+\.eyJ[\w-]+\.[\w-]+`)
+	privateKeyRegexp = regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)
+	cardNumberRegexp = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+)
+
+// DefaultLeakPolicy returns policy that detects commonly leaked data:
+//   - AWS access key IDs
+//   - JSON Web Tokens
+//   - PEM private keys
+//   - email addresses
+//   - payment card numbers (validated by IIN prefix and Luhn checksum)
+//
+// Endpoints that legitimately return some of these, e.g. login endpoint
+// returning JWT, should use a policy with corresponding rule removed.
+func DefaultLeakPolicy() LeakPolicy {
+	return LeakPolicy{
+		Rules: []LeakRule{
+			{
+				Name:    "AWS access key",
+				Pattern: awsKeyRegexp,
+			},
+			{
+				Name:    "JWT",
+				Pattern: jwtRegexp,
+			},
+			{
+				Name:    "private key",
+				Pattern: privateKeyRegexp,
+			},
+			{
+				Name:    "email",
+				Pattern: emailRegexp,
+			},
+			{
+				Name:    "card number",
+				Pattern: cardNumberRegexp,
+				Check:   isCardNumber,
+			},
+		},
+	}
+}
+
+// LeakMatcher returns matcher that invokes HasNoLeaks for every response.
+// It can be used in Config.Matchers for security regression testing
+// across all endpoints.
+//
+// Example:
+//
+//	e := WithConfig(Config{
+//	    BaseURL:  "http://example.com",
+//	    Reporter: NewAssertReporter(t),
+//	    Matchers: []func(*Response){
+//	        LeakMatcher(DefaultLeakPolicy()),
+//	    },
+//	})
+func LeakMatcher(policy LeakPolicy) func(*Response) {
+	return func(resp *Response) {
+		resp.HasNoLeaks(policy)
+	}
+}
+
+// maximum number of findings included into failure
+const maxLeakFindings = 20
+
+// returns list of found leaks, empty if none
+func (p *LeakPolicy) scan(header http.Header, body []byte) []error {
+	var findings []string
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		if !containsFold(p.IgnoreHeaders, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			for _, leak := range p.find(value) {
+				findings = append(findings, fmt.Sprintf("%s in header %q: %s",
+					leak.name, name, maskLeak(leak.match)))
+			}
+		}
+	}
+
+	for _, leak := range p.find(string(body)) {
+		findings = append(findings, fmt.Sprintf("%s in body at offset %d: %s",
+			leak.name, leak.offset, maskLeak(leak.match)))
+	}
+
+	var errs []error
+
+	for n, finding := range findings {
+		if n == maxLeakFindings {
+			errs = append(errs, fmt.Errorf("and %d more", len(findings)-n))
+			break
+		}
+		errs = append(errs, errors.New(finding))
+	}
+
+	return errs
+}
+
+type leakMatch struct {
+	name   string
+	match  string
+	offset int
+}
+
+// finds all rule and denylist matches in text, ordered by offset
+func (p *LeakPolicy) find(text string) []leakMatch {
+	var matches []leakMatch
+
+	for _, rule := range p.Rules {
+		if rule.Pattern == nil {
+			continue
+		}
+		for _, loc := range rule.Pattern.FindAllStringIndex(text, -1) {
+			match := text[loc[0]:loc[1]]
+			if rule.Check != nil && !rule.Check(match) {
+				continue
+			}
+			if p.allowed(match) {
+				continue
+			}
+			matches = append(matches, leakMatch{rule.Name, match, loc[0]})
+		}
+	}
+
+	for _, denied := range p.Denylist {
+		if denied == "" {
+			continue
+		}
+		for off := 0; ; {
+			i := strings.Index(text[off:], denied)
+			if i < 0 {
+				break
+			}
+			matches = append(matches, leakMatch{"denylisted value", denied, off + i})
+			off += i + len(denied)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].offset < matches[j].offset
+	})
+
+	return matches
+}
+
+func (p *LeakPolicy) allowed(match string) bool {
+	for _, s := range p.Allowlist {
+		if s == match {
+			return true
+		}
+	}
+	return false
+}
+
+// hides most of sensitive value, so that failure doesn't leak it further
+func maskLeak(s string) string {
+	masked := strings.Repeat("*", len(s))
+	if len(s) > 8 {
+		masked = s[:4] + "..." + s[len(s)-2:]
+	}
+	return fmt.Sprintf("%q (%d chars)", masked, len(s))
+}
+
+// checks card number length, issuer prefix, and Luhn checksum
+func isCardNumber(s string) bool {
+	digits := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			digits = append(digits, s[i]-'0')
+		}
+	}
+
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	prefix2 := int(digits[0])*10 + int(digits[1])
+	prefix4 := prefix2*100 + int(digits[2])*10 + int(digits[3])
+
+	switch {
+	case digits[0] == 4: // Visa
+	case prefix2 >= 51 && prefix2 <= 55: // Mastercard
+	case prefix4 >= 2221 && prefix4 <= 2720: // Mastercard
+	case prefix2 == 34 || prefix2 == 37: // American Express
+	case prefix4 == 6011 || prefix2 == 65: // Discover
+	case prefix4 >= 3528 && prefix4 <= 3589: // JCB
+	default:
+		return false
+	}
+
+	sum := 0
+	for i := range digits {
+		d := int(digits[len(digits)-1-i])
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+
+	return sum%10 == 0
+}
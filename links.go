@@ -0,0 +1,388 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Links provides methods to inspect Link header of response (RFC 8288).
+type Links struct {
+	chain *chain
+	links []linkValue
+	base  *url.URL
+}
+
+// Link provides methods to inspect single link from Link header.
+type Link struct {
+	chain *chain
+	link  linkValue
+	base  *url.URL
+}
+
+// Links returns a new Links instance with parsed Link header of response.
+//
+// All values of Link header are parsed; malformed links are skipped.
+// If header is missing, Links is empty.
+//
+// Relative link targets are resolved against request URL (or
+// Config.BaseURL, if request is unknown).
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Links().HasRel("next")
+//	resp.Links().Rel("next").Href().Equal("http://example.com/users?page=2")
+func (r *Response) Links() *Links {
+	r.chain.enter("Links()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newLinks(r.chain, nil, nil)
+	}
+
+	links := parseLinkHeader(headerValues(r.httpResp.Header, "Link"))
+
+	return newLinks(r.chain, links, r.linkBase())
+}
+
+// FollowLink returns a new Request instance for GET request to target of
+// link with given relation type from Link header.
+//
+// If response was received using Expect instance, request is created
+// by that instance, so that its builders and matchers are applied.
+// Otherwise, request is created using response config.
+//
+// FollowLink fails if there is no such link.
+//
+// Example:
+//
+//	e.GET("/users").Expect().
+//	    FollowLink("next").Expect().
+//	    Status(http.StatusOK)
+func (r *Response) FollowLink(rel string) *Request {
+	r.chain.enter("FollowLink(%q)", rel)
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r.followRequest("")
+	}
+
+	base := r.linkBase()
+
+	for _, link := range parseLinkHeader(headerValues(r.httpResp.Header, "Link")) {
+		if !link.hasRel(rel) {
+			continue
+		}
+
+		target, err := resolveLink(base, link.URL)
+		if err != nil {
+			r.chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{link.URL},
+				Errors: []error{
+					fmt.Errorf("invalid %q url in \"Link\" response header", rel),
+					err,
+				},
+			})
+			return r.followRequest("")
+		}
+
+		return r.followRequest(target)
+	}
+
+	r.chain.fail(AssertionFailure{
+		Type:     AssertContainsElement,
+		Actual:   &AssertionValue{headerValues(r.httpResp.Header, "Link")},
+		Expected: &AssertionValue{rel},
+		Errors: []error{
+			fmt.Errorf("expected: \"Link\" response header has %q link", rel),
+		},
+	})
+
+	return r.followRequest("")
+}
+
+// creates GET request with given url; if url is empty, request is
+// created for failed chain
+func (r *Response) followRequest(target string) *Request {
+	var req *Request
+
+	if r.expect != nil {
+		req = r.expect.newRequest(r.chain, http.MethodGet, "")
+	} else {
+		config := r.config
+		if config.AssertionHandler == nil {
+			config.AssertionHandler = r.chain.handler
+		}
+		config.fillDefaults()
+
+		req = newRequest(r.chain, config, http.MethodGet, "")
+	}
+
+	if target != "" {
+		req.WithURL(target)
+	}
+
+	return req
+}
+
+// returns url against which relative link targets are resolved
+func (r *Response) linkBase() *url.URL {
+	if r.httpResp.Request != nil && r.httpResp.Request.URL != nil {
+		return r.httpResp.Request.URL
+	}
+
+	if r.config.BaseURL != "" {
+		if u, err := url.Parse(r.config.BaseURL); err == nil {
+			return u
+		}
+	}
+
+	return nil
+}
+
+func resolveLink(base *url.URL, target string) (string, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+
+	return u.String(), nil
+}
+
+func newLinks(parent *chain, links []linkValue, base *url.URL) *Links {
+	return &Links{parent.clone(), links, base}
+}
+
+// Length returns a new Number instance with number of links.
+//
+// Example:
+//
+//	links := resp.Links()
+//	links.Length().Equal(2)
+func (l *Links) Length() *Number {
+	l.chain.enter("Length()")
+	defer l.chain.leave()
+
+	if l.chain.failed() {
+		return newNumber(l.chain, 0)
+	}
+
+	return newNumber(l.chain, float64(len(l.links)))
+}
+
+// Rels returns a new Array instance with relation types of all links,
+// in order they appear in header. If link has several space-separated
+// relation types, all of them are included.
+//
+// Example:
+//
+//	links := resp.Links()
+//	links.Rels().ContainsOnly("next", "last")
+func (l *Links) Rels() *Array {
+	l.chain.enter("Rels()")
+	defer l.chain.leave()
+
+	if l.chain.failed() {
+		return newArray(l.chain, nil)
+	}
+
+	rels := []interface{}{}
+	for _, link := range l.links {
+		for _, rel := range link.rels() {
+			rels = append(rels, rel)
+		}
+	}
+
+	return newArray(l.chain, rels)
+}
+
+// HasRel succeeds if there is a link with given relation type.
+// Relation type is case-insensitive.
+//
+// Example:
+//
+//	links := resp.Links()
+//	links.HasRel("next")
+func (l *Links) HasRel(rel string) *Links {
+	l.chain.enter("HasRel(%q)", rel)
+	defer l.chain.leave()
+
+	if l.chain.failed() {
+		return l
+	}
+
+	if _, ok := l.find(rel); !ok {
+		l.chain.fail(AssertionFailure{
+			Type:     AssertContainsElement,
+			Actual:   &AssertionValue{l.rels()},
+			Expected: &AssertionValue{rel},
+			Errors: []error{
+				errors.New("expected: links contain relation type"),
+			},
+		})
+	}
+
+	return l
+}
+
+// NotHasRel succeeds if there is no link with given relation type.
+// Relation type is case-insensitive.
+//
+// Example:
+//
+//	links := resp.Links()
+//	links.NotHasRel("next") // last page
+func (l *Links) NotHasRel(rel string) *Links {
+	l.chain.enter("NotHasRel(%q)", rel)
+	defer l.chain.leave()
+
+	if l.chain.failed() {
+		return l
+	}
+
+	if _, ok := l.find(rel); ok {
+		l.chain.fail(AssertionFailure{
+			Type:     AssertNotContainsElement,
+			Actual:   &AssertionValue{l.rels()},
+			Expected: &AssertionValue{rel},
+			Errors: []error{
+				errors.New("expected: links do not contain relation type"),
+			},
+		})
+	}
+
+	return l
+}
+
+// Rel returns a new Link instance with the first link with given relation
+// type. Relation type is case-insensitive.
+//
+// Rel fails if there is no such link.
+//
+// Example:
+//
+//	links := resp.Links()
+//	links.Rel("next").Href().Contains("page=2")
+func (l *Links) Rel(rel string) *Link {
+	l.chain.enter("Rel(%q)", rel)
+	defer l.chain.leave()
+
+	if l.chain.failed() {
+		return newLink(l.chain, linkValue{}, nil)
+	}
+
+	link, ok := l.find(rel)
+	if !ok {
+		l.chain.fail(AssertionFailure{
+			Type:     AssertContainsElement,
+			Actual:   &AssertionValue{l.rels()},
+			Expected: &AssertionValue{rel},
+			Errors: []error{
+				errors.New("expected: links contain relation type"),
+			},
+		})
+		return newLink(l.chain, linkValue{}, nil)
+	}
+
+	return newLink(l.chain, link, l.base)
+}
+
+func (l *Links) find(rel string) (linkValue, bool) {
+	for _, link := range l.links {
+		if link.hasRel(rel) {
+			return link, true
+		}
+	}
+	return linkValue{}, false
+}
+
+func (l *Links) rels() []string {
+	rels := []string{}
+	for _, link := range l.links {
+		rels = append(rels, link.rels()...)
+	}
+	return rels
+}
+
+func newLink(parent *chain, link linkValue, base *url.URL) *Link {
+	return &Link{parent.clone(), link, base}
+}
+
+// Raw returns link target as it appears in header, without resolving.
+func (l *Link) Raw() string {
+	return l.link.URL
+}
+
+// Href returns a new String instance with link target.
+//
+// Relative target is resolved against request URL, so that Href returns
+// absolute URL.
+//
+// Example:
+//
+//	link := resp.Links().Rel("next")
+//	link.Href().Equal("http://example.com/users?page=2")
+func (l *Link) Href() *String {
+	l.chain.enter("Href()")
+	defer l.chain.leave()
+
+	if l.chain.failed() {
+		return newString(l.chain, "")
+	}
+
+	href, err := resolveLink(l.base, l.link.URL)
+	if err != nil {
+		l.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{l.link.URL},
+			Errors: []error{
+				errors.New("expected: valid link url"),
+				err,
+			},
+		})
+		return newString(l.chain, "")
+	}
+
+	return newString(l.chain, href)
+}
+
+// Param returns a new String instance with value of given link parameter,
+// e.g. "title" or "type". Parameter name is case-insensitive.
+//
+// Param fails if there is no such parameter.
+//
+// Example:
+//
+//	link := resp.Links().Rel("alternate")
+//	link.Param("type").Equal("application/rss+xml")
+func (l *Link) Param(name string) *String {
+	l.chain.enter("Param(%q)", name)
+	defer l.chain.leave()
+
+	if l.chain.failed() {
+		return newString(l.chain, "")
+	}
+
+	if value, ok := l.link.Params[strings.ToLower(name)]; ok {
+		return newString(l.chain, value)
+	}
+
+	l.chain.fail(AssertionFailure{
+		Type:     AssertContainsKey,
+		Actual:   &AssertionValue{l.link.Params},
+		Expected: &AssertionValue{name},
+		Errors: []error{
+			errors.New("expected: link has parameter"),
+		},
+	})
+
+	return newString(l.chain, "")
+}
@@ -0,0 +1,172 @@
+package httpexpect
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinksFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	links := newLinks(chain, nil, nil)
+
+	links.Length().chain.assertFailed(t)
+	links.Rels().chain.assertFailed(t)
+	links.HasRel("next").chain.assertFailed(t)
+	links.NotHasRel("next").chain.assertFailed(t)
+
+	link := links.Rel("next")
+	link.chain.assertFailed(t)
+
+	assert.Equal(t, "", link.Raw())
+
+	link.Href().chain.assertFailed(t)
+	link.Param("title").chain.assertFailed(t)
+}
+
+func TestLinksParse(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	resp := NewResponse(reporter, &http.Response{
+		Header: http.Header{
+			"Link": {
+				`</users?page=2>; rel="next", <https://example.com/users?page=5>; rel=last`,
+				`<https://example.com/feed>; rel="alternate Feed"; ` +
+					`type="application/rss+xml"; title="a; b, c"`,
+			},
+		},
+		Request: &http.Request{
+			URL: &url.URL{Scheme: "http", Host: "example.com", Path: "/users"},
+		},
+	})
+
+	links := resp.Links()
+	links.chain.assertOK(t)
+
+	links.Length().Equal(3).chain.assertOK(t)
+	links.Rels().Elements("next", "last", "alternate", "Feed").chain.assertOK(t)
+
+	links.HasRel("next").chain.assertOK(t)
+	links.HasRel("NEXT").chain.assertOK(t)
+	links.HasRel("feed").chain.assertOK(t)
+	links.NotHasRel("prev").chain.assertOK(t)
+
+	next := links.Rel("next")
+	next.chain.assertOK(t)
+
+	assert.Equal(t, "/users?page=2", next.Raw())
+	next.Href().Equal("http://example.com/users?page=2").chain.assertOK(t)
+
+	links.Rel("last").Href().Equal("https://example.com/users?page=5").
+		chain.assertOK(t)
+
+	alt := links.Rel("alternate")
+	alt.Param("type").Equal("application/rss+xml").chain.assertOK(t)
+	alt.Param("Title").Equal("a; b, c").chain.assertOK(t)
+
+	alt.Param("hreflang").chain.assertFailed(t)
+	alt.chain.reset()
+
+	links.HasRel("prev").chain.assertFailed(t)
+	links.chain.reset()
+
+	links.NotHasRel("last").chain.assertFailed(t)
+	links.chain.reset()
+
+	links.Rel("prev").chain.assertFailed(t)
+	links.chain.reset()
+}
+
+func TestLinksEmpty(t *testing.T) {
+	resp := NewResponse(newMockReporter(t), &http.Response{
+		Header: http.Header{},
+	})
+
+	links := resp.Links()
+	links.chain.assertOK(t)
+
+	links.Length().Equal(0).chain.assertOK(t)
+	links.Rels().Empty().chain.assertOK(t)
+	links.NotHasRel("next").chain.assertOK(t)
+
+	resp.FollowLink("next").chain.assertFailed(t)
+	resp.chain.assertFailed(t)
+}
+
+func TestLinksFollow(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", `</users?page=2>; rel="next"`)
+			_, _ = w.Write([]byte("page1"))
+		case "2":
+			w.Header().Set("Link", `</users?page=3>; rel="next", </users>; rel="first"`)
+			_, _ = w.Write([]byte("page2:" + r.Header.Get("X-Test")))
+		default:
+			_, _ = w.Write([]byte("last"))
+		}
+	})
+
+	t.Run("expect", func(t *testing.T) {
+		assertionHandler := &mockAssertionHandler{}
+
+		e := WithConfig(Config{
+			BaseURL:          "http://example.com",
+			Client:           &http.Client{Transport: NewBinder(handler)},
+			AssertionHandler: assertionHandler,
+		}).Builder(func(req *Request) {
+			req.WithHeader("X-Test", "builder")
+		})
+
+		resp := e.GET("/users").Expect()
+		resp.Text().Equal("page1")
+
+		resp = resp.FollowLink("next").Expect()
+		resp.Text().Equal("page2:builder")
+
+		resp.FollowLink("first").Expect().Text().Equal("page1")
+
+		resp = resp.FollowLink("next").Expect()
+		resp.Text().Equal("last")
+
+		assert.Nil(t, assertionHandler.failure)
+
+		req := resp.FollowLink("next")
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertContainsElement, assertionHandler.failure.Type)
+
+		req.chain.assertFailed(t)
+		req.Expect().chain.assertFailed(t)
+	})
+
+	t.Run("standalone", func(t *testing.T) {
+		resp := NewResponse(newMockReporter(t), &http.Response{
+			Header: http.Header{
+				"Link": {`<http://example.com/users?page=2>; rel="next"`},
+			},
+		})
+
+		req := resp.FollowLink("next")
+		req.chain.assertOK(t)
+
+		assert.Equal(t, "GET", req.httpReq.Method)
+		assert.Equal(t, "http://example.com/users?page=2", req.httpReq.URL.String())
+	})
+
+	t.Run("invalid url", func(t *testing.T) {
+		resp := NewResponse(newMockReporter(t), &http.Response{
+			Header: http.Header{
+				"Link": {`<http://[::1>; rel="next"`},
+			},
+		})
+
+		resp.FollowLink("next").chain.assertFailed(t)
+		resp.chain.assertFailed(t)
+	})
+}
@@ -0,0 +1,384 @@
+package httpexpect
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// LoadProfile defines rate and duration of load generated by Expect.Load.
+type LoadProfile struct {
+	// RPS is the target number of iterations started per second.
+	// Zero means no rate limit: every worker starts next iteration
+	// immediately after finishing previous one.
+	RPS float64
+
+	// Duration of load. Iterations are not started after it expires,
+	// but already started ones are awaited.
+	Duration time.Duration
+
+	// Concurrency is the maximum number of iterations running at the
+	// same time. Default is 1.
+	Concurrency int
+}
+
+// LoadIteration holds result of a single iteration of Expect.Load.
+type LoadIteration struct {
+	// Time spent to run iteration function.
+	Duration time.Duration
+
+	// The first failure that occurred during iteration, if any.
+	Failure *AssertionFailure
+}
+
+// LoadResult provides methods to inspect results of Expect.Load.
+type LoadResult struct {
+	chain      *chain
+	elapsed    time.Duration
+	iterations []LoadIteration
+	samples    []StatsSample
+}
+
+// Load runs given function repeatedly and concurrently, with given rate,
+// during given duration, and returns LoadResult with aggregated results.
+//
+// Function receives a copy of Expect instance, and should make all requests
+// and assertions using it. Failures inside function are not reported;
+// instead, every iteration with a failure is counted as an error, and
+// error rate can be checked using returned LoadResult.
+//
+// Requests made inside function are recorded to statistics available via
+// LoadResult.Stats, instead of Config.Stats.
+//
+// Example:
+//
+//	result := e.Load(httpexpect.LoadProfile{
+//	    RPS:         100,
+//	    Duration:    10 * time.Second,
+//	    Concurrency: 10,
+//	}, func(e *httpexpect.Expect) {
+//	    e.GET("/users").Expect().Status(http.StatusOK)
+//	})
+//
+//	result.ErrorRate().Le(0.01)
+//	result.Throughput().Ge(90)
+//	result.Percentile(99).Lt(500 * time.Millisecond)
+func (e *Expect) Load(profile LoadProfile, fn func(e *Expect)) *LoadResult {
+	e.chain.enter("Load()")
+	defer e.chain.leave()
+
+	if fn == nil {
+		chain := e.chain.clone()
+		chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil argument"),
+			},
+		})
+		return newLoadResult(chain, 0, nil, nil)
+	}
+
+	if profile.Duration <= 0 || profile.RPS < 0 || profile.Concurrency < 0 {
+		chain := e.chain.clone()
+		chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{profile},
+			Errors: []error{
+				errors.New(
+					"unexpected load profile, expected positive duration" +
+						" and non-negative rps and concurrency"),
+			},
+		})
+		return newLoadResult(chain, 0, nil, nil)
+	}
+
+	concurrency := profile.Concurrency
+	if concurrency == 0 {
+		concurrency = 1
+	}
+
+	stats := NewStats()
+
+	var (
+		mu         sync.Mutex
+		iterations []LoadIteration
+		wg         sync.WaitGroup
+	)
+
+	// when rate is limited, workers take one token per iteration
+	var tokens chan struct{}
+	if profile.RPS > 0 {
+		tokens = make(chan struct{})
+	}
+
+	start := time.Now()
+	deadline := start.Add(profile.Duration)
+
+	for n := 0; n < concurrency; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				if tokens != nil {
+					if _, ok := <-tokens; !ok {
+						return
+					}
+				} else if !time.Now().Before(deadline) {
+					return
+				}
+
+				handler := &eventuallyHandler{}
+
+				worker := e.withHandler(handler)
+				worker.config.Stats = stats
+
+				iterStart := time.Now()
+				fn(worker)
+
+				iteration := LoadIteration{
+					Duration: time.Since(iterStart),
+					Failure:  handler.failure,
+				}
+
+				mu.Lock()
+				iterations = append(iterations, iteration)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	if tokens != nil {
+		interval := time.Duration(float64(time.Second) / profile.RPS)
+
+		timer := time.NewTimer(profile.Duration)
+		ticker := time.NewTicker(interval)
+
+	loop:
+		for {
+			// blocks while all workers are busy, so that the actual rate
+			// is lower than the target when system can't keep up
+			select {
+			case tokens <- struct{}{}:
+			case <-timer.C:
+				break loop
+			}
+
+			select {
+			case <-ticker.C:
+			case <-timer.C:
+				break loop
+			}
+		}
+
+		ticker.Stop()
+		close(tokens)
+	}
+
+	wg.Wait()
+
+	return newLoadResult(e.chain, time.Since(start), iterations, stats.Samples())
+}
+
+func newLoadResult(
+	parent *chain,
+	elapsed time.Duration,
+	iterations []LoadIteration,
+	samples []StatsSample,
+) *LoadResult {
+	return &LoadResult{parent.clone(), elapsed, iterations, samples}
+}
+
+// Raw returns underlying iterations attached to LoadResult, in order
+// of completion.
+func (r *LoadResult) Raw() []LoadIteration {
+	return r.iterations
+}
+
+// Elapsed returns a new Duration instance with total time of load,
+// including time spent waiting for the last iterations.
+//
+// Example:
+//
+//	result.Elapsed().Lt(15 * time.Second)
+func (r *LoadResult) Elapsed() *Duration {
+	r.chain.enter("Elapsed()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newDuration(r.chain, nil)
+	}
+
+	d := r.elapsed
+
+	return newDuration(r.chain, &d)
+}
+
+// Count returns a new Number instance with number of completed iterations.
+//
+// Example:
+//
+//	result.Count().Gt(0)
+func (r *LoadResult) Count() *Number {
+	r.chain.enter("Count()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newNumber(r.chain, 0)
+	}
+
+	return newNumber(r.chain, float64(len(r.iterations)))
+}
+
+// ErrorCount returns a new Number instance with number of iterations
+// with failures.
+//
+// Example:
+//
+//	result.ErrorCount().Equal(0)
+func (r *LoadResult) ErrorCount() *Number {
+	r.chain.enter("ErrorCount()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newNumber(r.chain, 0)
+	}
+
+	return newNumber(r.chain, float64(r.errors()))
+}
+
+// ErrorRate returns a new Number instance with fraction (from 0 to 1)
+// of iterations with failures.
+//
+// ErrorRate fails if there are no iterations.
+//
+// Example:
+//
+//	result.ErrorRate().Le(0.01)
+func (r *LoadResult) ErrorRate() *Number {
+	r.chain.enter("ErrorRate()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newNumber(r.chain, 0)
+	}
+
+	if !r.checkNotEmpty() {
+		return newNumber(r.chain, 0)
+	}
+
+	return newNumber(r.chain, float64(r.errors())/float64(len(r.iterations)))
+}
+
+// Throughput returns a new Number instance with number of completed
+// iterations per second.
+//
+// Example:
+//
+//	result.Throughput().Ge(90)
+func (r *LoadResult) Throughput() *Number {
+	r.chain.enter("Throughput()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newNumber(r.chain, 0)
+	}
+
+	if r.elapsed <= 0 {
+		return newNumber(r.chain, 0)
+	}
+
+	return newNumber(r.chain, float64(len(r.iterations))/r.elapsed.Seconds())
+}
+
+// Percentile returns a new Duration instance with given percentile of
+// iteration latency, computed using nearest-rank method.
+//
+// p should be in range (0; 100]. Percentile fails if there are no
+// iterations.
+//
+// Example:
+//
+//	result.Percentile(99).Lt(500 * time.Millisecond)
+func (r *LoadResult) Percentile(p float64) *Duration {
+	return r.latency().Percentile(p)
+}
+
+// Max returns a new Duration instance with maximum iteration latency.
+//
+// Max fails if there are no iterations.
+//
+// Example:
+//
+//	result.Max().Lt(time.Second)
+func (r *LoadResult) Max() *Duration {
+	return r.latency().Max()
+}
+
+// Mean returns a new Duration instance with average iteration latency.
+//
+// Mean fails if there are no iterations.
+//
+// Example:
+//
+//	result.Mean().Lt(100 * time.Millisecond)
+func (r *LoadResult) Mean() *Duration {
+	return r.latency().Mean()
+}
+
+// Stats returns a new StatsSummary instance with statistics of
+// individual requests made during load.
+//
+// Example:
+//
+//	result.Stats().Endpoint("GET", "/users").Percentile(95).
+//	    Lt(300 * time.Millisecond)
+//	result.Stats().StatusRatio(httpexpect.Status5xx).Equal(0)
+func (r *LoadResult) Stats() *StatsSummary {
+	r.chain.enter("Stats()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newStatsSummary(r.chain, nil)
+	}
+
+	return newStatsSummary(r.chain, r.samples)
+}
+
+// returns summary with iteration latencies, to reuse its percentiles
+func (r *LoadResult) latency() *StatsSummary {
+	samples := make([]StatsSample, 0, len(r.iterations))
+	for _, iteration := range r.iterations {
+		samples = append(samples, StatsSample{Duration: iteration.Duration})
+	}
+
+	if len(samples) == 0 {
+		samples = nil
+	}
+
+	return newStatsSummary(r.chain, samples)
+}
+
+func (r *LoadResult) errors() int {
+	n := 0
+	for _, iteration := range r.iterations {
+		if iteration.Failure != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *LoadResult) checkNotEmpty() bool {
+	if len(r.iterations) == 0 {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertNotEmpty,
+			Actual: &AssertionValue{r.iterations},
+			Errors: []error{
+				errors.New("expected: at least one completed iteration"),
+			},
+		})
+		return false
+	}
+	return true
+}
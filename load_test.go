@@ -0,0 +1,169 @@
+package httpexpect
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadUnlimited(t *testing.T) {
+	var count int64
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// every second request fails
+		if atomic.AddInt64(&count, 1)%2 == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	reporter := newMockReporter(t)
+
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   &http.Client{Transport: NewBinder(handler)},
+		Reporter: reporter,
+	})
+
+	result := e.Load(LoadProfile{
+		Duration:    50 * time.Millisecond,
+		Concurrency: 4,
+	}, func(e *Expect) {
+		e.GET("/users").Expect().Status(http.StatusOK)
+	})
+
+	assert.False(t, reporter.reported)
+
+	n := len(result.Raw())
+	assert.True(t, n > 0)
+	assert.Equal(t, int(atomic.LoadInt64(&count)), n)
+
+	errors := 0
+	for _, iteration := range result.Raw() {
+		if iteration.Failure != nil {
+			assert.Equal(t, AssertEqual, iteration.Failure.Type)
+			errors++
+		}
+	}
+
+	result.Count().Equal(n)
+	result.ErrorCount().Equal(errors)
+	result.ErrorRate().InRange(0.4, 0.6)
+	result.Throughput().Gt(0)
+	result.Elapsed().Ge(50 * time.Millisecond)
+	result.Percentile(99).Le(result.Max().Raw())
+	result.Mean().Le(result.Max().Raw())
+
+	result.Stats().Count().Equal(n)
+	result.Stats().Endpoint("GET", "/users").StatusCount(500).Equal(errors)
+
+	result.chain.assertOK(t)
+	e.chain.assertOK(t)
+}
+
+func TestLoadRateLimited(t *testing.T) {
+	var running, maxRunning int64
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt64(&running, 1)
+		defer atomic.AddInt64(&running, -1)
+
+		for {
+			m := atomic.LoadInt64(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt64(&maxRunning, m, n) {
+				break
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	})
+
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Client:   &http.Client{Transport: NewBinder(handler)},
+		Reporter: newMockReporter(t),
+	})
+
+	result := e.Load(LoadProfile{
+		RPS:         100,
+		Duration:    200 * time.Millisecond,
+		Concurrency: 2,
+	}, func(e *Expect) {
+		e.GET("/").Expect().Status(http.StatusOK)
+	})
+
+	result.Count().InRange(5, 25)
+	result.ErrorCount().Equal(0)
+	result.Throughput().InRange(10, 150)
+
+	result.chain.assertOK(t)
+
+	assert.True(t, atomic.LoadInt64(&maxRunning) <= 2)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	e := WithConfig(Config{
+		Client:   &mockClient{},
+		Reporter: newMockReporter(t),
+	})
+
+	cases := []struct {
+		name    string
+		profile LoadProfile
+		fn      func(*Expect)
+	}{
+		{
+			name:    "nil func",
+			profile: LoadProfile{Duration: time.Millisecond},
+			fn:      nil,
+		},
+		{
+			name:    "zero duration",
+			profile: LoadProfile{},
+			fn:      func(*Expect) {},
+		},
+		{
+			name:    "negative rps",
+			profile: LoadProfile{RPS: -1, Duration: time.Millisecond},
+			fn:      func(*Expect) {},
+		},
+		{
+			name:    "negative concurrency",
+			profile: LoadProfile{Concurrency: -1, Duration: time.Millisecond},
+			fn:      func(*Expect) {},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := e.Load(tc.profile, tc.fn)
+
+			result.chain.assertFailed(t)
+			e.chain.assertOK(t)
+
+			assert.Nil(t, result.Raw())
+			assert.Equal(t, time.Duration(0), result.Percentile(50).Raw())
+		})
+	}
+}
+
+func TestLoadEmpty(t *testing.T) {
+	result := newLoadResult(newMockChain(t), time.Second, nil, nil)
+
+	result.Count().Equal(0)
+	result.ErrorCount().Equal(0)
+	result.Throughput().Equal(0)
+	result.chain.assertOK(t)
+
+	result.ErrorRate()
+	result.chain.assertFailed(t)
+
+	result = newLoadResult(newMockChain(t), time.Second, nil, nil)
+
+	result.Percentile(50)
+	result.chain.assertOK(t)
+}
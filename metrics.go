@@ -0,0 +1,443 @@
+package httpexpect
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics collects Prometheus metrics of test traffic.
+//
+// Metrics is populated from Request.Expect and failed assertions when it's
+// set in Config.Metrics. It maintains the following metrics:
+//
+//	httpexpect_requests_total{method, endpoint, status}
+//	    counter of sent requests
+//	httpexpect_request_duration_seconds{method, endpoint}
+//	    histogram of request latency
+//	httpexpect_assertion_failures_total{type}
+//	    counter of failed assertions
+//
+// Endpoint is request path as passed to Expect.Request or NewRequest, before
+// path parameters substitution, e.g. "/users/{id}". If request was retried,
+// only the last attempt is recorded. Responses served from client-side cache
+// are not recorded.
+//
+// Metrics implements http.Handler and serves metrics in Prometheus text
+// exposition format, so it can be scraped directly, e.g. when checks are
+// run periodically as a canary. It doesn't depend on Prometheus client
+// library. To register metrics in existing Prometheus registry instead,
+// use Snapshot to implement prometheus.Collector (see README).
+//
+// Metrics is safe for concurrent use.
+//
+// Example:
+//
+//	metrics := httpexpect.NewMetrics()
+//
+//	http.Handle("/metrics", metrics)
+//	go http.ListenAndServe(":9090", nil)
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:  "http://example.com",
+//	    Reporter: httpexpect.NewAssertReporter(t),
+//	    Metrics:  metrics,
+//	})
+type Metrics struct {
+	// Prefix of metric names.
+	// If empty, "httpexpect" is used.
+	Namespace string
+
+	// Upper bounds of latency histogram buckets, in seconds, in
+	// increasing order.
+	// If nil, DefaultMetricsBuckets is used.
+	Buckets []float64
+
+	mu        sync.Mutex
+	requests  map[metricsRequestKey]uint64
+	latencies map[metricsEndpointKey]*metricsHistogram
+	failures  map[string]uint64
+}
+
+// MetricsSnapshot is a copy of metrics collected by Metrics.
+// Samples are sorted in the same order as they're written by WriteTo.
+type MetricsSnapshot struct {
+	// Samples of requests_total counter.
+	Requests []MetricsRequestSample
+
+	// Samples of request_duration_seconds histogram.
+	Latencies []MetricsLatencySample
+
+	// Samples of assertion_failures_total counter.
+	Failures []MetricsFailureSample
+}
+
+// MetricsRequestSample is a value of requests_total counter for
+// one combination of labels.
+type MetricsRequestSample struct {
+	Method   string
+	Endpoint string
+	Status   int
+	Count    uint64
+}
+
+// MetricsLatencySample is a value of request_duration_seconds histogram
+// for one combination of labels.
+type MetricsLatencySample struct {
+	Method   string
+	Endpoint string
+
+	// Cumulative counts of observations, by upper bound of bucket,
+	// in seconds. Doesn't include +Inf bucket, which is equal to Count.
+	Buckets map[float64]uint64
+
+	// Number of observations.
+	Count uint64
+
+	// Sum of observations, in seconds.
+	Sum float64
+}
+
+// MetricsFailureSample is a value of assertion_failures_total counter for
+// one assertion type.
+type MetricsFailureSample struct {
+	Type  string
+	Count uint64
+}
+
+// DefaultMetricsBuckets defines default latency histogram buckets, in
+// seconds. They're the same as default buckets of Prometheus client.
+var DefaultMetricsBuckets = []float64{
+	.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10,
+}
+
+type metricsEndpointKey struct {
+	method   string
+	endpoint string
+}
+
+type metricsRequestKey struct {
+	metricsEndpointKey
+	status int
+}
+
+type metricsHistogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewMetrics returns a new empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// ObserveRequest records sent request.
+//
+// Normally it's called automatically from Request.Expect.
+func (m *Metrics) ObserveRequest(
+	method, endpoint string, status int, duration time.Duration,
+) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.init()
+
+	key := metricsEndpointKey{method, endpoint}
+
+	m.requests[metricsRequestKey{key, status}]++
+
+	hist := m.latencies[key]
+	if hist == nil {
+		hist = &metricsHistogram{
+			counts: make([]uint64, len(m.buckets())),
+		}
+		m.latencies[key] = hist
+	}
+
+	seconds := duration.Seconds()
+
+	for n, bound := range m.buckets() {
+		if seconds <= bound {
+			hist.counts[n]++
+		}
+	}
+
+	hist.count++
+	hist.sum += seconds
+}
+
+// ObserveFailure records failed assertion.
+//
+// Normally it's called automatically for every failed assertion.
+func (m *Metrics) ObserveFailure(failure *AssertionFailure) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.init()
+
+	m.failures[failure.Type.String()]++
+}
+
+// Reset removes all collected metrics.
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests = nil
+	m.latencies = nil
+	m.failures = nil
+}
+
+// ServeHTTP implements http.Handler.
+//
+// Writes metrics in Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	_, _ = m.WriteTo(w)
+}
+
+// Snapshot returns copy of collected metrics.
+//
+// It can be used to export metrics to other systems, e.g. to implement
+// prometheus.Collector using prometheus.MustNewConstMetric and
+// prometheus.MustNewConstHistogram.
+//
+// Example:
+//
+//	for _, sample := range metrics.Snapshot().Requests {
+//	    fmt.Println(sample.Method, sample.Endpoint, sample.Status, sample.Count)
+//	}
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return MetricsSnapshot{
+		Requests:  m.snapshotRequests(),
+		Latencies: m.snapshotLatencies(),
+		Failures:  m.snapshotFailures(),
+	}
+}
+
+func (m *Metrics) snapshotRequests() []MetricsRequestSample {
+	keys := make([]metricsRequestKey, 0, len(m.requests))
+	for key := range m.requests {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].metricsEndpointKey != keys[j].metricsEndpointKey {
+			return keys[i].metricsEndpointKey.less(keys[j].metricsEndpointKey)
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	samples := make([]MetricsRequestSample, 0, len(keys))
+
+	for _, key := range keys {
+		samples = append(samples, MetricsRequestSample{
+			Method:   key.method,
+			Endpoint: key.endpoint,
+			Status:   key.status,
+			Count:    m.requests[key],
+		})
+	}
+
+	return samples
+}
+
+func (m *Metrics) snapshotLatencies() []MetricsLatencySample {
+	keys := make([]metricsEndpointKey, 0, len(m.latencies))
+	for key := range m.latencies {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].less(keys[j])
+	})
+
+	samples := make([]MetricsLatencySample, 0, len(keys))
+
+	for _, key := range keys {
+		hist := m.latencies[key]
+
+		buckets := make(map[float64]uint64, len(hist.counts))
+		for n, bound := range m.buckets() {
+			buckets[bound] = hist.counts[n]
+		}
+
+		samples = append(samples, MetricsLatencySample{
+			Method:   key.method,
+			Endpoint: key.endpoint,
+			Buckets:  buckets,
+			Count:    hist.count,
+			Sum:      hist.sum,
+		})
+	}
+
+	return samples
+}
+
+func (m *Metrics) snapshotFailures() []MetricsFailureSample {
+	types := make([]string, 0, len(m.failures))
+	for typ := range m.failures {
+		types = append(types, typ)
+	}
+
+	sort.Strings(types)
+
+	samples := make([]MetricsFailureSample, 0, len(types))
+
+	for _, typ := range types {
+		samples = append(samples, MetricsFailureSample{
+			Type:  typ,
+			Count: m.failures[typ],
+		})
+	}
+
+	return samples
+}
+
+// WriteTo writes metrics in Prometheus text exposition format.
+//
+// Metrics are written in stable order, so output can be compared between
+// runs.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	snapshot := m.Snapshot()
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	m.writeRequests(bw, snapshot.Requests)
+	m.writeLatencies(bw, snapshot.Latencies)
+	m.writeFailures(bw, snapshot.Failures)
+
+	err := bw.Flush()
+
+	return cw.n, err
+}
+
+func (m *Metrics) writeRequests(w io.Writer, samples []MetricsRequestSample) {
+	name := m.name("requests_total")
+
+	fmt.Fprintf(w, "# HELP %s Number of sent requests.\n", name)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+
+	for _, sample := range samples {
+		fmt.Fprintf(w, "%s{%s,status=\"%d\"} %d\n",
+			name, metricsLabels(sample.Method, sample.Endpoint),
+			sample.Status, sample.Count)
+	}
+}
+
+func (m *Metrics) writeLatencies(w io.Writer, samples []MetricsLatencySample) {
+	name := m.name("request_duration_seconds")
+
+	fmt.Fprintf(w, "# HELP %s Latency of sent requests.\n", name)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+
+	for _, sample := range samples {
+		labels := metricsLabels(sample.Method, sample.Endpoint)
+
+		bounds := make([]float64, 0, len(sample.Buckets))
+		for bound := range sample.Buckets {
+			bounds = append(bounds, bound)
+		}
+
+		sort.Float64s(bounds)
+
+		for _, bound := range bounds {
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n",
+				name, labels, formatMetricsFloat(bound), sample.Buckets[bound])
+		}
+
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n",
+			name, labels, sample.Count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n",
+			name, labels, formatMetricsFloat(sample.Sum))
+		fmt.Fprintf(w, "%s_count{%s} %d\n",
+			name, labels, sample.Count)
+	}
+}
+
+func (m *Metrics) writeFailures(w io.Writer, samples []MetricsFailureSample) {
+	name := m.name("assertion_failures_total")
+
+	fmt.Fprintf(w, "# HELP %s Number of failed assertions.\n", name)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+
+	for _, sample := range samples {
+		fmt.Fprintf(w, "%s{type=\"%s\"} %d\n",
+			name, escapeMetricsLabel(sample.Type), sample.Count)
+	}
+}
+
+func (m *Metrics) init() {
+	if m.requests == nil {
+		m.requests = make(map[metricsRequestKey]uint64)
+	}
+	if m.latencies == nil {
+		m.latencies = make(map[metricsEndpointKey]*metricsHistogram)
+	}
+	if m.failures == nil {
+		m.failures = make(map[string]uint64)
+	}
+}
+
+func (m *Metrics) name(metric string) string {
+	if m.Namespace != "" {
+		return m.Namespace + "_" + metric
+	}
+	return "httpexpect_" + metric
+}
+
+func (m *Metrics) buckets() []float64 {
+	if m.Buckets != nil {
+		return m.Buckets
+	}
+	return DefaultMetricsBuckets
+}
+
+func (k metricsEndpointKey) less(other metricsEndpointKey) bool {
+	if k.endpoint != other.endpoint {
+		return k.endpoint < other.endpoint
+	}
+	return k.method < other.method
+}
+
+func metricsLabels(method, endpoint string) string {
+	return fmt.Sprintf("method=\"%s\",endpoint=\"%s\"",
+		escapeMetricsLabel(method), escapeMetricsLabel(endpoint))
+}
+
+var metricsLabelReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+)
+
+func escapeMetricsLabel(s string) string {
+	return metricsLabelReplacer.Replace(s)
+}
+
+func formatMetricsFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,259 @@
+package httpexpect
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsWrite(t *testing.T) {
+	metrics := NewMetrics()
+
+	metrics.Buckets = []float64{0.1, 0.5}
+
+	metrics.ObserveRequest("GET", "/user/{id}", 200, 50*time.Millisecond)
+	metrics.ObserveRequest("GET", "/user/{id}", 200, 300*time.Millisecond)
+	metrics.ObserveRequest("GET", "/user/{id}", 404, time.Second)
+	metrics.ObserveRequest("POST", "/user", 201, 100*time.Millisecond)
+
+	metrics.ObserveFailure(&AssertionFailure{Type: AssertEqual})
+	metrics.ObserveFailure(&AssertionFailure{Type: AssertEqual})
+	metrics.ObserveFailure(&AssertionFailure{Type: AssertInRange})
+
+	var buf bytes.Buffer
+
+	n, err := metrics.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	assert.Equal(t,
+		`# HELP httpexpect_requests_total Number of sent requests.
+# TYPE httpexpect_requests_total counter
+httpexpect_requests_total{method="POST",endpoint="/user",status="201"} 1
+httpexpect_requests_total{method="GET",endpoint="/user/{id}",status="200"} 2
+httpexpect_requests_total{method="GET",endpoint="/user/{id}",status="404"} 1
+# HELP httpexpect_request_duration_seconds Latency of sent requests.
+# TYPE httpexpect_request_duration_seconds histogram
+httpexpect_request_duration_seconds_bucket{method="POST",endpoint="/user",le="0.1"} 1
+httpexpect_request_duration_seconds_bucket{method="POST",endpoint="/user",le="0.5"} 1
+httpexpect_request_duration_seconds_bucket{method="POST",endpoint="/user",le="+Inf"} 1
+httpexpect_request_duration_seconds_sum{method="POST",endpoint="/user"} 0.1
+httpexpect_request_duration_seconds_count{method="POST",endpoint="/user"} 1
+httpexpect_request_duration_seconds_bucket{method="GET",endpoint="/user/{id}",le="0.1"} 1
+httpexpect_request_duration_seconds_bucket{method="GET",endpoint="/user/{id}",le="0.5"} 2
+httpexpect_request_duration_seconds_bucket{method="GET",endpoint="/user/{id}",le="+Inf"} 3
+httpexpect_request_duration_seconds_sum{method="GET",endpoint="/user/{id}"} 1.35
+httpexpect_request_duration_seconds_count{method="GET",endpoint="/user/{id}"} 3
+# HELP httpexpect_assertion_failures_total Number of failed assertions.
+# TYPE httpexpect_assertion_failures_total counter
+httpexpect_assertion_failures_total{type="AssertEqual"} 2
+httpexpect_assertion_failures_total{type="AssertInRange"} 1
+`, buf.String())
+}
+
+func TestMetricsSnapshot(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		metrics := NewMetrics()
+
+		snapshot := metrics.Snapshot()
+
+		assert.Empty(t, snapshot.Requests)
+		assert.Empty(t, snapshot.Latencies)
+		assert.Empty(t, snapshot.Failures)
+	})
+
+	t.Run("samples", func(t *testing.T) {
+		metrics := NewMetrics()
+
+		metrics.Buckets = []float64{0.1, 0.5}
+
+		metrics.ObserveRequest("GET", "/user/{id}", 200, 50*time.Millisecond)
+		metrics.ObserveRequest("GET", "/user/{id}", 200, 300*time.Millisecond)
+		metrics.ObserveRequest("GET", "/user/{id}", 404, time.Second)
+		metrics.ObserveRequest("POST", "/user", 201, 100*time.Millisecond)
+
+		metrics.ObserveFailure(&AssertionFailure{Type: AssertEqual})
+		metrics.ObserveFailure(&AssertionFailure{Type: AssertEqual})
+		metrics.ObserveFailure(&AssertionFailure{Type: AssertInRange})
+
+		snapshot := metrics.Snapshot()
+
+		assert.Equal(t, []MetricsRequestSample{
+			{Method: "POST", Endpoint: "/user", Status: 201, Count: 1},
+			{Method: "GET", Endpoint: "/user/{id}", Status: 200, Count: 2},
+			{Method: "GET", Endpoint: "/user/{id}", Status: 404, Count: 1},
+		}, snapshot.Requests)
+
+		if assert.Equal(t, 2, len(snapshot.Latencies)) {
+			assert.Equal(t, "POST", snapshot.Latencies[0].Method)
+			assert.Equal(t, "/user", snapshot.Latencies[0].Endpoint)
+			assert.Equal(t, map[float64]uint64{0.1: 1, 0.5: 1},
+				snapshot.Latencies[0].Buckets)
+			assert.Equal(t, uint64(1), snapshot.Latencies[0].Count)
+			assert.InDelta(t, 0.1, snapshot.Latencies[0].Sum, 1e-9)
+
+			assert.Equal(t, "GET", snapshot.Latencies[1].Method)
+			assert.Equal(t, "/user/{id}", snapshot.Latencies[1].Endpoint)
+			assert.Equal(t, map[float64]uint64{0.1: 1, 0.5: 2},
+				snapshot.Latencies[1].Buckets)
+			assert.Equal(t, uint64(3), snapshot.Latencies[1].Count)
+			assert.InDelta(t, 1.35, snapshot.Latencies[1].Sum, 1e-9)
+		}
+
+		assert.Equal(t, []MetricsFailureSample{
+			{Type: "AssertEqual", Count: 2},
+			{Type: "AssertInRange", Count: 1},
+		}, snapshot.Failures)
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		metrics := NewMetrics()
+
+		metrics.ObserveRequest("GET", "/", 200, 0)
+
+		snapshot := metrics.Snapshot()
+
+		metrics.ObserveRequest("GET", "/", 200, 0)
+		metrics.Reset()
+
+		assert.Equal(t, uint64(1), snapshot.Requests[0].Count)
+		assert.Equal(t, uint64(1), snapshot.Latencies[0].Count)
+	})
+}
+
+func TestMetricsOptions(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		metrics := NewMetrics()
+
+		var buf bytes.Buffer
+
+		_, err := metrics.WriteTo(&buf)
+		assert.NoError(t, err)
+
+		assert.Equal(t,
+			`# HELP httpexpect_requests_total Number of sent requests.
+# TYPE httpexpect_requests_total counter
+# HELP httpexpect_request_duration_seconds Latency of sent requests.
+# TYPE httpexpect_request_duration_seconds histogram
+# HELP httpexpect_assertion_failures_total Number of failed assertions.
+# TYPE httpexpect_assertion_failures_total counter
+`, buf.String())
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		metrics := &Metrics{
+			Namespace: "canary",
+		}
+
+		metrics.ObserveFailure(&AssertionFailure{Type: AssertEqual})
+
+		var buf bytes.Buffer
+
+		_, err := metrics.WriteTo(&buf)
+		assert.NoError(t, err)
+
+		assert.Contains(t, buf.String(),
+			"\ncanary_assertion_failures_total{type=\"AssertEqual\"} 1\n")
+		assert.NotContains(t, buf.String(), "httpexpect_")
+	})
+
+	t.Run("default buckets", func(t *testing.T) {
+		metrics := NewMetrics()
+
+		metrics.ObserveRequest("GET", "/", 200, 20*time.Millisecond)
+
+		var buf bytes.Buffer
+
+		_, err := metrics.WriteTo(&buf)
+		assert.NoError(t, err)
+
+		assert.Contains(t, buf.String(),
+			`{method="GET",endpoint="/",le="0.01"} 0`)
+		assert.Contains(t, buf.String(),
+			`{method="GET",endpoint="/",le="0.025"} 1`)
+		assert.Contains(t, buf.String(),
+			`{method="GET",endpoint="/",le="10"} 1`)
+	})
+
+	t.Run("escaping", func(t *testing.T) {
+		metrics := NewMetrics()
+
+		metrics.ObserveRequest("GET", "/a\"b\\c\nd", 200, 0)
+
+		var buf bytes.Buffer
+
+		_, err := metrics.WriteTo(&buf)
+		assert.NoError(t, err)
+
+		assert.Contains(t, buf.String(),
+			`{method="GET",endpoint="/a\"b\\c\nd",status="200"} 1`)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		metrics := NewMetrics()
+
+		metrics.ObserveRequest("GET", "/", 200, 0)
+		metrics.ObserveFailure(&AssertionFailure{Type: AssertEqual})
+
+		metrics.Reset()
+
+		var buf bytes.Buffer
+
+		_, err := metrics.WriteTo(&buf)
+		assert.NoError(t, err)
+
+		assert.NotContains(t, buf.String(), "{")
+	})
+}
+
+func TestMetricsHandler(t *testing.T) {
+	metrics := NewMetrics()
+
+	metrics.ObserveRequest("GET", "/", 200, 0)
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8",
+		rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(),
+		`httpexpect_requests_total{method="GET",endpoint="/",status="200"} 1`)
+}
+
+func TestMetricsExpect(t *testing.T) {
+	metrics := NewMetrics()
+
+	config := Config{
+		BaseURL: "http://example.com",
+		Client: &mockClient{
+			resp: http.Response{
+				StatusCode: http.StatusOK,
+			},
+		},
+		Reporter: newMockReporter(t),
+		Metrics:  metrics,
+	}
+
+	e := WithConfig(config)
+
+	e.GET("/users/{id}", 1).Expect().Status(http.StatusOK)
+	e.GET("/users/{id}", 2).Expect().Status(http.StatusNotFound)
+	e.Value(123).Number().Equal(456)
+
+	var buf bytes.Buffer
+
+	_, err := metrics.WriteTo(&buf)
+	assert.NoError(t, err)
+
+	assert.Contains(t, buf.String(),
+		`httpexpect_requests_total{method="GET",endpoint="/users/{id}",status="200"} 2`)
+	assert.Contains(t, buf.String(),
+		`httpexpect_request_duration_seconds_count{method="GET",endpoint="/users/{id}"} 2`)
+	assert.Contains(t, buf.String(),
+		`httpexpect_assertion_failures_total{type="AssertEqual"} 2`)
+}
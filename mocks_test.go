@@ -2,11 +2,18 @@ package httpexpect
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 type mockClient struct {
@@ -53,6 +60,23 @@ func (b *mockBody) Close() error {
 	return nil
 }
 
+// reader which reports reads to channel and returns EOF
+type mockNotifyReader struct {
+	reads chan struct{}
+}
+
+func newMockNotifyReader() *mockNotifyReader {
+	return &mockNotifyReader{reads: make(chan struct{}, 1)}
+}
+
+func (r *mockNotifyReader) Read(p []byte) (int, error) {
+	select {
+	case r.reads <- struct{}{}:
+	default:
+	}
+	return 0, io.EOF
+}
+
 func newMockChain(t *testing.T) *chain {
 	return newChainWithDefaults("test", newMockReporter(t))
 }
@@ -123,13 +147,41 @@ func (h *mockAssertionHandler) Failure(
 	h.failure = failure
 }
 
+// like mockAssertionHandler, but safe for concurrent use
+type mockSyncAssertionHandler struct {
+	mu       sync.Mutex
+	failures []*AssertionFailure
+}
+
+func (h *mockSyncAssertionHandler) Success(ctx *AssertionContext) {
+}
+
+func (h *mockSyncAssertionHandler) Failure(
+	ctx *AssertionContext, failure *AssertionFailure,
+) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.failures = append(h.failures, failure)
+}
+
+func (h *mockSyncAssertionHandler) getFailures() []*AssertionFailure {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]*AssertionFailure(nil), h.failures...)
+}
+
 type mockPrinter struct {
-	reqBody  []byte
-	respBody []byte
-	rtt      time.Duration
+	reqHeader  http.Header
+	reqBody    []byte
+	respHeader http.Header
+	respBody   []byte
+	rtt        time.Duration
 }
 
 func (p *mockPrinter) Request(req *http.Request) {
+	p.reqHeader = req.Header
 	if req.Body != nil {
 		p.reqBody, _ = ioutil.ReadAll(req.Body)
 		req.Body.Close()
@@ -137,6 +189,7 @@ func (p *mockPrinter) Request(req *http.Request) {
 }
 
 func (p *mockPrinter) Response(resp *http.Response, rtt time.Duration) {
+	p.respHeader = resp.Header
 	if resp.Body != nil {
 		p.respBody, _ = ioutil.ReadAll(resp.Body)
 		resp.Body.Close()
@@ -179,6 +232,7 @@ type mockWebsocketConn struct {
 	writeDlError error
 	msg          []byte
 	subprotocol  string
+	closed       bool
 }
 
 func newMockWebsocketConn() *mockWebsocketConn {
@@ -235,6 +289,7 @@ func (wc *mockWebsocketConn) WriteMessage(messageType int, data []byte) error {
 }
 
 func (wc *mockWebsocketConn) Close() error {
+	wc.closed = true
 	return wc.closeError
 }
 
@@ -249,3 +304,188 @@ func (wc *mockWebsocketConn) SetWriteDeadline(t time.Time) error {
 func (wc *mockWebsocketConn) Subprotocol() string {
 	return wc.subprotocol
 }
+
+type mockWebsocketFragmentConn struct {
+	mockWebsocketConn
+	fragments        [][]byte
+	writtenType      int
+	writtenFragments [][]byte
+}
+
+func (wc *mockWebsocketFragmentConn) ReadFragments() (int, [][]byte, error) {
+	return wc.msgType, wc.fragments, wc.readMsgErr
+}
+
+func (wc *mockWebsocketFragmentConn) WriteFragments(
+	messageType int, fragments [][]byte,
+) error {
+	wc.writtenType = messageType
+	wc.writtenFragments = fragments
+	return wc.writeMsgErr
+}
+
+type mockWebsocketQueueConn struct {
+	mockWebsocketConn
+	messages [][]byte
+}
+
+func (wc *mockWebsocketQueueConn) ReadMessage() (int, []byte, error) {
+	if len(wc.messages) == 0 {
+		if wc.readMsgErr != nil {
+			return 0, nil, wc.readMsgErr
+		}
+		return 0, nil, errors.New("no more messages")
+	}
+	msg := wc.messages[0]
+	wc.messages = wc.messages[1:]
+	return websocket.TextMessage, msg, nil
+}
+
+type mockWebsocketControlConn struct {
+	mockWebsocketConn
+
+	mu          sync.Mutex
+	pingHandler func(string) error
+	pongHandler func(string) error
+	incoming    []websocketRead
+	written     []websocketRead
+}
+
+func (wc *mockWebsocketControlConn) SetPingHandler(h func(string) error) {
+	wc.pingHandler = h
+}
+
+func (wc *mockWebsocketControlConn) SetPongHandler(h func(string) error) {
+	wc.pongHandler = h
+}
+
+func (wc *mockWebsocketControlConn) WriteControl(
+	messageType int, data []byte, deadline time.Time,
+) error {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+
+	wc.written = append(wc.written, websocketRead{typ: messageType, content: data})
+
+	return wc.writeMsgErr
+}
+
+// handles queued control frames, then returns queued data message,
+// or error if there is no more messages
+func (wc *mockWebsocketControlConn) ReadMessage() (int, []byte, error) {
+	for {
+		wc.mu.Lock()
+		if len(wc.incoming) == 0 {
+			wc.mu.Unlock()
+			if wc.readMsgErr != nil {
+				return 0, nil, wc.readMsgErr
+			}
+			return 0, nil, errors.New("no more messages")
+		}
+		r := wc.incoming[0]
+		wc.incoming = wc.incoming[1:]
+		wc.mu.Unlock()
+
+		switch r.typ {
+		case websocket.PingMessage:
+			_ = wc.pingHandler(string(r.content))
+		case websocket.PongMessage:
+			_ = wc.pongHandler(string(r.content))
+		default:
+			return r.typ, r.content, nil
+		}
+	}
+}
+
+func (wc *mockWebsocketControlConn) writtenFrames() []websocketRead {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+
+	return append([]websocketRead(nil), wc.written...)
+}
+
+// reads messages from "in" channel, until it's closed, and writes
+// messages to "out" channel
+type mockWebsocketChanConn struct {
+	mockWebsocketConn
+	in  chan []byte
+	out chan []byte
+}
+
+func newMockWebsocketChanConn(outSize int) *mockWebsocketChanConn {
+	return &mockWebsocketChanConn{
+		in:  make(chan []byte, 16),
+		out: make(chan []byte, outSize),
+	}
+}
+
+func (wc *mockWebsocketChanConn) ReadMessage() (int, []byte, error) {
+	msg, ok := <-wc.in
+	if !ok {
+		return 0, nil, &websocket.CloseError{Code: websocket.CloseNormalClosure}
+	}
+	return websocket.TextMessage, msg, nil
+}
+
+func (wc *mockWebsocketChanConn) WriteMessage(messageType int, data []byte) error {
+	if wc.writeMsgErr != nil {
+		return wc.writeMsgErr
+	}
+	wc.out <- data
+	return nil
+}
+
+// websocket dialer which is not *websocket.Dialer
+type mockCustomWebsocketDialer struct{}
+
+func (d *mockCustomWebsocketDialer) Dial(
+	url string, reqH http.Header,
+) (*websocket.Conn, *http.Response, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
+// dialer which counts dial attempts and always fails
+type mockDialer struct {
+	dialed int
+}
+
+func (d *mockDialer) DialContext(
+	ctx context.Context, network, addr string,
+) (net.Conn, error) {
+	d.dialed++
+	return nil, errors.New("mock dialer")
+}
+
+// test which records failures and cleanup functions
+type mockTestingTB struct {
+	name     string
+	errors   []string
+	failed   bool
+	cleanups []func()
+}
+
+func (t *mockTestingTB) Name() string {
+	return t.name
+}
+
+func (t *mockTestingTB) Logf(message string, args ...interface{}) {
+}
+
+func (t *mockTestingTB) Errorf(message string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(message, args...))
+}
+
+func (t *mockTestingTB) FailNow() {
+	t.failed = true
+}
+
+func (t *mockTestingTB) Cleanup(fn func()) {
+	t.cleanups = append(t.cleanups, fn)
+}
+
+func (t *mockTestingTB) cleanup() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+	t.cleanups = nil
+}
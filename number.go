@@ -451,3 +451,115 @@ func (n *Number) NotInRange(min, max interface{}) *Number {
 
 	return n
 }
+
+// InList succeeds if number is equal to one of the values from given list.
+//
+// values should have numeric types convertible to float64. Before comparison,
+// they are converted to float64.
+//
+// Example:
+//
+//	number := NewNumber(t, 123)
+//	number.InList(float64(123), int32(123))
+func (n *Number) InList(values ...interface{}) *Number {
+	n.chain.enter("InList()")
+	defer n.chain.leave()
+
+	if n.chain.failed() {
+		return n
+	}
+
+	nums, ok := n.canonList(values)
+	if !ok {
+		return n
+	}
+
+	for _, num := range nums {
+		if n.value == num {
+			return n
+		}
+	}
+
+	n.chain.fail(AssertionFailure{
+		Type:     AssertBelongs,
+		Actual:   &AssertionValue{n.value},
+		Expected: &AssertionValue{numberList(nums)},
+		Errors: []error{
+			errors.New("expected: number is equal to one of the values"),
+		},
+	})
+
+	return n
+}
+
+// NotInList succeeds if number is not equal to any of the values from given
+// list.
+//
+// values should have numeric types convertible to float64. Before comparison,
+// they are converted to float64.
+//
+// Example:
+//
+//	number := NewNumber(t, 123)
+//	number.NotInList(float64(456), int32(456))
+func (n *Number) NotInList(values ...interface{}) *Number {
+	n.chain.enter("NotInList()")
+	defer n.chain.leave()
+
+	if n.chain.failed() {
+		return n
+	}
+
+	nums, ok := n.canonList(values)
+	if !ok {
+		return n
+	}
+
+	for _, num := range nums {
+		if n.value == num {
+			n.chain.fail(AssertionFailure{
+				Type:     AssertNotBelongs,
+				Actual:   &AssertionValue{n.value},
+				Expected: &AssertionValue{numberList(nums)},
+				Errors: []error{
+					errors.New("expected: number is not equal to any of the values"),
+				},
+			})
+			return n
+		}
+	}
+
+	return n
+}
+
+func (n *Number) canonList(values []interface{}) ([]float64, bool) {
+	if len(values) == 0 {
+		n.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected empty list argument"),
+			},
+		})
+		return nil, false
+	}
+
+	nums := make([]float64, 0, len(values))
+
+	for _, v := range values {
+		num, ok := canonNumber(n.chain, v)
+		if !ok {
+			return nil, false
+		}
+		nums = append(nums, num)
+	}
+
+	return nums, true
+}
+
+func numberList(nums []float64) AssertionList {
+	list := make(AssertionList, 0, len(nums))
+	for _, num := range nums {
+		list = append(list, num)
+	}
+	return list
+}
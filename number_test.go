@@ -26,6 +26,8 @@ func TestNumberFailed(t *testing.T) {
 	value.Le(0)
 	value.InRange(0, 0)
 	value.NotInRange(0, 0)
+	value.InList(0)
+	value.NotInList(0)
 }
 
 func TestNumberGetters(t *testing.T) {
@@ -254,6 +256,27 @@ func TestNumberInRange(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestNumberInList(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewNumber(reporter, 123)
+
+	value.InList(123).chain.assertOK(t)
+	value.InList(1, 123, 456).chain.assertOK(t)
+	value.InList(int32(123), uint8(1)).chain.assertOK(t)
+
+	NewNumber(reporter, 123).InList(1, 2).chain.assertFailed(t)
+	NewNumber(reporter, 123).InList().chain.assertFailed(t)
+	NewNumber(reporter, 123).InList("123").chain.assertFailed(t)
+
+	value.NotInList(1, 2).chain.assertOK(t)
+	value.NotInList(int64(1)).chain.assertOK(t)
+
+	NewNumber(reporter, 123).NotInList(1, 123).chain.assertFailed(t)
+	NewNumber(reporter, 123).NotInList().chain.assertFailed(t)
+	NewNumber(reporter, 123).NotInList("1").chain.assertFailed(t)
+}
+
 func TestNumberConvertEqual(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -397,6 +397,57 @@ func (o *Object) NotContainsKey(key string) *Object {
 	return o
 }
 
+// NoExtraKeys succeeds if object doesn't contain keys other than given
+// allowed keys. Allowed keys are not required to be present.
+//
+// Useful to detect accidental exposure of undocumented fields.
+//
+// Example:
+//
+//	object := NewObject(t, map[string]interface{}{"id": 1, "name": "john"})
+//	object.NoExtraKeys("id", "name", "email")
+func (o *Object) NoExtraKeys(allowed ...string) *Object {
+	o.chain.enter("NoExtraKeys()")
+	defer o.chain.leave()
+
+	if o.chain.failed() {
+		return o
+	}
+
+	allowedKeys := make(map[string]bool, len(allowed))
+	for _, key := range allowed {
+		allowedKeys[key] = true
+	}
+
+	var extra []string
+	for key := range o.value {
+		if !allowedKeys[key] {
+			extra = append(extra, key)
+		}
+	}
+
+	if len(extra) != 0 {
+		sort.Strings(extra)
+
+		list := make(AssertionList, 0, len(extra))
+		for _, key := range extra {
+			list = append(list, key)
+		}
+
+		o.chain.fail(AssertionFailure{
+			Type:     AssertNotContainsKey,
+			Actual:   &AssertionValue{o.value},
+			Expected: &AssertionValue{list},
+			Errors: []error{
+				errors.New("expected: map does not contain keys other than allowed"),
+				fmt.Errorf("unexpected keys: %q", extra),
+			},
+		})
+	}
+
+	return o
+}
+
 // ContainsValue succeeds if object contains given value with any key.
 // Before comparison, both object and value are converted to canonical form.
 //
@@ -23,6 +23,7 @@ func TestObjectFailed(t *testing.T) {
 		value.NotEqual(nil)
 		value.ContainsKey("foo")
 		value.NotContainsKey("foo")
+		value.NoExtraKeys("foo")
 		value.ContainsValue("foo")
 		value.NotContainsValue("foo")
 		value.ContainsSubset(nil)
@@ -302,6 +303,46 @@ func TestObjectContainsKey(t *testing.T) {
 	value.chain.reset()
 }
 
+func TestObjectNoExtraKeys(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewObject(reporter, map[string]interface{}{"foo": 123, "bar": ""})
+
+	value.NoExtraKeys("foo", "bar")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.NoExtraKeys("foo", "bar", "baz")
+	value.chain.assertOK(t)
+	value.chain.reset()
+
+	value.NoExtraKeys("foo")
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	value.NoExtraKeys()
+	value.chain.assertFailed(t)
+	value.chain.reset()
+
+	NewObject(reporter, map[string]interface{}{}).NoExtraKeys().
+		chain.assertOK(t)
+
+	handler := &mockAssertionHandler{}
+
+	value = newObject(newChainWithConfig("test", Config{
+		AssertionHandler: handler,
+	}), map[string]interface{}{"a": 1, "b": 2, "c": 3})
+
+	value.NoExtraKeys("b")
+	value.chain.assertFailed(t)
+
+	if assert.NotNil(t, handler.failure) {
+		assert.Equal(t, AssertNotContainsKey, handler.failure.Type)
+		assert.Equal(t, AssertionList{"a", "c"}, handler.failure.Expected.Value)
+		assert.Contains(t, handler.failure.Errors[1].Error(), `["a" "c"]`)
+	}
+}
+
 func TestObjectContainsValue(t *testing.T) {
 	reporter := newMockReporter(t)
 
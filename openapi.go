@@ -0,0 +1,898 @@
+package httpexpect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v2"
+)
+
+// timeout for fetching spec by URL
+const openAPISpecTimeout = 30 * time.Second
+
+// loaded specs, shared by all Expect instances
+var openAPISpecs = struct {
+	sync.Mutex
+	entries map[string]*openAPISpecEntry
+}{
+	entries: map[string]*openAPISpecEntry{},
+}
+
+// result of loading spec from one location; load errors are cached too,
+// so that a bad location is not re-fetched on every request
+type openAPISpecEntry struct {
+	once sync.Once
+	spec *openAPISpec
+	err  error
+}
+
+// returns spec from given file or URL, loading it on first use;
+// URLs are fetched using given client
+func loadOpenAPISpec(client Client, location string) (*openAPISpec, error) {
+	openAPISpecs.Lock()
+	entry := openAPISpecs.entries[location]
+	if entry == nil {
+		entry = &openAPISpecEntry{}
+		openAPISpecs.entries[location] = entry
+	}
+	openAPISpecs.Unlock()
+
+	// global lock is not held here, so loading one spec doesn't block
+	// requests that use other specs
+	entry.once.Do(func() {
+		data, err := readOpenAPISpec(client, location)
+		if err != nil {
+			entry.err = err
+			return
+		}
+
+		entry.spec, entry.err = parseOpenAPISpec(data)
+	})
+
+	return entry.spec, entry.err
+}
+
+func readOpenAPISpec(client Client, location string) ([]byte, error) {
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		if client == nil {
+			client = http.DefaultClient
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), openAPISpecTimeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %q", resp.Status)
+		}
+
+		return ioutil.ReadAll(resp.Body)
+	}
+
+	return ioutil.ReadFile(location)
+}
+
+// OpenAPI 3 document; only parts used for validation are parsed,
+// everything else is accessed as raw JSON values
+type openAPISpec struct {
+	root      map[string]interface{}
+	basePaths []string
+	paths     []*openAPIPath
+
+	mu       sync.Mutex
+	schemas  map[string]*gojsonschema.Schema
+	coverage map[string]map[string]bool
+}
+
+type openAPIPath struct {
+	template string
+	segments []string
+	item     map[string]interface{}
+}
+
+// operation matched by request
+type openAPIOperation struct {
+	path      *openAPIPath
+	method    string
+	operation map[string]interface{}
+}
+
+func parseOpenAPISpec(data []byte) (*openAPISpec, error) {
+	var doc interface{}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		var yamlDoc interface{}
+		if yamlErr := yaml.Unmarshal(data, &yamlDoc); yamlErr != nil {
+			return nil, fmt.Errorf("spec is neither JSON nor YAML: %s", yamlErr)
+		}
+		doc = convertYAML(yamlDoc)
+	}
+
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("spec is not an object")
+	}
+
+	if version, _ := root["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q, expected 3.x",
+			root["openapi"])
+	}
+
+	spec := &openAPISpec{
+		root:    root,
+		schemas: map[string]*gojsonschema.Schema{},
+	}
+
+	spec.basePaths = openAPIBasePaths(root)
+
+	paths, _ := root["paths"].(map[string]interface{})
+	for template, item := range paths {
+		itemMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		spec.paths = append(spec.paths, &openAPIPath{
+			template: template,
+			segments: strings.Split(strings.Trim(template, "/"), "/"),
+			item:     itemMap,
+		})
+	}
+
+	// paths without parameters take precedence over templated ones,
+	// see OpenAPI 3 spec, "Path Templating Matching"
+	sort.SliceStable(spec.paths, func(i, j int) bool {
+		pi, pj := spec.paths[i], spec.paths[j]
+		if ci, cj := countTemplateSegments(pi), countTemplateSegments(pj); ci != cj {
+			return ci < cj
+		}
+		return pi.template < pj.template
+	})
+
+	return spec, nil
+}
+
+func convertYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[fmt.Sprint(key)] = convertYAML(val)
+		}
+		return m
+	case []interface{}:
+		for i := range v {
+			v[i] = convertYAML(v[i])
+		}
+		return v
+	case int:
+		return float64(v)
+	default:
+		return v
+	}
+}
+
+// returns path prefixes of servers, with variables set to default values
+func openAPIBasePaths(root map[string]interface{}) []string {
+	var basePaths []string
+
+	servers, _ := root["servers"].([]interface{})
+	for _, server := range servers {
+		serverMap, _ := server.(map[string]interface{})
+		serverURL, _ := serverMap["url"].(string)
+
+		variables, _ := serverMap["variables"].(map[string]interface{})
+		for name, variable := range variables {
+			variableMap, _ := variable.(map[string]interface{})
+			def, _ := variableMap["default"].(string)
+			serverURL = strings.Replace(serverURL, "{"+name+"}", def, -1)
+		}
+
+		u, err := url.Parse(serverURL)
+		if err != nil {
+			continue
+		}
+
+		if basePath := strings.TrimRight(u.Path, "/"); basePath != "" {
+			basePaths = append(basePaths, basePath)
+		}
+	}
+
+	return basePaths
+}
+
+func countTemplateSegments(p *openAPIPath) int {
+	n := 0
+	for _, seg := range p.segments {
+		if strings.Contains(seg, "{") {
+			n++
+		}
+	}
+	return n
+}
+
+// finds operation for given method and path; returns nil operation and
+// non-nil path if path is found, but method is not defined for it
+func (s *openAPISpec) findOperation(
+	method, path string,
+) (*openAPIOperation, *openAPIPath, map[string]string) {
+	candidates := []string{path}
+	for _, basePath := range s.basePaths {
+		if strings.HasPrefix(path, basePath+"/") || path == basePath {
+			candidates = append([]string{strings.TrimPrefix(path, basePath)},
+				candidates...)
+		}
+	}
+
+	var matched *openAPIPath
+
+	for _, candidate := range candidates {
+		segments := strings.Split(strings.Trim(candidate, "/"), "/")
+
+		for _, p := range s.paths {
+			params, ok := matchOpenAPIPath(p.segments, segments)
+			if !ok {
+				continue
+			}
+
+			if matched == nil {
+				matched = p
+			}
+
+			op, ok := p.item[strings.ToLower(method)].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			return &openAPIOperation{
+				path:      p,
+				method:    strings.ToUpper(method),
+				operation: op,
+			}, p, params
+		}
+	}
+
+	return nil, matched, nil
+}
+
+func matchOpenAPIPath(template, segments []string) (map[string]string, bool) {
+	if len(template) != len(segments) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+
+	for i, seg := range template {
+		open := strings.Index(seg, "{")
+		if open < 0 {
+			if seg != segments[i] {
+				return nil, false
+			}
+			continue
+		}
+
+		// segment may have literal prefix and suffix, like "{id}.json"
+		end := strings.Index(seg, "}")
+		if end < open {
+			return nil, false
+		}
+
+		prefix, suffix := seg[:open], seg[end+1:]
+		value := segments[i]
+
+		if !strings.HasPrefix(value, prefix) || !strings.HasSuffix(value, suffix) ||
+			len(value) <= len(prefix)+len(suffix) {
+			return nil, false
+		}
+
+		decoded, err := url.PathUnescape(value[len(prefix) : len(value)-len(suffix)])
+		if err != nil {
+			return nil, false
+		}
+
+		params[seg[open+1:end]] = decoded
+	}
+
+	return params, true
+}
+
+// resolves local reference, like "#/components/schemas/User"
+func (s *openAPISpec) resolve(value interface{}) (map[string]interface{}, error) {
+	for depth := 0; depth < 32; depth++ {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("expected object")
+		}
+
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return m, nil
+		}
+
+		if !strings.HasPrefix(ref, "#/") {
+			return nil, fmt.Errorf("unsupported $ref %q, only local refs are supported",
+				ref)
+		}
+
+		var cur interface{} = s.root
+		for _, token := range strings.Split(ref[2:], "/") {
+			token = strings.Replace(token, "~1", "/", -1)
+			token = strings.Replace(token, "~0", "~", -1)
+
+			curMap, ok := cur.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unresolved $ref %q", ref)
+			}
+			if cur, ok = curMap[token]; !ok {
+				return nil, fmt.Errorf("unresolved $ref %q", ref)
+			}
+		}
+
+		value = cur
+	}
+
+	return nil, errors.New("too deep $ref chain")
+}
+
+// validates value against schema located at given key; returns violations
+func (s *openAPISpec) validateSchema(
+	key string, schema interface{}, value interface{},
+) []error {
+	compiled, err := s.compileSchema(key, schema)
+	if err != nil {
+		return []error{fmt.Errorf("%s: invalid schema: %s", key, err)}
+	}
+
+	result, err := compiled.Validate(gojsonschema.NewGoLoader(value))
+	if err != nil {
+		return []error{fmt.Errorf("%s: %s", key, err)}
+	}
+
+	var errs []error
+	for _, e := range result.Errors() {
+		errs = append(errs, fmt.Errorf("%s: %s", key, e))
+	}
+
+	return errs
+}
+
+func (s *openAPISpec) compileSchema(
+	key string, schema interface{},
+) (*gojsonschema.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if compiled, ok := s.schemas[key]; ok {
+		return compiled, nil
+	}
+
+	// schema is embedded into a document with spec components, so that
+	// local refs can be resolved by validator
+	doc := map[string]interface{}{
+		"allOf":      []interface{}{convertOpenAPISchema(schema)},
+		"components": convertOpenAPISchema(s.root["components"]),
+	}
+
+	compiled, err := gojsonschema.NewSchema(gojsonschema.NewGoLoader(doc))
+	if err != nil {
+		return nil, err
+	}
+
+	s.schemas[key] = compiled
+
+	return compiled, nil
+}
+
+// converts OpenAPI 3.0 schema extensions to JSON Schema
+func convertOpenAPISchema(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[key] = convertOpenAPISchema(val)
+		}
+		if nullable, _ := m["nullable"].(bool); nullable {
+			if typ, ok := m["type"].(string); ok {
+				m["type"] = []interface{}{typ, "null"}
+			}
+			delete(m, "nullable")
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, val := range v {
+			s[i] = convertOpenAPISchema(val)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// validates request and response against spec; returns matched operation
+// (if any) and violations
+//
+// If strict is true, object fields of JSON response body that are not
+// declared in response schema are reported too
+func (s *openAPISpec) validate(
+	httpReq *http.Request, reqBody []byte,
+	httpResp *http.Response, respBody []byte, strict bool,
+) (*openAPIOperation, []error) {
+	op, path, params := s.findOperation(httpReq.Method, httpReq.URL.Path)
+
+	if path == nil {
+		return nil, []error{
+			fmt.Errorf("path %q is not defined in spec", httpReq.URL.Path),
+		}
+	}
+	if op == nil {
+		return nil, []error{
+			fmt.Errorf("method %s is not defined in spec for path %q",
+				httpReq.Method, path.template),
+		}
+	}
+
+	s.cover(op, httpResp.StatusCode)
+
+	var errs []error
+
+	errs = append(errs, s.validateParameters(op, httpReq, params)...)
+	errs = append(errs, s.validateRequestBody(op, httpReq, reqBody)...)
+	errs = append(errs, s.validateResponse(op, httpResp, respBody, strict)...)
+
+	return op, errs
+}
+
+func (s *openAPISpec) validateParameters(
+	op *openAPIOperation, httpReq *http.Request, pathParams map[string]string,
+) []error {
+	var errs []error
+
+	// operation parameters override path item parameters with the same
+	// name and location
+	parameters := map[string]map[string]interface{}{}
+	var order []string
+
+	for _, list := range []interface{}{
+		op.path.item["parameters"], op.operation["parameters"],
+	} {
+		items, _ := list.([]interface{})
+		for _, item := range items {
+			param, err := s.resolve(item)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("parameter: %s", err))
+				continue
+			}
+			name, _ := param["name"].(string)
+			in, _ := param["in"].(string)
+			key := in + ":" + name
+			if _, ok := parameters[key]; !ok {
+				order = append(order, key)
+			}
+			parameters[key] = param
+		}
+	}
+
+	query := httpReq.URL.Query()
+
+	for _, key := range order {
+		param := parameters[key]
+
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		required, _ := param["required"].(bool)
+
+		var values []string
+
+		switch in {
+		case "path":
+			if v, ok := pathParams[name]; ok {
+				values = []string{v}
+			}
+			required = true
+		case "query":
+			values = query[name]
+		case "header":
+			values = httpReq.Header.Values(name)
+		case "cookie":
+			if c, err := httpReq.Cookie(name); err == nil {
+				values = []string{c.Value}
+			}
+		default:
+			continue
+		}
+
+		if len(values) == 0 {
+			if required {
+				errs = append(errs,
+					fmt.Errorf("missing required %s parameter %q", in, name))
+			}
+			continue
+		}
+
+		schema, ok := param["schema"]
+		if !ok {
+			continue
+		}
+
+		schemaMap, err := s.resolve(schema)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s parameter %q: %s", in, name, err))
+			continue
+		}
+
+		value, err := parseOpenAPIParameter(schemaMap, values, s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s parameter %q: %s", in, name, err))
+			continue
+		}
+
+		errs = append(errs, s.validateSchema(
+			fmt.Sprintf("%s %s %s parameter %q",
+				op.method, op.path.template, in, name),
+			schema, value)...)
+	}
+
+	return errs
+}
+
+// converts parameter string values to value of schema type
+func parseOpenAPIParameter(
+	schema map[string]interface{}, values []string, s *openAPISpec,
+) (interface{}, error) {
+	typ, _ := schema["type"].(string)
+
+	if typ == "array" {
+		items, _ := s.resolve(schema["items"])
+
+		if len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+
+		ret := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			item, err := parseOpenAPIScalar(items, v)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, item)
+		}
+		return ret, nil
+	}
+
+	return parseOpenAPIScalar(schema, values[0])
+}
+
+func parseOpenAPIScalar(
+	schema map[string]interface{}, value string,
+) (interface{}, error) {
+	typ, _ := schema["type"].(string)
+
+	switch typ {
+	case "integer":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected integer, got %q", value)
+		}
+		return n, nil
+	case "number":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected number, got %q", value)
+		}
+		return f, nil
+	case "boolean":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected boolean, got %q", value)
+		}
+		return b, nil
+	default:
+		return value, nil
+	}
+}
+
+func (s *openAPISpec) validateRequestBody(
+	op *openAPIOperation, httpReq *http.Request, body []byte,
+) []error {
+	requestBody, ok := op.operation["requestBody"]
+	if !ok {
+		return nil
+	}
+
+	requestBodyMap, err := s.resolve(requestBody)
+	if err != nil {
+		return []error{fmt.Errorf("request body: %s", err)}
+	}
+
+	if len(body) == 0 {
+		if required, _ := requestBodyMap["required"].(bool); required {
+			return []error{errors.New("missing required request body")}
+		}
+		return nil
+	}
+
+	content, _ := requestBodyMap["content"].(map[string]interface{})
+
+	return s.validateContent(
+		fmt.Sprintf("%s %s request body", op.method, op.path.template),
+		content, httpReq.Header.Get("Content-Type"), body, false)
+}
+
+func (s *openAPISpec) validateResponse(
+	op *openAPIOperation, httpResp *http.Response, body []byte, strict bool,
+) []error {
+	responses, _ := op.operation["responses"].(map[string]interface{})
+
+	_, response, ok := matchOpenAPIResponse(responses, httpResp.StatusCode)
+	if !ok {
+		return []error{
+			fmt.Errorf("response status %d is not defined in spec for %s %s",
+				httpResp.StatusCode, op.method, op.path.template),
+		}
+	}
+
+	responseMap, err := s.resolve(response)
+	if err != nil {
+		return []error{fmt.Errorf("response: %s", err)}
+	}
+
+	var errs []error
+
+	headers, _ := responseMap["headers"].(map[string]interface{})
+	for name, header := range headers {
+		headerMap, err := s.resolve(header)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("response header %q: %s", name, err))
+			continue
+		}
+		required, _ := headerMap["required"].(bool)
+		if required && httpResp.Header.Get(name) == "" {
+			errs = append(errs, fmt.Errorf("missing required response header %q", name))
+		}
+	}
+
+	if len(body) == 0 {
+		return errs
+	}
+
+	content, _ := responseMap["content"].(map[string]interface{})
+	if len(content) == 0 {
+		return append(errs, fmt.Errorf(
+			"response body is not defined in spec for status %d", httpResp.StatusCode))
+	}
+
+	return append(errs, s.validateContent(
+		fmt.Sprintf("%s %s response %d body", op.method, op.path.template,
+			httpResp.StatusCode),
+		content, httpResp.Header.Get("Content-Type"), body, strict)...)
+}
+
+// finds response for given status code; returns key of matched response,
+// like "200", "2XX", or "default"
+func matchOpenAPIResponse(
+	responses map[string]interface{}, statusCode int,
+) (string, interface{}, bool) {
+	status := strconv.Itoa(statusCode)
+
+	keys := []string{status, status[:1] + "XX", status[:1] + "xx", "default"}
+
+	for _, key := range keys {
+		if response, ok := responses[key]; ok {
+			return key, response, true
+		}
+	}
+
+	return "", nil, false
+}
+
+func (s *openAPISpec) validateContent(
+	key string, content map[string]interface{}, contentType string, body []byte,
+	strict bool,
+) []error {
+	if len(content) == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+
+	media, matched, ok := matchOpenAPIMediaType(content, mediaType)
+	if !ok {
+		var expected []string
+		for mt := range content {
+			expected = append(expected, mt)
+		}
+		sort.Strings(expected)
+		return []error{
+			fmt.Errorf("%s: content type %q is not one of %q",
+				key, contentType, expected),
+		}
+	}
+
+	schema, ok := media["schema"]
+	if !ok || !isJSONMediaType(mediaType) {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []error{fmt.Errorf("%s: invalid JSON: %s", key, err)}
+	}
+
+	key += " (" + matched + ")"
+
+	errs := s.validateSchema(key, schema, value)
+
+	if strict {
+		var fields []string
+		s.undeclaredFields(schema, value, "$", 0, &fields)
+
+		for _, field := range fields {
+			errs = append(errs, fmt.Errorf("%s: undocumented field %s", key, field))
+		}
+	}
+
+	return errs
+}
+
+// collects JSONPath's of object fields that are present in value, but are
+// not declared in schema
+//
+// Objects with additionalProperties and objects without declared properties
+// (free-form objects) may have any fields; in the former case, undeclared
+// fields are checked against additionalProperties schema. Properties from
+// allOf, anyOf, and oneOf subschemas are merged.
+func (s *openAPISpec) undeclaredFields(
+	schema interface{}, value interface{}, path string, depth int, fields *[]string,
+) {
+	if depth > 32 {
+		return
+	}
+
+	subschemas := s.flattenSchema(schema, 0)
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties := map[string]interface{}{}
+		var additional interface{}
+		open, closed := false, false
+
+		for _, sub := range subschemas {
+			props, _ := sub["properties"].(map[string]interface{})
+			for name, prop := range props {
+				if _, ok := properties[name]; !ok {
+					properties[name] = prop
+				}
+			}
+
+			switch ap := sub["additionalProperties"].(type) {
+			case bool:
+				if ap {
+					open = true
+				} else {
+					closed = true
+				}
+			case map[string]interface{}:
+				if additional == nil {
+					additional = ap
+				}
+			}
+		}
+
+		if open || (len(properties) == 0 && additional == nil && !closed) {
+			return
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fieldPath := path + "." + key
+
+			if prop, ok := properties[key]; ok {
+				s.undeclaredFields(prop, v[key], fieldPath, depth+1, fields)
+			} else if additional != nil {
+				s.undeclaredFields(additional, v[key], fieldPath, depth+1, fields)
+			} else {
+				*fields = append(*fields, fieldPath)
+			}
+		}
+
+	case []interface{}:
+		var items interface{}
+		for _, sub := range subschemas {
+			if items = sub["items"]; items != nil {
+				break
+			}
+		}
+
+		if items == nil {
+			return
+		}
+
+		for i, elem := range v {
+			s.undeclaredFields(items, elem, fmt.Sprintf("%s[%d]", path, i),
+				depth+1, fields)
+		}
+	}
+}
+
+// returns resolved schema and all its allOf, anyOf, and oneOf subschemas
+func (s *openAPISpec) flattenSchema(
+	schema interface{}, depth int,
+) []map[string]interface{} {
+	if depth > 32 {
+		return nil
+	}
+
+	m, err := s.resolve(schema)
+	if err != nil {
+		return nil
+	}
+
+	result := []map[string]interface{}{m}
+
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		subs, _ := m[keyword].([]interface{})
+		for _, sub := range subs {
+			result = append(result, s.flattenSchema(sub, depth+1)...)
+		}
+	}
+
+	return result
+}
+
+func matchOpenAPIMediaType(
+	content map[string]interface{}, mediaType string,
+) (map[string]interface{}, string, bool) {
+	candidates := []string{mediaType}
+	if slash := strings.Index(mediaType, "/"); slash >= 0 {
+		candidates = append(candidates, mediaType[:slash]+"/*")
+	}
+	candidates = append(candidates, "*/*")
+
+	for _, candidate := range candidates {
+		for mt, media := range content {
+			if strings.EqualFold(mt, candidate) {
+				mediaMap, _ := media.(map[string]interface{})
+				return mediaMap, mt, true
+			}
+		}
+	}
+
+	return nil, "", false
+}
+
+var jsonMediaTypeRegexp = regexp.MustCompile(`^application/([\w.\-]+\+)?json$`)
+
+func isJSONMediaType(mediaType string) bool {
+	return jsonMediaTypeRegexp.MatchString(strings.ToLower(mediaType))
+}
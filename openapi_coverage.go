@@ -0,0 +1,413 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// OpenAPICoverageReport describes which operations and responses of
+// OpenAPI spec were exercised by requests.
+type OpenAPICoverageReport struct {
+	// Location of spec, as set in Config.OpenAPISpec.
+	Spec string `json:"spec"`
+
+	// Number of operations defined in spec and exercised by requests.
+	TotalOperations   int `json:"total_operations"`
+	CoveredOperations int `json:"covered_operations"`
+
+	// Number of responses defined in spec and exercised by requests.
+	TotalResponses   int `json:"total_responses"`
+	CoveredResponses int `json:"covered_responses"`
+
+	// Operations sorted by path and method.
+	Operations []OpenAPIOperationCoverage `json:"operations"`
+}
+
+// OpenAPIOperationCoverage describes coverage of a single operation.
+type OpenAPIOperationCoverage struct {
+	// Operation method, e.g. "GET".
+	Method string `json:"method"`
+
+	// Path template, e.g. "/users/{id}".
+	Path string `json:"path"`
+
+	// True if at least one request matched operation.
+	Covered bool `json:"covered"`
+
+	// Responses sorted by status.
+	Responses []OpenAPIResponseCoverage `json:"responses"`
+}
+
+// OpenAPIResponseCoverage describes coverage of a single response.
+type OpenAPIResponseCoverage struct {
+	// Response key as defined in spec, e.g. "200", "4XX", or "default".
+	Status string `json:"status"`
+
+	// True if at least one response matched it.
+	Covered bool `json:"covered"`
+}
+
+// OpenAPICoverage provides methods to inspect coverage of OpenAPI spec.
+//
+// Coverage is recorded when requests and responses are validated against
+// Config.OpenAPISpec, see Expect.OpenAPICoverage. Requests sent with
+// Request.WithoutSpecValidation are not recorded.
+type OpenAPICoverage struct {
+	chain  *chain
+	report OpenAPICoverageReport
+}
+
+// OpenAPICoverage returns a new OpenAPICoverage instance with coverage of
+// spec set in Config.OpenAPISpec.
+//
+// Coverage is shared by all Expect instances using the same spec location,
+// so it should be inspected at the end of test suite, e.g. in TestMain.
+//
+// Example:
+//
+//	cov := e.OpenAPICoverage()
+//
+//	cov.Operations().Ge(0.9)
+//	cov.Responses().Ge(0.7)
+//	cov.WriteHTML("coverage/openapi.html")
+func (e *Expect) OpenAPICoverage() *OpenAPICoverage {
+	e.chain.enter("OpenAPICoverage()")
+	defer e.chain.leave()
+
+	cov := &OpenAPICoverage{
+		chain: e.chain.clone(),
+	}
+
+	if e.config.OpenAPISpec == "" {
+		cov.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected empty Config.OpenAPISpec"),
+			},
+		})
+		return cov
+	}
+
+	spec, err := loadOpenAPISpec(e.config.Client, e.config.OpenAPISpec)
+	if err != nil {
+		cov.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{e.config.OpenAPISpec},
+			Errors: []error{
+				errors.New("expected: valid OpenAPI spec"),
+				err,
+			},
+		})
+		return cov
+	}
+
+	cov.report = spec.coverageReport()
+	cov.report.Spec = e.config.OpenAPISpec
+
+	return cov
+}
+
+// Raw returns underlying coverage report.
+func (c *OpenAPICoverage) Raw() OpenAPICoverageReport {
+	return c.report
+}
+
+// Operations returns a new Number instance with ratio of covered operations,
+// in range [0; 1]. If spec has no operations, ratio is 1.
+//
+// Example:
+//
+//	e.OpenAPICoverage().Operations().Ge(0.9)
+func (c *OpenAPICoverage) Operations() *Number {
+	c.chain.enter("Operations()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newNumber(c.chain, 0)
+	}
+
+	return newNumber(c.chain,
+		coverageRatio(c.report.CoveredOperations, c.report.TotalOperations))
+}
+
+// Responses returns a new Number instance with ratio of covered responses,
+// in range [0; 1]. If spec has no responses, ratio is 1.
+//
+// Response is covered if a response status matched it, e.g. "4XX" is
+// covered by 404 if there is no "404" response in operation.
+//
+// Example:
+//
+//	e.OpenAPICoverage().Responses().Ge(0.7)
+func (c *OpenAPICoverage) Responses() *Number {
+	c.chain.enter("Responses()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newNumber(c.chain, 0)
+	}
+
+	return newNumber(c.chain,
+		coverageRatio(c.report.CoveredResponses, c.report.TotalResponses))
+}
+
+// Uncovered returns a new Array instance with operations not exercised by
+// requests, in "METHOD path" form.
+//
+// Example:
+//
+//	e.OpenAPICoverage().Uncovered().Empty()
+func (c *OpenAPICoverage) Uncovered() *Array {
+	c.chain.enter("Uncovered()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newArray(c.chain, nil)
+	}
+
+	items := []interface{}{}
+
+	for _, op := range c.report.Operations {
+		if !op.Covered {
+			items = append(items, op.Method+" "+op.Path)
+		}
+	}
+
+	return newArray(c.chain, items)
+}
+
+// UncoveredResponses returns a new Array instance with responses not
+// exercised by requests, in "METHOD path status" form.
+//
+// Example:
+//
+//	e.OpenAPICoverage().UncoveredResponses().
+//	    NotContains("POST /users 201")
+func (c *OpenAPICoverage) UncoveredResponses() *Array {
+	c.chain.enter("UncoveredResponses()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newArray(c.chain, nil)
+	}
+
+	items := []interface{}{}
+
+	for _, op := range c.report.Operations {
+		for _, resp := range op.Responses {
+			if !resp.Covered {
+				items = append(items, op.Method+" "+op.Path+" "+resp.Status)
+			}
+		}
+	}
+
+	return newArray(c.chain, items)
+}
+
+// WriteJSON writes coverage report in JSON format to given file.
+//
+// Example:
+//
+//	e.OpenAPICoverage().WriteJSON("coverage/openapi.json")
+func (c *OpenAPICoverage) WriteJSON(path string) *OpenAPICoverage {
+	c.chain.enter("WriteJSON(%q)", path)
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	data, err := json.MarshalIndent(c.report, "", "  ")
+	if err != nil {
+		c.failWrite(err)
+		return c
+	}
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		c.failWrite(err)
+	}
+
+	return c
+}
+
+// WriteHTML writes coverage report in HTML format to given file.
+//
+// Example:
+//
+//	e.OpenAPICoverage().WriteHTML("coverage/openapi.html")
+func (c *OpenAPICoverage) WriteHTML(path string) *OpenAPICoverage {
+	c.chain.enter("WriteHTML(%q)", path)
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		c.failWrite(err)
+		return c
+	}
+
+	err = openAPICoverageTemplate.Execute(f, c.report)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		c.failWrite(err)
+	}
+
+	return c
+}
+
+func (c *OpenAPICoverage) failWrite(err error) {
+	c.chain.fail(AssertionFailure{
+		Type: AssertOperation,
+		Errors: []error{
+			errors.New("failed to write coverage report"),
+			err,
+		},
+	})
+}
+
+func coverageRatio(covered, total int) float64 {
+	if total == 0 {
+		return 1
+	}
+	return float64(covered) / float64(total)
+}
+
+var openAPICoverageTemplate = template.Must(template.New("coverage").Funcs(
+	template.FuncMap{
+		"percent": func(covered, total int) string {
+			return fmt.Sprintf("%.1f%%", coverageRatio(covered, total)*100)
+		},
+	}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>OpenAPI coverage: {{.Spec}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+.covered { background: #dfd; }
+.uncovered { background: #fdd; }
+</style>
+</head>
+<body>
+<h1>OpenAPI coverage: {{.Spec}}</h1>
+<p>Operations: {{.CoveredOperations}} / {{.TotalOperations}}
+({{percent .CoveredOperations .TotalOperations}})</p>
+<p>Responses: {{.CoveredResponses}} / {{.TotalResponses}}
+({{percent .CoveredResponses .TotalResponses}})</p>
+<table>
+<tr><th>Method</th><th>Path</th><th>Responses</th></tr>
+{{- range .Operations}}
+<tr>
+<td class="{{if .Covered}}covered{{else}}uncovered{{end}}">{{.Method}}</td>
+<td class="{{if .Covered}}covered{{else}}uncovered{{end}}">{{.Path}}</td>
+<td>
+{{- range .Responses}}
+<span class="{{if .Covered}}covered{{else}}uncovered{{end}}">{{.Status}}</span>
+{{- end}}
+</td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`))
+
+var openAPIMethods = []string{
+	"get", "put", "post", "delete", "options", "head", "patch", "trace",
+}
+
+// records that operation was exercised with given response status
+func (s *openAPISpec) cover(op *openAPIOperation, statusCode int) {
+	responses, _ := op.operation["responses"].(map[string]interface{})
+	status, _, _ := matchOpenAPIResponse(responses, statusCode)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.coverage == nil {
+		s.coverage = map[string]map[string]bool{}
+	}
+
+	key := op.method + " " + op.path.template
+
+	if s.coverage[key] == nil {
+		s.coverage[key] = map[string]bool{}
+	}
+
+	if status != "" {
+		s.coverage[key][status] = true
+	}
+}
+
+func (s *openAPISpec) coverageReport() OpenAPICoverageReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var report OpenAPICoverageReport
+
+	paths := append([]*openAPIPath(nil), s.paths...)
+	sort.Slice(paths, func(i, j int) bool {
+		return paths[i].template < paths[j].template
+	})
+
+	for _, p := range paths {
+		for _, method := range openAPIMethods {
+			operation, ok := p.item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			method = strings.ToUpper(method)
+			covered, isCovered := s.coverage[method+" "+p.template]
+
+			op := OpenAPIOperationCoverage{
+				Method:  method,
+				Path:    p.template,
+				Covered: isCovered,
+			}
+
+			responses, _ := operation["responses"].(map[string]interface{})
+
+			statuses := make([]string, 0, len(responses))
+			for status := range responses {
+				statuses = append(statuses, status)
+			}
+			sort.Strings(statuses)
+
+			for _, status := range statuses {
+				op.Responses = append(op.Responses, OpenAPIResponseCoverage{
+					Status:  status,
+					Covered: covered[status],
+				})
+
+				report.TotalResponses++
+				if covered[status] {
+					report.CoveredResponses++
+				}
+			}
+
+			report.Operations = append(report.Operations, op)
+
+			report.TotalOperations++
+			if isCovered {
+				report.CoveredOperations++
+			}
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,140 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPICoverage(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v1/users/me":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == "POST" && r.URL.Path == "/v1/users":
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	specPath := writeOpenAPISpec(t, testOpenAPISpec)
+
+	newExpect := func() (*Expect, *mockAssertionHandler) {
+		assertionHandler := &mockAssertionHandler{}
+
+		return WithConfig(Config{
+			BaseURL:          "http://example.com/v1",
+			Client:           &http.Client{Transport: NewBinder(handler)},
+			AssertionHandler: assertionHandler,
+			OpenAPISpec:      specPath,
+		}), assertionHandler
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		e, assertionHandler := newExpect()
+
+		cov := e.OpenAPICoverage()
+
+		cov.Operations().Equal(0)
+		cov.Responses().Equal(0)
+		cov.Uncovered().ContainsOnly(
+			"GET /users", "POST /users", "GET /users/me",
+			"GET /users/{id}", "DELETE /users/{id}")
+
+		assert.Nil(t, assertionHandler.failure)
+	})
+
+	t.Run("covered", func(t *testing.T) {
+		e, assertionHandler := newExpect()
+
+		e.GET("/users/me").Expect()
+		e.POST("/users").WithJSON(map[string]interface{}{"name": "john"}).Expect()
+		e.DELETE("/users/{id}", 1).Expect()
+
+		// not recorded
+		e.GET("/users").WithoutSpecValidation().Expect()
+
+		// recorded, even though response is invalid
+		e2, _ := newExpect()
+		e2.GET("/users/{id}", 1).Expect()
+
+		cov := e.OpenAPICoverage()
+
+		cov.Operations().Equal(0.8)
+		cov.Responses().Equal(4.0 / 7.0)
+		cov.Uncovered().ContainsOnly("GET /users")
+		cov.UncoveredResponses().ContainsOnly(
+			"GET /users 200", "POST /users 4XX", "GET /users/{id} 200")
+
+		report := cov.Raw()
+		assert.Equal(t, specPath, report.Spec)
+		assert.Equal(t, 5, report.TotalOperations)
+		assert.Equal(t, 4, report.CoveredOperations)
+		assert.Equal(t, 7, report.TotalResponses)
+		assert.Equal(t, 4, report.CoveredResponses)
+
+		require.Equal(t, 5, len(report.Operations))
+		assert.Equal(t, OpenAPIOperationCoverage{
+			Method:  "GET",
+			Path:    "/users/{id}",
+			Covered: true,
+			Responses: []OpenAPIResponseCoverage{
+				{Status: "200", Covered: false},
+				{Status: "default", Covered: true},
+			},
+		}, report.Operations[3])
+
+		assert.Nil(t, assertionHandler.failure)
+	})
+
+	t.Run("write", func(t *testing.T) {
+		e, assertionHandler := newExpect()
+
+		dir := filepath.Dir(specPath)
+
+		e.OpenAPICoverage().
+			WriteJSON(filepath.Join(dir, "coverage.json")).
+			WriteHTML(filepath.Join(dir, "coverage.html"))
+
+		assert.Nil(t, assertionHandler.failure)
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, "coverage.json"))
+		require.NoError(t, err)
+
+		var report OpenAPICoverageReport
+		require.NoError(t, json.Unmarshal(data, &report))
+		assert.Equal(t, e.OpenAPICoverage().Raw(), report)
+
+		data, err = ioutil.ReadFile(filepath.Join(dir, "coverage.html"))
+		require.NoError(t, err)
+		assert.True(t, strings.Contains(string(data), "/users/{id}"))
+		assert.True(t, strings.Contains(string(data), "Operations: 4 / 5"))
+
+		e.OpenAPICoverage().WriteJSON(filepath.Join(dir, "missing", "coverage.json"))
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertOperation, assertionHandler.failure.Type)
+	})
+
+	t.Run("no spec", func(t *testing.T) {
+		assertionHandler := &mockAssertionHandler{}
+
+		e := WithConfig(Config{
+			AssertionHandler: assertionHandler,
+		})
+
+		e.OpenAPICoverage().Operations().Equal(1)
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertUsage, assertionHandler.failure.Type)
+	})
+}
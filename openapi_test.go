@@ -0,0 +1,627 @@
+package httpexpect
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOpenAPISpec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1.0"
+servers:
+  - url: http://example.com/{version}
+    variables:
+      version:
+        default: v1
+paths:
+  /users:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100
+        - name: tags
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+      responses:
+        "200":
+          description: ok
+          headers:
+            X-Total:
+              required: true
+              schema:
+                type: integer
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/User"
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: "#/components/schemas/User"
+      responses:
+        "201":
+          description: created
+        4XX:
+          $ref: "#/components/responses/Error"
+  /users/me:
+    get:
+      responses:
+        "200":
+          description: ok
+  /users/{id}:
+    parameters:
+      - $ref: "#/components/parameters/UserID"
+    get:
+      parameters:
+        - name: X-Request-ID
+          in: header
+          required: true
+          schema:
+            type: string
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+        default:
+          $ref: "#/components/responses/Error"
+    delete:
+      responses:
+        "204":
+          description: deleted
+components:
+  parameters:
+    UserID:
+      name: id
+      in: path
+      required: true
+      schema:
+        type: integer
+        minimum: 1
+  responses:
+    Error:
+      description: error
+      content:
+        application/problem+json:
+          schema:
+            type: object
+            required: [title]
+            properties:
+              title:
+                type: string
+  schemas:
+    User:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        email:
+          type: string
+          nullable: true
+`
+
+func writeOpenAPISpec(t *testing.T, spec string) string {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+
+	path := filepath.Join(dir, "spec.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(spec), 0644))
+
+	return path
+}
+
+func TestOpenAPIFindOperation(t *testing.T) {
+	spec, err := parseOpenAPISpec([]byte(testOpenAPISpec))
+	require.NoError(t, err)
+
+	cases := []struct {
+		method   string
+		path     string
+		template string
+		params   map[string]string
+		found    bool
+	}{
+		{"GET", "/v1/users", "/users", map[string]string{}, true},
+		{"GET", "/users", "/users", map[string]string{}, true},
+		{"GET", "/v1/users/me", "/users/me", map[string]string{}, true},
+		{"GET", "/v1/users/12", "/users/{id}", map[string]string{"id": "12"}, true},
+		{"DELETE", "/v1/users/me", "/users/{id}", map[string]string{"id": "me"}, true},
+		{"PUT", "/v1/users/12", "/users/{id}", nil, false},
+		{"GET", "/v1/orders", "", nil, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			op, path, params := spec.findOperation(tc.method, tc.path)
+
+			if tc.found {
+				require.NotNil(t, op)
+				assert.Equal(t, tc.template, op.path.template)
+				assert.Equal(t, tc.params, params)
+			} else {
+				assert.Nil(t, op)
+			}
+
+			if tc.template != "" {
+				require.NotNil(t, path)
+				assert.Equal(t, tc.template, path.template)
+			} else {
+				assert.Nil(t, path)
+			}
+		})
+	}
+}
+
+func TestOpenAPIParseSpec(t *testing.T) {
+	_, err := parseOpenAPISpec([]byte(`{"openapi": "3.1.0", "paths": {}}`))
+	assert.NoError(t, err)
+
+	_, err = parseOpenAPISpec([]byte(`{"swagger": "2.0"}`))
+	assert.Error(t, err)
+
+	_, err = parseOpenAPISpec([]byte(`[1, 2]`))
+	assert.Error(t, err)
+
+	_, err = parseOpenAPISpec([]byte("\tbad: [yaml"))
+	assert.Error(t, err)
+}
+
+func TestOpenAPIValidation(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v1/users":
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Query().Get("limit") != "1" {
+				w.Header().Set("X-Total", "2")
+			}
+			_, _ = w.Write([]byte(`[{"name": "john", "email": null}, {"email": "x"}]`))
+
+		case r.Method == "POST" && r.URL.Path == "/v1/users":
+			body, _ := ioutil.ReadAll(r.Body)
+			if strings.Contains(string(body), "name") {
+				w.WriteHeader(http.StatusCreated)
+			} else {
+				w.Header().Set("Content-Type", "application/problem+json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"title": "bad request"}`))
+			}
+
+		case r.Method == "GET" && r.URL.Path == "/v1/users/1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"name": "john"}`))
+
+		default:
+			w.WriteHeader(http.StatusTeapot)
+		}
+	})
+
+	specPath := writeOpenAPISpec(t, testOpenAPISpec)
+
+	newExpect := func(t *testing.T) (*Expect, *mockAssertionHandler) {
+		assertionHandler := &mockAssertionHandler{}
+
+		return WithConfig(Config{
+			BaseURL:          "http://example.com/v1",
+			Client:           &http.Client{Transport: NewBinder(handler)},
+			AssertionHandler: assertionHandler,
+			OpenAPISpec:      specPath,
+		}), assertionHandler
+	}
+
+	cases := []struct {
+		name    string
+		request func(e *Expect) *Request
+		errors  []string
+	}{
+		{
+			name: "valid get",
+			request: func(e *Expect) *Request {
+				return e.GET("/users/1").WithHeader("X-Request-ID", "1")
+			},
+		},
+		{
+			name: "valid post",
+			request: func(e *Expect) *Request {
+				return e.POST("/users").WithJSON(map[string]interface{}{"name": "john"})
+			},
+		},
+		{
+			name: "documented error",
+			request: func(e *Expect) *Request {
+				return e.POST("/users").WithJSON(map[string]interface{}{"email": "x"})
+			},
+			errors: []string{`request body`},
+		},
+		{
+			name: "unknown path",
+			request: func(e *Expect) *Request {
+				return e.GET("/orders")
+			},
+			errors: []string{`path "/v1/orders" is not defined in spec`},
+		},
+		{
+			name: "unknown method",
+			request: func(e *Expect) *Request {
+				return e.PUT("/users/1")
+			},
+			errors: []string{`method PUT is not defined in spec for path "/users/{id}"`},
+		},
+		{
+			name: "invalid parameters",
+			request: func(e *Expect) *Request {
+				return e.GET("/users/0")
+			},
+			errors: []string{
+				`missing required header parameter "X-Request-ID"`,
+				`path parameter "id"`,
+			},
+		},
+		{
+			name: "undocumented status",
+			request: func(e *Expect) *Request {
+				return e.DELETE("/users/1")
+			},
+			errors: []string{
+				`response status 418 is not defined in spec for DELETE /users/{id}`,
+			},
+		},
+		{
+			name: "invalid query",
+			request: func(e *Expect) *Request {
+				return e.GET("/users").WithQuery("limit", "x")
+			},
+			errors: []string{
+				`query parameter "limit": expected integer, got "x"`,
+				`response 200 body (application/json): 1: name is required`,
+			},
+		},
+		{
+			name: "missing response header",
+			request: func(e *Expect) *Request {
+				return e.GET("/users").WithQuery("limit", 1).WithQuery("tags", "a,b")
+			},
+			errors: []string{`missing required response header "X-Total"`},
+		},
+		{
+			name: "missing body",
+			request: func(e *Expect) *Request {
+				return e.POST("/users")
+			},
+			errors: []string{`missing required request body`},
+		},
+		{
+			name: "content type",
+			request: func(e *Expect) *Request {
+				return e.POST("/users").WithText("name")
+			},
+			errors: []string{`content type "text/plain; charset=utf-8" is not one of`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, assertionHandler := newExpect(t)
+
+			tc.request(e).Expect()
+
+			if len(tc.errors) == 0 {
+				assert.Nil(t, assertionHandler.failure)
+				return
+			}
+
+			require.NotNil(t, assertionHandler.failure)
+			assert.Equal(t, AssertMatchSchema, assertionHandler.failure.Type)
+
+			var messages []string
+			for _, err := range assertionHandler.failure.Errors {
+				messages = append(messages, err.Error())
+			}
+			text := strings.Join(messages, "\n")
+
+			for _, expected := range tc.errors {
+				assert.Contains(t, text, expected)
+			}
+		})
+	}
+
+	t.Run("repeat", func(t *testing.T) {
+		e, assertionHandler := newExpect(t)
+
+		resps := e.GET("/users/1").WithHeader("X-Request-ID", "1").Repeat(2)
+		require.Equal(t, 2, len(resps))
+
+		assert.Nil(t, assertionHandler.failure)
+
+		resps = e.GET("/orders").Repeat(2)
+		require.Equal(t, 2, len(resps))
+
+		for _, resp := range resps {
+			resp.chain.assertFailed(t)
+		}
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertMatchSchema, assertionHandler.failure.Type)
+	})
+
+	t.Run("repeat concurrently", func(t *testing.T) {
+		assertionHandler := &mockSyncAssertionHandler{}
+
+		e := WithConfig(Config{
+			BaseURL:          "http://example.com/v1",
+			Client:           &http.Client{Transport: NewBinder(handler)},
+			AssertionHandler: assertionHandler,
+			OpenAPISpec:      specPath,
+		})
+
+		resps := e.GET("/orders").RepeatConcurrently(2)
+		require.Equal(t, 2, len(resps))
+
+		for _, resp := range resps {
+			resp.chain.assertFailed(t)
+		}
+
+		failures := assertionHandler.getFailures()
+		require.Equal(t, 2, len(failures))
+
+		for _, failure := range failures {
+			assert.Equal(t, AssertMatchSchema, failure.Type)
+		}
+	})
+
+	t.Run("without validation", func(t *testing.T) {
+		e, assertionHandler := newExpect(t)
+
+		e.GET("/orders").WithoutSpecValidation().
+			Expect().
+			Status(http.StatusTeapot)
+
+		assert.Nil(t, assertionHandler.failure)
+	})
+
+	t.Run("invalid spec", func(t *testing.T) {
+		assertionHandler := &mockAssertionHandler{}
+
+		e := WithConfig(Config{
+			Client:           &http.Client{Transport: NewBinder(handler)},
+			AssertionHandler: assertionHandler,
+			OpenAPISpec:      filepath.Join(filepath.Dir(specPath), "missing.yaml"),
+		})
+
+		e.GET("/v1/users/1").Expect()
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertValid, assertionHandler.failure.Type)
+	})
+
+	t.Run("sanitizers", func(t *testing.T) {
+		assertionHandler := &mockAssertionHandler{}
+
+		e := WithConfig(Config{
+			BaseURL:          "http://example.com/v1",
+			Client:           &http.Client{Transport: NewBinder(handler)},
+			AssertionHandler: assertionHandler,
+			OpenAPISpec:      specPath,
+			Sanitizers: []Sanitizer{
+				ReplaceRegexp(regexp.MustCompile(`"name"`), `"masked"`),
+			},
+		})
+
+		resp := e.GET("/users/1").WithHeader("X-Request-ID", "1").Expect()
+
+		assert.Nil(t, assertionHandler.failure)
+		resp.JSON().Object().ContainsKey("masked")
+	})
+
+	t.Run("spec url", func(t *testing.T) {
+		var specRequests int32
+
+		specHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/spec.yaml" {
+				handler.ServeHTTP(w, r)
+				return
+			}
+			atomic.AddInt32(&specRequests, 1)
+			_, _ = w.Write([]byte(testOpenAPISpec))
+		})
+
+		assertionHandler := &mockAssertionHandler{}
+
+		e := WithConfig(Config{
+			BaseURL:          "http://example.com/v1",
+			Client:           &http.Client{Transport: NewBinder(specHandler)},
+			AssertionHandler: assertionHandler,
+			OpenAPISpec:      "http://spec-url.example.com/spec.yaml",
+		})
+
+		e.GET("/users/1").WithHeader("X-Request-ID", "1").Expect()
+		assert.Nil(t, assertionHandler.failure)
+
+		e.GET("/orders").Expect()
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertMatchSchema, assertionHandler.failure.Type)
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&specRequests))
+	})
+
+	t.Run("spec url error", func(t *testing.T) {
+		var specRequests int32
+
+		specHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/spec.yaml" {
+				handler.ServeHTTP(w, r)
+				return
+			}
+			atomic.AddInt32(&specRequests, 1)
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		for i := 0; i < 2; i++ {
+			assertionHandler := &mockAssertionHandler{}
+
+			e := WithConfig(Config{
+				BaseURL:          "http://example.com/v1",
+				Client:           &http.Client{Transport: NewBinder(specHandler)},
+				AssertionHandler: assertionHandler,
+				OpenAPISpec:      "http://spec-url-error.example.com/spec.yaml",
+			})
+
+			e.GET("/users/1").Expect()
+
+			require.NotNil(t, assertionHandler.failure)
+			assert.Equal(t, AssertValid, assertionHandler.failure.Type)
+		}
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&specRequests))
+	})
+}
+
+const testOpenAPIStrictSpec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: "1.0"
+paths:
+  /user:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+  /users:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: "#/components/schemas/User"
+components:
+  schemas:
+    Base:
+      type: object
+      properties:
+        id:
+          type: integer
+    User:
+      allOf:
+        - $ref: "#/components/schemas/Base"
+        - type: object
+          properties:
+            name:
+              type: string
+            address:
+              type: object
+              properties:
+                city:
+                  type: string
+            labels:
+              type: object
+              additionalProperties:
+                type: object
+                properties:
+                  value:
+                    type: string
+            extra:
+              type: object
+`
+
+func TestOpenAPIStrictResponseFields(t *testing.T) {
+	bodies := map[string]string{
+		"/user": `{"id": 1, "name": "john", "address": {"city": "x"},
+			"labels": {"a": {"value": "b"}}, "extra": {"any": true}}`,
+		"/users": `[{"id": 1, "password": "x", "address": {"zip": "1"}},
+			{"labels": {"a": {"value": "b", "secret": "c"}}}]`,
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(bodies[r.URL.Path]))
+	})
+
+	specPath := writeOpenAPISpec(t, testOpenAPIStrictSpec)
+
+	newExpect := func(strict bool) (*Expect, *mockAssertionHandler) {
+		assertionHandler := &mockAssertionHandler{}
+
+		return WithConfig(Config{
+			BaseURL:              "http://example.com",
+			Client:               &http.Client{Transport: NewBinder(handler)},
+			AssertionHandler:     assertionHandler,
+			OpenAPISpec:          specPath,
+			StrictResponseFields: strict,
+		}), assertionHandler
+	}
+
+	t.Run("documented fields", func(t *testing.T) {
+		e, assertionHandler := newExpect(true)
+
+		e.GET("/user").Expect()
+
+		assert.Nil(t, assertionHandler.failure)
+	})
+
+	t.Run("undocumented fields", func(t *testing.T) {
+		e, assertionHandler := newExpect(true)
+
+		e.GET("/users").Expect()
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertMatchSchema, assertionHandler.failure.Type)
+
+		var messages []string
+		for _, err := range assertionHandler.failure.Errors[1:] {
+			messages = append(messages, err.Error())
+		}
+
+		prefix := "GET /users response 200 body (application/json): "
+
+		assert.Equal(t, []string{
+			prefix + "undocumented field $[0].address.zip",
+			prefix + "undocumented field $[0].password",
+			prefix + "undocumented field $[1].labels.a.secret",
+		}, messages)
+	})
+
+	t.Run("not strict", func(t *testing.T) {
+		e, assertionHandler := newExpect(false)
+
+		e.GET("/users").Expect()
+
+		assert.Nil(t, assertionHandler.failure)
+	})
+}
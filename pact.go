@@ -0,0 +1,565 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PactRecorder is a Recorder that converts executed requests and responses
+// into Pact contract (Pact specification v2), for consumer-driven contract
+// testing.
+//
+// Every recorded exchange becomes an interaction. Its description is built
+// from request method, path, and response status; interactions with equal
+// descriptions but different content get numeric suffix, and exact
+// duplicates are skipped.
+//
+// Request headers are recorded, except User-Agent, Content-Length,
+// Accept-Encoding, and Cookie. Only Content-Type response header is
+// recorded. JSON bodies are recorded as JSON, other bodies as strings.
+// Matching rules are not generated.
+//
+// Pact file may be verified against provider using Expect.VerifyPact.
+//
+// PactRecorder is safe for concurrent use.
+//
+// Example:
+//
+//	recorder := httpexpect.NewPactRecorder("web-app", "users-api")
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:  server.URL,
+//	    Reporter: httpexpect.NewAssertReporter(t),
+//	    Recorder: recorder,
+//	})
+//
+//	recorder.Given("user 123 exists")
+//
+//	e.GET("/users/123").
+//	    Expect().
+//	    Status(http.StatusOK)
+//
+//	_ = recorder.WriteFile("pacts/web-app-users-api.json")
+type PactRecorder struct {
+	mu           sync.Mutex
+	consumer     string
+	provider     string
+	state        string
+	interactions []pactInteraction
+}
+
+// NewPactRecorder returns a new empty PactRecorder for given consumer
+// and provider names.
+func NewPactRecorder(consumer, provider string) *PactRecorder {
+	return &PactRecorder{
+		consumer: consumer,
+		provider: provider,
+	}
+}
+
+// Given sets provider state for subsequently recorded interactions.
+// Empty string resets provider state.
+func (p *PactRecorder) Given(state string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.state = state
+}
+
+// Record implements Recorder.Record.
+func (p *PactRecorder) Record(exchange *RecordedExchange) {
+	if exchange.Request == nil || exchange.Response == nil {
+		return
+	}
+
+	interaction := newPactInteraction(exchange)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	interaction.ProviderState = p.state
+
+	description := interaction.Description
+
+	for n := 2; ; n++ {
+		dup := false
+
+		for _, other := range p.interactions {
+			if other.Description != interaction.Description ||
+				other.ProviderState != interaction.ProviderState {
+				continue
+			}
+			if reflect.DeepEqual(other, interaction) {
+				return
+			}
+			dup = true
+		}
+
+		if !dup {
+			break
+		}
+
+		interaction.Description = fmt.Sprintf("%s #%d", description, n)
+	}
+
+	p.interactions = append(p.interactions, interaction)
+}
+
+// Len returns number of recorded interactions.
+func (p *PactRecorder) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.interactions)
+}
+
+// Reset removes all recorded interactions and resets provider state.
+func (p *PactRecorder) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.interactions = nil
+	p.state = ""
+}
+
+// MarshalJSON returns recorded interactions as Pact JSON document.
+func (p *PactRecorder) MarshalJSON() ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	interactions := p.interactions
+	if interactions == nil {
+		interactions = []pactInteraction{}
+	}
+
+	doc := pactDocument{
+		Consumer:     pactParticipant{Name: p.consumer},
+		Provider:     pactParticipant{Name: p.provider},
+		Interactions: interactions,
+	}
+	doc.Metadata.PactSpecification.Version = "2.0.0"
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// WriteTo writes Pact JSON document to given writer.
+func (p *PactRecorder) WriteTo(w io.Writer) (int64, error) {
+	b, err := p.MarshalJSON()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// WriteFile writes Pact JSON document to given file.
+func (p *PactRecorder) WriteFile(path string) error {
+	b, err := p.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// PactVerifyOpts defines additional options for Expect.VerifyPact.
+type PactVerifyOpts struct {
+	// ProviderStates maps provider state names to functions that set up
+	// provider before interaction is replayed, e.g. populate database.
+	// States without handler are ignored.
+	ProviderStates map[string]func()
+}
+
+// VerifyPact replays interactions from given Pact file against provider
+// and checks that responses match the contract.
+//
+// Requests are sent using Expect instance, so their paths are relative
+// to Config.BaseURL. Response status and headers should be equal to
+// expected ones. Response body should match expected body, with extra
+// object fields allowed in actual body. Matching rules are not supported.
+//
+// Both Pact specification v2 and v3 files can be verified.
+//
+// Example:
+//
+//	e := httpexpect.Default(t, server.URL)
+//
+//	e.VerifyPact("pacts/web-app-users-api.json", httpexpect.PactVerifyOpts{
+//	    ProviderStates: map[string]func(){
+//	        "user 123 exists": func() {
+//	            db.CreateUser(123)
+//	        },
+//	    },
+//	})
+func (e *Expect) VerifyPact(path string, opts ...PactVerifyOpts) {
+	e.chain.enter("VerifyPact(%q)", path)
+	defer e.chain.leave()
+
+	if len(opts) > 1 {
+		chain := e.chain.clone()
+		chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple opts arguments"),
+			},
+		})
+		return
+	}
+
+	var o PactVerifyOpts
+	if len(opts) == 1 {
+		o = opts[0]
+	}
+
+	doc, err := readPactFile(path)
+	if err != nil {
+		chain := e.chain.clone()
+		chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{path},
+			Errors: []error{
+				errors.New("expected: valid pact file"),
+				err,
+			},
+		})
+		return
+	}
+
+	for _, interaction := range doc.Interactions {
+		e.verifyPactInteraction(interaction, o)
+	}
+}
+
+func (e *Expect) verifyPactInteraction(interaction pactInteraction, opts PactVerifyOpts) {
+	e.chain.enter("Interaction(%q)", interaction.Description)
+	defer e.chain.leave()
+
+	if handler := opts.ProviderStates[interaction.ProviderState]; handler != nil {
+		handler()
+	}
+
+	req := e.Request(interaction.Request.Method, interaction.Request.Path)
+
+	if interaction.Request.Query != "" {
+		req.WithQueryString(interaction.Request.Query)
+	}
+
+	for k, v := range interaction.Request.Headers {
+		req.WithHeader(k, v)
+	}
+
+	if interaction.Request.Body != nil {
+		req.WithBytes(pactEncodeBody(interaction.Request.Body))
+	}
+
+	resp := req.Expect()
+
+	resp.Status(interaction.Response.Status)
+
+	for k, v := range interaction.Response.Headers {
+		resp.Header(k).Equal(v)
+	}
+
+	if interaction.Response.Body == nil || resp.chain.failed() {
+		return
+	}
+
+	expected := interaction.Response.Body
+
+	var actual interface{}
+	if _, ok := expected.(string); ok || !isJSONContent(resp.httpResp.Header) {
+		actual = string(resp.content)
+	} else if err := json.Unmarshal(resp.content, &actual); err != nil {
+		actual = string(resp.content)
+	}
+
+	if errs := pactMatchBody("$", expected, actual); len(errs) != 0 {
+		resp.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{actual},
+			Expected: &AssertionValue{expected},
+			Errors: append([]error{
+				errors.New("expected: response body matches pact"),
+			}, errs...),
+		})
+	}
+}
+
+type pactDocument struct {
+	Consumer     pactParticipant   `json:"consumer"`
+	Provider     pactParticipant   `json:"provider"`
+	Interactions []pactInteraction `json:"interactions"`
+	Metadata     struct {
+		PactSpecification struct {
+			Version string `json:"version"`
+		} `json:"pactSpecification"`
+	} `json:"metadata"`
+}
+
+type pactParticipant struct {
+	Name string `json:"name"`
+}
+
+type pactInteraction struct {
+	Description   string       `json:"description"`
+	ProviderState string       `json:"providerState,omitempty"`
+	Request       pactRequest  `json:"request"`
+	Response      pactResponse `json:"response"`
+}
+
+type pactRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Query   string            `json:"query,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+}
+
+type pactResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+}
+
+// v3 files have different representation of query and provider states
+type pactInteractionV3 struct {
+	ProviderStates []struct {
+		Name string `json:"name"`
+	} `json:"providerStates"`
+	Request struct {
+		Query interface{} `json:"query"`
+	} `json:"request"`
+}
+
+var pactSkipHeaders = map[string]bool{
+	"User-Agent":      true,
+	"Content-Length":  true,
+	"Accept-Encoding": true,
+	"Cookie":          true,
+}
+
+func newPactInteraction(exchange *RecordedExchange) pactInteraction {
+	req, resp := exchange.Request, exchange.Response
+
+	interaction := pactInteraction{
+		Description: fmt.Sprintf("%s %s -> %d", req.Method, req.URL.Path, resp.StatusCode),
+		Request: pactRequest{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Query:  req.URL.RawQuery,
+		},
+		Response: pactResponse{
+			Status: resp.StatusCode,
+		},
+	}
+
+	for k, v := range req.Header {
+		if pactSkipHeaders[http.CanonicalHeaderKey(k)] {
+			continue
+		}
+		if interaction.Request.Headers == nil {
+			interaction.Request.Headers = map[string]string{}
+		}
+		interaction.Request.Headers[k] = strings.Join(v, ", ")
+	}
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		interaction.Response.Headers = map[string]string{
+			"Content-Type": ct,
+		}
+	}
+
+	interaction.Request.Body = pactDecodeBody(req.Header, exchange.RequestBody)
+	interaction.Response.Body = pactDecodeBody(resp.Header, exchange.ResponseBody)
+
+	return interaction
+}
+
+func pactDecodeBody(header http.Header, body []byte) interface{} {
+	if len(body) == 0 {
+		return nil
+	}
+
+	if isJSONContent(header) {
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err == nil {
+			return value
+		}
+	}
+
+	return string(body)
+}
+
+func pactEncodeBody(body interface{}) []byte {
+	if s, ok := body.(string); ok {
+		return []byte(s)
+	}
+
+	b, _ := json.Marshal(body)
+	return b
+}
+
+func isJSONContent(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func readPactFile(path string) (*pactDocument, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Interactions []json.RawMessage `json:"interactions"`
+	}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	doc := &pactDocument{}
+
+	for i, rawInteraction := range raw.Interactions {
+		var v3 pactInteractionV3
+		if err := json.Unmarshal(rawInteraction, &v3); err != nil {
+			return nil, fmt.Errorf("interaction %d: %s", i, err)
+		}
+
+		query := v3.Request.Query
+
+		// v3 query is an object, replace it before decoding
+		if queryMap, ok := query.(map[string]interface{}); ok {
+			var fields map[string]json.RawMessage
+			_ = json.Unmarshal(rawInteraction, &fields)
+
+			var request map[string]interface{}
+			_ = json.Unmarshal(fields["request"], &request)
+			request["query"] = pactEncodeQuery(queryMap)
+
+			fields["request"], _ = json.Marshal(request)
+			rawInteraction, _ = json.Marshal(fields)
+		}
+
+		var interaction pactInteraction
+		if err := json.Unmarshal(rawInteraction, &interaction); err != nil {
+			return nil, fmt.Errorf("interaction %d: %s", i, err)
+		}
+
+		if interaction.ProviderState == "" && len(v3.ProviderStates) != 0 {
+			interaction.ProviderState = v3.ProviderStates[0].Name
+		}
+
+		if interaction.Request.Method == "" || interaction.Response.Status == 0 {
+			return nil, fmt.Errorf("interaction %d: missing request method or response status",
+				i)
+		}
+
+		doc.Interactions = append(doc.Interactions, interaction)
+	}
+
+	return doc, nil
+}
+
+func pactEncodeQuery(query map[string]interface{}) string {
+	values := url.Values{}
+
+	for k, v := range query {
+		switch vv := v.(type) {
+		case []interface{}:
+			for _, item := range vv {
+				values.Add(k, fmt.Sprint(item))
+			}
+		default:
+			values.Add(k, fmt.Sprint(vv))
+		}
+	}
+
+	return values.Encode()
+}
+
+// checks that actual body matches expected one; objects may have extra
+// fields, arrays and scalars should be equal
+func pactMatchBody(path string, expected, actual interface{}) []error {
+	switch exp := expected.(type) {
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			return []error{fmt.Errorf("%s: expected object, got %s", path, pactTypeOf(actual))}
+		}
+
+		keys := make([]string, 0, len(exp))
+		for k := range exp {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var errs []error
+		for _, k := range keys {
+			v, ok := act[k]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s.%s: missing field", path, k))
+				continue
+			}
+			errs = append(errs, pactMatchBody(path+"."+k, exp[k], v)...)
+		}
+		return errs
+
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok {
+			return []error{fmt.Errorf("%s: expected array, got %s", path, pactTypeOf(actual))}
+		}
+		if len(act) != len(exp) {
+			return []error{
+				fmt.Errorf("%s: expected array of length %d, got %d", path, len(exp), len(act)),
+			}
+		}
+
+		var errs []error
+		for i := range exp {
+			errs = append(errs,
+				pactMatchBody(fmt.Sprintf("%s[%d]", path, i), exp[i], act[i])...)
+		}
+		return errs
+
+	default:
+		if reflect.DeepEqual(expected, actual) {
+			return nil
+		}
+		return []error{fmt.Errorf("%s: expected %v, got %v", path, expected, actual)}
+	}
+}
+
+func pactTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
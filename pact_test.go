@@ -0,0 +1,273 @@
+package httpexpect
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPactRecorder(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id": 1, "name": "john"}`))
+		case "/text":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte(r.URL.Query().Get("q")))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	recorder := NewPactRecorder("consumer", "provider")
+
+	e := WithConfig(Config{
+		Client:   &http.Client{Transport: NewBinder(handler)},
+		Reporter: newMockReporter(t),
+		Recorder: recorder,
+	})
+
+	recorder.Given("user 1 exists")
+
+	e.GET("/users/1").WithHeader("X-Token", "secret").Expect().Status(http.StatusOK)
+	e.GET("/users/1").WithHeader("X-Token", "secret").Expect().Status(http.StatusOK)
+
+	recorder.Given("")
+
+	e.GET("/text").WithQuery("q", "a").Expect().Status(http.StatusOK)
+	e.GET("/text").WithQuery("q", "b").Expect().Status(http.StatusOK)
+	e.POST("/missing").WithJSON([]int{1, 2}).Expect().Status(http.StatusNotFound)
+
+	assert.Equal(t, 4, recorder.Len())
+
+	var buf bytes.Buffer
+	_, err := recorder.WriteTo(&buf)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Equal(t, map[string]interface{}{"name": "consumer"}, doc["consumer"])
+	assert.Equal(t, map[string]interface{}{"name": "provider"}, doc["provider"])
+	assert.Equal(t, map[string]interface{}{
+		"pactSpecification": map[string]interface{}{"version": "2.0.0"},
+	}, doc["metadata"])
+
+	interactions := doc["interactions"].([]interface{})
+	require.Equal(t, 4, len(interactions))
+
+	assert.Equal(t, map[string]interface{}{
+		"description":   "GET /users/1 -> 200",
+		"providerState": "user 1 exists",
+		"request": map[string]interface{}{
+			"method": "GET",
+			"path":   "/users/1",
+			"headers": map[string]interface{}{
+				"X-Token": "secret",
+			},
+		},
+		"response": map[string]interface{}{
+			"status": 200.0,
+			"headers": map[string]interface{}{
+				"Content-Type": "application/json",
+			},
+			"body": map[string]interface{}{
+				"id":   1.0,
+				"name": "john",
+			},
+		},
+	}, interactions[0])
+
+	assert.Equal(t, "GET /text -> 200",
+		interactions[1].(map[string]interface{})["description"])
+	assert.Equal(t, "GET /text -> 200 #2",
+		interactions[2].(map[string]interface{})["description"])
+	assert.Equal(t, "b",
+		interactions[2].(map[string]interface{})["response"].(map[string]interface{})["body"])
+	assert.Equal(t, []interface{}{1.0, 2.0},
+		interactions[3].(map[string]interface{})["request"].(map[string]interface{})["body"])
+
+	recorder.Reset()
+	assert.Equal(t, 0, recorder.Len())
+}
+
+func TestPactVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	users := map[string]string{}
+
+	provider := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/users/1":
+			name, ok := users["1"]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id": 1, "name": "` + name + `", "extra": true}`))
+		case r.Method == "POST" && r.URL.Path == "/echo":
+			w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+			body, _ := ioutil.ReadAll(r.Body)
+			_, _ = w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	recorder := NewPactRecorder("consumer", "provider")
+
+	consumer := WithConfig(Config{
+		Client:   &http.Client{Transport: NewBinder(provider)},
+		Reporter: newMockReporter(t),
+		Recorder: recorder,
+	})
+
+	users["1"] = "john"
+	recorder.Given("user 1 exists")
+	consumer.GET("/users/1").Expect().Status(http.StatusOK)
+
+	recorder.Given("")
+	consumer.POST("/echo").WithJSON(map[string]interface{}{"a": []int{1}}).
+		Expect().Status(http.StatusOK)
+	consumer.POST("/echo").WithText("hello").
+		Expect().Status(http.StatusOK)
+
+	pactFile := filepath.Join(dir, "pact.json")
+	require.NoError(t, recorder.WriteFile(pactFile))
+
+	newVerifier := func(handler http.Handler) (*Expect, *mockAssertionHandler) {
+		assertionHandler := &mockAssertionHandler{}
+
+		return WithConfig(Config{
+			Client:           &http.Client{Transport: NewBinder(handler)},
+			AssertionHandler: assertionHandler,
+		}), assertionHandler
+	}
+
+	t.Run("success", func(t *testing.T) {
+		users = map[string]string{}
+
+		e, assertionHandler := newVerifier(provider)
+
+		called := false
+		e.VerifyPact(pactFile, PactVerifyOpts{
+			ProviderStates: map[string]func(){
+				"user 1 exists": func() {
+					called = true
+					users["1"] = "john"
+				},
+			},
+		})
+
+		assert.True(t, called)
+		assert.Nil(t, assertionHandler.failure)
+	})
+
+	t.Run("missing state", func(t *testing.T) {
+		users = map[string]string{}
+
+		e, assertionHandler := newVerifier(provider)
+
+		e.VerifyPact(pactFile)
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertEqual, assertionHandler.failure.Type)
+	})
+
+	t.Run("body mismatch", func(t *testing.T) {
+		users = map[string]string{"1": "bob"}
+
+		e, assertionHandler := newVerifier(provider)
+
+		var path []string
+		e = e.Clone(Config{
+			OnFailure: func(ctx *AssertionContext, _ *AssertionFailure) {
+				path = append([]string(nil), ctx.Path...)
+			},
+		})
+
+		e.VerifyPact(pactFile)
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertEqual, assertionHandler.failure.Type)
+		assert.Contains(t, assertionHandler.failure.Errors,
+			errors.New("$.name: expected john, got bob"))
+		assert.Equal(t, []string{
+			`VerifyPact("` + pactFile + `")`,
+			`Interaction("GET /users/1 -> 200")`,
+		}, path[:2])
+	})
+
+	t.Run("v3", func(t *testing.T) {
+		v3File := filepath.Join(dir, "v3.json")
+		require.NoError(t, ioutil.WriteFile(v3File, []byte(`{
+			"interactions": [{
+				"description": "get user",
+				"providerStates": [{"name": "user 1 exists"}],
+				"request": {"method": "GET", "path": "/users/1", "query": {"x": ["1"]}},
+				"response": {"status": 200, "body": {"name": "alice"}}
+			}],
+			"metadata": {"pactSpecification": {"version": "3.0.0"}}
+		}`), 0644))
+
+		var query string
+
+		e, assertionHandler := newVerifier(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.RawQuery
+				provider.ServeHTTP(w, r)
+			}))
+
+		e.VerifyPact(v3File, PactVerifyOpts{
+			ProviderStates: map[string]func(){
+				"user 1 exists": func() {
+					users["1"] = "alice"
+				},
+			},
+		})
+
+		assert.Nil(t, assertionHandler.failure)
+		assert.Equal(t, "x=1", query)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		e, assertionHandler := newVerifier(provider)
+
+		e.VerifyPact(filepath.Join(dir, "missing.json"))
+
+		require.NotNil(t, assertionHandler.failure)
+		assert.Equal(t, AssertValid, assertionHandler.failure.Type)
+	})
+}
+
+func TestPactMatchBody(t *testing.T) {
+	cases := []struct {
+		expected interface{}
+		actual   interface{}
+		errors   int
+	}{
+		{map[string]interface{}{"a": 1.0}, map[string]interface{}{"a": 1.0, "b": 2.0}, 0},
+		{map[string]interface{}{"a": 1.0, "b": 2.0}, map[string]interface{}{"a": 2.0}, 2},
+		{[]interface{}{1.0}, []interface{}{1.0, 2.0}, 1},
+		{[]interface{}{map[string]interface{}{}}, []interface{}{"x"}, 1},
+		{"text", "text", 0},
+		{"text", 1.0, 1},
+		{nil, nil, 0},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.errors, len(pactMatchBody("$", tc.expected, tc.actual)))
+	}
+}
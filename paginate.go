@@ -0,0 +1,394 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/yalp/jsonpath"
+)
+
+// PaginateOpts defines how Expect.Paginate extracts items from pages and
+// finds the next page.
+//
+// By default, next page is found using Link header with rel="next"
+// (RFC 8288). If CursorPath is set, cursor from response body is used
+// instead. If NextPage is set, it overrides both.
+type PaginateOpts struct {
+	// ItemsPath is JSONPath of items array in every page, e.g. "$.items".
+	// If empty, "$" is used, i.e. whole body should be an array.
+	ItemsPath string
+
+	// CursorPath is JSONPath of next page cursor in every page,
+	// e.g. "$.next_cursor". If cursor is missing, null, or empty,
+	// pagination stops.
+	CursorPath string
+
+	// CursorParam is the name of query parameter used to pass cursor to
+	// the next page request. If empty, "cursor" is used.
+	// Ignored if CursorPath is empty.
+	CursorParam string
+
+	// NextPage returns request for the next page, or nil if there are no
+	// more pages.
+	NextPage func(*Response) *Request
+
+	// MaxPages defines maximum number of pages to fetch. If pagination
+	// doesn't stop after MaxPages pages, failure is reported.
+	// If zero, 100 is used.
+	MaxPages int
+}
+
+// Paginate fetches all pages starting from given request, and returns
+// a new Array instance with items from all pages combined.
+//
+// Every page should have JSON body; items are extracted from it using
+// opts.ItemsPath. Requests for subsequent pages are created using
+// Expect.Request with the same method as the first one and without body,
+// so builders and matchers attached to Expect are applied to them.
+//
+// Paginate fails if any page request fails, if page has no items array,
+// or if number of pages exceeds opts.MaxPages.
+//
+// Example:
+//
+//	e := httpexpect.Default(t, "http://example.com")
+//
+//	// follow Link: <...>; rel="next"
+//	items := e.Paginate(e.GET("/users"), httpexpect.PaginateOpts{
+//	    ItemsPath: "$.users",
+//	})
+//	items.Length().Equal(250)
+//
+//	// follow {"next_cursor": "..."} using ?after=... query parameter
+//	items = e.Paginate(e.GET("/events"), httpexpect.PaginateOpts{
+//	    ItemsPath:   "$.events",
+//	    CursorPath:  "$.next_cursor",
+//	    CursorParam: "after",
+//	})
+func (e *Expect) Paginate(first *Request, opts PaginateOpts) *Array {
+	e.chain.enter("Paginate()")
+	defer e.chain.leave()
+
+	chain := e.chain.clone()
+
+	if first == nil {
+		chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil argument"),
+			},
+		})
+		return newArray(chain, nil)
+	}
+
+	if opts.MaxPages < 0 {
+		chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{opts.MaxPages},
+			Errors: []error{
+				errors.New("unexpected negative MaxPages"),
+			},
+		})
+		return newArray(chain, nil)
+	}
+
+	p := paginator{
+		expect: e,
+		chain:  chain,
+		opts:   opts,
+	}
+
+	if !p.prepare() {
+		return newArray(chain, nil)
+	}
+
+	items, ok := p.walk(first)
+	if !ok {
+		return newArray(chain, nil)
+	}
+
+	return newArray(chain, items)
+}
+
+type paginator struct {
+	expect *Expect
+	chain  *chain
+	opts   PaginateOpts
+
+	itemsFilter  jsonpath.FilterFunc
+	cursorFilter jsonpath.FilterFunc
+}
+
+func (p *paginator) prepare() bool {
+	if p.opts.MaxPages == 0 {
+		p.opts.MaxPages = 100
+	}
+
+	if p.opts.ItemsPath == "" {
+		p.opts.ItemsPath = "$"
+	}
+
+	if p.opts.CursorParam == "" {
+		p.opts.CursorParam = "cursor"
+	}
+
+	var err error
+
+	p.itemsFilter, err = p.preparePath(p.opts.ItemsPath)
+	if err != nil {
+		return false
+	}
+
+	if p.opts.CursorPath != "" {
+		p.cursorFilter, err = p.preparePath(p.opts.CursorPath)
+		if err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (p *paginator) preparePath(path string) (jsonpath.FilterFunc, error) {
+	filter, err := jsonpath.Prepare(path)
+	if err != nil {
+		p.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{path},
+			Errors: []error{
+				errors.New("expected: valid json path"),
+				err,
+			},
+		})
+	}
+	return filter, err
+}
+
+func (p *paginator) walk(req *Request) ([]interface{}, bool) {
+	items := []interface{}{}
+
+	for page := 1; ; page++ {
+		resp := req.Expect()
+
+		value := resp.JSON()
+		if value.chain.failed() {
+			p.chain.setFailed()
+			return nil, false
+		}
+
+		pageItems, err := p.itemsFilter(value.Raw())
+		if err != nil {
+			pageItems = nil
+		}
+
+		arr, ok := pageItems.([]interface{})
+		if !ok {
+			resp.chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{value.Raw()},
+				Errors: []error{
+					fmt.Errorf("expected: page %d has items array at %q",
+						page, p.opts.ItemsPath),
+				},
+			})
+			p.chain.setFailed()
+			return nil, false
+		}
+
+		items = append(items, arr...)
+
+		next := p.nextPage(req, resp, value.Raw())
+		if next == nil {
+			break
+		}
+
+		if page >= p.opts.MaxPages {
+			p.chain.fail(AssertionFailure{
+				Type:   AssertOperation,
+				Actual: &AssertionValue{page},
+				Errors: []error{
+					fmt.Errorf("pagination didn't stop after %d pages",
+						p.opts.MaxPages),
+				},
+			})
+			return nil, false
+		}
+
+		req = next
+	}
+
+	return items, true
+}
+
+func (p *paginator) nextPage(
+	req *Request, resp *Response, value interface{},
+) *Request {
+	if p.opts.NextPage != nil {
+		return p.opts.NextPage(resp)
+	}
+
+	var nextURL *url.URL
+
+	if p.cursorFilter != nil {
+		cursor, err := p.cursorFilter(value)
+		if err != nil {
+			return nil
+		}
+
+		var cursorStr string
+		switch c := cursor.(type) {
+		case string:
+			cursorStr = c
+		case float64:
+			cursorStr = strconv.FormatFloat(c, 'f', -1, 64)
+		}
+		if cursorStr == "" {
+			return nil
+		}
+
+		u := *req.httpReq.URL
+		q := u.Query()
+		q.Set(p.opts.CursorParam, cursorStr)
+		u.RawQuery = q.Encode()
+
+		nextURL = &u
+	} else {
+		var link string
+
+		for _, l := range parseLinkHeader(resp.httpResp.Header.Values("Link")) {
+			if l.hasRel("next") {
+				link = l.URL
+				break
+			}
+		}
+		if link == "" {
+			return nil
+		}
+
+		u, err := req.httpReq.URL.Parse(link)
+		if err != nil {
+			resp.chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{link},
+				Errors: []error{
+					errors.New(`invalid "next" url in "Link" response header`),
+					err,
+				},
+			})
+			return nil
+		}
+
+		nextURL = u
+	}
+
+	return p.expect.Request(req.httpReq.Method, "").WithURL(nextURL.String())
+}
+
+// single link from Link header (RFC 8288)
+type linkValue struct {
+	URL    string
+	Params map[string]string
+}
+
+func (l linkValue) rels() []string {
+	return strings.Fields(l.Params["rel"])
+}
+
+func (l linkValue) hasRel(rel string) bool {
+	for _, r := range l.rels() {
+		if strings.EqualFold(r, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// parses values of Link header, e.g.:
+//
+//	<https://example.com/?page=2>; rel="next", <https://example.com/?page=5>; rel=last
+//
+// malformed links are skipped
+func parseLinkHeader(values []string) []linkValue {
+	var links []linkValue
+
+	for _, value := range values {
+		for {
+			start := strings.IndexByte(value, '<')
+			if start < 0 {
+				break
+			}
+			end := strings.IndexByte(value[start:], '>')
+			if end < 0 {
+				break
+			}
+			end += start
+
+			link := linkValue{
+				URL:    strings.TrimSpace(value[start+1 : end]),
+				Params: map[string]string{},
+			}
+
+			value = value[end+1:]
+
+			// parameters until next unquoted comma
+			var params string
+			params, value = splitLinkParams(value)
+
+			for _, param := range splitUnquoted(params, ';') {
+				param = strings.TrimSpace(param)
+				if param == "" {
+					continue
+				}
+				name, arg := param, ""
+				if i := strings.IndexByte(param, '='); i >= 0 {
+					name = strings.TrimSpace(param[:i])
+					arg = strings.Trim(strings.TrimSpace(param[i+1:]), `"`)
+				}
+				name = strings.ToLower(name)
+				if _, ok := link.Params[name]; !ok {
+					link.Params[name] = arg
+				}
+			}
+
+			links = append(links, link)
+		}
+	}
+
+	return links
+}
+
+func splitLinkParams(s string) (string, string) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				return s[:i], s[i+1:]
+			}
+		}
+	}
+	return s, ""
+}
+
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
@@ -0,0 +1,188 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createPaginateHandler(total, perPage int) http.Handler {
+	mux := http.NewServeMux()
+
+	pageItems := func(page int) []int {
+		items := []int{}
+		for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+			items = append(items, i)
+		}
+		return items
+	}
+
+	mux.HandleFunc("/link", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page*perPage < total {
+			w.Header().Add("Link", `</link?page=1>; rel="first"`)
+			w.Header().Add("Link",
+				fmt.Sprintf(`</link?page=%d>; rel="next"`, page+1))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(pageItems(page))
+	})
+
+	mux.HandleFunc("/cursor", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		if page == 0 {
+			page = 1
+		}
+		body := map[string]interface{}{
+			"items": pageItems(page),
+		}
+		if page*perPage < total {
+			body["next"] = strconv.Itoa(page + 1)
+		} else {
+			body["next"] = nil
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	})
+
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `</loop>; rel="next"`)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[1]`))
+	})
+
+	mux.HandleFunc("/object", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items": 1}`))
+	})
+
+	return mux
+}
+
+func TestPaginate(t *testing.T) {
+	server := httptest.NewServer(createPaginateHandler(25, 10))
+	defer server.Close()
+
+	expected := []int{}
+	for i := 0; i < 25; i++ {
+		expected = append(expected, i)
+	}
+
+	t.Run("link", func(t *testing.T) {
+		e := Default(t, server.URL)
+
+		e.Paginate(e.GET("/link"), PaginateOpts{}).
+			Equal(expected)
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		e := Default(t, server.URL)
+
+		e.Paginate(e.GET("/cursor"), PaginateOpts{
+			ItemsPath:   "$.items",
+			CursorPath:  "$.next",
+			CursorParam: "after",
+		}).Equal(expected)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		e := Default(t, server.URL)
+
+		page := 1
+
+		e.Paginate(e.GET("/link"), PaginateOpts{
+			NextPage: func(resp *Response) *Request {
+				page++
+				if page > 2 {
+					return nil
+				}
+				return e.GET("/link").WithQuery("page", page)
+			},
+		}).Equal(expected[:20])
+	})
+
+	t.Run("max pages", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		arr := e.Paginate(e.GET("/loop"), PaginateOpts{
+			MaxPages: 5,
+		})
+
+		arr.chain.assertFailed(t)
+		e.chain.assertOK(t)
+	})
+
+	t.Run("no items", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		e.Paginate(e.GET("/object"), PaginateOpts{ItemsPath: "$.items"}).
+			chain.assertFailed(t)
+	})
+
+	t.Run("failed page", func(t *testing.T) {
+		e := WithConfig(Config{
+			BaseURL:  server.URL,
+			Reporter: newMockReporter(t),
+		})
+
+		e.Paginate(e.GET("/missing"), PaginateOpts{}).
+			chain.assertFailed(t)
+	})
+}
+
+func TestPaginateUsage(t *testing.T) {
+	e := WithConfig(Config{
+		Client:   &mockClient{},
+		Reporter: newMockReporter(t),
+	})
+
+	e.Paginate(nil, PaginateOpts{}).chain.assertFailed(t)
+	e.Paginate(e.GET("/"), PaginateOpts{MaxPages: -1}).chain.assertFailed(t)
+	e.Paginate(e.GET("/"), PaginateOpts{ItemsPath: "!"}).chain.assertFailed(t)
+	e.Paginate(e.GET("/"), PaginateOpts{CursorPath: "!"}).chain.assertFailed(t)
+
+	e.chain.assertOK(t)
+}
+
+func TestPaginateLinkHeader(t *testing.T) {
+	links := parseLinkHeader([]string{
+		`<https://example.com/?page=2>; rel="next"; title="a, b",` +
+			` <https://example.com/?page=5>; REL=last`,
+		`</first>; rel="first prev"`,
+		`garbage`,
+	})
+
+	assert.Equal(t, []linkValue{
+		{
+			URL:    "https://example.com/?page=2",
+			Params: map[string]string{"rel": "next", "title": "a, b"},
+		},
+		{
+			URL:    "https://example.com/?page=5",
+			Params: map[string]string{"rel": "last"},
+		},
+		{
+			URL:    "/first",
+			Params: map[string]string{"rel": "first prev"},
+		},
+	}, links)
+
+	assert.True(t, links[0].hasRel("next"))
+	assert.False(t, links[0].hasRel("last"))
+	assert.True(t, links[2].hasRel("prev"))
+	assert.True(t, links[2].hasRel("First"))
+}
@@ -0,0 +1,259 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// PreparedRequest provides methods to inspect http.Request that would be
+// sent by Request (see Request.DryRun), or that was actually sent (see
+// Response.SentRequest).
+type PreparedRequest struct {
+	chain   *chain
+	httpReq *http.Request
+	body    []byte
+}
+
+func newPreparedRequest(
+	parent *chain, httpReq *http.Request, body []byte,
+) *PreparedRequest {
+	return &PreparedRequest{parent.clone(), httpReq, body}
+}
+
+// Raw returns underlying http.Request, or nil if request can't be built.
+func (p *PreparedRequest) Raw() *http.Request {
+	return p.httpReq
+}
+
+// Method returns a new String instance with request method.
+//
+// Example:
+//
+//	prepared := req.DryRun()
+//	prepared.Method().Equal("PUT")
+func (p *PreparedRequest) Method() *String {
+	p.chain.enter("Method()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newString(p.chain, "")
+	}
+
+	return newString(p.chain, p.httpReq.Method)
+}
+
+// URL returns a new String instance with full request URL, including
+// query string.
+//
+// Example:
+//
+//	prepared := req.DryRun()
+//	prepared.URL().Equal("http://example.com/users/1?notify=false")
+func (p *PreparedRequest) URL() *String {
+	p.chain.enter("URL()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newString(p.chain, "")
+	}
+
+	return newString(p.chain, p.httpReq.URL.String())
+}
+
+// Path returns a new String instance with request URL path.
+//
+// Example:
+//
+//	prepared := req.DryRun()
+//	prepared.Path().Equal("/users/1")
+func (p *PreparedRequest) Path() *String {
+	p.chain.enter("Path()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newString(p.chain, "")
+	}
+
+	return newString(p.chain, p.httpReq.URL.Path)
+}
+
+// Query returns a new String instance with value of given query parameter.
+// If parameter has multiple values, the first one is returned.
+//
+// Query fails if there is no such parameter.
+//
+// Example:
+//
+//	prepared := req.DryRun()
+//	prepared.Query("notify").Equal("false")
+func (p *PreparedRequest) Query(name string) *String {
+	p.chain.enter("Query(%q)", name)
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newString(p.chain, "")
+	}
+
+	query := p.httpReq.URL.Query()
+
+	values, ok := query[name]
+	if !ok {
+		p.chain.fail(AssertionFailure{
+			Type:     AssertContainsKey,
+			Actual:   &AssertionValue{query},
+			Expected: &AssertionValue{name},
+			Errors: []error{
+				fmt.Errorf("expected: request has %q query parameter", name),
+			},
+		})
+		return newString(p.chain, "")
+	}
+
+	var value string
+	if len(values) != 0 {
+		value = values[0]
+	}
+
+	return newString(p.chain, value)
+}
+
+// Headers returns a new Object instance with request header map.
+//
+// Example:
+//
+//	prepared := req.DryRun()
+//	prepared.Headers().ContainsKey("X-Request-Id")
+func (p *PreparedRequest) Headers() *Object {
+	p.chain.enter("Headers()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newObject(p.chain, nil)
+	}
+
+	var value map[string]interface{}
+	value, _ = canonMap(p.chain, p.httpReq.Header)
+
+	return newObject(p.chain, value)
+}
+
+// Header returns a new String instance with given request header field.
+//
+// Header name is case-insensitive. If header has multiple values, the
+// first one is returned. If header is missing, empty string is returned.
+//
+// Host header is taken from request Host field.
+//
+// Example:
+//
+//	prepared := req.DryRun()
+//	prepared.Header("Content-Type").Contains("application/json")
+func (p *PreparedRequest) Header(name string) *String {
+	p.chain.enter("Header(%q)", name)
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newString(p.chain, "")
+	}
+
+	if http.CanonicalHeaderKey(name) == "Host" && p.httpReq.Host != "" {
+		return newString(p.chain, p.httpReq.Host)
+	}
+
+	var value string
+	if values := headerValues(p.httpReq.Header, name); len(values) != 0 {
+		value = values[0]
+	}
+
+	return newString(p.chain, value)
+}
+
+// Body returns a new String instance with request body as it would be
+// sent, i.e. after encoding and compression.
+//
+// Streamed body is not available and is returned as empty string.
+//
+// Example:
+//
+//	prepared := req.DryRun()
+//	prepared.Body().Equal("foo=bar")
+func (p *PreparedRequest) Body() *String {
+	p.chain.enter("Body()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newString(p.chain, "")
+	}
+
+	return newString(p.chain, string(p.body))
+}
+
+// Wire returns a new Bytes instance with HTTP/1.1 representation of
+// request, as it's written to connection by http.Transport: request line,
+// headers (including ones added by transport, like User-Agent and
+// Content-Length), and body.
+//
+// Body is omitted if it was streamed. For HTTP/2 requests, representation
+// is still HTTP/1.1, because HTTP/2 framing is binary.
+//
+// Example:
+//
+//	prepared := req.DryRun()
+//	prepared.Wire().Raw() // "PUT /users/1 HTTP/1.1\r\nHost: example.com\r\n..."
+func (p *PreparedRequest) Wire() *Bytes {
+	p.chain.enter("Wire()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newBytes(p.chain, nil)
+	}
+
+	wire, err := dumpRequest(p.httpReq, p.body)
+	if err != nil {
+		p.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to serialize request"),
+				err,
+			},
+		})
+		return newBytes(p.chain, nil)
+	}
+
+	return newBytes(p.chain, wire)
+}
+
+// JSON returns a new Value instance with JSON decoded from request body.
+//
+// JSON fails if body is not a valid JSON.
+//
+// Example:
+//
+//	prepared := req.DryRun()
+//	prepared.JSON().Object().ValueEqual("name", "john")
+func (p *PreparedRequest) JSON() *Value {
+	p.chain.enter("JSON()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newValue(p.chain, nil)
+	}
+
+	var value interface{}
+
+	if err := json.Unmarshal(p.body, &value); err != nil {
+		p.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{string(p.body)},
+			Errors: []error{
+				errors.New("failed to decode json"),
+				err,
+			},
+		})
+		return newValue(p.chain, nil)
+	}
+
+	return newValue(p.chain, value)
+}
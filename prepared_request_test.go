@@ -0,0 +1,28 @@
+package httpexpect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreparedRequestFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newPreparedRequest(chain, nil, nil)
+
+	value.chain.assertFailed(t)
+
+	assert.Nil(t, value.Raw())
+
+	value.Method().chain.assertFailed(t)
+	value.URL().chain.assertFailed(t)
+	value.Path().chain.assertFailed(t)
+	value.Query("foo").chain.assertFailed(t)
+	value.Headers().chain.assertFailed(t)
+	value.Header("foo").chain.assertFailed(t)
+	value.Body().chain.assertFailed(t)
+	value.Wire().chain.assertFailed(t)
+	value.JSON().chain.assertFailed(t)
+}
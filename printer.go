@@ -3,9 +3,13 @@ package httpexpect
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -13,7 +17,8 @@ import (
 )
 
 // Printer is used to print requests and responses.
-// CompactPrinter, DebugPrinter, and CurlPrinter implement this interface.
+// CompactPrinter, DebugPrinter, CurlPrinter, and FilePrinter implement this
+// interface.
 type Printer interface {
 	// Request is called before request is sent.
 	// It is allowed to read and close request body, or ignore it.
@@ -170,3 +175,245 @@ func (p DebugPrinter) WebsocketRead(typ int, content []byte, closeCode int) {
 	fmt.Fprintf(b, "\n")
 	p.logger.Logf(b.String())
 }
+
+// FilePrinter implements Printer.
+// Writes full dumps of requests and responses in wire format to a log file
+// in per-test directory.
+//
+// Log file path is Dir/<test name>/http.log; subtests are stored in nested
+// directories. When file size would exceed MaxFileSize, file is rotated:
+// http.log is renamed to http.1.log, http.1.log to http.2.log, and so on,
+// keeping up to MaxFiles rotated files.
+//
+// When FilePrinter is used in Config.Printers, failures of assertions on
+// requests and responses include path to log file. Expect.Fork rebinds
+// FilePrinter to forked test, so that every subtest gets its own log file.
+//
+// Example:
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:  "http://example.com",
+//	    Reporter: httpexpect.NewAssertReporter(t),
+//	    Printers: []httpexpect.Printer{
+//	        httpexpect.NewFilePrinter(t, "testdata/logs"),
+//	    },
+//	})
+type FilePrinter struct {
+	// Directory where per-test directories are created.
+	Dir string
+
+	// Maximum size of log file in bytes before it's rotated.
+	// If zero, 10 MiB is used.
+	MaxFileSize int64
+
+	// Maximum number of rotated files to keep.
+	// If zero, 3 is used. If negative, rotated files are removed.
+	MaxFiles int
+
+	// Sanitizers applied to dumps, in addition to Config.Sanitizers.
+	// If nil, Authorization and Proxy-Authorization headers are masked.
+	Sanitizers []Sanitizer
+
+	name string
+	mu   sync.Mutex
+}
+
+const (
+	defaultFilePrinterMaxSize  = 10 << 20
+	defaultFilePrinterMaxFiles = 3
+)
+
+// NewFilePrinter returns a new FilePrinter given a test and a directory.
+// Log file is created on first write.
+func NewFilePrinter(t TestingTB, dir string) *FilePrinter {
+	return &FilePrinter{
+		Dir:  dir,
+		name: t.Name(),
+	}
+}
+
+// Path returns path to log file of the test.
+func (p *FilePrinter) Path() string {
+	parts := []string{p.Dir}
+
+	if p.name != "" {
+		for _, part := range strings.Split(p.name, "/") {
+			parts = append(parts, snapshotUnsafeChars.ReplaceAllString(part, "_"))
+		}
+	}
+
+	parts = append(parts, "http.log")
+
+	return filepath.Join(parts...)
+}
+
+// Request implements Printer.Request.
+func (p *FilePrinter) Request(req *http.Request) {
+	if req == nil {
+		return
+	}
+
+	ret := req.WithContext(req.Context())
+	ret.Header = sanitizeHeader(p.sanitizers(), req.Header)
+
+	if req.Body != nil && req.Body != http.NoBody {
+		content, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			panic(err)
+		}
+
+		content = sanitizeBody(p.sanitizers(), req.Header.Get("Content-Type"), content)
+
+		ret.Body = ioutil.NopCloser(bytes.NewReader(content))
+		ret.ContentLength = int64(len(content))
+	}
+
+	dump, err := httputil.DumpRequest(ret, true)
+	if err != nil {
+		panic(err)
+	}
+
+	p.write(fmt.Sprintf("### %s request\n",
+		time.Now().UTC().Format(time.RFC3339Nano)), dump)
+}
+
+// Response implements Printer.Response.
+func (p *FilePrinter) Response(resp *http.Response, duration time.Duration) {
+	if resp == nil {
+		return
+	}
+
+	ret := *resp
+	ret.Header = sanitizeHeader(p.sanitizers(), resp.Header)
+
+	if resp.Body != nil && resp.Body != http.NoBody {
+		content, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			panic(err)
+		}
+
+		content = sanitizeBody(p.sanitizers(), resp.Header.Get("Content-Type"), content)
+
+		ret.Body = ioutil.NopCloser(bytes.NewReader(content))
+		ret.ContentLength = int64(len(content))
+	}
+
+	dump, err := httputil.DumpResponse(&ret, true)
+	if err != nil {
+		panic(err)
+	}
+
+	p.write(fmt.Sprintf("### %s response %s\n",
+		time.Now().UTC().Format(time.RFC3339Nano), duration), dump)
+}
+
+func (p *FilePrinter) sanitizers() []Sanitizer {
+	if p.Sanitizers != nil {
+		return p.Sanitizers
+	}
+	return []Sanitizer{MaskHeaders("Authorization", "Proxy-Authorization")}
+}
+
+func (p *FilePrinter) write(title string, dump []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry := make([]byte, 0, len(title)+len(dump)+2)
+	entry = append(entry, title...)
+	entry = append(entry, dump...)
+	entry = append(entry, "\n\n"...)
+
+	path := p.Path()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		panic(err)
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 &&
+		info.Size()+int64(len(entry)) > p.maxFileSize() {
+		p.rotate(path)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(entry); err != nil {
+		panic(err)
+	}
+}
+
+func (p *FilePrinter) rotate(path string) {
+	maxFiles := p.MaxFiles
+	if maxFiles == 0 {
+		maxFiles = defaultFilePrinterMaxFiles
+	}
+
+	if maxFiles < 0 {
+		_ = os.Remove(path)
+		return
+	}
+
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+
+	rotated := func(n int) string {
+		return fmt.Sprintf("%s.%d%s", base, n, ext)
+	}
+
+	_ = os.Remove(rotated(maxFiles))
+
+	for n := maxFiles - 1; n > 0; n-- {
+		_ = os.Rename(rotated(n), rotated(n+1))
+	}
+
+	_ = os.Rename(path, rotated(1))
+}
+
+func (p *FilePrinter) maxFileSize() int64 {
+	if p.MaxFileSize > 0 {
+		return p.MaxFileSize
+	}
+	return defaultFilePrinterMaxSize
+}
+
+// returns a new FilePrinter with the same settings, writing to log file
+// of given test
+func (p *FilePrinter) fork(t TestingTB) *FilePrinter {
+	return &FilePrinter{
+		Dir:         p.Dir,
+		MaxFileSize: p.MaxFileSize,
+		MaxFiles:    p.MaxFiles,
+		Sanitizers:  p.Sanitizers,
+		name:        t.Name(),
+	}
+}
+
+// failures of requests logged to file include path to log file
+func (r *Request) initFilePrinters() {
+	var paths []string
+
+	for _, printer := range r.config.Printers {
+		if p, ok := printer.(*FilePrinter); ok {
+			paths = append(paths, p.Path())
+		}
+	}
+
+	if len(paths) == 0 {
+		return
+	}
+
+	onFailure := r.chain.onFailure
+
+	r.chain.onFailure = func(ctx *AssertionContext, failure *AssertionFailure) {
+		for _, path := range paths {
+			failure.Errors = append(failure.Errors,
+				fmt.Errorf("requests and responses are logged to %s", path))
+		}
+		if onFailure != nil {
+			onFailure(ctx, failure)
+		}
+	}
+}
@@ -2,9 +2,17 @@ package httpexpect
 
 import (
 	"bytes"
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCompactPrinter(t *testing.T) {
@@ -42,3 +50,157 @@ func TestDebugPrinter(t *testing.T) {
 	printer.Response(&http.Response{}, 0)
 	printer.Response(nil, 0)
 }
+
+func TestFilePrinter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	t.Run("path", func(t *testing.T) {
+		printer := NewFilePrinter(&mockTestingTB{name: "TestFoo/sub test"}, dir)
+
+		assert.Equal(t,
+			filepath.Join(dir, "TestFoo", "sub_test", "http.log"), printer.Path())
+
+		printer = NewFilePrinter(&mockTestingTB{}, dir)
+
+		assert.Equal(t, filepath.Join(dir, "http.log"), printer.Path())
+	})
+
+	t.Run("dump", func(t *testing.T) {
+		printer := NewFilePrinter(&mockTestingTB{name: "TestDump"}, dir)
+
+		req, _ := http.NewRequest("POST", "http://example.com/path",
+			bytes.NewBufferString("request body"))
+		req.Header.Set("Authorization", "Bearer secret")
+
+		printer.Request(req)
+		printer.Request(nil)
+
+		printer.Response(&http.Response{
+			StatusCode: http.StatusOK,
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{"X-Foo": {"bar"}},
+			Body:       ioutil.NopCloser(bytes.NewBufferString("response body")),
+		}, 0)
+		printer.Response(nil, 0)
+
+		b, err := ioutil.ReadFile(printer.Path())
+		require.NoError(t, err)
+
+		log := string(b)
+
+		assert.Contains(t, log, " request\nPOST /path HTTP/1.1\r\nHost: example.com\r\n")
+		assert.Contains(t, log, "Authorization: ***\r\n")
+		assert.NotContains(t, log, "secret")
+		assert.Contains(t, log, "\r\n\r\nrequest body\n\n")
+
+		assert.Contains(t, log, " response 0s\nHTTP/1.1 200 OK\r\n")
+		assert.Contains(t, log, "X-Foo: bar\r\n")
+		assert.Contains(t, log, "\r\n\r\nresponse body\n\n")
+	})
+
+	t.Run("sanitizers", func(t *testing.T) {
+		printer := NewFilePrinter(&mockTestingTB{name: "TestSanitizers"}, dir)
+
+		printer.Sanitizers = []Sanitizer{
+			ReplaceRegexp(regexp.MustCompile(`\d+`), "N"),
+		}
+
+		req, _ := http.NewRequest("POST", "http://example.com",
+			bytes.NewBufferString("id=123"))
+		req.Header.Set("Authorization", "Bearer secret")
+
+		printer.Request(req)
+
+		b, err := ioutil.ReadFile(printer.Path())
+		require.NoError(t, err)
+
+		assert.Contains(t, string(b), "Authorization: Bearer secret\r\n")
+		assert.Contains(t, string(b), "id=N")
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		printer := NewFilePrinter(&mockTestingTB{name: "TestRotation"}, dir)
+
+		printer.MaxFileSize = 100
+		printer.MaxFiles = 2
+
+		for i := 0; i < 5; i++ {
+			req, _ := http.NewRequest("GET", "http://example.com", nil)
+			printer.Request(req)
+		}
+
+		logDir := filepath.Dir(printer.Path())
+
+		files, err := ioutil.ReadDir(logDir)
+		require.NoError(t, err)
+
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Name())
+			assert.True(t, f.Size() <= 100)
+		}
+
+		assert.Equal(t, []string{"http.1.log", "http.2.log", "http.log"}, names)
+
+		printer.MaxFiles = -1
+
+		req, _ := http.NewRequest("GET", "http://example.com", nil)
+		printer.Request(req)
+
+		_, err = os.Stat(filepath.Join(logDir, "http.3.log"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("fork", func(t *testing.T) {
+		printer := NewFilePrinter(&mockTestingTB{name: "TestFork"}, dir)
+		printer.MaxFiles = 5
+
+		e := WithConfig(Config{
+			BaseURL:  "http://example.com",
+			Reporter: newMockReporter(t),
+			Printers: []Printer{printer},
+		})
+
+		forked := e.Fork(&mockTestingTB{name: "TestFork/sub"})
+
+		if assert.Equal(t, 1, len(forked.config.Printers)) {
+			p := forked.config.Printers[0].(*FilePrinter)
+
+			assert.Equal(t, filepath.Join(dir, "TestFork", "sub", "http.log"), p.Path())
+			assert.Equal(t, 5, p.MaxFiles)
+		}
+
+		assert.Equal(t, filepath.Join(dir, "TestFork", "http.log"), printer.Path())
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		printer := NewFilePrinter(&mockTestingTB{name: "TestFailure"}, dir)
+
+		handler := &mockAssertionHandler{}
+
+		e := WithConfig(Config{
+			BaseURL: "http://example.com",
+			Client: &mockClient{
+				err: errors.New("connection refused"),
+			},
+			AssertionHandler: handler,
+			Printers:         []Printer{printer},
+		})
+
+		e.GET("/path").Expect()
+
+		if assert.NotNil(t, handler.failure) {
+			last := handler.failure.Errors[len(handler.failure.Errors)-1]
+
+			assert.True(t, strings.HasSuffix(last.Error(), printer.Path()))
+		}
+
+		b, err := ioutil.ReadFile(printer.Path())
+		require.NoError(t, err)
+
+		assert.Contains(t, string(b), "GET /path HTTP/1.1\r\n")
+	})
+}
@@ -0,0 +1,263 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Problem provides methods to inspect "problem details" object, i.e.
+// machine-readable error description defined by RFC 7807, with "type",
+// "title", "status", "detail", and "instance" members.
+type Problem struct {
+	chain *chain
+	value map[string]interface{}
+}
+
+// NewProblem returns a new Problem instance.
+//
+// reporter should not be nil. value should be decoded problem details,
+// i.e. an object where standard members, if present, have proper types.
+// If it is not, failure is reported.
+//
+// Example:
+//
+//	problem := NewProblem(t, map[string]interface{}{
+//	    "type":   "https://example.com/probs/out-of-credit",
+//	    "title":  "You do not have enough credit.",
+//	    "status": 403,
+//	})
+//	problem.Status().Equal(403)
+func NewProblem(reporter Reporter, value interface{}) *Problem {
+	return newProblem(newChainWithDefaults("Problem()", reporter), value)
+}
+
+func newProblem(parent *chain, val interface{}) *Problem {
+	p := &Problem{parent.clone(), nil}
+
+	if p.chain.failed() {
+		return p
+	}
+
+	obj, ok := canonValue(p.chain, val)
+	if !ok {
+		return p
+	}
+
+	m, ok := obj.(map[string]interface{})
+	if !ok {
+		p.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{val},
+			Errors: []error{
+				errors.New("expected: problem details object"),
+			},
+		})
+		return p
+	}
+
+	for _, name := range []string{"type", "title", "detail", "instance"} {
+		if member, ok := m[name]; ok {
+			if _, ok := member.(string); !ok {
+				p.chain.fail(AssertionFailure{
+					Type:   AssertValid,
+					Actual: &AssertionValue{member},
+					Errors: []error{
+						fmt.Errorf("expected: problem details %q member is string", name),
+					},
+				})
+				return p
+			}
+		}
+	}
+
+	if member, ok := m["status"]; ok {
+		if _, ok := member.(float64); !ok {
+			p.chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{member},
+				Errors: []error{
+					errors.New(`expected: problem details "status" member is number`),
+				},
+			})
+			return p
+		}
+	}
+
+	p.value = m
+
+	return p
+}
+
+// Raw returns underlying value attached to Problem.
+//
+// Numbers in returned object are represented as float64, like in
+// decoded JSON.
+//
+// Example:
+//
+//	problem := NewProblem(t, value)
+//	assert.Equal(t, "Not Found", problem.Raw()["title"])
+func (p *Problem) Raw() map[string]interface{} {
+	return p.value
+}
+
+// Object returns a new Object instance with whole problem details object,
+// including extension members.
+//
+// Example:
+//
+//	problem := NewProblem(t, value)
+//	problem.Object().ContainsKey("balance")
+func (p *Problem) Object() *Object {
+	p.chain.enter("Object()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newObject(p.chain, nil)
+	}
+
+	return newObject(p.chain, p.value)
+}
+
+// Type returns a new String instance with "type" member.
+//
+// If "type" member is missing, "about:blank" is used, as defined by RFC.
+//
+// Example:
+//
+//	problem := NewProblem(t, value)
+//	problem.Type().Equal("https://example.com/probs/out-of-credit")
+func (p *Problem) Type() *String {
+	p.chain.enter("Type()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newString(p.chain, "")
+	}
+
+	if typ, ok := p.value["type"].(string); ok {
+		return newString(p.chain, typ)
+	}
+
+	return newString(p.chain, "about:blank")
+}
+
+// Title returns a new String instance with "title" member.
+//
+// Title fails if "title" member is missing.
+//
+// Example:
+//
+//	problem := NewProblem(t, value)
+//	problem.Title().Equal("You do not have enough credit.")
+func (p *Problem) Title() *String {
+	p.chain.enter("Title()")
+	defer p.chain.leave()
+
+	return p.stringMember("title")
+}
+
+// Detail returns a new String instance with "detail" member.
+//
+// Detail fails if "detail" member is missing.
+//
+// Example:
+//
+//	problem := NewProblem(t, value)
+//	problem.Detail().Contains("balance is 30")
+func (p *Problem) Detail() *String {
+	p.chain.enter("Detail()")
+	defer p.chain.leave()
+
+	return p.stringMember("detail")
+}
+
+// Instance returns a new String instance with "instance" member.
+//
+// Instance fails if "instance" member is missing.
+//
+// Example:
+//
+//	problem := NewProblem(t, value)
+//	problem.Instance().Equal("/account/12345/msgs/abc")
+func (p *Problem) Instance() *String {
+	p.chain.enter("Instance()")
+	defer p.chain.leave()
+
+	return p.stringMember("instance")
+}
+
+// Status returns a new Number instance with "status" member.
+//
+// Status fails if "status" member is missing.
+//
+// Example:
+//
+//	problem := NewProblem(t, value)
+//	problem.Status().Equal(http.StatusForbidden)
+func (p *Problem) Status() *Number {
+	p.chain.enter("Status()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newNumber(p.chain, 0)
+	}
+
+	status, ok := p.value["status"].(float64)
+	if !ok {
+		p.failMissing("status")
+		return newNumber(p.chain, 0)
+	}
+
+	return newNumber(p.chain, status)
+}
+
+// Extension returns a new Value instance with given extension member.
+//
+// Extension fails if member is missing.
+//
+// Example:
+//
+//	problem := NewProblem(t, value)
+//	problem.Extension("balance").Number().Equal(30)
+func (p *Problem) Extension(name string) *Value {
+	p.chain.enter("Extension(%q)", name)
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newValue(p.chain, nil)
+	}
+
+	member, ok := p.value[name]
+	if !ok {
+		p.failMissing(name)
+		return newValue(p.chain, nil)
+	}
+
+	return newValue(p.chain, member)
+}
+
+func (p *Problem) stringMember(name string) *String {
+	if p.chain.failed() {
+		return newString(p.chain, "")
+	}
+
+	member, ok := p.value[name].(string)
+	if !ok {
+		p.failMissing(name)
+		return newString(p.chain, "")
+	}
+
+	return newString(p.chain, member)
+}
+
+func (p *Problem) failMissing(name string) {
+	p.chain.fail(AssertionFailure{
+		Type:     AssertContainsKey,
+		Actual:   &AssertionValue{p.value},
+		Expected: &AssertionValue{name},
+		Errors: []error{
+			fmt.Errorf("expected: problem details contain %q member", name),
+		},
+	})
+}
@@ -0,0 +1,117 @@
+package httpexpect
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newProblem(chain, map[string]interface{}{
+		"title": "foo",
+	})
+
+	value.chain.assertFailed(t)
+
+	assert.Nil(t, value.Raw())
+
+	value.Object().chain.assertFailed(t)
+	value.Type().chain.assertFailed(t)
+	value.Title().chain.assertFailed(t)
+	value.Status().chain.assertFailed(t)
+	value.Detail().chain.assertFailed(t)
+	value.Instance().chain.assertFailed(t)
+	value.Extension("foo").chain.assertFailed(t)
+}
+
+func TestProblemConstructor(t *testing.T) {
+	cases := []struct {
+		name  string
+		value interface{}
+		ok    bool
+	}{
+		{"empty object", map[string]interface{}{}, true},
+		{"full object", map[string]interface{}{
+			"type":     "https://example.com/probs/out-of-credit",
+			"title":    "You do not have enough credit.",
+			"status":   403,
+			"detail":   "Your current balance is 30, but that costs 50.",
+			"instance": "/account/12345/msgs/abc",
+			"balance":  30,
+		}, true},
+		{"nil", nil, false},
+		{"array", []interface{}{}, false},
+		{"string", "problem", false},
+		{"bad type", map[string]interface{}{"type": 1}, false},
+		{"bad title", map[string]interface{}{"title": nil}, false},
+		{"bad detail", map[string]interface{}{"detail": true}, false},
+		{"bad instance", map[string]interface{}{"instance": 1}, false},
+		{"bad status", map[string]interface{}{"status": "403"}, false},
+		{"unmarshalable", map[string]interface{}{"a": make(chan int)}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			value := NewProblem(newMockReporter(t), tc.value)
+
+			if tc.ok {
+				value.chain.assertOK(t)
+			} else {
+				value.chain.assertFailed(t)
+			}
+		})
+	}
+}
+
+func TestProblemMembers(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewProblem(reporter, map[string]interface{}{
+		"type":     "https://example.com/probs/out-of-credit",
+		"title":    "You do not have enough credit.",
+		"status":   403,
+		"detail":   "Your current balance is 30, but that costs 50.",
+		"instance": "/account/12345/msgs/abc",
+		"balance":  30,
+	})
+
+	assert.Equal(t, float64(403), value.Raw()["status"])
+
+	value.Type().Equal("https://example.com/probs/out-of-credit").chain.assertOK(t)
+	value.Title().Equal("You do not have enough credit.").chain.assertOK(t)
+	value.Status().Equal(http.StatusForbidden).chain.assertOK(t)
+	value.Detail().Contains("balance is 30").chain.assertOK(t)
+	value.Instance().Equal("/account/12345/msgs/abc").chain.assertOK(t)
+	value.Extension("balance").Number().Equal(30).chain.assertOK(t)
+	value.Object().ContainsKey("balance").chain.assertOK(t)
+
+	value.chain.assertOK(t)
+}
+
+func TestProblemMissingMembers(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewProblem(reporter, map[string]interface{}{})
+
+	value.Type().Equal("about:blank").chain.assertOK(t)
+	value.chain.assertOK(t)
+
+	cases := []func(*Problem){
+		func(p *Problem) { p.Title() },
+		func(p *Problem) { p.Status() },
+		func(p *Problem) { p.Detail() },
+		func(p *Problem) { p.Instance() },
+		func(p *Problem) { p.Extension("balance") },
+	}
+
+	for _, check := range cases {
+		value := NewProblem(reporter, map[string]interface{}{})
+
+		check(value)
+		value.chain.assertFailed(t)
+	}
+}
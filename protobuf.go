@@ -0,0 +1,98 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ProtoCodec is used to marshal and unmarshal protobuf messages.
+//
+// ProtoCodec is used by Request.WithProtobuf, Request.WithProtobufJSON,
+// and Response.Protobuf. httpexpect doesn't depend on any protobuf library,
+// so if your messages are generated by google.golang.org/protobuf, you
+// should provide a codec that uses proto and protojson packages:
+//
+//	type protoCodec struct{}
+//
+//	func (protoCodec) Encode(msg interface{}) ([]byte, error) {
+//	    return proto.Marshal(msg.(proto.Message))
+//	}
+//
+//	func (protoCodec) Decode(data []byte, msg interface{}) error {
+//	    return proto.Unmarshal(data, msg.(proto.Message))
+//	}
+//
+//	func (protoCodec) EncodeJSON(msg interface{}) ([]byte, error) {
+//	    return protojson.Marshal(msg.(proto.Message))
+//	}
+//
+//	func (protoCodec) DecodeJSON(data []byte, msg interface{}) error {
+//	    return protojson.Unmarshal(data, msg.(proto.Message))
+//	}
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    Reporter:   httpexpect.NewAssertReporter(t),
+//	    ProtoCodec: protoCodec{},
+//	})
+type ProtoCodec interface {
+	// Encode encodes message into binary wire format.
+	Encode(msg interface{}) ([]byte, error)
+
+	// Decode decodes message from binary wire format.
+	Decode(data []byte, msg interface{}) error
+
+	// EncodeJSON encodes message into JSON.
+	EncodeJSON(msg interface{}) ([]byte, error)
+
+	// DecodeJSON decodes message from JSON.
+	DecodeJSON(data []byte, msg interface{}) error
+}
+
+// DefaultProtoCodec is the default ProtoCodec implementation.
+//
+// For binary format, it requires message to implement Marshal and
+// Unmarshal methods, like messages generated by gogo/protobuf:
+//
+//	Marshal() ([]byte, error)
+//	Unmarshal([]byte) error
+//
+// For JSON format, it uses encoding/json.
+type DefaultProtoCodec struct{}
+
+type protoMarshaler interface {
+	Marshal() ([]byte, error)
+}
+
+type protoUnmarshaler interface {
+	Unmarshal([]byte) error
+}
+
+// Encode implements ProtoCodec.Encode.
+func (DefaultProtoCodec) Encode(msg interface{}) ([]byte, error) {
+	m, ok := msg.(protoMarshaler)
+	if !ok {
+		return nil, fmt.Errorf(
+			"%T doesn't implement Marshal() method, set Config.ProtoCodec", msg)
+	}
+	return m.Marshal()
+}
+
+// Decode implements ProtoCodec.Decode.
+func (DefaultProtoCodec) Decode(data []byte, msg interface{}) error {
+	m, ok := msg.(protoUnmarshaler)
+	if !ok {
+		return fmt.Errorf(
+			"%T doesn't implement Unmarshal() method, set Config.ProtoCodec", msg)
+	}
+	return m.Unmarshal(data)
+}
+
+// EncodeJSON implements ProtoCodec.EncodeJSON.
+func (DefaultProtoCodec) EncodeJSON(msg interface{}) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+// DecodeJSON implements ProtoCodec.DecodeJSON.
+func (DefaultProtoCodec) DecodeJSON(data []byte, msg interface{}) error {
+	return json.Unmarshal(data, msg)
+}
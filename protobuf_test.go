@@ -0,0 +1,62 @@
+package httpexpect
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Message with single string field with tag 1
+type mockProtoMessage struct {
+	Name string `json:"name"`
+}
+
+func (m *mockProtoMessage) Marshal() ([]byte, error) {
+	if len(m.Name) > 127 {
+		return nil, errors.New("name too long")
+	}
+	b := []byte{0x0a, byte(len(m.Name))}
+	return append(b, m.Name...), nil
+}
+
+func (m *mockProtoMessage) Unmarshal(b []byte) error {
+	if len(b) < 2 || b[0] != 0x0a || int(b[1]) != len(b)-2 {
+		return errors.New("invalid message")
+	}
+	m.Name = string(b[2:])
+	return nil
+}
+
+func TestProtobufDefaultCodec(t *testing.T) {
+	codec := DefaultProtoCodec{}
+
+	t.Run("binary", func(t *testing.T) {
+		b, err := codec.Encode(&mockProtoMessage{Name: "john"})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("\x0a\x04john"), b)
+
+		var msg mockProtoMessage
+		assert.NoError(t, codec.Decode(b, &msg))
+		assert.Equal(t, "john", msg.Name)
+
+		assert.Error(t, codec.Decode([]byte("bad"), &msg))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		b, err := codec.EncodeJSON(&mockProtoMessage{Name: "john"})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"john"}`, string(b))
+
+		var msg mockProtoMessage
+		assert.NoError(t, codec.DecodeJSON(b, &msg))
+		assert.Equal(t, "john", msg.Name)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := codec.Encode(struct{}{})
+		assert.Error(t, err)
+
+		assert.Error(t, codec.Decode([]byte{}, &struct{}{}))
+	})
+}
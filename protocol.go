@@ -0,0 +1,123 @@
+package httpexpect
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"golang.org/x/net/http2"
+)
+
+// Protocol defines HTTP protocol version used to send requests.
+type Protocol int
+
+const (
+	// ProtocolAuto lets client negotiate protocol version.
+	// This is the default. http.Client uses HTTP/2 for HTTPS requests if
+	// server supports it, and HTTP/1.1 otherwise.
+	ProtocolAuto Protocol = iota
+
+	// ProtocolHTTP1 forces HTTP/1.1.
+	// HTTP/2 is not offered during TLS handshake (ALPN).
+	ProtocolHTTP1
+
+	// ProtocolHTTP2 forces HTTP/2 over TLS.
+	// Request fails if server doesn't negotiate "h2" during TLS handshake
+	// (ALPN), instead of falling back to HTTP/1.1. Server push is rejected.
+	ProtocolHTTP2
+
+	// ProtocolH2C forces HTTP/2 over cleartext TCP ("h2c") with prior
+	// knowledge, i.e. without HTTP/1.1 upgrade.
+	ProtocolH2C
+)
+
+// HTTP/3 is not listed because it is not supported by the standard library;
+// it can be used by passing an HTTP/3-capable client to Config.Client or
+// Request.WithClient, and checked using Response.ProtocolVersion.
+
+func (p Protocol) String() string {
+	switch p {
+	case ProtocolAuto:
+		return "auto"
+	case ProtocolHTTP1:
+		return "HTTP/1.1"
+	case ProtocolHTTP2:
+		return "HTTP/2"
+	case ProtocolH2C:
+		return "h2c"
+	}
+	return "unknown"
+}
+
+var errProtocolClient = errors.New(
+	"protocol can be set only if Client is *http.Client" +
+		" with nil Transport or *http.Transport")
+
+// returns copy of client with transport configured for given protocol
+func protocolClient(client Client, protocol Protocol) (*http.Client, error) {
+	clientCopy, transport := cloneTransport(client)
+	if transport == nil {
+		return nil, errProtocolClient
+	}
+
+	switch protocol {
+	case ProtocolHTTP1:
+		transport.ForceAttemptHTTP2 = false
+		// non-nil empty map disables HTTP/2
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.NextProtos = []string{"http/1.1"}
+
+	case ProtocolHTTP2, ProtocolH2C:
+		dial := transport.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+
+		h2Transport := &http2.Transport{
+			TLSClientConfig:    transport.TLSClientConfig,
+			DisableCompression: transport.DisableCompression,
+		}
+
+		if protocol == ProtocolH2C {
+			h2Transport.AllowHTTP = true
+			h2Transport.DialTLS = func(
+				network, addr string, _ *tls.Config,
+			) (net.Conn, error) {
+				return dial(context.Background(), network, addr)
+			}
+		} else {
+			h2Transport.DialTLS = func(
+				network, addr string, cfg *tls.Config,
+			) (net.Conn, error) {
+				conn, err := dial(context.Background(), network, addr)
+				if err != nil {
+					return nil, err
+				}
+				tlsConn := tls.Client(conn, cfg)
+				if err := tlsConn.Handshake(); err != nil {
+					_ = conn.Close()
+					return nil, err
+				}
+				if p := tlsConn.ConnectionState().NegotiatedProtocol; p != http2.NextProtoTLS {
+					_ = conn.Close()
+					return nil, fmt.Errorf(
+						"server negotiated %q protocol instead of %q", p, http2.NextProtoTLS)
+				}
+				return tlsConn, nil
+			}
+		}
+
+		clientCopy.Transport = h2Transport
+
+	default:
+		return nil, errors.New("unsupported protocol")
+	}
+
+	return clientCopy, nil
+}
@@ -0,0 +1,100 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// records proxy selected by transport for request
+type proxyUsage struct {
+	mu  sync.Mutex
+	url *url.URL
+}
+
+func (p *proxyUsage) set(u *url.URL) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.url = u
+}
+
+func (p *proxyUsage) get() *url.URL {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.url
+}
+
+// parses and validates proxy url
+func parseProxyURL(proxy string) (*url.URL, error) {
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf(
+			`unsupported proxy scheme %q, expected "http", "https", or "socks5"`,
+			proxyURL.Scheme)
+	}
+
+	if proxyURL.Host == "" {
+		return nil, errors.New("proxy url has no host")
+	}
+
+	return proxyURL, nil
+}
+
+// returns proxy function that always selects given proxy and records
+// its usage
+func proxyFunc(
+	proxyURL *url.URL, usage *proxyUsage,
+) func(*http.Request) (*url.URL, error) {
+	return func(*http.Request) (*url.URL, error) {
+		usage.set(proxyURL)
+		return proxyURL, nil
+	}
+}
+
+// returns copy of client that sends requests via given proxy;
+// returns nil if client doesn't support proxies
+func proxyClient(
+	client Client, proxyURL *url.URL, usage *proxyUsage,
+) *http.Client {
+	clientCopy, transport := cloneTransport(client)
+	if transport == nil {
+		return nil
+	}
+
+	transport.Proxy = proxyFunc(proxyURL, usage)
+
+	return clientCopy
+}
+
+// returns copy of websocket dialer that connects via given proxy;
+// returns nil if websocket dialer doesn't support proxies
+func proxyWebsocketDialer(
+	wsDialer WebsocketDialer, proxyURL *url.URL, usage *proxyUsage,
+) WebsocketDialer {
+	gorillaDialer, ok := wsDialer.(*websocket.Dialer)
+	if !ok {
+		return nil
+	}
+
+	dialerCopy := *gorillaDialer
+	dialerCopy.Proxy = proxyFunc(proxyURL, usage)
+
+	return &dialerCopy
+}
+
+// formats proxy url without credentials
+func proxyString(proxyURL *url.URL) string {
+	return proxyURL.Scheme + "://" + proxyURL.Host
+}
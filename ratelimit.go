@@ -0,0 +1,434 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit provides methods to inspect rate limit headers of response.
+//
+// Both header families are supported:
+//   - IETF draft "RateLimit header fields for HTTP": either separate
+//     RateLimit-Limit, RateLimit-Remaining, and RateLimit-Reset headers,
+//     or combined RateLimit header (with limit in RateLimit-Policy header)
+//   - de-facto X-RateLimit-Limit, X-RateLimit-Remaining, and
+//     X-RateLimit-Reset headers
+//
+// If response has both, IETF headers are used.
+type RateLimit struct {
+	chain *chain
+	value rateLimitValue
+}
+
+type rateLimitValue struct {
+	// header names, used in failure messages
+	names [3]string
+
+	limit     string
+	remaining string
+	reset     string
+
+	// base time for reset delta, from Date header
+	date time.Time
+}
+
+// NewRateLimit returns a new RateLimit instance.
+//
+// reporter should not be nil. header is response header.
+//
+// Example:
+//
+//	rl := NewRateLimit(t, http.Header{
+//	    "X-RateLimit-Limit":     {"100"},
+//	    "X-RateLimit-Remaining": {"99"},
+//	})
+//	rl.Limit().Equal(100)
+//	rl.Remaining().Lt(100)
+func NewRateLimit(reporter Reporter, header http.Header) *RateLimit {
+	chain := newChainWithDefaults("RateLimit()", reporter)
+
+	value, ok := parseRateLimit(header)
+	if !ok {
+		failRateLimitMissing(chain, header)
+	}
+
+	return newRateLimit(chain, value)
+}
+
+func newRateLimit(parent *chain, value rateLimitValue) *RateLimit {
+	return &RateLimit{parent.clone(), value}
+}
+
+func failRateLimitMissing(chain *chain, header http.Header) {
+	chain.fail(AssertionFailure{
+		Type:   AssertContainsKey,
+		Actual: &AssertionValue{header},
+		Expected: &AssertionValue{AssertionList{
+			"RateLimit", "RateLimit-Limit", "X-RateLimit-Limit",
+		}},
+		Errors: []error{
+			errors.New("expected: response contains rate limit headers"),
+		},
+	})
+}
+
+// Limit returns a new Number instance with request quota of current
+// time window.
+//
+// Limit fails if header is missing or is not a non-negative integer.
+//
+// Example:
+//
+//	rl := resp.RateLimit()
+//	rl.Limit().Equal(100)
+func (rl *RateLimit) Limit() *Number {
+	rl.chain.enter("Limit()")
+	defer rl.chain.leave()
+
+	return rl.number(rl.value.names[0], rl.value.limit)
+}
+
+// Remaining returns a new Number instance with number of requests
+// remaining in current time window.
+//
+// Remaining fails if header is missing or is not a non-negative integer.
+//
+// Example:
+//
+//	rl := resp.RateLimit()
+//	rl.Remaining().Gt(0)
+func (rl *RateLimit) Remaining() *Number {
+	rl.chain.enter("Remaining()")
+	defer rl.chain.leave()
+
+	return rl.number(rl.value.names[1], rl.value.remaining)
+}
+
+// Reset returns a new DateTime instance with time when current time
+// window ends and quota is restored.
+//
+// IETF headers define reset as delta in seconds; X-RateLimit-Reset is
+// treated as delta if it's small, and as Unix timestamp otherwise.
+// Delta is counted from response Date header, if present, or from
+// current time.
+//
+// Reset fails if header is missing or is not a non-negative integer.
+//
+// Example:
+//
+//	rl := resp.RateLimit()
+//	rl.Reset().Gt(time.Now())
+func (rl *RateLimit) Reset() *DateTime {
+	rl.chain.enter("Reset()")
+	defer rl.chain.leave()
+
+	reset, ok := rl.reset()
+	if !ok {
+		return newDateTime(rl.chain, time.Unix(0, 0))
+	}
+
+	return newDateTime(rl.chain, reset)
+}
+
+// ResetIn returns a new Duration instance with time remaining until
+// current time window ends, counted from response Date header, if
+// present, or from current time. See Reset.
+//
+// Example:
+//
+//	rl := resp.RateLimit()
+//	rl.ResetIn().Le(time.Minute)
+func (rl *RateLimit) ResetIn() *Duration {
+	rl.chain.enter("ResetIn()")
+	defer rl.chain.leave()
+
+	reset, ok := rl.reset()
+	if !ok {
+		return newDuration(rl.chain, nil)
+	}
+
+	d := reset.Sub(rl.value.date)
+	if d < 0 {
+		d = 0
+	}
+
+	return newDuration(rl.chain, &d)
+}
+
+func (rl *RateLimit) number(name, value string) *Number {
+	n, ok := rl.parse(name, value)
+	if !ok {
+		return newNumber(rl.chain, 0)
+	}
+
+	return newNumber(rl.chain, float64(n))
+}
+
+func (rl *RateLimit) reset() (time.Time, bool) {
+	n, ok := rl.parse(rl.value.names[2], rl.value.reset)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	// delta can't be that large, so it's a Unix timestamp
+	if rl.value.names[2] == "X-RateLimit-Reset" && n >= rateLimitEpochThreshold {
+		return time.Unix(int64(n), 0), true
+	}
+
+	return rl.value.date.Add(time.Duration(n) * time.Second), true
+}
+
+// X-RateLimit-Reset values starting from this are treated as Unix
+// timestamps (year 2001)
+const rateLimitEpochThreshold = 1000000000
+
+func (rl *RateLimit) parse(name, value string) (uint64, bool) {
+	if rl.chain.failed() {
+		return 0, false
+	}
+
+	if value == "" {
+		rl.chain.fail(AssertionFailure{
+			Type:     AssertContainsKey,
+			Actual:   &AssertionValue{rl.value.names},
+			Expected: &AssertionValue{name},
+			Errors: []error{
+				fmt.Errorf("expected: response contains %q rate limit value", name),
+			},
+		})
+		return 0, false
+	}
+
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		rl.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{value},
+			Errors: []error{
+				fmt.Errorf("invalid %q rate limit value", name),
+				errors.New("expected: non-negative integer"),
+			},
+		})
+		return 0, false
+	}
+
+	return n, true
+}
+
+// AssertRateLimited sends requests created by given function until
+// server responds with 429 Too Many Requests, and checks rate limiting
+// contract of that response:
+//   - it has Retry-After header with valid delay
+//   - if it has rate limit headers, remaining quota is zero
+//
+// AssertRateLimited fails if server doesn't respond with 429 within
+// maxRequests requests. Returns the 429 response.
+//
+// Function receives Expect instance and should return request that is
+// not yet sent; AssertRateLimited calls Expect on it.
+//
+// Example:
+//
+//	e := httpexpect.Default(t, "http://example.com")
+//
+//	resp := e.AssertRateLimited(101, func(e *httpexpect.Expect) *httpexpect.Request {
+//	    return e.GET("/search").WithQuery("q", "foo")
+//	})
+//	resp.RetryAfter().Le(time.Minute)
+func (e *Expect) AssertRateLimited(
+	maxRequests int, fn func(e *Expect) *Request,
+) *Response {
+	e.chain.enter("AssertRateLimited()")
+	defer e.chain.leave()
+
+	if fn == nil || maxRequests <= 0 {
+		chain := e.chain.clone()
+		chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{maxRequests},
+			Errors: []error{
+				errors.New("unexpected nil function or non-positive request count"),
+			},
+		})
+		return newResponse(responseOpts{
+			config: e.config,
+			chain:  chain,
+		})
+	}
+
+	var (
+		resp     *Response
+		statuses []int
+	)
+
+	for n := 0; n < maxRequests; n++ {
+		req := fn(e)
+		if req == nil {
+			chain := e.chain.clone()
+			chain.fail(AssertionFailure{
+				Type: AssertUsage,
+				Errors: []error{
+					errors.New("unexpected nil request returned from function"),
+				},
+			})
+			return newResponse(responseOpts{
+				config: e.config,
+				chain:  chain,
+			})
+		}
+
+		resp = req.Expect()
+
+		if resp.chain.failed() {
+			return resp
+		}
+
+		statuses = append(statuses, resp.httpResp.StatusCode)
+
+		if resp.httpResp.StatusCode == http.StatusTooManyRequests {
+			break
+		}
+	}
+
+	if resp.httpResp.StatusCode != http.StatusTooManyRequests {
+		resp.chain.fail(AssertionFailure{
+			Type:     AssertContainsElement,
+			Actual:   &AssertionValue{statuses},
+			Expected: &AssertionValue{http.StatusTooManyRequests},
+			Errors: []error{
+				fmt.Errorf("expected: status 429 within %d requests", maxRequests),
+			},
+		})
+		return resp
+	}
+
+	var errs []error
+
+	if _, ok := parseRetryAfter(resp.httpResp); !ok {
+		errs = append(errs, fmt.Errorf(
+			"429 response has missing or invalid Retry-After header %q",
+			resp.httpResp.Header.Get("Retry-After")))
+	}
+
+	if value, ok := parseRateLimit(resp.httpResp.Header); ok && value.remaining != "" {
+		if n, err := strconv.ParseUint(value.remaining, 10, 64); err != nil || n != 0 {
+			errs = append(errs, fmt.Errorf(
+				"429 response has %s %q, expected 0",
+				value.names[1], value.remaining))
+		}
+	}
+
+	if len(errs) != 0 {
+		resp.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{resp.httpResp.Header},
+			Errors: append([]error{
+				fmt.Errorf("expected: valid 429 response after %d requests",
+					len(statuses)),
+			}, errs...),
+		})
+	}
+
+	return resp
+}
+
+// parses rate limit headers; returns false if there are none
+func parseRateLimit(header http.Header) (rateLimitValue, bool) {
+	value := rateLimitValue{
+		date: time.Now(),
+	}
+
+	if date, err := http.ParseTime(header.Get("Date")); err == nil {
+		value.date = date
+	}
+
+	switch {
+	case header.Get("RateLimit") != "":
+		params := parseRateLimitParams(header.Get("RateLimit"))
+
+		value.names = [3]string{"RateLimit", "RateLimit", "RateLimit"}
+		value.limit = params["limit"]
+		value.remaining = firstNonEmpty(params["remaining"], params["r"])
+		value.reset = firstNonEmpty(params["reset"], params["t"])
+
+		if value.limit == "" {
+			value.names[0] = "RateLimit-Policy"
+			value.limit = parseRateLimitParams(header.Get("RateLimit-Policy"))["q"]
+		}
+
+	case hasRateLimitHeaders(header, "RateLimit-"):
+		value.names = [3]string{
+			"RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset",
+		}
+		value.readHeaders(header)
+
+	case hasRateLimitHeaders(header, "X-RateLimit-"):
+		value.names = [3]string{
+			"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset",
+		}
+		value.readHeaders(header)
+
+	default:
+		return value, false
+	}
+
+	return value, true
+}
+
+func (v *rateLimitValue) readHeaders(header http.Header) {
+	v.limit = rateLimitItem(header.Get(v.names[0]))
+	v.remaining = rateLimitItem(header.Get(v.names[1]))
+	v.reset = rateLimitItem(header.Get(v.names[2]))
+}
+
+func hasRateLimitHeaders(header http.Header, prefix string) bool {
+	for _, name := range []string{"Limit", "Remaining", "Reset"} {
+		if header.Get(prefix+name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// returns first item of list, without parameters; e.g. limit may be
+// followed by quota policies: "100, 100;w=60"
+func rateLimitItem(value string) string {
+	if i := strings.IndexAny(value, ",;"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+// parses "limit=10, remaining=5, reset=30" or `"default";r=5;t=30`;
+// keys are lower-cased, only the first occurrence of key is used
+func parseRateLimitParams(value string) map[string]string {
+	params := map[string]string{}
+
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';'
+	}) {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k := strings.ToLower(strings.TrimSpace(kv[0]))
+		if _, ok := params[k]; !ok {
+			params[k] = strings.Trim(strings.TrimSpace(kv[1]), `"`)
+		}
+	}
+
+	return params
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,246 @@
+package httpexpect
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newRateLimit(chain, rateLimitValue{})
+
+	value.chain.assertFailed(t)
+
+	assert.NotNil(t, value.Limit())
+	assert.NotNil(t, value.Remaining())
+	assert.NotNil(t, value.Reset())
+	assert.NotNil(t, value.ResetIn())
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	const date = "Sat, 01 Jan 2022 00:00:00 GMT"
+
+	dateTime, _ := http.ParseTime(date)
+
+	cases := []struct {
+		name      string
+		header    http.Header
+		limit     float64
+		remaining float64
+		reset     time.Time
+	}{
+		{
+			name: "ietf separate",
+			header: http.Header{
+				"Date":                {date},
+				"Ratelimit-Limit":     {"100, 100;w=60"},
+				"Ratelimit-Remaining": {"42"},
+				"Ratelimit-Reset":     {"30"},
+			},
+			limit:     100,
+			remaining: 42,
+			reset:     dateTime.Add(30 * time.Second),
+		},
+		{
+			name: "ietf combined",
+			header: http.Header{
+				"Date":      {date},
+				"Ratelimit": {"limit=100, remaining=42, reset=30"},
+			},
+			limit:     100,
+			remaining: 42,
+			reset:     dateTime.Add(30 * time.Second),
+		},
+		{
+			name: "ietf structured",
+			header: http.Header{
+				"Date":             {date},
+				"Ratelimit":        {`"default";r=42;t=30`},
+				"Ratelimit-Policy": {`"default";q=100;w=60`},
+			},
+			limit:     100,
+			remaining: 42,
+			reset:     dateTime.Add(30 * time.Second),
+		},
+		{
+			name: "x delta",
+			header: http.Header{
+				"Date":                  {date},
+				"X-Ratelimit-Limit":     {"100"},
+				"X-Ratelimit-Remaining": {"42"},
+				"X-Ratelimit-Reset":     {"30"},
+			},
+			limit:     100,
+			remaining: 42,
+			reset:     dateTime.Add(30 * time.Second),
+		},
+		{
+			name: "x timestamp",
+			header: http.Header{
+				"Date":                  {date},
+				"X-Ratelimit-Limit":     {"100"},
+				"X-Ratelimit-Remaining": {"42"},
+				"X-Ratelimit-Reset": {
+					strconv.FormatInt(dateTime.Add(time.Minute).Unix(), 10),
+				},
+			},
+			limit:     100,
+			remaining: 42,
+			reset:     dateTime.Add(time.Minute),
+		},
+		{
+			name: "ietf preferred",
+			header: http.Header{
+				"Date":                  {date},
+				"Ratelimit-Limit":       {"100"},
+				"Ratelimit-Remaining":   {"42"},
+				"Ratelimit-Reset":       {"30"},
+				"X-Ratelimit-Limit":     {"5"},
+				"X-Ratelimit-Remaining": {"1"},
+				"X-Ratelimit-Reset":     {"1"},
+			},
+			limit:     100,
+			remaining: 42,
+			reset:     dateTime.Add(30 * time.Second),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			value := NewRateLimit(newMockReporter(t), tc.header)
+
+			value.Limit().Equal(tc.limit).chain.assertOK(t)
+			value.Remaining().Equal(tc.remaining).chain.assertOK(t)
+			value.Reset().Equal(tc.reset).chain.assertOK(t)
+			value.ResetIn().Equal(tc.reset.Sub(dateTime)).chain.assertOK(t)
+		})
+	}
+}
+
+func TestRateLimitInvalid(t *testing.T) {
+	t.Run("missing headers", func(t *testing.T) {
+		value := NewRateLimit(newMockReporter(t), http.Header{})
+		value.chain.assertFailed(t)
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		value := NewRateLimit(newMockReporter(t), http.Header{
+			"X-Ratelimit-Limit": {"100"},
+		})
+		value.chain.assertOK(t)
+
+		value.Limit().chain.assertOK(t)
+		value.Remaining().chain.assertFailed(t)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		for _, bad := range []string{"-1", "abc", "1.5"} {
+			value := NewRateLimit(newMockReporter(t), http.Header{
+				"Ratelimit-Limit": {bad},
+				"Ratelimit-Reset": {bad},
+			})
+			value.Limit().chain.assertFailed(t)
+
+			value.chain.reset()
+			value.Reset().chain.assertFailed(t)
+		}
+	})
+}
+
+func TestExpectAssertRateLimited(t *testing.T) {
+	type server struct {
+		quota      int
+		retryAfter string
+		remaining  string
+	}
+
+	newExpect := func(t *testing.T, s server) *Expect {
+		count := 0
+
+		handler := func(w http.ResponseWriter, r *http.Request) {
+			count++
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.quota))
+
+			if count > s.quota {
+				if s.retryAfter != "" {
+					w.Header().Set("Retry-After", s.retryAfter)
+				}
+				if s.remaining != "" {
+					w.Header().Set("X-RateLimit-Remaining", s.remaining)
+				}
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(s.quota-count))
+			w.WriteHeader(http.StatusOK)
+		}
+
+		return WithConfig(Config{
+			BaseURL:  "http://example.com",
+			Client:   &http.Client{Transport: NewBinder(http.HandlerFunc(handler))},
+			Reporter: newMockReporter(t),
+		})
+	}
+
+	request := func(e *Expect) *Request {
+		return e.GET("/path")
+	}
+
+	t.Run("limited", func(t *testing.T) {
+		e := newExpect(t, server{quota: 3, retryAfter: "10", remaining: "0"})
+
+		resp := e.AssertRateLimited(5, request)
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.Raw().StatusCode)
+		resp.RetryAfter().Equal(10 * time.Second).chain.assertOK(t)
+	})
+
+	t.Run("limited on last request", func(t *testing.T) {
+		e := newExpect(t, server{quota: 3, retryAfter: "10"})
+
+		e.AssertRateLimited(4, request).chain.assertOK(t)
+	})
+
+	t.Run("not limited", func(t *testing.T) {
+		e := newExpect(t, server{quota: 3, retryAfter: "10"})
+
+		e.AssertRateLimited(3, request).chain.assertFailed(t)
+	})
+
+	t.Run("no retry-after", func(t *testing.T) {
+		e := newExpect(t, server{quota: 3})
+
+		e.AssertRateLimited(5, request).chain.assertFailed(t)
+	})
+
+	t.Run("invalid retry-after", func(t *testing.T) {
+		e := newExpect(t, server{quota: 3, retryAfter: "soon"})
+
+		e.AssertRateLimited(5, request).chain.assertFailed(t)
+	})
+
+	t.Run("non-zero remaining", func(t *testing.T) {
+		e := newExpect(t, server{quota: 3, retryAfter: "10", remaining: "1"})
+
+		e.AssertRateLimited(5, request).chain.assertFailed(t)
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		e := newExpect(t, server{quota: 3, retryAfter: "10"})
+
+		e.AssertRateLimited(0, request).chain.assertFailed(t)
+		e.AssertRateLimited(5, nil).chain.assertFailed(t)
+		e.AssertRateLimited(5, func(*Expect) *Request {
+			return nil
+		}).chain.assertFailed(t)
+	})
+}
@@ -0,0 +1,353 @@
+package httpexpect
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Recorder is used to record executed requests and responses.
+//
+// Recorder is invoked from Request.Expect after response is received
+// and its body is read. If request was retried, only the last attempt
+// is recorded.
+//
+// HARRecorder implements this interface.
+type Recorder interface {
+	// Record is called for every executed request.
+	// It should not modify request and response.
+	Record(exchange *RecordedExchange)
+}
+
+// RecordedExchange holds executed request and received response.
+type RecordedExchange struct {
+	// Sent request.
+	Request *http.Request
+
+	// Request body, nil if request has no body or if body was streamed.
+	RequestBody []byte
+
+	// Received response.
+	Response *http.Response
+
+	// Response body.
+	ResponseBody []byte
+
+	// Time when request was sent.
+	StartedAt time.Time
+
+	// Time spent to send request and receive response headers.
+	Duration time.Duration
+}
+
+// HARRecorder is a Recorder that collects requests and responses and
+// exports them in HTTP Archive (HAR) 1.2 format.
+//
+// HAR files can be imported into browser devtools and many other tools,
+// which is handy for investigating failures or sharing them.
+//
+// HARRecorder is safe for concurrent use.
+//
+// Example:
+//
+//	recorder := httpexpect.NewHARRecorder()
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:  "http://example.com",
+//	    Reporter: httpexpect.NewAssertReporter(t),
+//	    Recorder: recorder,
+//	})
+//
+//	defer func() {
+//	    if t.Failed() {
+//	        _ = recorder.WriteFile("failed.har")
+//	    }
+//	}()
+type HARRecorder struct {
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+// NewHARRecorder returns a new empty HARRecorder.
+func NewHARRecorder() *HARRecorder {
+	return &HARRecorder{}
+}
+
+// Record implements Recorder.Record.
+func (h *HARRecorder) Record(exchange *RecordedExchange) {
+	entry := newHAREntry(exchange)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = append(h.entries, entry)
+}
+
+// Len returns number of recorded entries.
+func (h *HARRecorder) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.entries)
+}
+
+// Reset removes all recorded entries.
+func (h *HARRecorder) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = nil
+}
+
+// MarshalJSON returns recorded entries as HAR 1.2 JSON document.
+func (h *HARRecorder) MarshalJSON() ([]byte, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := h.entries
+	if entries == nil {
+		entries = []harEntry{}
+	}
+
+	return json.Marshal(harDocument{
+		Log: harLog{
+			Version: "1.2",
+			Creator: harCreator{
+				Name:    "httpexpect",
+				Version: "2",
+			},
+			Entries: entries,
+		},
+	})
+}
+
+// WriteTo writes HAR 1.2 JSON document to given writer.
+func (h *HARRecorder) WriteTo(w io.Writer) (int64, error) {
+	b, err := h.MarshalJSON()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+// WriteFile writes HAR 1.2 JSON document to given file.
+func (h *HARRecorder) WriteFile(path string) error {
+	b, err := h.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harCookie    `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harCookie    `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+func newHAREntry(exchange *RecordedExchange) harEntry {
+	ms := float64(exchange.Duration) / float64(time.Millisecond)
+
+	entry := harEntry{
+		StartedDateTime: exchange.StartedAt.Format("2006-01-02T15:04:05.000Z07:00"),
+		Time:            ms,
+		Timings: harTimings{
+			Wait: ms,
+		},
+	}
+
+	if req := exchange.Request; req != nil {
+		entry.Request = harRequest{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			HTTPVersion: harProto(req.Proto),
+			Cookies:     harCookies(req.Cookies()),
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(exchange.RequestBody),
+		}
+
+		query := req.URL.Query()
+		for _, k := range harSortedKeys(query) {
+			for _, v := range query[k] {
+				entry.Request.QueryString = append(entry.Request.QueryString,
+					harNameValue{Name: k, Value: v})
+			}
+		}
+
+		if exchange.RequestBody != nil {
+			text, encoding := harText(exchange.RequestBody)
+			entry.Request.PostData = &harPostData{
+				MimeType: req.Header.Get("Content-Type"),
+				Text:     text,
+				Encoding: encoding,
+			}
+		}
+	}
+
+	if resp := exchange.Response; resp != nil {
+		text, encoding := harText(exchange.ResponseBody)
+
+		entry.Response = harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: harProto(resp.Proto),
+			Cookies:     harCookies(resp.Cookies()),
+			Headers:     harHeaders(resp.Header),
+			Content: harContent{
+				Size:     len(exchange.ResponseBody),
+				MimeType: resp.Header.Get("Content-Type"),
+				Text:     text,
+				Encoding: encoding,
+			},
+			RedirectURL: resp.Header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    len(exchange.ResponseBody),
+		}
+	}
+
+	return entry
+}
+
+func harProto(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+func harHeaders(header http.Header) []harNameValue {
+	ret := []harNameValue{}
+
+	for _, k := range harSortedKeys(header) {
+		for _, v := range header[k] {
+			ret = append(ret, harNameValue{Name: k, Value: v})
+		}
+	}
+
+	return ret
+}
+
+func harCookies(cookies []*http.Cookie) []harCookie {
+	ret := []harCookie{}
+
+	for _, c := range cookies {
+		hc := harCookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Domain:   c.Domain,
+			HTTPOnly: c.HttpOnly,
+			Secure:   c.Secure,
+		}
+		if !c.Expires.IsZero() {
+			hc.Expires = c.Expires.Format(time.RFC3339)
+		}
+		ret = append(ret, hc)
+	}
+
+	return ret
+}
+
+// binary content is base64-encoded, as permitted by HAR spec
+func harText(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), "base64"
+}
+
+func harSortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
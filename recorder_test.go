@@ -0,0 +1,172 @@
+package httpexpect
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorderHAR(t *testing.T) {
+	recorder := NewHARRecorder()
+
+	config := Config{
+		BaseURL:  "http://example.com",
+		Client:   &mockClient{},
+		Reporter: newMockReporter(t),
+		Recorder: recorder,
+	}
+
+	e := WithConfig(config)
+
+	e.POST("/path").
+		WithQuery("b", 2).
+		WithQuery("a", 1).
+		WithCookie("session", "123").
+		WithJSON(map[string]interface{}{"foo": "bar"}).
+		Expect().
+		chain.assertOK(t)
+
+	e.PUT("/binary").
+		WithBytes([]byte{0xff, 0xfe}).
+		Expect().
+		chain.assertOK(t)
+
+	e.GET("/empty").
+		Expect().
+		chain.assertOK(t)
+
+	require.Equal(t, 3, recorder.Len())
+
+	var buf bytes.Buffer
+	_, err := recorder.WriteTo(&buf)
+	require.NoError(t, err)
+
+	var har struct {
+		Log struct {
+			Version string `json:"version"`
+			Creator struct {
+				Name string `json:"name"`
+			} `json:"creator"`
+			Entries []struct {
+				StartedDateTime string  `json:"startedDateTime"`
+				Time            float64 `json:"time"`
+				Request         struct {
+					Method      string `json:"method"`
+					URL         string `json:"url"`
+					HTTPVersion string `json:"httpVersion"`
+					Cookies     []struct {
+						Name  string `json:"name"`
+						Value string `json:"value"`
+					} `json:"cookies"`
+					QueryString []struct {
+						Name  string `json:"name"`
+						Value string `json:"value"`
+					} `json:"queryString"`
+					PostData *struct {
+						MimeType string `json:"mimeType"`
+						Text     string `json:"text"`
+						Encoding string `json:"encoding"`
+					} `json:"postData"`
+					BodySize int `json:"bodySize"`
+				} `json:"request"`
+				Response struct {
+					Status  int `json:"status"`
+					Content struct {
+						Size     int    `json:"size"`
+						MimeType string `json:"mimeType"`
+						Text     string `json:"text"`
+						Encoding string `json:"encoding"`
+					} `json:"content"`
+				} `json:"response"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &har))
+
+	assert.Equal(t, "1.2", har.Log.Version)
+	assert.Equal(t, "httpexpect", har.Log.Creator.Name)
+	require.Equal(t, 3, len(har.Log.Entries))
+
+	entry := har.Log.Entries[0]
+
+	_, err = time.Parse(time.RFC3339, entry.StartedDateTime)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "POST", entry.Request.Method)
+	assert.Equal(t, "http://example.com/path?a=1&b=2", entry.Request.URL)
+	assert.Equal(t, "HTTP/1.1", entry.Request.HTTPVersion)
+	require.Equal(t, 1, len(entry.Request.Cookies))
+	assert.Equal(t, "session", entry.Request.Cookies[0].Name)
+	require.Equal(t, 2, len(entry.Request.QueryString))
+	assert.Equal(t, "a", entry.Request.QueryString[0].Name)
+	assert.Equal(t, "b", entry.Request.QueryString[1].Name)
+	require.NotNil(t, entry.Request.PostData)
+	assert.Equal(t, "application/json; charset=utf-8", entry.Request.PostData.MimeType)
+	assert.Equal(t, `{"foo":"bar"}`, entry.Request.PostData.Text)
+	assert.Equal(t, 13, entry.Request.BodySize)
+	assert.Equal(t, `{"foo":"bar"}`, entry.Response.Content.Text)
+	assert.Equal(t, 13, entry.Response.Content.Size)
+
+	entry = har.Log.Entries[1]
+
+	require.NotNil(t, entry.Request.PostData)
+	assert.Equal(t, "base64", entry.Request.PostData.Encoding)
+	assert.Equal(t, "//4=", entry.Request.PostData.Text)
+	assert.Equal(t, "base64", entry.Response.Content.Encoding)
+
+	entry = har.Log.Entries[2]
+
+	assert.Nil(t, entry.Request.PostData)
+	assert.Equal(t, 0, entry.Request.BodySize)
+
+	recorder.Reset()
+	assert.Equal(t, 0, recorder.Len())
+
+	b, err := recorder.MarshalJSON()
+	require.NoError(t, err)
+	assert.True(t, strings.Contains(string(b), `"entries":[]`))
+}
+
+func TestRecorderHARWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	recorder := NewHARRecorder()
+	recorder.Record(&RecordedExchange{
+		Request: &http.Request{
+			Method: "GET",
+			URL:    mustParseRequestURL(t, "http://example.com"),
+			Header: http.Header{},
+		},
+		Response: &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+		},
+		StartedAt: time.Now(),
+	})
+
+	path := filepath.Join(dir, "test.har")
+	require.NoError(t, recorder.WriteFile(path))
+
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, json.Valid(b))
+}
+
+func mustParseRequestURL(t *testing.T, s string) *url.URL {
+	u, err := url.Parse(s)
+	require.NoError(t, err)
+	return u
+}
@@ -0,0 +1,525 @@
+package httpexpect
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReportHandler implements AssertionHandler by collecting assertions
+// grouped by test name, and generating JUnit XML and Allure reports.
+//
+// Every test (AssertionContext.TestName) becomes a test case. Test case
+// is failed if it has at least one fatal failure. For every failure,
+// report includes formatted failure message and a dump of request and
+// response being matched, if any.
+//
+// If Handler is set, every assertion is also passed to it after it was
+// collected; usually it's DefaultAssertionHandler, so that failures are
+// still reported to the test.
+//
+// ReportHandler is usually shared by all tests, and every test wraps its
+// own handler using Wrap. Reports are written by WriteJUnit,
+// WriteJUnitFile, and WriteAllure after all tests finished, e.g. from
+// TestMain.
+//
+// ReportHandler is safe for concurrent use.
+//
+// Example:
+//
+//	var report = &httpexpect.ReportHandler{SuiteName: "api"}
+//
+//	func TestMain(m *testing.M) {
+//	    code := m.Run()
+//	    _ = report.WriteJUnitFile("junit.xml")
+//	    _ = report.WriteAllure("allure-results")
+//	    os.Exit(code)
+//	}
+//
+//	func TestUsers(t *testing.T) {
+//	    e := httpexpect.WithConfig(httpexpect.Config{
+//	        BaseURL:  server.URL,
+//	        TestName: t.Name(),
+//	        AssertionHandler: report.Wrap(&httpexpect.DefaultAssertionHandler{
+//	            Formatter: &httpexpect.DefaultFormatter{},
+//	            Reporter:  httpexpect.NewAssertReporter(t),
+//	        }),
+//	    })
+//
+//	    e.GET("/users").Expect().Status(http.StatusOK)
+//	}
+type ReportHandler struct {
+	// Name of test suite in reports. Default is "httpexpect".
+	SuiteName string
+
+	// Formatter used to format failure messages.
+	// If nil, DefaultFormatter is used.
+	Formatter Formatter
+
+	// Handler receives every assertion after it was collected. Optional.
+	Handler AssertionHandler
+
+	mu    sync.Mutex
+	tests []*reportTest
+}
+
+type reportTest struct {
+	name     string
+	start    time.Time
+	stop     time.Time
+	count    int
+	failures []reportFailure
+}
+
+type reportFailure struct {
+	message string
+	text    string
+	dump    string
+}
+
+// Success implements AssertionHandler.Success.
+func (h *ReportHandler) Success(ctx *AssertionContext) {
+	h.collect(ctx, nil)
+
+	if h.Handler != nil {
+		h.Handler.Success(ctx)
+	}
+}
+
+// Failure implements AssertionHandler.Failure.
+func (h *ReportHandler) Failure(
+	ctx *AssertionContext, failure *AssertionFailure,
+) {
+	h.collect(ctx, failure)
+
+	if h.Handler != nil {
+		h.Handler.Failure(ctx, failure)
+	}
+}
+
+// Wrap returns AssertionHandler that collects assertions into h, and then
+// passes them to given handler instead of h.Handler.
+//
+// handler may be nil.
+func (h *ReportHandler) Wrap(handler AssertionHandler) AssertionHandler {
+	return &reportWrapper{h, handler}
+}
+
+type reportWrapper struct {
+	report  *ReportHandler
+	handler AssertionHandler
+}
+
+func (w *reportWrapper) Success(ctx *AssertionContext) {
+	w.report.collect(ctx, nil)
+
+	if w.handler != nil {
+		w.handler.Success(ctx)
+	}
+}
+
+func (w *reportWrapper) Failure(ctx *AssertionContext, failure *AssertionFailure) {
+	w.report.collect(ctx, failure)
+
+	if w.handler != nil {
+		w.handler.Failure(ctx, failure)
+	}
+}
+
+// Reset removes all collected assertions.
+func (h *ReportHandler) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.tests = nil
+}
+
+func (h *ReportHandler) collect(ctx *AssertionContext, failure *AssertionFailure) {
+	var f *reportFailure
+
+	if failure != nil && failure.IsFatal {
+		formatter := h.Formatter
+		if formatter == nil {
+			formatter = &DefaultFormatter{}
+		}
+
+		f = &reportFailure{
+			message: failure.Type.String(),
+			text:    formatter.FormatFailure(ctx, failure),
+			dump:    dumpAssertionExchange(ctx),
+		}
+
+		if len(failure.Errors) != 0 && failure.Errors[0] != nil {
+			f.message = failure.Errors[0].Error()
+		}
+	}
+
+	name := ctx.TestName
+	if name == "" {
+		name = "unnamed"
+	}
+
+	now := time.Now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var test *reportTest
+	for _, t := range h.tests {
+		if t.name == name {
+			test = t
+			break
+		}
+	}
+
+	if test == nil {
+		test = &reportTest{name: name, start: now}
+		h.tests = append(h.tests, test)
+	}
+
+	test.stop = now
+	test.count++
+
+	if f != nil {
+		test.failures = append(test.failures, *f)
+	}
+}
+
+func (h *ReportHandler) snapshot() (string, []reportTest) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	suite := h.SuiteName
+	if suite == "" {
+		suite = "httpexpect"
+	}
+
+	tests := make([]reportTest, 0, len(h.tests))
+	for _, t := range h.tests {
+		test := *t
+		test.failures = append([]reportFailure(nil), t.failures...)
+		tests = append(tests, test)
+	}
+
+	return suite, tests
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string        `xml:"name,attr"`
+	Classname  string        `xml:"classname,attr"`
+	Assertions int           `xml:"assertions,attr"`
+	Time       string        `xml:"time,attr"`
+	Failure    *junitFailure `xml:"failure,omitempty"`
+	SystemOut  string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes collected assertions as JUnit XML report.
+//
+// Request and response dumps of failures are written to system-out
+// element of test case.
+func (h *ReportHandler) WriteJUnit(w io.Writer) error {
+	suiteName, tests := h.snapshot()
+
+	suite := junitTestSuite{
+		Name:  suiteName,
+		Tests: len(tests),
+		Cases: []junitTestCase{},
+	}
+
+	var start, stop time.Time
+
+	for _, test := range tests {
+		tc := junitTestCase{
+			Name:       test.name,
+			Classname:  suiteName,
+			Assertions: test.count,
+			Time:       formatReportSeconds(test.stop.Sub(test.start)),
+		}
+
+		// JUnit schema allows single failure per test case, so
+		// all failures of the test are merged into one
+		if len(test.failures) != 0 {
+			var texts, dumps []string
+			for _, f := range test.failures {
+				texts = append(texts, f.text)
+				if f.dump != "" {
+					dumps = append(dumps, f.dump)
+				}
+			}
+
+			tc.Failure = &junitFailure{
+				Message: test.failures[0].message,
+				Type:    "AssertionFailure",
+				Text:    strings.Join(texts, "\n\n"),
+			}
+			tc.SystemOut = strings.Join(dumps, "\n\n")
+
+			suite.Failures++
+		}
+
+		if start.IsZero() || test.start.Before(start) {
+			start = test.start
+		}
+		if test.stop.After(stop) {
+			stop = test.stop
+		}
+
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	suite.Time = formatReportSeconds(stop.Sub(start))
+	if !start.IsZero() {
+		suite.Timestamp = start.UTC().Format("2006-01-02T15:04:05")
+	}
+
+	b, err := xml.MarshalIndent(junitTestSuites{
+		Suites: []junitTestSuite{suite},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// WriteJUnitFile writes collected assertions as JUnit XML report to
+// given file.
+func (h *ReportHandler) WriteJUnitFile(path string) error {
+	var buf bytes.Buffer
+
+	if err := h.WriteJUnit(&buf); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+type allureResult struct {
+	UUID          string             `json:"uuid"`
+	HistoryID     string             `json:"historyId"`
+	Name          string             `json:"name"`
+	FullName      string             `json:"fullName"`
+	Status        string             `json:"status"`
+	StatusDetails *allureDetails     `json:"statusDetails,omitempty"`
+	Stage         string             `json:"stage"`
+	Start         int64              `json:"start"`
+	Stop          int64              `json:"stop"`
+	Labels        []allureLabel      `json:"labels"`
+	Attachments   []allureAttachment `json:"attachments"`
+}
+
+type allureDetails struct {
+	Message string `json:"message"`
+	Trace   string `json:"trace"`
+}
+
+type allureLabel struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type allureAttachment struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+}
+
+// WriteAllure writes collected assertions as Allure results to given
+// directory, creating it if necessary.
+//
+// For every test, "<uuid>-result.json" file is written, and for every
+// failure with request or response, "<uuid>-attachment.txt" file with
+// their dump is written and attached to the result.
+func (h *ReportHandler) WriteAllure(dir string) error {
+	suiteName, tests := h.snapshot()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, test := range tests {
+		fullName := suiteName + "." + test.name
+		history := md5.Sum([]byte(fullName))
+
+		result := allureResult{
+			UUID:      newReportUUID(),
+			HistoryID: hex.EncodeToString(history[:]),
+			Name:      test.name,
+			FullName:  fullName,
+			Status:    "passed",
+			Stage:     "finished",
+			Start:     test.start.UnixNano() / int64(time.Millisecond),
+			Stop:      test.stop.UnixNano() / int64(time.Millisecond),
+			Labels: []allureLabel{
+				{Name: "suite", Value: suiteName},
+				{Name: "framework", Value: "httpexpect"},
+				{Name: "language", Value: "go"},
+			},
+			Attachments: []allureAttachment{},
+		}
+
+		if len(test.failures) != 0 {
+			var texts []string
+			for _, f := range test.failures {
+				texts = append(texts, f.text)
+			}
+
+			result.Status = "failed"
+			result.StatusDetails = &allureDetails{
+				Message: test.failures[0].message,
+				Trace:   strings.Join(texts, "\n\n"),
+			}
+		}
+
+		for n, f := range test.failures {
+			if f.dump == "" {
+				continue
+			}
+
+			source := newReportUUID() + "-attachment.txt"
+
+			err := ioutil.WriteFile(filepath.Join(dir, source), []byte(f.dump), 0644)
+			if err != nil {
+				return err
+			}
+
+			result.Attachments = append(result.Attachments, allureAttachment{
+				Name:   fmt.Sprintf("failure #%d: request and response", n+1),
+				Source: source,
+				Type:   "text/plain",
+			})
+		}
+
+		b, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		err = ioutil.WriteFile(
+			filepath.Join(dir, result.UUID+"-result.json"), b, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatReportSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func newReportUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	s := hex.EncodeToString(b[:])
+
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// dumps request and response of assertion context in HTTP/1.x format,
+// with headers sanitized using Config.Sanitizers
+func dumpAssertionExchange(ctx *AssertionContext) string {
+	var buf bytes.Buffer
+
+	if ctx.Request != nil && ctx.Request.httpReq != nil {
+		req := ctx.Request.httpReq
+
+		fmt.Fprintf(&buf, "%s %s\n", req.Method, req.URL.String())
+		writeReportHeader(&buf,
+			sanitizeHeader(ctx.Request.config.Sanitizers, req.Header))
+
+		if body := ctx.Request.requestBody(); len(body) != 0 {
+			buf.WriteString("\n")
+			buf.Write(sanitizeBody(ctx.Request.config.Sanitizers,
+				req.Header.Get("Content-Type"), body))
+			buf.WriteString("\n")
+		}
+	}
+
+	if ctx.Response != nil && ctx.Response.httpResp != nil {
+		resp := ctx.Response.httpResp
+
+		if buf.Len() != 0 {
+			buf.WriteString("\n")
+		}
+
+		proto := resp.Proto
+		if proto == "" {
+			proto = "HTTP/1.1"
+		}
+
+		fmt.Fprintf(&buf, "%s %s\n", proto, statusCodeText(resp.StatusCode))
+		writeReportHeader(&buf, resp.Header)
+
+		if len(ctx.Response.content) != 0 {
+			buf.WriteString("\n")
+			buf.Write(ctx.Response.content)
+			buf.WriteString("\n")
+		}
+	}
+
+	return buf.String()
+}
+
+func writeReportHeader(buf *bytes.Buffer, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		for _, v := range header[k] {
+			fmt.Fprintf(buf, "%s: %s\n", k, v)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package httpexpect
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReportTestExpect(t *testing.T, name string, handler AssertionHandler) *Expect {
+	return WithConfig(Config{
+		BaseURL:          "http://example.com",
+		TestName:         name,
+		AssertionHandler: handler,
+		Sanitizers:       []Sanitizer{MaskHeaders("Authorization")},
+		Client: &http.Client{
+			Transport: NewBinder(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("Content-Type", "text/plain")
+					_, _ = w.Write([]byte("hello"))
+				})),
+		},
+	})
+}
+
+func runReportTests(t *testing.T, report *ReportHandler) *mockAssertionHandler {
+	next := &mockAssertionHandler{}
+
+	e := newReportTestExpect(t, "TestOK", report.Wrap(next))
+	e.GET("/ok").Expect().Status(http.StatusOK)
+
+	e = newReportTestExpect(t, "TestFail", report.Wrap(next))
+	e.POST("/fail").
+		WithHeader("Authorization", "Bearer secret").
+		WithText("request body").
+		Expect().
+		Status(http.StatusCreated)
+
+	return next
+}
+
+func TestReportHandlerCollect(t *testing.T) {
+	report := &ReportHandler{}
+	next := &mockAssertionHandler{}
+
+	report.Handler = next
+
+	report.Success(&AssertionContext{TestName: "TestA"})
+	report.Failure(&AssertionContext{TestName: "TestA"}, &AssertionFailure{
+		Type:    AssertValid,
+		IsFatal: false,
+	})
+	report.Failure(&AssertionContext{}, &AssertionFailure{
+		Type:    AssertValid,
+		IsFatal: true,
+	})
+
+	assert.NotNil(t, next.failure)
+
+	_, tests := report.snapshot()
+	require.Len(t, tests, 2)
+
+	assert.Equal(t, "TestA", tests[0].name)
+	assert.Equal(t, 2, tests[0].count)
+	assert.Empty(t, tests[0].failures)
+
+	assert.Equal(t, "unnamed", tests[1].name)
+	require.Len(t, tests[1].failures, 1)
+	assert.Equal(t, "AssertValid", tests[1].failures[0].message)
+	assert.Empty(t, tests[1].failures[0].dump)
+
+	report.Reset()
+
+	_, tests = report.snapshot()
+	assert.Empty(t, tests)
+}
+
+func TestReportHandlerJUnit(t *testing.T) {
+	report := &ReportHandler{SuiteName: "api"}
+
+	next := runReportTests(t, report)
+	assert.NotNil(t, next.failure)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJUnit(&buf))
+
+	assert.True(t, strings.HasPrefix(buf.String(), xml.Header))
+
+	var doc junitTestSuites
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+
+	require.Len(t, doc.Suites, 1)
+	suite := doc.Suites[0]
+
+	assert.Equal(t, "api", suite.Name)
+	assert.Equal(t, 2, suite.Tests)
+	assert.Equal(t, 1, suite.Failures)
+	assert.NotEmpty(t, suite.Timestamp)
+
+	require.Len(t, suite.Cases, 2)
+
+	assert.Equal(t, "TestOK", suite.Cases[0].Name)
+	assert.Equal(t, "api", suite.Cases[0].Classname)
+	assert.NotZero(t, suite.Cases[0].Assertions)
+	assert.Nil(t, suite.Cases[0].Failure)
+	assert.Empty(t, suite.Cases[0].SystemOut)
+
+	assert.Equal(t, "TestFail", suite.Cases[1].Name)
+	require.NotNil(t, suite.Cases[1].Failure)
+	assert.Equal(t, "unexpected http status value", suite.Cases[1].Failure.Message)
+	assert.Contains(t, suite.Cases[1].Failure.Text, "201 Created")
+
+	out := suite.Cases[1].SystemOut
+	assert.Contains(t, out, "POST http://example.com/fail")
+	assert.Contains(t, out, "request body")
+	assert.Contains(t, out, "200 OK")
+	assert.Contains(t, out, "hello")
+	assert.NotContains(t, out, "secret")
+
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "reports", "junit.xml")
+	require.NoError(t, report.WriteJUnitFile(path))
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, buf.String(), string(data))
+}
+
+func TestReportHandlerAllure(t *testing.T) {
+	report := &ReportHandler{}
+
+	runReportTests(t, report)
+
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, report.WriteAllure(filepath.Join(dir, "allure")))
+
+	files, err := filepath.Glob(filepath.Join(dir, "allure", "*-result.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	results := map[string]allureResult{}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+
+		var result allureResult
+		require.NoError(t, json.Unmarshal(data, &result))
+
+		assert.Equal(t, result.UUID+"-result.json", filepath.Base(file))
+		assert.Regexp(t,
+			regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-`+
+				`[0-9a-f]{12}$`), result.UUID)
+
+		results[result.Name] = result
+	}
+
+	ok := results["TestOK"]
+	assert.Equal(t, "passed", ok.Status)
+	assert.Equal(t, "finished", ok.Stage)
+	assert.Equal(t, "httpexpect.TestOK", ok.FullName)
+	assert.Len(t, ok.HistoryID, 32)
+	assert.Nil(t, ok.StatusDetails)
+	assert.Empty(t, ok.Attachments)
+	assert.LessOrEqual(t, ok.Start, ok.Stop)
+
+	fail := results["TestFail"]
+	assert.Equal(t, "failed", fail.Status)
+	require.NotNil(t, fail.StatusDetails)
+	assert.Equal(t, "unexpected http status value", fail.StatusDetails.Message)
+	assert.Contains(t, fail.StatusDetails.Trace, "201 Created")
+
+	require.Len(t, fail.Attachments, 1)
+	assert.Equal(t, "text/plain", fail.Attachments[0].Type)
+
+	data, err := ioutil.ReadFile(
+		filepath.Join(dir, "allure", fail.Attachments[0].Source))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "POST http://example.com/fail")
+	assert.Contains(t, string(data), "hello")
+}
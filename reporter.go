@@ -2,6 +2,8 @@ package httpexpect
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -38,3 +40,189 @@ func NewRequireReporter(t require.TestingT) *RequireReporter {
 func (r *RequireReporter) Errorf(message string, args ...interface{}) {
 	r.backend.FailNow(fmt.Sprintf(message, args...))
 }
+
+// AggregateReporter implements Reporter interface by collecting failures
+// and reporting them all at once when test finishes. Failures are non-fatal
+// with this reporter.
+//
+// If test implements Cleanup(func()), like *testing.T does, collected
+// failures are reported automatically from cleanup function. Otherwise,
+// Flush should be called manually.
+//
+// AggregateReporter is safe for concurrent use.
+type AggregateReporter struct {
+	t        TestingTB
+	mu       sync.Mutex
+	messages []string
+}
+
+// NewAggregateReporter returns a new AggregateReporter object.
+func NewAggregateReporter(t TestingTB) *AggregateReporter {
+	r := &AggregateReporter{t: t}
+
+	if c, ok := t.(interface{ Cleanup(func()) }); ok {
+		c.Cleanup(r.Flush)
+	}
+
+	return r
+}
+
+// Errorf implements Reporter.Errorf.
+func (r *AggregateReporter) Errorf(message string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages = append(r.messages, fmt.Sprintf(message, args...))
+}
+
+// Flush reports collected failures to test and forgets them.
+func (r *AggregateReporter) Flush() {
+	r.mu.Lock()
+	messages := r.messages
+	r.messages = nil
+	r.mu.Unlock()
+
+	for _, msg := range messages {
+		r.t.Errorf("%s", msg)
+	}
+}
+
+// GinkgoReporter implements Reporter interface using Ginkgo or Gomega fail
+// handler, e.g. ginkgo.Fail. Failures are fatal with this reporter, since
+// ginkgo.Fail aborts current spec.
+//
+// Example:
+//
+//	var _ = Describe("API", func() {
+//	    It("returns users", func() {
+//	        e := httpexpect.WithConfig(httpexpect.Config{
+//	            BaseURL:  server.URL,
+//	            Reporter: httpexpect.NewGinkgoReporter(ginkgo.Fail),
+//	        })
+//	        e.GET("/users").Expect().Status(http.StatusOK)
+//	    })
+//	})
+type GinkgoReporter struct {
+	fail func(message string, callerSkip ...int)
+}
+
+// NewGinkgoReporter returns a new GinkgoReporter object.
+//
+// fail should have the signature of ginkgo.Fail and gomega.OmegaFailHandler.
+func NewGinkgoReporter(fail func(message string, callerSkip ...int)) *GinkgoReporter {
+	if fail == nil {
+		panic("fail is nil")
+	}
+	return &GinkgoReporter{fail}
+}
+
+// Errorf implements Reporter.Errorf.
+func (r *GinkgoReporter) Errorf(message string, args ...interface{}) {
+	r.fail(fmt.Sprintf(message, args...))
+}
+
+// ErrorReporter implements Reporter interface by collecting failures into
+// a list of errors. Failures are non-fatal with this reporter.
+//
+// ErrorReporter allows to use httpexpect outside of tests, e.g. for smoke
+// checks of production deployment, where failures should be returned as
+// an error instead of failing a test.
+//
+// ErrorReporter is safe for concurrent use.
+//
+// Example:
+//
+//	func smokeCheck(baseURL string) error {
+//	    reporter := httpexpect.NewErrorReporter()
+//
+//	    e := httpexpect.WithConfig(httpexpect.Config{
+//	        BaseURL:  baseURL,
+//	        Reporter: reporter,
+//	    })
+//	    e.GET("/health").Expect().Status(http.StatusOK)
+//
+//	    return reporter.Err()
+//	}
+type ErrorReporter struct {
+	mu     sync.Mutex
+	errors []error
+}
+
+// NewErrorReporter returns a new ErrorReporter object.
+func NewErrorReporter() *ErrorReporter {
+	return &ErrorReporter{}
+}
+
+// Errorf implements Reporter.Errorf.
+func (r *ErrorReporter) Errorf(message string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errors = append(r.errors, fmt.Errorf(message, args...))
+}
+
+// Errors returns a copy of collected failures, in order of reporting.
+func (r *ErrorReporter) Errors() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]error(nil), r.errors...)
+}
+
+// Err returns an error that combines messages of all collected failures,
+// or nil if there were no failures.
+func (r *ErrorReporter) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch len(r.errors) {
+	case 0:
+		return nil
+	case 1:
+		return r.errors[0]
+	}
+
+	messages := make([]string, 0, len(r.errors))
+	for _, err := range r.errors {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Errorf("%d failures:\n\n%s",
+		len(r.errors), strings.Join(messages, "\n\n"))
+}
+
+// Reset forgets collected failures.
+func (r *ErrorReporter) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errors = nil
+}
+
+// PanicReporter implements Reporter interface by panicking with an error.
+// Failures are fatal with this reporter.
+//
+// PanicReporter is useful for standalone binaries and scripts, where there
+// is no testing framework. Panic can be recovered and the error can be
+// inspected; otherwise it terminates the program with the failure message.
+//
+// Example:
+//
+//	func main() {
+//	    e := httpexpect.WithConfig(httpexpect.Config{
+//	        BaseURL:  "https://example.com",
+//	        Reporter: httpexpect.NewPanicReporter(),
+//	    })
+//	    e.GET("/health").Expect().Status(http.StatusOK)
+//	}
+type PanicReporter struct{}
+
+// NewPanicReporter returns a new PanicReporter object.
+func NewPanicReporter() *PanicReporter {
+	return &PanicReporter{}
+}
+
+// Errorf implements Reporter.Errorf.
+func (r *PanicReporter) Errorf(message string, args ...interface{}) {
+	panic(fmt.Errorf(message, args...))
+}
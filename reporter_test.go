@@ -0,0 +1,104 @@
+package httpexpect
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporterGinkgo(t *testing.T) {
+	var messages []string
+
+	reporter := NewGinkgoReporter(func(message string, callerSkip ...int) {
+		messages = append(messages, message)
+	})
+
+	reporter.Errorf("foo %d", 123)
+	reporter.Errorf("bar")
+
+	assert.Equal(t, []string{"foo 123", "bar"}, messages)
+
+	assert.Panics(t, func() {
+		NewGinkgoReporter(nil)
+	})
+}
+
+func TestReporterError(t *testing.T) {
+	reporter := NewErrorReporter()
+
+	assert.NoError(t, reporter.Err())
+	assert.Empty(t, reporter.Errors())
+
+	reporter.Errorf("foo %d", 123)
+
+	require.Error(t, reporter.Err())
+	assert.Equal(t, "foo 123", reporter.Err().Error())
+
+	reporter.Errorf("bar")
+
+	require.Len(t, reporter.Errors(), 2)
+	assert.Equal(t, "foo 123", reporter.Errors()[0].Error())
+	assert.Equal(t, "bar", reporter.Errors()[1].Error())
+	assert.Equal(t, "2 failures:\n\nfoo 123\n\nbar", reporter.Err().Error())
+
+	reporter.Reset()
+
+	assert.NoError(t, reporter.Err())
+	assert.Empty(t, reporter.Errors())
+
+	t.Run("concurrent", func(t *testing.T) {
+		reporter := NewErrorReporter()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				reporter.Errorf("failure")
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, reporter.Errors(), 10)
+	})
+
+	t.Run("expect", func(t *testing.T) {
+		reporter := NewErrorReporter()
+
+		e := WithConfig(Config{
+			Reporter: reporter,
+			Client: &http.Client{
+				Transport: NewBinder(http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						w.WriteHeader(http.StatusServiceUnavailable)
+					})),
+			},
+		})
+
+		e.GET("/health").Expect().Status(http.StatusOK)
+		e.GET("/ready").Expect().Status(http.StatusServiceUnavailable)
+
+		require.Len(t, reporter.Errors(), 1)
+		assert.Contains(t, reporter.Err().Error(), "503 Service Unavailable")
+	})
+}
+
+func TestReporterPanic(t *testing.T) {
+	reporter := NewPanicReporter()
+
+	defer func() {
+		r := recover()
+		require.NotNil(t, r)
+
+		err, ok := r.(error)
+		require.True(t, ok)
+		assert.Equal(t, "foo 123", err.Error())
+	}()
+
+	reporter.Errorf("foo %d", 123)
+
+	t.Fatal("expected panic")
+}
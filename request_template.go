@@ -0,0 +1,144 @@
+package httpexpect
+
+// RequestTemplate is a reusable set of request builders.
+//
+// Template packages common request settings, like headers, auth, and query
+// defaults, and can be applied to any number of requests using Request.Apply
+// or attached to Expect instance using Expect.Template.
+//
+// RequestTemplate is immutable: every method returns a new template and
+// never modifies the original one. This allows to derive templates from
+// each other and share them between tests without leaking state.
+//
+// Example:
+//
+//	base := httpexpect.NewRequestTemplate().
+//	    WithHeader("Accept", "application/json").
+//	    WithQuery("version", 2)
+//
+//	admin := base.WithBasicAuth("admin", "secret")
+//
+//	e.GET("/users").Apply(base).
+//	    Expect().
+//	    Status(http.StatusOK)
+//
+//	e.DELETE("/users/{id}", 1).Apply(admin).
+//	    Expect().
+//	    Status(http.StatusNoContent)
+type RequestTemplate struct {
+	builders []func(*Request)
+}
+
+// NewRequestTemplate returns a new RequestTemplate with given builders.
+// Builders are invoked in the same order when the template is applied.
+func NewRequestTemplate(builders ...func(*Request)) *RequestTemplate {
+	t := &RequestTemplate{}
+
+	for _, builder := range builders {
+		if builder != nil {
+			t.builders = append(t.builders, builder)
+		}
+	}
+
+	return t
+}
+
+// Clone returns a copy of template.
+func (t *RequestTemplate) Clone() *RequestTemplate {
+	ret := &RequestTemplate{}
+	ret.builders = append(ret.builders, t.builders...)
+
+	return ret
+}
+
+// Builder returns a copy of template with given builder attached to it.
+// Returned copy contains all previously attached builders plus a new one.
+//
+// Example:
+//
+//	tmpl := httpexpect.NewRequestTemplate().
+//	    Builder(func(req *httpexpect.Request) {
+//	        req.WithHeader("Authorization", "Bearer "+token)
+//	    })
+func (t *RequestTemplate) Builder(builder func(*Request)) *RequestTemplate {
+	ret := t.Clone()
+
+	if builder != nil {
+		ret.builders = append(ret.builders, builder)
+	}
+
+	return ret
+}
+
+// WithHeader returns a copy of template that adds given header to request.
+// See Request.WithHeader.
+func (t *RequestTemplate) WithHeader(k, v string) *RequestTemplate {
+	return t.Builder(func(req *Request) {
+		req.WithHeader(k, v)
+	})
+}
+
+// WithHeaders returns a copy of template that adds given headers to request.
+// See Request.WithHeaders.
+func (t *RequestTemplate) WithHeaders(headers map[string]string) *RequestTemplate {
+	headersCopy := make(map[string]string, len(headers))
+	for k, v := range headers {
+		headersCopy[k] = v
+	}
+
+	return t.Builder(func(req *Request) {
+		req.WithHeaders(headersCopy)
+	})
+}
+
+// WithCookie returns a copy of template that adds given cookie to request.
+// See Request.WithCookie.
+func (t *RequestTemplate) WithCookie(k, v string) *RequestTemplate {
+	return t.Builder(func(req *Request) {
+		req.WithCookie(k, v)
+	})
+}
+
+// WithQuery returns a copy of template that adds given query parameter
+// to request. See Request.WithQuery.
+func (t *RequestTemplate) WithQuery(key string, value interface{}) *RequestTemplate {
+	return t.Builder(func(req *Request) {
+		req.WithQuery(key, value)
+	})
+}
+
+// WithBasicAuth returns a copy of template that sets Basic Authentication
+// credentials for request. See Request.WithBasicAuth.
+func (t *RequestTemplate) WithBasicAuth(username, password string) *RequestTemplate {
+	return t.Builder(func(req *Request) {
+		req.WithBasicAuth(username, password)
+	})
+}
+
+// WithAuth returns a copy of template that sets credentials provider
+// for request. See Request.WithAuth.
+func (t *RequestTemplate) WithAuth(provider CredentialsProvider) *RequestTemplate {
+	return t.Builder(func(req *Request) {
+		req.WithAuth(provider)
+	})
+}
+
+// Merge returns a copy of template with all builders of other templates
+// appended to it, in order.
+func (t *RequestTemplate) Merge(others ...*RequestTemplate) *RequestTemplate {
+	ret := t.Clone()
+
+	for _, other := range others {
+		if other != nil {
+			ret.builders = append(ret.builders, other.builders...)
+		}
+	}
+
+	return ret
+}
+
+func (t *RequestTemplate) apply(req *Request) {
+	for _, builder := range t.builders {
+		builder(req)
+	}
+}
@@ -0,0 +1,134 @@
+package httpexpect
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestTemplateApply(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	tmpl := NewRequestTemplate().
+		WithHeader("X-Foo", "foo").
+		WithHeaders(map[string]string{"X-Bar": "bar"}).
+		WithCookie("session", "123").
+		WithQuery("version", 2).
+		WithBasicAuth("john", "secret")
+
+	req := NewRequest(config, "GET", "url")
+	req.Apply(tmpl)
+	req.Expect().chain.assertOK(t)
+
+	assert.Equal(t, "foo", client.req.Header.Get("X-Foo"))
+	assert.Equal(t, "bar", client.req.Header.Get("X-Bar"))
+	assert.Equal(t, "version=2", client.req.URL.RawQuery)
+
+	cookie, err := client.req.Cookie("session")
+	assert.NoError(t, err)
+	assert.Equal(t, "123", cookie.Value)
+
+	user, pass, ok := client.req.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "john", user)
+	assert.Equal(t, "secret", pass)
+}
+
+func TestRequestTemplateOrder(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	tmpl1 := NewRequestTemplate().WithHeader("X-Order", "1")
+	tmpl2 := NewRequestTemplate().WithHeader("X-Order", "2")
+	tmpl3 := tmpl1.Merge(tmpl2, nil).WithHeader("X-Order", "3")
+
+	req := NewRequest(config, "GET", "url")
+	req.Apply(tmpl3, tmpl2)
+	req.Expect().chain.assertOK(t)
+
+	assert.Equal(t, []string{"1", "2", "3", "2"}, client.req.Header["X-Order"])
+}
+
+func TestRequestTemplateImmutable(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	reporter := newMockReporter(t)
+
+	headers := map[string]string{"X-Foo": "foo"}
+
+	base := NewRequestTemplate().WithHeaders(headers)
+
+	// Simulate the case when the builders slice has additional capacity,
+	// and check that derived templates don't overwrite each other
+	for i := 0; i < 10; i++ {
+		base = base.Builder(func(r *Request) {})
+	}
+
+	derived1 := base.WithHeader("X-Bar", "1")
+	derived2 := base.WithHeader("X-Baz", "2")
+
+	headers["X-Foo"] = "modified"
+
+	check := func(tmpl *RequestTemplate, expected http.Header) {
+		client := &mockClient{}
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       reporter,
+		}
+
+		NewRequest(config, "GET", "url").
+			Apply(tmpl).
+			Expect().chain.assertOK(t)
+
+		assert.Equal(t, expected, client.req.Header)
+	}
+
+	check(base, http.Header{
+		"X-Foo": {"foo"},
+	})
+	check(derived1, http.Header{
+		"X-Foo": {"foo"},
+		"X-Bar": {"1"},
+	})
+	check(derived2, http.Header{
+		"X-Foo": {"foo"},
+		"X-Baz": {"2"},
+	})
+	check(base.Clone(), http.Header{
+		"X-Foo": {"foo"},
+	})
+}
+
+func TestRequestTemplateNil(t *testing.T) {
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         &mockClient{},
+		Reporter:       newMockReporter(t),
+	}
+
+	req := NewRequest(config, "GET", "url")
+	req.Apply(nil)
+	req.chain.assertFailed(t)
+}
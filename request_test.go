@@ -3,8 +3,11 @@ package httpexpect
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"mime"
 	"mime/multipart"
@@ -12,10 +15,13 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -42,12 +48,18 @@ func TestRequestFailed(t *testing.T) {
 	req.WithHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
 	req.WithContext(context.TODO())
 	req.WithTimeout(0)
+	req.WithDeadline(time.Now())
 	req.WithRedirectPolicy(FollowAllRedirects)
 	req.WithMaxRedirects(1)
 	req.WithRetryPolicy(RetryAllErrors)
 	req.WithMaxRetries(1)
 	req.WithRetryDelay(time.Millisecond, time.Millisecond)
+	req.WithRetryPolicyFunc(func(*http.Response, error) bool { return true })
+	req.WithRetryBackoff(1)
+	req.WithRetryJitter(0.5)
+	req.WithRetryMaxElapsed(time.Second)
 	req.WithWebsocketUpgrade()
+	req.WithWebsocketSubprotocols("foo")
 	req.WithWebsocketDialer(
 		NewWebsocketDialer(
 			http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
@@ -59,19 +71,47 @@ func TestRequestFailed(t *testing.T) {
 	req.WithURL("http://example.com")
 	req.WithHeaders(map[string]string{"foo": "bar"})
 	req.WithHeader("foo", "bar")
+	req.WithRange(0, 1)
+	req.WithCORSPreflight("http://example.com", "PUT")
 	req.WithCookies(map[string]string{"foo": "bar"})
 	req.WithCookie("foo", "bar")
 	req.WithBasicAuth("foo", "bar")
 	req.WithHost("127.0.0.1")
 	req.WithProto("HTTP/1.1")
 	req.WithChunked(strings.NewReader("foo"))
+	req.WithBodyStream(strings.NewReader("foo"))
 	req.WithBytes([]byte("foo"))
 	req.WithText("foo")
 	req.WithJSON(map[string]string{"foo": "bar"})
+	req.WithJSONPatch([]JSONPatchOp{{Op: "remove", Path: "/foo"}})
+	req.WithMergePatch(map[string]string{"foo": "bar"})
+	req.WithXML(struct{}{})
+	req.WithProtobuf(&mockProtoMessage{})
+	req.WithQueryStruct(struct{}{})
+	req.WithQueryOrder(QueryInsertionOrder)
+	req.WithCompression("gzip")
+	assert.Equal(t, "", req.AsCurl())
+	assert.NotNil(t, req.DryRun())
+	httpReq, err := req.Build()
+	assert.Nil(t, httpReq)
+	assert.Error(t, err)
+	req.WithIfNoneMatch("foo")
+	req.WithIfMatch("foo")
+	req.WithIfModifiedSince(time.Now())
+	req.WithIfUnmodifiedSince(time.Now())
+	req.WithProtobufJSON(&mockProtoMessage{})
 	req.WithForm(map[string]string{"foo": "bar"})
 	req.WithFormField("foo", "bar")
 	req.WithFile("foo", "bar", strings.NewReader("baz"))
 	req.WithFileBytes("foo", "bar", []byte("baz"))
+	req.WithFileStream("foo", "bar", strings.NewReader("baz"))
+	req.Apply(NewRequestTemplate())
+	req.WithResponseTransformer(func(*http.Response) {})
+	req.WithResponseTransformerPriority(1, func(*http.Response) {})
+	req.WithOAuth2(StaticTokenSource(&Token{AccessToken: "foo"}))
+	req.WithJWT(map[string]interface{}{"sub": "foo"}, []byte("key"))
+	req.WithSigner(&HMACSigner{Key: []byte("key")})
+	req.WithMultipartField("foo", "bar", "text/plain", nil)
 	req.WithMultipart()
 
 	resp := req.Expect()
@@ -223,6 +263,140 @@ func TestRequestTransformers(t *testing.T) {
 	})
 }
 
+func TestRequestResponseTransformers(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	t.Run("order", func(t *testing.T) {
+		req := NewRequest(config, "METHOD", "/")
+
+		req.WithText("body")
+
+		req.WithResponseTransformer(func(r *http.Response) {
+			r.Header.Add("X-Order", "1")
+		})
+
+		req.WithResponseTransformer(func(r *http.Response) {
+			r.Header.Add("X-Order", "2")
+		})
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, []string{"1", "2"}, resp.Raw().Header["X-Order"])
+	})
+
+	t.Run("priority", func(t *testing.T) {
+		req := NewRequest(config, "METHOD", "/")
+
+		req.WithText("body")
+
+		req.WithResponseTransformerPriority(1, func(r *http.Response) {
+			r.Header.Add("X-Order", "4")
+		})
+
+		req.WithResponseTransformer(func(r *http.Response) {
+			r.Header.Add("X-Order", "2")
+		})
+
+		req.WithResponseTransformerPriority(-1, func(r *http.Response) {
+			r.Header.Add("X-Order", "1")
+		})
+
+		req.WithResponseTransformerPriority(0, func(r *http.Response) {
+			r.Header.Add("X-Order", "3")
+		})
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, []string{"1", "2", "3", "4"}, resp.Raw().Header["X-Order"])
+	})
+
+	t.Run("unwrap-envelope", func(t *testing.T) {
+		req := NewRequest(config, "METHOD", "/")
+
+		req.WithJSON(map[string]interface{}{
+			"data": map[string]interface{}{"foo": 123},
+		})
+
+		req.WithResponseTransformer(func(r *http.Response) {
+			var envelope struct {
+				Data json.RawMessage `json:"data"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&envelope)
+			assert.NoError(t, err)
+			r.Body = ioutil.NopCloser(bytes.NewReader(envelope.Data))
+		})
+
+		matched := false
+		req.WithMatcher(func(resp *Response) {
+			matched = true
+			resp.JSON().Object().Equal(map[string]interface{}{"foo": 123})
+		})
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		assert.True(t, matched)
+		assert.Equal(t, `{"foo":123}`, string(resp.content))
+	})
+
+	t.Run("unwrap-envelope-content-length", func(t *testing.T) {
+		body := `{"data":{"foo":123},"ok":true}`
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			_, _ = w.Write([]byte(body))
+		})
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         &http.Client{Transport: NewBinder(handler)},
+			Reporter:       reporter,
+		}
+
+		req := NewRequest(config, "GET", "/")
+
+		req.WithResponseTransformer(func(r *http.Response) {
+			var envelope struct {
+				Data json.RawMessage `json:"data"`
+			}
+			err := json.NewDecoder(r.Body).Decode(&envelope)
+			assert.NoError(t, err)
+			r.Body = ioutil.NopCloser(bytes.NewReader(envelope.Data))
+		})
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, strconv.Itoa(len(body)), resp.Raw().Header.Get("Content-Length"))
+		assert.Equal(t, `{"foo":123}`, string(resp.content))
+	})
+
+	t.Run("nil-func", func(t *testing.T) {
+		req := NewRequest(config, "METHOD", "/")
+		req.WithResponseTransformer(nil)
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("nil-func-priority", func(t *testing.T) {
+		req := NewRequest(config, "METHOD", "/")
+		req.WithResponseTransformerPriority(1, nil)
+		req.chain.assertFailed(t)
+	})
+}
+
 func TestRequestClient(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -620,6 +794,96 @@ func TestRequestURLQuery(t *testing.T) {
 		WithQueryString("%").chain.assertFailed(t)
 }
 
+func TestRequestURLQueryStruct(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+		BaseURL:        "http://example.com",
+	}
+
+	type Embedded struct {
+		Limit int `url:"limit"`
+	}
+
+	type S struct {
+		Zz    string   `url:"zz"`
+		Tags  []string `url:"tag"`
+		Csv   []int    `url:"csv,comma"`
+		Empty string   `url:"empty,omitempty"`
+		Skip  string   `url:"-"`
+		Embedded
+	}
+
+	obj := S{
+		Zz:       "foo",
+		Tags:     []string{"a", "b"},
+		Csv:      []int{1, 2},
+		Skip:     "dummy",
+		Embedded: Embedded{Limit: 10},
+	}
+
+	t.Run("sorted", func(t *testing.T) {
+		NewRequest(config, "METHOD", "/path").
+			WithQueryStruct(obj).
+			Expect().chain.assertOK(t)
+
+		assert.Equal(t,
+			"http://example.com/path?csv=1%2C2&limit=10&tag=a&tag=b&zz=foo",
+			client.req.URL.String())
+	})
+
+	t.Run("insertion", func(t *testing.T) {
+		NewRequest(config, "METHOD", "/path").
+			WithQueryOrder(QueryInsertionOrder).
+			WithQuery("first", 1).
+			WithQueryStruct(&obj).
+			WithQueryString("b=1&a=2&b=3").
+			WithQuery("zz", "bar").
+			Expect().chain.assertOK(t)
+
+		assert.Equal(t,
+			"http://example.com/path?first=1&zz=foo&zz=bar&tag=a&tag=b&csv=1%2C2"+
+				"&limit=10&b=1&b=3&a=2",
+			client.req.URL.String())
+	})
+
+	t.Run("insertion object", func(t *testing.T) {
+		NewRequest(config, "METHOD", "/path").
+			WithQueryOrder(QueryInsertionOrder).
+			WithQuery("zz", 1).
+			WithQueryObject(map[string]interface{}{"b": 2, "a": 3}).
+			Expect().chain.assertOK(t)
+
+		assert.Equal(t, "http://example.com/path?zz=1&a=3&b=2",
+			client.req.URL.String())
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		NewRequest(config, "METHOD", "/path").
+			WithQueryStruct(nil).chain.assertFailed(t)
+
+		type MyQuery struct {
+			A string `url:"a"`
+		}
+
+		NewRequest(config, "METHOD", "/path").
+			WithQueryStruct((*MyQuery)(nil)).chain.assertFailed(t)
+
+		NewRequest(config, "METHOD", "/path").
+			WithQueryStruct(map[string]string{"a": "b"}).chain.assertFailed(t)
+
+		NewRequest(config, "METHOD", "/path").
+			WithQueryOrder(QueryOrder(100)).chain.assertFailed(t)
+	})
+}
+
 func TestRequestHeaders(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -660,6 +924,263 @@ func TestRequestHeaders(t *testing.T) {
 	assert.Equal(t, &client.resp, resp.Raw())
 }
 
+func TestRequestRange(t *testing.T) {
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         &mockClient{},
+		Reporter:       newMockReporter(t),
+	}
+
+	t.Run("single", func(t *testing.T) {
+		req := NewRequest(config, "GET", "url")
+
+		req.WithRange(100, 199)
+		req.chain.assertOK(t)
+
+		assert.Equal(t, "bytes=100-199", req.httpReq.Header.Get("Range"))
+	})
+
+	t.Run("open", func(t *testing.T) {
+		req := NewRequest(config, "GET", "url")
+
+		req.WithRange(100, -1)
+		req.chain.assertOK(t)
+
+		assert.Equal(t, "bytes=100-", req.httpReq.Header.Get("Range"))
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		req := NewRequest(config, "GET", "url")
+
+		req.WithRange(0, 0).WithRange(10, 19).WithRange(50, -1)
+		req.chain.assertOK(t)
+
+		assert.Equal(t, []string{"bytes=0-0,10-19,50-"}, req.httpReq.Header["Range"])
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, rng := range [][2]int64{{-1, 10}, {10, 9}, {-5, -1}} {
+			req := NewRequest(config, "GET", "url")
+
+			req.WithRange(rng[0], rng[1])
+			req.chain.assertFailed(t)
+		}
+	})
+}
+
+func TestRequestCORSPreflight(t *testing.T) {
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         &mockClient{},
+		Reporter:       newMockReporter(t),
+	}
+
+	t.Run("without headers", func(t *testing.T) {
+		req := NewRequest(config, "OPTIONS", "url")
+
+		req.WithCORSPreflight("https://example.com", "PUT")
+		req.chain.assertOK(t)
+
+		assert.Equal(t, "https://example.com", req.httpReq.Header.Get("Origin"))
+		assert.Equal(t, "PUT",
+			req.httpReq.Header.Get("Access-Control-Request-Method"))
+		assert.NotContains(t, req.httpReq.Header, "Access-Control-Request-Headers")
+	})
+
+	t.Run("with headers", func(t *testing.T) {
+		req := NewRequest(config, "OPTIONS", "url")
+
+		req.WithCORSPreflight("https://example.com", "PUT",
+			"X-Request-Id", "Content-Type")
+		req.chain.assertOK(t)
+
+		assert.Equal(t, "content-type,x-request-id",
+			req.httpReq.Header.Get("Access-Control-Request-Headers"))
+	})
+
+	t.Run("not options", func(t *testing.T) {
+		req := NewRequest(config, "GET", "url")
+
+		req.WithCORSPreflight("https://example.com", "PUT")
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		req := NewRequest(config, "OPTIONS", "url")
+		req.WithCORSPreflight("", "PUT")
+		req.chain.assertFailed(t)
+
+		req = NewRequest(config, "OPTIONS", "url")
+		req.WithCORSPreflight("https://example.com", "")
+		req.chain.assertFailed(t)
+	})
+}
+
+func TestRequestWebsocketSubprotocols(t *testing.T) {
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         &mockClient{},
+		Reporter:       newMockReporter(t),
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		req := NewRequest(config, "GET", "url")
+
+		req.WithWebsocketSubprotocols("v2.chat", "v1.chat")
+		req.chain.assertOK(t)
+
+		assert.Equal(t, []string{"v2.chat, v1.chat"},
+			req.httpReq.Header["Sec-Websocket-Protocol"])
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		cases := [][]string{
+			nil,
+			{""},
+			{"v1", "v2,v3"},
+			{"v1 chat"},
+		}
+
+		for _, tc := range cases {
+			req := NewRequest(config, "GET", "url")
+
+			req.WithWebsocketSubprotocols(tc...)
+			req.chain.assertFailed(t)
+		}
+	})
+}
+
+func TestRequestConfigHeaders(t *testing.T) {
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         &mockClient{},
+		Reporter:       newMockReporter(t),
+		Headers: http.Header{
+			"Host":         {"example.com"},
+			"Content-Type": {"text/plain"},
+			"X-Test":       {"config"},
+		},
+	}
+
+	req := NewRequest(config, "GET", "url")
+	req.WithHeader("X-Test", "request")
+	req.WithJSON(map[string]interface{}{})
+	req.chain.assertOK(t)
+
+	assert.Equal(t, "example.com", req.httpReq.Host)
+	assert.Equal(t, []string{"config", "request"}, req.httpReq.Header["X-Test"])
+	assert.Equal(t, "application/json; charset=utf-8",
+		req.httpReq.Header.Get("Content-Type"))
+
+	assert.Equal(t, []string{"config"}, config.Headers["X-Test"])
+}
+
+func TestRequestWebsocketCompression(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	t.Run("enable", func(t *testing.T) {
+		dialer := &websocket.Dialer{}
+
+		req := NewRequest(Config{
+			RequestFactory:  DefaultRequestFactory{},
+			Client:          &mockClient{},
+			WebsocketDialer: dialer,
+			Reporter:        reporter,
+		}, "GET", "url")
+
+		req.WithWebsocketUpgrade()
+		req.WithWebsocketCompression(true)
+		req.chain.assertOK(t)
+
+		require.True(t, req.setupWebsocketCompression())
+
+		gorillaDialer, ok := req.config.WebsocketDialer.(*websocket.Dialer)
+		require.True(t, ok)
+		assert.True(t, gorillaDialer.EnableCompression)
+		assert.False(t, dialer.EnableCompression)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		req := NewRequest(Config{
+			RequestFactory:  DefaultRequestFactory{},
+			Client:          &mockClient{},
+			WebsocketDialer: &websocket.Dialer{EnableCompression: true},
+			Reporter:        reporter,
+		}, "GET", "url")
+
+		req.WithWebsocketUpgrade()
+		req.WithWebsocketCompression(false)
+
+		require.True(t, req.setupWebsocketCompression())
+
+		gorillaDialer, ok := req.config.WebsocketDialer.(*websocket.Dialer)
+		require.True(t, ok)
+		assert.False(t, gorillaDialer.EnableCompression)
+	})
+
+	t.Run("no upgrade", func(t *testing.T) {
+		req := NewRequest(Config{
+			RequestFactory: DefaultRequestFactory{},
+			Client:         &mockClient{},
+			Reporter:       reporter,
+		}, "GET", "url")
+
+		req.WithWebsocketCompression(true)
+
+		assert.False(t, req.setupWebsocketCompression())
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("custom dialer", func(t *testing.T) {
+		req := NewRequest(Config{
+			RequestFactory:  DefaultRequestFactory{},
+			Client:          &mockClient{},
+			WebsocketDialer: &mockCustomWebsocketDialer{},
+			Reporter:        reporter,
+		}, "GET", "url")
+
+		req.WithWebsocketUpgrade()
+		req.WithWebsocketCompression(true)
+
+		assert.False(t, req.setupWebsocketCompression())
+		req.chain.assertFailed(t)
+	})
+}
+
+func TestRequestConditionalHeaders(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	ts := time.Date(2022, 1, 1, 3, 0, 0, 0, time.FixedZone("", 3*60*60))
+
+	req := NewRequest(config, "GET", "url").
+		WithIfNoneMatch("abc", `"quoted"`, `W/"weak"`).
+		WithIfMatch("*").
+		WithIfModifiedSince(ts).
+		WithIfUnmodifiedSince(ts)
+
+	req.Expect().chain.assertOK(t)
+
+	assert.Equal(t, `"abc", "quoted", W/"weak"`, client.req.Header.Get("If-None-Match"))
+	assert.Equal(t, `*`, client.req.Header.Get("If-Match"))
+	assert.Equal(t, "Sat, 01 Jan 2022 00:00:00 GMT",
+		client.req.Header.Get("If-Modified-Since"))
+	assert.Equal(t, "Sat, 01 Jan 2022 00:00:00 GMT",
+		client.req.Header.Get("If-Unmodified-Since"))
+
+	NewRequest(config, "GET", "url").WithIfNoneMatch().chain.assertFailed(t)
+	NewRequest(config, "GET", "url").WithIfMatch().chain.assertFailed(t)
+}
+
 func TestRequestCookies(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -869,45 +1390,131 @@ func TestRequestBodyChunkedProto(t *testing.T) {
 	assert.Equal(t, 0, req2.httpReq.ProtoMinor)
 }
 
-func TestRequestBodyBytes(t *testing.T) {
+func TestRequestBodyStream(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
 	client := &mockClient{}
 
 	reporter := newMockReporter(t)
 
+	printer := &mockPrinter{}
+
 	config := Config{
 		RequestFactory: factory,
 		Client:         client,
 		Reporter:       reporter,
+		Printers:       []Printer{printer},
 	}
 
-	req := NewRequest(config, "METHOD", "/path")
+	t.Run("basic", func(t *testing.T) {
+		body := newMockBody("body")
 
-	req.WithBytes([]byte("body"))
+		req := NewRequest(config, "PUT", "url")
+		req.WithBodyStream(body)
 
-	resp := req.Expect()
-	resp.chain.assertOK(t)
+		resp := req.Expect()
+		resp.chain.assertOK(t)
 
-	assert.False(t, client.req.Body == nil)
-	assert.Equal(t, int64(len("body")), client.req.ContentLength)
+		assert.Equal(t, int64(-1), client.req.ContentLength)
+		assert.Equal(t, "body", string(resp.content))
+		assert.Equal(t, "", string(printer.reqBody))
 
-	assert.Equal(t, "METHOD", client.req.Method)
-	assert.Equal(t, "/path", client.req.URL.String())
-	assert.Equal(t, make(http.Header), client.req.Header)
-	assert.Equal(t, "body", string(resp.content))
+		_, ok := client.req.Body.(*bodyWrapper)
+		assert.False(t, ok)
+	})
 
-	assert.Equal(t, &client.resp, resp.Raw())
-}
+	t.Run("nil", func(t *testing.T) {
+		req := NewRequest(config, "PUT", "url")
+		req.WithBodyStream(nil)
+		req.chain.assertFailed(t)
+	})
 
-func TestRequestBodyBytesNil(t *testing.T) {
-	factory := DefaultRequestFactory{}
+	t.Run("proto", func(t *testing.T) {
+		req := NewRequest(config, "PUT", "url")
+		req.WithProto("HTTP/1.0")
+		req.WithBodyStream(strings.NewReader("body"))
+		req.chain.assertFailed(t)
+	})
 
-	client := &mockClient{}
+	t.Run("retries", func(t *testing.T) {
+		req := NewRequest(config, "PUT", "url")
+		req.WithBodyStream(strings.NewReader("body"))
+		req.WithMaxRetries(1)
+		req.Expect().chain.assertFailed(t)
+	})
 
-	reporter := newMockReporter(t)
+	t.Run("redirects", func(t *testing.T) {
+		req := NewRequest(config, "PUT", "url")
+		req.WithBodyStream(strings.NewReader("body"))
+		req.WithRedirectPolicy(FollowAllRedirects)
+		req.Expect().chain.assertFailed(t)
+	})
 
-	config := Config{
+	t.Run("upload progress", func(t *testing.T) {
+		req := NewRequest(config, "PUT", "url")
+		req.WithBodyStream(newMockBody("body"))
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		progress := resp.UploadProgress()
+		progress.chain.assertOK(t)
+
+		progress.Bytes().Equal(4).chain.assertOK(t)
+		progress.Chunks().Equal(1).chain.assertOK(t)
+		progress.Progressive().chain.assertFailed(t)
+	})
+
+	t.Run("upload progress not streamed", func(t *testing.T) {
+		req := NewRequest(config, "PUT", "url")
+		req.WithText("body")
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		resp.UploadProgress().chain.assertFailed(t)
+	})
+}
+
+func TestRequestBodyBytes(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	req := NewRequest(config, "METHOD", "/path")
+
+	req.WithBytes([]byte("body"))
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.False(t, client.req.Body == nil)
+	assert.Equal(t, int64(len("body")), client.req.ContentLength)
+
+	assert.Equal(t, "METHOD", client.req.Method)
+	assert.Equal(t, "/path", client.req.URL.String())
+	assert.Equal(t, make(http.Header), client.req.Header)
+	assert.Equal(t, "body", string(resp.content))
+
+	assert.Equal(t, &client.resp, resp.Raw())
+}
+
+func TestRequestBodyBytesNil(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
 		RequestFactory: factory,
 		Client:         client,
 		Reporter:       reporter,
@@ -961,6 +1568,209 @@ func TestRequestBodyText(t *testing.T) {
 	assert.Equal(t, &client.resp, resp.Raw())
 }
 
+func TestRequestBodyCompression(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	reporter := newMockReporter(t)
+
+	for _, encoding := range []string{"gzip", "deflate", "br", "zstd"} {
+		t.Run(encoding, func(t *testing.T) {
+			client := &mockClient{}
+
+			config := Config{
+				RequestFactory: factory,
+				Client:         client,
+				Reporter:       reporter,
+			}
+
+			resp := NewRequest(config, "POST", "url").
+				WithText("hello, world").
+				WithCompression(encoding).
+				Expect()
+			resp.chain.assertOK(t)
+
+			assert.Equal(t, encoding, client.req.Header.Get("Content-Encoding"))
+			assert.Equal(t, int64(len(resp.rawContent)), client.req.ContentLength)
+			assert.Equal(t, "hello, world",
+				decompressForTest(t, encoding, resp.rawContent))
+			assert.Equal(t, "hello, world", string(resp.content))
+		})
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		client := &mockClient{}
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       reporter,
+		}
+
+		resp := NewRequest(config, "POST", "url").
+			WithBodyStream(strings.NewReader("hello, world")).
+			WithCompression("gzip").
+			Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "gzip", client.req.Header.Get("Content-Encoding"))
+		assert.Equal(t, int64(-1), client.req.ContentLength)
+		assert.Equal(t, "hello, world", decompressForTest(t, "gzip", resp.rawContent))
+		assert.Equal(t, "hello, world", string(resp.content))
+	})
+
+	t.Run("stream usage error", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &mockClient{},
+			Reporter:       reporter,
+		}
+
+		body := newMockNotifyReader()
+
+		NewRequest(config, "POST", "url").
+			WithBodyStream(body).
+			WithCompression("gzip").
+			WithMaxRetries(1).
+			Expect().
+			chain.assertFailed(t)
+
+		// body is not compressed in background if request is rejected
+		select {
+		case <-body.reads:
+			t.Fatal("unexpected read of rejected request body")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		client := &mockClient{}
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       reporter,
+		}
+
+		NewRequest(config, "GET", "url").
+			WithCompression("gzip").
+			Expect().
+			chain.assertOK(t)
+
+		assert.Equal(t, "", client.req.Header.Get("Content-Encoding"))
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &mockClient{},
+			Reporter:       reporter,
+		}
+
+		NewRequest(config, "POST", "url").
+			WithCompression("compress").
+			chain.assertFailed(t)
+	})
+}
+
+func TestRequestBodyEncoded(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+		Codecs: map[string]BodyCodec{
+			"application/x-mock": mockBodyCodec{},
+		},
+	}
+
+	req := NewRequest(config, "POST", "url")
+	req.WithEncoded("application/msgpack", map[string]interface{}{"foo": 1})
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "application/msgpack", client.req.Header.Get("Content-Type"))
+	assert.Equal(t, []byte{0x81, 0xa3, 'f', 'o', 'o', 0x01}, resp.content)
+
+	req = NewRequest(config, "POST", "url")
+	req.WithEncoded("application/x-mock", nil)
+
+	resp = req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "application/x-mock", client.req.Header.Get("Content-Type"))
+	assert.Equal(t, "mock", string(resp.content))
+
+	req = NewRequest(config, "POST", "url")
+	req.WithEncoded("text/plain", "foo")
+	req.chain.assertFailed(t)
+
+	req = NewRequest(config, "POST", "url")
+	req.WithEncoded("application/cbor", func() {})
+	req.chain.assertFailed(t)
+}
+
+func TestRequestBodyProtobuf(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	t.Run("binary", func(t *testing.T) {
+		req := NewRequest(config, "POST", "url").
+			WithProtobuf(&mockProtoMessage{Name: "john"})
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "application/x-protobuf",
+			client.req.Header.Get("Content-Type"))
+		assert.Equal(t, "\x0a\x04john", string(resp.content))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		req := NewRequest(config, "POST", "url").
+			WithProtobufJSON(&mockProtoMessage{Name: "john"})
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "application/json; charset=utf-8",
+			client.req.Header.Get("Content-Type"))
+		assert.Equal(t, `{"name":"john"}`, string(resp.content))
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		req := NewRequest(config, "POST", "url").
+			WithProtobuf(&mockProtoMessage{Name: strings.Repeat("x", 200)})
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("unsupported message", func(t *testing.T) {
+		req := NewRequest(config, "POST", "url").
+			WithProtobuf(struct{}{})
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("nil message", func(t *testing.T) {
+		req := NewRequest(config, "POST", "url").
+			WithProtobuf(nil)
+		req.chain.assertFailed(t)
+	})
+}
+
 func TestRequestBodyForm(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -1239,6 +2049,160 @@ func TestRequestBodyMultipartFile(t *testing.T) {
 	assert.True(t, eof == nil)
 }
 
+func TestRequestBodyMultipartField(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	req := NewRequest(config, "POST", "url")
+
+	req.WithMultipart()
+	req.WithMultipartField("a", `{"x":1}`, "application/json",
+		map[string]string{"Content-ID": "<a>"})
+	req.WithMultipartField("b", 123, "", nil)
+	req.WithMultipartField("c", []byte("3"), "text/plain",
+		map[string]string{"Content-Disposition": `form-data; name="c"; filename="c.txt"`})
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, int64(len(resp.content)), client.req.ContentLength)
+
+	_, params, err := mime.ParseMediaType(client.req.Header.Get("Content-Type"))
+	assert.True(t, err == nil)
+
+	reader := multipart.NewReader(bytes.NewReader(resp.content), params["boundary"])
+
+	part1, _ := reader.NextPart()
+	assert.Equal(t, "a", part1.FormName())
+	assert.Equal(t, "application/json", part1.Header.Get("Content-Type"))
+	assert.Equal(t, "<a>", part1.Header.Get("Content-ID"))
+	b1, _ := ioutil.ReadAll(part1)
+	assert.Equal(t, `{"x":1}`, string(b1))
+
+	part2, _ := reader.NextPart()
+	assert.Equal(t, "b", part2.FormName())
+	assert.Equal(t, "", part2.Header.Get("Content-Type"))
+	b2, _ := ioutil.ReadAll(part2)
+	assert.Equal(t, "123", string(b2))
+
+	part3, _ := reader.NextPart()
+	assert.Equal(t, "c", part3.FormName())
+	assert.Equal(t, "c.txt", part3.FileName())
+	assert.Equal(t, "text/plain", part3.Header.Get("Content-Type"))
+	b3, _ := ioutil.ReadAll(part3)
+	assert.Equal(t, "3", string(b3))
+
+	eof, _ := reader.NextPart()
+	assert.True(t, eof == nil)
+}
+
+func TestRequestBodyMultipartStream(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	reporter := newMockReporter(t)
+
+	t.Run("streamed", func(t *testing.T) {
+		client := &mockClient{}
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       reporter,
+		}
+
+		req := NewRequest(config, "POST", "url")
+
+		req.WithMultipart()
+		req.WithFormField("a", "1")
+		req.WithFileStream("b", "filename2", strings.NewReader("2"))
+		req.WithFileBytes("c", "filename3", []byte("3"))
+		req.WithFileStream("d", "filename4", strings.NewReader("4"))
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, int64(-1), client.req.ContentLength)
+
+		_, params, err := mime.ParseMediaType(client.req.Header.Get("Content-Type"))
+		assert.True(t, err == nil)
+
+		reader := multipart.NewReader(bytes.NewReader(resp.content), params["boundary"])
+
+		expected := []struct {
+			name     string
+			filename string
+			content  string
+		}{
+			{"a", "", "1"},
+			{"b", "filename2", "2"},
+			{"c", "filename3", "3"},
+			{"d", "filename4", "4"},
+		}
+
+		for _, exp := range expected {
+			part, err := reader.NextPart()
+			require.NoError(t, err)
+			assert.Equal(t, exp.name, part.FormName())
+			assert.Equal(t, exp.filename, part.FileName())
+			b, _ := ioutil.ReadAll(part)
+			assert.Equal(t, exp.content, string(b))
+		}
+
+		eof, _ := reader.NextPart()
+		assert.True(t, eof == nil)
+	})
+
+	t.Run("nil reader", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &mockClient{},
+			Reporter:       reporter,
+		}
+
+		req := NewRequest(config, "POST", "url")
+		req.WithMultipart()
+		req.WithFileStream("a", "a", nil)
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("proto", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &mockClient{},
+			Reporter:       reporter,
+		}
+
+		req := NewRequest(config, "POST", "url")
+		req.WithProto("HTTP/1.0")
+		req.WithMultipart()
+		req.WithFileStream("a", "a", strings.NewReader("a"))
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &mockClient{},
+			Reporter:       reporter,
+		}
+
+		req := NewRequest(config, "POST", "url")
+		req.WithMultipart()
+		req.WithFileStream("a", "a", strings.NewReader("a"))
+		req.WithMaxRetries(1)
+		req.Expect().chain.assertFailed(t)
+	})
+}
+
 func TestRequestBodyJSON(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
@@ -1276,7 +2240,7 @@ func TestRequestBodyJSON(t *testing.T) {
 	assert.Equal(t, &client.resp, resp.Raw())
 }
 
-func TestRequestContentLength(t *testing.T) {
+func TestRequestBodyJSONPatch(t *testing.T) {
 	factory := DefaultRequestFactory{}
 
 	client := &mockClient{}
@@ -1289,36 +2253,334 @@ func TestRequestContentLength(t *testing.T) {
 		Reporter:       reporter,
 	}
 
-	req1 := NewRequest(config, "METHOD", "url")
-	req1.WithChunked(bytes.NewReader([]byte("12345")))
-	req1.Expect().chain.assertOK(t)
-	assert.Equal(t, int64(-1), client.req.ContentLength)
+	req := NewRequest(config, "PATCH", "url")
 
-	req2 := NewRequest(config, "METHOD", "url")
-	req2.WithBytes([]byte("12345"))
-	req2.Expect().chain.assertOK(t)
-	assert.Equal(t, int64(5), client.req.ContentLength)
+	req.WithJSONPatch([]JSONPatchOp{
+		{Op: "replace", Path: "/foo", Value: 123},
+		{Op: "move", From: "/bar", Path: "/baz"},
+		{Op: "add", Path: "/x", Value: nil},
+		{Op: "test", Path: "/y", Value: nil},
+		{Op: "remove", Path: "/z", Value: nil},
+	})
 
-	req3 := NewRequest(config, "METHOD", "url")
-	req3.WithText("12345")
-	req3.Expect().chain.assertOK(t)
-	assert.Equal(t, int64(5), client.req.ContentLength)
+	resp := req.Expect()
+	resp.chain.assertOK(t)
 
-	j, _ := json.Marshal(map[string]string{"a": "b"})
-	req4 := NewRequest(config, "METHOD", "url")
-	req4.WithJSON(map[string]string{"a": "b"})
-	req4.Expect().chain.assertOK(t)
-	assert.Equal(t, int64(len(j)), client.req.ContentLength)
+	assert.Equal(t, "application/json-patch+json",
+		client.req.Header.Get("Content-Type"))
+	assert.Equal(t,
+		`[{"op":"replace","path":"/foo","value":123},`+
+			`{"op":"move","path":"/baz","from":"/bar"},`+
+			`{"op":"add","path":"/x","value":null},`+
+			`{"op":"test","path":"/y","value":null},`+
+			`{"op":"remove","path":"/z"}]`,
+		string(resp.content))
+
+	req = NewRequest(config, "PATCH", "url")
+	req.WithJSONPatch(nil)
+	req.chain.assertFailed(t)
+}
 
-	f := `a=b`
-	req5 := NewRequest(config, "METHOD", "url")
-	req5.WithForm(map[string]string{"a": "b"})
-	req5.Expect().chain.assertOK(t)
-	assert.Equal(t, int64(len(f)), client.req.ContentLength)
+func TestRequestBodyGraphQL(t *testing.T) {
+	factory := DefaultRequestFactory{}
 
-	req6 := NewRequest(config, "METHOD", "url")
-	req6.WithFormField("a", "b")
-	req6.Expect().chain.assertOK(t)
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	req := NewRequest(config, "POST", "url")
+
+	req.WithGraphQL(`query User($id: ID!) { user(id: $id) { name } }`,
+		map[string]interface{}{"id": 1}, "User")
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "application/json; charset=utf-8",
+		client.req.Header.Get("Content-Type"))
+	assert.JSONEq(t,
+		`{"query":"query User($id: ID!) { user(id: $id) { name } }",`+
+			`"variables":{"id":1},"operationName":"User"}`,
+		string(resp.content))
+
+	req = NewRequest(config, "POST", "url")
+	req.WithGraphQL(`{ users { name } }`, nil, "")
+
+	resp = req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, `{"query":"{ users { name } }"}`, string(resp.content))
+
+	req = NewRequest(config, "POST", "url")
+	req.WithGraphQL("", nil, "")
+	req.chain.assertFailed(t)
+}
+
+func TestRequestBodyJSONRPC(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	req := NewRequest(config, "POST", "url")
+	req.WithJSONRPC("subtract", []int{42, 23}, 1)
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "application/json; charset=utf-8",
+		client.req.Header.Get("Content-Type"))
+	assert.JSONEq(t,
+		`{"jsonrpc":"2.0","method":"subtract","params":[42,23],"id":1}`,
+		string(resp.content))
+	assert.Equal(t, &jsonrpcRequest{ids: []interface{}{1.0}}, resp.jsonrpc)
+
+	req = NewRequest(config, "POST", "url")
+	req.WithJSONRPC("update", nil, nil)
+
+	resp = req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, `{"jsonrpc":"2.0","method":"update"}`, string(resp.content))
+	assert.Equal(t, &jsonrpcRequest{}, resp.jsonrpc)
+
+	req = NewRequest(config, "POST", "url")
+	req.WithJSONRPCBatch(
+		JSONRPCCall{Method: "sum", Params: map[string]int{"a": 1}, ID: "1"},
+		JSONRPCCall{Method: "notify", Params: []string{"hello"}},
+		JSONRPCCall{Method: "get", ID: 2},
+	)
+
+	resp = req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.JSONEq(t,
+		`[{"jsonrpc":"2.0","method":"sum","params":{"a":1},"id":"1"},`+
+			`{"jsonrpc":"2.0","method":"notify","params":["hello"]},`+
+			`{"jsonrpc":"2.0","method":"get","id":2}]`,
+		string(resp.content))
+	assert.Equal(t,
+		&jsonrpcRequest{batch: true, ids: []interface{}{"1", 2.0}}, resp.jsonrpc)
+
+	t.Run("invalid", func(t *testing.T) {
+		cases := []func(req *Request){
+			func(req *Request) {
+				req.WithJSONRPC("", nil, 1)
+			},
+			func(req *Request) {
+				req.WithJSONRPC("foo", 123, 1)
+			},
+			func(req *Request) {
+				req.WithJSONRPC("foo", nil, true)
+			},
+			func(req *Request) {
+				req.WithJSONRPC("foo", nil, []int{1})
+			},
+			func(req *Request) {
+				req.WithJSONRPC("foo", func() {}, 1)
+			},
+			func(req *Request) {
+				req.WithJSONRPCBatch()
+			},
+			func(req *Request) {
+				req.WithJSONRPCBatch(
+					JSONRPCCall{Method: "foo", ID: 1},
+					JSONRPCCall{Method: "bar", ID: 1.0},
+				)
+			},
+			func(req *Request) {
+				req.WithJSON(nil).WithJSONRPC("foo", nil, 1)
+			},
+		}
+
+		for _, tc := range cases {
+			req := NewRequest(config, "POST", "url")
+			tc(req)
+			req.chain.assertFailed(t)
+		}
+	})
+}
+
+func TestRequestBodySOAP(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	type GetOrder struct {
+		XMLName xml.Name `xml:"http://example.com/orders GetOrder"`
+		ID      int      `xml:"Id"`
+	}
+
+	req := NewRequest(config, "POST", "url")
+	req.WithSOAP("http://example.com/orders/GetOrder", GetOrder{ID: 42})
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "text/xml; charset=utf-8",
+		client.req.Header.Get("Content-Type"))
+	assert.Equal(t, `"http://example.com/orders/GetOrder"`,
+		client.req.Header.Get("SOAPAction"))
+	assert.Equal(t, xml.Header+
+		`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">`+
+		`<soap:Body>`+
+		`<GetOrder xmlns="http://example.com/orders"><Id>42</Id></GetOrder>`+
+		`</soap:Body>`+
+		`</soap:Envelope>`,
+		string(resp.content))
+
+	envelope := `<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/">` +
+		`<s:Body/></s:Envelope>`
+
+	req = NewRequest(config, "POST", "url")
+	req.WithSOAP("action", envelope)
+
+	resp = req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, xml.Header+
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/">`+
+		`<s:Body/></s:Envelope>`,
+		string(resp.content))
+
+	req = NewRequest(config, "POST", "url")
+	req.WithSOAP("action", nil)
+	req.chain.assertFailed(t)
+
+	req = NewRequest(config, "POST", "url")
+	req.WithSOAP("action", "not xml")
+	req.chain.assertFailed(t)
+
+	req = NewRequest(config, "POST", "url")
+	req.WithSOAP("action", make(chan int))
+	req.chain.assertFailed(t)
+}
+
+func TestRequestBodyMergePatch(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	req := NewRequest(config, "PATCH", "url")
+
+	req.WithMergePatch(map[string]interface{}{"foo": nil})
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "application/merge-patch+json",
+		client.req.Header.Get("Content-Type"))
+	assert.Equal(t, `{"foo":null}`, string(resp.content))
+}
+
+func TestRequestBodyXML(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	type item struct {
+		XMLName struct{} `xml:"item"`
+		Foo     int      `xml:"foo"`
+	}
+
+	req := NewRequest(config, "PUT", "url")
+
+	req.WithXML(item{Foo: 123})
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	assert.Equal(t, "application/xml; charset=utf-8",
+		client.req.Header.Get("Content-Type"))
+	assert.Equal(t, xml.Header+`<item><foo>123</foo></item>`,
+		string(resp.content))
+
+	req = NewRequest(config, "PUT", "url")
+	req.WithXML(make(chan int))
+	req.chain.assertFailed(t)
+}
+
+func TestRequestContentLength(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+	}
+
+	req1 := NewRequest(config, "METHOD", "url")
+	req1.WithChunked(bytes.NewReader([]byte("12345")))
+	req1.Expect().chain.assertOK(t)
+	assert.Equal(t, int64(-1), client.req.ContentLength)
+
+	req2 := NewRequest(config, "METHOD", "url")
+	req2.WithBytes([]byte("12345"))
+	req2.Expect().chain.assertOK(t)
+	assert.Equal(t, int64(5), client.req.ContentLength)
+
+	req3 := NewRequest(config, "METHOD", "url")
+	req3.WithText("12345")
+	req3.Expect().chain.assertOK(t)
+	assert.Equal(t, int64(5), client.req.ContentLength)
+
+	j, _ := json.Marshal(map[string]string{"a": "b"})
+	req4 := NewRequest(config, "METHOD", "url")
+	req4.WithJSON(map[string]string{"a": "b"})
+	req4.Expect().chain.assertOK(t)
+	assert.Equal(t, int64(len(j)), client.req.ContentLength)
+
+	f := `a=b`
+	req5 := NewRequest(config, "METHOD", "url")
+	req5.WithForm(map[string]string{"a": "b"})
+	req5.Expect().chain.assertOK(t)
+	assert.Equal(t, int64(len(f)), client.req.ContentLength)
+
+	req6 := NewRequest(config, "METHOD", "url")
+	req6.WithFormField("a", "b")
+	req6.Expect().chain.assertOK(t)
 	assert.Equal(t, int64(len(f)), client.req.ContentLength)
 
 	req7 := NewRequest(config, "METHOD", "url")
@@ -1601,6 +2863,153 @@ func TestRequestErrorConflictMultipart(t *testing.T) {
 	req3 := NewRequest(config, "METHOD", "url")
 	req3.WithFileBytes("a", "a", []byte("a"))
 	req3.chain.assertFailed(t)
+
+	req4 := NewRequest(config, "METHOD", "url")
+	req4.WithFileStream("a", "a", strings.NewReader("a"))
+	req4.chain.assertFailed(t)
+
+	req5 := NewRequest(config, "METHOD", "url")
+	req5.WithMultipartField("a", "a", "", nil)
+	req5.chain.assertFailed(t)
+}
+
+type mockRetryClient struct {
+	statuses []int
+	header   http.Header
+	calls    int
+	auth     []string
+}
+
+func (c *mockRetryClient) Do(req *http.Request) (*http.Response, error) {
+	c.auth = append(c.auth, req.Header.Get("Authorization"))
+	status := http.StatusOK
+	if c.calls < len(c.statuses) {
+		status = c.statuses[c.calls]
+	}
+	c.calls++
+	return &http.Response{
+		StatusCode: status,
+		Header:     c.header,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+func TestRequestRetryPolicyFunc(t *testing.T) {
+	client := &mockRetryClient{
+		statuses: []int{http.StatusTooManyRequests, http.StatusBadRequest},
+	}
+
+	config := Config{
+		Client:   client,
+		Reporter: newMockReporter(t),
+	}
+
+	req := NewRequest(config, "GET", "url").
+		WithMaxRetries(5).
+		WithRetryDelay(0, 0).
+		WithRetryPolicyFunc(func(resp *http.Response, err error) bool {
+			return resp.StatusCode == http.StatusTooManyRequests
+		})
+
+	req.Expect().Status(http.StatusBadRequest).chain.assertOK(t)
+	assert.Equal(t, 2, client.calls)
+
+	req = NewRequest(config, "GET", "url")
+	req.WithRetryPolicyFunc(nil)
+	req.chain.assertFailed(t)
+}
+
+func TestRequestRetryOptions(t *testing.T) {
+	config := Config{
+		Client:   &mockRetryClient{},
+		Reporter: newMockReporter(t),
+	}
+
+	t.Run("backoff", func(t *testing.T) {
+		NewRequest(config, "GET", "url").WithRetryBackoff(1).chain.assertOK(t)
+		NewRequest(config, "GET", "url").WithRetryBackoff(0.5).chain.assertFailed(t)
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		NewRequest(config, "GET", "url").WithRetryJitter(0).chain.assertOK(t)
+		NewRequest(config, "GET", "url").WithRetryJitter(1).chain.assertOK(t)
+		NewRequest(config, "GET", "url").WithRetryJitter(-0.1).chain.assertFailed(t)
+		NewRequest(config, "GET", "url").WithRetryJitter(1.1).chain.assertFailed(t)
+
+		req := NewRequest(config, "GET", "url").
+			WithRetryDelay(time.Second, 2*time.Second).
+			WithRetryJitter(0.5)
+
+		for n := 0; n < 100; n++ {
+			d := req.jitterDelay(time.Second)
+			assert.True(t, d >= time.Second/2)
+			assert.True(t, d <= 2*time.Second)
+		}
+	})
+
+	t.Run("max_elapsed", func(t *testing.T) {
+		NewRequest(config, "GET", "url").WithRetryMaxElapsed(0).chain.assertOK(t)
+		NewRequest(config, "GET", "url").WithRetryMaxElapsed(-1).chain.assertFailed(t)
+
+		client := &mockRetryClient{
+			statuses: []int{500, 500, 500, 500},
+		}
+
+		req := NewRequest(config, "GET", "url").
+			WithClient(client).
+			WithMaxRetries(3).
+			WithRetryDelay(time.Hour, time.Hour).
+			WithRetryMaxElapsed(time.Minute)
+
+		req.Expect().Status(http.StatusInternalServerError).chain.assertOK(t)
+		assert.Equal(t, 1, client.calls)
+	})
+
+	t.Run("retry_after", func(t *testing.T) {
+		client := &mockRetryClient{
+			statuses: []int{http.StatusServiceUnavailable},
+			header:   http.Header{"Retry-After": {"0"}},
+		}
+
+		req := NewRequest(config, "GET", "url").
+			WithClient(client).
+			WithMaxRetries(1).
+			WithRetryDelay(time.Hour, time.Hour)
+
+		req.Expect().Status(http.StatusOK).chain.assertOK(t)
+		assert.Equal(t, 2, client.calls)
+	})
+}
+
+func TestRequestRetryAfter(t *testing.T) {
+	cases := []struct {
+		header string
+		ok     bool
+		value  time.Duration
+	}{
+		{"", false, 0},
+		{"bad", false, 0},
+		{"-1", false, 0},
+		{"0", true, 0},
+		{"120", true, 2 * time.Minute},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", true, 0},
+	}
+
+	for _, tc := range cases {
+		resp := &http.Response{
+			Header: http.Header{},
+		}
+		if tc.header != "" {
+			resp.Header.Set("Retry-After", tc.header)
+		}
+
+		d, ok := parseRetryAfter(resp)
+		assert.Equal(t, tc.ok, ok, tc.header)
+		assert.Equal(t, tc.value, d, tc.header)
+	}
+
+	_, ok := parseRetryAfter(nil)
+	assert.False(t, ok)
 }
 
 func TestRequestRedirect(t *testing.T) {
@@ -2215,3 +3624,1016 @@ func (mt *mockTransportRedirect) WithMaxRedirect(
 
 	return mt
 }
+
+func TestRequestOAuth2(t *testing.T) {
+	t.Run("refresh on retry", func(t *testing.T) {
+		client := &mockRetryClient{
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+		}
+
+		config := Config{
+			Client:   client,
+			Reporter: newMockReporter(t),
+		}
+
+		n := 0
+		ts := TokenSourceFunc(func() (*Token, error) {
+			n++
+			return &Token{AccessToken: fmt.Sprintf("token%d", n)}, nil
+		})
+
+		NewRequest(config, "GET", "url").
+			WithOAuth2(ts).
+			WithMaxRetries(1).
+			WithRetryDelay(0, 0).
+			Expect().
+			Status(http.StatusOK).
+			chain.assertOK(t)
+
+		assert.Equal(t, []string{"Bearer token1", "Bearer token2"}, client.auth)
+	})
+
+	t.Run("token type", func(t *testing.T) {
+		client := &mockRetryClient{}
+
+		config := Config{
+			Client:   client,
+			Reporter: newMockReporter(t),
+		}
+
+		NewRequest(config, "GET", "url").
+			WithOAuth2(StaticTokenSource(&Token{
+				AccessToken: "foo",
+				TokenType:   "MAC",
+			})).
+			Expect().
+			chain.assertOK(t)
+
+		assert.Equal(t, []string{"MAC foo"}, client.auth)
+	})
+
+	t.Run("token error", func(t *testing.T) {
+		client := &mockRetryClient{}
+
+		config := Config{
+			Client:   client,
+			Reporter: newMockReporter(t),
+		}
+
+		NewRequest(config, "GET", "url").
+			WithOAuth2(TokenSourceFunc(func() (*Token, error) {
+				return nil, errors.New("refresh failed")
+			})).
+			Expect().
+			chain.assertFailed(t)
+
+		assert.Equal(t, 0, client.calls)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		config := Config{
+			Client:   &mockRetryClient{},
+			Reporter: newMockReporter(t),
+		}
+
+		NewRequest(config, "GET", "url").
+			WithOAuth2(StaticTokenSource(&Token{
+				AccessToken: "foo",
+				Expiry:      time.Now().Add(-time.Hour),
+			})).
+			Expect().
+			chain.assertFailed(t)
+	})
+
+	t.Run("nil source", func(t *testing.T) {
+		config := Config{
+			Client:   &mockRetryClient{},
+			Reporter: newMockReporter(t),
+		}
+
+		NewRequest(config, "GET", "url").
+			WithOAuth2(nil).
+			chain.assertFailed(t)
+	})
+}
+
+func TestRequestAuth(t *testing.T) {
+	basic := func(username, password string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString(
+			[]byte(username+":"+password))
+	}
+
+	t.Run("rotate on retry", func(t *testing.T) {
+		client := &mockRetryClient{
+			statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+		}
+
+		config := Config{
+			Client:   client,
+			Reporter: newMockReporter(t),
+		}
+
+		n := 0
+		cp := CredentialsProviderFunc(func() (*Credentials, error) {
+			n++
+			return &Credentials{Token: fmt.Sprintf("token%d", n)}, nil
+		})
+
+		NewRequest(config, "GET", "url").
+			WithAuth(cp).
+			WithMaxRetries(1).
+			WithRetryDelay(0, 0).
+			Expect().
+			Status(http.StatusOK).
+			chain.assertOK(t)
+
+		assert.Equal(t, []string{"Bearer token1", "Bearer token2"}, client.auth)
+	})
+
+	t.Run("basic", func(t *testing.T) {
+		client := &mockRetryClient{}
+
+		config := Config{
+			Client:   client,
+			Reporter: newMockReporter(t),
+		}
+
+		NewRequest(config, "GET", "url").
+			WithAuth(MapCredentials(map[string]string{
+				"username": "john",
+				"password": "secret",
+			})).
+			Expect().
+			chain.assertOK(t)
+
+		assert.Equal(t, []string{basic("john", "secret")}, client.auth)
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		client := &mockRetryClient{}
+
+		config := Config{
+			Client:   client,
+			Reporter: newMockReporter(t),
+		}
+
+		NewRequest(config, "GET", "url").
+			WithAuth(CredentialsProviderFunc(func() (*Credentials, error) {
+				return nil, errors.New("secret store unavailable")
+			})).
+			Expect().
+			chain.assertFailed(t)
+
+		assert.Equal(t, 0, client.calls)
+	})
+
+	t.Run("nil provider", func(t *testing.T) {
+		config := Config{
+			Client:   &mockRetryClient{},
+			Reporter: newMockReporter(t),
+		}
+
+		NewRequest(config, "GET", "url").
+			WithAuth(nil).
+			chain.assertFailed(t)
+	})
+
+	t.Run("basic auth file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "httpexpect")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "credentials")
+
+		client := &mockRetryClient{}
+
+		config := Config{
+			Client:   client,
+			Reporter: newMockReporter(t),
+		}
+
+		require.NoError(t, ioutil.WriteFile(path, []byte("john:secret\n"), 0600))
+
+		NewRequest(config, "GET", "url").
+			WithBasicAuthFile(path).
+			Expect().
+			chain.assertOK(t)
+
+		assert.Equal(t, []string{basic("john", "secret")}, client.auth)
+
+		require.NoError(t, ioutil.WriteFile(path, []byte("token"), 0600))
+
+		NewRequest(config, "GET", "url").
+			WithBasicAuthFile(path).
+			Expect().
+			chain.assertFailed(t)
+
+		NewRequest(config, "GET", "url").
+			WithBasicAuthFile(filepath.Join(dir, "missing")).
+			Expect().
+			chain.assertFailed(t)
+	})
+
+	t.Run("default auth", func(t *testing.T) {
+		client := &mockRetryClient{}
+
+		config := Config{
+			Client:      client,
+			Reporter:    newMockReporter(t),
+			DefaultAuth: StaticCredentials(&Credentials{Token: "default"}),
+		}
+
+		NewRequest(config, "GET", "url").
+			Expect().
+			chain.assertOK(t)
+
+		NewRequest(config, "GET", "url").
+			WithAuth(StaticCredentials(&Credentials{Token: "custom"})).
+			Expect().
+			chain.assertOK(t)
+
+		NewRequest(config, "GET", "url").
+			WithBasicAuth("john", "secret").
+			Expect().
+			chain.assertOK(t)
+
+		NewRequest(config, "GET", "url").
+			WithHeader("Authorization", "Bearer header").
+			Expect().
+			chain.assertOK(t)
+
+		assert.Equal(t, []string{
+			"Bearer default",
+			"Bearer custom",
+			basic("john", "secret"),
+			"Bearer header",
+		}, client.auth)
+	})
+
+	t.Run("explicit header overrides provider", func(t *testing.T) {
+		client := &mockRetryClient{}
+
+		config := Config{
+			Client:   client,
+			Reporter: newMockReporter(t),
+		}
+
+		provider := StaticCredentials(&Credentials{Token: "provider"})
+
+		NewRequest(config, "GET", "url").
+			WithAuth(provider).
+			WithBasicAuth("john", "secret").
+			Expect().
+			chain.assertOK(t)
+
+		NewRequest(config, "GET", "url").
+			WithOAuth2(StaticTokenSource(&Token{AccessToken: "oauth"})).
+			WithHeaders(map[string]string{"authorization": "Bearer headers"}).
+			Expect().
+			chain.assertOK(t)
+
+		// last call wins
+		NewRequest(config, "GET", "url").
+			WithBasicAuth("john", "secret").
+			WithAuth(provider).
+			Expect().
+			chain.assertOK(t)
+
+		assert.Equal(t, []string{
+			basic("john", "secret"),
+			"Bearer headers",
+			"Bearer provider",
+		}, client.auth)
+	})
+}
+
+func TestRequestJWT(t *testing.T) {
+	client := &mockRetryClient{}
+
+	config := Config{
+		Client:   client,
+		Reporter: newMockReporter(t),
+	}
+
+	NewRequest(config, "GET", "url").
+		WithJWT(map[string]interface{}{"sub": "john"}, []byte("secret")).
+		Expect().
+		chain.assertOK(t)
+
+	require.Equal(t, 1, len(client.auth))
+	assert.True(t, strings.HasPrefix(client.auth[0], "Bearer "))
+
+	parts := strings.Split(strings.TrimPrefix(client.auth[0], "Bearer "), ".")
+	require.Equal(t, 3, len(parts))
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sub":"john"}`, string(payload))
+
+	NewRequest(config, "GET", "url").
+		WithJWT(map[string]interface{}{"sub": "john"}, 123).
+		chain.assertFailed(t)
+
+	NewRequest(config, "GET", "url").
+		WithJWT(nil, []byte("secret")).
+		chain.assertFailed(t)
+}
+
+func TestRequestAsCurl(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	client := &mockClient{}
+
+	reporter := newMockReporter(t)
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         client,
+		Reporter:       reporter,
+		BaseURL:        "http://example.com",
+	}
+
+	t.Run("body", func(t *testing.T) {
+		req := NewRequest(config, "PUT", "/path").
+			WithQuery("a", 1).
+			WithHeader("X-Foo", "bar").
+			WithText("hello").
+			WithTransformer(func(r *http.Request) {
+				r.Header.Add("X-Transformed", "1")
+			})
+
+		cmd := req.AsCurl()
+		req.chain.assertOK(t)
+
+		assert.Contains(t, cmd, "curl -X 'PUT'")
+		assert.Contains(t, cmd, "-d 'hello'")
+		assert.Contains(t, cmd, "-H 'X-Foo: bar'")
+		assert.Contains(t, cmd, "-H 'X-Transformed: 1'")
+		assert.Contains(t, cmd, "'http://example.com/path?a=1'")
+
+		// request can be still sent, transformers are not applied twice
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "hello", string(resp.content))
+		assert.Equal(t, []string{"1"}, client.req.Header["X-Transformed"])
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		req := NewRequest(config, "PUT", "/path").
+			WithBodyStream(strings.NewReader("hello"))
+
+		cmd := req.AsCurl()
+		req.chain.assertOK(t)
+
+		assert.NotContains(t, cmd, "hello")
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+		assert.Equal(t, "hello", string(resp.content))
+	})
+}
+
+func TestRequestBuild(t *testing.T) {
+	client := &mockClient{}
+
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         client,
+		Reporter:       newMockReporter(t),
+		BaseURL:        "http://example.com",
+	}
+
+	t.Run("body", func(t *testing.T) {
+		req := NewRequest(config, "PUT", "/path/{id}", 1).
+			WithQuery("a", 1).
+			WithHeader("X-Foo", "bar").
+			WithText("hello").
+			WithTransformer(func(r *http.Request) {
+				r.Header.Add("X-Transformed", "1")
+			})
+
+		httpReq, err := req.Build()
+		req.chain.assertOK(t)
+
+		require.NoError(t, err)
+		require.NotNil(t, httpReq)
+
+		assert.Equal(t, "PUT", httpReq.Method)
+		assert.Equal(t, "http://example.com/path/1?a=1", httpReq.URL.String())
+		assert.Equal(t, "bar", httpReq.Header.Get("X-Foo"))
+		assert.Equal(t, []string{"1"}, httpReq.Header["X-Transformed"])
+
+		b, _ := ioutil.ReadAll(httpReq.Body)
+		assert.Equal(t, "hello", string(b))
+
+		rd, err := httpReq.GetBody()
+		require.NoError(t, err)
+		b, _ = ioutil.ReadAll(rd)
+		assert.Equal(t, "hello", string(b))
+
+		// built request is a copy
+		httpReq.Header.Set("X-Foo", "baz")
+
+		// request can be still sent, transformers are not applied twice
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		assert.Equal(t, "hello", string(resp.content))
+		assert.Equal(t, "bar", client.req.Header.Get("X-Foo"))
+		assert.Equal(t, []string{"1"}, client.req.Header["X-Transformed"])
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		req := NewRequest(config, "GET", "/path")
+
+		httpReq, err := req.Build()
+		req.chain.assertOK(t)
+
+		require.NoError(t, err)
+
+		b, _ := ioutil.ReadAll(httpReq.Body)
+		assert.Empty(t, b)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		req := NewRequest(config, "GET", "/path").
+			WithMaxRetries(1).
+			WithBodyStream(strings.NewReader("hello"))
+
+		httpReq, err := req.Build()
+		req.chain.assertFailed(t)
+
+		assert.Nil(t, httpReq)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "WithMaxRetries()")
+	})
+}
+
+func TestRequestDryRun(t *testing.T) {
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         &mockClient{},
+		Reporter:       newMockReporter(t),
+		BaseURL:        "http://example.com",
+	}
+
+	t.Run("json", func(t *testing.T) {
+		req := NewRequest(config, "POST", "/users").
+			WithQuery("notify", false).
+			WithHost("api.example.com").
+			WithJSON(map[string]interface{}{"name": "john"})
+
+		prepared := req.DryRun()
+		prepared.chain.assertOK(t)
+
+		require.NotNil(t, prepared.Raw())
+
+		prepared.Method().Equal("POST").chain.assertOK(t)
+		prepared.URL().Equal("http://example.com/users?notify=false").chain.assertOK(t)
+		prepared.Path().Equal("/users").chain.assertOK(t)
+		prepared.Query("notify").Equal("false").chain.assertOK(t)
+		prepared.Header("Host").Equal("api.example.com").chain.assertOK(t)
+		prepared.Header("content-type").
+			Equal("application/json; charset=utf-8").chain.assertOK(t)
+		prepared.Headers().ContainsKey("Content-Type").chain.assertOK(t)
+		prepared.Body().Equal(`{"name":"john"}`).chain.assertOK(t)
+		prepared.JSON().Object().ValueEqual("name", "john").chain.assertOK(t)
+
+		wire := string(prepared.Wire().Raw())
+
+		assert.True(t, strings.HasPrefix(wire, "POST /users?notify=false HTTP/1.1\r\n"))
+		assert.Contains(t, wire, "Host: api.example.com\r\n")
+		assert.True(t, strings.HasSuffix(wire, `{"name":"john"}`))
+
+		prepared.Query("missing").chain.assertFailed(t)
+	})
+
+	t.Run("form", func(t *testing.T) {
+		req := NewRequest(config, "POST", "/users").
+			WithFormField("name", "john")
+
+		prepared := req.DryRun()
+		prepared.chain.assertOK(t)
+
+		prepared.Body().Equal("name=john").chain.assertOK(t)
+		prepared.JSON().chain.assertFailed(t)
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		req := NewRequest(config, "GET", "/users")
+
+		prepared := req.DryRun()
+		prepared.chain.assertOK(t)
+
+		prepared.Body().Empty().chain.assertOK(t)
+
+		wire := string(prepared.Wire().Raw())
+
+		assert.True(t, strings.HasPrefix(wire, "GET /users HTTP/1.1\r\n"))
+		assert.NotContains(t, wire, "Transfer-Encoding")
+		assert.True(t, strings.HasSuffix(wire, "\r\n\r\n"))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		req := NewRequest(config, "GET", "/path").
+			WithMaxRetries(1).
+			WithBodyStream(strings.NewReader("hello"))
+
+		prepared := req.DryRun()
+		prepared.chain.assertFailed(t)
+
+		assert.Nil(t, prepared.Raw())
+	})
+}
+
+func TestRequestBuildersAfterPrepare(t *testing.T) {
+	config := Config{
+		RequestFactory: DefaultRequestFactory{},
+		Client:         &mockClient{},
+		Reporter:       newMockReporter(t),
+		BaseURL:        "http://example.com",
+	}
+
+	cases := []struct {
+		name    string
+		prepare func(req *Request)
+	}{
+		{"AsCurl", func(req *Request) { req.AsCurl() }},
+		{"Build", func(req *Request) { _, _ = req.Build() }},
+		{"DryRun", func(req *Request) { req.DryRun() }},
+		{"Expect", func(req *Request) { req.Expect() }},
+		{"Repeat", func(req *Request) { req.Repeat(1) }},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := NewRequest(config, "GET", "/path").
+				WithHeader("X-Foo", "1")
+
+			tc.prepare(req)
+			req.chain.assertOK(t)
+
+			req.WithName("name")
+			req.chain.assertOK(t)
+
+			req.WithHeader("X-Bar", "2")
+			req.chain.assertFailed(t)
+			req.chain.reset()
+
+			req.WithQuery("q", "2")
+			req.chain.assertFailed(t)
+			req.chain.reset()
+
+			assert.Equal(t, "", req.httpReq.Header.Get("X-Bar"))
+			assert.Equal(t, "", req.httpReq.URL.RawQuery)
+		})
+	}
+
+	t.Run("Expect after DryRun", func(t *testing.T) {
+		client := &mockClient{}
+
+		config := config
+		config.Client = client
+
+		req := NewRequest(config, "GET", "/path").
+			WithQuery("q", "1")
+
+		req.DryRun().chain.assertOK(t)
+
+		resp := req.Expect()
+		resp.chain.assertOK(t)
+
+		require.NotNil(t, client.req)
+		assert.Equal(t, "q=1", client.req.URL.RawQuery)
+	})
+}
+
+func TestRequestRepeat(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	reporter := newMockReporter(t)
+
+	t.Run("sequential", func(t *testing.T) {
+		var bodies []string
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+			if len(bodies) == 1 {
+				w.WriteHeader(http.StatusCreated)
+			} else {
+				w.WriteHeader(http.StatusOK)
+			}
+		})
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         &http.Client{Transport: NewBinder(handler)},
+			Reporter:       reporter,
+		}
+
+		var matched int
+
+		req := NewRequest(config, "PUT", "/path").
+			WithText("hello").
+			WithMatcher(func(*Response) {
+				matched++
+			})
+
+		resps := req.Repeat(3)
+		req.chain.assertOK(t)
+
+		require.Equal(t, 3, len(resps))
+		resps[0].Status(http.StatusCreated)
+		resps[1].Status(http.StatusOK)
+		resps[2].Status(http.StatusOK)
+
+		assert.Equal(t, []string{"hello", "hello", "hello"}, bodies)
+		assert.Equal(t, 3, matched)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		var mu sync.Mutex
+		var bodies []string
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := ioutil.ReadAll(r.Body)
+			mu.Lock()
+			bodies = append(bodies, string(b))
+			mu.Unlock()
+		})
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         &http.Client{Transport: NewBinder(handler)},
+			Reporter:       reporter,
+		}
+
+		req := NewRequest(config, "POST", "/path").
+			WithText("hello")
+
+		resps := req.RepeatConcurrently(10)
+		req.chain.assertOK(t)
+
+		require.Equal(t, 10, len(resps))
+		for _, resp := range resps {
+			resp.chain.assertOK(t)
+			resp.Status(http.StatusOK)
+		}
+
+		assert.Equal(t, 10, len(bodies))
+		for _, b := range bodies {
+			assert.Equal(t, "hello", b)
+		}
+	})
+
+	t.Run("invalid count", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &mockClient{},
+			Reporter:       reporter,
+		}
+
+		req := NewRequest(config, "GET", "/path")
+
+		resps := req.Repeat(0)
+		req.chain.assertFailed(t)
+		assert.Equal(t, 0, len(resps))
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &mockClient{},
+			Reporter:       newMockReporter(t),
+		}
+
+		req := NewRequest(config, "PUT", "/path").
+			WithBodyStream(strings.NewReader("hello"))
+
+		resps := req.Repeat(2)
+		req.chain.assertFailed(t)
+
+		require.Equal(t, 2, len(resps))
+		resps[0].chain.assertFailed(t)
+		resps[1].chain.assertFailed(t)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &mockClient{err: errors.New("error")},
+			Reporter:       newMockReporter(t),
+		}
+
+		req := NewRequest(config, "GET", "/path")
+
+		resps := req.Repeat(2)
+		req.chain.assertFailed(t)
+
+		require.Equal(t, 2, len(resps))
+		resps[0].chain.assertFailed(t)
+		resps[1].chain.assertFailed(t)
+	})
+}
+
+func TestRequestCache(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	var hits int
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+
+		switch r.URL.Path {
+		case "/fresh":
+			w.Header().Set("Cache-Control", "max-age=60")
+		case "/etag":
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		case "/no-store":
+			w.Header().Set("Cache-Control", "no-store")
+		}
+
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	newConfig := func(reporter Reporter) Config {
+		return Config{
+			RequestFactory: factory,
+			Client:         &http.Client{Transport: NewBinder(handler)},
+			Reporter:       reporter,
+			CacheStore:     NewMemoryCacheStore(),
+		}
+	}
+
+	t.Run("fresh", func(t *testing.T) {
+		config := newConfig(NewAssertReporter(t))
+		hits = 0
+
+		resp1 := NewRequest(config, "GET", "/fresh").Expect()
+		resp1.chain.assertOK(t)
+		resp1.NotFromCache()
+
+		resp2 := NewRequest(config, "GET", "/fresh").Expect()
+		resp2.chain.assertOK(t)
+		resp2.FromCache()
+		resp2.Status(http.StatusOK)
+		resp2.Body().Equal("hello")
+		resp2.Header("Age").Equal("0")
+
+		assert.Equal(t, 1, hits)
+	})
+
+	t.Run("revalidate", func(t *testing.T) {
+		config := newConfig(NewAssertReporter(t))
+		hits = 0
+
+		NewRequest(config, "GET", "/etag").Expect().NotFromCache()
+
+		resp := NewRequest(config, "GET", "/etag").Expect()
+		resp.chain.assertOK(t)
+		resp.FromCache()
+		resp.Status(http.StatusOK)
+		resp.Body().Equal("hello")
+
+		assert.Equal(t, 2, hits)
+	})
+
+	t.Run("no-store", func(t *testing.T) {
+		config := newConfig(NewAssertReporter(t))
+		hits = 0
+
+		NewRequest(config, "GET", "/no-store").Expect().NotFromCache()
+		NewRequest(config, "GET", "/no-store").Expect().NotFromCache()
+
+		assert.Equal(t, 2, hits)
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		config := newConfig(NewAssertReporter(t))
+		hits = 0
+
+		NewRequest(config, "GET", "/fresh").Expect().NotFromCache()
+		NewRequest(config, "POST", "/fresh").Expect().NotFromCache()
+		NewRequest(config, "GET", "/fresh").Expect().NotFromCache()
+
+		assert.Equal(t, 3, hits)
+	})
+
+	t.Run("per-request", func(t *testing.T) {
+		config := newConfig(NewAssertReporter(t))
+		config.CacheStore = nil
+		hits = 0
+
+		store := NewMemoryCacheStore()
+
+		NewRequest(config, "GET", "/fresh").Expect().NotFromCache()
+		NewRequest(config, "GET", "/fresh").WithCache(store).Expect().NotFromCache()
+		NewRequest(config, "GET", "/fresh").WithCache(store).Expect().FromCache()
+		NewRequest(config, "GET", "/fresh").Expect().NotFromCache()
+
+		assert.Equal(t, 3, hits)
+	})
+
+	t.Run("nil store", func(t *testing.T) {
+		req := NewRequest(newConfig(newMockReporter(t)), "GET", "/fresh").
+			WithCache(nil)
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("assertions", func(t *testing.T) {
+		config := newConfig(newMockReporter(t))
+
+		resp := NewRequest(config, "GET", "/fresh").Expect()
+		resp.FromCache()
+		resp.chain.assertFailed(t)
+
+		resp = NewRequest(config, "GET", "/fresh").Expect()
+		resp.NotFromCache()
+		resp.chain.assertFailed(t)
+	})
+}
+
+func TestRequestTrailers(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+
+		w.Header().Set("Trailer", "X-Received")
+		_, _ = w.Write(b)
+
+		w.Header().Set("X-Received", r.Trailer.Get("X-Checksum"))
+	})
+
+	config := Config{
+		RequestFactory: factory,
+		Client:         &http.Client{Transport: NewBinder(handler)},
+		Reporter:       NewAssertReporter(t),
+	}
+
+	t.Run("body", func(t *testing.T) {
+		req := NewRequest(config, "PUT", "/path").
+			WithText("hello").
+			WithTrailer("X-Checksum", "123")
+
+		resp := req.Expect()
+
+		assert.Equal(t, int64(-1), req.httpReq.ContentLength)
+
+		resp.Body().Equal("hello")
+		resp.Trailer("X-Received").Equal("123")
+		resp.Trailers().Equal(map[string][]string{
+			"X-Received": {"123"},
+		})
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		req := NewRequest(config, "POST", "/path").
+			WithTrailer("X-Checksum", "123")
+
+		resp := req.Expect()
+
+		assert.Equal(t, int64(-1), req.httpReq.ContentLength)
+
+		resp.Body().Empty()
+		resp.Trailer("X-Received").Equal("123")
+	})
+}
+
+func TestRequestProtocol(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	t.Run("invalid", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &mockClient{},
+			Reporter:       newMockReporter(t),
+		}
+
+		req := NewRequest(config, "GET", "/path").
+			WithProtocol(Protocol(100))
+
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("unsupported client", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &mockClient{},
+			Reporter:       newMockReporter(t),
+		}
+
+		req := NewRequest(config, "GET", "/path").
+			WithProtocol(ProtocolHTTP2)
+
+		req.chain.assertOK(t)
+
+		req.Expect().chain.assertFailed(t)
+	})
+
+	t.Run("auto", func(t *testing.T) {
+		client := &mockClient{}
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       newMockReporter(t),
+			Protocol:       ProtocolHTTP1,
+		}
+
+		req := NewRequest(config, "GET", "/path").
+			WithProtocol(ProtocolAuto)
+
+		req.Expect().chain.assertOK(t)
+		assert.NotNil(t, client.req)
+	})
+}
+
+func TestRequestProxy(t *testing.T) {
+	factory := DefaultRequestFactory{}
+
+	t.Run("invalid", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &mockClient{},
+			Reporter:       newMockReporter(t),
+		}
+
+		req := NewRequest(config, "GET", "/path").
+			WithProxy("ftp://localhost:21")
+
+		req.chain.assertFailed(t)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &http.Client{},
+			Reporter:       newMockReporter(t),
+			Proxy:          "localhost:3128",
+		}
+
+		req := NewRequest(config, "GET", "/path")
+
+		req.Expect().chain.assertFailed(t)
+	})
+
+	t.Run("unsupported client", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &mockClient{},
+			Reporter:       newMockReporter(t),
+		}
+
+		req := NewRequest(config, "GET", "/path").
+			WithProxy("http://localhost:3128")
+
+		req.chain.assertOK(t)
+
+		req.Expect().chain.assertFailed(t)
+	})
+
+	t.Run("unsupported protocol", func(t *testing.T) {
+		config := Config{
+			RequestFactory: factory,
+			Client:         &http.Client{},
+			Reporter:       newMockReporter(t),
+		}
+
+		req := NewRequest(config, "GET", "/path").
+			WithProxy("http://localhost:3128").
+			WithProtocol(ProtocolH2C)
+
+		req.chain.assertOK(t)
+
+		req.Expect().chain.assertFailed(t)
+	})
+
+	t.Run("disable", func(t *testing.T) {
+		client := &mockClient{}
+
+		config := Config{
+			RequestFactory: factory,
+			Client:         client,
+			Reporter:       newMockReporter(t),
+			Proxy:          "http://localhost:3128",
+		}
+
+		resp := NewRequest(config, "GET", "/path").
+			WithProxy("").
+			Expect()
+
+		resp.chain.assertOK(t)
+		assert.NotNil(t, client.req)
+
+		resp.NotViaProxy()
+		resp.chain.assertOK(t)
+	})
+}
@@ -0,0 +1,83 @@
+package httpexpect
+
+import (
+	"context"
+	"net"
+
+	"github.com/gorilla/websocket"
+)
+
+// returns copy of client that dials addresses from resolve map instead of
+// requested ones; client is returned as is if it doesn't dial by itself
+func resolveClient(client Client, resolve map[string]string) Client {
+	clientCopy, transport := cloneTransport(client)
+	if transport == nil {
+		return client
+	}
+
+	transport.DialContext = resolveDialer(transport.DialContext, resolve)
+
+	if transport.DialTLSContext != nil {
+		transport.DialTLSContext = resolveDialer(transport.DialTLSContext, resolve)
+	}
+
+	return clientCopy
+}
+
+// returns copy of websocket dialer that dials addresses from resolve map
+func resolveWebsocketDialer(
+	dialer WebsocketDialer, resolve map[string]string,
+) WebsocketDialer {
+	wsDialer, ok := dialer.(*websocket.Dialer)
+	if !ok {
+		return dialer
+	}
+
+	dial := wsDialer.NetDialContext
+	if dial == nil && wsDialer.NetDial != nil {
+		netDial := wsDialer.NetDial
+		dial = func(_ context.Context, network, addr string) (net.Conn, error) {
+			return netDial(network, addr)
+		}
+	}
+
+	dialerCopy := *wsDialer
+	dialerCopy.NetDialContext = resolveDialer(dial, resolve)
+
+	return &dialerCopy
+}
+
+type dialFunc = func(ctx context.Context, network, addr string) (net.Conn, error)
+
+func resolveDialer(dial dialFunc, resolve map[string]string) dialFunc {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dial(ctx, network, resolveAddr(addr, resolve))
+	}
+}
+
+// maps "host:port" to address from resolve map; keys may be either
+// "host:port" or "host", and values may be either "addr:port" or "addr"
+func resolveAddr(addr string, resolve map[string]string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	target, ok := resolve[addr]
+	if !ok {
+		target, ok = resolve[host]
+	}
+	if !ok {
+		return addr
+	}
+
+	if _, _, err := net.SplitHostPort(target); err == nil {
+		return target
+	}
+
+	return net.JoinHostPort(target, port)
+}
@@ -0,0 +1,62 @@
+package httpexpect
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolverAddr(t *testing.T) {
+	resolve := map[string]string{
+		"example.com:443": "127.0.0.1:8443",
+		"example.com":     "127.0.0.2",
+		"example.org":     "127.0.0.3:9000",
+	}
+
+	assert.Equal(t, "127.0.0.1:8443", resolveAddr("example.com:443", resolve))
+	assert.Equal(t, "127.0.0.2:80", resolveAddr("example.com:80", resolve))
+	assert.Equal(t, "127.0.0.3:9000", resolveAddr("example.org:80", resolve))
+	assert.Equal(t, "example.net:80", resolveAddr("example.net:80", resolve))
+	assert.Equal(t, "bad", resolveAddr("bad", resolve))
+}
+
+func TestResolverClient(t *testing.T) {
+	resolve := map[string]string{
+		"example.com": "127.0.0.1",
+	}
+
+	t.Run("nil transport", func(t *testing.T) {
+		client := &http.Client{}
+
+		resolved := resolveClient(client, resolve)
+
+		assert.True(t, client != resolved)
+		assert.Nil(t, client.Transport)
+		assert.IsType(t, &http.Transport{}, resolved.(*http.Client).Transport)
+	})
+
+	t.Run("http transport", func(t *testing.T) {
+		transport := &http.Transport{}
+		client := &http.Client{Transport: transport}
+
+		resolved := resolveClient(client, resolve)
+
+		assert.True(t, client != resolved)
+		assert.True(t, transport != resolved.(*http.Client).Transport)
+		assert.Nil(t, transport.DialContext)
+		assert.NotNil(t, resolved.(*http.Client).Transport.(*http.Transport).DialContext)
+	})
+
+	t.Run("custom transport", func(t *testing.T) {
+		client := &http.Client{Transport: NewBinder(http.NotFoundHandler())}
+
+		assert.Same(t, client, resolveClient(client, resolve))
+	})
+
+	t.Run("custom client", func(t *testing.T) {
+		client := &mockClient{}
+
+		assert.Same(t, client, resolveClient(client, resolve))
+	})
+}
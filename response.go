@@ -2,33 +2,60 @@ package httpexpect
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"mime"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"path"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/ajg/form"
 	"github.com/gorilla/websocket"
+	"github.com/yalp/jsonpath"
 )
 
 // Response provides methods to inspect attached http.Response object.
 type Response struct {
 	config Config
 	chain  *chain
+	expect *Expect
 
 	httpResp  *http.Response
 	websocket *websocket.Conn
+	wsWire    *websocketWire
+	wsDial    *websocketDial
 	rtt       *time.Duration
-
-	content []byte
-	cookies []*http.Cookie
+	fromCache bool
+	proxy     *url.URL
+	jsonrpc   *jsonrpcRequest
+	sentReq   *sentRequest
+	conn      *httptrace.GotConnInfo
+	upload    *uploadRecorder
+
+	content    []byte
+	rawContent []byte
+	wireSize   int64
+	cookies    []*http.Cookie
+
+	// header and decoded body before Config.Sanitizers were applied
+	origHeader  http.Header
+	origContent []byte
+
+	stream     io.ReadCloser
+	bodyStream *BodyStream
+	sseStream  *SSEStream
 }
 
 // NewResponse returns a new Response instance.
@@ -57,15 +84,28 @@ func NewResponse(
 type responseOpts struct {
 	config    Config
 	chain     *chain
+	expect    *Expect
 	httpResp  *http.Response
 	websocket *websocket.Conn
+	wsWire    *websocketWire
+	wsDial    *websocketDial
 	rtt       []time.Duration
+	fromCache bool
+	proxy     *url.URL
+	sentReq   *sentRequest
+	conn      *httptrace.GotConnInfo
+	upload    *uploadRecorder
+	stream    bool
+
+	// verify that Content-Length matches body
+	checkLength bool
 }
 
 func newResponse(opts responseOpts) *Response {
 	r := &Response{
 		config: opts.config,
 		chain:  opts.chain.clone(),
+		expect: opts.expect,
 	}
 
 	if opts.httpResp == nil {
@@ -91,8 +131,42 @@ func newResponse(opts responseOpts) *Response {
 
 	r.httpResp = opts.httpResp
 	r.websocket = opts.websocket
+	r.wsWire = opts.wsWire
+	r.wsDial = opts.wsDial
+	r.fromCache = opts.fromCache
+	r.proxy = opts.proxy
+	r.sentReq = opts.sentReq
+	r.conn = opts.conn
+	r.upload = opts.upload
+
+	if opts.stream && r.httpResp.Body != nil {
+		r.stream = r.httpResp.Body
+		r.rawContent = []byte{}
+		r.content = []byte{}
+	} else {
+		r.rawContent = getContent(r.chain, r.httpResp)
+		r.content = decodeResponseContent(r.chain, r.httpResp, r.rawContent)
+	}
+
+	if opts.checkLength && r.stream == nil {
+		r.checkContentLength()
+	}
+
+	r.wireSize = responseWireSize(r.httpResp, r.rawContent)
+
+	if r.config.MaxResponseSize > 0 && r.stream == nil {
+		r.checkMaxSize()
+	}
+
+	r.origHeader = r.httpResp.Header
+	r.origContent = r.content
+
+	if len(r.config.Sanitizers) != 0 {
+		r.httpResp.Header = sanitizeHeader(r.config.Sanitizers, r.httpResp.Header)
+		r.content = sanitizeBody(r.config.Sanitizers,
+			r.httpResp.Header.Get("Content-Type"), r.content)
+	}
 
-	r.content = getContent(r.chain, r.httpResp)
 	r.cookies = r.httpResp.Cookies()
 
 	if len(opts.rtt) > 0 {
@@ -101,6 +175,7 @@ func newResponse(opts responseOpts) *Response {
 	}
 
 	r.chain.setResponse(r)
+	r.chain.setRoundTripTime(r.rtt)
 
 	return r
 }
@@ -122,6 +197,18 @@ func getContent(chain *chain, resp *http.Response) []byte {
 	}
 
 	if err != nil {
+		if defect := bodyReadDefect(resp, content, err); defect != nil {
+			chain.fail(AssertionFailure{
+				Type: AssertValid,
+				Errors: []error{
+					errors.New("failed to read response body"),
+					defect,
+					err,
+				},
+			})
+			return nil
+		}
+
 		chain.fail(AssertionFailure{
 			Type: AssertOperation,
 			Errors: []error{
@@ -135,6 +222,109 @@ func getContent(chain *chain, resp *http.Response) []byte {
 	return content
 }
 
+// returns protocol-level defect explaining body read error, if any
+func bodyReadDefect(resp *http.Response, content []byte, err error) error {
+	chunked := len(resp.TransferEncoding) != 0 &&
+		strings.EqualFold(resp.TransferEncoding[0], "chunked")
+
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF) && chunked:
+		return errors.New("chunked body is not terminated with last chunk")
+
+	case errors.Is(err, io.ErrUnexpectedEOF) && resp.ContentLength > 0:
+		n := len(content)
+		if bw, ok := resp.Body.(*bodyWrapper); ok {
+			n = bw.readLen()
+		}
+
+		return fmt.Errorf(
+			"connection closed after %d of %d bytes declared in Content-Length",
+			n, resp.ContentLength)
+
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return errors.New("connection closed in the middle of body")
+
+	case chunked && strings.Contains(err.Error(), "chunk"):
+		return errors.New("malformed chunked encoding")
+	}
+
+	return nil
+}
+
+// verifies that Content-Length header matches body size
+func (r *Response) checkContentLength() {
+	if r.chain.failed() {
+		return
+	}
+
+	if r.httpResp.Request != nil && r.httpResp.Request.Method == http.MethodHead {
+		return
+	}
+
+	if code := r.httpResp.StatusCode; (code >= 100 && code < 200) ||
+		code == http.StatusNoContent || code == http.StatusNotModified {
+		return
+	}
+
+	value := r.httpResp.Header.Get("Content-Length")
+	if value == "" {
+		return
+	}
+
+	if len(r.httpResp.TransferEncoding) != 0 {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{r.httpResp.TransferEncoding},
+			Errors: []error{
+				errors.New("response has both Content-Length and Transfer-Encoding"),
+			},
+		})
+		return
+	}
+
+	length, err := strconv.ParseUint(strings.TrimSpace(value), 10, 63)
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{value},
+			Errors: []error{
+				errors.New(`invalid "Content-Length" response header`),
+				err,
+			},
+		})
+		return
+	}
+
+	if uint64(len(r.rawContent)) != length {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{len(r.rawContent)},
+			Expected: &AssertionValue{int(length)},
+			Errors: []error{
+				errors.New("expected: body size matches Content-Length header"),
+			},
+		})
+	}
+}
+
+// decodes content according to Content-Encoding header
+func decodeResponseContent(chain *chain, resp *http.Response, content []byte) []byte {
+	decoded, _, err := decodeContent(resp.Header, content)
+	if err != nil {
+		chain.fail(AssertionFailure{
+			Type:   AssertOperation,
+			Actual: &AssertionValue{resp.Header["Content-Encoding"]},
+			Errors: []error{
+				errors.New("failed to decode response body"),
+				err,
+			},
+		})
+		return nil
+	}
+
+	return decoded
+}
+
 // Raw returns underlying http.Response object.
 // This is the value originally passed to NewResponse.
 func (r *Response) Raw() *http.Response {
@@ -147,6 +337,10 @@ func (r *Response) Raw() *http.Response {
 // sent and ending right after response is received (handshake finished for
 // WebSocket request), retrieved from a monotonic clock source.
 //
+// The same value is also available to AssertionHandler and Formatter via
+// AssertionContext.RoundTripTime. If request was retried, this is the time
+// of the last attempt.
+//
 // Example:
 //
 //	resp := NewResponse(t, response, time.Duration(10000000))
@@ -198,6 +392,83 @@ func (r *Response) Status(status int) *Response {
 	return r
 }
 
+// StatusList succeeds if response contains one of given status codes.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.StatusList(http.StatusOK, http.StatusCreated, http.StatusAccepted)
+func (r *Response) StatusList(codes ...int) *Response {
+	r.chain.enter("StatusList()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	if len(codes) == 0 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected empty status list"),
+			},
+		})
+		return r
+	}
+
+	expected := make(AssertionList, 0, len(codes))
+
+	for _, code := range codes {
+		if code == r.httpResp.StatusCode {
+			return r
+		}
+		expected = append(expected, statusCodeText(code))
+	}
+
+	r.chain.fail(AssertionFailure{
+		Type:     AssertBelongs,
+		Actual:   &AssertionValue{statusCodeText(r.httpResp.StatusCode)},
+		Expected: &AssertionValue{expected},
+		Errors: []error{
+			errors.New("expected: http status belongs to given list"),
+		},
+	})
+
+	return r
+}
+
+// StatusText returns a new String instance with reason phrase of
+// response status line, e.g. "Not Found".
+//
+// If response has no status line (e.g. it was constructed manually),
+// standard reason phrase for status code is used.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.StatusText().Equal("Not Found")
+func (r *Response) StatusText() *String {
+	r.chain.enter("StatusText()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newString(r.chain, "")
+	}
+
+	text := http.StatusText(r.httpResp.StatusCode)
+
+	if status := r.httpResp.Status; status != "" {
+		prefix := strconv.Itoa(r.httpResp.StatusCode)
+		if strings.HasPrefix(status, prefix) {
+			text = strings.TrimSpace(status[len(prefix):])
+		} else {
+			text = status
+		}
+	}
+
+	return newString(r.chain, text)
+}
+
 // StatusRange is enum for response status ranges.
 type StatusRange int
 
@@ -241,25 +512,142 @@ func (r *Response) StatusRange(rn StatusRange) *Response {
 		return r
 	}
 
-	status := statusCodeText(r.httpResp.StatusCode)
+	r.checkStatusRange(rn)
+
+	return r
+}
+
+// IsSuccess succeeds if response status is 2xx (Success).
+// Same as StatusRange(Status2xx).
+//
+// On failure, reports actual status and beginning of response body.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.IsSuccess()
+func (r *Response) IsSuccess() *Response {
+	r.chain.enter("IsSuccess()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	r.checkStatusRange(Status2xx)
+
+	return r
+}
+
+// IsRedirect succeeds if response status is 3xx (Redirection).
+// Same as StatusRange(Status3xx).
+//
+// On failure, reports actual status and beginning of response body.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.IsRedirect()
+func (r *Response) IsRedirect() *Response {
+	r.chain.enter("IsRedirect()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	r.checkStatusRange(Status3xx)
+
+	return r
+}
+
+// IsClientError succeeds if response status is 4xx (Client Error).
+// Same as StatusRange(Status4xx).
+//
+// On failure, reports actual status and beginning of response body.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.IsClientError()
+func (r *Response) IsClientError() *Response {
+	r.chain.enter("IsClientError()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	r.checkStatusRange(Status4xx)
+
+	return r
+}
+
+// IsServerError succeeds if response status is 5xx (Server Error).
+// Same as StatusRange(Status5xx).
+//
+// On failure, reports actual status and beginning of response body.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.IsServerError()
+func (r *Response) IsServerError() *Response {
+	r.chain.enter("IsServerError()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	r.checkStatusRange(Status5xx)
 
+	return r
+}
+
+func (r *Response) checkStatusRange(rn StatusRange) {
 	actual := statusRangeText(r.httpResp.StatusCode)
 	expected := statusRangeText(int(rn))
 
-	if actual == "" || actual != expected {
-		r.chain.fail(AssertionFailure{
-			Type:   AssertBelongs,
-			Actual: &AssertionValue{status},
-			Expected: &AssertionValue{AssertionList{
-				statusRangeText(int(rn)),
-			}},
-			Errors: []error{
-				errors.New("expected: http status belongs to given range"),
-			},
-		})
+	if actual != "" && actual == expected {
+		return
 	}
 
-	return r
+	errs := []error{
+		errors.New("expected: http status belongs to given range"),
+	}
+
+	if len(r.content) != 0 {
+		errs = append(errs,
+			fmt.Errorf("response body: %s", bodySnippet(r.content)))
+	}
+
+	r.chain.fail(AssertionFailure{
+		Type:   AssertBelongs,
+		Actual: &AssertionValue{statusCodeText(r.httpResp.StatusCode)},
+		Expected: &AssertionValue{AssertionList{
+			expected,
+		}},
+		Errors: errs,
+	})
+}
+
+// max number of body bytes included into status failure
+const bodySnippetLimit = 200
+
+func bodySnippet(content []byte) string {
+	if len(content) <= bodySnippetLimit {
+		return strconv.Quote(string(content))
+	}
+
+	// don't split multi-byte UTF-8 character at the cut point
+	n := bodySnippetLimit
+	for i := 0; i < utf8.UTFMax-1 && n > 0 && !utf8.RuneStart(content[n]); i++ {
+		n--
+	}
+
+	return fmt.Sprintf("%q... (%d bytes total)",
+		string(content[:n]), len(content))
 }
 
 func statusCodeText(code int) string {
@@ -286,270 +674,1980 @@ func statusRangeText(code int) string {
 	}
 }
 
-// Headers returns a new Object instance with response header map.
+// FromCache succeeds if response was served from client-side cache.
+//
+// Response is served from cache if stored response was fresh, or if it
+// was successfully revalidated with server. See Request.WithCache.
 //
 // Example:
 //
-//	resp := NewResponse(t, response)
-//	resp.Headers().Value("Content-Type").String().Equal("application-json")
-func (r *Response) Headers() *Object {
-	r.chain.enter("Headers()")
+//	req := NewRequest(config, "GET", "/path")
+//	req.WithCache(store)
+//	req.Expect().FromCache()
+func (r *Response) FromCache() *Response {
+	r.chain.enter("FromCache()")
 	defer r.chain.leave()
 
 	if r.chain.failed() {
-		return newObject(r.chain, nil)
+		return r
 	}
 
-	var value map[string]interface{}
-	value, _ = canonMap(r.chain, r.httpResp.Header)
+	if !r.fromCache {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{false},
+			Expected: &AssertionValue{true},
+			Errors: []error{
+				errors.New("expected: response is served from cache"),
+			},
+		})
+	}
 
-	return newObject(r.chain, value)
+	return r
 }
 
-// Header returns a new String instance with given header field.
+// NotFromCache succeeds if response was not served from client-side cache.
 //
 // Example:
 //
-//	resp := NewResponse(t, response)
-//	resp.Header("Content-Type").Equal("application-json")
-//	resp.Header("Date").AsDateTime().Le(time.Now())
-func (r *Response) Header(header string) *String {
-	r.chain.enter("Header(%q)", header)
+//	req := NewRequest(config, "GET", "/path")
+//	req.WithCache(store)
+//	req.Expect().NotFromCache()
+func (r *Response) NotFromCache() *Response {
+	r.chain.enter("NotFromCache()")
 	defer r.chain.leave()
 
 	if r.chain.failed() {
-		return newString(r.chain, "")
+		return r
 	}
 
-	value := r.httpResp.Header.Get(header)
+	if r.fromCache {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{true},
+			Expected: &AssertionValue{false},
+			Errors: []error{
+				errors.New("expected: response is not served from cache"),
+			},
+		})
+	}
 
-	return newString(r.chain, value)
+	return r
 }
 
-// Cookies returns a new Array instance with all cookie names set by this response.
-// Returned Array contains a String value for every cookie name.
+// ViaProxy succeeds if request was sent via proxy.
 //
-// Note that this returns only cookies set by Set-Cookie headers of this response.
-// It doesn't return session cookies from previous responses, which may be stored
-// in a cookie jar.
+// Proxy is set using Config.Proxy or Request.WithProxy. Request is
+// considered sent via proxy if client selected proxy when sending it.
 //
 // Example:
 //
-//	resp := NewResponse(t, response)
-//	resp.Cookies().Contains("session")
-func (r *Response) Cookies() *Array {
-	r.chain.enter("Cookies()")
+//	req := NewRequest(config, "GET", "/path")
+//	req.WithProxy("http://localhost:3128")
+//	req.Expect().ViaProxy()
+func (r *Response) ViaProxy() *Response {
+	r.chain.enter("ViaProxy()")
 	defer r.chain.leave()
 
 	if r.chain.failed() {
-		return newArray(r.chain, nil)
+		return r
 	}
 
-	names := []interface{}{}
-	for _, c := range r.cookies {
-		names = append(names, c.Name)
+	if r.proxy == nil {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{false},
+			Expected: &AssertionValue{true},
+			Errors: []error{
+				errors.New("expected: request is sent via proxy"),
+			},
+		})
 	}
 
-	return newArray(r.chain, names)
+	return r
 }
 
-// Cookie returns a new Cookie instance with specified cookie from response.
-//
-// Note that this returns only cookies set by Set-Cookie headers of this response.
-// It doesn't return session cookies from previous responses, which may be stored
-// in a cookie jar.
+// NotViaProxy succeeds if request was not sent via proxy.
 //
 // Example:
 //
-//	resp := NewResponse(t, response)
-//	resp.Cookie("session").Domain().Equal("example.com")
-func (r *Response) Cookie(name string) *Cookie {
-	r.chain.enter("Cookie(%q)", name)
+//	req := NewRequest(config, "GET", "/path")
+//	req.Expect().NotViaProxy()
+func (r *Response) NotViaProxy() *Response {
+	r.chain.enter("NotViaProxy()")
 	defer r.chain.leave()
 
 	if r.chain.failed() {
-		return newCookie(r.chain, nil)
+		return r
 	}
 
-	var cookie *Cookie
+	if r.proxy != nil {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{proxyString(r.proxy)},
+			Expected: &AssertionValue{nil},
+			Errors: []error{
+				errors.New("expected: request is not sent via proxy"),
+			},
+		})
+	}
 
-	names := []string{}
-	for _, c := range r.cookies {
-		if c.Name == name {
-			cookie = newCookie(r.chain, c)
-			break
-		}
-		names = append(names, c.Name)
+	return r
+}
+
+// Connection returns a new Connection instance with info about connection
+// that was used to send request, e.g. whether it was reused, and its local
+// and remote addresses. If request was retried or redirected, connection
+// of the last attempt is returned.
+//
+// Connection fails if connection info is not available, e.g. if response
+// was served from cache, or if client doesn't use http.Transport.
+//
+// Example:
+//
+//	resp := e.GET("/path").Expect()
+//	resp.Connection().RemoteAddr().Equal(server.Listener.Addr().String())
+func (r *Response) Connection() *Connection {
+	r.chain.enter("Connection()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newConnection(r.chain, httptrace.GotConnInfo{})
 	}
 
-	if cookie == nil {
+	if r.conn == nil {
 		r.chain.fail(AssertionFailure{
-			Type:     AssertContainsElement,
-			Actual:   &AssertionValue{names},
-			Expected: &AssertionValue{name},
+			Type:   AssertNotNil,
+			Actual: &AssertionValue{r.conn},
 			Errors: []error{
-				errors.New("expected: response contains cookie with given name"),
+				errors.New("expected: connection info is available"),
 			},
 		})
-		return newCookie(r.chain, nil)
+		return newConnection(r.chain, httptrace.GotConnInfo{})
 	}
 
-	return cookie
+	return newConnection(r.chain, *r.conn)
 }
 
-// Websocket returns Websocket instance for interaction with WebSocket server.
+// UploadProgress returns a new UploadProgress instance that can be used
+// to check how streamed request body was delivered, e.g. whether it was
+// delivered progressively instead of being buffered.
 //
-// May be called only if the WithWebsocketUpgrade was called on the request.
-// That is responsibility of the caller to explicitly disconnect websocket after use.
+// UploadProgress fails if request body wasn't set using WithBodyStream
+// or WithFileStream.
 //
 // Example:
 //
-//	req := NewRequest(config, "GET", "/path")
-//	req.WithWebsocketUpgrade()
-//	ws := req.Expect().Websocket()
-//	defer ws.Disconnect()
-func (r *Response) Websocket() *Websocket {
-	r.chain.enter("Websocket()")
+//	resp := e.PUT("/upload").WithBodyStream(reader).Expect()
+//	resp.UploadProgress().Progressive()
+func (r *Response) UploadProgress() *UploadProgress {
+	r.chain.enter("UploadProgress()")
 	defer r.chain.leave()
 
 	if r.chain.failed() {
-		return newWebsocket(r.chain, r.config, nil)
+		return newUploadProgress(r.chain, nil)
 	}
 
-	if r.websocket == nil {
+	if r.upload == nil {
 		r.chain.fail(AssertionFailure{
 			Type: AssertUsage,
 			Errors: []error{
 				errors.New(
-					"Websocket() requires WithWebsocketUpgrade() to be called on request"),
+					"UploadProgress() requires WithBodyStream() or WithFileStream()"),
 			},
 		})
-		return newWebsocket(r.chain, r.config, nil)
+		return newUploadProgress(r.chain, nil)
 	}
 
-	return newWebsocket(r.chain, r.config, r.websocket)
+	return newUploadProgress(r.chain, r.upload)
 }
 
-// Body returns a new String instance with response body.
+// ConnectionReused returns a new Boolean instance that is true if request
+// was sent over connection reused from pool of keep-alive connections.
+//
+// It is a shorthand for Connection().Reused().
 //
 // Example:
 //
-//	resp := NewResponse(t, response)
-//	resp.Body().NotEmpty()
-//	resp.Body().Length().Equal(100)
-func (r *Response) Body() *String {
-	return newString(r.chain, string(r.content))
+//	e.GET("/path").Expect().ConnectionReused().False()
+//	e.GET("/path").Expect().ConnectionReused().True()
+func (r *Response) ConnectionReused() *Boolean {
+	r.chain.enter("ConnectionReused()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newBoolean(r.chain, false)
+	}
+
+	if r.conn == nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertNotNil,
+			Actual: &AssertionValue{r.conn},
+			Errors: []error{
+				errors.New("expected: connection info is available"),
+			},
+		})
+		return newBoolean(r.chain, false)
+	}
+
+	return newBoolean(r.chain, r.conn.Reused)
 }
 
-// NoContent succeeds if response contains empty Content-Type header and
-// empty body.
-func (r *Response) NoContent() *Response {
-	r.chain.enter("NoContent()")
+// KeepAlive succeeds if server allows to reuse connection for subsequent
+// requests, i.e. response doesn't have "Connection: close" header, and,
+// for HTTP/1.0, has "Connection: keep-alive" header.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.KeepAlive()
+func (r *Response) KeepAlive() *Response {
+	r.chain.enter("KeepAlive()")
 	defer r.chain.leave()
 
 	if r.chain.failed() {
 		return r
 	}
 
-	contentType := r.httpResp.Header.Get("Content-Type")
-
-	r.checkEqual(`"Content-Type" header`, "", contentType)
-	r.checkEqual("body", "", string(r.content))
+	if r.connectionClose() {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{false},
+			Expected: &AssertionValue{true},
+			Errors: []error{
+				errors.New("expected: server keeps connection alive"),
+			},
+		})
+	}
 
 	return r
 }
 
-// ContentType succeeds if response contains Content-Type header with given
-// media type and charset.
+// NotKeepAlive succeeds if server closes connection after response, i.e.
+// response has "Connection: close" header, or is HTTP/1.0 response without
+// "Connection: keep-alive" header.
 //
-// If charset is omitted, and mediaType is non-empty, Content-Type header
-// should contain empty or utf-8 charset.
+// Example:
 //
-// If charset is omitted, and mediaType is also empty, Content-Type header
-// should contain no charset.
-func (r *Response) ContentType(mediaType string, charset ...string) *Response {
-	r.chain.enter("ContentType()")
+//	resp := e.GET("/path").WithHeader("Connection", "close").Expect()
+//	resp.NotKeepAlive()
+func (r *Response) NotKeepAlive() *Response {
+	r.chain.enter("NotKeepAlive()")
 	defer r.chain.leave()
 
 	if r.chain.failed() {
 		return r
 	}
 
-	if len(charset) > 1 {
+	if !r.connectionClose() {
 		r.chain.fail(AssertionFailure{
-			Type: AssertUsage,
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{true},
+			Expected: &AssertionValue{false},
 			Errors: []error{
-				errors.New("unexpected multiple charset arguments"),
+				errors.New("expected: server closes connection"),
 			},
 		})
-		return r
 	}
 
-	r.checkContentType(mediaType, charset...)
-
 	return r
 }
 
-// ContentEncoding succeeds if response has exactly given Content-Encoding list.
-// Common values are empty, "gzip", "compress", "deflate", "identity" and "br".
-func (r *Response) ContentEncoding(encoding ...string) *Response {
-	r.chain.enter("ContentEncoding()")
+// checks if server closes connection after response; Close field is set
+// by http.Transport, but may be not set if response was constructed by
+// other client, e.g. Binder, so Connection header is checked as well
+func (r *Response) connectionClose() bool {
+	if r.httpResp.Close {
+		return true
+	}
+
+	for _, value := range r.httpResp.Header.Values("Connection") {
+		for _, token := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "close") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ProtocolVersion returns a new String instance with protocol version
+// of response, e.g. "HTTP/1.1" or "HTTP/2.0".
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.ProtocolVersion().Equal("HTTP/2.0")
+func (r *Response) ProtocolVersion() *String {
+	r.chain.enter("ProtocolVersion()")
 	defer r.chain.leave()
 
 	if r.chain.failed() {
-		return r
+		return newString(r.chain, "")
 	}
 
-	r.checkEqual(`"Content-Encoding" header`,
-		encoding,
-		r.httpResp.Header["Content-Encoding"])
+	proto := r.httpResp.Proto
+	if proto == "" && (r.httpResp.ProtoMajor != 0 || r.httpResp.ProtoMinor != 0) {
+		proto = fmt.Sprintf("HTTP/%d.%d", r.httpResp.ProtoMajor, r.httpResp.ProtoMinor)
+	}
 
-	return r
+	return newString(r.chain, proto)
 }
 
-// TransferEncoding succeeds if response contains given Transfer-Encoding list.
-// Common values are empty, "chunked" and "identity".
-func (r *Response) TransferEncoding(encoding ...string) *Response {
-	r.chain.enter("TransferEncoding()")
+// Headers returns a new Object instance with response header map.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Headers().Value("Content-Type").String().Equal("application-json")
+func (r *Response) Headers() *Object {
+	r.chain.enter("Headers()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newObject(r.chain, nil)
+	}
+
+	var value map[string]interface{}
+	value, _ = canonMap(r.chain, r.httpResp.Header)
+
+	return newObject(r.chain, value)
+}
+
+// Header returns a new String instance with given header field.
+//
+// Header name is case-insensitive. If header has multiple values, the
+// first one is returned; use HeaderValues to inspect all of them.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Header("Content-Type").Equal("application-json")
+//	resp.Header("Date").AsDateTime().Le(time.Now())
+func (r *Response) Header(header string) *String {
+	r.chain.enter("Header(%q)", header)
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newString(r.chain, "")
+	}
+
+	var value string
+	if values := headerValues(r.httpResp.Header, header); len(values) != 0 {
+		value = values[0]
+	}
+
+	return newString(r.chain, value)
+}
+
+// HeaderValues returns a new Array instance with all values of given
+// header field, in order they were received.
+//
+// Header name is case-insensitive. Values of header keys that differ only
+// in case (possible if response header map was constructed manually) are
+// merged, starting from canonical key. If header is missing, empty array
+// is returned.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.HeaderValues("Vary").ContainsOnly("Accept", "Accept-Encoding")
+func (r *Response) HeaderValues(header string) *Array {
+	r.chain.enter("HeaderValues(%q)", header)
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newArray(r.chain, nil)
+	}
+
+	values := []interface{}{}
+	for _, v := range headerValues(r.httpResp.Header, header) {
+		values = append(values, v)
+	}
+
+	return newArray(r.chain, values)
+}
+
+// NoDuplicateHeaders succeeds if none of the response headers is repeated,
+// i.e. every header has at most one value.
+//
+// If names are given, only these headers are checked. Otherwise all headers
+// are checked, except Set-Cookie, which is repeated by design. Header names
+// are case-insensitive, and keys that differ only in case are considered
+// the same header.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.NoDuplicateHeaders()
+//	resp.NoDuplicateHeaders("Content-Type", "Cache-Control")
+func (r *Response) NoDuplicateHeaders(names ...string) *Response {
+	r.chain.enter("NoDuplicateHeaders()")
 	defer r.chain.leave()
 
 	if r.chain.failed() {
 		return r
 	}
 
-	r.checkEqual(`"Transfer-Encoding" header`,
-		encoding,
-		r.httpResp.TransferEncoding)
+	if len(names) == 0 {
+		seen := map[string]bool{}
+		for key := range r.httpResp.Header {
+			name := http.CanonicalHeaderKey(key)
+			if !seen[name] && name != "Set-Cookie" {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+
+	var errs []error
+
+	for _, name := range names {
+		values := headerValues(r.httpResp.Header, name)
+		if len(values) > 1 {
+			errs = append(errs, fmt.Errorf("header %q is repeated %d times: %q",
+				http.CanonicalHeaderKey(name), len(values), values))
+		}
+	}
+
+	if len(errs) != 0 {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{r.httpResp.Header},
+			Errors: append([]error{
+				errors.New("expected: response headers are not repeated"),
+			}, errs...),
+		})
+	}
 
 	return r
 }
 
-// ContentOpts define parameters for matching the response content parameters.
-type ContentOpts struct {
-	// The media type Content-Type part, e.g. "application/json"
-	MediaType string
-	// The character set Content-Type part, e.g. "utf-8"
-	Charset string
+// returns values of header with all keys matching name case-insensitively,
+// starting from canonical key, and then in sorted order of other keys
+func headerValues(header http.Header, name string) []string {
+	canonical := http.CanonicalHeaderKey(name)
+
+	values := append([]string(nil), header[canonical]...)
+
+	var keys []string
+	for key := range header {
+		if key != canonical && strings.EqualFold(key, name) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		values = append(values, header[key]...)
+	}
+
+	return values
 }
 
-// Text returns a new String instance with response body.
+// ContentLength returns a new Number instance with value of
+// Content-Length header.
 //
-// Text succeeds if response contains "text/plain" Content-Type header
-// with empty or "utf-8" charset.
+// ContentLength fails if header is missing or is not a non-negative
+// integer. If header was removed by http.Client (e.g. when it decoded
+// gzip response by itself), length reported by client is used if known.
 //
 // Example:
 //
 //	resp := NewResponse(t, response)
-//	resp.Text().Equal("hello, world!")
-//	resp.Text(ContentOpts{
-//	  MediaType: "text/plain",
-//	}).Equal("hello, world!")
-func (r *Response) Text(options ...ContentOpts) *String {
-	r.chain.enter("Text()")
+//	resp.ContentLength().Le(1024)
+func (r *Response) ContentLength() *Number {
+	r.chain.enter("ContentLength()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newNumber(r.chain, 0)
+	}
+
+	value := r.httpResp.Header.Get("Content-Length")
+
+	if value == "" {
+		if r.httpResp.ContentLength > 0 {
+			return newNumber(r.chain, float64(r.httpResp.ContentLength))
+		}
+
+		r.chain.fail(AssertionFailure{
+			Type:     AssertContainsKey,
+			Actual:   &AssertionValue{r.httpResp.Header},
+			Expected: &AssertionValue{"Content-Length"},
+			Errors: []error{
+				errors.New(`expected: response has "Content-Length" header`),
+			},
+		})
+		return newNumber(r.chain, 0)
+	}
+
+	length, err := strconv.ParseUint(strings.TrimSpace(value), 10, 63)
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{value},
+			Errors: []error{
+				errors.New(`invalid "Content-Length" response header`),
+				err,
+			},
+		})
+		return newNumber(r.chain, 0)
+	}
+
+	return newNumber(r.chain, float64(length))
+}
+
+// RetryAfter returns a new Duration instance with delay from Retry-After
+// header.
+//
+// Header may contain either number of seconds or HTTP date; in the latter
+// case, delay is computed relative to current time and is zero if date is
+// in the past. RetryAfter fails if header is missing or invalid.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Status(http.StatusTooManyRequests)
+//	resp.RetryAfter().InRange(time.Second, time.Minute)
+func (r *Response) RetryAfter() *Duration {
+	r.chain.enter("RetryAfter()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newDuration(r.chain, nil)
+	}
+
+	delay, ok := parseRetryAfter(r.httpResp)
+	if !ok {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{r.httpResp.Header.Get("Retry-After")},
+			Errors: []error{
+				errors.New(
+					`expected: "Retry-After" header with delay in seconds or HTTP date`),
+			},
+		})
+		return newDuration(r.chain, nil)
+	}
+
+	return newDuration(r.chain, &delay)
+}
+
+// CacheControl returns a new CacheControl instance with parsed directives
+// of Cache-Control header.
+//
+// Missing header is the same as header without directives.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.CacheControl().Public().MaxAge().Ge(time.Minute)
+//	resp.CacheControl().NoStore()
+func (r *Response) CacheControl() *CacheControl {
+	r.chain.enter("CacheControl()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newCacheControl(r.chain, nil)
+	}
+
+	return newCacheControl(r.chain, r.httpResp.Header)
+}
+
+// CORS returns a new CORS instance with Access-Control-* headers of
+// response.
+//
+// Example:
+//
+//	e.OPTIONS("/users").
+//	    WithCORSPreflight("https://example.com", "PUT").
+//	    Expect().
+//	    CORS().
+//	    AllowsOrigin("https://example.com").
+//	    AllowsMethod("PUT").
+//	    AllowsCredentials()
+func (r *Response) CORS() *CORS {
+	r.chain.enter("CORS()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newCORS(r.chain, nil)
+	}
+
+	return newCORS(r.chain, r.httpResp.Header)
+}
+
+// ContentDisposition returns a new ContentDisposition instance with parsed
+// Content-Disposition header.
+//
+// ContentDisposition fails if header is missing or malformed.
+//
+// Example:
+//
+//	resp := e.GET("/reports/{id}", 1).Expect()
+//	resp.ContentDisposition().Attachment().Filename().Equal("report.pdf")
+func (r *Response) ContentDisposition() *ContentDisposition {
+	r.chain.enter("ContentDisposition()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newContentDisposition(r.chain, contentDisposition{})
+	}
+
+	value := r.httpResp.Header.Get("Content-Disposition")
+	if value == "" {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertContainsKey,
+			Actual:   &AssertionValue{r.httpResp.Header},
+			Expected: &AssertionValue{"Content-Disposition"},
+			Errors: []error{
+				errors.New(`expected: response has "Content-Disposition" header`),
+			},
+		})
+		return newContentDisposition(r.chain, contentDisposition{})
+	}
+
+	cd, err := parseContentDisposition(value)
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{value},
+			Errors: []error{
+				errors.New(`expected: valid "Content-Disposition" header`),
+				err,
+			},
+		})
+		return newContentDisposition(r.chain, contentDisposition{})
+	}
+
+	return newContentDisposition(r.chain, cd)
+}
+
+// RateLimit returns a new RateLimit instance with parsed rate limit
+// headers, either IETF RateLimit-* or X-RateLimit-* family.
+//
+// RateLimit fails if response has no rate limit headers.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.RateLimit().Limit().Equal(100)
+//	resp.RateLimit().Remaining().Gt(0)
+//	resp.RateLimit().ResetIn().Le(time.Minute)
+func (r *Response) RateLimit() *RateLimit {
+	r.chain.enter("RateLimit()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newRateLimit(r.chain, rateLimitValue{})
+	}
+
+	value, ok := parseRateLimit(r.httpResp.Header)
+	if !ok {
+		failRateLimitMissing(r.chain, r.httpResp.Header)
+	}
+
+	return newRateLimit(r.chain, value)
+}
+
+// Size returns a new Number instance with size of response as received,
+// in bytes, including status line, headers, and body.
+//
+// Size is estimated from HTTP/1.1 representation of response: framing of
+// chunked encoding and HTTP/2 frames and header compression are not counted.
+// Body is counted before content decoding, see BodySize.
+//
+// Size fails if response body is streamed.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Size().Le(64 * 1024)
+func (r *Response) Size() *Number {
+	r.chain.enter("Size()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newNumber(r.chain, 0)
+	}
+
+	if !r.checkNotStreamed() {
+		return newNumber(r.chain, 0)
+	}
+
+	return newNumber(r.chain, float64(r.wireSize))
+}
+
+// BodySize returns a new Number instance with size of response body as
+// received, in bytes, before decoding Content-Encoding.
+//
+// If response was decoded by http.Client itself (e.g. when it requested
+// gzip transparently), the size of decoded body is returned.
+//
+// BodySize fails if response body is streamed.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.BodySize().Le(10 * 1024)
+func (r *Response) BodySize() *Number {
+	r.chain.enter("BodySize()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newNumber(r.chain, 0)
+	}
+
+	if !r.checkNotStreamed() {
+		return newNumber(r.chain, 0)
+	}
+
+	return newNumber(r.chain, float64(len(r.rawContent)))
+}
+
+func (r *Response) checkNotStreamed() bool {
+	if r.stream != nil {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected size check of streamed response body"),
+			},
+		})
+		return false
+	}
+
+	return true
+}
+
+// verifies that response doesn't exceed Config.MaxResponseSize
+func (r *Response) checkMaxSize() {
+	if r.chain.failed() {
+		return
+	}
+
+	if r.wireSize > r.config.MaxResponseSize {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertLe,
+			Actual:   &AssertionValue{r.wireSize},
+			Expected: &AssertionValue{r.config.MaxResponseSize},
+			Errors: []error{
+				fmt.Errorf("expected: response size is at most %d bytes",
+					r.config.MaxResponseSize),
+			},
+		})
+	}
+}
+
+// estimates size of response in HTTP/1.1 wire format
+func responseWireSize(resp *http.Response, body []byte) int64 {
+	cw := &countingWriter{w: ioutil.Discard}
+
+	fmt.Fprintf(cw, "HTTP/%d.%d %s\r\n",
+		resp.ProtoMajor, resp.ProtoMinor, statusCodeText(resp.StatusCode))
+
+	_ = resp.Header.Write(cw)
+
+	if len(resp.TransferEncoding) != 0 &&
+		resp.Header.Get("Transfer-Encoding") == "" {
+		fmt.Fprintf(cw, "Transfer-Encoding: %s\r\n",
+			strings.Join(resp.TransferEncoding, ", "))
+	}
+
+	_, _ = io.WriteString(cw, "\r\n")
+
+	return cw.n + int64(len(body))
+}
+
+// Vary returns a new Array instance with header names listed in Vary
+// header, in canonical form. Multiple Vary headers and comma-separated
+// lists are merged. Wildcard "*" is kept as is.
+//
+// Returned array is empty if response has no Vary header.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Vary().Contains("Accept-Encoding")
+func (r *Response) Vary() *Array {
+	r.chain.enter("Vary()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newArray(r.chain, nil)
+	}
+
+	names := []interface{}{}
+
+	for _, value := range r.httpResp.Header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	return newArray(r.chain, names)
+}
+
+// TLS returns a new TLSState instance with TLS connection state of
+// response, e.g. negotiated version, cipher suite, and server certificates.
+//
+// TLS fails if response was not received over TLS.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	tlsState := resp.TLS()
+//	tlsState.Version().Ge(tls.VersionTLS12)
+//	tlsState.NotExpiredWithin(30 * 24 * time.Hour)
+//	tlsState.PeerCertificate().Subject().Contains("CN=example.com")
+func (r *Response) TLS() *TLSState {
+	r.chain.enter("TLS()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newTLSState(r.chain, &tls.ConnectionState{})
+	}
+
+	if r.httpResp.TLS == nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertNotNil,
+			Actual: &AssertionValue{r.httpResp.TLS},
+			Errors: []error{
+				errors.New("expected: response was received over TLS"),
+			},
+		})
+		return newTLSState(r.chain, &tls.ConnectionState{})
+	}
+
+	return newTLSState(r.chain, r.httpResp.TLS)
+}
+
+// HasSecurityHeaders succeeds if response headers satisfy given security
+// policy, i.e. Strict-Transport-Security, Content-Security-Policy,
+// X-Content-Type-Options, X-Frame-Options, and Referrer-Policy headers
+// are present and have expected values.
+//
+// All enabled checks are performed, and failure lists every violation.
+// See SecurityPolicy and DefaultSecurityPolicy.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.HasSecurityHeaders(DefaultSecurityPolicy())
+//	resp.HasSecurityHeaders(SecurityPolicy{
+//	    NoSniff:      true,
+//	    FrameOptions: []string{"DENY"},
+//	})
+func (r *Response) HasSecurityHeaders(policy SecurityPolicy) *Response {
+	r.chain.enter("HasSecurityHeaders()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	if errs := policy.check(r.httpResp.Header); len(errs) != 0 {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{r.httpResp.Header},
+			Errors: append([]error{
+				errors.New("expected: response headers satisfy security policy"),
+			}, errs...),
+		})
+	}
+
+	return r
+}
+
+// HasNoLeaks succeeds if response headers and body don't contain sensitive
+// data described by given policy, e.g. secret keys, tokens, emails, or card
+// numbers.
+//
+// Failure lists every finding with its location. Found values are masked,
+// so that they are not leaked further into test logs. Body of streamed
+// response is not scanned. See LeakPolicy and DefaultLeakPolicy.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.HasNoLeaks(DefaultLeakPolicy())
+//	resp.HasNoLeaks(LeakPolicy{
+//	    Denylist: []string{"s3cr3t-fixture-password"},
+//	})
+func (r *Response) HasNoLeaks(policy LeakPolicy) *Response {
+	r.chain.enter("HasNoLeaks()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	var body []byte
+	if r.stream == nil {
+		body = r.content
+	}
+
+	if errs := policy.scan(r.httpResp.Header, body); len(errs) != 0 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertValid,
+			Errors: append([]error{
+				errors.New("expected: response doesn't contain sensitive data"),
+			}, errs...),
+		})
+	}
+
+	return r
+}
+
+// Trailers returns a new Object instance with response trailer map.
+//
+// Trailers are sent by server after response body, e.g. by gRPC and
+// streaming APIs.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Trailers().ContainsKey("Grpc-Status")
+func (r *Response) Trailers() *Object {
+	r.chain.enter("Trailers()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newObject(r.chain, nil)
+	}
+
+	trailer := r.httpResp.Trailer
+	if trailer == nil {
+		trailer = http.Header{}
+	}
+
+	var value map[string]interface{}
+	value, _ = canonMap(r.chain, trailer)
+
+	return newObject(r.chain, value)
+}
+
+// Trailer returns a new String instance with given trailer field.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Trailer("Grpc-Status").Equal("0")
+func (r *Response) Trailer(trailer string) *String {
+	r.chain.enter("Trailer(%q)", trailer)
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newString(r.chain, "")
+	}
+
+	value := r.httpResp.Trailer.Get(trailer)
+
+	return newString(r.chain, value)
+}
+
+// Cookies returns a new Array instance with all cookie names set by this response.
+// Returned Array contains a String value for every cookie name.
+//
+// Note that this returns only cookies set by Set-Cookie headers of this response.
+// It doesn't return session cookies from previous responses, which may be stored
+// in a cookie jar.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Cookies().Contains("session")
+func (r *Response) Cookies() *Array {
+	r.chain.enter("Cookies()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newArray(r.chain, nil)
+	}
+
+	names := []interface{}{}
+	for _, c := range r.cookies {
+		names = append(names, c.Name)
+	}
+
+	return newArray(r.chain, names)
+}
+
+// Cookie returns a new Cookie instance with specified cookie from response.
+//
+// Note that this returns only cookies set by Set-Cookie headers of this response.
+// It doesn't return session cookies from previous responses, which may be stored
+// in a cookie jar.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Cookie("session").Domain().Equal("example.com")
+func (r *Response) Cookie(name string) *Cookie {
+	r.chain.enter("Cookie(%q)", name)
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newCookie(r.chain, nil)
+	}
+
+	var cookie *Cookie
+
+	names := []string{}
+	for _, c := range r.cookies {
+		if c.Name == name {
+			cookie = newCookie(r.chain, c)
+			break
+		}
+		names = append(names, c.Name)
+	}
+
+	if cookie == nil {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertContainsElement,
+			Actual:   &AssertionValue{names},
+			Expected: &AssertionValue{name},
+			Errors: []error{
+				errors.New("expected: response contains cookie with given name"),
+			},
+		})
+		return newCookie(r.chain, nil)
+	}
+
+	return cookie
+}
+
+// Websocket returns Websocket instance for interaction with WebSocket server.
+//
+// May be called only if the WithWebsocketUpgrade was called on the request.
+// That is responsibility of the caller to explicitly disconnect websocket after use.
+//
+// Example:
+//
+//	req := NewRequest(config, "GET", "/path")
+//	req.WithWebsocketUpgrade()
+//	ws := req.Expect().Websocket()
+//	defer ws.Disconnect()
+func (r *Response) Websocket() *Websocket {
+	r.chain.enter("Websocket()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newWebsocket(r.chain, r.config, nil)
+	}
+
+	if r.websocket == nil {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New(
+					"Websocket() requires WithWebsocketUpgrade() to be called on request"),
+			},
+		})
+		return newWebsocket(r.chain, r.config, nil)
+	}
+
+	ws := newWebsocket(r.chain, r.config, r.websocket)
+	ws.handshake = r.httpResp.Header
+	ws.wire = r.wsWire
+	ws.dial = r.wsDial
+	ws.isClient = true
+
+	return ws
+}
+
+// Body returns a new String instance with response body.
+//
+// If response has Content-Encoding header with "gzip", "deflate", "br",
+// or "zstd" coding, body is decoded transparently. Use BodyRaw to access
+// body as it was received.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Body().NotEmpty()
+//	resp.Body().Length().Equal(100)
+func (r *Response) Body() *String {
+	return newString(r.chain, string(r.content))
+}
+
+// BodyRaw returns a new String instance with response body as it was
+// received, before decoding according to Content-Encoding header.
+//
+// If response has no supported Content-Encoding, it's the same as Body.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.ContentEncoding("br")
+//	resp.BodyRaw().Length().Lt(resp.Body().Length().Raw())
+func (r *Response) BodyRaw() *String {
+	return newString(r.chain, string(r.rawContent))
+}
+
+// Bytes returns a new Bytes instance with response body.
+//
+// Like Body, it decodes body according to Content-Encoding header, but
+// is more convenient for binary payloads, like images and archives.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Bytes().HasPrefix([]byte("\x89PNG"))
+//	resp.Bytes().EqualFile("testdata/logo.png")
+func (r *Response) Bytes() *Bytes {
+	r.chain.enter("Bytes()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newBytes(r.chain, nil)
+	}
+
+	return newBytes(r.chain, r.content)
+}
+
+// BodyStream returns a new BodyStream instance that reads response body
+// incrementally.
+//
+// If response was requested using Request.WithResponseStream(), stream
+// reads body from network as it arrives. Otherwise, stream reads body
+// that was already received (and decoded).
+//
+// Repeated calls return the same instance. Stream should be closed by
+// calling Close() when it's not needed anymore. If response is already
+// failed, BodyStream closes streamed body and returns failed stream.
+//
+// Example:
+//
+//	stream := req.WithResponseStream().Expect().BodyStream()
+//	defer stream.Close()
+//
+//	stream.NextChunk().Contains(`"id":1`)
+//	stream.EventuallyContains(`"id":2`, time.Second)
+func (r *Response) BodyStream() *BodyStream {
+	r.chain.enter("BodyStream()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		r.closeStream()
+		return newBodyStream(r.chain, nil)
+	}
+
+	return r.getBodyStream()
+}
+
+// SSE returns a new SSEStream instance that reads Server-Sent Events
+// from response body.
+//
+// SSE fails if response Content-Type is not "text/event-stream". For
+// endpoints that keep connection open, request should be sent using
+// Request.WithResponseStream(); otherwise Expect waits until server
+// closes the stream.
+//
+// SSE and BodyStream share underlying body reader. Repeated calls return
+// the same instance.
+//
+// Example:
+//
+//	sse := e.GET("/events").WithResponseStream().Expect().SSE()
+//	defer sse.Disconnect()
+//
+//	sse.NextEvent().Name().Equal("update")
+func (r *Response) SSE() *SSEStream {
+	r.chain.enter("SSE()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		r.closeStream()
+		return newSSEStream(r.chain, nil)
+	}
+
+	if r.sseStream == nil {
+		if !r.checkContentType("text/event-stream") {
+			r.closeStream()
+			return newSSEStream(r.chain, nil)
+		}
+
+		r.sseStream = newSSEStream(r.chain, r.getBodyStream())
+	}
+
+	return r.sseStream
+}
+
+// closes streamed body when stream can't be returned to user because of
+// failure, so that connection is not leaked
+func (r *Response) closeStream() {
+	if r.stream != nil {
+		_ = r.stream.Close()
+	}
+}
+
+func (r *Response) getBodyStream() *BodyStream {
+	if r.bodyStream == nil {
+		if r.stream != nil {
+			r.bodyStream = newBodyStream(r.chain, r.stream)
+		} else {
+			r.bodyStream = newBodyStream(r.chain,
+				ioutil.NopCloser(bytes.NewReader(r.content)))
+		}
+	}
+
+	return r.bodyStream
+}
+
+// NoContent succeeds if response contains empty Content-Type header and
+// empty body.
+func (r *Response) NoContent() *Response {
+	r.chain.enter("NoContent()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	contentType := r.httpResp.Header.Get("Content-Type")
+
+	r.checkEqual(`"Content-Type" header`, "", contentType)
+	r.checkEqual("body", "", string(r.content))
+
+	return r
+}
+
+// ContentType succeeds if response contains Content-Type header with given
+// media type and charset.
+//
+// If charset is omitted, and mediaType is non-empty, Content-Type header
+// should contain empty or utf-8 charset.
+//
+// If charset is omitted, and mediaType is also empty, Content-Type header
+// should contain no charset.
+func (r *Response) ContentType(mediaType string, charset ...string) *Response {
+	r.chain.enter("ContentType()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	if len(charset) > 1 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple charset arguments"),
+			},
+		})
+		return r
+	}
+
+	r.checkContentType(mediaType, charset...)
+
+	return r
+}
+
+// ContentTypeMatches succeeds if response contains Content-Type header with
+// media type matching given pattern, and given charset.
+//
+// Pattern has "type/subtype" form, where both type and subtype may contain
+// "*" wildcards matching any sequence of characters. It allows to match
+// structured syntax suffixes (RFC 6839) and vendor media types, e.g.
+// "application/*+json" matches "application/problem+json" and
+// "application/vnd.api+json". Comparison is case-insensitive.
+//
+// Charset is handled in the same way as in ContentType: if charset is
+// omitted, Content-Type header should contain empty or utf-8 charset.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.ContentTypeMatches("application/*+json")
+//	resp.ContentTypeMatches("text/*", "utf-8")
+func (r *Response) ContentTypeMatches(pattern string, charset ...string) *Response {
+	r.chain.enter("ContentTypeMatches(%q)", pattern)
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	if len(charset) > 1 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple charset arguments"),
+			},
+		})
+		return r
+	}
+
+	patternType, patternSubtype, err := splitMediaTypePattern(pattern)
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{pattern},
+			Errors: []error{
+				errors.New("invalid media type pattern"),
+				err,
+			},
+		})
+		return r
+	}
+
+	contentType := r.httpResp.Header.Get("Content-Type")
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{contentType},
+			Errors: []error{
+				errors.New(`invalid "Content-Type" response header`),
+				err,
+			},
+		})
+		return r
+	}
+
+	actualType, actualSubtype, _ := splitMediaTypePattern(mediaType)
+
+	typeOK, _ := path.Match(patternType, actualType)
+	subtypeOK, _ := path.Match(patternSubtype, actualSubtype)
+
+	if !typeOK || !subtypeOK {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertMatchRegexp,
+			Actual:   &AssertionValue{mediaType},
+			Expected: &AssertionValue{pattern},
+			Errors: []error{
+				errors.New(
+					`expected: media type in "Content-Type" response header` +
+						` matches given pattern`),
+			},
+		})
+		return r
+	}
+
+	r.checkContentCharset(params["charset"], charset...)
+
+	return r
+}
+
+// splits "type/subtype" into lower-case type and subtype
+func splitMediaTypePattern(pattern string) (string, string, error) {
+	slash := strings.IndexByte(pattern, '/')
+	if slash <= 0 || slash == len(pattern)-1 {
+		return "", "", fmt.Errorf("expected type/subtype, got %q", pattern)
+	}
+
+	typ := strings.ToLower(strings.TrimSpace(pattern[:slash]))
+	subtype := strings.ToLower(strings.TrimSpace(pattern[slash+1:]))
+
+	for _, p := range []string{typ, subtype} {
+		if _, err := path.Match(p, ""); err != nil {
+			return "", "", err
+		}
+	}
+
+	return typ, subtype, nil
+}
+
+// ContentEncoding succeeds if response has exactly given Content-Encoding list.
+// Common values are empty, "gzip", "compress", "deflate", "identity", "br",
+// and "zstd".
+//
+// Note that http.Client removes Content-Encoding header when it decodes
+// gzip response by itself, i.e. when Accept-Encoding header was not set
+// explicitly. Other codings are decoded by Response, keeping the header.
+func (r *Response) ContentEncoding(encoding ...string) *Response {
+	r.chain.enter("ContentEncoding()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	r.checkEqual(`"Content-Encoding" header`,
+		encoding,
+		r.httpResp.Header["Content-Encoding"])
+
+	return r
+}
+
+// TransferEncoding succeeds if response contains given Transfer-Encoding list.
+// Common values are empty, "chunked" and "identity".
+func (r *Response) TransferEncoding(encoding ...string) *Response {
+	r.chain.enter("TransferEncoding()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	r.checkEqual(`"Transfer-Encoding" header`,
+		encoding,
+		r.httpResp.TransferEncoding)
+
+	return r
+}
+
+// ContentOpts define parameters for matching the response content parameters.
+type ContentOpts struct {
+	// The media type Content-Type part, e.g. "application/json"
+	MediaType string
+	// The character set Content-Type part, e.g. "utf-8"
+	Charset string
+}
+
+// Text returns a new String instance with response body.
+//
+// Text succeeds if response contains "text/plain" Content-Type header
+// with empty or "utf-8" charset.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Text().Equal("hello, world!")
+//	resp.Text(ContentOpts{
+//	  MediaType: "text/plain",
+//	}).Equal("hello, world!")
+func (r *Response) Text(options ...ContentOpts) *String {
+	r.chain.enter("Text()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newString(r.chain, "")
+	}
+
+	if len(options) > 1 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple options arguments"),
+			},
+		})
+		return newString(r.chain, "")
+	}
+
+	if !r.checkContentOptions(options, "text/plain") {
+		return newString(r.chain, "")
+	}
+
+	content := string(r.content)
+
+	return newString(r.chain, content)
+}
+
+// Form returns a new Object instance with form decoded from response body.
+//
+// Form succeeds if response contains "application/x-www-form-urlencoded"
+// Content-Type header and if form may be decoded from response body.
+// Decoding is performed using https://github.com/ajg/form.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Form().Value("foo").Equal("bar")
+//	resp.Form(ContentOpts{
+//	  MediaType: "application/x-www-form-urlencoded",
+//	}).Value("foo").Equal("bar")
+func (r *Response) Form(options ...ContentOpts) *Object {
+	r.chain.enter("Form()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newObject(r.chain, nil)
+	}
+
+	if len(options) > 1 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple options arguments"),
+			},
+		})
+		return newObject(r.chain, nil)
+	}
+
+	object := r.getForm(options...)
+
+	return newObject(r.chain, object)
+}
+
+func (r *Response) getForm(options ...ContentOpts) map[string]interface{} {
+	if !r.checkContentOptions(options, "application/x-www-form-urlencoded", "") {
+		return nil
+	}
+
+	decoder := form.NewDecoder(bytes.NewReader(r.content))
+
+	var object map[string]interface{}
+
+	if err := decoder.Decode(&object); err != nil {
+		r.chain.fail(AssertionFailure{
+			Type: AssertValid,
+			Actual: &AssertionValue{
+				string(r.content),
+			},
+			Errors: []error{
+				errors.New("failed to decode form"),
+				err,
+			},
+		})
+		return nil
+	}
+
+	return object
+}
+
+// JSON returns a new Value instance with JSON decoded from response body.
+//
+// JSON succeeds if response contains "application/json" Content-Type header
+// with empty or "utf-8" charset and if JSON may be decoded from response body.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.JSON().Array().Elements("foo", "bar")
+//	resp.JSON(ContentOpts{
+//	  MediaType: "application/json",
+//	}).Array.Elements("foo", "bar")
+func (r *Response) JSON(options ...ContentOpts) *Value {
+	r.chain.enter("JSON()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newValue(r.chain, nil)
+	}
+
+	if len(options) > 1 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple options arguments"),
+			},
+		})
+		return newValue(r.chain, nil)
+	}
+
+	value := r.getJSON(options...)
+
+	return newValue(r.chain, value)
+}
+
+// StoreJSON captures value at given JSONPath in JSON response body and
+// puts it into environment with given key.
+//
+// Numbers are stored as json.Number, so they can be retrieved using
+// both Environment.GetInt and Environment.GetFloat. Objects are stored
+// as map[string]interface{} and can be retrieved using
+// Environment.GetObject.
+//
+// If env is nil, environment of Expect instance is used.
+//
+// StoreJSON fails if response body is not JSON (see JSON), or path is
+// invalid or doesn't match anything.
+//
+// Example:
+//
+//	e.POST("/login").WithJSON(credentials).
+//	    Expect().
+//	    Status(http.StatusOK).
+//	    StoreJSON("$.token", nil, "token")
+//
+//	e.GET("/profile").
+//	    WithHeader("Authorization", "Bearer "+e.Env().GetString("token")).
+//	    Expect().
+//	    Status(http.StatusOK)
+func (r *Response) StoreJSON(path string, env *Environment, key string) *Response {
+	r.chain.enter("StoreJSON(%q, %q)", path, key)
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	value := jsonPath(r.chain, r.getJSON(), path)
+	if r.chain.failed() {
+		return r
+	}
+
+	if value.value == nil {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertMatchPath,
+			Expected: &AssertionValue{path},
+			Errors: []error{
+				fmt.Errorf("expected: non-null value at %q", path),
+			},
+		})
+		return r
+	}
+
+	data, err := json.Marshal(value.value)
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to encode captured value"),
+				err,
+			},
+		})
+		return r
+	}
+
+	captured, err := decodeJSONNumbers(data)
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to decode captured value"),
+				err,
+			},
+		})
+		return r
+	}
+
+	r.storeEnv(env, key, captured)
+
+	return r
+}
+
+// StoreHeader captures value of given header and puts it into environment
+// with given key, as string.
+//
+// If env is nil, environment of Expect instance is used.
+//
+// StoreHeader fails if header is missing.
+//
+// Example:
+//
+//	e.POST("/users").WithJSON(user).
+//	    Expect().
+//	    Status(http.StatusCreated).
+//	    StoreHeader("Location", nil, "user_url")
+func (r *Response) StoreHeader(header string, env *Environment, key string) *Response {
+	r.chain.enter("StoreHeader(%q, %q)", header, key)
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	values := r.httpResp.Header.Values(header)
+	if len(values) == 0 {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertContainsKey,
+			Actual:   &AssertionValue{r.httpResp.Header},
+			Expected: &AssertionValue{header},
+			Errors: []error{
+				errors.New("expected: response contains header"),
+			},
+		})
+		return r
+	}
+
+	r.storeEnv(env, key, values[0])
+
+	return r
+}
+
+func (r *Response) storeEnv(env *Environment, key string, value interface{}) {
+	if env == nil {
+		env = r.chain.getEnv()
+	}
+
+	env.store.mu.Lock()
+	defer env.store.mu.Unlock()
+
+	env.store.data[env.prefix+key] = value
+}
+
+// GraphQL returns a new GraphQL instance with GraphQL response decoded
+// from response body.
+//
+// GraphQL succeeds if response contains "application/json" or
+// "application/graphql-response+json" Content-Type header with empty or
+// "utf-8" charset, and body is a JSON object with "data" or "errors" field.
+//
+// If options are provided, they override expected media type and charset.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	gql := resp.GraphQL()
+//	gql.NoErrors()
+//	gql.Data().Path("$.user.name").String().Equal("john")
+func (r *Response) GraphQL(options ...ContentOpts) *GraphQL {
+	r.chain.enter("GraphQL()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newGraphQL(r.chain, nil)
+	}
+
+	if len(options) > 1 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple options arguments"),
+			},
+		})
+		return newGraphQL(r.chain, nil)
+	}
+
+	if len(options) == 0 {
+		mediaType, _, _ := mime.ParseMediaType(r.httpResp.Header.Get("Content-Type"))
+		if mediaType == graphQLResponseType {
+			options = []ContentOpts{{MediaType: graphQLResponseType}}
+		}
+	}
+
+	value := r.getJSON(options...)
+	if r.chain.failed() {
+		return newGraphQL(r.chain, nil)
+	}
+
+	return newGraphQL(r.chain, value)
+}
+
+const graphQLResponseType = "application/graphql-response+json"
+
+// JSONRPC returns a new JSONRPC instance with JSON-RPC 2.0 response object
+// decoded from response body.
+//
+// JSONRPC succeeds if response contains "application/json" Content-Type
+// header with empty or "utf-8" charset, and body is a valid JSON-RPC 2.0
+// response object.
+//
+// If request was built using WithJSONRPC, JSONRPC also checks that
+// response id matches request id. Error response may have null id.
+//
+// Example:
+//
+//	resp := e.POST("/rpc").
+//	    WithJSONRPC("eth_getBalance", []interface{}{"0x407d", "latest"}, 1).
+//	    Expect()
+//
+//	resp.JSONRPC().Result().String().HasPrefix("0x")
+func (r *Response) JSONRPC(options ...ContentOpts) *JSONRPC {
+	r.chain.enter("JSONRPC()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newJSONRPC(r.chain, nil)
+	}
+
+	if len(options) > 1 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple options arguments"),
+			},
+		})
+		return newJSONRPC(r.chain, nil)
+	}
+
+	if r.jsonrpc != nil && r.jsonrpc.batch {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New(
+					"unexpected JSONRPC call for batch request, use JSONRPCBatch"),
+			},
+		})
+		return newJSONRPC(r.chain, nil)
+	}
+
+	value := r.getJSON(options...)
+	if r.chain.failed() {
+		return newJSONRPC(r.chain, nil)
+	}
+
+	rpc := newJSONRPC(r.chain, value)
+
+	if !rpc.chain.failed() && r.jsonrpc != nil && len(r.jsonrpc.ids) != 0 {
+		expectedID := r.jsonrpc.ids[0]
+
+		actualID := rpc.value["id"]
+		if _, hasError := rpc.value["error"]; hasError && actualID == nil {
+			return rpc
+		}
+
+		if actualID != expectedID {
+			rpc.chain.fail(AssertionFailure{
+				Type:     AssertEqual,
+				Actual:   &AssertionValue{actualID},
+				Expected: &AssertionValue{expectedID},
+				Errors: []error{
+					errors.New("expected: JSON-RPC response id matches request id"),
+				},
+			})
+		}
+	}
+
+	return rpc
+}
+
+// JSONRPCBatch returns a new JSONRPCBatch instance with JSON-RPC 2.0
+// batch response decoded from response body.
+//
+// JSONRPCBatch succeeds if response contains "application/json"
+// Content-Type header with empty or "utf-8" charset, and body is an array
+// of valid JSON-RPC 2.0 response objects.
+//
+// If request was built using WithJSONRPCBatch, JSONRPCBatch also checks
+// that there is exactly one response for every call that is not a
+// notification. If all calls were notifications, empty body is allowed.
+//
+// Example:
+//
+//	resp := e.POST("/rpc").
+//	    WithJSONRPCBatch(
+//	        httpexpect.JSONRPCCall{Method: "sum", Params: []int{1, 2}, ID: 1},
+//	        httpexpect.JSONRPCCall{Method: "sum", Params: []int{3, 4}, ID: 2},
+//	    ).
+//	    Expect()
+//
+//	batch := resp.JSONRPCBatch()
+//	batch.Response(1).Result().Number().Equal(3)
+//	batch.Response(2).Result().Number().Equal(7)
+func (r *Response) JSONRPCBatch(options ...ContentOpts) *JSONRPCBatch {
+	r.chain.enter("JSONRPCBatch()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newJSONRPCBatch(r.chain, nil)
+	}
+
+	if len(options) > 1 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple options arguments"),
+			},
+		})
+		return newJSONRPCBatch(r.chain, nil)
+	}
+
+	if r.jsonrpc != nil && !r.jsonrpc.batch {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New(
+					"unexpected JSONRPCBatch call for single request, use JSONRPC"),
+			},
+		})
+		return newJSONRPCBatch(r.chain, nil)
+	}
+
+	if r.jsonrpc != nil && len(r.jsonrpc.ids) == 0 && len(r.content) == 0 {
+		return newJSONRPCBatch(r.chain, []interface{}{})
+	}
+
+	value := r.getJSON(options...)
+	if r.chain.failed() {
+		return newJSONRPCBatch(r.chain, nil)
+	}
+
+	batch := newJSONRPCBatch(r.chain, value)
+
+	if !batch.chain.failed() && r.jsonrpc != nil {
+		batch.checkIDs(r.jsonrpc.ids)
+	}
+
+	return batch
+}
+
+// Problem returns a new Problem instance with RFC 7807 problem details
+// decoded from response body.
+//
+// Problem succeeds if response contains "application/problem+json"
+// Content-Type header with empty or "utf-8" charset, and body is a JSON
+// object with properly typed standard members.
+//
+// If "status" member is present, Problem also checks that it's equal
+// to response status code.
+//
+// If options are provided, they override expected media type and charset.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	problem := resp.Problem()
+//	problem.Type().Equal("https://example.com/probs/out-of-credit")
+//	problem.Title().NotEmpty()
+func (r *Response) Problem(options ...ContentOpts) *Problem {
+	r.chain.enter("Problem()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newProblem(r.chain, nil)
+	}
+
+	if len(options) > 1 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple options arguments"),
+			},
+		})
+		return newProblem(r.chain, nil)
+	}
+
+	if len(options) == 0 {
+		options = []ContentOpts{{MediaType: problemResponseType}}
+	}
+
+	value := r.getJSON(options...)
+	if r.chain.failed() {
+		return newProblem(r.chain, nil)
+	}
+
+	problem := newProblem(r.chain, value)
+	if problem.chain.failed() {
+		return problem
+	}
+
+	if status, ok := problem.value["status"].(float64); ok &&
+		int(status) != r.httpResp.StatusCode {
+		r.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{status},
+			Expected: &AssertionValue{r.httpResp.StatusCode},
+			Errors: []error{
+				errors.New(
+					`expected: problem details "status" member is equal to http status`),
+			},
+		})
+		return newProblem(r.chain, nil)
+	}
+
+	return problem
+}
+
+const problemResponseType = "application/problem+json"
+
+// XML returns a new XML instance with XML document decoded from response
+// body.
+//
+// XML succeeds if response contains "application/xml", "text/xml", or
+// "application/*+xml" (e.g. "application/soap+xml") Content-Type header
+// with empty or "utf-8" charset, and body is a well-formed XML document.
+//
+// If options are provided, they override expected media type and charset.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.XML().XPath("//Order/Id").String().Equal("42")
+func (r *Response) XML(options ...ContentOpts) *XML {
+	r.chain.enter("XML()")
 	defer r.chain.leave()
 
 	if r.chain.failed() {
-		return newString(r.chain, "")
+		return newXML(r.chain, nil)
 	}
 
 	if len(options) > 1 {
@@ -559,37 +2657,45 @@ func (r *Response) Text(options ...ContentOpts) *String {
 				errors.New("unexpected multiple options arguments"),
 			},
 		})
-		return newString(r.chain, "")
+		return newXML(r.chain, nil)
 	}
 
-	if !r.checkContentOptions(options, "text/plain") {
-		return newString(r.chain, "")
+	if len(options) == 0 {
+		mediaType, _, _ := mime.ParseMediaType(r.httpResp.Header.Get("Content-Type"))
+		if mediaType == "text/xml" ||
+			(strings.HasPrefix(mediaType, "application/") &&
+				strings.HasSuffix(mediaType, "+xml")) {
+			options = []ContentOpts{{MediaType: mediaType}}
+		}
 	}
 
-	content := string(r.content)
+	if !r.checkContentOptions(options, "application/xml") {
+		return newXML(r.chain, nil)
+	}
 
-	return newString(r.chain, content)
+	return newXML(r.chain, r.content)
 }
 
-// Form returns a new Object instance with form decoded from response body.
+// HTML returns a new HTML instance with HTML document decoded from response
+// body.
 //
-// Form succeeds if response contains "application/x-www-form-urlencoded"
-// Content-Type header and if form may be decoded from response body.
-// Decoding is performed using https://github.com/ajg/form.
+// HTML succeeds if response contains "text/html" or "application/xhtml+xml"
+// Content-Type header with empty or "utf-8" charset.
+//
+// If options are provided, they override expected media type and charset.
 //
 // Example:
 //
 //	resp := NewResponse(t, response)
-//	resp.Form().Value("foo").Equal("bar")
-//	resp.Form(ContentOpts{
-//	  MediaType: "application/x-www-form-urlencoded",
-//	}).Value("foo").Equal("bar")
-func (r *Response) Form(options ...ContentOpts) *Object {
-	r.chain.enter("Form()")
+//	doc := resp.HTML()
+//	doc.Title().Equal("Sign in")
+//	doc.Select("div.error").Count().Equal(0)
+func (r *Response) HTML(options ...ContentOpts) *HTML {
+	r.chain.enter("HTML()")
 	defer r.chain.leave()
 
 	if r.chain.failed() {
-		return newObject(r.chain, nil)
+		return newHTML(r.chain, nil)
 	}
 
 	if len(options) > 1 {
@@ -599,103 +2705,224 @@ func (r *Response) Form(options ...ContentOpts) *Object {
 				errors.New("unexpected multiple options arguments"),
 			},
 		})
-		return newObject(r.chain, nil)
+		return newHTML(r.chain, nil)
 	}
 
-	object := r.getForm(options...)
+	if len(options) == 0 {
+		mediaType, _, _ := mime.ParseMediaType(r.httpResp.Header.Get("Content-Type"))
+		if mediaType == "application/xhtml+xml" {
+			options = []ContentOpts{{MediaType: mediaType}}
+		}
+	}
 
-	return newObject(r.chain, object)
+	if !r.checkContentOptions(options, "text/html") {
+		return newHTML(r.chain, nil)
+	}
+
+	return newHTML(r.chain, r.content)
 }
 
-func (r *Response) getForm(options ...ContentOpts) map[string]interface{} {
-	if !r.checkContentOptions(options, "application/x-www-form-urlencoded", "") {
+func (r *Response) getJSON(options ...ContentOpts) interface{} {
+	if !r.checkContentOptions(options, "application/json") {
 		return nil
 	}
 
-	decoder := form.NewDecoder(bytes.NewReader(r.content))
-
-	var object map[string]interface{}
+	var value interface{}
 
-	if err := decoder.Decode(&object); err != nil {
+	if err := json.Unmarshal(r.content, &value); err != nil {
 		r.chain.fail(AssertionFailure{
 			Type: AssertValid,
 			Actual: &AssertionValue{
 				string(r.content),
 			},
 			Errors: []error{
-				errors.New("failed to decode form"),
+				errors.New("failed to decode json"),
 				err,
 			},
 		})
 		return nil
 	}
 
-	return object
+	return value
 }
 
-// JSON returns a new Value instance with JSON decoded from response body.
+// EnvelopeOpts define structure of JSON response envelope, i.e. object
+// that wraps payload and error, like {"data": {...}, "error": null}.
+type EnvelopeOpts struct {
+	// JSONPath of payload, e.g. "$.data"
+	DataPath string
+	// JSONPath of error, e.g. "$.error"
+	ErrorPath string
+}
+
+// JSONEnvelope verifies that response body is a JSON envelope without
+// error and returns a new Value instance with unwrapped payload.
 //
-// JSON succeeds if response contains "application/json" Content-Type header
-// with empty or "utf-8" charset and if JSON may be decoded from response body.
+// dataPath and errorPath are JSONPath expressions selecting payload and
+// error. If empty, Config.Envelope is used, and then "$.data" and
+// "$.error". Envelope is valid if error is missing or null, and payload
+// is present (but may be null).
+//
+// Like JSON, JSONEnvelope requires "application/json" Content-Type header
+// with empty or "utf-8" charset.
 //
 // Example:
 //
 //	resp := NewResponse(t, response)
-//	resp.JSON().Array().Elements("foo", "bar")
-//	resp.JSON(ContentOpts{
-//	  MediaType: "application/json",
-//	}).Array.Elements("foo", "bar")
-func (r *Response) JSON(options ...ContentOpts) *Value {
-	r.chain.enter("JSON()")
+//	resp.JSONEnvelope("", "").Object().ValueEqual("id", 1)
+//	resp.JSONEnvelope("$.result", "$.errors[0]").Array().NotEmpty()
+func (r *Response) JSONEnvelope(dataPath, errorPath string) *Value {
+	r.chain.enter("JSONEnvelope()")
 	defer r.chain.leave()
 
 	if r.chain.failed() {
 		return newValue(r.chain, nil)
 	}
 
-	if len(options) > 1 {
+	if dataPath == "" {
+		dataPath = r.config.Envelope.DataPath
+	}
+	if dataPath == "" {
+		dataPath = "$.data"
+	}
+
+	if errorPath == "" {
+		errorPath = r.config.Envelope.ErrorPath
+	}
+	if errorPath == "" {
+		errorPath = "$.error"
+	}
+
+	errorFilter, err := jsonpath.Prepare(errorPath)
+	if err != nil {
 		r.chain.fail(AssertionFailure{
-			Type: AssertUsage,
+			Type:   AssertValid,
+			Actual: &AssertionValue{errorPath},
 			Errors: []error{
-				errors.New("unexpected multiple options arguments"),
+				errors.New("expected: valid json path"),
+				err,
 			},
 		})
 		return newValue(r.chain, nil)
 	}
 
-	value := r.getJSON(options...)
+	value := r.getJSON()
+	if r.chain.failed() {
+		return newValue(r.chain, nil)
+	}
 
-	return newValue(r.chain, value)
+	// error path that doesn't match is the same as null error
+	if errValue, err := errorFilter(value); err == nil && errValue != nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertNil,
+			Actual: &AssertionValue{errValue},
+			Errors: []error{
+				fmt.Errorf("expected: envelope has no error at %q", errorPath),
+			},
+		})
+		return newValue(r.chain, nil)
+	}
+
+	return jsonPath(r.chain, value, dataPath)
 }
 
-func (r *Response) getJSON(options ...ContentOpts) interface{} {
-	if !r.checkContentOptions(options, "application/json") {
-		return nil
+// JSONLines returns a new Array instance with values decoded from
+// NDJSON (JSON Lines) response body, one array element per line.
+//
+// JSONLines succeeds if response contains "application/x-ndjson",
+// "application/jsonl", or "application/x-jsonlines" Content-Type header
+// with empty or "utf-8" charset, and every non-empty line of body is a
+// valid JSON value. Lines may be terminated with LF or CRLF.
+//
+// If some lines can't be decoded, failure is reported with an error for
+// every such line.
+//
+// If options are provided, they override expected media type and charset.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	lines := resp.JSONLines()
+//	lines.Length().Equal(2)
+//	lines.Element(0).Object().ValueEqual("id", 1)
+func (r *Response) JSONLines(options ...ContentOpts) *Array {
+	r.chain.enter("JSONLines()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newArray(r.chain, nil)
 	}
 
-	var value interface{}
+	if len(options) > 1 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple options arguments"),
+			},
+		})
+		return newArray(r.chain, nil)
+	}
 
-	if err := json.Unmarshal(r.content, &value); err != nil {
+	if len(options) == 0 {
+		mediaType, _, _ := mime.ParseMediaType(r.httpResp.Header.Get("Content-Type"))
+		if mediaType == "application/jsonl" || mediaType == "application/x-jsonlines" {
+			options = []ContentOpts{{MediaType: mediaType}}
+		}
+	}
+
+	if !r.checkContentOptions(options, "application/x-ndjson") {
+		return newArray(r.chain, nil)
+	}
+
+	values, errs := decodeJSONLines(r.content)
+
+	if len(errs) != 0 {
 		r.chain.fail(AssertionFailure{
 			Type: AssertValid,
 			Actual: &AssertionValue{
 				string(r.content),
 			},
-			Errors: []error{
-				errors.New("failed to decode json"),
-				err,
-			},
+			Errors: append([]error{
+				errors.New("failed to decode json lines"),
+			}, errs...),
 		})
-		return nil
+		return newArray(r.chain, nil)
 	}
 
-	return value
+	return newArray(r.chain, values)
+}
+
+// decodes every non-empty line as JSON value; returns one error per
+// line that can't be decoded
+func decodeJSONLines(content []byte) ([]interface{}, []error) {
+	values := []interface{}{}
+
+	var errs []error
+
+	for n, line := range bytes.Split(content, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var value interface{}
+
+		if err := json.Unmarshal(line, &value); err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %s", n+1, err))
+			continue
+		}
+
+		values = append(values, value)
+	}
+
+	return values, errs
 }
 
 // JSON returns a new Value instance with JSONP decoded from response body.
 //
-// JSONP succeeds if response contains "application/javascript" Content-Type
-// header with empty or "utf-8" charset and response body of the following form:
+// JSONP succeeds if response contains "application/javascript" or
+// "text/javascript" Content-Type header with empty or "utf-8" charset and
+// response body of the following form:
 //
 //	callback(<valid json>);
 //
@@ -703,7 +2930,9 @@ func (r *Response) getJSON(options ...ContentOpts) interface{} {
 //
 //	callback(<valid json>)
 //
-// Whitespaces are allowed.
+// Whitespaces and newlines are allowed. Leading "/**/" comment, which is
+// added by some frameworks to protect against content sniffing attacks,
+// is skipped.
 //
 // Example:
 //
@@ -736,10 +2965,18 @@ func (r *Response) JSONP(callback string, options ...ContentOpts) *Value {
 }
 
 var (
-	jsonp = regexp.MustCompile(`^\s*([^\s(]+)\s*\((.*)\)\s*;*\s*$`)
+	jsonp = regexp.MustCompile(
+		`(?s)^\s*(?:/\*\*/)?\s*([^\s(]+)\s*\((.*)\)\s*;*\s*$`)
 )
 
 func (r *Response) getJSONP(callback string, options ...ContentOpts) interface{} {
+	if len(options) == 0 {
+		mediaType, _, _ := mime.ParseMediaType(r.httpResp.Header.Get("Content-Type"))
+		if mediaType == "text/javascript" {
+			options = []ContentOpts{{MediaType: mediaType}}
+		}
+	}
+
 	if !r.checkContentOptions(options, "application/javascript") {
 		return nil
 	}
@@ -779,6 +3016,215 @@ func (r *Response) getJSONP(callback string, options ...ContentOpts) interface{}
 	return value
 }
 
+// Decoded decodes response body using codec selected by media type,
+// and returns a new Value instance with decoded value.
+//
+// Codec is looked up in Config.Codecs, and then in built-in codecs for
+// JSON, MessagePack, and CBOR (see BodyCodec). Decoded fails if there is
+// no codec for response media type.
+//
+// If options are provided, response media type is checked against
+// ContentOpts.MediaType, and codec is selected by ContentOpts.MediaType.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.Decoded().Object().ValueEqual("name", "john")
+//
+//	resp.Decoded(ContentOpts{
+//	    MediaType: "application/cbor",
+//	}).Object().ContainsKey("id")
+func (r *Response) Decoded(options ...ContentOpts) *Value {
+	r.chain.enter("Decoded()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newValue(r.chain, nil)
+	}
+
+	if len(options) > 1 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple options arguments"),
+			},
+		})
+		return newValue(r.chain, nil)
+	}
+
+	var mediaType string
+
+	if len(options) != 0 && options[0].MediaType != "" {
+		if !r.checkContentOptions(options, "") {
+			return newValue(r.chain, nil)
+		}
+		mediaType = options[0].MediaType
+	} else {
+		contentType := r.httpResp.Header.Get("Content-Type")
+
+		var err error
+		mediaType, _, err = mime.ParseMediaType(contentType)
+		if err != nil {
+			r.chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{contentType},
+				Errors: []error{
+					errors.New(`invalid "Content-Type" response header`),
+					err,
+				},
+			})
+			return newValue(r.chain, nil)
+		}
+	}
+
+	codec := lookupCodec(r.config.Codecs, mediaType)
+	if codec == nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{mediaType},
+			Errors: []error{
+				errors.New(
+					"unsupported media type, expected codec registered" +
+						" in Config.Codecs or built-in codec"),
+			},
+		})
+		return newValue(r.chain, nil)
+	}
+
+	value, err := codec.Decode(r.content)
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type: AssertValid,
+			Actual: &AssertionValue{
+				bodySnippet(r.content),
+			},
+			Errors: []error{
+				fmt.Errorf("failed to decode %q body", mediaType),
+				err,
+			},
+		})
+		return newValue(r.chain, nil)
+	}
+
+	value, ok := canonValue(r.chain, value)
+	if !ok {
+		return newValue(r.chain, nil)
+	}
+
+	return newValue(r.chain, value)
+}
+
+// Protobuf decodes response body into given protobuf message using
+// Config.ProtoCodec.
+//
+// Encoding is selected by response Content-Type header:
+//   - "application/json" - message is decoded from JSON, as produced by
+//     gRPC-gateway and Twirp JSON endpoints
+//   - "application/x-protobuf", "application/protobuf", or
+//     "application/octet-stream" - message is decoded from binary wire format
+//
+// If options are provided, response media type is checked against
+// ContentOpts.MediaType, and JSON decoding is used if it ends with "json".
+//
+// Example:
+//
+//	var user pb.User
+//	resp := NewResponse(t, response)
+//	resp.Protobuf(&user)
+//	assert.Equal(t, "john", user.Name)
+func (r *Response) Protobuf(target interface{}, options ...ContentOpts) *Response {
+	r.chain.enter("Protobuf()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	if len(options) > 1 {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple options arguments"),
+			},
+		})
+		return r
+	}
+
+	if target == nil {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil argument"),
+			},
+		})
+		return r
+	}
+
+	var mediaType string
+
+	if len(options) != 0 && options[0].MediaType != "" {
+		if !r.checkContentOptions(options, "") {
+			return r
+		}
+		mediaType = options[0].MediaType
+	} else {
+		contentType := r.httpResp.Header.Get("Content-Type")
+
+		mediaType, _, _ = mime.ParseMediaType(contentType)
+
+		switch mediaType {
+		case "application/json",
+			"application/x-protobuf",
+			"application/protobuf",
+			"application/octet-stream":
+		default:
+			r.chain.fail(AssertionFailure{
+				Type:   AssertBelongs,
+				Actual: &AssertionValue{contentType},
+				Expected: &AssertionValue{AssertionList{
+					"application/json",
+					"application/x-protobuf",
+					"application/protobuf",
+					"application/octet-stream",
+				}},
+				Errors: []error{
+					errors.New(
+						`unexpected media type in "Content-Type" response header`),
+				},
+			})
+			return r
+		}
+	}
+
+	codec := r.config.ProtoCodec
+	if codec == nil {
+		codec = DefaultProtoCodec{}
+	}
+
+	var err error
+	if strings.HasSuffix(mediaType, "json") {
+		err = codec.DecodeJSON(r.content, target)
+	} else {
+		err = codec.Decode(r.content, target)
+	}
+
+	if err != nil {
+		r.chain.fail(AssertionFailure{
+			Type: AssertValid,
+			Actual: &AssertionValue{
+				r.content,
+			},
+			Errors: []error{
+				errors.New("failed to decode protobuf message"),
+				err,
+			},
+		})
+		return r
+	}
+
+	return r
+}
+
 func (r *Response) checkContentOptions(
 	options []ContentOpts, expectedType string, expectedCharset ...string,
 ) bool {
@@ -827,8 +3273,10 @@ func (r *Response) checkContentType(expectedType string, expectedCharset ...stri
 		return false
 	}
 
-	charset := params["charset"]
+	return r.checkContentCharset(params["charset"], expectedCharset...)
+}
 
+func (r *Response) checkContentCharset(charset string, expectedCharset ...string) bool {
 	if len(expectedCharset) == 0 {
 		if charset != "" && !strings.EqualFold(charset, "utf-8") {
 			r.chain.fail(AssertionFailure{
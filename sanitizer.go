@@ -0,0 +1,433 @@
+package httpexpect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MaskedValue is used by builtin sanitizers to replace masked values.
+const MaskedValue = "***"
+
+// Sanitizer masks or normalizes sensitive and volatile data in requests
+// and responses.
+//
+// Sanitizers are attached to Config, and are applied to received responses
+// before assertions and to requests and responses before printing.
+//
+// Every method receives value and returns its sanitized version; returning
+// value unchanged means that sanitizer is not interested in it.
+type Sanitizer interface {
+	// SanitizeHeader returns sanitized value of header.
+	// Not used for Cookie and Set-Cookie headers.
+	SanitizeHeader(name, value string) string
+
+	// SanitizeCookie returns sanitized value of cookie from Cookie or
+	// Set-Cookie header.
+	SanitizeCookie(name, value string) string
+
+	// SanitizeBody returns sanitized body, given its content type.
+	SanitizeBody(contentType string, body []byte) []byte
+}
+
+// MaskHeaders returns Sanitizer that replaces values of headers with given
+// names with MaskedValue. Names are case-insensitive.
+//
+// Example:
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    Reporter:   httpexpect.NewAssertReporter(t),
+//	    Sanitizers: []httpexpect.Sanitizer{
+//	        httpexpect.MaskHeaders("Authorization", "X-Api-Key"),
+//	    },
+//	})
+func MaskHeaders(names ...string) Sanitizer {
+	s := headerSanitizer{names: map[string]bool{}}
+	for _, name := range names {
+		s.names[http.CanonicalHeaderKey(name)] = true
+	}
+	return s
+}
+
+type headerSanitizer struct {
+	noopSanitizer
+	names map[string]bool
+}
+
+func (s headerSanitizer) SanitizeHeader(name, value string) string {
+	if s.names[http.CanonicalHeaderKey(name)] {
+		return MaskedValue
+	}
+	return value
+}
+
+// MaskCookies returns Sanitizer that replaces values of cookies with given
+// names with MaskedValue. Names are case-sensitive.
+func MaskCookies(names ...string) Sanitizer {
+	s := cookieSanitizer{names: map[string]bool{}}
+	for _, name := range names {
+		s.names[name] = true
+	}
+	return s
+}
+
+type cookieSanitizer struct {
+	noopSanitizer
+	names map[string]bool
+}
+
+func (s cookieSanitizer) SanitizeCookie(name, value string) string {
+	if s.names[name] {
+		return MaskedValue
+	}
+	return value
+}
+
+// MaskJSONPaths returns Sanitizer that replaces values at given paths in
+// JSON bodies with MaskedValue.
+//
+// Paths use a subset of JSONPath syntax:
+//   - "$.user.token" or "$['user']['token']" - member of object
+//   - "$.items[0]" - element of array
+//   - "$.items[*].token" or "$.*.token" - all members or elements
+//   - "$..password" - member at any depth
+//
+// Body is treated as JSON if its content type is empty or contains "json".
+// If something was masked, body is re-encoded, so its formatting is not
+// preserved.
+//
+// MaskJSONPaths panics if path is invalid.
+//
+// Example:
+//
+//	httpexpect.MaskJSONPaths("$.access_token", "$..password")
+func MaskJSONPaths(paths ...string) Sanitizer {
+	s := jsonPathSanitizer{}
+	for _, path := range paths {
+		steps, err := parseMaskPath(path)
+		if err != nil {
+			panic(fmt.Sprintf("invalid json path %q: %s", path, err))
+		}
+		s.paths = append(s.paths, steps)
+	}
+	return s
+}
+
+type jsonPathSanitizer struct {
+	noopSanitizer
+	paths [][]maskPathStep
+}
+
+func (s jsonPathSanitizer) SanitizeBody(contentType string, body []byte) []byte {
+	if contentType != "" && !strings.Contains(strings.ToLower(contentType), "json") {
+		return body
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var value interface{}
+	if err := dec.Decode(&value); err != nil || dec.More() {
+		return body
+	}
+
+	changed := false
+	for _, steps := range s.paths {
+		var ok bool
+		if value, ok = maskJSONPath(value, steps); ok {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return body
+	}
+
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(value); err != nil {
+		return body
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// ReplaceRegexp returns Sanitizer that replaces all matches of regular
+// expression in header values, cookie values, and bodies with replacement.
+// Replacement may use "$1" syntax, see regexp.Regexp.ReplaceAll.
+//
+// Example:
+//
+//	httpexpect.ReplaceRegexp(regexp.MustCompile(`\d{4}-\d{4}-\d{4}-\d{4}`),
+//	    "XXXX-XXXX-XXXX-XXXX")
+func ReplaceRegexp(re *regexp.Regexp, replacement string) Sanitizer {
+	return regexpSanitizer{re, replacement}
+}
+
+type regexpSanitizer struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+func (s regexpSanitizer) SanitizeHeader(name, value string) string {
+	return s.re.ReplaceAllString(value, s.replacement)
+}
+
+func (s regexpSanitizer) SanitizeCookie(name, value string) string {
+	return s.re.ReplaceAllString(value, s.replacement)
+}
+
+func (s regexpSanitizer) SanitizeBody(contentType string, body []byte) []byte {
+	return s.re.ReplaceAll(body, []byte(s.replacement))
+}
+
+var (
+	emailRegexp = regexp.MustCompile(
+		`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+
+	uuidRegexp = regexp.MustCompile(
+		`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+)
+
+// RedactEmails returns Sanitizer that replaces email addresses in header
+// values, cookie values, and bodies with MaskedValue.
+func RedactEmails() Sanitizer {
+	return ReplaceRegexp(emailRegexp, MaskedValue)
+}
+
+// NormalizeUUIDs returns Sanitizer that replaces UUIDs in header values,
+// cookie values, and bodies with nil UUID, so that snapshots and logs
+// don't depend on generated identifiers.
+func NormalizeUUIDs() Sanitizer {
+	return ReplaceRegexp(uuidRegexp, "00000000-0000-0000-0000-000000000000")
+}
+
+// sanitizer that leaves everything unchanged, embedded by sanitizers
+// interested only in some values
+type noopSanitizer struct{}
+
+func (noopSanitizer) SanitizeHeader(name, value string) string {
+	return value
+}
+
+func (noopSanitizer) SanitizeCookie(name, value string) string {
+	return value
+}
+
+func (noopSanitizer) SanitizeBody(contentType string, body []byte) []byte {
+	return body
+}
+
+// returns sanitized copy of header
+func sanitizeHeader(sanitizers []Sanitizer, header http.Header) http.Header {
+	if header == nil {
+		return nil
+	}
+
+	ret := make(http.Header, len(header))
+
+	for key, values := range header {
+		sanitized := make([]string, 0, len(values))
+
+		for _, value := range values {
+			switch http.CanonicalHeaderKey(key) {
+			case "Cookie":
+				value = sanitizeCookieHeader(sanitizers, value, false)
+			case "Set-Cookie":
+				value = sanitizeCookieHeader(sanitizers, value, true)
+			default:
+				for _, s := range sanitizers {
+					value = s.SanitizeHeader(key, value)
+				}
+			}
+			sanitized = append(sanitized, value)
+		}
+
+		ret[key] = sanitized
+	}
+
+	return ret
+}
+
+// sanitizes "a=1; b=2" (Cookie) or "a=1; Path=/" (Set-Cookie);
+// for Set-Cookie, only first pair is a cookie, the rest are attributes
+func sanitizeCookieHeader(sanitizers []Sanitizer, value string, set bool) string {
+	pairs := strings.Split(value, ";")
+
+	for i, pair := range pairs {
+		if set && i > 0 {
+			break
+		}
+
+		eq := strings.Index(pair, "=")
+		if eq < 0 {
+			continue
+		}
+
+		name := strings.TrimSpace(pair[:eq])
+		cookie := pair[eq+1:]
+
+		for _, s := range sanitizers {
+			cookie = s.SanitizeCookie(name, cookie)
+		}
+
+		pairs[i] = pair[:eq+1] + cookie
+	}
+
+	return strings.Join(pairs, ";")
+}
+
+func sanitizeBody(sanitizers []Sanitizer, contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	// sanitizers may modify body in place
+	body = append([]byte(nil), body...)
+
+	for _, s := range sanitizers {
+		body = s.SanitizeBody(contentType, body)
+	}
+
+	return body
+}
+
+// single step of path passed to MaskJSONPaths
+type maskPathStep struct {
+	key       string
+	index     int // -1 if step is not array index
+	wildcard  bool
+	recursive bool
+}
+
+func parseMaskPath(path string) ([]maskPathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("path should start with '$'")
+	}
+
+	var steps []maskPathStep
+
+	rest := path[1:]
+
+	for rest != "" {
+		step := maskPathStep{index: -1}
+
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			step.recursive = true
+			rest = rest[2:]
+			step.key, rest = splitMaskPathKey(rest)
+			if step.key == "" || step.key == "*" {
+				return nil, fmt.Errorf("expected member name after '..'")
+			}
+
+		case strings.HasPrefix(rest, "."):
+			step.key, rest = splitMaskPathKey(rest[1:])
+			if step.key == "" {
+				return nil, fmt.Errorf("expected member name after '.'")
+			}
+			if step.key == "*" {
+				step.key, step.wildcard = "", true
+			}
+
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated '['")
+			}
+			sel := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			switch {
+			case sel == "*":
+				step.wildcard = true
+
+			case len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') &&
+				sel[len(sel)-1] == sel[0]:
+				step.key = sel[1 : len(sel)-1]
+
+			default:
+				n, err := strconv.Atoi(sel)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid selector '[%s]'", sel)
+				}
+				step.index = n
+			}
+
+		default:
+			return nil, fmt.Errorf("unexpected %q", rest)
+		}
+
+		steps = append(steps, step)
+	}
+
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("path should not be empty")
+	}
+
+	return steps, nil
+}
+
+func splitMaskPathKey(s string) (string, string) {
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+// replaces values matching path with MaskedValue; returns true if anything
+// was replaced
+func maskJSONPath(value interface{}, steps []maskPathStep) (interface{}, bool) {
+	if len(steps) == 0 {
+		return MaskedValue, true
+	}
+
+	step, rest := steps[0], steps[1:]
+	changed := false
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if step.wildcard || (step.index < 0 && key == step.key) {
+				if masked, ok := maskJSONPath(child, rest); ok {
+					v[key] = masked
+					changed = true
+					continue
+				}
+			}
+			if step.recursive {
+				if masked, ok := maskJSONPath(child, steps); ok {
+					v[key] = masked
+					changed = true
+				}
+			}
+		}
+
+	case []interface{}:
+		for i, child := range v {
+			if step.wildcard || i == step.index {
+				if masked, ok := maskJSONPath(child, rest); ok {
+					v[i] = masked
+					changed = true
+					continue
+				}
+			}
+			if step.recursive {
+				if masked, ok := maskJSONPath(child, steps); ok {
+					v[i] = masked
+					changed = true
+				}
+			}
+		}
+	}
+
+	return value, changed
+}
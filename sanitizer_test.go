@@ -0,0 +1,186 @@
+package httpexpect
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizerHeaders(t *testing.T) {
+	sanitizers := []Sanitizer{
+		MaskHeaders("authorization"),
+		MaskCookies("session"),
+	}
+
+	header := http.Header{
+		"Authorization": {"Bearer secret"},
+		"Accept":        {"application/json"},
+		"Cookie":        {"session=secret; lang=en"},
+		"Set-Cookie":    {"session=secret; Path=/; HttpOnly", "lang=en"},
+	}
+
+	sanitized := sanitizeHeader(sanitizers, header)
+
+	assert.Equal(t, http.Header{
+		"Authorization": {"***"},
+		"Accept":        {"application/json"},
+		"Cookie":        {"session=***; lang=en"},
+		"Set-Cookie":    {"session=***; Path=/; HttpOnly", "lang=en"},
+	}, sanitized)
+
+	assert.Equal(t, "Bearer secret", header.Get("Authorization"))
+}
+
+func TestSanitizerJSONPaths(t *testing.T) {
+	cases := []struct {
+		name   string
+		paths  []string
+		body   string
+		result string
+	}{
+		{
+			name:   "member",
+			paths:  []string{"$.token"},
+			body:   `{"token":"secret","id":1}`,
+			result: `{"id":1,"token":"***"}`,
+		},
+		{
+			name:   "bracket member",
+			paths:  []string{"$['user']['token']"},
+			body:   `{"user":{"token":"secret"}}`,
+			result: `{"user":{"token":"***"}}`,
+		},
+		{
+			name:   "index",
+			paths:  []string{"$.items[1]"},
+			body:   `{"items":["a","b","c"]}`,
+			result: `{"items":["a","***","c"]}`,
+		},
+		{
+			name:   "wildcard",
+			paths:  []string{"$.items[*].token"},
+			body:   `{"items":[{"token":"a"},{"token":"b"},{}]}`,
+			result: `{"items":[{"token":"***"},{"token":"***"},{}]}`,
+		},
+		{
+			name:   "recursive",
+			paths:  []string{"$..password"},
+			body:   `{"password":"a","users":[{"password":"b","name":"<c>"}]}`,
+			result: `{"password":"***","users":[{"name":"<c>","password":"***"}]}`,
+		},
+		{
+			name:   "no match",
+			paths:  []string{"$.token"},
+			body:   `{ "id": 12345678901234567890 }`,
+			result: `{ "id": 12345678901234567890 }`,
+		},
+		{
+			name:   "not json",
+			paths:  []string{"$.token"},
+			body:   `token=secret`,
+			result: `token=secret`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := MaskJSONPaths(tc.paths...)
+
+			result := s.SanitizeBody("application/json", []byte(tc.body))
+
+			assert.Equal(t, tc.result, string(result))
+		})
+	}
+
+	t.Run("content type", func(t *testing.T) {
+		s := MaskJSONPaths("$.token")
+
+		body := []byte(`{"token":"secret"}`)
+
+		assert.Equal(t, `{"token":"***"}`, string(s.SanitizeBody("", body)))
+		assert.Equal(t, string(body), string(s.SanitizeBody("text/plain", body)))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, path := range []string{"", "token", "$.", "$..", "$[x]", "$[1", "$x"} {
+			assert.Panics(t, func() {
+				MaskJSONPaths(path)
+			}, path)
+		}
+	})
+}
+
+func TestSanitizerRegexp(t *testing.T) {
+	sanitizers := []Sanitizer{
+		RedactEmails(),
+		NormalizeUUIDs(),
+		ReplaceRegexp(regexp.MustCompile(`card-(\d+)`), "card-$1-masked"),
+	}
+
+	body := sanitizeBody(sanitizers, "text/plain", []byte(
+		"user john.doe@mail.example.com, "+
+			"id 123e4567-E89B-12d3-a456-426614174000, card-42"))
+
+	assert.Equal(t,
+		"user ***, id 00000000-0000-0000-0000-000000000000, card-42-masked",
+		string(body))
+
+	header := sanitizeHeader(sanitizers, http.Header{
+		"X-User": {"john@example.org"},
+		"Cookie": {"id=123e4567-e89b-12d3-a456-426614174000"},
+	})
+
+	assert.Equal(t, "***", header.Get("X-User"))
+	assert.Equal(t, "id=00000000-0000-0000-0000-000000000000", header.Get("Cookie"))
+}
+
+func TestSanitizerExpect(t *testing.T) {
+	client := &mockClient{
+		resp: http.Response{
+			StatusCode: http.StatusOK,
+		},
+	}
+
+	printer := &mockPrinter{}
+
+	e := WithConfig(Config{
+		Client:   client,
+		Reporter: newMockReporter(t),
+		Printers: []Printer{printer},
+		Sanitizers: []Sanitizer{
+			MaskHeaders("Authorization"),
+			MaskJSONPaths("$.token"),
+		},
+	})
+
+	resp := e.POST("/path").
+		WithHeader("Authorization", "Bearer secret").
+		WithJSON(map[string]interface{}{"token": "secret", "id": 1}).
+		Expect()
+
+	resp.chain.assertOK(t)
+
+	t.Run("sent", func(t *testing.T) {
+		assert.Equal(t, "Bearer secret", client.req.Header.Get("Authorization"))
+	})
+
+	t.Run("printed", func(t *testing.T) {
+		assert.Equal(t, "***", printer.reqHeader.Get("Authorization"))
+		assert.Equal(t, `{"id":1,"token":"***"}`, string(printer.reqBody))
+
+		assert.Equal(t, "***", printer.respHeader.Get("Authorization"))
+		assert.Equal(t, `{"id":1,"token":"***"}`, string(printer.respBody))
+	})
+
+	t.Run("asserted", func(t *testing.T) {
+		resp.Header("Authorization").Equal("***")
+		resp.JSON().Object().Value("token").Equal("***")
+		resp.JSON().Object().Value("id").Equal(1)
+
+		assert.False(t, strings.Contains(string(resp.content), "secret"))
+		resp.chain.assertOK(t)
+	})
+}
@@ -0,0 +1,256 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Scenario is a sequence of named steps that share Expect instance and
+// Environment, like login, create resource, and verify it.
+//
+// Steps are run in order by Run. If a step fails, remaining steps are
+// skipped, and failure is reported with scenario and step names.
+// Steps may be retried, and may register teardown functions, which are
+// invoked after all steps, even if some step failed.
+//
+// Scenario is created by Expect.Scenario.
+type Scenario struct {
+	expect   *Expect
+	name     string
+	steps    []scenarioStep
+	teardown []scenarioTeardown
+}
+
+// StepFunc is a function that implements a single scenario step.
+//
+// It receives a copy of Expect instance, and should make all requests and
+// assertions using it. It also receives scenario environment, which is used
+// to pass values between steps.
+type StepFunc func(e *Expect, env *Environment)
+
+// StepOpts defines additional options for a scenario step.
+type StepOpts struct {
+	// Retries is the number of additional attempts made if step fails.
+	// Failures are not reported while there are attempts left.
+	Retries int
+
+	// RetryDelay is the delay between attempts.
+	RetryDelay time.Duration
+
+	// Teardown is invoked after all steps of scenario, if step was run,
+	// even if it failed. Teardowns are invoked in reverse order.
+	Teardown StepFunc
+}
+
+type scenarioStep struct {
+	name string
+	fn   StepFunc
+	opts StepOpts
+	err  error
+}
+
+type scenarioTeardown struct {
+	name string
+	fn   StepFunc
+}
+
+// Scenario returns a new Scenario with given name.
+//
+// Steps of scenario receive environment scope with the same name, see
+// Environment.Scope. Values stored in it are also accessible via
+// Expect.Env(), with scenario name prefix.
+//
+// Example:
+//
+//	e.Scenario("order").
+//	    Step("login", func(e *httpexpect.Expect, env *httpexpect.Environment) {
+//	        e.POST("/login").WithJSON(credentials).
+//	            Expect().
+//	            Status(http.StatusOK).
+//	            StoreJSON("$.token", env, "token")
+//	    }).
+//	    Step("create", func(e *httpexpect.Expect, env *httpexpect.Environment) {
+//	        e.POST("/orders").WithJSON(order).
+//	            WithHeader("Authorization", "Bearer "+env.GetString("token")).
+//	            Expect().
+//	            Status(http.StatusCreated).
+//	            StoreJSON("$.id", env, "id")
+//	    }, httpexpect.StepOpts{
+//	        Teardown: func(e *httpexpect.Expect, env *httpexpect.Environment) {
+//	            e.DELETE("/orders/{id}", env.GetInt("id")).
+//	                Expect().
+//	                Status(http.StatusNoContent)
+//	        },
+//	    }).
+//	    Step("verify", func(e *httpexpect.Expect, env *httpexpect.Environment) {
+//	        e.GET("/orders/{id}", env.GetInt("id")).
+//	            Expect().
+//	            Status(http.StatusOK)
+//	    }, httpexpect.StepOpts{
+//	        Retries:    3,
+//	        RetryDelay: time.Second,
+//	    }).
+//	    Run()
+func (e *Expect) Scenario(name string) *Scenario {
+	return &Scenario{
+		expect: e,
+		name:   name,
+	}
+}
+
+// Step appends a new step to scenario and returns scenario.
+//
+// Optional StepOpts may be given to configure retries and teardown.
+func (s *Scenario) Step(name string, fn StepFunc, opts ...StepOpts) *Scenario {
+	step := scenarioStep{
+		name: name,
+		fn:   fn,
+	}
+
+	if len(opts) != 0 {
+		step.opts = opts[0]
+	}
+
+	// reported by Run
+	if len(opts) > 1 {
+		step.err = fmt.Errorf("unexpected multiple opts arguments of step %q", name)
+	}
+
+	s.steps = append(s.steps, step)
+
+	return s
+}
+
+// Teardown registers function that is invoked after all steps of scenario,
+// even if some step failed. It's invoked after teardowns of steps.
+func (s *Scenario) Teardown(fn StepFunc) *Scenario {
+	s.teardown = append(s.teardown, scenarioTeardown{
+		fn: fn,
+	})
+
+	return s
+}
+
+// Run runs all steps of scenario in order, until some step fails, and
+// then invokes teardown functions.
+func (s *Scenario) Run() {
+	e := s.expect
+
+	e.chain.enter("Scenario(%q)", s.name)
+	defer e.chain.leave()
+
+	if err := s.validate(); err != nil {
+		chain := e.chain.clone()
+		chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				err,
+			},
+		})
+		return
+	}
+
+	env := e.chain.getEnv().Scope(s.name)
+
+	var teardown []scenarioTeardown
+
+	defer func() {
+		teardown = append(teardown, s.teardown...)
+
+		for i := len(teardown) - 1; i >= 0; i-- {
+			s.runTeardown(teardown[i], env)
+		}
+	}()
+
+	for _, step := range s.steps {
+		if step.opts.Teardown != nil {
+			teardown = append(teardown, scenarioTeardown{
+				name: step.name,
+				fn:   step.opts.Teardown,
+			})
+		}
+
+		if !s.runStep(step, env) {
+			return
+		}
+	}
+}
+
+func (s *Scenario) validate() error {
+	for _, step := range s.steps {
+		if step.err != nil {
+			return step.err
+		}
+		if step.fn == nil {
+			return fmt.Errorf("unexpected nil function of step %q", step.name)
+		}
+		if step.opts.Retries < 0 {
+			return fmt.Errorf("unexpected negative retries of step %q", step.name)
+		}
+		if step.opts.RetryDelay < 0 {
+			return fmt.Errorf("unexpected negative retry delay of step %q", step.name)
+		}
+	}
+
+	for _, teardown := range s.teardown {
+		if teardown.fn == nil {
+			return errors.New("unexpected nil teardown function")
+		}
+	}
+
+	return nil
+}
+
+func (s *Scenario) runStep(step scenarioStep, env *Environment) bool {
+	e := s.expect
+
+	e.chain.enter("Step(%q)", step.name)
+	defer e.chain.leave()
+
+	for attempt := 1; ; attempt++ {
+		handler := &eventuallyHandler{}
+
+		stepExpect := e.withHandler(handler)
+
+		// environment failures are step failures as well
+		stepEnv := &Environment{
+			chain:  stepExpect.chain.clone(),
+			store:  env.store,
+			prefix: env.prefix,
+		}
+
+		step.fn(stepExpect, stepEnv)
+
+		if handler.failure == nil {
+			return true
+		}
+
+		if attempt <= step.opts.Retries {
+			time.Sleep(step.opts.RetryDelay)
+			continue
+		}
+
+		handler.report(e.chain,
+			fmt.Errorf("scenario %q failed at step %q after %d attempt(s)",
+				s.name, step.name, attempt))
+
+		return false
+	}
+}
+
+func (s *Scenario) runTeardown(teardown scenarioTeardown, env *Environment) {
+	e := s.expect
+
+	if teardown.name != "" {
+		e.chain.enter("Teardown(%q)", teardown.name)
+	} else {
+		e.chain.enter("Teardown()")
+	}
+	defer e.chain.leave()
+
+	ret := e.clone()
+	ret.chain = e.chain.clone()
+
+	teardown.fn(ret, env)
+}
@@ -0,0 +1,209 @@
+package httpexpect
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScenarioSteps(t *testing.T) {
+	client := &mockClient{
+		resp: http.Response{
+			StatusCode: http.StatusOK,
+		},
+	}
+
+	handler := &mockAssertionHandler{}
+
+	e := WithConfig(Config{
+		Client:           client,
+		AssertionHandler: handler,
+	})
+
+	var steps []string
+
+	e.Scenario("flow").
+		Step("login", func(e *Expect, env *Environment) {
+			steps = append(steps, "login")
+			e.GET("/login").Expect().Status(http.StatusOK)
+			env.Put("token", "abc")
+		}).
+		Step("act", func(e *Expect, env *Environment) {
+			steps = append(steps, "act:"+env.GetString("token"))
+			e.GET("/act").Expect().Status(http.StatusOK)
+		}).
+		Run()
+
+	assert.Equal(t, []string{"login", "act:abc"}, steps)
+	assert.Nil(t, handler.failure)
+
+	assert.Equal(t, "abc", e.Env().GetString("flow.token"))
+}
+
+func TestScenarioFailure(t *testing.T) {
+	client := &mockClient{
+		resp: http.Response{
+			StatusCode: http.StatusOK,
+		},
+	}
+
+	handler := &mockAssertionHandler{}
+
+	var (
+		hooked int
+		path   []string
+	)
+
+	e := WithConfig(Config{
+		Client:           client,
+		AssertionHandler: handler,
+		OnFailure: func(ctx *AssertionContext, _ *AssertionFailure) {
+			hooked++
+			path = append([]string(nil), ctx.Path...)
+		},
+	})
+
+	var steps []string
+
+	e.Scenario("flow").
+		Step("login", func(e *Expect, env *Environment) {
+			steps = append(steps, "login")
+		}, StepOpts{
+			Teardown: func(e *Expect, env *Environment) {
+				steps = append(steps, "teardown login")
+			},
+		}).
+		Step("act", func(e *Expect, env *Environment) {
+			steps = append(steps, "act")
+			e.GET("/act").Expect().Status(http.StatusNotFound)
+		}, StepOpts{
+			Teardown: func(e *Expect, env *Environment) {
+				steps = append(steps, "teardown act")
+			},
+		}).
+		Step("verify", func(e *Expect, env *Environment) {
+			steps = append(steps, "verify")
+		}, StepOpts{
+			Teardown: func(e *Expect, env *Environment) {
+				steps = append(steps, "teardown verify")
+			},
+		}).
+		Teardown(func(e *Expect, env *Environment) {
+			steps = append(steps, "teardown")
+		}).
+		Run()
+
+	assert.Equal(t, []string{
+		"login", "act", "teardown", "teardown act", "teardown login",
+	}, steps)
+
+	assert.NotNil(t, handler.failure)
+	assert.Equal(t, 1, hooked)
+
+	assert.Contains(t, path, `Scenario("flow")`)
+	assert.Contains(t, path, `Step("act")`)
+	assert.True(t, strings.Contains(handler.failure.Errors[0].Error(),
+		`scenario "flow" failed at step "act" after 1 attempt(s)`))
+}
+
+func TestScenarioRetries(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		e := WithConfig(Config{
+			Client:           &mockClient{},
+			AssertionHandler: handler,
+		})
+
+		attempts := 0
+
+		e.Scenario("flow").
+			Step("poll", func(e *Expect, env *Environment) {
+				attempts++
+				e.Value(attempts).Equal(3)
+			}, StepOpts{
+				Retries: 5,
+			}).
+			Run()
+
+		assert.Equal(t, 3, attempts)
+		assert.Nil(t, handler.failure)
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		e := WithConfig(Config{
+			Client:           &mockClient{},
+			AssertionHandler: handler,
+		})
+
+		attempts := 0
+
+		e.Scenario("flow").
+			Step("poll", func(e *Expect, env *Environment) {
+				attempts++
+				env.GetString("missing")
+			}, StepOpts{
+				Retries: 2,
+			}).
+			Run()
+
+		assert.Equal(t, 3, attempts)
+		assert.NotNil(t, handler.failure)
+		assert.True(t, strings.Contains(handler.failure.Errors[0].Error(),
+			"after 3 attempt(s)"))
+	})
+}
+
+func TestScenarioUsage(t *testing.T) {
+	cases := []struct {
+		name     string
+		scenario func(e *Expect) *Scenario
+	}{
+		{
+			name: "nil step",
+			scenario: func(e *Expect) *Scenario {
+				return e.Scenario("flow").Step("step", nil)
+			},
+		},
+		{
+			name: "nil teardown",
+			scenario: func(e *Expect) *Scenario {
+				return e.Scenario("flow").Teardown(nil)
+			},
+		},
+		{
+			name: "negative retries",
+			scenario: func(e *Expect) *Scenario {
+				return e.Scenario("flow").
+					Step("step", func(*Expect, *Environment) {}, StepOpts{Retries: -1})
+			},
+		},
+		{
+			name: "multiple opts",
+			scenario: func(e *Expect) *Scenario {
+				return e.Scenario("flow").
+					Step("step", func(*Expect, *Environment) {}, StepOpts{}, StepOpts{})
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := &mockAssertionHandler{}
+
+			e := WithConfig(Config{
+				Client:           &mockClient{},
+				AssertionHandler: handler,
+			})
+
+			tc.scenario(e).Run()
+
+			assert.NotNil(t, handler.failure)
+			assert.Equal(t, AssertUsage, handler.failure.Type)
+		})
+	}
+}
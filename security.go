@@ -0,0 +1,228 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SecurityPolicy defines set of security-related response headers checked
+// by Response.HasSecurityHeaders.
+//
+// Zero value doesn't check anything; enable checks you need, or start
+// from DefaultSecurityPolicy.
+type SecurityPolicy struct {
+	// HSTS requires Strict-Transport-Security header with valid max-age.
+	HSTS bool
+
+	// HSTSMinAge is minimum allowed max-age of Strict-Transport-Security.
+	// Ignored if HSTS is false.
+	HSTSMinAge time.Duration
+
+	// HSTSIncludeSubdomains requires includeSubDomains directive of
+	// Strict-Transport-Security. Ignored if HSTS is false.
+	HSTSIncludeSubdomains bool
+
+	// CSP requires non-empty Content-Security-Policy header.
+	CSP bool
+
+	// CSPDirectives lists directives that should be present in
+	// Content-Security-Policy, e.g. "default-src", "frame-ancestors".
+	// Ignored if CSP is false.
+	CSPDirectives []string
+
+	// NoSniff requires "X-Content-Type-Options: nosniff" header.
+	NoSniff bool
+
+	// FrameOptions lists allowed values of X-Frame-Options header,
+	// e.g. "DENY", "SAMEORIGIN". Comparison is case-insensitive.
+	// If empty, header is not checked.
+	FrameOptions []string
+
+	// ReferrerPolicy lists allowed values of Referrer-Policy header,
+	// e.g. "no-referrer", "strict-origin-when-cross-origin". If header
+	// contains several comma-separated policies, the last one is checked.
+	// If empty, header is not checked.
+	ReferrerPolicy []string
+}
+
+// DefaultSecurityPolicy returns policy that follows common recommendations
+// (OWASP Secure Headers Project):
+//   - HSTS with max-age of at least 180 days
+//   - any non-empty CSP
+//   - X-Content-Type-Options: nosniff
+//   - X-Frame-Options: DENY or SAMEORIGIN
+//   - Referrer-Policy that doesn't leak full URL to other origins
+func DefaultSecurityPolicy() SecurityPolicy {
+	return SecurityPolicy{
+		HSTS:       true,
+		HSTSMinAge: 180 * 24 * time.Hour,
+		CSP:        true,
+		NoSniff:    true,
+		FrameOptions: []string{
+			"DENY",
+			"SAMEORIGIN",
+		},
+		ReferrerPolicy: []string{
+			"no-referrer",
+			"same-origin",
+			"strict-origin",
+			"strict-origin-when-cross-origin",
+		},
+	}
+}
+
+// SecurityHeadersMatcher returns matcher that invokes HasSecurityHeaders
+// for every response. It can be used in Config.Matchers to enforce policy
+// across all endpoints.
+//
+// Example:
+//
+//	e := WithConfig(Config{
+//	    BaseURL:  "http://example.com",
+//	    Reporter: NewAssertReporter(t),
+//	    Matchers: []func(*Response){
+//	        SecurityHeadersMatcher(DefaultSecurityPolicy()),
+//	    },
+//	})
+func SecurityHeadersMatcher(policy SecurityPolicy) func(*Response) {
+	return func(resp *Response) {
+		resp.HasSecurityHeaders(policy)
+	}
+}
+
+// returns list of policy violations, empty if none
+func (p *SecurityPolicy) check(header http.Header) []error {
+	var errs []error
+
+	if p.HSTS {
+		errs = append(errs, p.checkHSTS(header.Get("Strict-Transport-Security"))...)
+	}
+
+	if p.CSP {
+		errs = append(errs, p.checkCSP(header.Get("Content-Security-Policy"))...)
+	}
+
+	if p.NoSniff {
+		value := strings.TrimSpace(header.Get("X-Content-Type-Options"))
+		if !strings.EqualFold(value, "nosniff") {
+			errs = append(errs, fmt.Errorf(
+				`"X-Content-Type-Options" header is %q, expected "nosniff"`, value))
+		}
+	}
+
+	if len(p.FrameOptions) != 0 {
+		value := strings.TrimSpace(header.Get("X-Frame-Options"))
+		if !containsFold(p.FrameOptions, value) {
+			errs = append(errs, fmt.Errorf(
+				`"X-Frame-Options" header is %q, expected one of %q`,
+				value, p.FrameOptions))
+		}
+	}
+
+	if len(p.ReferrerPolicy) != 0 {
+		value := header.Get("Referrer-Policy")
+		if i := strings.LastIndexByte(value, ','); i >= 0 {
+			value = value[i+1:]
+		}
+		value = strings.TrimSpace(value)
+		if !containsFold(p.ReferrerPolicy, value) {
+			errs = append(errs, fmt.Errorf(
+				`"Referrer-Policy" header is %q, expected one of %q`,
+				value, p.ReferrerPolicy))
+		}
+	}
+
+	return errs
+}
+
+func (p *SecurityPolicy) checkHSTS(value string) []error {
+	if value == "" {
+		return []error{
+			errors.New(`missing "Strict-Transport-Security" header`),
+		}
+	}
+
+	var (
+		maxAge            *time.Duration
+		includeSubdomains bool
+	)
+
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+
+		name, arg := part, ""
+		if i := strings.IndexByte(part, '='); i >= 0 {
+			name, arg = strings.TrimSpace(part[:i]), strings.Trim(part[i+1:], ` "`)
+		}
+
+		switch strings.ToLower(name) {
+		case "max-age":
+			if secs, err := strconv.ParseUint(arg, 10, 32); err == nil {
+				d := time.Duration(secs) * time.Second
+				maxAge = &d
+			}
+		case "includesubdomains":
+			includeSubdomains = true
+		}
+	}
+
+	var errs []error
+
+	switch {
+	case maxAge == nil:
+		errs = append(errs, fmt.Errorf(
+			`"Strict-Transport-Security" header %q has no valid max-age`, value))
+
+	case *maxAge < p.HSTSMinAge:
+		errs = append(errs, fmt.Errorf(
+			`"Strict-Transport-Security" max-age is %s, expected at least %s`,
+			*maxAge, p.HSTSMinAge))
+	}
+
+	if p.HSTSIncludeSubdomains && !includeSubdomains {
+		errs = append(errs, fmt.Errorf(
+			`"Strict-Transport-Security" header %q has no includeSubDomains`, value))
+	}
+
+	return errs
+}
+
+func (p *SecurityPolicy) checkCSP(value string) []error {
+	if strings.TrimSpace(value) == "" {
+		return []error{
+			errors.New(`missing "Content-Security-Policy" header`),
+		}
+	}
+
+	directives := map[string]bool{}
+
+	for _, part := range strings.Split(value, ";") {
+		if fields := strings.Fields(part); len(fields) != 0 {
+			directives[strings.ToLower(fields[0])] = true
+		}
+	}
+
+	var errs []error
+
+	for _, name := range p.CSPDirectives {
+		if !directives[strings.ToLower(name)] {
+			errs = append(errs, fmt.Errorf(
+				`"Content-Security-Policy" header has no %q directive`, name))
+		}
+	}
+
+	return errs
+}
+
+func containsFold(list []string, value string) bool {
+	for _, s := range list {
+		if strings.EqualFold(s, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package httpexpect
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func secureHeaders() http.Header {
+	return http.Header{
+		"Strict-Transport-Security": {"max-age=31536000; includeSubDomains"},
+		"Content-Security-Policy":   {"default-src 'self'; frame-ancestors 'none'"},
+		"X-Content-Type-Options":    {"nosniff"},
+		"X-Frame-Options":           {"DENY"},
+		"Referrer-Policy":           {"no-referrer, strict-origin-when-cross-origin"},
+	}
+}
+
+func TestSecurityPolicyDefault(t *testing.T) {
+	policy := DefaultSecurityPolicy()
+
+	assert.Empty(t, policy.check(secureHeaders()))
+
+	errs := policy.check(http.Header{})
+	assert.Equal(t, 5, len(errs))
+}
+
+func TestSecurityPolicyZero(t *testing.T) {
+	policy := SecurityPolicy{}
+
+	assert.Empty(t, policy.check(http.Header{}))
+}
+
+func TestSecurityPolicyChecks(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy SecurityPolicy
+		header string
+		value  string
+		ok     bool
+	}{
+		{
+			name:   "hsts ok",
+			policy: SecurityPolicy{HSTS: true, HSTSMinAge: time.Hour},
+			header: "Strict-Transport-Security",
+			value:  "max-age=3600",
+			ok:     true,
+		},
+		{
+			name:   "hsts short max-age",
+			policy: SecurityPolicy{HSTS: true, HSTSMinAge: time.Hour},
+			header: "Strict-Transport-Security",
+			value:  "max-age=60",
+		},
+		{
+			name:   "hsts bad max-age",
+			policy: SecurityPolicy{HSTS: true},
+			header: "Strict-Transport-Security",
+			value:  "max-age=abc",
+		},
+		{
+			name:   "hsts subdomains",
+			policy: SecurityPolicy{HSTS: true, HSTSIncludeSubdomains: true},
+			header: "Strict-Transport-Security",
+			value:  `max-age="100"; IncludeSubDomains; preload`,
+			ok:     true,
+		},
+		{
+			name:   "hsts no subdomains",
+			policy: SecurityPolicy{HSTS: true, HSTSIncludeSubdomains: true},
+			header: "Strict-Transport-Security",
+			value:  "max-age=100",
+		},
+		{
+			name: "csp directives",
+			policy: SecurityPolicy{
+				CSP:           true,
+				CSPDirectives: []string{"default-src", "Frame-Ancestors"},
+			},
+			header: "Content-Security-Policy",
+			value:  "default-src 'self'; frame-ancestors 'none'",
+			ok:     true,
+		},
+		{
+			name: "csp missing directive",
+			policy: SecurityPolicy{
+				CSP:           true,
+				CSPDirectives: []string{"frame-ancestors"},
+			},
+			header: "Content-Security-Policy",
+			value:  "default-src 'self'",
+		},
+		{
+			name:   "nosniff case",
+			policy: SecurityPolicy{NoSniff: true},
+			header: "X-Content-Type-Options",
+			value:  "NoSniff",
+			ok:     true,
+		},
+		{
+			name:   "nosniff wrong",
+			policy: SecurityPolicy{NoSniff: true},
+			header: "X-Content-Type-Options",
+			value:  "sniff",
+		},
+		{
+			name:   "frame options",
+			policy: SecurityPolicy{FrameOptions: []string{"DENY", "SAMEORIGIN"}},
+			header: "X-Frame-Options",
+			value:  "sameorigin",
+			ok:     true,
+		},
+		{
+			name:   "frame options wrong",
+			policy: SecurityPolicy{FrameOptions: []string{"DENY"}},
+			header: "X-Frame-Options",
+			value:  "ALLOW-FROM https://example.com",
+		},
+		{
+			name:   "referrer policy last",
+			policy: SecurityPolicy{ReferrerPolicy: []string{"no-referrer"}},
+			header: "Referrer-Policy",
+			value:  "unsafe-url, no-referrer",
+			ok:     true,
+		},
+		{
+			name:   "referrer policy wrong",
+			policy: SecurityPolicy{ReferrerPolicy: []string{"no-referrer"}},
+			header: "Referrer-Policy",
+			value:  "unsafe-url",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := tc.policy.check(http.Header{tc.header: {tc.value}})
+
+			if tc.ok {
+				assert.Empty(t, errs)
+			} else {
+				assert.Equal(t, 1, len(errs))
+			}
+		})
+	}
+}
+
+func TestSecurityHeadersMatcher(t *testing.T) {
+	matcher := SecurityHeadersMatcher(DefaultSecurityPolicy())
+
+	resp := NewResponse(newMockReporter(t), &http.Response{
+		Header: secureHeaders(),
+	})
+	matcher(resp)
+	resp.chain.assertOK(t)
+
+	resp = NewResponse(newMockReporter(t), &http.Response{
+		Header: http.Header{},
+	})
+	matcher(resp)
+	resp.chain.assertFailed(t)
+}
@@ -0,0 +1,194 @@
+package httpexpect
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+)
+
+// SentRequest returns a new PreparedRequest instance with request as it
+// was passed to transport, after transformers, BeforeRequest, signing,
+// cookies, and redirects. If request was retried or redirected, the last
+// attempt is returned.
+//
+// Unlike Request.DryRun, it shows what was actually sent, so it can be used
+// to verify headers added by client middleware, cookie jar, or signer.
+//
+// SentRequest fails if Config.CaptureSentRequest is not enabled, or if
+// response was served from cache without sending request.
+//
+// Example:
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:            "http://example.com",
+//	    Reporter:           httpexpect.NewAssertReporter(t),
+//	    CaptureSentRequest: true,
+//	})
+//
+//	sent := e.POST("/users").WithJSON(user).Expect().SentRequest()
+//	sent.Header("Authorization").NotEmpty()
+//	sent.Wire().Raw() // "POST /users HTTP/1.1\r\nHost: example.com\r\n..."
+func (r *Response) SentRequest() *PreparedRequest {
+	r.chain.enter("SentRequest()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return newPreparedRequest(r.chain, nil, nil)
+	}
+
+	if !r.config.CaptureSentRequest {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("SentRequest() requires Config.CaptureSentRequest"),
+			},
+		})
+		return newPreparedRequest(r.chain, nil, nil)
+	}
+
+	if r.sentReq == nil {
+		r.chain.fail(AssertionFailure{
+			Type:   AssertNotNil,
+			Actual: &AssertionValue{r.sentReq},
+			Errors: []error{
+				errors.New("expected: request was sent to server"),
+			},
+		})
+		return newPreparedRequest(r.chain, nil, nil)
+	}
+
+	httpReq := r.sentReq.httpReq.Clone(r.sentReq.httpReq.Context())
+	httpReq.Body = ioutil.NopCloser(bytes.NewReader(r.sentReq.body))
+
+	return newPreparedRequest(r.chain, httpReq, r.sentReq.body)
+}
+
+type sentRequest struct {
+	httpReq *http.Request
+	body    []byte // nil if body was streamed
+}
+
+// captures request passed to transport or client, see
+// Config.CaptureSentRequest; every attempt of request has its own
+// recorder, passed to transport via request context
+type sentRequestRecorder struct {
+	mu   sync.Mutex
+	last *sentRequest
+}
+
+type sentRequestKey struct{}
+
+// returns context that passes recorder to client wrapped by
+// captureSentRequests
+func withSentRequestRecorder(
+	ctx context.Context, rec *sentRequestRecorder,
+) context.Context {
+	return context.WithValue(ctx, sentRequestKey{}, rec)
+}
+
+// returns copy of client that captures requests into recorder from
+// request context, if any
+func captureSentRequests(client Client) Client {
+	httpClient, ok := client.(*http.Client)
+	if !ok {
+		return &sentRequestClient{client}
+	}
+
+	next := httpClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	clientCopy := *httpClient
+	clientCopy.Transport = &sentRequestTransport{next}
+
+	return &clientCopy
+}
+
+func recordSentRequest(req *http.Request) {
+	if rec, ok := req.Context().Value(sentRequestKey{}).(*sentRequestRecorder); ok {
+		rec.record(req)
+	}
+}
+
+func (rec *sentRequestRecorder) record(req *http.Request) {
+	sent := &sentRequest{
+		httpReq: req.Clone(req.Context()),
+	}
+
+	switch {
+	case req.Body == nil || req.Body == http.NoBody:
+		sent.body = []byte{}
+
+	case req.GetBody != nil:
+		if rd, err := req.GetBody(); err == nil {
+			sent.body, _ = ioutil.ReadAll(rd)
+			_ = rd.Close()
+		}
+
+	default:
+		if bw, ok := req.Body.(*bodyWrapper); ok {
+			if rd, err := bw.GetBody(); err == nil {
+				sent.body, _ = ioutil.ReadAll(rd)
+			}
+		}
+	}
+
+	sent.httpReq.Body = nil
+
+	rec.mu.Lock()
+	rec.last = sent
+	rec.mu.Unlock()
+}
+
+func (rec *sentRequestRecorder) get() *sentRequest {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	return rec.last
+}
+
+type sentRequestTransport struct {
+	next http.RoundTripper
+}
+
+func (t *sentRequestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recordSentRequest(req)
+
+	return t.next.RoundTrip(req)
+}
+
+type sentRequestClient struct {
+	next Client
+}
+
+func (c *sentRequestClient) Do(req *http.Request) (*http.Response, error) {
+	recordSentRequest(req)
+
+	return c.next.Do(req)
+}
+
+// returns HTTP/1.1 representation of request, as written by http.Transport;
+// if body is nil, only request line and headers are returned
+func dumpRequest(req *http.Request, body []byte) ([]byte, error) {
+	dump := req.Clone(context.Background())
+
+	switch {
+	case len(body) != 0:
+		dump.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	case body != nil:
+		dump.Body = http.NoBody
+
+	case dump.Body == nil || dump.Body == http.NoBody:
+		// streamed body is not available, but it's not read when
+		// dumping without body anyway
+		dump.Body = ioutil.NopCloser(bytes.NewReader(nil))
+	}
+
+	return httputil.DumpRequestOut(dump, body != nil)
+}
@@ -0,0 +1,214 @@
+package httpexpect
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSentRequestCapture(t *testing.T) {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/new", http.StatusFound)
+	})
+
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	newConfig := func(capture bool) Config {
+		return Config{
+			BaseURL:  "http://example.com",
+			Reporter: newMockReporter(t),
+			Client: &http.Client{
+				Transport: NewBinder(mux),
+			},
+			CaptureSentRequest: capture,
+		}
+	}
+
+	t.Run("json", func(t *testing.T) {
+		config := newConfig(true)
+		config.BeforeRequest = func(req *http.Request) {
+			req.Header.Set("X-Before", "1")
+		}
+
+		e := WithConfig(config)
+
+		resp := e.POST("/users").
+			WithQuery("notify", false).
+			WithJSON(map[string]interface{}{"name": "john"}).
+			Expect()
+
+		resp.Status(http.StatusCreated)
+
+		sent := resp.SentRequest()
+		sent.chain.assertOK(t)
+
+		require.NotNil(t, sent.Raw())
+
+		sent.Method().Equal("POST").chain.assertOK(t)
+		sent.Path().Equal("/users").chain.assertOK(t)
+		sent.Query("notify").Equal("false").chain.assertOK(t)
+		sent.Header("X-Before").Equal("1").chain.assertOK(t)
+		sent.Body().Equal(`{"name":"john"}`).chain.assertOK(t)
+		sent.JSON().Object().ValueEqual("name", "john").chain.assertOK(t)
+
+		wire := string(sent.Wire().Raw())
+
+		assert.True(t, strings.HasPrefix(wire, "POST /users?notify=false HTTP/1.1\r\n"))
+		assert.Contains(t, wire, "X-Before: 1\r\n")
+		assert.True(t, strings.HasSuffix(wire, `{"name":"john"}`))
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		e := WithConfig(newConfig(true))
+
+		resp := e.GET("/old").Expect()
+
+		resp.Status(http.StatusOK)
+
+		sent := resp.SentRequest()
+		sent.chain.assertOK(t)
+
+		sent.Path().Equal("/new").chain.assertOK(t)
+		sent.Body().Empty().chain.assertOK(t)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		e := WithConfig(newConfig(true))
+
+		resp := e.POST("/users").
+			WithBodyStream(bytes.NewReader([]byte("streamed"))).
+			Expect()
+
+		resp.Status(http.StatusCreated)
+
+		sent := resp.SentRequest()
+		sent.chain.assertOK(t)
+
+		sent.Body().Empty().chain.assertOK(t)
+
+		wire := string(sent.Wire().Raw())
+
+		assert.True(t, strings.HasPrefix(wire, "POST /users HTTP/1.1\r\n"))
+		assert.NotContains(t, wire, "streamed")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		e := WithConfig(newConfig(false))
+
+		resp := e.GET("/new").Expect()
+
+		resp.chain.assertOK(t)
+
+		sent := resp.SentRequest()
+
+		sent.chain.assertFailed(t)
+		resp.chain.assertFailed(t)
+	})
+}
+
+func TestSentRequestConcurrent(t *testing.T) {
+	const n = 20
+
+	var arrived int64
+	barrier := make(chan struct{})
+
+	// wait until all requests are sent, so that they overlap
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&arrived, 1) == n {
+			close(barrier)
+		}
+		select {
+		case <-barrier:
+		case <-time.After(time.Second):
+		}
+
+		w.Header().Set("X-Attempt", r.Header.Get("X-Attempt"))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var counter int64
+
+	e := WithConfig(Config{
+		BaseURL:  "http://example.com",
+		Reporter: newMockReporter(t),
+		Client: &http.Client{
+			Transport: NewBinder(handler),
+		},
+		BeforeRequest: func(req *http.Request) {
+			n := atomic.AddInt64(&counter, 1)
+			req.Header.Set("X-Attempt", strconv.FormatInt(n, 10))
+		},
+		CaptureSentRequest: true,
+	})
+
+	resps := e.GET("/path").RepeatConcurrently(n)
+	require.Equal(t, n, len(resps))
+
+	for _, resp := range resps {
+		attempt := resp.Raw().Header.Get("X-Attempt")
+		require.NotEmpty(t, attempt)
+
+		sent := resp.SentRequest()
+		sent.chain.assertOK(t)
+
+		sent.Header("X-Attempt").Equal(attempt).chain.assertOK(t)
+	}
+}
+
+func TestSentRequestClient(t *testing.T) {
+	client := &mockClient{}
+
+	config := Config{
+		BaseURL:            "http://example.com",
+		Reporter:           newMockReporter(t),
+		Client:             client,
+		CaptureSentRequest: true,
+	}
+
+	resp := NewRequest(config, "PUT", "/users/1").
+		WithHeader("X-Foo", "bar").
+		WithText("hello").
+		Expect()
+
+	sent := resp.SentRequest()
+	sent.chain.assertOK(t)
+
+	sent.Method().Equal("PUT").chain.assertOK(t)
+	sent.Header("X-Foo").Equal("bar").chain.assertOK(t)
+	sent.Body().Equal("hello").chain.assertOK(t)
+
+	require.NotNil(t, client.req)
+	assert.Equal(t, "bar", client.req.Header.Get("X-Foo"))
+}
+
+func TestSentRequestNotSent(t *testing.T) {
+	resp := newResponse(responseOpts{
+		config: Config{
+			CaptureSentRequest: true,
+		},
+		chain:    newMockChain(t),
+		httpResp: &http.Response{StatusCode: http.StatusOK},
+	})
+
+	resp.chain.assertOK(t)
+
+	resp.SentRequest().chain.assertFailed(t)
+	resp.chain.assertFailed(t)
+}
@@ -0,0 +1,127 @@
+package httpexpect
+
+// AsWarning returns a copy of Response, which reports failures of all
+// assertions made via it, and via values derived from it, as warnings.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//	resp.AsWarning().Header("Strict-Transport-Security").NotEmpty()
+func (r *Response) AsWarning() *Response {
+	r.chain.enter("AsWarning()")
+	defer r.chain.leave()
+
+	ret := *r
+	ret.chain = r.chain.clone()
+	ret.chain.setSeverity(SeverityWarning)
+
+	return &ret
+}
+
+// AsWarning returns a copy of Value, which reports failures of all
+// assertions made via it, and via values derived from it, as warnings.
+//
+// Example:
+//
+//	value := NewValue(t, map[string]interface{}{"foo": 123})
+//	value.AsWarning().Object().NotContainsKey("deprecated")
+func (v *Value) AsWarning() *Value {
+	v.chain.enter("AsWarning()")
+	defer v.chain.leave()
+
+	ret := *v
+	ret.chain = v.chain.clone()
+	ret.chain.setSeverity(SeverityWarning)
+
+	return &ret
+}
+
+// AsWarning returns a copy of Object, which reports failures of all
+// assertions made via it, and via values derived from it, as warnings.
+//
+// Example:
+//
+//	object := NewObject(t, map[string]interface{}{"foo": 123})
+//	object.AsWarning().NotContainsKey("deprecated")
+func (o *Object) AsWarning() *Object {
+	o.chain.enter("AsWarning()")
+	defer o.chain.leave()
+
+	ret := *o
+	ret.chain = o.chain.clone()
+	ret.chain.setSeverity(SeverityWarning)
+
+	return &ret
+}
+
+// AsWarning returns a copy of Array, which reports failures of all
+// assertions made via it, and via values derived from it, as warnings.
+//
+// Example:
+//
+//	array := NewArray(t, []interface{}{"foo", 123})
+//	array.AsWarning().Length().Le(100)
+func (a *Array) AsWarning() *Array {
+	a.chain.enter("AsWarning()")
+	defer a.chain.leave()
+
+	ret := *a
+	ret.chain = a.chain.clone()
+	ret.chain.setSeverity(SeverityWarning)
+
+	return &ret
+}
+
+// AsWarning returns a copy of String, which reports failures of all
+// assertions made via it, and via values derived from it, as warnings.
+//
+// Example:
+//
+//	str := NewString(t, "Hello")
+//	str.AsWarning().Length().Le(100)
+func (s *String) AsWarning() *String {
+	s.chain.enter("AsWarning()")
+	defer s.chain.leave()
+
+	ret := *s
+	ret.chain = s.chain.clone()
+	ret.chain.setSeverity(SeverityWarning)
+
+	return &ret
+}
+
+// AsWarning returns a copy of Number, which reports failures of all
+// assertions made via it, and via values derived from it, as warnings.
+//
+// Example:
+//
+//	number := NewNumber(t, 123)
+//	number.AsWarning().Le(100)
+func (n *Number) AsWarning() *Number {
+	n.chain.enter("AsWarning()")
+	defer n.chain.leave()
+
+	ret := *n
+	ret.chain = n.chain.clone()
+	ret.chain.setSeverity(SeverityWarning)
+
+	return &ret
+}
+
+// AsWarning returns a copy of Boolean, which reports failures of all
+// assertions made via it, and via values derived from it, as warnings.
+//
+// Example:
+//
+//	boolean := NewBoolean(t, true)
+//	boolean.AsWarning().True()
+func (b *Boolean) AsWarning() *Boolean {
+	b.chain.enter("AsWarning()")
+	defer b.chain.leave()
+
+	ret := *b
+	ret.chain = b.chain.clone()
+	ret.chain.setSeverity(SeverityWarning)
+
+	return &ret
+}
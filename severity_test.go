@@ -0,0 +1,101 @@
+package httpexpect
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeverityString(t *testing.T) {
+	assert.Equal(t, "error", SeverityError.String())
+	assert.Equal(t, "warning", SeverityWarning.String())
+	assert.Equal(t, "AssertionSeverity(5)", AssertionSeverity(5).String())
+}
+
+func TestSeverityAsWarning(t *testing.T) {
+	handler := &mockAssertionHandler{}
+
+	e := WithConfig(Config{
+		BaseURL: "http://example.com",
+		Client: &mockClient{
+			resp: http.Response{
+				StatusCode: http.StatusOK,
+			},
+		},
+		AssertionHandler: handler,
+	})
+
+	resp := e.GET("/").WithHeader("Foo", "bar").Expect()
+
+	resp.AsWarning().
+		Header("Strict-Transport-Security").NotEmpty()
+
+	require.NotNil(t, handler.failure)
+	assert.Equal(t, SeverityWarning, handler.failure.Severity)
+	assert.False(t, handler.failure.IsFatal)
+	resp.chain.assertOK(t)
+
+	handler.failure = nil
+
+	resp.Header("Strict-Transport-Security").NotEmpty()
+
+	require.NotNil(t, handler.failure)
+	assert.Equal(t, SeverityError, handler.failure.Severity)
+	assert.True(t, handler.failure.IsFatal)
+}
+
+func TestSeverityTypes(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	handler := &DefaultAssertionHandler{
+		Formatter: &DefaultFormatter{},
+		Reporter:  reporter,
+	}
+
+	e := WithConfig(Config{
+		AssertionHandler: handler,
+	})
+
+	value := e.Value(map[string]interface{}{
+		"arr":  []interface{}{1, 2},
+		"str":  "foo",
+		"num":  123,
+		"bool": false,
+	})
+
+	value.AsWarning().Object().ContainsKey("missing")
+	value.Object().AsWarning().Value("arr").Array().Length().Equal(3)
+	value.Object().Value("arr").Array().AsWarning().Length().Equal(3)
+	value.Object().Value("str").String().AsWarning().Equal("bar")
+	value.Object().Value("num").Number().AsWarning().Equal(456)
+	value.Object().Value("bool").Boolean().AsWarning().True()
+
+	assert.False(t, reporter.reported)
+	assert.Equal(t, 0, handler.Errors())
+	assert.Equal(t, 6, handler.Warnings())
+
+	value.chain.assertOK(t)
+
+	value.Object().Value("bool").Boolean().True()
+
+	assert.True(t, reporter.reported)
+	assert.Equal(t, 1, handler.Errors())
+	assert.Equal(t, 6, handler.Warnings())
+}
+
+func TestSeverityFormat(t *testing.T) {
+	formatter := &DefaultFormatter{}
+
+	msg := formatter.FormatFailure(&AssertionContext{}, &AssertionFailure{
+		Type:     AssertValid,
+		Severity: SeverityWarning,
+	})
+	assert.Contains(t, msg, "severity: warning")
+
+	msg = formatter.FormatFailure(&AssertionContext{}, &AssertionFailure{
+		Type: AssertValid,
+	})
+	assert.NotContains(t, msg, "severity:")
+}
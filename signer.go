@@ -0,0 +1,321 @@
+package httpexpect
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Signer is used to sign requests, e.g. by adding signature header.
+//
+// Signer is invoked by Request.WithSigner before every attempt to send
+// request, including retries, after all headers, body, and transformers
+// were applied. body contains full request body and is nil if request
+// has no body. Signer should not modify body.
+type Signer interface {
+	Sign(req *http.Request, body []byte) error
+}
+
+// SignerFunc is an adapter that allows a function to be used as the Signer.
+//
+// Example:
+//
+//	req := NewRequest(config, "PUT", "http://example.com/path")
+//	req.WithSigner(httpexpect.SignerFunc(
+//	    func(req *http.Request, body []byte) error {
+//	        req.Header.Set("X-Body-Length", strconv.Itoa(len(body)))
+//	        return nil
+//	    }))
+type SignerFunc func(req *http.Request, body []byte) error
+
+// Sign implements Signer.Sign.
+func (f SignerFunc) Sign(req *http.Request, body []byte) error {
+	return f(req, body)
+}
+
+// HMACSigner is a Signer that computes HMAC of the request and puts it
+// into a header.
+//
+// By default, HMAC-SHA256 of "<method>\n<request uri>\n<body>" is computed
+// and put into X-Signature header, hex-encoded.
+//
+// Example:
+//
+//	// GitHub-style webhook signature
+//	signer := &httpexpect.HMACSigner{
+//	    Key:    []byte("secret"),
+//	    Header: "X-Hub-Signature-256",
+//	    Prefix: "sha256=",
+//	    Message: func(req *http.Request, body []byte) []byte {
+//	        return body
+//	    },
+//	}
+type HMACSigner struct {
+	// Secret key. Must be non-empty.
+	Key []byte
+
+	// Hash function constructor.
+	// If nil, sha256.New is used.
+	Hash func() hash.Hash
+
+	// Header to put signature into.
+	// If empty, "X-Signature" is used.
+	Header string
+
+	// Prefix prepended to encoded signature in header value.
+	// Empty by default.
+	Prefix string
+
+	// Encode converts raw signature to header value.
+	// If nil, hex.EncodeToString is used.
+	Encode func([]byte) string
+
+	// Message builds signed message from request.
+	// If nil, "<method>\n<request uri>\n<body>" is used.
+	Message func(req *http.Request, body []byte) []byte
+}
+
+// Sign implements Signer.Sign.
+func (s *HMACSigner) Sign(req *http.Request, body []byte) error {
+	if len(s.Key) == 0 {
+		return errors.New("HMACSigner requires non-empty key")
+	}
+
+	h := s.Hash
+	if h == nil {
+		h = sha256.New
+	}
+
+	header := s.Header
+	if header == "" {
+		header = "X-Signature"
+	}
+
+	encode := s.Encode
+	if encode == nil {
+		encode = hex.EncodeToString
+	}
+
+	var msg []byte
+	if s.Message != nil {
+		msg = s.Message(req, body)
+	} else {
+		msg = []byte(req.Method + "\n" + req.URL.RequestURI() + "\n")
+		msg = append(msg, body...)
+	}
+
+	mac := hmac.New(h, s.Key)
+	_, _ = mac.Write(msg)
+
+	req.Header.Set(header, s.Prefix+encode(mac.Sum(nil)))
+
+	return nil
+}
+
+// AWSSigV4Signer is a Signer that implements AWS Signature Version 4.
+//
+// Signer adds X-Amz-Date, Authorization, and, if SessionToken is set,
+// X-Amz-Security-Token headers. For "s3" service, it also adds
+// X-Amz-Content-Sha256 header and doesn't double-encode path.
+//
+// Example:
+//
+//	req := NewRequest(config, "GET",
+//	    "https://example.execute-api.us-east-1.amazonaws.com/")
+//	req.WithSigner(&httpexpect.AWSSigV4Signer{
+//	    AccessKeyID:     "AKIDEXAMPLE",
+//	    SecretAccessKey: "secret",
+//	    Region:          "us-east-1",
+//	    Service:         "execute-api",
+//	})
+type AWSSigV4Signer struct {
+	// Credentials.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// Region and service name, e.g. "us-east-1" and "execute-api".
+	Region  string
+	Service string
+
+	// Now returns signing time.
+	// If nil, time.Now is used.
+	Now func() time.Time
+}
+
+// Sign implements Signer.Sign.
+func (s *AWSSigV4Signer) Sign(req *http.Request, body []byte) error {
+	if s.AccessKeyID == "" || s.SecretAccessKey == "" {
+		return errors.New("AWSSigV4Signer requires access key id and secret access key")
+	}
+	if s.Region == "" || s.Service == "" {
+		return errors.New("AWSSigV4Signer requires region and service")
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	t := now().UTC()
+
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	payloadHash := sha256Hex(body)
+
+	req.Header.Del("Authorization")
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+	if s.Service == "s3" {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	canonHeaders, signedHeaders := awsCanonicalHeaders(req)
+
+	canonRequest := strings.Join([]string{
+		req.Method,
+		awsCanonicalPath(req.URL, s.Service != "s3"),
+		awsCanonicalQuery(req.URL),
+		canonHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/" + s.Service + "/aws4_request"
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization",
+		"AWS4-HMAC-SHA256 Credential="+s.AccessKeyID+"/"+scope+
+			", SignedHeaders="+signedHeaders+
+			", Signature="+signature)
+
+	return nil
+}
+
+func awsCanonicalPath(u *url.URL, doubleEncode bool) string {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	if !doubleEncode {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		segments[i] = awsURIEncode(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+func awsCanonicalQuery(u *url.URL) string {
+	query := u.Query()
+
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, awsURIEncode(k)+"="+awsURIEncode(v))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+func awsCanonicalHeaders(req *http.Request) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{
+		"host": host,
+	}
+
+	for k, v := range req.Header {
+		lk := strings.ToLower(k)
+		if lk == "content-type" || strings.HasPrefix(lk, "x-amz-") {
+			trimmed := make([]string, len(v))
+			for i := range v {
+				trimmed[i] = strings.Join(strings.Fields(v[i]), " ")
+			}
+			headers[lk] = strings.Join(trimmed, ",")
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var canon strings.Builder
+	for _, k := range names {
+		canon.WriteString(k)
+		canon.WriteString(":")
+		canon.WriteString(headers[k])
+		canon.WriteString("\n")
+	}
+
+	return canon.String(), strings.Join(names, ";")
+}
+
+func awsURIEncode(s string) string {
+	const hexDigits = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') ||
+			('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			b.WriteByte('%')
+			b.WriteByte(hexDigits[c>>4])
+			b.WriteByte(hexDigits[c&15])
+		}
+	}
+
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
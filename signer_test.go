@@ -0,0 +1,216 @@
+package httpexpect
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignerHMAC(t *testing.T) {
+	mac := func(h func() hash.Hash, key, msg string) []byte {
+		m := hmac.New(h, []byte(key))
+		_, _ = m.Write([]byte(msg))
+		return m.Sum(nil)
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "http://example.com/path?a=b", nil)
+
+		signer := &HMACSigner{Key: []byte("secret")}
+		require.NoError(t, signer.Sign(req, []byte("body")))
+
+		expected := hex.EncodeToString(
+			mac(sha256.New, "secret", "POST\n/path?a=b\nbody"))
+
+		assert.Equal(t, expected, req.Header.Get("X-Signature"))
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "http://example.com/path", nil)
+
+		signer := &HMACSigner{
+			Key:    []byte("secret"),
+			Hash:   sha1.New,
+			Header: "X-Hub-Signature",
+			Prefix: "sha1=",
+			Encode: base64.StdEncoding.EncodeToString,
+			Message: func(req *http.Request, body []byte) []byte {
+				return body
+			},
+		}
+		require.NoError(t, signer.Sign(req, []byte("body")))
+
+		expected := "sha1=" + base64.StdEncoding.EncodeToString(
+			mac(sha1.New, "secret", "body"))
+
+		assert.Equal(t, expected, req.Header.Get("X-Hub-Signature"))
+	})
+
+	t.Run("empty key", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "http://example.com/path", nil)
+
+		signer := &HMACSigner{}
+		assert.Error(t, signer.Sign(req, nil))
+	})
+}
+
+func TestSignerAWSSigV4(t *testing.T) {
+	newSigner := func() *AWSSigV4Signer {
+		return &AWSSigV4Signer{
+			AccessKeyID:     "AKIDEXAMPLE",
+			SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+			Region:          "us-east-1",
+			Service:         "service",
+			Now: func() time.Time {
+				return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+			},
+		}
+	}
+
+	// test vectors from AWS SigV4 test suite
+	cases := []struct {
+		name      string
+		url       string
+		signature string
+	}{
+		{
+			name:      "get-vanilla",
+			url:       "http://example.amazonaws.com/",
+			signature: "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:      "get-vanilla-query-order-key-case",
+			url:       "http://example.amazonaws.com/?Param2=value2&Param1=value1",
+			signature: "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tc.url, nil)
+
+			require.NoError(t, newSigner().Sign(req, nil))
+
+			assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+			assert.Equal(t,
+				"AWS4-HMAC-SHA256 "+
+					"Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+					"SignedHeaders=host;x-amz-date, "+
+					"Signature="+tc.signature,
+				req.Header.Get("Authorization"))
+		})
+	}
+
+	t.Run("session token", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "http://example.amazonaws.com/", nil)
+
+		signer := newSigner()
+		signer.SessionToken = "token"
+		require.NoError(t, signer.Sign(req, nil))
+
+		assert.Equal(t, "token", req.Header.Get("X-Amz-Security-Token"))
+		assert.Contains(t, req.Header.Get("Authorization"),
+			"SignedHeaders=host;x-amz-date;x-amz-security-token,")
+	})
+
+	t.Run("s3", func(t *testing.T) {
+		req, _ := http.NewRequest("PUT", "http://bucket.s3.amazonaws.com/a%20b", nil)
+
+		signer := newSigner()
+		signer.Service = "s3"
+		require.NoError(t, signer.Sign(req, []byte("body")))
+
+		sum := sha256.Sum256([]byte("body"))
+		assert.Equal(t, hex.EncodeToString(sum[:]),
+			req.Header.Get("X-Amz-Content-Sha256"))
+		assert.Contains(t, req.Header.Get("Authorization"),
+			"SignedHeaders=host;x-amz-content-sha256;x-amz-date,")
+	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "http://example.amazonaws.com/", nil)
+
+		signer := newSigner()
+		signer.SecretAccessKey = ""
+		assert.Error(t, signer.Sign(req, nil))
+
+		signer = newSigner()
+		signer.Region = ""
+		assert.Error(t, signer.Sign(req, nil))
+	})
+
+	t.Run("path encoding", func(t *testing.T) {
+		mustParseURL := func(t *testing.T, s string) *url.URL {
+			u, err := url.Parse(s)
+			require.NoError(t, err)
+			return u
+		}
+
+		assert.Equal(t, "/", awsCanonicalPath(mustParseURL(t, "http://a"), true))
+		assert.Equal(t, "/a%2520b",
+			awsCanonicalPath(mustParseURL(t, "http://a/a%20b"), true))
+		assert.Equal(t, "/a%20b",
+			awsCanonicalPath(mustParseURL(t, "http://a/a%20b"), false))
+		assert.Equal(t, "a=1&a=2&b=%20",
+			awsCanonicalQuery(mustParseURL(t, "http://a/?b=+&a=2&a=1")))
+	})
+}
+
+func TestSignerRequest(t *testing.T) {
+	client := &mockRetryClient{
+		statuses: []int{http.StatusServiceUnavailable, http.StatusOK},
+	}
+
+	config := Config{
+		Client:   client,
+		Reporter: newMockReporter(t),
+	}
+
+	var bodies []string
+
+	NewRequest(config, "POST", "http://example.com/path").
+		WithText("hello").
+		WithTransformer(func(r *http.Request) {
+			r.Header.Set("X-Transformed", "1")
+		}).
+		WithSigner(SignerFunc(func(r *http.Request, body []byte) error {
+			assert.Equal(t, "1", r.Header.Get("X-Transformed"))
+			bodies = append(bodies, string(body))
+			r.Header.Set("Authorization", strings.Repeat("x", len(bodies)))
+			return nil
+		})).
+		WithMaxRetries(1).
+		WithRetryDelay(0, 0).
+		Expect().
+		Status(http.StatusOK).
+		chain.assertOK(t)
+
+	assert.Equal(t, []string{"hello", "hello"}, bodies)
+	assert.Equal(t, []string{"x", "xx"}, client.auth)
+
+	NewRequest(config, "POST", "http://example.com/path").
+		WithSigner(&HMACSigner{}).
+		Expect().
+		chain.assertFailed(t)
+
+	NewRequest(config, "POST", "http://example.com/path").
+		WithBodyStream(strings.NewReader("hello")).
+		WithSigner(&HMACSigner{Key: []byte("secret")}).
+		Expect().
+		chain.assertFailed(t)
+
+	NewRequest(config, "POST", "http://example.com/path").
+		WithSigner(nil).
+		chain.assertFailed(t)
+}
@@ -0,0 +1,321 @@
+package httpexpect
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SnapshotOpts defines additional options for MatchSnapshot methods.
+type SnapshotOpts struct {
+	// Dir is directory where snapshots are stored.
+	// Default is "testdata/snapshots".
+	Dir string
+
+	// Ignore is a list of JSON paths of volatile values, like timestamps
+	// or generated identifiers, in the same format as for MaskJSONPaths.
+	// Matching values are replaced with MaskedValue both in stored
+	// snapshot and in compared value.
+	Ignore []string
+}
+
+// DefaultSnapshotDir is the default directory where snapshots are stored.
+const DefaultSnapshotDir = "testdata/snapshots"
+
+// MatchSnapshot succeeds if response matches snapshot with given name.
+//
+// Snapshot includes response status, Content-Type header, and body.
+// JSON body is stored in canonical form: indented, with sorted keys.
+// Response is sanitized by Config.Sanitizers before snapshotting.
+//
+// Snapshot is stored in "<Dir>/<TestName>/<name>.snap" file, where
+// TestName is taken from Config.TestName (if set). If file does not
+// exist, it is created and assertion succeeds. If test binary is run with
+// "-update" flag set to true (see Bytes.EqualFile), file is overwritten.
+//
+// Example:
+//
+//	resp := e.GET("/users/123").Expect()
+//
+//	resp.MatchSnapshot("user", httpexpect.SnapshotOpts{
+//	    Ignore: []string{"$.created_at"},
+//	})
+func (r *Response) MatchSnapshot(name string, opts ...SnapshotOpts) *Response {
+	r.chain.enter("MatchSnapshot(%q)", name)
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	o, ignore, ok := snapshotOptions(r.chain, opts)
+	if !ok {
+		return r
+	}
+
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "HTTP %d\n", r.httpResp.StatusCode)
+	if ct := r.httpResp.Header.Get("Content-Type"); ct != "" {
+		fmt.Fprintf(&buf, "Content-Type: %s\n", ct)
+	}
+	buf.WriteString("\n")
+
+	body := r.content
+
+	if isJSONContent(r.httpResp.Header) {
+		dec := json.NewDecoder(bytes.NewReader(r.content))
+		dec.UseNumber()
+
+		var value interface{}
+		if err := dec.Decode(&value); err == nil && !dec.More() {
+			if b, err := snapshotJSON(value, ignore); err == nil {
+				body = b
+			}
+		}
+	}
+
+	buf.Write(body)
+	if len(body) != 0 && body[len(body)-1] != '\n' {
+		buf.WriteString("\n")
+	}
+
+	matchSnapshot(r.chain, snapshotPath(r.chain, o.Dir, name, ".snap"), buf.Bytes())
+
+	return r
+}
+
+// MatchSnapshot succeeds if value matches JSON snapshot with given name.
+//
+// Snapshot is stored in canonical form (indented, with sorted keys) in
+// "<Dir>/<TestName>/<name>.json" file. See Response.MatchSnapshot for
+// details about snapshot location and update.
+//
+// Example:
+//
+//	e.GET("/users").Expect().JSON().
+//	    MatchSnapshot("users", httpexpect.SnapshotOpts{
+//	        Ignore: []string{"$[*].id"},
+//	    })
+func (v *Value) MatchSnapshot(name string, opts ...SnapshotOpts) *Value {
+	v.chain.enter("MatchSnapshot(%q)", name)
+	defer v.chain.leave()
+
+	if v.chain.failed() {
+		return v
+	}
+
+	matchJSONSnapshot(v.chain, name, v.value, opts)
+
+	return v
+}
+
+// MatchSnapshot succeeds if object matches JSON snapshot with given name.
+//
+// See Value.MatchSnapshot for details.
+//
+// Example:
+//
+//	e.GET("/users/123").Expect().JSON().Object().
+//	    MatchSnapshot("user")
+func (o *Object) MatchSnapshot(name string, opts ...SnapshotOpts) *Object {
+	o.chain.enter("MatchSnapshot(%q)", name)
+	defer o.chain.leave()
+
+	if o.chain.failed() {
+		return o
+	}
+
+	matchJSONSnapshot(o.chain, name, o.value, opts)
+
+	return o
+}
+
+// MatchSnapshot succeeds if array matches JSON snapshot with given name.
+//
+// See Value.MatchSnapshot for details.
+//
+// Example:
+//
+//	e.GET("/users").Expect().JSON().Array().
+//	    MatchSnapshot("users")
+func (a *Array) MatchSnapshot(name string, opts ...SnapshotOpts) *Array {
+	a.chain.enter("MatchSnapshot(%q)", name)
+	defer a.chain.leave()
+
+	if a.chain.failed() {
+		return a
+	}
+
+	matchJSONSnapshot(a.chain, name, a.value, opts)
+
+	return a
+}
+
+func matchJSONSnapshot(
+	chain *chain, name string, value interface{}, opts []SnapshotOpts,
+) {
+	o, ignore, ok := snapshotOptions(chain, opts)
+	if !ok {
+		return
+	}
+
+	// round-trip to get a copy that can be modified
+	b, err := json.Marshal(value)
+	if err == nil {
+		value = nil
+		err = json.Unmarshal(b, &value)
+	}
+	if err == nil {
+		b, err = snapshotJSON(value, ignore)
+	}
+	if err != nil {
+		chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{value},
+			Errors: []error{
+				errors.New("expected: value can be encoded to JSON"),
+				err,
+			},
+		})
+		return
+	}
+
+	matchSnapshot(chain, snapshotPath(chain, o.Dir, name, ".json"), b)
+}
+
+func snapshotOptions(
+	chain *chain, opts []SnapshotOpts,
+) (SnapshotOpts, [][]maskPathStep, bool) {
+	if len(opts) > 1 {
+		chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple opts arguments"),
+			},
+		})
+		return SnapshotOpts{}, nil, false
+	}
+
+	var o SnapshotOpts
+	if len(opts) == 1 {
+		o = opts[0]
+	}
+
+	if o.Dir == "" {
+		o.Dir = DefaultSnapshotDir
+	}
+
+	var ignore [][]maskPathStep
+
+	for _, path := range o.Ignore {
+		steps, err := parseMaskPath(path)
+		if err != nil {
+			chain.fail(AssertionFailure{
+				Type:   AssertUsage,
+				Actual: &AssertionValue{path},
+				Errors: []error{
+					errors.New("expected: valid json path"),
+					err,
+				},
+			})
+			return o, nil, false
+		}
+		ignore = append(ignore, steps)
+	}
+
+	return o, ignore, true
+}
+
+// value is modified in place
+func snapshotJSON(value interface{}, ignore [][]maskPathStep) ([]byte, error) {
+	for _, steps := range ignore {
+		if masked, ok := maskJSONPath(value, steps); ok {
+			value = masked
+		}
+	}
+
+	b, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(b, '\n'), nil
+}
+
+var snapshotUnsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func snapshotPath(chain *chain, dir, name, ext string) string {
+	var parts []string
+
+	parts = append(parts, dir)
+
+	// subtests are stored in nested directories
+	if chain.context.TestName != "" {
+		for _, part := range strings.Split(chain.context.TestName, "/") {
+			parts = append(parts, snapshotUnsafeChars.ReplaceAllString(part, "_"))
+		}
+	}
+
+	parts = append(parts, snapshotUnsafeChars.ReplaceAllString(name, "_")+ext)
+
+	return filepath.Join(parts...)
+}
+
+func matchSnapshot(chain *chain, path string, actual []byte) {
+	expected, err := ioutil.ReadFile(path)
+
+	if goldenUpdate() || os.IsNotExist(err) {
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = ioutil.WriteFile(path, actual, 0644)
+		}
+		if err != nil {
+			chain.fail(AssertionFailure{
+				Type: AssertOperation,
+				Errors: []error{
+					errors.New("failed to write snapshot"),
+					err,
+				},
+			})
+		}
+		return
+	}
+
+	if err != nil {
+		chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to read snapshot"),
+				err,
+			},
+		})
+		return
+	}
+
+	if bytes.Equal(actual, expected) {
+		return
+	}
+
+	// decoded JSON gives more readable diff
+	var actualValue, expectedValue interface{}
+	if json.Unmarshal(actual, &actualValue) != nil ||
+		json.Unmarshal(expected, &expectedValue) != nil {
+		actualValue, expectedValue = string(actual), string(expected)
+	}
+
+	chain.fail(AssertionFailure{
+		Type:     AssertEqual,
+		Actual:   &AssertionValue{actualValue},
+		Expected: &AssertionValue{expectedValue},
+		Errors: []error{
+			fmt.Errorf("expected: value matches snapshot %q", path),
+		},
+	})
+}
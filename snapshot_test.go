@@ -0,0 +1,170 @@
+package httpexpect
+
+import (
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotResponse(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	body := `{"name": "john", "id": 123, "created_at": "2020-01-01"}`
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	})
+
+	e := WithConfig(Config{
+		TestName: "TestUsers/get",
+		Client:   &http.Client{Transport: NewBinder(handler)},
+		Reporter: newMockReporter(t),
+	})
+
+	opts := SnapshotOpts{
+		Dir:    dir,
+		Ignore: []string{"$.created_at"},
+	}
+
+	resp := e.GET("/users/123").Expect()
+	resp.MatchSnapshot("user", opts)
+	resp.chain.assertOK(t)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "TestUsers", "get", "user.snap"))
+	require.NoError(t, err)
+	assert.Equal(t, `HTTP 200
+Content-Type: application/json
+
+{
+  "created_at": "***",
+  "id": 123,
+  "name": "john"
+}
+`, string(data))
+
+	body = `{"id": 123, "name": "john", "created_at": "2021-01-01"}`
+
+	resp = e.GET("/users/123").Expect()
+	resp.MatchSnapshot("user", opts)
+	resp.chain.assertOK(t)
+
+	body = `{"id": 123, "name": "bob", "created_at": "2021-01-01"}`
+
+	resp = e.GET("/users/123").Expect()
+	resp.MatchSnapshot("user", opts)
+	resp.chain.assertFailed(t)
+
+	resp = e.GET("/users/123").Expect()
+	resp.MatchSnapshot("user", SnapshotOpts{Dir: dir, Ignore: []string{"bad"}})
+	resp.chain.assertFailed(t)
+
+	resp = e.GET("/users/123").Expect()
+	resp.MatchSnapshot("user", opts, opts)
+	resp.chain.assertFailed(t)
+}
+
+func TestSnapshotText(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	e := WithConfig(Config{
+		Client:   &http.Client{Transport: NewBinder(handler)},
+		Reporter: newMockReporter(t),
+	})
+
+	resp := e.GET("/").Expect()
+	resp.MatchSnapshot("text/plain", SnapshotOpts{Dir: dir})
+	resp.chain.assertOK(t)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "text_plain.snap"))
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP 202\nContent-Type: text/plain\n\nhello\n", string(data))
+}
+
+func TestSnapshotJSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpexpect")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	reporter := newMockReporter(t)
+
+	opts := SnapshotOpts{
+		Dir:    dir,
+		Ignore: []string{"$[*].id"},
+	}
+
+	value := []interface{}{
+		map[string]interface{}{"id": 1, "name": "a"},
+		map[string]interface{}{"id": 2, "name": "b"},
+	}
+
+	NewValue(reporter, value).MatchSnapshot("list", opts).chain.assertOK(t)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "list.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `[
+  {
+    "id": "***",
+    "name": "a"
+  },
+  {
+    "id": "***",
+    "name": "b"
+  }
+]
+`, string(data))
+
+	// ignored values are not modified in original value
+	assert.Equal(t, 1, value[0].(map[string]interface{})["id"])
+
+	value[0].(map[string]interface{})["id"] = 10
+
+	NewArray(reporter, value).MatchSnapshot("list", opts).chain.assertOK(t)
+	NewValue(reporter, value).MatchSnapshot("list", opts).chain.assertOK(t)
+
+	NewArray(reporter, value[:1]).MatchSnapshot("list", opts).chain.assertFailed(t)
+
+	obj := map[string]interface{}{"a": 1}
+
+	NewObject(reporter, obj).MatchSnapshot("obj", SnapshotOpts{Dir: dir}).chain.assertOK(t)
+	NewObject(reporter, obj).MatchSnapshot("obj", SnapshotOpts{Dir: dir}).chain.assertOK(t)
+
+	obj["a"] = 2
+
+	NewObject(reporter, obj).MatchSnapshot("obj", SnapshotOpts{Dir: dir}).
+		chain.assertFailed(t)
+
+	t.Run("update", func(t *testing.T) {
+		prevFlag := goldenUpdateFlag
+		goldenUpdateFlag = "httpexpect-test-update"
+		defer func() {
+			goldenUpdateFlag = prevFlag
+		}()
+
+		require.NoError(t, flag.Set("httpexpect-test-update", "true"))
+		defer func() {
+			*testGoldenUpdate = false
+		}()
+
+		NewObject(reporter, obj).MatchSnapshot("obj", SnapshotOpts{Dir: dir}).
+			chain.assertOK(t)
+	})
+
+	NewObject(reporter, obj).MatchSnapshot("obj", SnapshotOpts{Dir: dir}).chain.assertOK(t)
+}
@@ -0,0 +1,126 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Soft invokes given function with a copy of response in soft mode.
+//
+// In soft mode, a failed assertion doesn't stop the following assertions:
+// every assertion made inside the function is checked, and all failures are
+// collected. When the function returns, collected failures are reported
+// together as a single failure of Soft(), so one test run shows everything
+// that is wrong with the response, instead of only the first problem.
+//
+// Assertions that are derived from a failed assertion are still skipped,
+// e.g. if JSON() fails, nothing is checked on the returned Value.
+//
+// Example:
+//
+//	resp := NewResponse(t, response)
+//
+//	resp.Soft(func(resp *httpexpect.Response) {
+//	    resp.Status(http.StatusOK)
+//	    resp.Header("Content-Type").Equal("application/json")
+//	    resp.JSON().Object().ContainsKey("id")
+//	})
+func (r *Response) Soft(fn func(resp *Response)) *Response {
+	r.chain.enter("Soft()")
+	defer r.chain.leave()
+
+	if r.chain.failed() {
+		return r
+	}
+
+	if fn == nil {
+		r.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil argument"),
+			},
+		})
+		return r
+	}
+
+	soft := *r
+	soft.chain = r.chain.clone()
+
+	collector := soft.chain.setSoft()
+
+	fn(&soft)
+
+	if len(collector.failures) == 0 {
+		return r
+	}
+
+	r.chain.fail(collector.failure(r.config.Formatter))
+
+	return r
+}
+
+// combines all collected failures into one; every failure is formatted
+// using given formatter, so that its options are respected
+func (s *softCollector) failure(formatter Formatter) AssertionFailure {
+	failure := AssertionFailure{
+		Type: s.failures[0].failure.Type,
+		Errors: []error{
+			fmt.Errorf("%d soft assertion(s) failed", len(s.failures)),
+		},
+	}
+
+	if formatter == nil {
+		formatter = &DefaultFormatter{}
+	}
+
+	for n, f := range s.failures {
+		df, ok := formatter.(*DefaultFormatter)
+		if !ok {
+			// custom formatter, use its message as is
+			msg := formatter.FormatFailure(&f.context, &f.failure)
+
+			failure.Errors = append(failure.Errors,
+				fmt.Errorf("[%d] %s", n+1, softIndent(strings.TrimSpace(msg))))
+			continue
+		}
+
+		data := df.buildFormatData(&f.context, &f.failure)
+
+		var b strings.Builder
+
+		fmt.Fprintf(&b, "[%d]", n+1)
+
+		if !df.DisablePaths {
+			fmt.Fprintf(&b, " %s", strings.Join(f.context.Path, "."))
+		}
+
+		for _, err := range data.Errors {
+			fmt.Fprintf(&b, "\n  %s", err)
+		}
+
+		if data.HaveExpected {
+			verb := "expected"
+			if data.IsNegation {
+				verb = "denied"
+			} else if data.IsComparison {
+				verb = "compared"
+			}
+
+			fmt.Fprintf(&b, "\n  %s %s: %s", verb, data.ExpectedKind,
+				softIndent(strings.Join(data.Expected, ", ")))
+		}
+
+		if data.HaveActual {
+			fmt.Fprintf(&b, "\n  actual value: %s", softIndent(data.Actual))
+		}
+
+		failure.Errors = append(failure.Errors, errors.New(b.String()))
+	}
+
+	return failure
+}
+
+func softIndent(s string) string {
+	return strings.ReplaceAll(s, "\n", "\n  ")
+}
@@ -0,0 +1,207 @@
+package httpexpect
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSoftResponse(t *testing.T, handler AssertionHandler) *Response {
+	e := WithConfig(Config{
+		BaseURL: "http://example.com",
+		Client: &mockClient{
+			resp: http.Response{
+				StatusCode: http.StatusOK,
+			},
+		},
+		AssertionHandler: handler,
+	})
+
+	return e.GET("/").WithJSON(map[string]interface{}{"id": 1}).Expect()
+}
+
+func TestSoftSuccess(t *testing.T) {
+	handler := &mockAssertionHandler{}
+
+	resp := newSoftResponse(t, handler)
+
+	called := false
+
+	resp.Soft(func(resp *Response) {
+		called = true
+
+		resp.Status(http.StatusOK)
+		resp.Header("Content-Type").Equal("application/json; charset=utf-8")
+		resp.JSON().Object().ContainsKey("id")
+	})
+
+	assert.True(t, called)
+	assert.Nil(t, handler.failure)
+	resp.chain.assertOK(t)
+}
+
+func TestSoftFailures(t *testing.T) {
+	handler := &mockAssertionHandler{}
+
+	resp := newSoftResponse(t, handler)
+
+	count := 0
+
+	resp.Soft(func(resp *Response) {
+		resp.Status(http.StatusNotFound)
+
+		// checked despite the previous failure
+		resp.Header("Content-Type").Equal("text/plain")
+		resp.JSON().Object().ContainsKey("name")
+
+		// skipped, because Value() failed on the same object
+		obj := resp.JSON().Object()
+		obj.Value("missing").Number().Equal(1)
+		obj.Value("id").Number().Equal(2)
+
+		resp.Header("Content-Type").Equal("application/json; charset=utf-8")
+
+		count++
+	})
+
+	assert.Equal(t, 1, count)
+	resp.chain.assertFailed(t)
+
+	require.NotNil(t, handler.failure)
+
+	failure := handler.failure
+
+	assert.Equal(t, AssertEqual, failure.Type)
+
+	if assert.Equal(t, 5, len(failure.Errors)) {
+		assert.Equal(t, "4 soft assertion(s) failed", failure.Errors[0].Error())
+
+		assert.Contains(t, failure.Errors[1].Error(),
+			`[1] Request("GET").Expect().Soft().Status()`)
+		assert.Contains(t, failure.Errors[1].Error(), "404 Not Found")
+		assert.Contains(t, failure.Errors[1].Error(), "200 OK")
+
+		assert.Contains(t, failure.Errors[2].Error(),
+			`[2] Request("GET").Expect().Soft().Header("Content-Type").Equal()`)
+		assert.Contains(t, failure.Errors[2].Error(), `expected value: "text/plain"`)
+		assert.Contains(t, failure.Errors[2].Error(),
+			`actual value: "application/json; charset=utf-8"`)
+
+		assert.Contains(t, failure.Errors[3].Error(),
+			"[3] Request(\"GET\").Expect().Soft().JSON().Object().ContainsKey()")
+
+		assert.Contains(t, failure.Errors[4].Error(),
+			`[4] Request("GET").Expect().Soft().JSON().Object().Value("missing")`)
+	}
+}
+
+func TestSoftFormatter(t *testing.T) {
+	newResponse := func(
+		handler AssertionHandler, formatter Formatter,
+	) *Response {
+		e := WithConfig(Config{
+			BaseURL: "http://example.com",
+			Client: &mockClient{
+				resp: http.Response{
+					StatusCode: http.StatusOK,
+				},
+			},
+			AssertionHandler: handler,
+			Formatter:        formatter,
+		})
+
+		return e.GET("/").WithJSON(map[string]interface{}{"id": 1}).Expect()
+	}
+
+	t.Run("default formatter options", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		resp := newResponse(handler, &DefaultFormatter{
+			DisablePaths: true,
+			Config: FormatterConfig{
+				MaxValueLength: 5,
+			},
+		})
+
+		resp.Soft(func(resp *Response) {
+			resp.Header("Content-Type").Equal("text/plain")
+		})
+
+		require.NotNil(t, handler.failure)
+		require.Equal(t, 2, len(handler.failure.Errors))
+
+		msg := handler.failure.Errors[1].Error()
+
+		assert.NotContains(t, msg, "Soft()")
+		assert.Contains(t, msg, `actual value: "appl... (28 more bytes)`)
+	})
+
+	t.Run("custom formatter", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		formatter := newMockFormatter(t)
+
+		resp := newResponse(handler, formatter)
+
+		resp.Soft(func(resp *Response) {
+			resp.Status(http.StatusNotFound)
+			resp.Header("Content-Type").Equal("text/plain")
+		})
+
+		require.NotNil(t, handler.failure)
+		require.Equal(t, 3, len(handler.failure.Errors))
+
+		assert.Equal(t, 2, formatter.formattedFailure)
+		assert.Equal(t, "[1] ", handler.failure.Errors[1].Error())
+		assert.Equal(t, "[2] ", handler.failure.Errors[2].Error())
+	})
+}
+
+func TestSoftChain(t *testing.T) {
+	t.Run("nil func", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		resp := newSoftResponse(t, handler)
+		resp.Soft(nil)
+
+		resp.chain.assertFailed(t)
+
+		require.NotNil(t, handler.failure)
+		assert.Equal(t, AssertUsage, handler.failure.Type)
+	})
+
+	t.Run("failed response", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		resp := newSoftResponse(t, handler)
+		resp.chain.setFailed()
+
+		called := false
+
+		resp.Soft(func(resp *Response) {
+			called = true
+		})
+
+		assert.False(t, called)
+	})
+
+	t.Run("after soft", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		resp := newSoftResponse(t, handler)
+
+		resp.Soft(func(resp *Response) {
+			resp.Status(http.StatusNotFound)
+		})
+
+		resp.chain.assertFailed(t)
+
+		handler.failure = nil
+
+		resp.Status(http.StatusNotFound)
+
+		assert.Nil(t, handler.failure)
+	})
+}
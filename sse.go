@@ -0,0 +1,369 @@
+package httpexpect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSEStream provides methods to read Server-Sent Events from response body.
+//
+// Events are parsed according to the HTML Living Standard, section
+// "Server-sent events": lines are separated by LF or CRLF, lines starting
+// with colon are comments, and each event is terminated by empty line.
+type SSEStream struct {
+	chain *chain
+	body  *BodyStream
+
+	readTimeout time.Duration
+
+	buf         []byte
+	cur         sseFields
+	lastEventID string
+
+	isClosed bool
+}
+
+// fields of event being parsed
+type sseFields struct {
+	name    string
+	data    strings.Builder
+	hasData bool
+	retry   *time.Duration
+}
+
+// NewSSEStream returns a new SSEStream instance.
+//
+// reporter should not be nil. If reader is nil, failure is reported.
+//
+// Example:
+//
+//	sse := NewSSEStream(t, resp.Body)
+//	defer sse.Disconnect()
+//
+//	sse.NextEvent().Data().Equal("hello")
+func NewSSEStream(reporter Reporter, reader io.ReadCloser) *SSEStream {
+	chain := newChainWithDefaults("SSEStream()", reporter)
+
+	if reader == nil {
+		chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil argument"),
+			},
+		})
+		return newSSEStream(chain, nil)
+	}
+
+	return newSSEStream(chain, newBodyStream(chain, reader))
+}
+
+func newSSEStream(parent *chain, body *BodyStream) *SSEStream {
+	return &SSEStream{
+		chain: parent.clone(),
+		body:  body,
+	}
+}
+
+// WithReadTimeout sets timeout duration for reading events.
+//
+// By default no timeout is used, though request timeout and deadline,
+// if set, still apply.
+func (s *SSEStream) WithReadTimeout(timeout time.Duration) *SSEStream {
+	s.chain.enter("WithReadTimeout()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	s.readTimeout = timeout
+
+	return s
+}
+
+// WithoutReadTimeout removes timeout for reading events.
+func (s *SSEStream) WithoutReadTimeout() *SSEStream {
+	s.chain.enter("WithoutReadTimeout()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	s.readTimeout = noDuration
+
+	return s
+}
+
+// NextEvent reads next event from stream and returns a new SSEEvent
+// instance.
+//
+// NextEvent blocks until event is received. If read timeout expires,
+// stream ends, or read fails, failure is reported.
+//
+// Example:
+//
+//	sse := resp.SSE()
+//	event := sse.NextEvent()
+//	event.Name().Equal("update")
+//	event.JSON().Object().ValueEqual("id", 1)
+func (s *SSEStream) NextEvent() *SSEEvent {
+	s.chain.enter("NextEvent()")
+	defer s.chain.leave()
+
+	if s.checkUnusable("NextEvent()") {
+		return newSSEEvent(s.chain)
+	}
+
+	var timer <-chan time.Time
+	if s.readTimeout > 0 {
+		t := time.NewTimer(s.readTimeout)
+		defer t.Stop()
+		timer = t.C
+	}
+
+	event, err := s.readEvent(timer)
+	if err != nil {
+		s.failRead(err, s.readTimeout)
+		return newSSEEvent(s.chain)
+	}
+
+	return event
+}
+
+// NextEvents reads n events from stream within given timeout and returns
+// a slice of n new SSEEvent instances.
+//
+// If fewer than n events are received before timeout expires or stream
+// ends, failure is reported. Returned slice always has n elements.
+//
+// Example:
+//
+//	sse := resp.SSE()
+//	events := sse.NextEvents(3, time.Second)
+//	events[2].Data().Equal("done")
+func (s *SSEStream) NextEvents(n int, timeout time.Duration) []*SSEEvent {
+	s.chain.enter("NextEvents(%d)", n)
+	defer s.chain.leave()
+
+	if n < 0 {
+		s.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{n},
+			Errors: []error{
+				errors.New("expected: non-negative number of events"),
+			},
+		})
+		return []*SSEEvent{}
+	}
+
+	events := make([]*SSEEvent, 0, n)
+
+	fill := func() []*SSEEvent {
+		for len(events) < n {
+			events = append(events, newSSEEvent(s.chain))
+		}
+		return events
+	}
+
+	if s.checkUnusable("NextEvents()") {
+		return fill()
+	}
+
+	if timeout <= 0 {
+		s.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{timeout},
+			Errors: []error{
+				errors.New("expected: positive timeout"),
+			},
+		})
+		return fill()
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for len(events) < n {
+		event, err := s.readEvent(timer.C)
+		if err != nil {
+			if err == errBodyStreamTimeout {
+				s.chain.fail(AssertionFailure{
+					Type:     AssertEqual,
+					Actual:   &AssertionValue{len(events)},
+					Expected: &AssertionValue{n},
+					Errors: []error{
+						fmt.Errorf(
+							"expected: %d events received within %s", n, timeout),
+					},
+				})
+			} else {
+				s.failRead(err, timeout)
+			}
+			return fill()
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// Disconnect closes the underlying response body.
+//
+// It's okay to call this function multiple times.
+//
+// It's recommended to always call this function after stream usage is over
+// to ensure that no resource leaks will happen.
+//
+// Example:
+//
+//	sse := resp.SSE()
+//	defer sse.Disconnect()
+func (s *SSEStream) Disconnect() *SSEStream {
+	s.chain.enter("Disconnect()")
+	defer s.chain.leave()
+
+	if s.body == nil || s.isClosed {
+		return s
+	}
+
+	s.isClosed = true
+	s.body.Close()
+
+	return s
+}
+
+func (s *SSEStream) checkUnusable(where string) bool {
+	switch {
+	case s.chain.failed():
+		return true
+
+	case s.body == nil:
+		s.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				fmt.Errorf("unexpected %s call for failed SSE stream", where),
+			},
+		})
+		return true
+
+	case s.isClosed:
+		s.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				fmt.Errorf("unexpected %s call for disconnected SSE stream", where),
+			},
+		})
+		return true
+	}
+
+	return false
+}
+
+func (s *SSEStream) failRead(err error, timeout time.Duration) {
+	if err == errBodyStreamTimeout {
+		s.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				fmt.Errorf("no SSE event received within %s", timeout),
+			},
+		})
+		return
+	}
+
+	failStreamRead(s.chain, err)
+}
+
+// reads lines until complete event is dispatched; parser state is kept
+// between calls, so partially received event survives timeout
+func (s *SSEStream) readEvent(timer <-chan time.Time) (*SSEEvent, error) {
+	for {
+		line, err := s.readLine(timer)
+		if err != nil {
+			return nil, err
+		}
+
+		if line == "" {
+			if event := s.dispatch(); event != nil {
+				return event, nil
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value := line, ""
+		if idx := strings.IndexByte(line, ':'); idx >= 0 {
+			field, value = line[:idx], strings.TrimPrefix(line[idx+1:], " ")
+		}
+
+		switch field {
+		case "event":
+			s.cur.name = value
+
+		case "data":
+			s.cur.data.WriteString(value)
+			s.cur.data.WriteByte('\n')
+			s.cur.hasData = true
+
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				s.lastEventID = value
+			}
+
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 32); err == nil {
+				retry := time.Duration(ms) * time.Millisecond
+				s.cur.retry = &retry
+			}
+		}
+	}
+}
+
+// returns event built from accumulated fields, or nil if there is no data
+func (s *SSEStream) dispatch() *SSEEvent {
+	defer func() {
+		s.cur = sseFields{}
+	}()
+
+	if !s.cur.hasData {
+		return nil
+	}
+
+	event := newSSEEvent(s.chain)
+
+	event.name = s.cur.name
+	if event.name == "" {
+		event.name = "message"
+	}
+	event.data = strings.TrimSuffix(s.cur.data.String(), "\n")
+	event.id = s.lastEventID
+	event.retry = s.cur.retry
+
+	return event
+}
+
+func (s *SSEStream) readLine(timer <-chan time.Time) (string, error) {
+	for {
+		if idx := bytes.IndexByte(s.buf, '\n'); idx >= 0 {
+			line := strings.TrimSuffix(string(s.buf[:idx]), "\r")
+			s.buf = s.buf[idx+1:]
+			return line, nil
+		}
+
+		data, err := s.body.receive(timer)
+		if err != nil {
+			return "", err
+		}
+
+		s.buf = append(s.buf, data...)
+	}
+}
@@ -0,0 +1,166 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// SSEEvent provides methods to inspect event read from SSEStream.
+type SSEEvent struct {
+	chain *chain
+	name  string
+	data  string
+	id    string
+	retry *time.Duration
+}
+
+// NewSSEEvent returns a new SSEEvent instance.
+//
+// reporter should not be nil. If name is empty, "message" is used, like
+// for events without "event" field.
+//
+// Example:
+//
+//	event := NewSSEEvent(t, "update", `{"id":1}`, "42")
+//	event.JSON().Object().ValueEqual("id", 1)
+func NewSSEEvent(reporter Reporter, name, data, id string) *SSEEvent {
+	e := newSSEEvent(newChainWithDefaults("SSEEvent()", reporter))
+
+	e.name = name
+	if e.name == "" {
+		e.name = "message"
+	}
+	e.data = data
+	e.id = id
+
+	return e
+}
+
+func newSSEEvent(parent *chain) *SSEEvent {
+	return &SSEEvent{
+		chain: parent.clone(),
+	}
+}
+
+// Raw returns underlying name, data, and id of event.
+func (e *SSEEvent) Raw() (name, data, id string) {
+	return e.name, e.data, e.id
+}
+
+// Name returns a new String instance with event name, i.e. value of
+// "event" field, or "message" if field is missing.
+//
+// Example:
+//
+//	event := sse.NextEvent()
+//	event.Name().Equal("update")
+func (e *SSEEvent) Name() *String {
+	e.chain.enter("Name()")
+	defer e.chain.leave()
+
+	if e.chain.failed() {
+		return newString(e.chain, "")
+	}
+
+	return newString(e.chain, e.name)
+}
+
+// Data returns a new String instance with event data. Multiple "data"
+// fields are joined with newline.
+//
+// Example:
+//
+//	event := sse.NextEvent()
+//	event.Data().Equal("hello")
+func (e *SSEEvent) Data() *String {
+	e.chain.enter("Data()")
+	defer e.chain.leave()
+
+	if e.chain.failed() {
+		return newString(e.chain, "")
+	}
+
+	return newString(e.chain, e.data)
+}
+
+// JSON returns a new Value instance with JSON decoded from event data.
+//
+// JSON succeeds if JSON may be decoded from event data.
+//
+// Example:
+//
+//	event := sse.NextEvent()
+//	event.JSON().Object().ValueEqual("status", "ok")
+func (e *SSEEvent) JSON() *Value {
+	e.chain.enter("JSON()")
+	defer e.chain.leave()
+
+	if e.chain.failed() {
+		return newValue(e.chain, nil)
+	}
+
+	var value interface{}
+
+	if err := json.Unmarshal([]byte(e.data), &value); err != nil {
+		e.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{e.data},
+			Errors: []error{
+				errors.New("failed to decode json"),
+				err,
+			},
+		})
+		return newValue(e.chain, nil)
+	}
+
+	return newValue(e.chain, value)
+}
+
+// ID returns a new String instance with last event ID, i.e. value of
+// the most recent "id" field received in stream.
+//
+// Example:
+//
+//	event := sse.NextEvent()
+//	event.ID().Equal("42")
+func (e *SSEEvent) ID() *String {
+	e.chain.enter("ID()")
+	defer e.chain.leave()
+
+	if e.chain.failed() {
+		return newString(e.chain, "")
+	}
+
+	return newString(e.chain, e.id)
+}
+
+// Retry returns a new Duration instance with reconnection time, i.e.
+// value of "retry" field received along with event.
+//
+// Retry fails if event has no valid "retry" field.
+//
+// Example:
+//
+//	event := sse.NextEvent()
+//	event.Retry().Equal(3 * time.Second)
+func (e *SSEEvent) Retry() *Duration {
+	e.chain.enter("Retry()")
+	defer e.chain.leave()
+
+	if e.chain.failed() {
+		return newDuration(e.chain, nil)
+	}
+
+	if e.retry == nil {
+		e.chain.fail(AssertionFailure{
+			Type: AssertNotNil,
+			Errors: []error{
+				errors.New(`expected: event has "retry" field`),
+			},
+		})
+		return newDuration(e.chain, nil)
+	}
+
+	return newDuration(e.chain, e.retry)
+}
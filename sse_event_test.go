@@ -0,0 +1,74 @@
+package httpexpect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSSEEventFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	event := newSSEEvent(chain)
+
+	event.chain.assertFailed(t)
+
+	assert.NotNil(t, event.Name())
+	assert.NotNil(t, event.Data())
+	assert.NotNil(t, event.JSON())
+	assert.NotNil(t, event.ID())
+	assert.NotNil(t, event.Retry())
+}
+
+func TestSSEEventGetters(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	event := NewSSEEvent(reporter, "update", `{"id":1}`, "42")
+
+	name, data, id := event.Raw()
+	assert.Equal(t, "update", name)
+	assert.Equal(t, `{"id":1}`, data)
+	assert.Equal(t, "42", id)
+
+	event.Name().Equal("update").chain.assertOK(t)
+	event.Data().Equal(`{"id":1}`).chain.assertOK(t)
+	event.ID().Equal("42").chain.assertOK(t)
+
+	event.JSON().Object().ValueEqual("id", 1).chain.assertOK(t)
+	event.chain.assertOK(t)
+}
+
+func TestSSEEventDefaultName(t *testing.T) {
+	event := NewSSEEvent(newMockReporter(t), "", "data", "")
+
+	event.Name().Equal("message").chain.assertOK(t)
+	event.ID().Empty().chain.assertOK(t)
+}
+
+func TestSSEEventBadJSON(t *testing.T) {
+	event := NewSSEEvent(newMockReporter(t), "", "{bad", "")
+
+	event.JSON().chain.assertFailed(t)
+	event.chain.assertFailed(t)
+}
+
+func TestSSEEventRetry(t *testing.T) {
+	t.Run("present", func(t *testing.T) {
+		event := NewSSEEvent(newMockReporter(t), "", "data", "")
+
+		retry := 3 * time.Second
+		event.retry = &retry
+
+		event.Retry().Equal(3 * time.Second).chain.assertOK(t)
+		event.chain.assertOK(t)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		event := NewSSEEvent(newMockReporter(t), "", "data", "")
+
+		event.Retry().chain.assertFailed(t)
+		event.chain.assertFailed(t)
+	})
+}
@@ -0,0 +1,199 @@
+package httpexpect
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSEStreamFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	body := newBodyStream(chain, ioutil.NopCloser(strings.NewReader("data: x\n\n")))
+
+	sse := newSSEStream(chain, body)
+	defer sse.Disconnect()
+
+	sse.chain.assertFailed(t)
+
+	assert.NotNil(t, sse.WithReadTimeout(time.Second))
+	assert.NotNil(t, sse.WithoutReadTimeout())
+	assert.NotNil(t, sse.NextEvent())
+	assert.Equal(t, 2, len(sse.NextEvents(2, time.Second)))
+
+	sse.Disconnect()
+}
+
+func TestSSEStreamNil(t *testing.T) {
+	sse := NewSSEStream(newMockReporter(t), nil)
+
+	sse.chain.assertFailed(t)
+
+	assert.NotNil(t, sse.NextEvent())
+	assert.Equal(t, 1, len(sse.NextEvents(1, time.Second)))
+
+	sse.Disconnect()
+}
+
+func TestSSEStreamParse(t *testing.T) {
+	input := ": comment\n" +
+		"retry: 1500\n" +
+		"data: first\n" +
+		"\n" +
+		"event: update\r\n" +
+		"id: 42\r\n" +
+		"data:line1\r\n" +
+		"data: line2\r\n" +
+		"\r\n" +
+		"event: ignored\n" +
+		"\n" +
+		"data\n" +
+		"unknown: field\n" +
+		"\n" +
+		"data: incomplete\n"
+
+	sse := NewSSEStream(newMockReporter(t),
+		ioutil.NopCloser(strings.NewReader(input)))
+	defer sse.Disconnect()
+
+	event := sse.NextEvent()
+	event.chain.assertOK(t)
+	event.Name().Equal("message")
+	event.Data().Equal("first")
+	event.ID().Empty()
+	event.Retry().Equal(1500 * time.Millisecond)
+	event.chain.assertOK(t)
+
+	event = sse.NextEvent()
+	event.chain.assertOK(t)
+	event.Name().Equal("update")
+	event.Data().Equal("line1\nline2")
+	event.ID().Equal("42")
+	event.Retry().chain.assertFailed(t)
+
+	event = sse.NextEvent()
+	event.chain.assertOK(t)
+	event.Name().Equal("message")
+	event.Data().Empty()
+	event.ID().Equal("42")
+	event.chain.assertOK(t)
+
+	sse.chain.assertOK(t)
+
+	sse.NextEvent().chain.assertFailed(t)
+	sse.chain.assertFailed(t)
+}
+
+func TestSSEStreamReadTimeout(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	sse := NewSSEStream(newMockReporter(t), reader)
+	defer sse.Disconnect()
+
+	sse.WithReadTimeout(50 * time.Millisecond)
+
+	go func() {
+		_, _ = writer.Write([]byte("data: partial"))
+	}()
+
+	sse.NextEvent().chain.assertFailed(t)
+	sse.chain.assertFailed(t)
+}
+
+func TestSSEStreamPartialEvent(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	sse := NewSSEStream(newMockReporter(t), reader)
+	defer sse.Disconnect()
+
+	go func() {
+		_, _ = writer.Write([]byte("event: up"))
+		_, _ = writer.Write([]byte("date\ndata: {\"id\""))
+		_, _ = writer.Write([]byte(":1}\n\n"))
+	}()
+
+	event := sse.NextEvent()
+	event.Name().Equal("update")
+	event.JSON().Object().ValueEqual("id", 1)
+	event.chain.assertOK(t)
+}
+
+func TestSSEStreamNextEvents(t *testing.T) {
+	t.Run("received", func(t *testing.T) {
+		sse := NewSSEStream(newMockReporter(t), ioutil.NopCloser(strings.NewReader(
+			"data: 1\n\ndata: 2\n\ndata: 3\n\n")))
+		defer sse.Disconnect()
+
+		events := sse.NextEvents(2, time.Second)
+		sse.chain.assertOK(t)
+
+		require.Equal(t, 2, len(events))
+		events[0].Data().Equal("1").chain.assertOK(t)
+		events[1].Data().Equal("2").chain.assertOK(t)
+
+		sse.NextEvent().Data().Equal("3").chain.assertOK(t)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		reader, writer := io.Pipe()
+		defer writer.Close()
+
+		sse := NewSSEStream(newMockReporter(t), reader)
+		defer sse.Disconnect()
+
+		go func() {
+			_, _ = writer.Write([]byte("data: 1\n\n"))
+		}()
+
+		events := sse.NextEvents(2, 100*time.Millisecond)
+		sse.chain.assertFailed(t)
+
+		require.Equal(t, 2, len(events))
+	})
+
+	t.Run("eof", func(t *testing.T) {
+		sse := NewSSEStream(newMockReporter(t), ioutil.NopCloser(strings.NewReader(
+			"data: 1\n\n")))
+		defer sse.Disconnect()
+
+		events := sse.NextEvents(2, time.Second)
+		sse.chain.assertFailed(t)
+
+		require.Equal(t, 2, len(events))
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		sse := NewSSEStream(newMockReporter(t), ioutil.NopCloser(strings.NewReader(
+			"data: 1\n\n")))
+		defer sse.Disconnect()
+
+		assert.Equal(t, 0, len(sse.NextEvents(-1, time.Second)))
+		sse.chain.assertFailed(t)
+		sse.chain.reset()
+
+		assert.Equal(t, 1, len(sse.NextEvents(1, 0)))
+		sse.chain.assertFailed(t)
+	})
+}
+
+func TestSSEStreamDisconnect(t *testing.T) {
+	sse := NewSSEStream(newMockReporter(t), ioutil.NopCloser(strings.NewReader(
+		"data: 1\n\n")))
+
+	sse.Disconnect()
+	sse.chain.assertOK(t)
+
+	sse.Disconnect()
+	sse.chain.assertOK(t)
+
+	sse.NextEvent().chain.assertFailed(t)
+	sse.chain.assertFailed(t)
+}
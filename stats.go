@@ -0,0 +1,511 @@
+package httpexpect
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Stats collects latency, status, and size of executed requests.
+//
+// Stats is populated from Request.Expect when it's set in Config.Stats.
+// Collected statistics can be inspected via Expect.Stats, e.g. to check
+// latency percentiles at the end of a smoke test suite.
+//
+// If request was retried, only the last attempt is recorded.
+// Responses served from client-side cache are not recorded.
+//
+// Stats is safe for concurrent use.
+//
+// Example:
+//
+//	stats := httpexpect.NewStats()
+//
+//	e := httpexpect.WithConfig(httpexpect.Config{
+//	    BaseURL:  "http://example.com",
+//	    Reporter: httpexpect.NewAssertReporter(t),
+//	    Stats:    stats,
+//	})
+//
+//	e.GET("/users").Expect().Status(http.StatusOK)
+//	e.GET("/users/{id}", 123).Expect().Status(http.StatusOK)
+//
+//	e.Stats().Percentile(95).Lt(300 * time.Millisecond)
+//	e.Stats().Endpoint("GET", "/users/{id}").Max().Lt(time.Second)
+type Stats struct {
+	mu      sync.Mutex
+	samples []StatsSample
+}
+
+// StatsSample holds statistics of a single executed request.
+type StatsSample struct {
+	// Request method, e.g. "GET".
+	Method string
+
+	// Request path as passed to Expect.Request or NewRequest, before
+	// path parameters substitution, e.g. "/users/{id}".
+	Path string
+
+	// Response status code.
+	Status int
+
+	// Time spent to send request and receive response headers.
+	Duration time.Duration
+
+	// Size of request body, zero if request has no body or if body
+	// was streamed.
+	RequestSize int
+
+	// Size of response body.
+	ResponseSize int
+}
+
+// NewStats returns a new empty Stats.
+func NewStats() *Stats {
+	return &Stats{}
+}
+
+// Add appends sample to collected statistics.
+//
+// Normally it's called automatically from Request.Expect.
+func (s *Stats) Add(sample StatsSample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.samples = append(s.samples, sample)
+}
+
+// Len returns number of collected samples.
+func (s *Stats) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.samples)
+}
+
+// Reset removes all collected samples.
+func (s *Stats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.samples = nil
+}
+
+// Samples returns copy of collected samples in order of recording.
+func (s *Stats) Samples() []StatsSample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]StatsSample(nil), s.samples...)
+}
+
+// StatsSummary provides methods to inspect collected statistics.
+type StatsSummary struct {
+	chain   *chain
+	samples []StatsSample
+}
+
+// NewStatsSummary returns a new StatsSummary instance.
+//
+// reporter should not be nil.
+//
+// Example:
+//
+//	summary := NewStatsSummary(t, stats.Samples())
+//	summary.Percentile(99).Lt(time.Second)
+func NewStatsSummary(reporter Reporter, samples []StatsSample) *StatsSummary {
+	return newStatsSummary(newChainWithDefaults("StatsSummary()", reporter), samples)
+}
+
+func newStatsSummary(parent *chain, samples []StatsSample) *StatsSummary {
+	return &StatsSummary{parent.clone(), samples}
+}
+
+// Raw returns underlying samples attached to StatsSummary.
+func (s *StatsSummary) Raw() []StatsSample {
+	return s.samples
+}
+
+// Endpoint returns a new StatsSummary instance with samples of given
+// endpoint only. path should be the same as passed to Expect.Request,
+// before path parameters substitution.
+//
+// Example:
+//
+//	e.GET("/users/{id}", 1).Expect()
+//	e.GET("/users/{id}", 2).Expect()
+//
+//	e.Stats().Endpoint("GET", "/users/{id}").Count().Equal(2)
+func (s *StatsSummary) Endpoint(method, path string) *StatsSummary {
+	s.chain.enter("Endpoint(%q, %q)", method, path)
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newStatsSummary(s.chain, nil)
+	}
+
+	var samples []StatsSample
+
+	for _, sample := range s.samples {
+		if sample.Method == method && sample.Path == path {
+			samples = append(samples, sample)
+		}
+	}
+
+	return newStatsSummary(s.chain, samples)
+}
+
+// Endpoints returns a new Array instance with sorted list of recorded
+// endpoints, in "METHOD path" form.
+//
+// Example:
+//
+//	e.Stats().Endpoints().ContainsOnly("GET /users", "POST /users")
+func (s *StatsSummary) Endpoints() *Array {
+	s.chain.enter("Endpoints()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newArray(s.chain, nil)
+	}
+
+	seen := map[string]bool{}
+
+	for _, sample := range s.samples {
+		seen[sample.Method+" "+sample.Path] = true
+	}
+
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	endpoints := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		endpoints = append(endpoints, k)
+	}
+
+	return newArray(s.chain, endpoints)
+}
+
+// Count returns a new Number instance with number of samples.
+//
+// Example:
+//
+//	e.Stats().Count().Gt(0)
+func (s *StatsSummary) Count() *Number {
+	s.chain.enter("Count()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newNumber(s.chain, 0)
+	}
+
+	return newNumber(s.chain, float64(len(s.samples)))
+}
+
+// Percentile returns a new Duration instance with given percentile of
+// request latency, computed using nearest-rank method.
+//
+// p should be in range (0; 100]. Percentile fails if there are no samples.
+//
+// Example:
+//
+//	e.Stats().Percentile(95).Lt(300 * time.Millisecond)
+func (s *StatsSummary) Percentile(p float64) *Duration {
+	s.chain.enter("Percentile(%v)", p)
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newDuration(s.chain, nil)
+	}
+
+	if !(p > 0 && p <= 100) {
+		s.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{p},
+			Errors: []error{
+				errors.New("unexpected percentile, expected value in range (0; 100]"),
+			},
+		})
+		return newDuration(s.chain, nil)
+	}
+
+	durations, ok := s.durations()
+	if !ok {
+		return newDuration(s.chain, nil)
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(durations))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	d := durations[rank-1]
+
+	return newDuration(s.chain, &d)
+}
+
+// Min returns a new Duration instance with minimum request latency.
+//
+// Min fails if there are no samples.
+//
+// Example:
+//
+//	e.Stats().Min().Gt(time.Millisecond)
+func (s *StatsSummary) Min() *Duration {
+	s.chain.enter("Min()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newDuration(s.chain, nil)
+	}
+
+	durations, ok := s.durations()
+	if !ok {
+		return newDuration(s.chain, nil)
+	}
+
+	d := durations[0]
+
+	return newDuration(s.chain, &d)
+}
+
+// Max returns a new Duration instance with maximum request latency.
+//
+// Max fails if there are no samples.
+//
+// Example:
+//
+//	e.Stats().Max().Lt(time.Second)
+func (s *StatsSummary) Max() *Duration {
+	s.chain.enter("Max()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newDuration(s.chain, nil)
+	}
+
+	durations, ok := s.durations()
+	if !ok {
+		return newDuration(s.chain, nil)
+	}
+
+	d := durations[len(durations)-1]
+
+	return newDuration(s.chain, &d)
+}
+
+// Mean returns a new Duration instance with average request latency.
+//
+// Mean fails if there are no samples.
+//
+// Example:
+//
+//	e.Stats().Mean().Lt(100 * time.Millisecond)
+func (s *StatsSummary) Mean() *Duration {
+	s.chain.enter("Mean()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newDuration(s.chain, nil)
+	}
+
+	durations, ok := s.durations()
+	if !ok {
+		return newDuration(s.chain, nil)
+	}
+
+	var sum time.Duration
+	for _, d := range durations {
+		sum += d
+	}
+
+	d := sum / time.Duration(len(durations))
+
+	return newDuration(s.chain, &d)
+}
+
+// Histogram returns a new Array instance with number of samples in
+// latency buckets defined by given upper bounds.
+//
+// Bounds should be in ascending order. Bucket i contains samples with
+// latency less than or equal to bounds[i] and greater than previous bound.
+// The last extra bucket contains samples greater than the last bound.
+//
+// Example:
+//
+//	e.Stats().Histogram(100*time.Millisecond, time.Second).
+//	    Equal([]int{95, 5, 0})
+func (s *StatsSummary) Histogram(bounds ...time.Duration) *Array {
+	s.chain.enter("Histogram()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newArray(s.chain, nil)
+	}
+
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] <= bounds[i-1] {
+			s.chain.fail(AssertionFailure{
+				Type:   AssertUsage,
+				Actual: &AssertionValue{bounds},
+				Errors: []error{
+					errors.New("unexpected bounds, expected ascending order"),
+				},
+			})
+			return newArray(s.chain, nil)
+		}
+	}
+
+	counts := make([]int, len(bounds)+1)
+
+	for _, sample := range s.samples {
+		counts[sort.Search(len(bounds), func(i int) bool {
+			return sample.Duration <= bounds[i]
+		})]++
+	}
+
+	buckets := make([]interface{}, 0, len(counts))
+	for _, n := range counts {
+		buckets = append(buckets, float64(n))
+	}
+
+	return newArray(s.chain, buckets)
+}
+
+// StatusCount returns a new Number instance with number of responses
+// with given status code.
+//
+// Example:
+//
+//	e.Stats().StatusCount(http.StatusInternalServerError).Equal(0)
+func (s *StatsSummary) StatusCount(status int) *Number {
+	s.chain.enter("StatusCount(%d)", status)
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newNumber(s.chain, 0)
+	}
+
+	n := 0
+	for _, sample := range s.samples {
+		if sample.Status == status {
+			n++
+		}
+	}
+
+	return newNumber(s.chain, float64(n))
+}
+
+// StatusRatio returns a new Number instance with fraction (from 0 to 1)
+// of responses with status in given range.
+//
+// StatusRatio fails if there are no samples.
+//
+// Example:
+//
+//	e.Stats().StatusRatio(Status2xx).Ge(0.99)
+func (s *StatsSummary) StatusRatio(rn StatusRange) *Number {
+	s.chain.enter("StatusRatio()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newNumber(s.chain, 0)
+	}
+
+	if !s.checkNotEmpty() {
+		return newNumber(s.chain, 0)
+	}
+
+	expected := statusRangeText(int(rn))
+
+	n := 0
+	for _, sample := range s.samples {
+		if actual := statusRangeText(sample.Status); actual != "" && actual == expected {
+			n++
+		}
+	}
+
+	return newNumber(s.chain, float64(n)/float64(len(s.samples)))
+}
+
+// RequestSize returns a new Number instance with total size of request
+// bodies, in bytes.
+//
+// Example:
+//
+//	e.Stats().RequestSize().Le(1024 * 1024)
+func (s *StatsSummary) RequestSize() *Number {
+	s.chain.enter("RequestSize()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newNumber(s.chain, 0)
+	}
+
+	total := 0
+	for _, sample := range s.samples {
+		total += sample.RequestSize
+	}
+
+	return newNumber(s.chain, float64(total))
+}
+
+// ResponseSize returns a new Number instance with total size of response
+// bodies, in bytes.
+//
+// Example:
+//
+//	e.Stats().Endpoint("GET", "/users").ResponseSize().Le(10 * 1024)
+func (s *StatsSummary) ResponseSize() *Number {
+	s.chain.enter("ResponseSize()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newNumber(s.chain, 0)
+	}
+
+	total := 0
+	for _, sample := range s.samples {
+		total += sample.ResponseSize
+	}
+
+	return newNumber(s.chain, float64(total))
+}
+
+// returns sorted latencies, or fails if there are no samples
+func (s *StatsSummary) durations() ([]time.Duration, bool) {
+	if !s.checkNotEmpty() {
+		return nil, false
+	}
+
+	durations := make([]time.Duration, 0, len(s.samples))
+	for _, sample := range s.samples {
+		durations = append(durations, sample.Duration)
+	}
+
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+
+	return durations, true
+}
+
+func (s *StatsSummary) checkNotEmpty() bool {
+	if len(s.samples) == 0 {
+		s.chain.fail(AssertionFailure{
+			Type:   AssertNotEmpty,
+			Actual: &AssertionValue{s.samples},
+			Errors: []error{
+				errors.New("expected: non-empty statistics"),
+			},
+		})
+		return false
+	}
+	return true
+}
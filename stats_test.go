@@ -0,0 +1,235 @@
+package httpexpect
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newStatsSummary(chain, []StatsSample{
+		{Method: "GET", Path: "/", Status: 200, Duration: time.Second},
+	})
+
+	value.chain.assertFailed(t)
+
+	value.Endpoint("GET", "/").chain.assertFailed(t)
+	value.Endpoints().chain.assertFailed(t)
+	value.Count().chain.assertFailed(t)
+	value.Percentile(50).chain.assertFailed(t)
+	value.Min().chain.assertFailed(t)
+	value.Max().chain.assertFailed(t)
+	value.Mean().chain.assertFailed(t)
+	value.Histogram(time.Second).chain.assertFailed(t)
+	value.StatusCount(200).chain.assertFailed(t)
+	value.StatusRatio(Status2xx).chain.assertFailed(t)
+	value.RequestSize().chain.assertFailed(t)
+	value.ResponseSize().chain.assertFailed(t)
+}
+
+func TestStatsCollector(t *testing.T) {
+	stats := NewStats()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stats.Add(StatsSample{Method: "GET", Path: "/"})
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, stats.Len())
+	assert.Equal(t, 10, len(stats.Samples()))
+
+	stats.Reset()
+
+	assert.Equal(t, 0, stats.Len())
+	assert.Empty(t, stats.Samples())
+}
+
+func makeStatsSamples() []StatsSample {
+	var samples []StatsSample
+
+	for i := 1; i <= 100; i++ {
+		sample := StatsSample{
+			Method:       "GET",
+			Path:         "/users/{id}",
+			Status:       http.StatusOK,
+			Duration:     time.Duration(i) * time.Millisecond,
+			ResponseSize: 10,
+		}
+		if i%10 == 0 {
+			sample.Method = "POST"
+			sample.Path = "/users"
+			sample.Status = http.StatusInternalServerError
+			sample.RequestSize = 5
+		}
+		samples = append(samples, sample)
+	}
+
+	return samples
+}
+
+func TestStatsLatency(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewStatsSummary(reporter, makeStatsSamples())
+
+	value.Count().Equal(100).chain.assertOK(t)
+
+	value.Percentile(50).Equal(50 * time.Millisecond).chain.assertOK(t)
+	value.Percentile(95).Equal(95 * time.Millisecond).chain.assertOK(t)
+	value.Percentile(100).Equal(100 * time.Millisecond).chain.assertOK(t)
+	value.Percentile(0.1).Equal(1 * time.Millisecond).chain.assertOK(t)
+
+	value.Min().Equal(1 * time.Millisecond).chain.assertOK(t)
+	value.Max().Equal(100 * time.Millisecond).chain.assertOK(t)
+	value.Mean().Equal(50500 * time.Microsecond).chain.assertOK(t)
+
+	value.Percentile(95).Lt(90 * time.Millisecond).chain.assertFailed(t)
+
+	value.chain.assertOK(t)
+}
+
+func TestStatsPercentileInvalid(t *testing.T) {
+	for _, p := range []float64{0, -1, 101} {
+		value := NewStatsSummary(newMockReporter(t), makeStatsSamples())
+
+		value.Percentile(p).chain.assertFailed(t)
+		value.chain.assertFailed(t)
+	}
+}
+
+func TestStatsEmpty(t *testing.T) {
+	cases := []func(*StatsSummary){
+		func(s *StatsSummary) { s.Percentile(50) },
+		func(s *StatsSummary) { s.Min() },
+		func(s *StatsSummary) { s.Max() },
+		func(s *StatsSummary) { s.Mean() },
+		func(s *StatsSummary) { s.StatusRatio(Status2xx) },
+	}
+
+	for _, check := range cases {
+		value := NewStatsSummary(newMockReporter(t), nil)
+
+		check(value)
+		value.chain.assertFailed(t)
+	}
+
+	value := NewStatsSummary(newMockReporter(t), nil)
+
+	value.Count().Equal(0)
+	value.Endpoints().Empty()
+	value.Histogram(time.Second).Equal([]int{0, 0})
+	value.RequestSize().Equal(0)
+	value.ResponseSize().Equal(0)
+
+	value.chain.assertOK(t)
+}
+
+func TestStatsEndpoints(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewStatsSummary(reporter, makeStatsSamples())
+
+	value.Endpoints().Equal([]string{"GET /users/{id}", "POST /users"}).
+		chain.assertOK(t)
+
+	value.Endpoint("POST", "/users").Count().Equal(10).chain.assertOK(t)
+	value.Endpoint("GET", "/users/{id}").Count().Equal(90).chain.assertOK(t)
+	value.Endpoint("GET", "/users").Count().Equal(0).chain.assertOK(t)
+
+	value.Endpoint("POST", "/users").Max().Equal(100 * time.Millisecond).
+		chain.assertOK(t)
+}
+
+func TestStatsStatusesAndSizes(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewStatsSummary(reporter, makeStatsSamples())
+
+	value.StatusCount(http.StatusOK).Equal(90).chain.assertOK(t)
+	value.StatusCount(http.StatusInternalServerError).Equal(10).chain.assertOK(t)
+	value.StatusCount(http.StatusNotFound).Equal(0).chain.assertOK(t)
+
+	value.StatusRatio(Status2xx).Equal(0.9).chain.assertOK(t)
+	value.StatusRatio(Status5xx).Equal(0.1).chain.assertOK(t)
+	value.StatusRatio(Status4xx).Equal(0).chain.assertOK(t)
+
+	value.RequestSize().Equal(50).chain.assertOK(t)
+	value.ResponseSize().Equal(1000).chain.assertOK(t)
+}
+
+func TestStatsHistogram(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewStatsSummary(reporter, makeStatsSamples())
+
+	value.Histogram().Equal([]int{100}).chain.assertOK(t)
+
+	value.Histogram(10*time.Millisecond, 50*time.Millisecond).
+		Equal([]int{10, 40, 50}).chain.assertOK(t)
+
+	value.chain.assertOK(t)
+
+	value.Histogram(time.Second, time.Millisecond).chain.assertFailed(t)
+	value.chain.assertFailed(t)
+}
+
+func TestStatsExpect(t *testing.T) {
+	t.Run("collect", func(t *testing.T) {
+		stats := NewStats()
+
+		e := WithConfig(Config{
+			Client:   &mockClient{resp: http.Response{StatusCode: http.StatusOK}},
+			Reporter: newMockReporter(t),
+			Stats:    stats,
+		})
+
+		e.GET("/users/{id}", 1).Expect()
+		e.GET("/users/{id}", 2).Expect()
+		e.POST("/users").WithText("hello").Expect()
+
+		assert.Equal(t, 3, stats.Len())
+
+		samples := stats.Samples()
+
+		assert.Equal(t, "GET", samples[0].Method)
+		assert.Equal(t, "/users/{id}", samples[0].Path)
+		assert.Equal(t, http.StatusOK, samples[0].Status)
+
+		assert.Equal(t, "POST", samples[2].Method)
+		assert.Equal(t, "/users", samples[2].Path)
+		assert.Equal(t, 5, samples[2].RequestSize)
+		assert.Equal(t, 5, samples[2].ResponseSize)
+
+		summary := e.Stats()
+
+		summary.Count().Equal(3)
+		summary.Endpoint("GET", "/users/{id}").Count().Equal(2)
+		summary.StatusRatio(Status2xx).Equal(1)
+		summary.Percentile(95).Lt(time.Minute)
+
+		summary.chain.assertOK(t)
+	})
+
+	t.Run("not configured", func(t *testing.T) {
+		e := WithConfig(Config{
+			Client:   &mockClient{},
+			Reporter: newMockReporter(t),
+		})
+
+		summary := e.Stats()
+
+		summary.chain.assertFailed(t)
+		e.chain.assertOK(t)
+	})
+}
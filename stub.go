@@ -0,0 +1,407 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StubServer is a declaratively configured HTTP server, used to stub
+// external services called by the system under test, and to verify
+// requests made to them.
+//
+// Routes are defined with On. Every route has a sequence of canned
+// responses; they are returned in order, and the last one is repeated.
+// Requests not matching any route get 404 response.
+//
+// All received requests are recorded and can be inspected with Received.
+//
+// StubServer is safe for concurrent use.
+//
+// Example:
+//
+//	stub := httpexpect.NewStubServer(t)
+//	defer stub.Close()
+//
+//	stub.On("GET", "/users/{id}").
+//	    RespondJSON(http.StatusOK, map[string]interface{}{"name": "john"})
+//
+//	stub.On("POST", "/notify").
+//	    Respond(http.StatusServiceUnavailable, "").
+//	    Respond(http.StatusOK, "")
+//
+//	// run system under test configured with stub.URL()
+//
+//	stub.Received().Length().Equal(2)
+//	stub.Route("POST", "/notify").Received().
+//	    Element(0).Object().ValueEqual("path", "/notify")
+type StubServer struct {
+	chain  *chain
+	server *httptest.Server
+
+	mu       sync.Mutex
+	routes   []*StubRoute
+	received []interface{}
+}
+
+// StubRoute defines canned responses for requests with given method and
+// path. It is created by StubServer.On.
+type StubRoute struct {
+	stub     *StubServer
+	method   string
+	path     string
+	segments []string
+
+	responses []StubResponse
+	next      int
+	received  []interface{}
+}
+
+// StubResponse defines canned response returned by StubRoute.
+type StubResponse struct {
+	// Response status code. Default is 200.
+	Status int
+
+	// Response headers.
+	Header http.Header
+
+	// Response body.
+	Body []byte
+
+	// Delay before sending response. If request is canceled by client
+	// during delay, response is not sent.
+	Delay time.Duration
+}
+
+// NewStubServer starts and returns a new StubServer.
+//
+// Reporter should not be nil. Close should be called when server is
+// not needed anymore.
+//
+// Example:
+//
+//	stub := NewStubServer(t)
+//	defer stub.Close()
+func NewStubServer(reporter Reporter) *StubServer {
+	stub := &StubServer{
+		chain: newChainWithDefaults("StubServer()", reporter),
+	}
+
+	stub.server = httptest.NewServer(http.HandlerFunc(stub.serveHTTP))
+
+	return stub
+}
+
+// URL returns base URL of server, e.g. "http://127.0.0.1:1234".
+func (s *StubServer) URL() string {
+	return s.server.URL
+}
+
+// Close shuts down server and blocks until all outstanding requests
+// have completed.
+func (s *StubServer) Close() {
+	s.server.Close()
+}
+
+// On returns route for given method and path, creating it if needed.
+//
+// method may be "*" to match any method. path may contain parameters in
+// braces, like "/users/{id}", matching a single path segment.
+// If several routes match request, the first defined one is used.
+//
+// Example:
+//
+//	stub.On("GET", "/users/{id}").
+//	    RespondJSON(http.StatusOK, user)
+func (s *StubServer) On(method, path string) *StubRoute {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if route := s.findRoute(method, path); route != nil {
+		return route
+	}
+
+	route := &StubRoute{
+		stub:     s,
+		method:   strings.ToUpper(method),
+		path:     path,
+		segments: strings.Split(strings.Trim(path, "/"), "/"),
+	}
+
+	s.routes = append(s.routes, route)
+
+	return route
+}
+
+// Route returns route previously created by On with the same method and
+// path. If there is no such route, Route reports failure and returns
+// a new route that is not attached to server.
+func (s *StubServer) Route(method, path string) *StubRoute {
+	chain := s.chain.clone()
+
+	chain.enter("Route(%q, %q)", method, path)
+	defer chain.leave()
+
+	s.mu.Lock()
+	route := s.findRoute(method, path)
+	s.mu.Unlock()
+
+	if route == nil {
+		chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{strings.ToUpper(method) + " " + path},
+			Errors: []error{
+				errors.New("expected: route is defined in stub server"),
+			},
+		})
+		return &StubRoute{stub: s, method: method, path: path}
+	}
+
+	return route
+}
+
+// Received returns a new Array instance with all requests received by
+// server, including ones not matching any route, in order of arrival.
+//
+// Every request is represented by an object with the following fields:
+//   - "method": request method
+//   - "path": request path
+//   - "query": object with query parameters (string arrays)
+//   - "header": object with request headers (string arrays)
+//   - "body": request body as string
+//   - "json": request body decoded from JSON (only if body is valid JSON)
+//
+// Example:
+//
+//	stub.Received().Length().Equal(2)
+//	stub.Received().Element(0).Object().
+//	    ContainsSubset(map[string]interface{}{"method": "POST"})
+func (s *StubServer) Received() *Array {
+	chain := s.chain.clone()
+
+	chain.enter("Received()")
+	defer chain.leave()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return newArray(chain, append([]interface{}{}, s.received...))
+}
+
+// Reset removes all routes and received requests.
+func (s *StubServer) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.routes = nil
+	s.received = nil
+}
+
+func (s *StubServer) findRoute(method, path string) *StubRoute {
+	for _, route := range s.routes {
+		if route.method == strings.ToUpper(method) && route.path == path {
+			return route
+		}
+	}
+	return nil
+}
+
+func (s *StubServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	request := stubRequest(r, body)
+
+	s.mu.Lock()
+
+	s.received = append(s.received, request)
+
+	var (
+		resp  StubResponse
+		found bool
+	)
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	for _, route := range s.routes {
+		if route.method != "*" && route.method != r.Method {
+			continue
+		}
+		if _, ok := matchOpenAPIPath(route.segments, segments); !ok {
+			continue
+		}
+
+		route.received = append(route.received, request)
+
+		if len(route.responses) != 0 {
+			resp = route.responses[route.next]
+			if route.next < len(route.responses)-1 {
+				route.next++
+			}
+		}
+
+		found = true
+		break
+	}
+
+	s.mu.Unlock()
+
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	for k, v := range resp.Header {
+		w.Header()[k] = append([]string(nil), v...)
+	}
+
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write(resp.Body)
+}
+
+func stubRequest(r *http.Request, body []byte) map[string]interface{} {
+	query := map[string]interface{}{}
+	for k, v := range r.URL.Query() {
+		query[k] = stubStrings(v)
+	}
+
+	header := map[string]interface{}{}
+	for k, v := range r.Header {
+		header[k] = stubStrings(v)
+	}
+
+	request := map[string]interface{}{
+		"method": r.Method,
+		"path":   r.URL.Path,
+		"query":  query,
+		"header": header,
+		"body":   string(body),
+	}
+
+	var value interface{}
+	if len(body) != 0 && json.Unmarshal(body, &value) == nil {
+		request["json"] = value
+	}
+
+	return request
+}
+
+func stubStrings(values []string) []interface{} {
+	ret := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+// Respond appends response with given status and body to route's
+// response sequence.
+func (r *StubRoute) Respond(status int, body string) *StubRoute {
+	return r.RespondWith(StubResponse{
+		Status: status,
+		Body:   []byte(body),
+	})
+}
+
+// RespondJSON appends response with given status and JSON body to
+// route's response sequence. Content-Type is set to "application/json".
+//
+// If value can't be encoded to JSON, RespondJSON reports failure.
+func (r *StubRoute) RespondJSON(status int, value interface{}) *StubRoute {
+	chain := r.stub.chain.clone()
+
+	chain.enter("On(%q, %q).RespondJSON()", r.method, r.path)
+	defer chain.leave()
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{value},
+			Errors: []error{
+				errors.New("expected: value can be encoded to JSON"),
+				err,
+			},
+		})
+		return r
+	}
+
+	return r.RespondWith(StubResponse{
+		Status: status,
+		Header: http.Header{
+			"Content-Type": {"application/json"},
+		},
+		Body: b,
+	})
+}
+
+// RespondWith appends given response to route's response sequence.
+//
+// Example:
+//
+//	stub.On("GET", "/slow").RespondWith(httpexpect.StubResponse{
+//	    Status: http.StatusOK,
+//	    Delay:  time.Second,
+//	})
+func (r *StubRoute) RespondWith(resp StubResponse) *StubRoute {
+	r.stub.mu.Lock()
+	defer r.stub.mu.Unlock()
+
+	r.responses = append(r.responses, resp)
+
+	return r
+}
+
+// Delay sets delay of the last response in route's response sequence.
+// If route has no responses yet, empty 200 response is appended.
+//
+// Example:
+//
+//	stub.On("GET", "/slow").
+//	    Respond(http.StatusOK, "ok").Delay(time.Second)
+func (r *StubRoute) Delay(d time.Duration) *StubRoute {
+	r.stub.mu.Lock()
+	defer r.stub.mu.Unlock()
+
+	if len(r.responses) == 0 {
+		r.responses = append(r.responses, StubResponse{})
+	}
+
+	r.responses[len(r.responses)-1].Delay = d
+
+	return r
+}
+
+// Received returns a new Array instance with requests matched by route,
+// in order of arrival. See StubServer.Received for format of requests.
+//
+// Example:
+//
+//	stub.Route("POST", "/notify").Received().Length().Equal(1)
+func (r *StubRoute) Received() *Array {
+	chain := r.stub.chain.clone()
+
+	chain.enter("Route(%q, %q).Received()", r.method, r.path)
+	defer chain.leave()
+
+	r.stub.mu.Lock()
+	defer r.stub.mu.Unlock()
+
+	return newArray(chain, append([]interface{}{}, r.received...))
+}
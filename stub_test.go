@@ -0,0 +1,129 @@
+package httpexpect
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStubServer(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	stub := NewStubServer(reporter)
+	defer stub.Close()
+
+	stub.On("GET", "/users/{id}").
+		RespondJSON(http.StatusOK, map[string]interface{}{"name": "john"})
+
+	stub.On("POST", "/notify").
+		Respond(http.StatusServiceUnavailable, "try later").
+		Respond(http.StatusAccepted, "")
+
+	stub.On("*", "/any").
+		RespondWith(StubResponse{
+			Status: http.StatusTeapot,
+			Header: http.Header{"X-Stub": {"yes"}},
+		})
+
+	e := Default(t, stub.URL())
+
+	e.GET("/users/123").
+		Expect().
+		Status(http.StatusOK).
+		JSON().Object().ValueEqual("name", "john")
+
+	e.POST("/notify").WithJSON(map[string]interface{}{"event": "created"}).
+		Expect().
+		Status(http.StatusServiceUnavailable).
+		Body().Equal("try later")
+
+	e.POST("/notify").WithQuery("retry", 1).
+		Expect().
+		Status(http.StatusAccepted)
+
+	e.POST("/notify").
+		Expect().
+		Status(http.StatusAccepted)
+
+	e.DELETE("/any").
+		Expect().
+		Status(http.StatusTeapot).
+		Header("X-Stub").Equal("yes")
+
+	e.GET("/unknown").
+		Expect().
+		Status(http.StatusNotFound)
+
+	stub.Received().Length().Equal(6)
+	stub.Received().Element(5).Object().ValueEqual("path", "/unknown")
+
+	notify := stub.Route("POST", "/notify").Received()
+
+	notify.Length().Equal(3)
+	notify.Element(0).Object().ValueEqual("json",
+		map[string]interface{}{"event": "created"})
+	notify.Element(1).Object().Value("query").Object().
+		ValueEqual("retry", []string{"1"})
+	notify.chain.assertOK(t)
+
+	stub.On("POST", "/notify").Received().Length().Equal(3)
+
+	stub.Reset()
+	stub.Received().Length().Equal(0)
+
+	e.GET("/users/123").
+		Expect().
+		Status(http.StatusNotFound)
+}
+
+func TestStubServerDelay(t *testing.T) {
+	stub := NewStubServer(newMockReporter(t))
+	defer stub.Close()
+
+	stub.On("GET", "/slow").
+		Respond(http.StatusOK, "ok").Delay(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	e := WithConfig(Config{
+		BaseURL:  stub.URL(),
+		Reporter: newMockReporter(t),
+	})
+
+	resp := e.GET("/slow").WithContext(ctx).Expect()
+	resp.chain.assertFailed(t)
+
+	stub.On("GET", "/empty").Delay(time.Millisecond)
+
+	e.GET("/empty").Expect().Status(http.StatusOK)
+}
+
+func TestStubServerFailures(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	stub := NewStubServer(reporter)
+	defer stub.Close()
+
+	stub.On("GET", "/a")
+
+	route := stub.Route("GET", "/missing")
+	assert.NotNil(t, route)
+	assert.True(t, reporter.reported)
+
+	reporter.reported = false
+
+	received := stub.Received()
+	received.chain.assertOK(t)
+
+	stub.On("GET", "/b").RespondJSON(http.StatusOK, math.Inf(1))
+	assert.True(t, reporter.reported)
+
+	// failures are not propagated to other calls
+	received = stub.Route("GET", "/a").Received()
+	received.chain.assertOK(t)
+}
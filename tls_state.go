@@ -0,0 +1,366 @@
+package httpexpect
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TLSState provides methods to inspect attached tls.ConnectionState value,
+// e.g. TLS version, cipher suite, and server certificates.
+type TLSState struct {
+	chain *chain
+	value *tls.ConnectionState
+}
+
+// NewTLSState returns a new TLSState instance.
+//
+// reporter should not be nil.
+//
+// Example:
+//
+//	state := NewTLSState(t, *resp.TLS)
+//	state.Version().Ge(tls.VersionTLS12)
+func NewTLSState(reporter Reporter, value tls.ConnectionState) *TLSState {
+	return newTLSState(newChainWithDefaults("TLSState()", reporter), &value)
+}
+
+func newTLSState(parent *chain, val *tls.ConnectionState) *TLSState {
+	return &TLSState{parent.clone(), val}
+}
+
+// Raw returns underlying tls.ConnectionState attached to TLSState.
+// This is the value originally passed to NewTLSState.
+//
+// Returns nil if response was not received over TLS.
+func (s *TLSState) Raw() *tls.ConnectionState {
+	return s.value
+}
+
+// Version returns a new Number instance with negotiated TLS version,
+// e.g. tls.VersionTLS12.
+//
+// Example:
+//
+//	state := NewTLSState(t, *resp.TLS)
+//	state.Version().Ge(tls.VersionTLS12)
+func (s *TLSState) Version() *Number {
+	s.chain.enter("Version()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newNumber(s.chain, 0)
+	}
+
+	return newNumber(s.chain, float64(s.value.Version))
+}
+
+// CipherSuite returns a new Number instance with negotiated cipher suite,
+// e.g. tls.TLS_AES_128_GCM_SHA256.
+//
+// Example:
+//
+//	state := NewTLSState(t, *resp.TLS)
+//	state.CipherSuite().InList(
+//	    tls.TLS_AES_128_GCM_SHA256,
+//	    tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+//	)
+func (s *TLSState) CipherSuite() *Number {
+	s.chain.enter("CipherSuite()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newNumber(s.chain, 0)
+	}
+
+	return newNumber(s.chain, float64(s.value.CipherSuite))
+}
+
+// CipherSuiteName returns a new String instance with name of negotiated
+// cipher suite, e.g. "TLS_AES_128_GCM_SHA256".
+//
+// Example:
+//
+//	state := NewTLSState(t, *resp.TLS)
+//	state.CipherSuiteName().NotContains("CBC")
+func (s *TLSState) CipherSuiteName() *String {
+	s.chain.enter("CipherSuiteName()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newString(s.chain, "")
+	}
+
+	return newString(s.chain, tls.CipherSuiteName(s.value.CipherSuite))
+}
+
+// ServerName returns a new String instance with server name requested
+// by client (SNI).
+//
+// Example:
+//
+//	state := NewTLSState(t, *resp.TLS)
+//	state.ServerName().Equal("example.com")
+func (s *TLSState) ServerName() *String {
+	s.chain.enter("ServerName()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newString(s.chain, "")
+	}
+
+	return newString(s.chain, s.value.ServerName)
+}
+
+// NegotiatedProtocol returns a new String instance with application
+// protocol negotiated via ALPN, e.g. "h2". String is empty if no protocol
+// was negotiated.
+//
+// Example:
+//
+//	state := NewTLSState(t, *resp.TLS)
+//	state.NegotiatedProtocol().Equal("h2")
+func (s *TLSState) NegotiatedProtocol() *String {
+	s.chain.enter("NegotiatedProtocol()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newString(s.chain, "")
+	}
+
+	return newString(s.chain, s.value.NegotiatedProtocol)
+}
+
+// PeerCertificate returns a new Certificate instance with leaf certificate
+// presented by server.
+//
+// PeerCertificate fails if server presented no certificates.
+//
+// Example:
+//
+//	state := NewTLSState(t, *resp.TLS)
+//	state.PeerCertificate().Subject().Contains("CN=example.com")
+func (s *TLSState) PeerCertificate() *Certificate {
+	s.chain.enter("PeerCertificate()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newCertificate(s.chain, nil)
+	}
+
+	if len(s.value.PeerCertificates) == 0 {
+		s.chain.fail(AssertionFailure{
+			Type:   AssertNotEmpty,
+			Actual: &AssertionValue{s.value.PeerCertificates},
+			Errors: []error{
+				errors.New("expected: server presented certificates"),
+			},
+		})
+		return newCertificate(s.chain, nil)
+	}
+
+	return newCertificate(s.chain, s.value.PeerCertificates[0])
+}
+
+// NotExpiredWithin succeeds if none of the certificates presented by server
+// expires within given duration from now.
+//
+// Example:
+//
+//	state := NewTLSState(t, *resp.TLS)
+//	state.NotExpiredWithin(30 * 24 * time.Hour)
+func (s *TLSState) NotExpiredWithin(d time.Duration) *TLSState {
+	s.chain.enter("NotExpiredWithin()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	deadline := time.Now().Add(d)
+
+	for _, cert := range s.value.PeerCertificates {
+		if !checkCertNotExpired(s.chain, cert, deadline) {
+			break
+		}
+	}
+
+	return s
+}
+
+// Certificate provides methods to inspect attached x509.Certificate value.
+type Certificate struct {
+	chain *chain
+	value *x509.Certificate
+}
+
+// NewCertificate returns a new Certificate instance.
+//
+// reporter should not be nil.
+//
+// Example:
+//
+//	cert := NewCertificate(t, resp.TLS.PeerCertificates[0])
+//	cert.DNSNames().Contains("example.com")
+func NewCertificate(reporter Reporter, value *x509.Certificate) *Certificate {
+	chain := newChainWithDefaults("Certificate()", reporter)
+
+	if value == nil {
+		chain.fail(AssertionFailure{
+			Type:   AssertNotNil,
+			Actual: &AssertionValue{value},
+			Errors: []error{
+				errors.New("expected: non-nil certificate"),
+			},
+		})
+	}
+
+	return newCertificate(chain, value)
+}
+
+func newCertificate(parent *chain, val *x509.Certificate) *Certificate {
+	if val == nil {
+		val = &x509.Certificate{}
+	}
+	return &Certificate{parent.clone(), val}
+}
+
+// Raw returns underlying x509.Certificate attached to Certificate.
+// This is the value originally passed to NewCertificate.
+func (c *Certificate) Raw() *x509.Certificate {
+	return c.value
+}
+
+// Subject returns a new String instance with certificate subject in
+// RFC 2253 form, e.g. "CN=example.com,O=Example".
+//
+// Example:
+//
+//	cert := NewCertificate(t, value)
+//	cert.Subject().Contains("CN=example.com")
+func (c *Certificate) Subject() *String {
+	c.chain.enter("Subject()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newString(c.chain, "")
+	}
+
+	return newString(c.chain, c.value.Subject.String())
+}
+
+// Issuer returns a new String instance with certificate issuer in
+// RFC 2253 form.
+//
+// Example:
+//
+//	cert := NewCertificate(t, value)
+//	cert.Issuer().Contains("O=Let's Encrypt")
+func (c *Certificate) Issuer() *String {
+	c.chain.enter("Issuer()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newString(c.chain, "")
+	}
+
+	return newString(c.chain, c.value.Issuer.String())
+}
+
+// DNSNames returns a new Array instance with DNS names from Subject
+// Alternative Name extension.
+//
+// Example:
+//
+//	cert := NewCertificate(t, value)
+//	cert.DNSNames().Contains("example.com")
+func (c *Certificate) DNSNames() *Array {
+	c.chain.enter("DNSNames()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newArray(c.chain, nil)
+	}
+
+	names := make([]interface{}, 0, len(c.value.DNSNames))
+	for _, name := range c.value.DNSNames {
+		names = append(names, name)
+	}
+
+	return newArray(c.chain, names)
+}
+
+// NotBefore returns a new DateTime instance with start of certificate
+// validity period.
+//
+// Example:
+//
+//	cert := NewCertificate(t, value)
+//	cert.NotBefore().Lt(time.Now())
+func (c *Certificate) NotBefore() *DateTime {
+	c.chain.enter("NotBefore()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newDateTime(c.chain, time.Unix(0, 0))
+	}
+
+	return newDateTime(c.chain, c.value.NotBefore)
+}
+
+// NotAfter returns a new DateTime instance with end of certificate
+// validity period.
+//
+// Example:
+//
+//	cert := NewCertificate(t, value)
+//	cert.NotAfter().Gt(time.Now().Add(30 * 24 * time.Hour))
+func (c *Certificate) NotAfter() *DateTime {
+	c.chain.enter("NotAfter()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newDateTime(c.chain, time.Unix(0, 0))
+	}
+
+	return newDateTime(c.chain, c.value.NotAfter)
+}
+
+// NotExpiredWithin succeeds if certificate doesn't expire within given
+// duration from now.
+//
+// Example:
+//
+//	cert := NewCertificate(t, value)
+//	cert.NotExpiredWithin(30 * 24 * time.Hour)
+func (c *Certificate) NotExpiredWithin(d time.Duration) *Certificate {
+	c.chain.enter("NotExpiredWithin()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	checkCertNotExpired(c.chain, c.value, time.Now().Add(d))
+
+	return c
+}
+
+func checkCertNotExpired(
+	chain *chain, cert *x509.Certificate, deadline time.Time,
+) bool {
+	if cert.NotAfter.Before(deadline) {
+		chain.fail(AssertionFailure{
+			Type:     AssertGt,
+			Actual:   &AssertionValue{cert.NotAfter},
+			Expected: &AssertionValue{deadline},
+			Errors: []error{
+				fmt.Errorf("certificate %q expires too soon", cert.Subject.String()),
+			},
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package httpexpect
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTLSStateFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newTLSState(chain, &tls.ConnectionState{})
+
+	value.chain.assertFailed(t)
+
+	value.Version().chain.assertFailed(t)
+	value.CipherSuite().chain.assertFailed(t)
+	value.CipherSuiteName().chain.assertFailed(t)
+	value.ServerName().chain.assertFailed(t)
+	value.NegotiatedProtocol().chain.assertFailed(t)
+	value.PeerCertificate().chain.assertFailed(t)
+
+	value.NotExpiredWithin(0)
+}
+
+func TestCertificateFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newCertificate(chain, nil)
+
+	value.chain.assertFailed(t)
+
+	assert.NotNil(t, value.Raw())
+
+	value.Subject().chain.assertFailed(t)
+	value.Issuer().chain.assertFailed(t)
+	value.DNSNames().chain.assertFailed(t)
+	value.NotBefore().chain.assertFailed(t)
+	value.NotAfter().chain.assertFailed(t)
+
+	value.NotExpiredWithin(0)
+}
+
+func newTestCertificate(notAfter time.Time) *x509.Certificate {
+	return &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   "example.com",
+			Organization: []string{"Example"},
+		},
+		Issuer: pkix.Name{
+			CommonName: "Example CA",
+		},
+		DNSNames:  []string{"example.com", "www.example.com"},
+		NotBefore: notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:  notAfter,
+	}
+}
+
+func TestTLSStateGetters(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	state := tls.ConnectionState{
+		Version:            tls.VersionTLS13,
+		CipherSuite:        tls.TLS_AES_128_GCM_SHA256,
+		ServerName:         "example.com",
+		NegotiatedProtocol: "h2",
+		PeerCertificates: []*x509.Certificate{
+			newTestCertificate(time.Now().Add(90 * 24 * time.Hour)),
+		},
+	}
+
+	value := NewTLSState(reporter, state)
+
+	assert.Equal(t, state, *value.Raw())
+
+	value.Version().Ge(tls.VersionTLS12).chain.assertOK(t)
+	value.Version().Lt(tls.VersionTLS13).chain.assertFailed(t)
+
+	value.CipherSuite().
+		InList(tls.TLS_AES_128_GCM_SHA256, tls.TLS_AES_256_GCM_SHA384).
+		chain.assertOK(t)
+	value.CipherSuiteName().Equal("TLS_AES_128_GCM_SHA256").chain.assertOK(t)
+
+	value.ServerName().Equal("example.com").chain.assertOK(t)
+	value.NegotiatedProtocol().Equal("h2").chain.assertOK(t)
+
+	value.PeerCertificate().Subject().Contains("CN=example.com").chain.assertOK(t)
+
+	value.chain.assertOK(t)
+}
+
+func TestTLSStatePeerCertificate(t *testing.T) {
+	value := NewTLSState(newMockReporter(t), tls.ConnectionState{})
+
+	value.PeerCertificate().chain.assertFailed(t)
+	value.chain.assertFailed(t)
+}
+
+func TestTLSStateNotExpiredWithin(t *testing.T) {
+	state := tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{
+			newTestCertificate(time.Now().Add(90 * 24 * time.Hour)),
+			newTestCertificate(time.Now().Add(10 * 24 * time.Hour)),
+		},
+	}
+
+	NewTLSState(newMockReporter(t), state).
+		NotExpiredWithin(7 * 24 * time.Hour).
+		chain.assertOK(t)
+
+	NewTLSState(newMockReporter(t), state).
+		NotExpiredWithin(30 * 24 * time.Hour).
+		chain.assertFailed(t)
+}
+
+func TestCertificateGetters(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	notAfter := time.Now().Add(90 * 24 * time.Hour)
+	cert := newTestCertificate(notAfter)
+
+	value := NewCertificate(reporter, cert)
+
+	assert.Same(t, cert, value.Raw())
+
+	value.Subject().Equal("CN=example.com,O=Example").chain.assertOK(t)
+	value.Issuer().Equal("CN=Example CA").chain.assertOK(t)
+	value.DNSNames().ContainsOnly("example.com", "www.example.com").chain.assertOK(t)
+	value.NotBefore().Lt(time.Now()).chain.assertOK(t)
+	value.NotAfter().Equal(notAfter).chain.assertOK(t)
+
+	value.chain.assertOK(t)
+}
+
+func TestCertificateNil(t *testing.T) {
+	value := NewCertificate(newMockReporter(t), nil)
+
+	value.chain.assertFailed(t)
+}
+
+func TestCertificateNotExpiredWithin(t *testing.T) {
+	cert := newTestCertificate(time.Now().Add(10 * 24 * time.Hour))
+
+	NewCertificate(newMockReporter(t), cert).
+		NotExpiredWithin(24 * time.Hour).
+		chain.assertOK(t)
+
+	NewCertificate(newMockReporter(t), cert).
+		NotExpiredWithin(30 * 24 * time.Hour).
+		chain.assertFailed(t)
+
+	expired := newTestCertificate(time.Now().Add(-time.Hour))
+
+	NewCertificate(newMockReporter(t), expired).
+		NotExpiredWithin(0).
+		chain.assertFailed(t)
+}
@@ -0,0 +1,167 @@
+package httpexpect
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// TracerProvider is used to create a span for every sent request.
+//
+// When it's set in Config.TracerProvider, Request.Expect starts a span
+// before sending request and ends it before returning. Span context is
+// attached to http.Request, so that instrumented Client (e.g. with
+// OpenTelemetry transport) can propagate it to the server.
+//
+// Span gets request method, URL, response status code, and number of
+// retries as attributes. Failed assertions are recorded as span events.
+// Failures of assertions made on Response after Expect returned are
+// recorded in a new child span, because request span is already ended.
+//
+// TracerProvider doesn't depend on particular tracing library; see README
+// for an example of OpenTelemetry adapter.
+type TracerProvider interface {
+	// Start creates a new span with given name as a child of span from
+	// ctx, if any. Returns context holding new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation created by TracerProvider.
+type Span interface {
+	// SetAttributes sets attributes of span.
+	SetAttributes(attrs ...SpanAttribute)
+
+	// AddEvent adds event with attributes to span.
+	AddEvent(name string, attrs ...SpanAttribute)
+
+	// SetError marks span as failed.
+	SetError(description string)
+
+	// End completes span.
+	End()
+}
+
+// SpanAttribute is a key-value pair attached to Span or span event.
+//
+// Value is string, int, or bool.
+type SpanAttribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Names of attributes set by Request.Expect. When applicable, they follow
+// OpenTelemetry semantic conventions.
+const (
+	SpanAttrMethod      = "http.request.method"
+	SpanAttrURL         = "url.full"
+	SpanAttrRoute       = "http.route"
+	SpanAttrStatusCode  = "http.response.status_code"
+	SpanAttrResendCount = "http.request.resend_count"
+	SpanAttrTestName    = "httpexpect.test_name"
+	SpanAttrRequestName = "httpexpect.request_name"
+
+	SpanAttrAssertType   = "httpexpect.assertion.type"
+	SpanAttrAssertPath   = "httpexpect.assertion.path"
+	SpanAttrAssertErrors = "httpexpect.assertion.errors"
+)
+
+// Name of span event added for every failed assertion.
+const SpanEventAssertionFailure = "assertion failure"
+
+type requestTrace struct {
+	provider TracerProvider
+	ctx      context.Context
+	span     Span
+	ended    bool
+}
+
+// failures of traced requests are recorded as span events
+func (r *Request) initTracing() {
+	onFailure := r.chain.onFailure
+
+	r.chain.onFailure = func(ctx *AssertionContext, failure *AssertionFailure) {
+		if ctx.Request != nil && ctx.Request.trace != nil {
+			ctx.Request.trace.failure(ctx, failure)
+		}
+		if onFailure != nil {
+			onFailure(ctx, failure)
+		}
+	}
+}
+
+// starts request span and attaches it to request context
+func (r *Request) startTrace() {
+	ctx := r.config.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	ctx, span := r.config.TracerProvider.Start(ctx,
+		r.httpReq.Method+" "+r.endpoint)
+
+	attrs := []SpanAttribute{
+		{SpanAttrMethod, r.httpReq.Method},
+		{SpanAttrURL, r.httpReq.URL.String()},
+		{SpanAttrRoute, r.endpoint},
+	}
+	if r.chain.context.TestName != "" {
+		attrs = append(attrs,
+			SpanAttribute{SpanAttrTestName, r.chain.context.TestName})
+	}
+	if r.chain.context.RequestName != "" {
+		attrs = append(attrs,
+			SpanAttribute{SpanAttrRequestName, r.chain.context.RequestName})
+	}
+
+	span.SetAttributes(attrs...)
+
+	r.config.Context = ctx
+
+	r.trace = &requestTrace{
+		provider: r.config.TracerProvider,
+		ctx:      ctx,
+		span:     span,
+	}
+}
+
+// ends request span; does nothing if request is not traced
+func (r *Request) endTrace() {
+	if r.trace == nil || r.trace.ended {
+		return
+	}
+
+	r.trace.ended = true
+	r.trace.span.End()
+}
+
+func (t *requestTrace) response(resp *http.Response, retries int) {
+	t.span.SetAttributes(SpanAttribute{SpanAttrStatusCode, resp.StatusCode})
+
+	if retries > 0 {
+		t.span.SetAttributes(SpanAttribute{SpanAttrResendCount, retries})
+	}
+}
+
+func (t *requestTrace) failure(ctx *AssertionContext, failure *AssertionFailure) {
+	span := t.span
+
+	if t.ended {
+		_, span = t.provider.Start(t.ctx, SpanEventAssertionFailure)
+		defer span.End()
+	}
+
+	var errs []string
+	for _, err := range failure.Errors {
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	span.AddEvent(SpanEventAssertionFailure,
+		SpanAttribute{SpanAttrAssertType, failure.Type.String()},
+		SpanAttribute{SpanAttrAssertPath, strings.Join(ctx.Path, ".")},
+		SpanAttribute{SpanAttrAssertErrors, strings.Join(errs, "\n")},
+	)
+
+	span.SetError(failure.Type.String())
+}
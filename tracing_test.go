@@ -0,0 +1,253 @@
+package httpexpect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSpanEvent struct {
+	name  string
+	attrs map[string]interface{}
+}
+
+type mockSpan struct {
+	name   string
+	parent *mockSpan
+	attrs  map[string]interface{}
+	events []mockSpanEvent
+	err    string
+	ended  bool
+}
+
+func (s *mockSpan) SetAttributes(attrs ...SpanAttribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *mockSpan) AddEvent(name string, attrs ...SpanAttribute) {
+	ev := mockSpanEvent{name: name, attrs: map[string]interface{}{}}
+	for _, a := range attrs {
+		ev.attrs[a.Key] = a.Value
+	}
+	s.events = append(s.events, ev)
+}
+
+func (s *mockSpan) SetError(description string) {
+	s.err = description
+}
+
+func (s *mockSpan) End() {
+	s.ended = true
+}
+
+type mockSpanKey struct{}
+
+type mockTracerProvider struct {
+	spans []*mockSpan
+}
+
+func (p *mockTracerProvider) Start(
+	ctx context.Context, name string,
+) (context.Context, Span) {
+	span := &mockSpan{
+		name:  name,
+		attrs: map[string]interface{}{},
+	}
+	if parent, ok := ctx.Value(mockSpanKey{}).(*mockSpan); ok {
+		span.parent = parent
+	}
+
+	p.spans = append(p.spans, span)
+
+	return context.WithValue(ctx, mockSpanKey{}, span), span
+}
+
+func TestTracingSpan(t *testing.T) {
+	provider := &mockTracerProvider{}
+
+	client := &mockClient{
+		resp: http.Response{
+			StatusCode: http.StatusCreated,
+		},
+	}
+
+	config := Config{
+		TestName:       "TestFoo",
+		BaseURL:        "http://example.com",
+		Client:         client,
+		Reporter:       newMockReporter(t),
+		TracerProvider: provider,
+	}
+
+	req := NewRequest(config, "POST", "/users/{id}", 123).
+		WithName("create user")
+
+	resp := req.Expect()
+	resp.chain.assertOK(t)
+
+	if assert.Equal(t, 1, len(provider.spans)) {
+		span := provider.spans[0]
+
+		assert.Equal(t, "POST /users/{id}", span.name)
+		assert.Nil(t, span.parent)
+		assert.True(t, span.ended)
+		assert.Equal(t, "", span.err)
+		assert.Empty(t, span.events)
+
+		assert.Equal(t, map[string]interface{}{
+			SpanAttrMethod:      "POST",
+			SpanAttrURL:         "http://example.com/users/123",
+			SpanAttrRoute:       "/users/{id}",
+			SpanAttrStatusCode:  http.StatusCreated,
+			SpanAttrTestName:    "TestFoo",
+			SpanAttrRequestName: "create user",
+		}, span.attrs)
+
+		assert.Equal(t, span, client.req.Context().Value(mockSpanKey{}))
+	}
+}
+
+func TestTracingRetries(t *testing.T) {
+	provider := &mockTracerProvider{}
+
+	client := &mockClient{
+		resp: http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	config := Config{
+		BaseURL:        "http://example.com",
+		Client:         client,
+		Reporter:       newMockReporter(t),
+		TracerProvider: provider,
+	}
+
+	req := NewRequest(config, "GET", "/").
+		WithMaxRetries(2).
+		WithRetryDelay(0, 0)
+
+	req.Expect()
+
+	if assert.Equal(t, 1, len(provider.spans)) {
+		span := provider.spans[0]
+
+		assert.Equal(t, http.StatusServiceUnavailable, span.attrs[SpanAttrStatusCode])
+		assert.Equal(t, 2, span.attrs[SpanAttrResendCount])
+	}
+}
+
+func TestTracingFailures(t *testing.T) {
+	t.Run("request failure", func(t *testing.T) {
+		provider := &mockTracerProvider{}
+
+		config := Config{
+			BaseURL: "http://example.com",
+			Client: &mockClient{
+				err: errors.New("connection refused"),
+			},
+			Reporter:       newMockReporter(t),
+			TracerProvider: provider,
+		}
+
+		resp := NewRequest(config, "GET", "/").Expect()
+		resp.chain.assertFailed(t)
+
+		if assert.Equal(t, 1, len(provider.spans)) {
+			span := provider.spans[0]
+
+			assert.True(t, span.ended)
+			assert.Equal(t, "AssertOperation", span.err)
+			assert.NotContains(t, span.attrs, SpanAttrStatusCode)
+
+			if assert.Equal(t, 1, len(span.events)) {
+				ev := span.events[0]
+
+				assert.Equal(t, SpanEventAssertionFailure, ev.name)
+				assert.Equal(t, "AssertOperation", ev.attrs[SpanAttrAssertType])
+				assert.Equal(t, "Request().Expect()", ev.attrs[SpanAttrAssertPath])
+				assert.Contains(t, ev.attrs[SpanAttrAssertErrors], "connection refused")
+			}
+		}
+	})
+
+	t.Run("matcher failure", func(t *testing.T) {
+		provider := &mockTracerProvider{}
+
+		config := Config{
+			BaseURL: "http://example.com",
+			Client: &mockClient{
+				resp: http.Response{
+					StatusCode: http.StatusOK,
+				},
+			},
+			Reporter:       newMockReporter(t),
+			TracerProvider: provider,
+		}
+
+		resp := NewRequest(config, "GET", "/").
+			WithMatcher(func(resp *Response) {
+				resp.Status(http.StatusNotFound)
+			}).
+			Expect()
+		resp.chain.assertFailed(t)
+
+		if assert.Equal(t, 1, len(provider.spans)) {
+			span := provider.spans[0]
+
+			assert.True(t, span.ended)
+			assert.Equal(t, "AssertEqual", span.err)
+
+			if assert.Equal(t, 1, len(span.events)) {
+				assert.Equal(t, "AssertEqual",
+					span.events[0].attrs[SpanAttrAssertType])
+			}
+		}
+	})
+
+	t.Run("response failure", func(t *testing.T) {
+		provider := &mockTracerProvider{}
+
+		config := Config{
+			BaseURL: "http://example.com",
+			Client: &mockClient{
+				resp: http.Response{
+					StatusCode: http.StatusOK,
+				},
+			},
+			Reporter:       newMockReporter(t),
+			TracerProvider: provider,
+		}
+
+		resp := NewRequest(config, "GET", "/").Expect()
+		resp.chain.assertOK(t)
+
+		resp.Status(http.StatusNotFound)
+		resp.chain.assertFailed(t)
+
+		if assert.Equal(t, 2, len(provider.spans)) {
+			reqSpan := provider.spans[0]
+
+			assert.True(t, reqSpan.ended)
+			assert.Equal(t, "", reqSpan.err)
+			assert.Empty(t, reqSpan.events)
+
+			span := provider.spans[1]
+
+			assert.Equal(t, SpanEventAssertionFailure, span.name)
+			assert.Equal(t, reqSpan, span.parent)
+			assert.True(t, span.ended)
+			assert.Equal(t, "AssertEqual", span.err)
+
+			if assert.Equal(t, 1, len(span.events)) {
+				assert.Equal(t, "Request().Expect().Status()",
+					span.events[0].attrs[SpanAttrAssertPath])
+			}
+		}
+	})
+}
@@ -0,0 +1,298 @@
+//go:build go1.21
+// +build go1.21
+
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Typed provides methods to inspect a value of Go type T, usually decoded
+// from response body using Decode.
+//
+// Typed is available only with Go 1.21 and later, since older toolchains
+// don't allow generics in this module, which declares an older Go version.
+// It gives compile-time safety for well-known response types: the decoded
+// value can be used directly, and fields are selected by Go name.
+type Typed[T any] struct {
+	chain *chain
+	value T
+}
+
+// NewTyped returns a new Typed instance.
+//
+// Reporter should not be nil.
+//
+// Example:
+//
+//	typed := NewTyped(t, User{Name: "john"})
+//	typed.Field("Name").String().Equal("john")
+func NewTyped[T any](reporter Reporter, value T) *Typed[T] {
+	return newTyped(newChainWithDefaults(
+		fmt.Sprintf("Typed[%s]()", typeName[T]()), reporter), value)
+}
+
+func newTyped[T any](parent *chain, value T) *Typed[T] {
+	return &Typed[T]{parent.clone(), value}
+}
+
+// Decode decodes JSON response body into a value of type T and returns
+// a new Typed instance.
+//
+// Like Response.JSON, Decode succeeds if response contains "application/json"
+// Content-Type header with empty or "utf-8" charset and if JSON may be
+// decoded from response body into T. Unknown fields are ignored.
+//
+// Example:
+//
+//	type User struct {
+//	    ID   int    `json:"id"`
+//	    Name string `json:"name"`
+//	}
+//
+//	user := httpexpect.Decode[User](resp)
+//	user.Field("Name").String().NotEmpty()
+//
+//	assert.Equal(t, "john", user.Raw().Name)
+func Decode[T any](resp *Response, options ...ContentOpts) *Typed[T] {
+	resp.chain.enter("Decode[%s]()", typeName[T]())
+	defer resp.chain.leave()
+
+	var value T
+
+	if resp.chain.failed() {
+		return newTyped(resp.chain, value)
+	}
+
+	if len(options) > 1 {
+		resp.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple options arguments"),
+			},
+		})
+		return newTyped(resp.chain, value)
+	}
+
+	if !resp.checkContentOptions(options, "application/json") {
+		return newTyped(resp.chain, value)
+	}
+
+	if err := json.Unmarshal(resp.content, &value); err != nil {
+		resp.chain.fail(AssertionFailure{
+			Type: AssertValid,
+			Actual: &AssertionValue{
+				string(resp.content),
+			},
+			Errors: []error{
+				fmt.Errorf("failed to decode json into %s", typeName[T]()),
+				err,
+			},
+		})
+		return newTyped(resp.chain, value)
+	}
+
+	return newTyped(resp.chain, value)
+}
+
+// Raw returns underlying value of type T.
+//
+// If decoding failed, zero value is returned.
+//
+// Example:
+//
+//	user := httpexpect.Decode[User](resp)
+//	assert.Equal(t, "john", user.Raw().Name)
+func (t *Typed[T]) Raw() T {
+	return t.value
+}
+
+// Value returns a new Value instance with JSON representation of
+// underlying value.
+//
+// Example:
+//
+//	user := httpexpect.Decode[User](resp)
+//	user.Value().Object().ContainsKey("name")
+func (t *Typed[T]) Value() *Value {
+	t.chain.enter("Value()")
+	defer t.chain.leave()
+
+	if t.chain.failed() {
+		return newValue(t.chain, nil)
+	}
+
+	value, ok := canonValue(t.chain, t.value)
+	if !ok {
+		return newValue(t.chain, nil)
+	}
+
+	return newValue(t.chain, value)
+}
+
+// Field returns a new Value instance with JSON representation of struct
+// field with given Go name.
+//
+// Name may be a dot-separated path to nested field, e.g. "Address.City".
+// Pointers are dereferenced; if a nil pointer is met, field value is null.
+// Fails if T is not a struct or there is no such exported field.
+//
+// Example:
+//
+//	user := httpexpect.Decode[User](resp)
+//	user.Field("Name").String().Equal("john")
+//	user.Field("Address.City").String().NotEmpty()
+func (t *Typed[T]) Field(name string) *Value {
+	t.chain.enter("Field(%q)", name)
+	defer t.chain.leave()
+
+	if t.chain.failed() {
+		return newValue(t.chain, nil)
+	}
+
+	field := reflect.ValueOf(&t.value).Elem()
+
+	for _, part := range strings.Split(name, ".") {
+		for field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+			if field.IsNil() {
+				return newValue(t.chain, nil)
+			}
+			field = field.Elem()
+		}
+
+		if field.Kind() != reflect.Struct {
+			t.chain.fail(AssertionFailure{
+				Type:   AssertUsage,
+				Actual: &AssertionValue{field.Type().String()},
+				Errors: []error{
+					fmt.Errorf("can't select field %q from non-struct type", part),
+				},
+			})
+			return newValue(t.chain, nil)
+		}
+
+		sf, ok := field.Type().FieldByName(part)
+		if !ok || sf.PkgPath != "" {
+			t.chain.fail(AssertionFailure{
+				Type:   AssertUsage,
+				Actual: &AssertionValue{field.Type().String()},
+				Errors: []error{
+					fmt.Errorf("type has no exported field %q", part),
+				},
+			})
+			return newValue(t.chain, nil)
+		}
+
+		field = field.FieldByIndex(sf.Index)
+	}
+
+	value, ok := canonValue(t.chain, field.Interface())
+	if !ok {
+		return newValue(t.chain, nil)
+	}
+
+	return newValue(t.chain, value)
+}
+
+// Equal succeeds if underlying value is deeply equal to given value.
+//
+// Example:
+//
+//	user := httpexpect.Decode[User](resp)
+//	user.Equal(User{ID: 1, Name: "john"})
+func (t *Typed[T]) Equal(value T) *Typed[T] {
+	t.chain.enter("Equal()")
+	defer t.chain.leave()
+
+	if t.chain.failed() {
+		return t
+	}
+
+	if !reflect.DeepEqual(value, t.value) {
+		t.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{t.value},
+			Expected: &AssertionValue{value},
+			Errors: []error{
+				errors.New("expected: values are equal"),
+			},
+		})
+	}
+
+	return t
+}
+
+// NotEqual succeeds if underlying value is not deeply equal to given value.
+//
+// Example:
+//
+//	user := httpexpect.Decode[User](resp)
+//	user.NotEqual(User{})
+func (t *Typed[T]) NotEqual(value T) *Typed[T] {
+	t.chain.enter("NotEqual()")
+	defer t.chain.leave()
+
+	if t.chain.failed() {
+		return t
+	}
+
+	if reflect.DeepEqual(value, t.value) {
+		t.chain.fail(AssertionFailure{
+			Type:     AssertNotEqual,
+			Actual:   &AssertionValue{t.value},
+			Expected: &AssertionValue{value},
+			Errors: []error{
+				errors.New("expected: values are non-equal"),
+			},
+		})
+	}
+
+	return t
+}
+
+// Assert succeeds if given function returns nil for underlying value.
+//
+// Like Value.Assert, but function receives value of type T. Function
+// may return *AssertionError to customize reported failure.
+//
+// Example:
+//
+//	user := httpexpect.Decode[User](resp)
+//	user.Assert("IsAdult", func(u User) error {
+//	    if u.Age < 18 {
+//	        return errors.New("user is underage")
+//	    }
+//	    return nil
+//	})
+func (t *Typed[T]) Assert(name string, fn func(value T) error) *Typed[T] {
+	t.chain.enter("Assert(%q)", name)
+	defer t.chain.leave()
+
+	if t.chain.failed() {
+		return t
+	}
+
+	if fn == nil {
+		t.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil function argument"),
+			},
+		})
+		return t
+	}
+
+	if err := fn(t.value); err != nil {
+		failCustomAssertion(t.chain, name, t.value, err)
+	}
+
+	return t
+}
+
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
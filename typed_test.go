@@ -0,0 +1,223 @@
+//go:build go1.21
+// +build go1.21
+
+package httpexpect
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedAddress struct {
+	City string `json:"city"`
+}
+
+type typedUser struct {
+	ID      int           `json:"id"`
+	Name    string        `json:"name"`
+	Tags    []string      `json:"tags"`
+	Address *typedAddress `json:"address"`
+
+	secret string
+}
+
+func newTypedResponse(t *testing.T, body interface{}) *Response {
+	e := WithConfig(Config{
+		BaseURL: "http://example.com",
+		Client: &mockClient{
+			resp: http.Response{
+				StatusCode: http.StatusOK,
+			},
+		},
+		Reporter: newMockReporter(t),
+	})
+
+	return e.POST("/").WithJSON(body).Expect()
+}
+
+func TestTypedFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	typed := newTyped(chain, typedUser{})
+
+	typed.Value().chain.assertFailed(t)
+	typed.Field("Name").chain.assertFailed(t)
+
+	typed.Equal(typedUser{})
+	typed.NotEqual(typedUser{})
+	typed.Assert("foo", func(typedUser) error {
+		t.Fatal("function should not be called")
+		return nil
+	})
+}
+
+func TestTypedDecode(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		resp := newTypedResponse(t, map[string]interface{}{
+			"id":      1,
+			"name":    "john",
+			"tags":    []string{"a", "b"},
+			"address": map[string]interface{}{"city": "Paris"},
+			"unknown": true,
+		})
+
+		user := Decode[typedUser](resp)
+		user.chain.assertOK(t)
+
+		assert.Equal(t, typedUser{
+			ID:      1,
+			Name:    "john",
+			Tags:    []string{"a", "b"},
+			Address: &typedAddress{City: "Paris"},
+		}, user.Raw())
+
+		assert.Equal(t,
+			[]string{`Request("POST")`, "Expect()", "Decode[httpexpect.typedUser]()"},
+			user.chain.context.Path)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		resp := newTypedResponse(t, map[string]interface{}{
+			"id": "not a number",
+		})
+
+		user := Decode[typedUser](resp)
+		user.chain.assertFailed(t)
+		resp.chain.assertFailed(t)
+
+		assert.Equal(t, typedUser{}, user.Raw())
+	})
+
+	t.Run("content type", func(t *testing.T) {
+		resp := newTypedResponse(t, map[string]interface{}{})
+
+		Decode[typedUser](resp, ContentOpts{
+			MediaType: "application/xml",
+		}).chain.assertFailed(t)
+	})
+
+	t.Run("failed response", func(t *testing.T) {
+		resp := newTypedResponse(t, map[string]interface{}{"id": 1})
+		resp.chain.setFailed()
+
+		user := Decode[typedUser](resp)
+		user.chain.assertFailed(t)
+
+		assert.Equal(t, typedUser{}, user.Raw())
+	})
+}
+
+func TestTypedField(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	typed := NewTyped(reporter, typedUser{
+		ID:     1,
+		Name:   "john",
+		Tags:   []string{"a"},
+		secret: "foo",
+	})
+
+	assert.Equal(t, []string{"Typed[httpexpect.typedUser]()"},
+		typed.chain.context.Path)
+
+	typed.Field("ID").Number().Equal(1).chain.assertOK(t)
+	typed.Field("Name").String().Equal("john").chain.assertOK(t)
+	typed.Field("Tags").Array().Elements("a").chain.assertOK(t)
+	typed.Field("Address").Null().chain.assertOK(t)
+	typed.Field("Address.City").Null().chain.assertOK(t)
+
+	for _, name := range []string{"Missing", "secret", "Name.Foo"} {
+		typed.Field(name).chain.assertFailed(t)
+		typed.chain.assertFailed(t)
+		typed.chain.reset()
+	}
+
+	typed = NewTyped(reporter, typedUser{
+		Address: &typedAddress{City: "Paris"},
+	})
+
+	typed.Field("Address.City").String().Equal("Paris").chain.assertOK(t)
+	typed.Field("Address").Object().ValueEqual("city", "Paris").chain.assertOK(t)
+
+	NewTyped(reporter, 123).Field("Foo").chain.assertFailed(t)
+}
+
+func TestTypedValue(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	typed := NewTyped(reporter, typedUser{ID: 1, Name: "john"})
+
+	typed.Value().Object().
+		ValueEqual("id", 1).
+		ValueEqual("name", "john").
+		NotContainsKey("secret").
+		chain.assertOK(t)
+}
+
+func TestTypedEqual(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	typed := NewTyped(reporter, typedUser{ID: 1, Tags: []string{"a"}})
+
+	typed.Equal(typedUser{ID: 1, Tags: []string{"a"}})
+	typed.chain.assertOK(t)
+	typed.chain.reset()
+
+	typed.Equal(typedUser{ID: 2})
+	typed.chain.assertFailed(t)
+	typed.chain.reset()
+
+	typed.NotEqual(typedUser{ID: 2})
+	typed.chain.assertOK(t)
+	typed.chain.reset()
+
+	typed.NotEqual(typedUser{ID: 1, Tags: []string{"a"}})
+	typed.chain.assertFailed(t)
+	typed.chain.reset()
+}
+
+func TestTypedAssert(t *testing.T) {
+	handler := &mockAssertionHandler{}
+
+	e := WithConfig(Config{
+		BaseURL: "http://example.com",
+		Client: &mockClient{
+			resp: http.Response{
+				StatusCode: http.StatusOK,
+			},
+		},
+		AssertionHandler: handler,
+	})
+
+	user := Decode[typedUser](
+		e.POST("/").WithJSON(typedUser{ID: 1}).Expect())
+
+	isNamed := func(u typedUser) error {
+		if u.Name == "" {
+			return errors.New("user has no name")
+		}
+		return nil
+	}
+
+	user.Assert("IsNamed", isNamed)
+	user.chain.assertFailed(t)
+
+	require.NotNil(t, handler.failure)
+	assert.Equal(t, AssertValid, handler.failure.Type)
+	assert.Equal(t, "user has no name", handler.failure.Errors[1].Error())
+
+	user.chain.reset()
+
+	user.Assert("IsNamed", nil)
+	user.chain.assertFailed(t)
+	assert.Equal(t, AssertUsage, handler.failure.Type)
+
+	NewTyped(newMockReporter(t), typedUser{Name: "john"}).
+		Assert("IsNamed", isNamed).
+		chain.assertOK(t)
+}
@@ -0,0 +1,181 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// UploadProgress provides methods to inspect how streamed request body
+// (see Request.WithBodyStream and Request.WithFileStream) was delivered.
+//
+// Progress is measured on client side, as body is consumed by transport.
+// When using Binder, body is read directly by handler, so this is when
+// handler received the data. When using real server, this is when data
+// was passed to connection.
+type UploadProgress struct {
+	chain *chain
+	value uploadStats
+}
+
+type uploadStats struct {
+	chunks   int
+	bytes    int64
+	duration time.Duration
+}
+
+func newUploadProgress(parent *chain, rec *uploadRecorder) *UploadProgress {
+	p := &UploadProgress{chain: parent.clone()}
+
+	if rec != nil {
+		p.value = rec.stats()
+	}
+
+	return p
+}
+
+// Chunks returns a new Number instance with number of chunks in which
+// request body was consumed, i.e. number of reads that returned data.
+//
+// Example:
+//
+//	resp := req.WithBodyStream(reader).Expect()
+//	resp.UploadProgress().Chunks().Ge(3)
+func (p *UploadProgress) Chunks() *Number {
+	p.chain.enter("Chunks()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newNumber(p.chain, 0)
+	}
+
+	return newNumber(p.chain, float64(p.value.chunks))
+}
+
+// Bytes returns a new Number instance with number of body bytes that
+// were consumed.
+//
+// Example:
+//
+//	resp := req.WithBodyStream(reader).Expect()
+//	resp.UploadProgress().Bytes().IsEqual(len(data))
+func (p *UploadProgress) Bytes() *Number {
+	p.chain.enter("Bytes()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newNumber(p.chain, 0)
+	}
+
+	return newNumber(p.chain, float64(p.value.bytes))
+}
+
+// Duration returns a new Duration instance with time between first and
+// last chunk of body were consumed.
+//
+// Example:
+//
+//	resp := req.WithBodyStream(reader).Expect()
+//	resp.UploadProgress().Duration().Gt(time.Second)
+func (p *UploadProgress) Duration() *Duration {
+	p.chain.enter("Duration()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return newDuration(p.chain, nil)
+	}
+
+	duration := p.value.duration
+
+	return newDuration(p.chain, &duration)
+}
+
+// Progressive succeeds if request body was consumed in at least two chunks,
+// i.e. first chunk was delivered before the rest of body became available,
+// instead of being buffered and delivered at once.
+//
+// Example:
+//
+//	pr, pw := io.Pipe()
+//	go func() {
+//	    for _, chunk := range chunks {
+//	        pw.Write(chunk)
+//	    }
+//	    pw.Close()
+//	}()
+//
+//	resp := req.WithBodyStream(pr).Expect()
+//	resp.UploadProgress().Progressive()
+func (p *UploadProgress) Progressive() *UploadProgress {
+	p.chain.enter("Progressive()")
+	defer p.chain.leave()
+
+	if p.chain.failed() {
+		return p
+	}
+
+	if p.value.chunks < 2 {
+		p.chain.fail(AssertionFailure{
+			Type:     AssertGe,
+			Actual:   &AssertionValue{p.value.chunks},
+			Expected: &AssertionValue{2},
+			Errors: []error{
+				errors.New("expected: request body is delivered progressively"),
+				fmt.Errorf("body was delivered in %d chunk(s)", p.value.chunks),
+			},
+		})
+	}
+
+	return p
+}
+
+// records reads of streamed request body made by transport
+type uploadRecorder struct {
+	mu     sync.Mutex
+	chunks int
+	bytes  int64
+	first  time.Time
+	last   time.Time
+}
+
+func (rec *uploadRecorder) record(n int) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	now := time.Now()
+
+	if rec.chunks == 0 {
+		rec.first = now
+	}
+
+	rec.chunks++
+	rec.bytes += int64(n)
+	rec.last = now
+}
+
+func (rec *uploadRecorder) stats() uploadStats {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	return uploadStats{
+		chunks:   rec.chunks,
+		bytes:    rec.bytes,
+		duration: rec.last.Sub(rec.first),
+	}
+}
+
+// wraps streamed request body and records each read that returned data
+type uploadBody struct {
+	io.ReadCloser
+	rec *uploadRecorder
+}
+
+func (b *uploadBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.rec.record(n)
+	}
+	return n, err
+}
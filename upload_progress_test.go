@@ -0,0 +1,85 @@
+package httpexpect
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadProgressFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newUploadProgress(chain, &uploadRecorder{})
+
+	value.chain.assertFailed(t)
+
+	value.Chunks().chain.assertFailed(t)
+	value.Bytes().chain.assertFailed(t)
+	value.Duration().chain.assertFailed(t)
+	value.Progressive().chain.assertFailed(t)
+}
+
+func TestUploadProgressStats(t *testing.T) {
+	t.Run("progressive", func(t *testing.T) {
+		rec := &uploadRecorder{}
+
+		rec.record(3)
+		time.Sleep(time.Millisecond)
+		rec.record(4)
+
+		value := newUploadProgress(newMockChain(t), rec)
+
+		value.Chunks().Equal(2).chain.assertOK(t)
+		value.Bytes().Equal(7).chain.assertOK(t)
+		value.Duration().Gt(0).chain.assertOK(t)
+		value.Progressive().chain.assertOK(t)
+	})
+
+	t.Run("single chunk", func(t *testing.T) {
+		rec := &uploadRecorder{}
+
+		rec.record(7)
+
+		value := newUploadProgress(newMockChain(t), rec)
+
+		value.Chunks().Equal(1).chain.assertOK(t)
+		value.Bytes().Equal(7).chain.assertOK(t)
+		value.Duration().Equal(0).chain.assertOK(t)
+		value.Progressive().chain.assertFailed(t)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		value := newUploadProgress(newMockChain(t), &uploadRecorder{})
+
+		value.Chunks().Equal(0).chain.assertOK(t)
+		value.Bytes().Equal(0).chain.assertOK(t)
+		value.Duration().Equal(0).chain.assertOK(t)
+		value.Progressive().chain.assertFailed(t)
+	})
+}
+
+func TestUploadProgressBody(t *testing.T) {
+	rec := &uploadRecorder{}
+
+	body := &uploadBody{
+		ReadCloser: ioutil.NopCloser(io.MultiReader(
+			strings.NewReader("foo"),
+			strings.NewReader("barbaz"),
+		)),
+		rec: rec,
+	}
+
+	b, err := ioutil.ReadAll(body)
+	assert.NoError(t, err)
+	assert.Equal(t, "foobarbaz", string(b))
+
+	stats := rec.stats()
+
+	assert.Equal(t, 2, stats.chunks)
+	assert.Equal(t, int64(9), stats.bytes)
+}
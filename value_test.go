@@ -38,6 +38,12 @@ func TestValueFailed(t *testing.T) {
 
 	value.Equal(nil)
 	value.NotEqual(nil)
+
+	value.Assert("foo", func(interface{}) error {
+		t.Fatal("function should not be called")
+		return nil
+	})
+	value.Check("foo")
 }
 
 func TestValueCastNull(t *testing.T) {
@@ -1,9 +1,15 @@
 package httpexpect
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -19,12 +25,22 @@ type Websocket struct {
 	config Config
 	chain  *chain
 
-	conn WebsocketConn
+	conn       WebsocketConn
+	wire       *websocketWire
+	dial       *websocketDial
+	handshake  http.Header
+	transcript *websocketTranscript
 
 	readTimeout  time.Duration
 	writeTimeout time.Duration
 
 	isClosed bool
+	isClient bool
+
+	control  *websocketControl
+	lastPing []byte
+	reads    chan websocketRead
+	pending  []websocketRead
 }
 
 // WebsocketConn is used by Websocket to communicate with actual WebSocket connection.
@@ -37,6 +53,49 @@ type WebsocketConn interface {
 	Subprotocol() string
 }
 
+// WebsocketFragmentConn is an optional interface that may be implemented
+// by WebsocketConn to support fragmented messages, i.e. messages sent as
+// several frames (RFC 6455, section 5.4).
+//
+// If connection implements this interface, Websocket uses ReadFragments
+// instead of ReadMessage, and WebsocketMessage reports individual
+// fragments. WriteFragments is used by Websocket.WriteFragmented.
+//
+// Connections established using Request.WithWebsocketUpgrade don't need
+// to implement this interface: fragments of received messages are known if
+// connection is not encrypted (ws://) and uses *websocket.Dialer, and
+// WriteFragmented writes frames directly to the underlying net.Conn.
+// Otherwise, every received message is treated as single fragment, and
+// WriteFragmented reports failure.
+type WebsocketFragmentConn interface {
+	// ReadFragments reads next message and returns payload of its frames.
+	ReadFragments() (messageType int, fragments [][]byte, err error)
+
+	// WriteFragments writes message, sending every fragment in its own frame.
+	WriteFragments(messageType int, fragments [][]byte) error
+}
+
+// WebsocketControlConn is an optional interface that may be implemented
+// by WebsocketConn to support control frames, i.e. pings and pongs
+// (RFC 6455, section 5.5). It is implemented by *websocket.Conn.
+//
+// If connection implements this interface, Websocket installs its own
+// ping and pong handlers, which record received control frames; ping
+// handler also replies with a pong, like the default one.
+//
+// Websocket.ExpectPing and Websocket.ExpectPong require this interface.
+// If connection doesn't implement it, they report failure.
+type WebsocketControlConn interface {
+	// SetPingHandler sets handler invoked for received ping frames.
+	SetPingHandler(h func(appData string) error)
+
+	// SetPongHandler sets handler invoked for received pong frames.
+	SetPongHandler(h func(appData string) error)
+
+	// WriteControl writes control frame with given deadline.
+	WriteControl(messageType int, data []byte, deadline time.Time) error
+}
+
 // NewWebsocket returns a new Websocket instance.
 func NewWebsocket(config Config, conn WebsocketConn) *Websocket {
 	config.fillDefaults()
@@ -51,11 +110,20 @@ func NewWebsocket(config Config, conn WebsocketConn) *Websocket {
 func newWebsocket(parent *chain, config Config, conn WebsocketConn) *Websocket {
 	chain := parent.clone()
 
-	return &Websocket{
-		config: config,
-		chain:  chain,
-		conn:   conn,
+	c := &Websocket{
+		config:     config,
+		chain:      chain,
+		conn:       conn,
+		transcript: &websocketTranscript{},
 	}
+
+	c.chain.setWebsocket(c)
+
+	if cc, ok := conn.(WebsocketControlConn); ok {
+		c.control = newWebsocketControl(cc)
+	}
+
+	return c
 }
 
 // Conn returns underlying WebsocketConn object.
@@ -155,6 +223,86 @@ func (c *Websocket) Subprotocol() *String {
 	return newString(c.chain, c.conn.Subprotocol())
 }
 
+// Headers returns a new Object instance with header map of handshake
+// response, i.e. "101 Switching Protocols" response.
+//
+// Example:
+//
+//	ws := resp.Websocket()
+//	ws.Headers().ContainsKey("Sec-Websocket-Accept")
+func (c *Websocket) Headers() *Object {
+	c.chain.enter("Headers()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newObject(c.chain, nil)
+	}
+
+	var value map[string]interface{}
+	value, _ = canonMap(c.chain, c.getHandshake())
+
+	return newObject(c.chain, value)
+}
+
+// Header returns a new String instance with given header field of
+// handshake response.
+//
+// Example:
+//
+//	ws := resp.Websocket()
+//	ws.Header("Sec-WebSocket-Extensions").Contains("permessage-deflate")
+func (c *Websocket) Header(header string) *String {
+	c.chain.enter("Header(%q)", header)
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newString(c.chain, "")
+	}
+
+	return newString(c.chain, c.getHandshake().Get(header))
+}
+
+// Extensions returns a new Array instance with names of extensions
+// accepted by server in handshake response, using Sec-WebSocket-Extensions
+// header. Extension parameters are stripped.
+//
+// If header is missing, empty array is returned.
+//
+// Example:
+//
+//	ws := resp.Websocket()
+//	ws.Extensions().ContainsOnly("permessage-deflate")
+func (c *Websocket) Extensions() *Array {
+	c.chain.enter("Extensions()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newArray(c.chain, nil)
+	}
+
+	extensions := []interface{}{}
+
+	for _, value := range c.getHandshake().Values("Sec-WebSocket-Extensions") {
+		for _, ext := range strings.Split(value, ",") {
+			if i := strings.IndexByte(ext, ';'); i >= 0 {
+				ext = ext[:i]
+			}
+			if ext = strings.TrimSpace(ext); ext != "" {
+				extensions = append(extensions, ext)
+			}
+		}
+	}
+
+	return newArray(c.chain, extensions)
+}
+
+func (c *Websocket) getHandshake() http.Header {
+	if c.handshake == nil {
+		return http.Header{}
+	}
+	return c.handshake
+}
+
 // Expect reads next message from WebSocket connection and
 // returns a new WebsocketMessage instance.
 //
@@ -178,6 +326,281 @@ func (c *Websocket) Expect() *WebsocketMessage {
 	return m
 }
 
+// ExpectBinary reads next message from WebSocket connection, checks that
+// it's a binary message, and returns a new WebsocketMessage instance.
+//
+// Example:
+//
+//	msg := conn.ExpectBinary()
+//	msg.BodyBytes().HasPrefix([]byte{0x01, 0x02})
+func (c *Websocket) ExpectBinary() *WebsocketMessage {
+	c.chain.enter("ExpectBinary()")
+	defer c.chain.leave()
+
+	if c.checkUnusable("ExpectBinary()") {
+		return newWebsocketMessage(c.chain)
+	}
+
+	m := c.readMessage()
+	if m == nil {
+		return newWebsocketMessage(c.chain)
+	}
+
+	m.checkType(websocket.BinaryMessage)
+
+	return m
+}
+
+// ExpectText reads next message from WebSocket connection, checks that
+// it's a text message, and returns a new WebsocketMessage instance.
+//
+// Example:
+//
+//	msg := conn.ExpectText()
+//	msg.Body().Equal("hello")
+func (c *Websocket) ExpectText() *WebsocketMessage {
+	c.chain.enter("ExpectText()")
+	defer c.chain.leave()
+
+	if c.checkUnusable("ExpectText()") {
+		return newWebsocketMessage(c.chain)
+	}
+
+	m := c.readMessage()
+	if m == nil {
+		return newWebsocketMessage(c.chain)
+	}
+
+	m.checkType(websocket.TextMessage)
+
+	return m
+}
+
+// ExpectJSON reads messages from WebSocket connection until it receives
+// a message with JSON content matching given value, and returns a new
+// WebsocketMessage instance for it.
+//
+// Messages that are not JSON or don't match are skipped, which allows to
+// wait for specific event in a stream interleaved with heartbeats or other
+// events. If match is an object (map or struct), message should contain
+// it as a subset, like in Object.ContainsSubset; otherwise message should
+// be equal to it. Before comparison, match is converted to canonical form.
+//
+// Read timeout (see WithReadTimeout) is applied to the whole sequence of
+// reads, not to every message. ExpectJSON fails if timeout expires or
+// close message is received before a matching message.
+//
+// Example:
+//
+//	msg := conn.ExpectJSON(map[string]interface{}{
+//	    "event": "subscribed",
+//	})
+//	msg.JSON().Object().Value("channel").Equal("orders")
+func (c *Websocket) ExpectJSON(match interface{}) *WebsocketMessage {
+	c.chain.enter("ExpectJSON()")
+	defer c.chain.leave()
+
+	if c.checkUnusable("ExpectJSON()") {
+		return newWebsocketMessage(c.chain)
+	}
+
+	expected, ok := canonValue(c.chain, match)
+	if !ok {
+		return newWebsocketMessage(c.chain)
+	}
+
+	if !c.setReadDeadline() {
+		return newWebsocketMessage(c.chain)
+	}
+
+	for skipped := 0; ; skipped++ {
+		m, err := c.receiveMessage()
+		if err != nil {
+			c.chain.fail(AssertionFailure{
+				Type:     AssertOperation,
+				Expected: &AssertionValue{expected},
+				Errors: []error{
+					fmt.Errorf(
+						"failed to read matching message from websocket"+
+							" (%d message(s) skipped)", skipped),
+					err,
+				},
+			})
+			return newWebsocketMessage(c.chain)
+		}
+
+		if m.typ == websocket.CloseMessage {
+			c.chain.fail(AssertionFailure{
+				Type:     AssertOperation,
+				Actual:   &AssertionValue{m},
+				Expected: &AssertionValue{expected},
+				Errors: []error{
+					fmt.Errorf(
+						"connection closed before matching message was received"+
+							" (%d message(s) skipped)", skipped),
+				},
+			})
+			return newWebsocketMessage(c.chain)
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(m.content, &value); err != nil {
+			continue
+		}
+
+		if checkJSONMatch(value, expected) {
+			return m
+		}
+	}
+}
+
+// ExpectClose reads next message from WebSocket connection, checks that
+// it's a close message, and returns a new WebsocketMessage instance.
+//
+// If close codes are given, ExpectClose also checks that close code is
+// one of them, like WebsocketMessage.Code.
+//
+// If connection is dropped without close message, close code is
+// "1006 - Abnormal Closure".
+//
+// Example:
+//
+//	msg := conn.ExpectClose(websocket.CloseNormalClosure)
+//	msg.Body().Equal("bye")
+func (c *Websocket) ExpectClose(code ...int) *WebsocketMessage {
+	c.chain.enter("ExpectClose()")
+	defer c.chain.leave()
+
+	if c.checkUnusable("ExpectClose()") {
+		return newWebsocketMessage(c.chain)
+	}
+
+	m := c.readMessage()
+	if m == nil {
+		return newWebsocketMessage(c.chain)
+	}
+
+	m.checkType(websocket.CloseMessage)
+
+	if len(code) != 0 {
+		m.checkCode(code...)
+	}
+
+	return m
+}
+
+// Ping writes ping control frame with given payload to the underlying
+// WebSocket connection.
+//
+// Payload should not be longer than 125 bytes. Use ExpectPong to wait
+// for reply.
+//
+// Example:
+//
+//	conn := resp.Websocket()
+//	conn.Ping([]byte("hello")).ExpectPong(time.Second)
+func (c *Websocket) Ping(payload []byte) *Websocket {
+	c.chain.enter("Ping()")
+	defer c.chain.leave()
+
+	if c.checkUnusable("Ping()") {
+		return c
+	}
+
+	if len(payload) > maxControlPayload {
+		c.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{len(payload)},
+			Errors: []error{
+				fmt.Errorf("unexpected ping payload longer than %d bytes",
+					maxControlPayload),
+			},
+		})
+		return c
+	}
+
+	c.printWrite(websocket.PingMessage, payload, 0)
+
+	var err error
+	if c.control != nil {
+		deadline := infiniteTime
+		if c.writeTimeout != noDuration {
+			deadline = time.Now().Add(c.writeTimeout)
+		}
+		err = c.control.conn.WriteControl(websocket.PingMessage, payload, deadline)
+	} else {
+		if !c.setWriteDeadline() {
+			return c
+		}
+		err = c.conn.WriteMessage(websocket.PingMessage, payload)
+	}
+
+	if err != nil {
+		c.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to write ping to websocket"),
+				err,
+			},
+		})
+		return c
+	}
+
+	c.lastPing = append([]byte{}, payload...)
+
+	return c
+}
+
+// ExpectPong waits until pong control frame is received from WebSocket
+// connection, and returns a new WebsocketMessage instance with its payload.
+//
+// If Ping was called before, ExpectPong waits for pong with the same
+// payload, as required by RFC; other pongs are skipped. ExpectPong fails
+// if matching pong is not received within given timeout.
+//
+// Data messages received while waiting are not lost and are returned
+// by subsequent Expect calls.
+//
+// Underlying connection should implement WebsocketControlConn interface,
+// otherwise failure is reported.
+//
+// Example:
+//
+//	conn := resp.Websocket()
+//	conn.Ping([]byte("hello")).
+//	    ExpectPong(time.Second).
+//	    Body().Equal("hello")
+func (c *Websocket) ExpectPong(timeout time.Duration) *WebsocketMessage {
+	c.chain.enter("ExpectPong()")
+	defer c.chain.leave()
+
+	return c.expectControl("ExpectPong()", websocket.PongMessage, c.lastPing, timeout)
+}
+
+// ExpectPing waits until ping control frame is received from WebSocket
+// connection, and returns a new WebsocketMessage instance with its payload.
+// It's useful to check server-initiated keep-alive pings.
+//
+// Received pings are replied with pongs automatically. ExpectPing fails
+// if ping is not received within given timeout.
+//
+// Data messages received while waiting are not lost and are returned
+// by subsequent Expect calls.
+//
+// Underlying connection should implement WebsocketControlConn interface,
+// otherwise failure is reported.
+//
+// Example:
+//
+//	conn := resp.Websocket()
+//	conn.ExpectPing(30 * time.Second)
+func (c *Websocket) ExpectPing(timeout time.Duration) *WebsocketMessage {
+	c.chain.enter("ExpectPing()")
+	defer c.chain.leave()
+
+	return c.expectControl("ExpectPing()", websocket.PingMessage, nil, timeout)
+}
+
 // Disconnect closes the underlying WebSocket connection without sending or
 // waiting for a close message.
 //
@@ -456,6 +879,102 @@ func (c *Websocket) WriteText(s string) *Websocket {
 	return c.WriteMessage(websocket.TextMessage, []byte(s))
 }
 
+// WriteFragmented writes text or binary message to the underlying WebSocket
+// connection, sending every fragment in its own frame.
+//
+// Underlying connection should either implement WebsocketFragmentConn
+// interface, or be established using Request.WithWebsocketUpgrade,
+// otherwise failure is reported.
+//
+// Example:
+//
+//	conn := resp.Websocket()
+//	conn.WriteFragmented(websocket.TextMessage, []byte("hel"), []byte("lo"))
+func (c *Websocket) WriteFragmented(typ int, fragments ...[]byte) *Websocket {
+	c.chain.enter("WriteFragmented()")
+	defer c.chain.leave()
+
+	if c.checkUnusable("WriteFragmented()") {
+		return c
+	}
+
+	if typ != websocket.TextMessage && typ != websocket.BinaryMessage {
+		c.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				fmt.Errorf("unexpected websocket message type %s",
+					wsMessageType(typ)),
+			},
+		})
+		return c
+	}
+
+	if len(fragments) == 0 {
+		c.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected empty fragments list"),
+			},
+		})
+		return c
+	}
+
+	fc, ok := c.conn.(WebsocketFragmentConn)
+	gc, isGorilla := c.conn.(*websocket.Conn)
+
+	if !ok && !(isGorilla && c.isClient) {
+		c.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New(
+					"websocket connection doesn't implement WebsocketFragmentConn"),
+			},
+		})
+		return c
+	}
+
+	c.printWrite(typ, bytes.Join(fragments, nil), 0)
+
+	if !c.setWriteDeadline() {
+		return c
+	}
+
+	var err error
+	if ok {
+		err = fc.WriteFragments(typ, fragments)
+	} else {
+		err = c.writeFrames(gc, typ, fragments)
+	}
+
+	if err != nil {
+		c.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to write to websocket"),
+				err,
+			},
+		})
+	}
+
+	return c
+}
+
+// writes fragments as raw frames to connection underlying websocket.Conn
+func (c *Websocket) writeFrames(conn *websocket.Conn, typ int, fragments [][]byte) error {
+	netConn := conn.UnderlyingConn()
+
+	deadline := infiniteTime
+	if c.writeTimeout != noDuration {
+		deadline = time.Now().Add(c.writeTimeout)
+	}
+
+	if err := netConn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+
+	return writeWebsocketFrames(netConn, typ, fragments)
+}
+
 // WriteJSON writes to the underlying WebSocket connection given object,
 // marshaled using json.Marshal().
 func (c *Websocket) WriteJSON(object interface{}) *Websocket {
@@ -511,37 +1030,183 @@ func (c *Websocket) checkUnusable(where string) bool {
 	return false
 }
 
-func (c *Websocket) readMessage() *WebsocketMessage {
-	m := newWebsocketMessage(c.chain)
+// invokes function that reads or writes messages, reporting failures
+// to given chain instead of Websocket chain; used by protocol layers
+func (c *Websocket) withChain(chain *chain, fn func()) {
+	saved := c.chain
+	c.chain = chain
+	defer func() {
+		c.chain = saved
+	}()
+
+	fn()
+}
 
+func (c *Websocket) readMessage() *WebsocketMessage {
 	if !c.setReadDeadline() {
 		return nil
 	}
 
-	var err error
-	m.typ, m.content, err = c.conn.ReadMessage()
-
+	m, err := c.receiveMessage()
 	if err != nil {
+		c.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to read from websocket"),
+				err,
+			},
+		})
+		return nil
+	}
+
+	return m
+}
+
+func (c *Websocket) receiveMessage() (*WebsocketMessage, error) {
+	r := c.nextRead()
+
+	m := newWebsocketMessage(c.chain)
+
+	m.typ, m.content, m.fragments = r.typ, r.content, r.fragments
+
+	if err := r.err; err != nil {
 		closeErr, ok := err.(*websocket.CloseError)
 		if !ok {
-			c.chain.fail(AssertionFailure{
-				Type: AssertOperation,
-				Errors: []error{
-					errors.New("failed to read from websocket"),
-					err,
-				},
-			})
-			return nil
+			return nil, err
 		}
 
 		m.typ = websocket.CloseMessage
 		m.closeCode = closeErr.Code
 		m.content = []byte(closeErr.Text)
+		m.fragments = nil
+	}
+
+	if m.typ == websocket.TextMessage || m.typ == websocket.BinaryMessage {
+		if wm, ok := c.wire.next(); ok {
+			m.wire = &wm
+
+			if m.fragments == nil {
+				m.fragments = wm.split(m.content)
+			}
+		}
 	}
 
 	c.printRead(m.typ, m.content, m.closeCode)
 
-	return m
+	return m, nil
+}
+
+// returns result of read performed in background while waiting for
+// control frames, or reads from connection
+func (c *Websocket) nextRead() websocketRead {
+	if len(c.pending) != 0 {
+		r := c.pending[0]
+		c.pending = c.pending[1:]
+		return r
+	}
+
+	if c.reads != nil {
+		r := <-c.reads
+		c.reads = nil
+		return r
+	}
+
+	return readWebsocketConn(c.conn)
+}
+
+func (c *Websocket) expectControl(
+	where string, typ int, payload []byte, timeout time.Duration,
+) *WebsocketMessage {
+	if c.checkUnusable(where) {
+		return newWebsocketMessage(c.chain)
+	}
+
+	if c.control == nil {
+		c.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New(
+					"websocket connection doesn't implement WebsocketControlConn"),
+			},
+		})
+		return newWebsocketMessage(c.chain)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		if data, ok := c.control.take(typ, payload); ok {
+			c.printRead(typ, data, 0)
+
+			m := newWebsocketMessage(c.chain)
+			m.typ = typ
+			m.content = data
+			return m
+		}
+
+		// control frames are handled by connection only while reading,
+		// so keep reading in background and remember received messages
+		if c.reads == nil && !c.readFailed() {
+			if err := c.conn.SetReadDeadline(infiniteTime); err != nil {
+				c.chain.fail(AssertionFailure{
+					Type: AssertOperation,
+					Errors: []error{
+						errors.New("failed to set read deadline for websocket"),
+						err,
+					},
+				})
+				return newWebsocketMessage(c.chain)
+			}
+
+			c.startRead()
+		}
+
+		select {
+		case <-c.control.notify:
+
+		case r := <-c.reads:
+			c.pending = append(c.pending, r)
+			c.reads = nil
+
+		case <-timer.C:
+			var errs []error
+			if payload != nil {
+				errs = append(errs,
+					fmt.Errorf("expected: %s with payload %q received within %s",
+						wsMessageType(typ), payload, timeout))
+			} else {
+				errs = append(errs,
+					fmt.Errorf("expected: %s received within %s",
+						wsMessageType(typ), timeout))
+			}
+			if c.readFailed() {
+				errs = append(errs, c.pending[len(c.pending)-1].err)
+			}
+			c.chain.fail(AssertionFailure{
+				Type:   AssertOperation,
+				Errors: errs,
+			})
+			return newWebsocketMessage(c.chain)
+		}
+	}
+}
+
+// starts read in background, if it's not started yet
+func (c *Websocket) startRead() {
+	if c.reads != nil || c.readFailed() {
+		return
+	}
+
+	reads := make(chan websocketRead, 1)
+	go func(conn WebsocketConn) {
+		reads <- readWebsocketConn(conn)
+	}(c.conn)
+	c.reads = reads
+}
+
+func (c *Websocket) readFailed() bool {
+	return len(c.pending) != 0 && c.pending[len(c.pending)-1].err != nil
 }
 
 func (c *Websocket) writeMessage(typ int, content []byte, closeCode ...int) {
@@ -637,6 +1302,8 @@ func (c *Websocket) setWriteDeadline() bool {
 }
 
 func (c *Websocket) printRead(typ int, content []byte, closeCode int) {
+	c.transcript.add(false, typ, content, closeCode)
+
 	for _, printer := range c.config.Printers {
 		if p, ok := printer.(WebsocketPrinter); ok {
 			p.WebsocketRead(typ, content, closeCode)
@@ -645,9 +1312,116 @@ func (c *Websocket) printRead(typ int, content []byte, closeCode int) {
 }
 
 func (c *Websocket) printWrite(typ int, content []byte, closeCode int) {
+	c.transcript.add(true, typ, content, closeCode)
+
 	for _, printer := range c.config.Printers {
 		if p, ok := printer.(WebsocketPrinter); ok {
 			p.WebsocketWrite(typ, content, closeCode)
 		}
 	}
 }
+
+func checkJSONMatch(value, match interface{}) bool {
+	if mm, ok := match.(map[string]interface{}); ok {
+		vm, ok := value.(map[string]interface{})
+		return ok && checkSubset(vm, mm)
+	}
+	return reflect.DeepEqual(value, match)
+}
+
+// maximum payload length of control frame (RFC 6455, section 5.5)
+const maxControlPayload = 125
+
+// result of single read from connection
+type websocketRead struct {
+	typ       int
+	content   []byte
+	fragments [][]byte
+	err       error
+}
+
+func readWebsocketConn(conn WebsocketConn) (r websocketRead) {
+	if fc, ok := conn.(WebsocketFragmentConn); ok {
+		r.typ, r.fragments, r.err = fc.ReadFragments()
+		r.content = bytes.Join(r.fragments, nil)
+	} else {
+		r.typ, r.content, r.err = conn.ReadMessage()
+	}
+	return r
+}
+
+// records control frames received by connection; handlers are invoked
+// from goroutine that reads from connection
+type websocketControl struct {
+	conn WebsocketControlConn
+
+	mu     sync.Mutex
+	frames []websocketRead
+	notify chan struct{}
+}
+
+func newWebsocketControl(conn WebsocketControlConn) *websocketControl {
+	ctl := &websocketControl{
+		conn:   conn,
+		notify: make(chan struct{}, 1),
+	}
+
+	conn.SetPingHandler(func(data string) error {
+		ctl.push(websocket.PingMessage, []byte(data))
+
+		err := conn.WriteControl(websocket.PongMessage, []byte(data),
+			time.Now().Add(time.Second))
+		if err == websocket.ErrCloseSent {
+			return nil
+		}
+		if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+			return nil
+		}
+		return err
+	})
+
+	conn.SetPongHandler(func(data string) error {
+		ctl.push(websocket.PongMessage, []byte(data))
+		return nil
+	})
+
+	return ctl
+}
+
+func (ctl *websocketControl) push(typ int, data []byte) {
+	ctl.mu.Lock()
+	ctl.frames = append(ctl.frames, websocketRead{typ: typ, content: data})
+	ctl.mu.Unlock()
+
+	select {
+	case ctl.notify <- struct{}{}:
+	default:
+	}
+}
+
+// removes and returns first frame of given type; if payload is non-nil,
+// frames of given type with other payload are dropped
+func (ctl *websocketControl) take(typ int, payload []byte) ([]byte, bool) {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+
+	frames := ctl.frames[:0]
+	var (
+		data  []byte
+		found bool
+	)
+
+	for _, f := range ctl.frames {
+		if !found && f.typ == typ {
+			if payload == nil || bytes.Equal(f.content, payload) {
+				data, found = f.content, true
+			}
+			continue
+		}
+		frames = append(frames, f)
+	}
+
+	ctl.frames = frames
+
+	return data, found
+}
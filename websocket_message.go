@@ -12,7 +12,9 @@ type WebsocketMessage struct {
 	chain     *chain
 	typ       int
 	content   []byte
+	fragments [][]byte
 	closeCode int
+	wire      *websocketWireMessage
 }
 
 // NewWebsocketMessage returns a new WebsocketMessage instance.
@@ -253,8 +255,14 @@ func (m *WebsocketMessage) Code(code ...int) *WebsocketMessage {
 	m.chain.enter("Code()")
 	defer m.chain.leave()
 
+	m.checkCode(code...)
+
+	return m
+}
+
+func (m *WebsocketMessage) checkCode(code ...int) {
 	if m.chain.failed() {
-		return m
+		return
 	}
 
 	if len(code) == 0 {
@@ -264,7 +272,7 @@ func (m *WebsocketMessage) Code(code ...int) *WebsocketMessage {
 				errors.New("missing code argument"),
 			},
 		})
-		return m
+		return
 	}
 
 	if m.typ != websocket.CloseMessage {
@@ -276,7 +284,7 @@ func (m *WebsocketMessage) Code(code ...int) *WebsocketMessage {
 				errors.New("expected: close message"),
 			},
 		})
-		return m
+		return
 	}
 
 	found := false
@@ -308,8 +316,6 @@ func (m *WebsocketMessage) Code(code ...int) *WebsocketMessage {
 			})
 		}
 	}
-
-	return m
 }
 
 // NotCode succeeds if WebSocket close code is none of the given.
@@ -404,6 +410,253 @@ func (m *WebsocketMessage) Body() *String {
 	return newString(m.chain, string(m.content))
 }
 
+// BodyBytes returns a new Bytes instance with WebSocket message content.
+//
+// Example:
+//
+//	msg := conn.Expect()
+//	msg.BodyBytes().HasPrefix([]byte{0xCA, 0xFE})
+func (m *WebsocketMessage) BodyBytes() *Bytes {
+	m.chain.enter("BodyBytes()")
+	defer m.chain.leave()
+
+	if m.chain.failed() {
+		return newBytes(m.chain, nil)
+	}
+
+	return newBytes(m.chain, m.content)
+}
+
+// FragmentCount returns a new Number instance with number of frames
+// in which message was received.
+//
+// Fragments are reported only if WebSocket connection implements
+// WebsocketFragmentConn, or if frames are tracked on the wire (see
+// WebsocketFragmentConn); otherwise, every message has one fragment.
+//
+// Example:
+//
+//	msg := conn.Expect()
+//	msg.FragmentCount().Equal(3)
+func (m *WebsocketMessage) FragmentCount() *Number {
+	m.chain.enter("FragmentCount()")
+	defer m.chain.leave()
+
+	if m.chain.failed() {
+		return newNumber(m.chain, 0)
+	}
+
+	return newNumber(m.chain, float64(len(m.getFragments())))
+}
+
+// Fragment returns a new Bytes instance with payload of given fragment
+// (frame) of message. Index is zero-based.
+//
+// Fragment fails if index is out of range.
+//
+// Example:
+//
+//	msg := conn.Expect()
+//	msg.Fragment(0).Equal([]byte("hel"))
+//	msg.Fragment(1).Equal([]byte("lo"))
+func (m *WebsocketMessage) Fragment(index int) *Bytes {
+	m.chain.enter("Fragment(%d)", index)
+	defer m.chain.leave()
+
+	if m.chain.failed() {
+		return newBytes(m.chain, nil)
+	}
+
+	fragments := m.getFragments()
+
+	if index < 0 || index >= len(fragments) {
+		m.chain.fail(AssertionFailure{
+			Type:   AssertInRange,
+			Actual: &AssertionValue{index},
+			Expected: &AssertionValue{AssertionRange{
+				Min: 0,
+				Max: len(fragments) - 1,
+			}},
+			Errors: []error{
+				errors.New("expected: valid fragment index"),
+			},
+		})
+		return newBytes(m.chain, nil)
+	}
+
+	return newBytes(m.chain, fragments[index])
+}
+
+// Fragmented succeeds if message was received in more than one frame.
+//
+// Example:
+//
+//	msg := conn.Expect()
+//	msg.Fragmented()
+func (m *WebsocketMessage) Fragmented() *WebsocketMessage {
+	m.chain.enter("Fragmented()")
+	defer m.chain.leave()
+
+	if m.chain.failed() {
+		return m
+	}
+
+	if n := len(m.getFragments()); !(n > 1) {
+		m.chain.fail(AssertionFailure{
+			Type:     AssertGt,
+			Actual:   &AssertionValue{n},
+			Expected: &AssertionValue{1},
+			Errors: []error{
+				errors.New("expected: message is fragmented"),
+			},
+		})
+	}
+
+	return m
+}
+
+// NotFragmented succeeds if message was received in a single frame.
+//
+// Example:
+//
+//	msg := conn.Expect()
+//	msg.NotFragmented()
+func (m *WebsocketMessage) NotFragmented() *WebsocketMessage {
+	m.chain.enter("NotFragmented()")
+	defer m.chain.leave()
+
+	if m.chain.failed() {
+		return m
+	}
+
+	if n := len(m.getFragments()); n > 1 {
+		m.chain.fail(AssertionFailure{
+			Type:     AssertLe,
+			Actual:   &AssertionValue{n},
+			Expected: &AssertionValue{1},
+			Errors: []error{
+				errors.New("expected: message is not fragmented"),
+			},
+		})
+	}
+
+	return m
+}
+
+// Compressed succeeds if message was compressed on the wire using
+// permessage-deflate extension (RFC 7692).
+//
+// Compression of message is known only for connections established by
+// Request over unencrypted ("ws://") URL using *websocket.Dialer.
+// Otherwise, Compressed reports failure.
+//
+// Example:
+//
+//	ws := e.GET("/path").WithWebsocketUpgrade().
+//	    WithWebsocketCompression(true).
+//	    Expect().
+//	    Websocket()
+//	ws.Expect().Compressed()
+func (m *WebsocketMessage) Compressed() *WebsocketMessage {
+	m.chain.enter("Compressed()")
+	defer m.chain.leave()
+
+	m.checkCompressed(true)
+
+	return m
+}
+
+// NotCompressed succeeds if message was not compressed on the wire.
+//
+// Compression of message is known only for connections established by
+// Request over unencrypted ("ws://") URL using *websocket.Dialer.
+// Otherwise, NotCompressed reports failure.
+//
+// Example:
+//
+//	ws := e.GET("/path").WithWebsocketUpgrade().
+//	    WithWebsocketCompression(false).
+//	    Expect().
+//	    Websocket()
+//	ws.Expect().NotCompressed()
+func (m *WebsocketMessage) NotCompressed() *WebsocketMessage {
+	m.chain.enter("NotCompressed()")
+	defer m.chain.leave()
+
+	m.checkCompressed(false)
+
+	return m
+}
+
+// WireSize returns a new Number instance with size of message payload
+// as it was sent on the wire, i.e. after compression, in bytes.
+// Frame headers are not included.
+//
+// Like Compressed, WireSize requires connection established by Request
+// over unencrypted URL; otherwise, failure is reported.
+//
+// Example:
+//
+//	msg := ws.Expect()
+//	msg.WireSize().Lt(1024)
+func (m *WebsocketMessage) WireSize() *Number {
+	m.chain.enter("WireSize()")
+	defer m.chain.leave()
+
+	if m.chain.failed() || !m.checkWire() {
+		return newNumber(m.chain, 0)
+	}
+
+	return newNumber(m.chain, float64(m.wire.size))
+}
+
+func (m *WebsocketMessage) checkCompressed(compressed bool) {
+	if m.chain.failed() || !m.checkWire() {
+		return
+	}
+
+	if m.wire.compressed != compressed {
+		var err error
+		if compressed {
+			err = errors.New("expected: message is compressed on the wire")
+		} else {
+			err = errors.New("expected: message is not compressed on the wire")
+		}
+
+		m.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{m.wire.compressed},
+			Expected: &AssertionValue{compressed},
+			Errors:   []error{err},
+		})
+	}
+}
+
+func (m *WebsocketMessage) checkWire() bool {
+	if m.wire != nil {
+		return true
+	}
+
+	m.chain.fail(AssertionFailure{
+		Type: AssertUsage,
+		Errors: []error{
+			errors.New("wire format of websocket message is unknown"),
+			errors.New(
+				"it is known only for messages received over unencrypted" +
+					" connection established by Request using *websocket.Dialer"),
+		},
+	})
+
+	return false
+}
+
+func (m *WebsocketMessage) getFragments() [][]byte {
+	if m.fragments == nil {
+		return [][]byte{m.content}
+	}
+	return m.fragments
+}
+
 // NoContent succeeds if WebSocket message has no content (is empty).
 func (m *WebsocketMessage) NoContent() *WebsocketMessage {
 	m.chain.enter("NoContent()")
@@ -451,6 +704,42 @@ func (m *WebsocketMessage) JSON() *Value {
 		return newValue(m.chain, nil)
 	}
 
+	value, ok := m.decodeJSON()
+	if !ok {
+		return newValue(m.chain, nil)
+	}
+
+	return newValue(m.chain, value)
+}
+
+// JSONSchema succeeds if JSON contents of WebSocket message matches given
+// JSON Schema. It's a shorthand for JSON().Schema(schema).
+//
+// See Value.Schema for supported schema formats.
+//
+// Example:
+//
+//	msg := conn.Expect()
+//	msg.JSONSchema(`{"type": "object", "required": ["event"]}`)
+func (m *WebsocketMessage) JSONSchema(schema interface{}) *WebsocketMessage {
+	m.chain.enter("JSONSchema()")
+	defer m.chain.leave()
+
+	if m.chain.failed() {
+		return m
+	}
+
+	value, ok := m.decodeJSON()
+	if !ok {
+		return m
+	}
+
+	jsonSchema(m.chain, value, schema)
+
+	return m
+}
+
+func (m *WebsocketMessage) decodeJSON() (interface{}, bool) {
 	var value interface{}
 
 	if err := json.Unmarshal(m.content, &value); err != nil {
@@ -464,8 +753,8 @@ func (m *WebsocketMessage) JSON() *Value {
 				err,
 			},
 		})
-		return newValue(m.chain, nil)
+		return nil, false
 	}
 
-	return newValue(m.chain, value)
+	return value, true
 }
@@ -26,8 +26,18 @@ func TestWebsocketMessageFailed(t *testing.T) {
 	msg.NotCode(0)
 	msg.NoContent()
 
+	msg.Fragmented()
+	msg.NotFragmented()
+	msg.Compressed()
+	msg.NotCompressed()
+
 	msg.Body().chain.assertFailed(t)
+	msg.BodyBytes().chain.assertFailed(t)
+	msg.FragmentCount().chain.assertFailed(t)
+	msg.Fragment(0).chain.assertFailed(t)
+	msg.WireSize().chain.assertFailed(t)
 	msg.JSON().chain.assertFailed(t)
+	msg.JSONSchema(`{"type": "object"}`).chain.assertFailed(t)
 }
 
 func TestWebsocketMessageBadUsage(t *testing.T) {
@@ -353,6 +363,54 @@ func TestWebsocketMessageBody(t *testing.T) {
 	require.Equal(t, "test", s.Raw())
 }
 
+func TestWebsocketMessageBodyBytes(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	body := []byte{0xCA, 0xFE, 0x00}
+
+	msg := NewWebsocketMessage(reporter, websocket.BinaryMessage, body)
+
+	b := msg.BodyBytes()
+	b.chain.assertOK(t)
+
+	require.Equal(t, body, b.Raw())
+}
+
+func TestWebsocketMessageFragments(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	t.Run("single", func(t *testing.T) {
+		msg := NewWebsocketMessage(reporter, websocket.TextMessage, []byte("hello"))
+
+		msg.FragmentCount().Equal(1).chain.assertOK(t)
+		msg.Fragment(0).Equal([]byte("hello")).chain.assertOK(t)
+
+		msg.NotFragmented().chain.assertOK(t)
+		msg.Fragmented().chain.assertFailed(t)
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		msg := NewWebsocketMessage(reporter, websocket.TextMessage, []byte("hello"))
+		msg.fragments = [][]byte{[]byte("hel"), []byte("lo")}
+
+		msg.FragmentCount().Equal(2).chain.assertOK(t)
+		msg.Fragment(0).Equal([]byte("hel")).chain.assertOK(t)
+		msg.Fragment(1).Equal([]byte("lo")).chain.assertOK(t)
+
+		msg.Fragmented().chain.assertOK(t)
+		msg.NotFragmented().chain.assertFailed(t)
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		for _, index := range []int{-1, 1} {
+			msg := NewWebsocketMessage(reporter, websocket.TextMessage, []byte("a"))
+
+			msg.Fragment(index).chain.assertFailed(t)
+			msg.chain.assertFailed(t)
+		}
+	})
+}
+
 func TestWebsocketMessageJSON(t *testing.T) {
 	reporter := newMockReporter(t)
 
@@ -378,3 +436,87 @@ func TestWebsocketMessageJSON(t *testing.T) {
 		msg.chain.assertFailed(t)
 	})
 }
+
+func TestWebsocketMessageJSONSchema(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	schema := `{
+		"type": "object",
+		"properties": {"event": {"type": "string"}},
+		"required": ["event"]
+	}`
+
+	t.Run("match", func(t *testing.T) {
+		msg := NewWebsocketMessage(reporter, websocket.TextMessage,
+			[]byte(`{"event": "ping"}`))
+
+		msg.JSONSchema(schema)
+		msg.chain.assertOK(t)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		msg := NewWebsocketMessage(reporter, websocket.TextMessage,
+			[]byte(`{"event": 123}`))
+
+		msg.JSONSchema(schema)
+		msg.chain.assertFailed(t)
+	})
+
+	t.Run("bad json", func(t *testing.T) {
+		msg := NewWebsocketMessage(reporter, websocket.TextMessage,
+			[]byte(`{`))
+
+		msg.JSONSchema(schema)
+		msg.chain.assertFailed(t)
+	})
+}
+
+func TestWebsocketMessageCompressed(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	t.Run("compressed", func(t *testing.T) {
+		msg := NewWebsocketMessage(reporter, websocket.TextMessage, []byte("test"))
+		msg.wire = &websocketWireMessage{compressed: true, size: 3}
+
+		msg.Compressed()
+		msg.chain.assertOK(t)
+		msg.chain.reset()
+
+		msg.NotCompressed()
+		msg.chain.assertFailed(t)
+		msg.chain.reset()
+
+		msg.WireSize().Equal(3)
+		msg.chain.assertOK(t)
+	})
+
+	t.Run("not compressed", func(t *testing.T) {
+		msg := NewWebsocketMessage(reporter, websocket.TextMessage, []byte("test"))
+		msg.wire = &websocketWireMessage{compressed: false, size: 4}
+
+		msg.Compressed()
+		msg.chain.assertFailed(t)
+		msg.chain.reset()
+
+		msg.NotCompressed()
+		msg.chain.assertOK(t)
+		msg.chain.reset()
+
+		msg.WireSize().Equal(4)
+		msg.chain.assertOK(t)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		msg := NewWebsocketMessage(reporter, websocket.TextMessage, []byte("test"))
+
+		msg.Compressed()
+		msg.chain.assertFailed(t)
+		msg.chain.reset()
+
+		msg.NotCompressed()
+		msg.chain.assertFailed(t)
+		msg.chain.reset()
+
+		msg.WireSize().chain.assertFailed(t)
+	})
+}
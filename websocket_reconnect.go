@@ -0,0 +1,275 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/websocket"
+	"github.com/yalp/jsonpath"
+)
+
+// WebsocketReconnectOpts defines how Websocket.CloseAndReconnect drops
+// and re-establishes connection.
+//
+// If neither ResumeToken nor ResumeTokenPath is set, connection is
+// re-established with exactly the same request.
+type WebsocketReconnectOpts struct {
+	// CloseCode is close code sent to server before dropping connection.
+	// If zero, connection is dropped without close message, like on
+	// network failure.
+	CloseCode int
+
+	// ResumeToken is token passed to server in reconnect request.
+	ResumeToken string
+
+	// ResumeTokenPath is JSONPath of resume token in JSON messages received
+	// from server, e.g. "$.session.resume_token". Last received message
+	// with non-empty string or number at given path is used.
+	// Ignored if ResumeToken is set.
+	ResumeTokenPath string
+
+	// ResumeParam is the name of query parameter used to pass resume token.
+	// If both ResumeParam and ResumeHeader are empty, "resume_token" is used.
+	ResumeParam string
+
+	// ResumeHeader is the name of header used to pass resume token.
+	ResumeHeader string
+}
+
+// parameters of request that established connection, used to dial it again
+type websocketDial struct {
+	url    *url.URL
+	header http.Header
+	dial   func(url string, header http.Header) (
+		WebsocketConn, *http.Response, *websocketWire, error)
+}
+
+func newWebsocketDial(
+	dialer WebsocketDialer, u *url.URL, header http.Header,
+) *websocketDial {
+	dialURL := *u
+
+	return &websocketDial{
+		url:    &dialURL,
+		header: header.Clone(),
+		dial: func(u string, h http.Header) (
+			WebsocketConn, *http.Response, *websocketWire, error,
+		) {
+			conn, resp, wire, err := dialWebsocketWire(dialer, u, h)
+			if conn == nil {
+				return nil, resp, nil, err
+			}
+			return conn, resp, wire, err
+		},
+	}
+}
+
+// CloseAndReconnect drops WebSocket connection and establishes a new one
+// using the same request, so that reconnection and replay behavior of
+// server can be tested.
+//
+// Optionally, resume token may be passed to server in reconnect request,
+// either given explicitly or extracted from a message received before.
+// See WebsocketReconnectOpts for details.
+//
+// Messages received before reconnect but not consumed yet are discarded.
+// Transcript is preserved and continues with messages of new connection.
+//
+// CloseAndReconnect requires Websocket created by Response.Websocket.
+//
+// Example:
+//
+//	ws := e.GET("/events").WithWebsocketUpgrade().
+//	    Expect().
+//	    Websocket()
+//	defer ws.Disconnect()
+//
+//	ws.Expect().JSON().Object().ValueEqual("seq", 1)
+//
+//	ws.CloseAndReconnect(httpexpect.WebsocketReconnectOpts{
+//	    ResumeTokenPath: "$.resume_token",
+//	    ResumeParam:     "resume",
+//	})
+//
+//	// server replays events after resume token
+//	ws.Expect().JSON().Object().ValueEqual("seq", 2)
+func (c *Websocket) CloseAndReconnect(opts ...WebsocketReconnectOpts) *Websocket {
+	c.chain.enter("CloseAndReconnect()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return c
+	}
+
+	if len(opts) > 1 {
+		c.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple opts arguments"),
+			},
+		})
+		return c
+	}
+
+	var o WebsocketReconnectOpts
+	if len(opts) == 1 {
+		o = opts[0]
+	}
+
+	if c.conn == nil || c.dial == nil {
+		c.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New(
+					"CloseAndReconnect() requires Websocket created by Response.Websocket()"),
+			},
+		})
+		return c
+	}
+
+	token, ok := c.resumeToken(o)
+	if !ok {
+		return c
+	}
+
+	c.dropConn(o.CloseCode)
+
+	dialURL := *c.dial.url
+	header := c.dial.header.Clone()
+
+	if token != "" && o.ResumeHeader != "" {
+		header.Set(o.ResumeHeader, token)
+	}
+
+	if token != "" && (o.ResumeParam != "" || o.ResumeHeader == "") {
+		param := o.ResumeParam
+		if param == "" {
+			param = "resume_token"
+		}
+		q := dialURL.Query()
+		q.Set(param, token)
+		dialURL.RawQuery = q.Encode()
+	}
+
+	conn, resp, wire, err := c.dial.dial(dialURL.String(), header)
+
+	if err != nil {
+		c.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to reconnect websocket"),
+				err,
+			},
+		})
+		return c
+	}
+
+	if conn == nil {
+		c.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to upgrade connection to websocket on reconnect"),
+			},
+		})
+		return c
+	}
+
+	c.conn = conn
+	c.wire = wire
+	c.isClosed = false
+
+	if resp != nil {
+		c.handshake = resp.Header
+	} else {
+		c.handshake = nil
+	}
+
+	c.control = nil
+	if cc, ok := conn.(WebsocketControlConn); ok {
+		c.control = newWebsocketControl(cc)
+	}
+
+	return c
+}
+
+// closes connection and forgets its state
+func (c *Websocket) dropConn(closeCode int) {
+	if !c.isClosed {
+		if closeCode != 0 {
+			c.printWrite(websocket.CloseMessage, nil, closeCode)
+
+			// error is ignored, since connection is dropped anyway
+			_ = c.conn.WriteMessage(websocket.CloseMessage,
+				websocket.FormatCloseMessage(closeCode, ""))
+		}
+
+		_ = c.conn.Close()
+	}
+
+	c.reads = nil
+	c.pending = nil
+	c.lastPing = nil
+}
+
+func (c *Websocket) resumeToken(opts WebsocketReconnectOpts) (string, bool) {
+	if opts.ResumeToken != "" || opts.ResumeTokenPath == "" {
+		return opts.ResumeToken, true
+	}
+
+	filter, err := jsonpath.Prepare(opts.ResumeTokenPath)
+	if err != nil {
+		c.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{opts.ResumeTokenPath},
+			Errors: []error{
+				errors.New("expected: valid json path"),
+				err,
+			},
+		})
+		return "", false
+	}
+
+	entries := c.transcript.entries
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.sent ||
+			(e.typ != websocket.TextMessage && e.typ != websocket.BinaryMessage) {
+			continue
+		}
+
+		var value interface{}
+		if err := json.Unmarshal(e.content, &value); err != nil {
+			continue
+		}
+
+		token, err := filter(value)
+		if err != nil {
+			continue
+		}
+
+		switch t := token.(type) {
+		case string:
+			if t != "" {
+				return t, true
+			}
+		case float64:
+			return strconv.FormatFloat(t, 'f', -1, 64), true
+		}
+	}
+
+	c.chain.fail(AssertionFailure{
+		Type:   AssertValid,
+		Actual: &AssertionValue{opts.ResumeTokenPath},
+		Errors: []error{
+			fmt.Errorf("expected: resume token at %q in received messages",
+				opts.ResumeTokenPath),
+		},
+	})
+
+	return "", false
+}
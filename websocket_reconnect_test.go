@@ -0,0 +1,234 @@
+package httpexpect
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWebsocketDialer struct {
+	conns  []WebsocketConn
+	err    error
+	urls   []string
+	header []http.Header
+}
+
+func (d *mockWebsocketDialer) dial() *websocketDial {
+	u, _ := url.Parse("ws://example.com/path?a=b")
+
+	return &websocketDial{
+		url:    u,
+		header: http.Header{"X-Test": {"test"}},
+		dial: func(u string, h http.Header) (
+			WebsocketConn, *http.Response, *websocketWire, error,
+		) {
+			d.urls = append(d.urls, u)
+			d.header = append(d.header, h)
+			if d.err != nil {
+				return nil, nil, nil, d.err
+			}
+			conn := d.conns[0]
+			d.conns = d.conns[1:]
+			return conn, &http.Response{
+				Header: http.Header{"X-Conn": {"new"}},
+			}, nil, nil
+		},
+	}
+}
+
+func TestWebsocketReconnectFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	ws := newWebsocket(chain, Config{}, nil)
+
+	ws.CloseAndReconnect()
+	ws.chain.assertFailed(t)
+}
+
+func TestWebsocketReconnectNoDial(t *testing.T) {
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, &mockWebsocketConn{})
+
+	ws.CloseAndReconnect()
+	ws.chain.assertFailed(t)
+}
+
+func TestWebsocketReconnect(t *testing.T) {
+	oldConn := &mockWebsocketQueueConn{
+		messages: [][]byte{
+			[]byte(`{"token":"t1"}`),
+			[]byte(`{"token":"t2"}`),
+			[]byte(`not json`),
+			[]byte(`unread`),
+		},
+	}
+	newConn := &mockWebsocketQueueConn{
+		messages: [][]byte{
+			[]byte(`new`),
+		},
+	}
+
+	dialer := &mockWebsocketDialer{
+		conns: []WebsocketConn{newConn},
+	}
+
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, oldConn)
+	ws.dial = dialer.dial()
+
+	ws.Expect()
+	ws.Expect()
+	ws.Expect()
+
+	ws.CloseAndReconnect(WebsocketReconnectOpts{
+		ResumeTokenPath: "$.token",
+	})
+	ws.chain.assertOK(t)
+
+	assert.True(t, oldConn.closed)
+
+	require.Equal(t, 1, len(dialer.urls))
+	assert.Equal(t, "ws://example.com/path?a=b&resume_token=t2", dialer.urls[0])
+	assert.Equal(t, "test", dialer.header[0].Get("X-Test"))
+
+	ws.Expect().Body().Equal("new")
+	ws.Header("X-Conn").Equal("new")
+	ws.chain.assertOK(t)
+}
+
+func TestWebsocketReconnectOpts(t *testing.T) {
+	cases := []struct {
+		name   string
+		opts   WebsocketReconnectOpts
+		url    string
+		header string
+	}{
+		{
+			name: "no token",
+			opts: WebsocketReconnectOpts{},
+			url:  "ws://example.com/path?a=b",
+		},
+		{
+			name: "token in param",
+			opts: WebsocketReconnectOpts{
+				ResumeToken: "tok",
+				ResumeParam: "resume",
+			},
+			url: "ws://example.com/path?a=b&resume=tok",
+		},
+		{
+			name: "token in header",
+			opts: WebsocketReconnectOpts{
+				ResumeToken:  "tok",
+				ResumeHeader: "X-Resume",
+			},
+			url:    "ws://example.com/path?a=b",
+			header: "tok",
+		},
+		{
+			name: "token in param and header",
+			opts: WebsocketReconnectOpts{
+				ResumeToken:  "tok",
+				ResumeParam:  "resume",
+				ResumeHeader: "X-Resume",
+			},
+			url:    "ws://example.com/path?a=b&resume=tok",
+			header: "tok",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dialer := &mockWebsocketDialer{
+				conns: []WebsocketConn{&mockWebsocketConn{}},
+			}
+
+			ws := NewWebsocket(Config{
+				Reporter: newMockReporter(t),
+			}, &mockWebsocketConn{})
+			ws.dial = dialer.dial()
+
+			ws.CloseAndReconnect(tc.opts)
+			ws.chain.assertOK(t)
+
+			require.Equal(t, 1, len(dialer.urls))
+			assert.Equal(t, tc.url, dialer.urls[0])
+			assert.Equal(t, tc.header, dialer.header[0].Get("X-Resume"))
+		})
+	}
+}
+
+func TestWebsocketReconnectCloseCode(t *testing.T) {
+	oldConn := newMockWebsocketChanConn(1)
+	close(oldConn.in)
+
+	dialer := &mockWebsocketDialer{
+		conns: []WebsocketConn{&mockWebsocketConn{}},
+	}
+
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, oldConn)
+	ws.dial = dialer.dial()
+
+	ws.CloseAndReconnect(WebsocketReconnectOpts{
+		CloseCode: websocket.CloseGoingAway,
+	})
+	ws.chain.assertOK(t)
+
+	assert.Equal(t,
+		websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), <-oldConn.out)
+
+	ws.Transcript().Element(0).Object().
+		ValueEqual("type", "close").
+		ValueEqual("code", websocket.CloseGoingAway)
+}
+
+func TestWebsocketReconnectErrors(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	t.Run("multiple opts", func(t *testing.T) {
+		ws := NewWebsocket(config, &mockWebsocketConn{})
+		ws.dial = (&mockWebsocketDialer{}).dial()
+
+		ws.CloseAndReconnect(WebsocketReconnectOpts{}, WebsocketReconnectOpts{})
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("dial error", func(t *testing.T) {
+		ws := NewWebsocket(config, &mockWebsocketConn{})
+		ws.dial = (&mockWebsocketDialer{err: errors.New("test")}).dial()
+
+		ws.CloseAndReconnect()
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("bad token path", func(t *testing.T) {
+		ws := NewWebsocket(config, &mockWebsocketConn{})
+		ws.dial = (&mockWebsocketDialer{}).dial()
+
+		ws.CloseAndReconnect(WebsocketReconnectOpts{ResumeTokenPath: "$["})
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("token not found", func(t *testing.T) {
+		ws := NewWebsocket(config, &mockWebsocketQueueConn{
+			messages: [][]byte{[]byte(`{"token":""}`)},
+		})
+		ws.dial = (&mockWebsocketDialer{}).dial()
+
+		ws.Expect()
+		ws.CloseAndReconnect(WebsocketReconnectOpts{ResumeTokenPath: "$.token"})
+		ws.chain.assertFailed(t)
+	})
+}
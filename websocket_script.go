@@ -0,0 +1,408 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// timeout for WebsocketScript expectations, used if read timeout
+// of Websocket is not set
+const defaultScriptTimeout = 10 * time.Second
+
+// maximum number of writes queued by WebsocketScript
+const maxScriptWrites = 1024
+
+// WebsocketScript is passed to function given to Websocket.Script and
+// provides methods to write and read messages concurrently.
+//
+// Writes are queued and performed by a separate goroutine in order, so
+// they never wait for reads. Reads are performed by another goroutine
+// in background, and Expect returns received messages in order.
+// All assertions are made in the goroutine that invoked Script.
+type WebsocketScript struct {
+	ws      *Websocket
+	chain   *chain
+	timeout time.Duration
+
+	writes  chan websocketWrite
+	pending int
+	results chan error
+	done    chan struct{}
+	err     error
+}
+
+type websocketWrite struct {
+	typ     int
+	content []byte
+}
+
+// Script invokes given function with a new WebsocketScript instance,
+// which can be used to test full-duplex protocols, when client sends
+// messages while receiving pushes from server.
+//
+// Inside function, messages are sent using Send and friends, which don't
+// block, and received messages are checked using Expect, which returns
+// messages in order. Script returns after function returns and all
+// queued messages are written.
+//
+// If Expect doesn't receive a message within read timeout (see
+// WithReadTimeout; 10 seconds if not set), failure is reported with
+// description of likely deadlock: whether writes are blocked because
+// peer doesn't read, or all writes are done and peer doesn't respond.
+//
+// Messages not consumed by Expect are not lost and are returned by
+// subsequent Websocket.Expect calls. Websocket itself should not be used
+// inside function.
+//
+// Example:
+//
+//	ws := resp.Websocket()
+//	ws.Script(func(s *httpexpect.WebsocketScript) {
+//	    s.SendText("subscribe:orders")
+//	    s.SendText("subscribe:trades")
+//
+//	    s.Expect().Body().Equal("subscribed:orders")
+//	    s.Expect().Body().Equal("subscribed:trades")
+//	})
+func (c *Websocket) Script(fn func(s *WebsocketScript)) *Websocket {
+	c.chain.enter("Script()")
+	defer c.chain.leave()
+
+	if c.checkUnusable("Script()") {
+		return c
+	}
+
+	if fn == nil {
+		c.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected nil argument"),
+			},
+		})
+		return c
+	}
+
+	if err := c.conn.SetReadDeadline(infiniteTime); err != nil {
+		c.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to set read deadline for websocket"),
+				err,
+			},
+		})
+		return c
+	}
+
+	s := newWebsocketScript(c)
+
+	go s.writeLoop()
+
+	c.startRead()
+
+	fn(s)
+
+	s.finish()
+
+	return c
+}
+
+func newWebsocketScript(ws *Websocket) *WebsocketScript {
+	timeout := ws.readTimeout
+	if timeout == noDuration {
+		timeout = defaultScriptTimeout
+	}
+
+	return &WebsocketScript{
+		ws:      ws,
+		chain:   ws.chain,
+		timeout: timeout,
+		writes:  make(chan websocketWrite, maxScriptWrites),
+		results: make(chan error, maxScriptWrites),
+		done:    make(chan struct{}),
+	}
+}
+
+// Send queues message of given type with given content for writing.
+// Only text and binary messages are allowed.
+//
+// Send doesn't wait until message is written. Write errors are reported
+// by subsequent calls and when script ends.
+//
+// Example:
+//
+//	s.Send(websocket.BinaryMessage, []byte{0x01})
+func (s *WebsocketScript) Send(typ int, content []byte) *WebsocketScript {
+	s.chain.enter("Send()")
+	defer s.chain.leave()
+
+	s.send(typ, content)
+
+	return s
+}
+
+// SendText is a shorthand for s.Send(websocket.TextMessage, []byte(str)).
+func (s *WebsocketScript) SendText(str string) *WebsocketScript {
+	s.chain.enter("SendText()")
+	defer s.chain.leave()
+
+	s.send(websocket.TextMessage, []byte(str))
+
+	return s
+}
+
+// SendBytes is a shorthand for s.Send(websocket.BinaryMessage, b).
+func (s *WebsocketScript) SendBytes(b []byte) *WebsocketScript {
+	s.chain.enter("SendBytes()")
+	defer s.chain.leave()
+
+	s.send(websocket.BinaryMessage, b)
+
+	return s
+}
+
+// SendJSON queues text message with given object marshaled using
+// json.Marshal.
+//
+// Example:
+//
+//	s.SendJSON(map[string]interface{}{"op": "subscribe"})
+func (s *WebsocketScript) SendJSON(object interface{}) *WebsocketScript {
+	s.chain.enter("SendJSON()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	b, err := json.Marshal(object)
+	if err != nil {
+		s.chain.fail(AssertionFailure{
+			Type: AssertValid,
+			Errors: []error{
+				errors.New("invalid json object"),
+				err,
+			},
+		})
+		return s
+	}
+
+	s.send(websocket.TextMessage, b)
+
+	return s
+}
+
+// Flush waits until all queued messages are written.
+//
+// Example:
+//
+//	s.SendText("bye").Flush()
+func (s *WebsocketScript) Flush() *WebsocketScript {
+	s.chain.enter("Flush()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	for s.pending != 0 && s.err == nil {
+		s.collect(<-s.results)
+	}
+
+	s.checkWriteError()
+
+	return s
+}
+
+// Expect returns next message received from connection.
+//
+// If no message is received within timeout, failure is reported.
+//
+// Example:
+//
+//	s.SendText("ping")
+//	s.Expect().TextMessage().Body().Equal("pong")
+func (s *WebsocketScript) Expect() *WebsocketMessage {
+	s.chain.enter("Expect()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newWebsocketMessage(s.chain)
+	}
+
+	ws := s.ws
+
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+
+	for len(ws.pending) == 0 {
+		ws.startRead()
+
+		select {
+		case r := <-ws.reads:
+			ws.pending = append(ws.pending, r)
+			ws.reads = nil
+
+		case err := <-s.results:
+			s.collect(err)
+			if !s.checkWriteError() {
+				return newWebsocketMessage(s.chain)
+			}
+
+		case <-timer.C:
+			s.failDeadlock()
+			return newWebsocketMessage(s.chain)
+		}
+	}
+
+	m, err := ws.receiveMessage()
+	if err != nil {
+		s.chain.fail(AssertionFailure{
+			Type: AssertOperation,
+			Errors: []error{
+				errors.New("failed to read from websocket"),
+				err,
+			},
+		})
+		return newWebsocketMessage(s.chain)
+	}
+
+	if m.typ != websocket.CloseMessage {
+		ws.startRead()
+	}
+
+	return m
+}
+
+func (s *WebsocketScript) send(typ int, content []byte) {
+	if s.chain.failed() {
+		return
+	}
+
+	if typ != websocket.TextMessage && typ != websocket.BinaryMessage {
+		s.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				fmt.Errorf("unexpected websocket message type %s",
+					wsMessageType(typ)),
+			},
+		})
+		return
+	}
+
+	s.drain()
+
+	if !s.checkWriteError() {
+		return
+	}
+
+	if s.pending >= maxScriptWrites {
+		s.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				fmt.Errorf("unexpected more than %d unwritten messages",
+					maxScriptWrites),
+			},
+		})
+		return
+	}
+
+	s.ws.printWrite(typ, content, 0)
+
+	s.pending++
+	s.writes <- websocketWrite{typ, content}
+}
+
+func (s *WebsocketScript) writeLoop() {
+	defer close(s.done)
+
+	for w := range s.writes {
+		err := s.writeOne(w)
+		s.results <- err
+	}
+}
+
+func (s *WebsocketScript) writeOne(w websocketWrite) error {
+	deadline := infiniteTime
+	if s.ws.writeTimeout != noDuration {
+		deadline = time.Now().Add(s.ws.writeTimeout)
+	}
+
+	if err := s.ws.conn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+
+	return s.ws.conn.WriteMessage(w.typ, w.content)
+}
+
+// collects results of completed writes without waiting
+func (s *WebsocketScript) drain() {
+	for {
+		select {
+		case err := <-s.results:
+			s.collect(err)
+		default:
+			return
+		}
+	}
+}
+
+func (s *WebsocketScript) collect(err error) {
+	s.pending--
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *WebsocketScript) checkWriteError() bool {
+	if s.err == nil {
+		return true
+	}
+
+	s.chain.fail(AssertionFailure{
+		Type: AssertOperation,
+		Errors: []error{
+			errors.New("failed to write to websocket"),
+			s.err,
+		},
+	})
+
+	return false
+}
+
+func (s *WebsocketScript) failDeadlock() {
+	var hint error
+	if s.pending != 0 {
+		hint = fmt.Errorf(
+			"%d message(s) are not written yet, peer might not be reading",
+			s.pending)
+	} else {
+		hint = errors.New(
+			"all messages are written, peer might be waiting for another message")
+	}
+
+	s.chain.fail(AssertionFailure{
+		Type: AssertOperation,
+		Errors: []error{
+			fmt.Errorf("possible deadlock: no message received within %s",
+				s.timeout),
+			hint,
+		},
+	})
+}
+
+func (s *WebsocketScript) finish() {
+	close(s.writes)
+
+	if !s.chain.failed() {
+		for s.pending != 0 {
+			s.collect(<-s.results)
+		}
+		s.checkWriteError()
+	}
+
+	<-s.done
+}
@@ -0,0 +1,209 @@
+package httpexpect
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebsocketScriptFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	ws := newWebsocket(chain, Config{}, nil)
+
+	called := false
+	ws.Script(func(s *WebsocketScript) {
+		called = true
+	})
+
+	assert.False(t, called)
+	ws.chain.assertFailed(t)
+}
+
+func TestWebsocketScriptNilFunc(t *testing.T) {
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, newMockWebsocketChanConn(1))
+
+	ws.Script(nil)
+	ws.chain.assertFailed(t)
+}
+
+func TestWebsocketScriptDuplex(t *testing.T) {
+	conn := newMockWebsocketChanConn(16)
+
+	// server pushes message before client sends anything
+	conn.in <- []byte("hello")
+
+	// server replies to every message
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for msg := range conn.out {
+			conn.in <- append([]byte("re:"), msg...)
+		}
+	}()
+	defer func() {
+		close(conn.out)
+		<-done
+		close(conn.in)
+	}()
+
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, conn)
+
+	ws.Script(func(s *WebsocketScript) {
+		s.SendText("a")
+		s.SendBytes([]byte("b"))
+		s.SendJSON(map[string]interface{}{"c": 1})
+
+		s.Expect().Body().Equal("hello")
+		s.Expect().Body().Equal("re:a")
+		s.Expect().Body().Equal("re:b")
+		s.Expect().JSON().Object().ValueEqual("c", 1)
+
+		s.SendText("d").Flush()
+	})
+
+	ws.chain.assertOK(t)
+
+	// message not consumed by script is available after it
+	ws.Expect().Body().Equal("re:d")
+	ws.chain.assertOK(t)
+}
+
+func TestWebsocketScriptClose(t *testing.T) {
+	conn := newMockWebsocketChanConn(1)
+	close(conn.in)
+
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, conn)
+
+	ws.Script(func(s *WebsocketScript) {
+		s.Expect().CloseMessage().Code(websocket.CloseNormalClosure)
+	})
+
+	ws.chain.assertOK(t)
+}
+
+func TestWebsocketScriptDeadlock(t *testing.T) {
+	t.Run("peer not responding", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		conn := newMockWebsocketChanConn(16)
+		defer close(conn.in)
+
+		ws := NewWebsocket(Config{
+			AssertionHandler: handler,
+		}, conn).WithReadTimeout(20 * time.Millisecond)
+
+		ws.Script(func(s *WebsocketScript) {
+			s.SendText("a").Flush()
+			s.Expect().chain.assertFailed(t)
+		})
+
+		ws.chain.assertFailed(t)
+
+		require.NotNil(t, handler.failure)
+		require.Equal(t, 2, len(handler.failure.Errors))
+		assert.True(t, strings.Contains(handler.failure.Errors[1].Error(),
+			"all messages are written"))
+	})
+
+	t.Run("peer not reading", func(t *testing.T) {
+		handler := &mockAssertionHandler{}
+
+		conn := newMockWebsocketChanConn(0)
+		defer close(conn.in)
+
+		ws := NewWebsocket(Config{
+			AssertionHandler: handler,
+		}, conn).WithReadTimeout(20 * time.Millisecond)
+
+		ws.Script(func(s *WebsocketScript) {
+			s.SendText("a")
+			s.Expect().chain.assertFailed(t)
+
+			// unblock writer
+			<-conn.out
+		})
+
+		ws.chain.assertFailed(t)
+
+		require.NotNil(t, handler.failure)
+		require.Equal(t, 2, len(handler.failure.Errors))
+		assert.True(t, strings.Contains(handler.failure.Errors[1].Error(),
+			"not written yet"))
+	})
+}
+
+func TestWebsocketScriptErrors(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	t.Run("write error", func(t *testing.T) {
+		conn := newMockWebsocketChanConn(1)
+		defer close(conn.in)
+
+		conn.writeMsgErr = errors.New("test")
+
+		ws := NewWebsocket(config, conn)
+
+		ws.Script(func(s *WebsocketScript) {
+			s.SendText("a").Flush()
+			s.chain.assertFailed(t)
+		})
+
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("write error at end", func(t *testing.T) {
+		conn := newMockWebsocketChanConn(1)
+		defer close(conn.in)
+
+		conn.writeMsgErr = errors.New("test")
+
+		ws := NewWebsocket(config, conn)
+
+		ws.Script(func(s *WebsocketScript) {
+			s.SendText("a")
+		})
+
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("bad message type", func(t *testing.T) {
+		conn := newMockWebsocketChanConn(1)
+		defer close(conn.in)
+
+		ws := NewWebsocket(config, conn)
+
+		ws.Script(func(s *WebsocketScript) {
+			s.Send(websocket.CloseMessage, nil)
+		})
+
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("bad json", func(t *testing.T) {
+		conn := newMockWebsocketChanConn(1)
+		defer close(conn.in)
+
+		ws := NewWebsocket(config, conn)
+
+		ws.Script(func(s *WebsocketScript) {
+			s.SendJSON(func() {})
+		})
+
+		ws.chain.assertFailed(t)
+	})
+}
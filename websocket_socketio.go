@@ -0,0 +1,687 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// Engine.IO packet types
+const (
+	engineIOOpen    = '0'
+	engineIOClose   = '1'
+	engineIOPing    = '2'
+	engineIOPong    = '3'
+	engineIOMessage = '4'
+	engineIONoop    = '6'
+)
+
+// Socket.IO packet types
+const (
+	socketIOConnect      = 0
+	socketIODisconnect   = 1
+	socketIOEvent        = 2
+	socketIOAck          = 3
+	socketIOConnectError = 4
+)
+
+// SocketIO provides methods to exchange Socket.IO events over WebSocket
+// connection.
+//
+// It implements Socket.IO protocol v5 over Engine.IO v4: performs
+// handshake, replies to Engine.IO pings, encodes and decodes events and
+// acknowledgements. Binary events are not supported.
+type SocketIO struct {
+	chain *chain
+	ws    *Websocket
+
+	namespace string
+	sid       string
+	nextAckID int
+	pending   []socketIOPacket
+}
+
+type socketIOPacket struct {
+	typ       int
+	namespace string
+	id        int
+	data      interface{}
+}
+
+// SocketIO performs Socket.IO handshake over WebSocket connection and
+// returns a new SocketIO instance.
+//
+// Connection should be established to Socket.IO endpoint using WebSocket
+// transport, e.g. "/socket.io/?EIO=4&transport=websocket".
+//
+// Namespace may be optionally specified. If not, "/" is used.
+//
+// Example:
+//
+//	ws := e.GET("/socket.io/").
+//	    WithQuery("EIO", 4).WithQuery("transport", "websocket").
+//	    WithWebsocketUpgrade().
+//	    Expect().
+//	    Websocket()
+//	defer ws.Disconnect()
+//
+//	sio := ws.SocketIO("/chat")
+//	sio.Emit("join", "room1")
+//	sio.ExpectEvent("joined").Args().Equal([]interface{}{"room1"})
+func (c *Websocket) SocketIO(namespace ...string) *SocketIO {
+	c.chain.enter("SocketIO()")
+	defer c.chain.leave()
+
+	s := &SocketIO{
+		chain:     c.chain.clone(),
+		ws:        c,
+		namespace: "/",
+	}
+
+	if c.checkUnusable("SocketIO()") {
+		s.chain.setFailed()
+		return s
+	}
+
+	if len(namespace) > 1 {
+		s.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple namespace arguments"),
+			},
+		})
+		return s
+	}
+
+	if len(namespace) == 1 {
+		if !strings.HasPrefix(namespace[0], "/") {
+			s.chain.fail(AssertionFailure{
+				Type:   AssertUsage,
+				Actual: &AssertionValue{namespace[0]},
+				Errors: []error{
+					errors.New("unexpected namespace without leading slash"),
+				},
+			})
+			return s
+		}
+		s.namespace = namespace[0]
+	}
+
+	s.handshake()
+
+	return s
+}
+
+// SessionID returns a new String instance with session id assigned
+// by server during handshake.
+//
+// Example:
+//
+//	sio := ws.SocketIO()
+//	sio.SessionID().NotEmpty()
+func (s *SocketIO) SessionID() *String {
+	s.chain.enter("SessionID()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newString(s.chain, "")
+	}
+
+	return newString(s.chain, s.sid)
+}
+
+// Emit sends event with given name and arguments.
+//
+// Arguments are marshaled using json.Marshal.
+//
+// Example:
+//
+//	sio := ws.SocketIO()
+//	sio.Emit("message", map[string]interface{}{"text": "hi"})
+func (s *SocketIO) Emit(event string, args ...interface{}) *SocketIO {
+	s.chain.enter("Emit(%q)", event)
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	s.write(s.chain, socketIOPacket{
+		typ:       socketIOEvent,
+		namespace: s.namespace,
+		id:        -1,
+		data:      append([]interface{}{event}, args...),
+	})
+
+	return s
+}
+
+// EmitWithAck sends event with given name and arguments, requesting
+// acknowledgement, waits for it, and returns a new Array instance with
+// acknowledgement arguments.
+//
+// Events received while waiting are not lost and are returned by
+// subsequent ExpectEvent calls.
+//
+// Example:
+//
+//	sio := ws.SocketIO()
+//	sio.EmitWithAck("create", "room1").Equal([]interface{}{"ok"})
+func (s *SocketIO) EmitWithAck(event string, args ...interface{}) *Array {
+	s.chain.enter("EmitWithAck(%q)", event)
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newArray(s.chain, nil)
+	}
+
+	id := s.nextAckID
+	s.nextAckID++
+
+	if !s.write(s.chain, socketIOPacket{
+		typ:       socketIOEvent,
+		namespace: s.namespace,
+		id:        id,
+		data:      append([]interface{}{event}, args...),
+	}) {
+		return newArray(s.chain, nil)
+	}
+
+	for {
+		p, ok := s.read()
+		if !ok {
+			return newArray(s.chain, nil)
+		}
+
+		if p.typ == socketIOAck && p.id == id {
+			ackArgs, _ := p.data.([]interface{})
+			if ackArgs == nil {
+				ackArgs = []interface{}{}
+			}
+			return newArray(s.chain, ackArgs)
+		}
+
+		s.pending = append(s.pending, p)
+	}
+}
+
+// ExpectEvent reads next event from connection, checks that its name
+// is equal to given one, and returns a new SocketIOEvent instance.
+//
+// Example:
+//
+//	sio := ws.SocketIO()
+//	sio.ExpectEvent("welcome").Arg(0).String().Equal("hello")
+func (s *SocketIO) ExpectEvent(event string) *SocketIOEvent {
+	s.chain.enter("ExpectEvent(%q)", event)
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newSocketIOEvent(s, nil, "", nil, -1)
+	}
+
+	var p socketIOPacket
+
+	for {
+		if len(s.pending) != 0 {
+			p = s.pending[0]
+			s.pending = s.pending[1:]
+		} else {
+			var ok bool
+			if p, ok = s.read(); !ok {
+				return newSocketIOEvent(s, nil, "", nil, -1)
+			}
+		}
+
+		if p.typ == socketIOEvent {
+			break
+		}
+	}
+
+	data, _ := p.data.([]interface{})
+
+	name, ok := "", len(data) != 0
+	if ok {
+		name, ok = data[0].(string)
+	}
+	if !ok {
+		s.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{p.data},
+			Errors: []error{
+				errors.New("expected: event payload is array starting with name"),
+			},
+		})
+		return newSocketIOEvent(s, nil, "", nil, -1)
+	}
+
+	if name != event {
+		s.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{name},
+			Expected: &AssertionValue{event},
+			Errors: []error{
+				errors.New("expected: event names are equal"),
+			},
+		})
+		return newSocketIOEvent(s, nil, "", nil, -1)
+	}
+
+	return newSocketIOEvent(s, s.chain, name, data[1:], p.id)
+}
+
+// Disconnect sends Socket.IO disconnect packet for namespace.
+// It doesn't close WebSocket connection.
+//
+// Example:
+//
+//	sio := ws.SocketIO()
+//	sio.Disconnect()
+func (s *SocketIO) Disconnect() *SocketIO {
+	s.chain.enter("Disconnect()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	s.write(s.chain, socketIOPacket{
+		typ:       socketIODisconnect,
+		namespace: s.namespace,
+		id:        -1,
+	})
+
+	return s
+}
+
+func (s *SocketIO) handshake() {
+	msg, ok := s.readText()
+	if !ok {
+		return
+	}
+
+	if msg == "" || msg[0] != engineIOOpen {
+		s.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{msg},
+			Errors: []error{
+				errors.New("expected: Engine.IO open packet"),
+			},
+		})
+		return
+	}
+
+	var open struct {
+		SID string `json:"sid"`
+	}
+	if err := json.Unmarshal([]byte(msg[1:]), &open); err != nil {
+		s.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{msg},
+			Errors: []error{
+				errors.New("invalid Engine.IO open packet"),
+				err,
+			},
+		})
+		return
+	}
+
+	s.sid = open.SID
+
+	if !s.write(s.chain, socketIOPacket{
+		typ:       socketIOConnect,
+		namespace: s.namespace,
+		id:        -1,
+	}) {
+		return
+	}
+
+	for {
+		p, ok := s.read()
+		if !ok {
+			return
+		}
+
+		switch p.typ {
+		case socketIOConnect:
+			if obj, ok := p.data.(map[string]interface{}); ok {
+				if sid, ok := obj["sid"].(string); ok {
+					s.sid = sid
+				}
+			}
+			return
+
+		case socketIOConnectError:
+			s.chain.fail(AssertionFailure{
+				Type:   AssertOperation,
+				Actual: &AssertionValue{p.data},
+				Errors: []error{
+					fmt.Errorf("connection to namespace %q refused by Socket.IO server",
+						s.namespace),
+				},
+			})
+			return
+
+		default:
+			s.pending = append(s.pending, p)
+		}
+	}
+}
+
+// reads next Socket.IO packet for namespace, replying to Engine.IO pings
+func (s *SocketIO) read() (socketIOPacket, bool) {
+	for {
+		msg, ok := s.readText()
+		if !ok {
+			return socketIOPacket{}, false
+		}
+
+		switch {
+		case msg == "":
+			continue
+
+		case msg[0] == engineIOPing:
+			if !s.writeText(s.chain, string(engineIOPong)+msg[1:]) {
+				return socketIOPacket{}, false
+			}
+			continue
+
+		case msg[0] == engineIONoop || msg[0] == engineIOPong:
+			continue
+
+		case msg[0] == engineIOClose:
+			s.chain.fail(AssertionFailure{
+				Type: AssertOperation,
+				Errors: []error{
+					errors.New("connection closed by Engine.IO server"),
+				},
+			})
+			return socketIOPacket{}, false
+
+		case msg[0] != engineIOMessage:
+			continue
+		}
+
+		p, err := decodeSocketIOPacket(msg[1:])
+		if err != nil {
+			s.chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{msg},
+				Errors: []error{
+					errors.New("invalid Socket.IO packet"),
+					err,
+				},
+			})
+			return socketIOPacket{}, false
+		}
+
+		if p.namespace != s.namespace {
+			continue
+		}
+
+		if p.typ == socketIODisconnect {
+			s.chain.fail(AssertionFailure{
+				Type: AssertOperation,
+				Errors: []error{
+					fmt.Errorf("namespace %q disconnected by Socket.IO server",
+						s.namespace),
+				},
+			})
+			return socketIOPacket{}, false
+		}
+
+		return p, true
+	}
+}
+
+func (s *SocketIO) readText() (string, bool) {
+	var m *WebsocketMessage
+
+	s.ws.withChain(s.chain, func() {
+		if !s.ws.checkUnusable("SocketIO()") {
+			m = s.ws.readMessage()
+		}
+	})
+
+	if m == nil {
+		return "", false
+	}
+
+	if m.typ != websocket.TextMessage {
+		s.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{wsMessageType(m.typ)},
+			Expected: &AssertionValue{wsMessageType(websocket.TextMessage)},
+			Errors: []error{
+				errors.New("expected: Engine.IO text message"),
+			},
+		})
+		return "", false
+	}
+
+	return string(m.content), true
+}
+
+func (s *SocketIO) write(chain *chain, p socketIOPacket) bool {
+	msg, err := encodeSocketIOPacket(p)
+	if err != nil {
+		chain.fail(AssertionFailure{
+			Type: AssertValid,
+			Errors: []error{
+				errors.New("invalid json object"),
+				err,
+			},
+		})
+		return false
+	}
+
+	return s.writeText(chain, string(engineIOMessage)+msg)
+}
+
+func (s *SocketIO) writeText(chain *chain, msg string) bool {
+	s.ws.withChain(chain, func() {
+		if !s.ws.checkUnusable("SocketIO()") {
+			s.ws.writeMessage(websocket.TextMessage, []byte(msg))
+		}
+	})
+
+	return !chain.failed()
+}
+
+func encodeSocketIOPacket(p socketIOPacket) (string, error) {
+	var b strings.Builder
+
+	b.WriteString(strconv.Itoa(p.typ))
+
+	if p.namespace != "" && p.namespace != "/" {
+		b.WriteString(p.namespace)
+		b.WriteByte(',')
+	}
+
+	if p.id >= 0 {
+		b.WriteString(strconv.Itoa(p.id))
+	}
+
+	if p.data != nil {
+		data, err := json.Marshal(p.data)
+		if err != nil {
+			return "", err
+		}
+		b.Write(data)
+	}
+
+	return b.String(), nil
+}
+
+func decodeSocketIOPacket(s string) (socketIOPacket, error) {
+	p := socketIOPacket{
+		namespace: "/",
+		id:        -1,
+	}
+
+	if s == "" || s[0] < '0' || s[0] > '6' {
+		return p, errors.New("missing packet type")
+	}
+
+	p.typ = int(s[0] - '0')
+	s = s[1:]
+
+	if p.typ == 5 || p.typ == 6 {
+		return p, errors.New("binary packets are not supported")
+	}
+
+	if strings.HasPrefix(s, "/") {
+		end := strings.IndexByte(s, ',')
+		if end < 0 {
+			p.namespace, s = s, ""
+		} else {
+			p.namespace, s = s[:end], s[end+1:]
+		}
+	}
+
+	digits := 0
+	for digits < len(s) && s[digits] >= '0' && s[digits] <= '9' {
+		digits++
+	}
+	if digits != 0 {
+		id, err := strconv.Atoi(s[:digits])
+		if err != nil {
+			return p, err
+		}
+		p.id, s = id, s[digits:]
+	}
+
+	if s != "" {
+		if err := json.Unmarshal([]byte(s), &p.data); err != nil {
+			return p, err
+		}
+	}
+
+	return p, nil
+}
+
+// SocketIOEvent provides methods to inspect Socket.IO event received
+// from server.
+type SocketIOEvent struct {
+	chain *chain
+	sio   *SocketIO
+	name  string
+	args  []interface{}
+	ackID int
+}
+
+func newSocketIOEvent(
+	sio *SocketIO, parent *chain, name string, args []interface{}, ackID int,
+) *SocketIOEvent {
+	if parent == nil {
+		parent = sio.chain
+	}
+	if args == nil {
+		args = []interface{}{}
+	}
+	return &SocketIOEvent{parent.clone(), sio, name, args, ackID}
+}
+
+// Name returns a new String instance with event name.
+func (e *SocketIOEvent) Name() *String {
+	e.chain.enter("Name()")
+	defer e.chain.leave()
+
+	if e.chain.failed() {
+		return newString(e.chain, "")
+	}
+
+	return newString(e.chain, e.name)
+}
+
+// Args returns a new Array instance with event arguments.
+//
+// Example:
+//
+//	ev := sio.ExpectEvent("message")
+//	ev.Args().Length().Equal(2)
+func (e *SocketIOEvent) Args() *Array {
+	e.chain.enter("Args()")
+	defer e.chain.leave()
+
+	if e.chain.failed() {
+		return newArray(e.chain, nil)
+	}
+
+	return newArray(e.chain, e.args)
+}
+
+// Arg returns a new Value instance with event argument with given index.
+//
+// Arg fails if index is out of range.
+//
+// Example:
+//
+//	ev := sio.ExpectEvent("message")
+//	ev.Arg(0).Object().ValueEqual("text", "hi")
+func (e *SocketIOEvent) Arg(index int) *Value {
+	e.chain.enter("Arg(%d)", index)
+	defer e.chain.leave()
+
+	if e.chain.failed() {
+		return newValue(e.chain, nil)
+	}
+
+	if index < 0 || index >= len(e.args) {
+		e.chain.fail(AssertionFailure{
+			Type:     AssertInRange,
+			Actual:   &AssertionValue{index},
+			Expected: &AssertionValue{AssertionRange{0, len(e.args) - 1}},
+			Errors: []error{
+				errors.New("expected: valid argument index"),
+			},
+		})
+		return newValue(e.chain, nil)
+	}
+
+	return newValue(e.chain, e.args[index])
+}
+
+// Ack sends acknowledgement with given arguments for event.
+//
+// Ack fails if server didn't request acknowledgement.
+//
+// Example:
+//
+//	ev := sio.ExpectEvent("confirm")
+//	ev.Ack("ok")
+func (e *SocketIOEvent) Ack(args ...interface{}) *SocketIOEvent {
+	e.chain.enter("Ack()")
+	defer e.chain.leave()
+
+	if e.chain.failed() {
+		return e
+	}
+
+	if e.ackID < 0 {
+		e.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				fmt.Errorf("event %q doesn't request acknowledgement", e.name),
+			},
+		})
+		return e
+	}
+
+	if args == nil {
+		args = []interface{}{}
+	}
+
+	e.sio.write(e.chain, socketIOPacket{
+		typ:       socketIOAck,
+		namespace: e.sio.namespace,
+		id:        e.ackID,
+		data:      args,
+	})
+
+	return e
+}
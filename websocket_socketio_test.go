@@ -0,0 +1,236 @@
+package httpexpect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSocketIOConn(messages ...string) *mockWebsocketChanConn {
+	conn := newMockWebsocketChanConn(16)
+	for _, m := range messages {
+		conn.in <- []byte(m)
+	}
+	close(conn.in)
+	return conn
+}
+
+func TestSocketIOFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	ws := newWebsocket(chain, Config{}, nil)
+
+	sio := ws.SocketIO()
+	sio.chain.assertFailed(t)
+
+	sio.SessionID().chain.assertFailed(t)
+	sio.Emit("test")
+	sio.EmitWithAck("test").chain.assertFailed(t)
+	sio.ExpectEvent("test").chain.assertFailed(t)
+	sio.Disconnect()
+
+	ev := sio.ExpectEvent("test")
+	ev.Name().chain.assertFailed(t)
+	ev.Args().chain.assertFailed(t)
+	ev.Arg(0).chain.assertFailed(t)
+	ev.Ack()
+}
+
+func TestSocketIOHandshake(t *testing.T) {
+	conn := newSocketIOConn(
+		`0{"sid":"engine"}`,
+		`40{"sid":"socket"}`,
+	)
+
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, conn)
+
+	sio := ws.SocketIO()
+	sio.chain.assertOK(t)
+
+	sio.SessionID().Equal("socket")
+	sio.chain.assertOK(t)
+
+	assert.Equal(t, "40", string(<-conn.out))
+}
+
+func TestSocketIONamespace(t *testing.T) {
+	conn := newSocketIOConn(
+		`0{"sid":"engine"}`,
+		`40{"sid":"root"}`,
+		`40/chat,{"sid":"chat"}`,
+		`42["ignored"]`,
+		`42/chat,["hello","world"]`,
+	)
+
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, conn)
+
+	sio := ws.SocketIO("/chat")
+	sio.chain.assertOK(t)
+
+	sio.SessionID().Equal("chat")
+	sio.ExpectEvent("hello").Arg(0).String().Equal("world")
+	sio.chain.assertOK(t)
+
+	assert.Equal(t, "40/chat,", string(<-conn.out))
+}
+
+func TestSocketIOBadNamespace(t *testing.T) {
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, newSocketIOConn())
+
+	ws.SocketIO("chat").chain.assertFailed(t)
+	ws.SocketIO("/a", "/b").chain.assertFailed(t)
+}
+
+func TestSocketIOHandshakeErrors(t *testing.T) {
+	cases := []struct {
+		name     string
+		messages []string
+	}{
+		{"no open packet", []string{`40`}},
+		{"bad open packet", []string{`0{`}},
+		{"connect error", []string{`0{}`, `44{"message":"denied"}`}},
+		{"engine close", []string{`0{}`, `1`}},
+		{"connection closed", []string{`0{}`}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ws := NewWebsocket(Config{
+				Reporter: newMockReporter(t),
+			}, newSocketIOConn(tc.messages...))
+
+			ws.SocketIO().chain.assertFailed(t)
+		})
+	}
+}
+
+func TestSocketIOEmit(t *testing.T) {
+	conn := newSocketIOConn(
+		`0{}`,
+		`40`,
+		`2`,
+		`42["pushed",1]`,
+		`430["ok",{"id":2}]`,
+		`421["confirm"]`,
+	)
+
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, conn)
+
+	sio := ws.SocketIO()
+
+	sio.Emit("message", "hi", map[string]interface{}{"a": 1})
+	sio.EmitWithAck("create", "room").Equal([]interface{}{
+		"ok", map[string]interface{}{"id": 2},
+	})
+
+	sio.ExpectEvent("pushed").Args().Equal([]interface{}{1})
+	sio.ExpectEvent("confirm").Ack("yes")
+	sio.Disconnect()
+	sio.chain.assertOK(t)
+
+	var written []string
+	for len(conn.out) != 0 {
+		written = append(written, string(<-conn.out))
+	}
+
+	assert.Equal(t, []string{
+		`40`,
+		`42["message","hi",{"a":1}]`,
+		`420["create","room"]`,
+		`3`,
+		`431["yes"]`,
+		`41`,
+	}, written)
+}
+
+func TestSocketIOExpectEventErrors(t *testing.T) {
+	t.Run("name mismatch", func(t *testing.T) {
+		ws := NewWebsocket(Config{
+			Reporter: newMockReporter(t),
+		}, newSocketIOConn(`0{}`, `40`, `42["other"]`))
+
+		sio := ws.SocketIO()
+		sio.ExpectEvent("test").chain.assertFailed(t)
+		sio.chain.assertFailed(t)
+	})
+
+	t.Run("namespace disconnect", func(t *testing.T) {
+		ws := NewWebsocket(Config{
+			Reporter: newMockReporter(t),
+		}, newSocketIOConn(`0{}`, `40`, `41`))
+
+		sio := ws.SocketIO()
+		sio.ExpectEvent("test").chain.assertFailed(t)
+	})
+
+	t.Run("ack not requested", func(t *testing.T) {
+		ws := NewWebsocket(Config{
+			Reporter: newMockReporter(t),
+		}, newSocketIOConn(`0{}`, `40`, `42["test"]`))
+
+		sio := ws.SocketIO()
+		ev := sio.ExpectEvent("test")
+		ev.chain.assertOK(t)
+
+		ev.Ack()
+		ev.chain.assertFailed(t)
+	})
+
+	t.Run("arg out of range", func(t *testing.T) {
+		ws := NewWebsocket(Config{
+			Reporter: newMockReporter(t),
+		}, newSocketIOConn(`0{}`, `40`, `42["test"]`))
+
+		ev := ws.SocketIO().ExpectEvent("test")
+		ev.Arg(0).chain.assertFailed(t)
+	})
+}
+
+func TestSocketIOPacketCodec(t *testing.T) {
+	cases := []struct {
+		encoded string
+		packet  socketIOPacket
+	}{
+		{`0`, socketIOPacket{typ: socketIOConnect, namespace: "/", id: -1}},
+		{`0/admin,`, socketIOPacket{typ: socketIOConnect, namespace: "/admin", id: -1}},
+		{`2["a",1]`, socketIOPacket{
+			typ: socketIOEvent, namespace: "/", id: -1,
+			data: []interface{}{"a", 1.0},
+		}},
+		{`2/chat,12["a"]`, socketIOPacket{
+			typ: socketIOEvent, namespace: "/chat", id: 12,
+			data: []interface{}{"a"},
+		}},
+		{`312[]`, socketIOPacket{
+			typ: socketIOAck, namespace: "/", id: 12,
+			data: []interface{}{},
+		}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.encoded, func(t *testing.T) {
+			p, err := decodeSocketIOPacket(tc.encoded)
+			require.NoError(t, err)
+			assert.Equal(t, tc.packet, p)
+
+			s, err := encodeSocketIOPacket(tc.packet)
+			require.NoError(t, err)
+			assert.Equal(t, tc.encoded, s)
+		})
+	}
+
+	for _, s := range []string{``, `x`, `5["a"]`, `2{`} {
+		_, err := decodeSocketIOPacket(s)
+		assert.Error(t, err, s)
+	}
+}
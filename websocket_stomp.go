@@ -0,0 +1,753 @@
+package httpexpect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+// STOMP provides methods to exchange STOMP frames over WebSocket
+// connection.
+//
+// It implements client side of STOMP 1.2: performs CONNECT handshake,
+// encodes and decodes frames, skips heart-beats, and handles
+// subscriptions, receipts, and acknowledgements.
+type STOMP struct {
+	chain *chain
+	ws    *Websocket
+
+	connected *stompFrame
+
+	subscriptions map[string]string
+	nextID        int
+}
+
+// STOMP performs STOMP handshake over WebSocket connection and returns
+// a new STOMP instance.
+//
+// CONNECT frame is sent with "accept-version:1.2" header and given
+// headers, e.g. "host", "login", "passcode". If "host" is not given,
+// "/" is used. STOMP fails if server doesn't reply with CONNECTED frame.
+//
+// Example:
+//
+//	ws := e.GET("/stomp").WithWebsocketUpgrade().
+//	    WithWebsocketSubprotocols("v12.stomp").
+//	    Expect().
+//	    Websocket()
+//	defer ws.Disconnect()
+//
+//	stomp := ws.STOMP(map[string]string{"host": "example.com"})
+//	stomp.Subscribe("/topic/news")
+//	stomp.Send("/app/news", "hello")
+//	stomp.ExpectMessage().Body().Equal("hello")
+func (c *Websocket) STOMP(headers ...map[string]string) *STOMP {
+	c.chain.enter("STOMP()")
+	defer c.chain.leave()
+
+	s := &STOMP{
+		chain:         c.chain.clone(),
+		ws:            c,
+		subscriptions: map[string]string{},
+	}
+
+	if c.checkUnusable("STOMP()") {
+		s.chain.setFailed()
+		return s
+	}
+
+	if len(headers) > 1 {
+		s.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple headers arguments"),
+			},
+		})
+		return s
+	}
+
+	connect := map[string]string{
+		"accept-version": "1.2",
+		"host":           "/",
+	}
+	if len(headers) == 1 {
+		for k, v := range headers[0] {
+			connect[k] = v
+		}
+	}
+
+	if !s.write(s.chain, "CONNECT", connect, nil) {
+		return s
+	}
+
+	frame, ok := s.read(s.chain)
+	if !ok {
+		return s
+	}
+
+	if frame.command != "CONNECTED" {
+		s.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{frame.command},
+			Expected: &AssertionValue{"CONNECTED"},
+			Errors: stompErrors(frame,
+				errors.New("expected: STOMP server accepts connection")),
+		})
+		return s
+	}
+
+	s.connected = frame
+
+	return s
+}
+
+// Connected returns a new STOMPFrame instance with CONNECTED frame
+// received from server during handshake.
+//
+// Example:
+//
+//	stomp := ws.STOMP()
+//	stomp.Connected().Header("version").Equal("1.2")
+func (s *STOMP) Connected() *STOMPFrame {
+	s.chain.enter("Connected()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newSTOMPFrame(s.chain, s, nil)
+	}
+
+	return newSTOMPFrame(s.chain, s, s.connected)
+}
+
+// Subscribe sends SUBSCRIBE frame for given destination.
+//
+// Acknowledgement mode may be optionally specified: "auto" (default),
+// "client", or "client-individual". In non-auto modes, received messages
+// should be acknowledged using STOMPFrame.Ack.
+//
+// Example:
+//
+//	stomp := ws.STOMP()
+//	stomp.Subscribe("/queue/orders", "client")
+func (s *STOMP) Subscribe(destination string, ack ...string) *STOMP {
+	s.chain.enter("Subscribe(%q)", destination)
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	if len(ack) > 1 {
+		s.chain.fail(AssertionFailure{
+			Type: AssertUsage,
+			Errors: []error{
+				errors.New("unexpected multiple ack arguments"),
+			},
+		})
+		return s
+	}
+
+	mode := "auto"
+	if len(ack) == 1 {
+		mode = ack[0]
+	}
+
+	switch mode {
+	case "auto", "client", "client-individual":
+	default:
+		s.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{mode},
+			Errors: []error{
+				errors.New("unexpected STOMP ack mode"),
+			},
+		})
+		return s
+	}
+
+	id := "sub-" + strconv.Itoa(s.nextID)
+	s.nextID++
+
+	if s.write(s.chain, "SUBSCRIBE", map[string]string{
+		"id":          id,
+		"destination": destination,
+		"ack":         mode,
+	}, nil) {
+		s.subscriptions[destination] = id
+	}
+
+	return s
+}
+
+// Unsubscribe sends UNSUBSCRIBE frame for subscription to given
+// destination.
+//
+// Unsubscribe fails if there is no subscription to destination.
+//
+// Example:
+//
+//	stomp := ws.STOMP()
+//	stomp.Subscribe("/topic/news")
+//	stomp.Unsubscribe("/topic/news")
+func (s *STOMP) Unsubscribe(destination string) *STOMP {
+	s.chain.enter("Unsubscribe(%q)", destination)
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	id, ok := s.subscriptions[destination]
+	if !ok {
+		s.chain.fail(AssertionFailure{
+			Type:   AssertUsage,
+			Actual: &AssertionValue{destination},
+			Errors: []error{
+				errors.New("unexpected destination without subscription"),
+			},
+		})
+		return s
+	}
+
+	if s.write(s.chain, "UNSUBSCRIBE", map[string]string{"id": id}, nil) {
+		delete(s.subscriptions, destination)
+	}
+
+	return s
+}
+
+// Send sends SEND frame with given destination and body.
+//
+// Additional headers, e.g. "content-type", may be optionally specified.
+// "content-length" header is set automatically.
+//
+// Example:
+//
+//	stomp := ws.STOMP()
+//	stomp.Send("/queue/orders", "hello", map[string]string{
+//	    "content-type": "text/plain",
+//	})
+func (s *STOMP) Send(
+	destination string, body string, headers ...map[string]string,
+) *STOMP {
+	s.chain.enter("Send(%q)", destination)
+	defer s.chain.leave()
+
+	s.send(destination, []byte(body), headers)
+
+	return s
+}
+
+// SendJSON sends SEND frame with given destination and body marshaled
+// using json.Marshal. "content-type" header is set to "application/json".
+//
+// Example:
+//
+//	stomp := ws.STOMP()
+//	stomp.SendJSON("/queue/orders", map[string]interface{}{"id": 1})
+func (s *STOMP) SendJSON(
+	destination string, object interface{}, headers ...map[string]string,
+) *STOMP {
+	s.chain.enter("SendJSON(%q)", destination)
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	b, err := json.Marshal(object)
+	if err != nil {
+		s.chain.fail(AssertionFailure{
+			Type: AssertValid,
+			Errors: []error{
+				errors.New("invalid json object"),
+				err,
+			},
+		})
+		return s
+	}
+
+	headers = append([]map[string]string{
+		{"content-type": "application/json"},
+	}, headers...)
+
+	s.send(destination, b, headers)
+
+	return s
+}
+
+// ExpectFrame reads next frame from connection, skipping heart-beats,
+// and returns a new STOMPFrame instance.
+//
+// Example:
+//
+//	stomp := ws.STOMP()
+//	stomp.ExpectFrame().Command().Equal("MESSAGE")
+func (s *STOMP) ExpectFrame() *STOMPFrame {
+	s.chain.enter("ExpectFrame()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newSTOMPFrame(s.chain, s, nil)
+	}
+
+	frame, ok := s.read(s.chain)
+	if !ok {
+		return newSTOMPFrame(s.chain, s, nil)
+	}
+
+	return newSTOMPFrame(s.chain, s, frame)
+}
+
+// ExpectMessage reads next frame from connection, checks that it's
+// MESSAGE frame, and returns a new STOMPFrame instance.
+//
+// If server sent ERROR frame, failure is reported with its "message"
+// header.
+//
+// Example:
+//
+//	stomp := ws.STOMP()
+//	stomp.Subscribe("/topic/news")
+//	msg := stomp.ExpectMessage()
+//	msg.Header("destination").Equal("/topic/news")
+//	msg.JSON().Object().ContainsKey("title")
+func (s *STOMP) ExpectMessage() *STOMPFrame {
+	s.chain.enter("ExpectMessage()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return newSTOMPFrame(s.chain, s, nil)
+	}
+
+	frame, ok := s.read(s.chain)
+	if !ok {
+		return newSTOMPFrame(s.chain, s, nil)
+	}
+
+	if frame.command != "MESSAGE" {
+		s.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{frame.command},
+			Expected: &AssertionValue{"MESSAGE"},
+			Errors: stompErrors(frame,
+				errors.New("expected: STOMP MESSAGE frame")),
+		})
+		return newSTOMPFrame(s.chain, s, nil)
+	}
+
+	return newSTOMPFrame(s.chain, s, frame)
+}
+
+// Disconnect sends DISCONNECT frame with receipt request and waits
+// for RECEIPT frame from server. It doesn't close WebSocket connection.
+//
+// Example:
+//
+//	stomp := ws.STOMP()
+//	stomp.Disconnect()
+func (s *STOMP) Disconnect() *STOMP {
+	s.chain.enter("Disconnect()")
+	defer s.chain.leave()
+
+	if s.chain.failed() {
+		return s
+	}
+
+	receipt := "disconnect-" + strconv.Itoa(s.nextID)
+	s.nextID++
+
+	if !s.write(s.chain, "DISCONNECT", map[string]string{"receipt": receipt}, nil) {
+		return s
+	}
+
+	frame, ok := s.read(s.chain)
+	if !ok {
+		return s
+	}
+
+	if frame.command != "RECEIPT" || frame.headers["receipt-id"] != receipt {
+		s.chain.fail(AssertionFailure{
+			Type:     AssertEqual,
+			Actual:   &AssertionValue{frame.command + " " + frame.headers["receipt-id"]},
+			Expected: &AssertionValue{"RECEIPT " + receipt},
+			Errors: []error{
+				errors.New("expected: STOMP server confirms disconnect"),
+			},
+		})
+	}
+
+	return s
+}
+
+func (s *STOMP) send(
+	destination string, body []byte, headers []map[string]string,
+) {
+	if s.chain.failed() {
+		return
+	}
+
+	h := map[string]string{}
+	for _, m := range headers {
+		for k, v := range m {
+			h[k] = v
+		}
+	}
+
+	h["destination"] = destination
+	h["content-length"] = strconv.Itoa(len(body))
+
+	s.write(s.chain, "SEND", h, body)
+}
+
+func (s *STOMP) write(
+	chain *chain, command string, headers map[string]string, body []byte,
+) bool {
+	data := encodeSTOMPFrame(&stompFrame{command, headers, body})
+
+	s.ws.withChain(chain, func() {
+		if !s.ws.checkUnusable("STOMP()") {
+			s.ws.writeMessage(websocket.TextMessage, data)
+		}
+	})
+
+	return !chain.failed()
+}
+
+// reads next frame, skipping heart-beats
+func (s *STOMP) read(chain *chain) (*stompFrame, bool) {
+	for {
+		var m *WebsocketMessage
+
+		s.ws.withChain(chain, func() {
+			if !s.ws.checkUnusable("STOMP()") {
+				m = s.ws.readMessage()
+			}
+		})
+
+		if m == nil {
+			return nil, false
+		}
+
+		if m.typ == websocket.CloseMessage {
+			chain.fail(AssertionFailure{
+				Type: AssertOperation,
+				Errors: []error{
+					fmt.Errorf("connection closed by STOMP server: %s %q",
+						wsCloseCode(m.closeCode), m.content),
+				},
+			})
+			return nil, false
+		}
+
+		if strings.Trim(string(m.content), "\r\n") == "" {
+			continue
+		}
+
+		frame, err := decodeSTOMPFrame(m.content)
+		if err != nil {
+			chain.fail(AssertionFailure{
+				Type:   AssertValid,
+				Actual: &AssertionValue{string(m.content)},
+				Errors: []error{
+					errors.New("invalid STOMP frame"),
+					err,
+				},
+			})
+			return nil, false
+		}
+
+		return frame, true
+	}
+}
+
+// appends "message" header of ERROR frame, if any
+func stompErrors(f *stompFrame, errs ...error) []error {
+	if msg := f.headers["message"]; f.command == "ERROR" && msg != "" {
+		errs = append(errs, fmt.Errorf("server error: %s", msg))
+	}
+	return errs
+}
+
+type stompFrame struct {
+	command string
+	headers map[string]string
+	body    []byte
+}
+
+var (
+	stompEscaper = strings.NewReplacer(
+		"\\", "\\\\", "\r", "\\r", "\n", "\\n", ":", "\\c")
+	stompUnescaper = strings.NewReplacer(
+		"\\\\", "\\", "\\r", "\r", "\\n", "\n", "\\c", ":")
+)
+
+func encodeSTOMPFrame(f *stompFrame) []byte {
+	var b strings.Builder
+
+	b.WriteString(f.command)
+	b.WriteByte('\n')
+
+	keys := make([]string, 0, len(f.headers))
+	for k := range f.headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		// CONNECT and CONNECTED headers are not escaped
+		if f.command == "CONNECT" {
+			b.WriteString(k + ":" + f.headers[k])
+		} else {
+			b.WriteString(stompEscaper.Replace(k) + ":" +
+				stompEscaper.Replace(f.headers[k]))
+		}
+		b.WriteByte('\n')
+	}
+
+	b.WriteByte('\n')
+	b.Write(f.body)
+	b.WriteByte(0)
+
+	return []byte(b.String())
+}
+
+func decodeSTOMPFrame(data []byte) (*stompFrame, error) {
+	s := strings.TrimLeft(string(data), "\r\n")
+
+	end := strings.Index(s, "\n\n")
+	crlf := strings.Index(s, "\r\n\r\n")
+	sepLen := 2
+	if end < 0 || (crlf >= 0 && crlf < end) {
+		end, sepLen = crlf, 4
+	}
+	if end < 0 {
+		return nil, errors.New("missing blank line after headers")
+	}
+
+	lines := strings.Split(strings.ReplaceAll(s[:end], "\r\n", "\n"), "\n")
+
+	f := &stompFrame{
+		command: lines[0],
+		headers: map[string]string{},
+	}
+
+	if f.command == "" {
+		return nil, errors.New("missing command")
+	}
+
+	for _, line := range lines[1:] {
+		i := strings.IndexByte(line, ':')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid header line %q", line)
+		}
+
+		key, value := line[:i], line[i+1:]
+		if f.command != "CONNECTED" {
+			key, value = stompUnescaper.Replace(key), stompUnescaper.Replace(value)
+		}
+
+		// if header is repeated, first value is used
+		if _, ok := f.headers[key]; !ok {
+			f.headers[key] = value
+		}
+	}
+
+	body := s[end+sepLen:]
+
+	if cl, ok := f.headers["content-length"]; ok {
+		n, err := strconv.Atoi(cl)
+		if err != nil || n < 0 || n > len(body) {
+			return nil, fmt.Errorf("invalid content-length %q", cl)
+		}
+		body = body[:n]
+	} else if i := strings.IndexByte(body, 0); i >= 0 {
+		body = body[:i]
+	} else {
+		return nil, errors.New("missing NULL octet after body")
+	}
+
+	f.body = []byte(body)
+
+	return f, nil
+}
+
+// STOMPFrame provides methods to inspect STOMP frame received from server.
+type STOMPFrame struct {
+	chain *chain
+	stomp *STOMP
+	frame *stompFrame
+}
+
+func newSTOMPFrame(parent *chain, stomp *STOMP, frame *stompFrame) *STOMPFrame {
+	if frame == nil {
+		frame = &stompFrame{headers: map[string]string{}}
+	}
+	return &STOMPFrame{parent.clone(), stomp, frame}
+}
+
+// Command returns a new String instance with frame command,
+// e.g. "MESSAGE".
+func (f *STOMPFrame) Command() *String {
+	f.chain.enter("Command()")
+	defer f.chain.leave()
+
+	if f.chain.failed() {
+		return newString(f.chain, "")
+	}
+
+	return newString(f.chain, f.frame.command)
+}
+
+// Headers returns a new Object instance with frame headers.
+//
+// Example:
+//
+//	msg := stomp.ExpectMessage()
+//	msg.Headers().ContainsKey("message-id")
+func (f *STOMPFrame) Headers() *Object {
+	f.chain.enter("Headers()")
+	defer f.chain.leave()
+
+	if f.chain.failed() {
+		return newObject(f.chain, nil)
+	}
+
+	headers := make(map[string]interface{}, len(f.frame.headers))
+	for k, v := range f.frame.headers {
+		headers[k] = v
+	}
+
+	return newObject(f.chain, headers)
+}
+
+// Header returns a new String instance with given frame header.
+//
+// If header is missing, empty string is used.
+//
+// Example:
+//
+//	msg := stomp.ExpectMessage()
+//	msg.Header("destination").Equal("/topic/news")
+func (f *STOMPFrame) Header(name string) *String {
+	f.chain.enter("Header(%q)", name)
+	defer f.chain.leave()
+
+	if f.chain.failed() {
+		return newString(f.chain, "")
+	}
+
+	return newString(f.chain, f.frame.headers[name])
+}
+
+// Body returns a new String instance with frame body.
+//
+// Example:
+//
+//	msg := stomp.ExpectMessage()
+//	msg.Body().Equal("hello")
+func (f *STOMPFrame) Body() *String {
+	f.chain.enter("Body()")
+	defer f.chain.leave()
+
+	if f.chain.failed() {
+		return newString(f.chain, "")
+	}
+
+	return newString(f.chain, string(f.frame.body))
+}
+
+// JSON returns a new Value instance with JSON decoded from frame body.
+//
+// Example:
+//
+//	msg := stomp.ExpectMessage()
+//	msg.JSON().Object().ValueEqual("id", 1)
+func (f *STOMPFrame) JSON() *Value {
+	f.chain.enter("JSON()")
+	defer f.chain.leave()
+
+	if f.chain.failed() {
+		return newValue(f.chain, nil)
+	}
+
+	var value interface{}
+
+	if err := json.Unmarshal(f.frame.body, &value); err != nil {
+		f.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{string(f.frame.body)},
+			Errors: []error{
+				errors.New("failed to decode json"),
+				err,
+			},
+		})
+		return newValue(f.chain, nil)
+	}
+
+	return newValue(f.chain, value)
+}
+
+// Ack sends ACK frame for MESSAGE frame.
+//
+// Ack fails if frame doesn't have "ack" header, i.e. subscription uses
+// "auto" acknowledgement mode.
+//
+// Example:
+//
+//	stomp.Subscribe("/queue/orders", "client")
+//	stomp.ExpectMessage().Ack()
+func (f *STOMPFrame) Ack() *STOMPFrame {
+	f.chain.enter("Ack()")
+	defer f.chain.leave()
+
+	f.acknowledge("ACK")
+
+	return f
+}
+
+// Nack sends NACK frame for MESSAGE frame.
+//
+// Nack fails if frame doesn't have "ack" header, i.e. subscription uses
+// "auto" acknowledgement mode.
+//
+// Example:
+//
+//	stomp.Subscribe("/queue/orders", "client")
+//	stomp.ExpectMessage().Nack()
+func (f *STOMPFrame) Nack() *STOMPFrame {
+	f.chain.enter("Nack()")
+	defer f.chain.leave()
+
+	f.acknowledge("NACK")
+
+	return f
+}
+
+func (f *STOMPFrame) acknowledge(command string) {
+	if f.chain.failed() {
+		return
+	}
+
+	id, ok := f.frame.headers["ack"]
+	if !ok {
+		f.chain.fail(AssertionFailure{
+			Type:   AssertContainsKey,
+			Actual: &AssertionValue{f.frame.headers},
+			Expected: &AssertionValue{
+				"ack",
+			},
+			Errors: []error{
+				errors.New("expected: frame requests acknowledgement"),
+			},
+		})
+		return
+	}
+
+	f.stomp.write(f.chain, command, map[string]string{"id": id}, nil)
+}
@@ -0,0 +1,288 @@
+package httpexpect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSTOMPConn(frames ...string) *mockWebsocketChanConn {
+	conn := newMockWebsocketChanConn(16)
+	for _, f := range frames {
+		conn.in <- []byte(f)
+	}
+	close(conn.in)
+	return conn
+}
+
+func writtenSTOMPFrames(t *testing.T, conn *mockWebsocketChanConn) []*stompFrame {
+	var frames []*stompFrame
+	for len(conn.out) != 0 {
+		f, err := decodeSTOMPFrame(<-conn.out)
+		require.NoError(t, err)
+		frames = append(frames, f)
+	}
+	return frames
+}
+
+const stompConnected = "CONNECTED\nversion:1.2\nsession:s1\n\n\x00"
+
+func TestSTOMPFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	ws := newWebsocket(chain, Config{}, nil)
+
+	stomp := ws.STOMP()
+	stomp.chain.assertFailed(t)
+
+	stomp.Connected().chain.assertFailed(t)
+	stomp.Subscribe("/test")
+	stomp.Unsubscribe("/test")
+	stomp.Send("/test", "")
+	stomp.SendJSON("/test", nil)
+	stomp.ExpectFrame().chain.assertFailed(t)
+	stomp.Disconnect()
+
+	f := stomp.ExpectMessage()
+	f.chain.assertFailed(t)
+	f.Command().chain.assertFailed(t)
+	f.Headers().chain.assertFailed(t)
+	f.Header("test").chain.assertFailed(t)
+	f.Body().chain.assertFailed(t)
+	f.JSON().chain.assertFailed(t)
+	f.Ack()
+	f.Nack()
+}
+
+func TestSTOMPConnect(t *testing.T) {
+	conn := newSTOMPConn(stompConnected)
+
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, conn)
+
+	stomp := ws.STOMP(map[string]string{
+		"host":  "example.com",
+		"login": "user",
+	})
+	stomp.chain.assertOK(t)
+
+	stomp.Connected().Header("version").Equal("1.2")
+	stomp.Connected().Header("session").Equal("s1")
+	stomp.chain.assertOK(t)
+
+	frames := writtenSTOMPFrames(t, conn)
+	require.Equal(t, 1, len(frames))
+
+	assert.Equal(t, "CONNECT", frames[0].command)
+	assert.Equal(t, map[string]string{
+		"accept-version": "1.2",
+		"host":           "example.com",
+		"login":          "user",
+	}, frames[0].headers)
+}
+
+func TestSTOMPConnectErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		frames []string
+	}{
+		{"error frame", []string{"ERROR\nmessage:denied\n\n\x00"}},
+		{"bad frame", []string{"CONNECTED"}},
+		{"connection closed", nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ws := NewWebsocket(Config{
+				Reporter: newMockReporter(t),
+			}, newSTOMPConn(tc.frames...))
+
+			ws.STOMP().chain.assertFailed(t)
+		})
+	}
+
+	t.Run("multiple headers", func(t *testing.T) {
+		ws := NewWebsocket(Config{
+			Reporter: newMockReporter(t),
+		}, newSTOMPConn(stompConnected))
+
+		ws.STOMP(nil, nil).chain.assertFailed(t)
+	})
+}
+
+func TestSTOMPMessages(t *testing.T) {
+	conn := newSTOMPConn(
+		stompConnected,
+		"\n",
+		"MESSAGE\ndestination:/topic/a\nsubscription:sub-0\nmessage-id:1\n\nhello\x00",
+		"MESSAGE\ndestination:/queue/b\nack:m2\ncontent-length:9\n\n"+
+			"{\"id\":1}\n\x00",
+		"RECEIPT\nreceipt-id:disconnect-2\n\n\x00",
+	)
+
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, conn)
+
+	stomp := ws.STOMP()
+
+	stomp.Subscribe("/topic/a")
+	stomp.Subscribe("/queue/b", "client")
+
+	msg := stomp.ExpectMessage()
+	msg.Command().Equal("MESSAGE")
+	msg.Header("destination").Equal("/topic/a")
+	msg.Headers().ContainsKey("message-id")
+	msg.Body().Equal("hello")
+	msg.chain.assertOK(t)
+
+	msg.Ack()
+	msg.chain.assertFailed(t)
+
+	msg = stomp.ExpectMessage()
+	msg.JSON().Object().ValueEqual("id", 1)
+	msg.Ack()
+	msg.chain.assertOK(t)
+
+	stomp.Send("/app/c", "hi", map[string]string{"content-type": "text/plain"})
+	stomp.SendJSON("/app/d", map[string]interface{}{"x": 1})
+	stomp.Unsubscribe("/topic/a")
+	stomp.Disconnect()
+	stomp.chain.assertOK(t)
+
+	frames := writtenSTOMPFrames(t, conn)
+	require.Equal(t, 8, len(frames))
+
+	assert.Equal(t, "SUBSCRIBE", frames[1].command)
+	assert.Equal(t, map[string]string{
+		"id": "sub-0", "destination": "/topic/a", "ack": "auto",
+	}, frames[1].headers)
+
+	assert.Equal(t, "SUBSCRIBE", frames[2].command)
+	assert.Equal(t, map[string]string{
+		"id": "sub-1", "destination": "/queue/b", "ack": "client",
+	}, frames[2].headers)
+
+	assert.Equal(t, "ACK", frames[3].command)
+	assert.Equal(t, map[string]string{"id": "m2"}, frames[3].headers)
+
+	assert.Equal(t, "SEND", frames[4].command)
+	assert.Equal(t, map[string]string{
+		"destination":    "/app/c",
+		"content-type":   "text/plain",
+		"content-length": "2",
+	}, frames[4].headers)
+	assert.Equal(t, "hi", string(frames[4].body))
+
+	assert.Equal(t, "SEND", frames[5].command)
+	assert.Equal(t, "application/json", frames[5].headers["content-type"])
+	assert.Equal(t, `{"x":1}`, string(frames[5].body))
+
+	assert.Equal(t, "UNSUBSCRIBE", frames[6].command)
+	assert.Equal(t, map[string]string{"id": "sub-0"}, frames[6].headers)
+
+	assert.Equal(t, "DISCONNECT", frames[7].command)
+	assert.Equal(t, map[string]string{"receipt": "disconnect-2"}, frames[7].headers)
+}
+
+func TestSTOMPErrors(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	t.Run("error frame", func(t *testing.T) {
+		ws := NewWebsocket(config, newSTOMPConn(
+			stompConnected, "ERROR\nmessage:bad\n\n\x00"))
+
+		stomp := ws.STOMP()
+		stomp.ExpectMessage().chain.assertFailed(t)
+		stomp.chain.assertFailed(t)
+	})
+
+	t.Run("expect frame", func(t *testing.T) {
+		ws := NewWebsocket(config, newSTOMPConn(
+			stompConnected, "ERROR\nmessage:bad\n\n\x00"))
+
+		stomp := ws.STOMP()
+		stomp.ExpectFrame().Command().Equal("ERROR")
+		stomp.chain.assertOK(t)
+	})
+
+	t.Run("bad ack mode", func(t *testing.T) {
+		ws := NewWebsocket(config, newSTOMPConn(stompConnected))
+
+		stomp := ws.STOMP()
+		stomp.Subscribe("/test", "bad")
+		stomp.chain.assertFailed(t)
+	})
+
+	t.Run("unsubscribe unknown", func(t *testing.T) {
+		ws := NewWebsocket(config, newSTOMPConn(stompConnected))
+
+		stomp := ws.STOMP()
+		stomp.Unsubscribe("/test")
+		stomp.chain.assertFailed(t)
+	})
+
+	t.Run("bad receipt", func(t *testing.T) {
+		ws := NewWebsocket(config, newSTOMPConn(
+			stompConnected, "RECEIPT\nreceipt-id:other\n\n\x00"))
+
+		stomp := ws.STOMP()
+		stomp.Disconnect()
+		stomp.chain.assertFailed(t)
+	})
+
+	t.Run("bad json", func(t *testing.T) {
+		ws := NewWebsocket(config, newSTOMPConn(
+			stompConnected, "MESSAGE\n\n{\x00"))
+
+		stomp := ws.STOMP()
+		msg := stomp.ExpectMessage()
+		msg.JSON().chain.assertFailed(t)
+
+		stomp.SendJSON("/test", func() {})
+		stomp.chain.assertFailed(t)
+	})
+}
+
+func TestSTOMPFrameCodec(t *testing.T) {
+	f := &stompFrame{
+		command: "SEND",
+		headers: map[string]string{
+			"destination": "/a:b",
+			"x-line":      "1\n2\\3",
+		},
+		body: []byte("body\x00with null"),
+	}
+
+	data := encodeSTOMPFrame(f)
+	assert.Equal(t,
+		"SEND\ndestination:/a\\cb\nx-line:1\\n2\\\\3\n\nbody\x00with null\x00",
+		string(data))
+
+	f.headers["content-length"] = "14"
+	decoded, err := decodeSTOMPFrame(encodeSTOMPFrame(f))
+	require.NoError(t, err)
+	assert.Equal(t, f, decoded)
+
+	decoded, err = decodeSTOMPFrame([]byte("MESSAGE\r\na:1\r\na:2\r\n\r\nbody\x00\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "MESSAGE", decoded.command)
+	assert.Equal(t, map[string]string{"a": "1"}, decoded.headers)
+	assert.Equal(t, "body", string(decoded.body))
+
+	for _, s := range []string{
+		"MESSAGE",
+		"\n\n\x00",
+		"MESSAGE\nbad\n\n\x00",
+		"MESSAGE\n\nbody",
+		"MESSAGE\ncontent-length:100\n\nbody\x00",
+	} {
+		_, err := decodeSTOMPFrame([]byte(s))
+		assert.Error(t, err, s)
+	}
+}
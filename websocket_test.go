@@ -2,6 +2,7 @@ package httpexpect
 
 import (
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -24,13 +25,24 @@ func TestWebsocketFailed(t *testing.T) {
 	ws.WithoutWriteTimeout()
 
 	ws.Subprotocol().chain.assertFailed(t)
+	ws.Headers().chain.assertFailed(t)
+	ws.Header("foo").chain.assertFailed(t)
+	ws.Extensions().chain.assertFailed(t)
 	ws.Expect().chain.assertFailed(t)
+	ws.ExpectBinary().chain.assertFailed(t)
+	ws.ExpectText().chain.assertFailed(t)
+	ws.ExpectJSON(map[string]interface{}{}).chain.assertFailed(t)
+	ws.ExpectClose().chain.assertFailed(t)
+	ws.ExpectPong(time.Millisecond).chain.assertFailed(t)
+	ws.ExpectPing(time.Millisecond).chain.assertFailed(t)
 
 	ws.WriteMessage(websocket.TextMessage, []byte("a"))
 	ws.WriteBytesBinary([]byte("a"))
 	ws.WriteBytesText([]byte("a"))
 	ws.WriteText("a")
 	ws.WriteJSON(map[string]string{"a": "b"})
+	ws.WriteFragmented(websocket.TextMessage, []byte("a"))
+	ws.Ping([]byte("a"))
 
 	ws.Close()
 	ws.CloseWithBytes([]byte("a"))
@@ -205,6 +217,425 @@ func TestWebsocketExpect(t *testing.T) {
 	}
 }
 
+func TestWebsocketExpectType(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	t.Run("binary", func(t *testing.T) {
+		ws := NewWebsocket(config,
+			newMockWebsocketConn().WithMsgType(websocket.BinaryMessage))
+
+		ws.ExpectBinary().chain.assertOK(t)
+		ws.chain.assertOK(t)
+
+		ws.ExpectText().chain.assertFailed(t)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		ws := NewWebsocket(config,
+			newMockWebsocketConn().WithMsgType(websocket.TextMessage))
+
+		ws.ExpectText().chain.assertOK(t)
+		ws.chain.assertOK(t)
+
+		ws.ExpectBinary().chain.assertFailed(t)
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		ws := NewWebsocket(config,
+			newMockWebsocketConn().WithReadMsgError(fmt.Errorf("test")))
+
+		ws.ExpectBinary().chain.assertFailed(t)
+		ws.chain.assertFailed(t)
+	})
+}
+
+func TestWebsocketExpectJSON(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	t.Run("skip messages", func(t *testing.T) {
+		conn := &mockWebsocketQueueConn{
+			messages: [][]byte{
+				[]byte(`not json`),
+				[]byte(`{"type": "heartbeat"}`),
+				[]byte(`{"type": "event", "data": {"id": 1, "name": "foo"}}`),
+				[]byte(`{"type": "event", "data": {"id": 2}}`),
+			},
+		}
+
+		ws := NewWebsocket(config, conn)
+
+		msg := ws.ExpectJSON(map[string]interface{}{
+			"type": "event",
+			"data": map[string]interface{}{"id": 1},
+		})
+
+		msg.chain.assertOK(t)
+		ws.chain.assertOK(t)
+
+		msg.JSON().Object().ValueEqual("data", map[string]interface{}{
+			"id":   1,
+			"name": "foo",
+		})
+		msg.chain.assertOK(t)
+
+		if len(conn.messages) != 1 {
+			t.Fatal("unexpected number of remaining messages")
+		}
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		conn := &mockWebsocketQueueConn{
+			messages: [][]byte{
+				[]byte(`{"type": "heartbeat"}`),
+				[]byte(`{"type": "event", "id": 1}`),
+			},
+		}
+
+		ws := NewWebsocket(config, conn)
+
+		msg := ws.ExpectJSON(struct {
+			Type string `json:"type"`
+		}{
+			Type: "event",
+		})
+
+		msg.chain.assertOK(t)
+		msg.Body().Equal(`{"type": "event", "id": 1}`)
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		conn := &mockWebsocketQueueConn{
+			messages: [][]byte{
+				[]byte(`"a"`),
+				[]byte(`"b"`),
+			},
+		}
+
+		ws := NewWebsocket(config, conn)
+
+		ws.ExpectJSON("b").chain.assertOK(t)
+		ws.chain.assertOK(t)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		conn := &mockWebsocketQueueConn{
+			messages: [][]byte{
+				[]byte(`{"type": "heartbeat"}`),
+			},
+		}
+
+		ws := NewWebsocket(config, conn)
+
+		ws.ExpectJSON(map[string]interface{}{"type": "event"}).
+			chain.assertFailed(t)
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		conn := &mockWebsocketQueueConn{}
+		conn.readMsgErr = &websocket.CloseError{
+			Code: websocket.CloseNormalClosure,
+		}
+
+		ws := NewWebsocket(config, conn)
+
+		ws.ExpectJSON(map[string]interface{}{"type": "event"}).
+			chain.assertFailed(t)
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("bad match", func(t *testing.T) {
+		ws := NewWebsocket(config, &mockWebsocketQueueConn{})
+
+		ws.ExpectJSON(func() {}).chain.assertFailed(t)
+		ws.chain.assertFailed(t)
+	})
+}
+
+func TestWebsocketExpectClose(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	closeConn := func(code int) WebsocketConn {
+		return newMockWebsocketConn().WithReadMsgError(&websocket.CloseError{
+			Code: code,
+			Text: "bye",
+		})
+	}
+
+	t.Run("any code", func(t *testing.T) {
+		ws := NewWebsocket(config, closeConn(websocket.CloseGoingAway))
+
+		msg := ws.ExpectClose()
+		msg.chain.assertOK(t)
+
+		msg.Body().Equal("bye")
+		msg.chain.assertOK(t)
+	})
+
+	t.Run("code match", func(t *testing.T) {
+		ws := NewWebsocket(config, closeConn(websocket.CloseGoingAway))
+
+		ws.ExpectClose(websocket.CloseNormalClosure, websocket.CloseGoingAway).
+			chain.assertOK(t)
+	})
+
+	t.Run("code mismatch", func(t *testing.T) {
+		ws := NewWebsocket(config, closeConn(websocket.CloseAbnormalClosure))
+
+		ws.ExpectClose(websocket.CloseNormalClosure).chain.assertFailed(t)
+	})
+
+	t.Run("abnormal", func(t *testing.T) {
+		ws := NewWebsocket(config, closeConn(websocket.CloseAbnormalClosure))
+
+		ws.ExpectClose(websocket.CloseAbnormalClosure).chain.assertOK(t)
+	})
+
+	t.Run("not close", func(t *testing.T) {
+		ws := NewWebsocket(config,
+			newMockWebsocketConn().WithMsgType(websocket.TextMessage))
+
+		ws.ExpectClose().chain.assertFailed(t)
+	})
+}
+
+func TestWebsocketPing(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	t.Run("control conn", func(t *testing.T) {
+		conn := &mockWebsocketControlConn{}
+
+		ws := NewWebsocket(config, conn)
+
+		ws.Ping([]byte("hello"))
+		ws.chain.assertOK(t)
+
+		written := conn.writtenFrames()
+		if len(written) != 1 ||
+			written[0].typ != websocket.PingMessage ||
+			string(written[0].content) != "hello" {
+			t.Fatal("unexpected written control frames")
+		}
+	})
+
+	t.Run("basic conn", func(t *testing.T) {
+		ws := NewWebsocket(config, newMockWebsocketConn())
+
+		ws.Ping(nil)
+		ws.chain.assertOK(t)
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		conn := &mockWebsocketControlConn{}
+		conn.writeMsgErr = fmt.Errorf("test")
+
+		ws := NewWebsocket(config, conn)
+
+		ws.Ping([]byte("hello"))
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("too long", func(t *testing.T) {
+		ws := NewWebsocket(config, &mockWebsocketControlConn{})
+
+		ws.Ping(make([]byte, 126))
+		ws.chain.assertFailed(t)
+	})
+}
+
+func TestWebsocketExpectPong(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	t.Run("matching payload", func(t *testing.T) {
+		conn := &mockWebsocketControlConn{}
+		conn.incoming = []websocketRead{
+			{typ: websocket.PongMessage, content: []byte("other")},
+			{typ: websocket.TextMessage, content: []byte("data")},
+			{typ: websocket.PongMessage, content: []byte("hello")},
+		}
+
+		ws := NewWebsocket(config, conn)
+
+		msg := ws.Ping([]byte("hello")).ExpectPong(time.Second)
+		msg.chain.assertOK(t)
+
+		msg.Type(websocket.PongMessage)
+		msg.Body().Equal("hello")
+		msg.chain.assertOK(t)
+
+		// message received while waiting for pong is not lost
+		data := ws.Expect()
+		data.TextMessage()
+		data.Body().Equal("data")
+		data.chain.assertOK(t)
+	})
+
+	t.Run("any payload", func(t *testing.T) {
+		conn := &mockWebsocketControlConn{}
+		conn.incoming = []websocketRead{
+			{typ: websocket.PongMessage, content: []byte("heartbeat")},
+		}
+
+		ws := NewWebsocket(config, conn)
+
+		ws.ExpectPong(time.Second).Body().Equal("heartbeat").
+			chain.assertOK(t)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		conn := &mockWebsocketControlConn{}
+		conn.incoming = []websocketRead{
+			{typ: websocket.PongMessage, content: []byte("other")},
+		}
+
+		ws := NewWebsocket(config, conn)
+
+		ws.Ping([]byte("hello")).ExpectPong(10 * time.Millisecond).
+			chain.assertFailed(t)
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("unsupported conn", func(t *testing.T) {
+		ws := NewWebsocket(config, newMockWebsocketConn())
+
+		ws.ExpectPong(time.Second).chain.assertFailed(t)
+		ws.chain.assertFailed(t)
+	})
+}
+
+func TestWebsocketExpectPing(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	t.Run("received", func(t *testing.T) {
+		conn := &mockWebsocketControlConn{}
+		conn.incoming = []websocketRead{
+			{typ: websocket.PingMessage, content: []byte("keepalive")},
+		}
+
+		ws := NewWebsocket(config, conn)
+
+		msg := ws.ExpectPing(time.Second)
+		msg.chain.assertOK(t)
+
+		msg.Type(websocket.PingMessage)
+		msg.Body().Equal("keepalive")
+		msg.chain.assertOK(t)
+
+		// ping is replied with pong
+		written := conn.writtenFrames()
+		if len(written) != 1 ||
+			written[0].typ != websocket.PongMessage ||
+			string(written[0].content) != "keepalive" {
+			t.Fatal("unexpected written control frames")
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ws := NewWebsocket(config, &mockWebsocketControlConn{})
+
+		ws.ExpectPing(10 * time.Millisecond).chain.assertFailed(t)
+		ws.chain.assertFailed(t)
+	})
+}
+
+func TestWebsocketFragments(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	t.Run("read", func(t *testing.T) {
+		conn := &mockWebsocketFragmentConn{}
+		conn.msgType = websocket.BinaryMessage
+		conn.fragments = [][]byte{{1, 2}, {3}, {4, 5}}
+
+		ws := NewWebsocket(config, conn)
+
+		msg := ws.ExpectBinary()
+
+		msg.Fragmented()
+		msg.FragmentCount().Equal(3)
+		msg.Fragment(1).Equal([]byte{3})
+		msg.BodyBytes().Equal([]byte{1, 2, 3, 4, 5})
+
+		msg.chain.assertOK(t)
+		ws.chain.assertOK(t)
+	})
+
+	t.Run("read close", func(t *testing.T) {
+		conn := &mockWebsocketFragmentConn{}
+		conn.readMsgErr = &websocket.CloseError{
+			Code: websocket.CloseNormalClosure,
+			Text: "bye",
+		}
+
+		ws := NewWebsocket(config, conn)
+
+		msg := ws.Expect()
+
+		msg.CloseMessage()
+		msg.NotFragmented()
+		msg.Body().Equal("bye")
+
+		msg.chain.assertOK(t)
+	})
+
+	t.Run("write", func(t *testing.T) {
+		conn := &mockWebsocketFragmentConn{}
+
+		ws := NewWebsocket(config, conn)
+
+		ws.WriteFragmented(websocket.TextMessage, []byte("hel"), []byte("lo"))
+		ws.chain.assertOK(t)
+
+		if conn.writtenType != websocket.TextMessage ||
+			len(conn.writtenFragments) != 2 {
+			t.Fatal("unexpected written fragments")
+		}
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		conn := &mockWebsocketFragmentConn{}
+		conn.writeMsgErr = fmt.Errorf("test")
+
+		ws := NewWebsocket(config, conn)
+
+		ws.WriteFragmented(websocket.TextMessage, []byte("a"))
+		ws.chain.assertFailed(t)
+	})
+
+	t.Run("write usage", func(t *testing.T) {
+		cases := []struct {
+			conn      WebsocketConn
+			typ       int
+			fragments [][]byte
+		}{
+			{newMockWebsocketConn(), websocket.TextMessage, [][]byte{[]byte("a")}},
+			{&mockWebsocketFragmentConn{}, websocket.CloseMessage,
+				[][]byte{[]byte("a")}},
+			{&mockWebsocketFragmentConn{}, websocket.TextMessage, nil},
+		}
+
+		for _, tc := range cases {
+			ws := NewWebsocket(config, tc.conn)
+
+			ws.WriteFragmented(tc.typ, tc.fragments...)
+			ws.chain.assertFailed(t)
+		}
+	})
+}
+
 func TestWebsocketClose(t *testing.T) {
 	type args struct {
 		config     Config
@@ -999,6 +1430,39 @@ func TestWebsocketSubprotocol(t *testing.T) {
 	}
 }
 
+func TestWebsocketHandshake(t *testing.T) {
+	config := Config{
+		Reporter: newMockReporter(t),
+	}
+
+	t.Run("headers", func(t *testing.T) {
+		ws := NewWebsocket(config, newMockWebsocketConn())
+		ws.handshake = http.Header{
+			"Sec-Websocket-Accept": {"s3pPLMBiTxaQ9kYGzzhZRbK+xOo="},
+			"Sec-Websocket-Extensions": {
+				"permessage-deflate; client_max_window_bits, x-foo",
+				"x-bar",
+			},
+		}
+
+		ws.Headers().ContainsKey("Sec-Websocket-Accept")
+		ws.Header("sec-websocket-accept").Equal("s3pPLMBiTxaQ9kYGzzhZRbK+xOo=")
+		ws.Extensions().Equal([]string{"permessage-deflate", "x-foo", "x-bar"})
+
+		ws.chain.assertOK(t)
+	})
+
+	t.Run("no handshake", func(t *testing.T) {
+		ws := NewWebsocket(config, newMockWebsocketConn())
+
+		ws.Headers().Empty()
+		ws.Header("Sec-Websocket-Accept").Empty()
+		ws.Extensions().Empty()
+
+		ws.chain.assertOK(t)
+	})
+}
+
 func TestWebsocketSetReadDeadline(t *testing.T) {
 	type args struct {
 		config Config
@@ -0,0 +1,172 @@
+package httpexpect
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// maximum number of messages remembered in transcript
+const maxTranscriptEntries = 1000
+
+// maximum number of last messages included into failure message
+const maxFormattedTranscript = 20
+
+// Transcript returns a new Array instance with all messages sent and
+// received over WebSocket connection, in order.
+//
+// Every element is an object with the following fields:
+//   - "direction" - "sent" or "received"
+//   - "type" - message type, e.g. "text", "binary", "close", "ping", "pong"
+//   - "content" - message content; binary content is hex-encoded
+//   - "code" - close code, only for close messages
+//   - "time" - time when message was sent or received, RFC 3339
+//
+// Only last 1000 messages are remembered.
+//
+// Last messages of transcript are also included into failure reports
+// of Websocket and WebsocketMessage assertions.
+//
+// Example:
+//
+//	ws := resp.Websocket()
+//	ws.WriteText("hi").Expect()
+//	ws.Transcript().Length().Equal(2)
+//	ws.Transcript().Element(1).Object().ValueEqual("direction", "received")
+func (c *Websocket) Transcript() *Array {
+	c.chain.enter("Transcript()")
+	defer c.chain.leave()
+
+	if c.chain.failed() {
+		return newArray(c.chain, nil)
+	}
+
+	entries := make([]interface{}, 0, len(c.transcript.entries))
+
+	for _, e := range c.transcript.entries {
+		entry := map[string]interface{}{
+			"direction": e.direction(),
+			"type":      e.typeName(),
+			"content":   e.contentString(),
+			"time":      e.time.Format(time.RFC3339Nano),
+		}
+		if e.typ == websocket.CloseMessage {
+			entry["code"] = float64(e.closeCode)
+		}
+		entries = append(entries, entry)
+	}
+
+	return newArray(c.chain, entries)
+}
+
+// messages sent and received over connection
+type websocketTranscript struct {
+	entries []websocketTranscriptEntry
+	dropped int
+}
+
+type websocketTranscriptEntry struct {
+	sent      bool
+	typ       int
+	content   []byte
+	closeCode int
+	time      time.Time
+}
+
+func (t *websocketTranscript) add(sent bool, typ int, content []byte, closeCode int) {
+	if t == nil {
+		return
+	}
+
+	if len(t.entries) == maxTranscriptEntries {
+		t.entries = append(t.entries[:0], t.entries[1:]...)
+		t.dropped++
+	}
+
+	t.entries = append(t.entries, websocketTranscriptEntry{
+		sent:      sent,
+		typ:       typ,
+		content:   append([]byte(nil), content...),
+		closeCode: closeCode,
+		time:      time.Now(),
+	})
+}
+
+// formats up to limit last messages, one line per message
+func (t *websocketTranscript) format(limit int) []string {
+	if t == nil || len(t.entries) == 0 {
+		return nil
+	}
+
+	entries := t.entries
+	omitted := t.dropped
+
+	if len(entries) > limit {
+		omitted += len(entries) - limit
+		entries = entries[len(entries)-limit:]
+	}
+
+	var lines []string
+
+	if omitted != 0 {
+		lines = append(lines, fmt.Sprintf("... %d earlier message(s) omitted", omitted))
+	}
+
+	for _, e := range entries {
+		lines = append(lines, e.String())
+	}
+
+	return lines
+}
+
+func (e websocketTranscriptEntry) String() string {
+	dir := "<"
+	if e.sent {
+		dir = ">"
+	}
+
+	switch e.typ {
+	case websocket.CloseMessage:
+		return fmt.Sprintf("%s close %s %q",
+			dir, wsCloseCode(e.closeCode), e.content)
+
+	case websocket.BinaryMessage:
+		return fmt.Sprintf("%s binary (%d bytes) %s",
+			dir, len(e.content), e.contentString())
+
+	default:
+		return fmt.Sprintf("%s %s %q", dir, e.typeName(), e.content)
+	}
+}
+
+func (e websocketTranscriptEntry) direction() string {
+	if e.sent {
+		return "sent"
+	}
+	return "received"
+}
+
+func (e websocketTranscriptEntry) typeName() string {
+	switch e.typ {
+	case websocket.TextMessage:
+		return "text"
+	case websocket.BinaryMessage:
+		return "binary"
+	case websocket.CloseMessage:
+		return "close"
+	case websocket.PingMessage:
+		return "ping"
+	case websocket.PongMessage:
+		return "pong"
+	}
+	return fmt.Sprintf("unknown(%d)", e.typ)
+}
+
+func (e websocketTranscriptEntry) contentString() string {
+	if e.typ == websocket.BinaryMessage {
+		return hex.EncodeToString(e.content)
+	}
+	return string(e.content)
+}
@@ -0,0 +1,79 @@
+package httpexpect
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebsocketTranscriptFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	ws := newWebsocket(chain, Config{}, nil)
+
+	ws.Transcript().chain.assertFailed(t)
+}
+
+func TestWebsocketTranscript(t *testing.T) {
+	ws := NewWebsocket(Config{
+		Reporter: newMockReporter(t),
+	}, &mockWebsocketQueueConn{
+		messages: [][]byte{[]byte("hello")},
+	})
+
+	ws.Transcript().Empty()
+
+	ws.WriteText("hi")
+	ws.WriteBytesBinary([]byte{0x01, 0x02})
+	ws.Expect()
+	ws.CloseWithText("bye", websocket.CloseGoingAway)
+
+	ws.chain.assertOK(t)
+
+	tr := ws.Transcript()
+	tr.chain.assertOK(t)
+
+	tr.Length().Equal(4)
+
+	tr.Element(0).Object().
+		ValueEqual("direction", "sent").
+		ValueEqual("type", "text").
+		ValueEqual("content", "hi").
+		NotContainsKey("code").
+		ContainsKey("time")
+
+	tr.Element(1).Object().
+		ValueEqual("type", "binary").
+		ValueEqual("content", "0102")
+
+	tr.Element(2).Object().
+		ValueEqual("direction", "received").
+		ValueEqual("content", "hello")
+
+	tr.Element(3).Object().
+		ValueEqual("type", "close").
+		ValueEqual("content", "bye").
+		ValueEqual("code", websocket.CloseGoingAway)
+
+	tr.chain.assertOK(t)
+}
+
+func TestWebsocketTranscriptLimit(t *testing.T) {
+	transcript := &websocketTranscript{}
+
+	for i := 0; i < maxTranscriptEntries+5; i++ {
+		transcript.add(true, websocket.TextMessage, []byte("x"), 0)
+	}
+
+	assert.Equal(t, maxTranscriptEntries, len(transcript.entries))
+
+	lines := transcript.format(3)
+	assert.Equal(t, []string{
+		"... 1002 earlier message(s) omitted",
+		`> text "x"`,
+		`> text "x"`,
+		`> text "x"`,
+	}, lines)
+}
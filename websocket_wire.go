@@ -0,0 +1,301 @@
+package httpexpect
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// maximum size of HTTP response headers preceding websocket frames
+const maxWireHeaderSize = 64 * 1024
+
+// data message as it was received on the wire
+type websocketWireMessage struct {
+	compressed bool
+	size       int
+	frames     []int
+}
+
+// tracks frames received over connection by inspecting bytes read by
+// websocket.Conn from net.Conn; only works for unencrypted connections
+type websocketWire struct {
+	mu       sync.Mutex
+	messages []websocketWireMessage
+	failed   bool
+
+	handshake bool
+	header    []byte
+	frame     []byte
+	skip      uint64
+	current   *websocketWireMessage
+}
+
+// returns next data message received on the wire, if known
+func (w *websocketWire) next() (websocketWireMessage, bool) {
+	if w == nil {
+		return websocketWireMessage{}, false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.messages) == 0 {
+		return websocketWireMessage{}, false
+	}
+
+	m := w.messages[0]
+	w.messages = w.messages[1:]
+
+	return m, true
+}
+
+func (w *websocketWire) feed(b []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for len(b) != 0 && !w.failed {
+		switch {
+		case !w.handshake:
+			b = w.feedHeader(b)
+
+		case w.skip != 0:
+			n := uint64(len(b))
+			if n > w.skip {
+				n = w.skip
+			}
+			w.skip -= n
+			b = b[n:]
+
+		default:
+			b = w.feedFrame(b)
+		}
+	}
+}
+
+// skips HTTP responses until "101 Switching Protocols"; responses from
+// proxy may precede it
+func (w *websocketWire) feedHeader(b []byte) []byte {
+	w.header = append(w.header, b...)
+
+	end := bytes.Index(w.header, []byte("\r\n\r\n"))
+	if end < 0 {
+		if len(w.header) > maxWireHeaderSize {
+			w.failed = true
+		}
+		return nil
+	}
+
+	block, rest := w.header[:end], w.header[end+4:]
+	w.header = nil
+
+	if bytes.HasPrefix(block, []byte("HTTP/1.1 101")) ||
+		bytes.HasPrefix(block, []byte("HTTP/1.0 101")) {
+		w.handshake = true
+	}
+
+	return rest
+}
+
+// parses frame header (RFC 6455, section 5.2) and skips its payload
+func (w *websocketWire) feedFrame(b []byte) []byte {
+	for {
+		need := 2
+		if len(w.frame) >= 2 {
+			need = frameHeaderSize(w.frame)
+		}
+		if len(w.frame) >= need {
+			break
+		}
+		if len(b) == 0 {
+			return nil
+		}
+		w.frame = append(w.frame, b[0])
+		b = b[1:]
+	}
+
+	hdr := w.frame
+	w.frame = nil
+
+	fin := hdr[0]&0x80 != 0
+	rsv1 := hdr[0]&0x40 != 0
+	opcode := int(hdr[0] & 0x0f)
+
+	var length uint64
+	switch n := hdr[1] & 0x7f; n {
+	case 126:
+		length = uint64(binary.BigEndian.Uint16(hdr[2:4]))
+	case 127:
+		length = binary.BigEndian.Uint64(hdr[2:10])
+	default:
+		length = uint64(n)
+	}
+
+	w.skip = length
+
+	switch opcode {
+	case websocket.TextMessage, websocket.BinaryMessage:
+		w.current = &websocketWireMessage{compressed: rsv1}
+	case 0: // continuation
+		if w.current == nil {
+			w.failed = true
+			return nil
+		}
+	default: // control frame
+		return b
+	}
+
+	w.current.size += int(length)
+	w.current.frames = append(w.current.frames, int(length))
+
+	if fin {
+		w.messages = append(w.messages, *w.current)
+		w.current = nil
+	}
+
+	return b
+}
+
+// returns full size of frame header, given its first two bytes
+func frameHeaderSize(hdr []byte) int {
+	size := 2
+
+	switch hdr[1] & 0x7f {
+	case 126:
+		size += 2
+	case 127:
+		size += 8
+	}
+
+	if hdr[1]&0x80 != 0 {
+		size += 4 // masking key
+	}
+
+	return size
+}
+
+// splits content of message into fragments using sizes of frames in which
+// it was received; returns nil if frame sizes don't describe content, e.g.
+// if message was compressed
+func (m websocketWireMessage) split(content []byte) [][]byte {
+	if m.compressed || len(m.frames) < 2 || m.size != len(content) {
+		return nil
+	}
+
+	fragments := make([][]byte, 0, len(m.frames))
+
+	for _, size := range m.frames {
+		fragments = append(fragments, content[:size])
+		content = content[size:]
+	}
+
+	return fragments
+}
+
+// writes message as client frames (RFC 6455, section 5.2), one frame per
+// fragment; websocket.Conn doesn't allow to control frame boundaries, so
+// frames are written directly to underlying connection, using single write
+// to avoid interleaving with control frames written by websocket.Conn
+func writeWebsocketFrames(conn net.Conn, typ int, fragments [][]byte) error {
+	var buf bytes.Buffer
+
+	for n, fragment := range fragments {
+		var b0 byte
+		if n == 0 {
+			b0 = byte(typ)
+		}
+		if n == len(fragments)-1 {
+			b0 |= 0x80 // fin
+		}
+		buf.WriteByte(b0)
+
+		const mask = 0x80
+
+		switch size := len(fragment); {
+		case size <= 125:
+			buf.WriteByte(mask | byte(size))
+		case size <= 0xffff:
+			buf.WriteByte(mask | 126)
+			_ = binary.Write(&buf, binary.BigEndian, uint16(size))
+		default:
+			buf.WriteByte(mask | 127)
+			_ = binary.Write(&buf, binary.BigEndian, uint64(size))
+		}
+
+		var key [4]byte
+		if _, err := rand.Read(key[:]); err != nil {
+			return err
+		}
+		buf.Write(key[:])
+
+		for i, b := range fragment {
+			buf.WriteByte(b ^ key[i%4])
+		}
+	}
+
+	_, err := conn.Write(buf.Bytes())
+
+	return err
+}
+
+type websocketWireConn struct {
+	net.Conn
+	wire *websocketWire
+}
+
+func (c *websocketWireConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.wire.feed(b[:n])
+	}
+	return n, err
+}
+
+// dials websocket connection; if possible, tracks frames received over it,
+// otherwise returns nil wire
+func dialWebsocketWire(
+	dialer WebsocketDialer, u string, header http.Header,
+) (*websocket.Conn, *http.Response, *websocketWire, error) {
+	gorillaDialer, ok := dialer.(*websocket.Dialer)
+
+	if parsed, err := url.Parse(u); !ok || err != nil ||
+		(parsed.Scheme != "ws" && parsed.Scheme != "http") {
+		conn, resp, err := dialer.Dial(u, header)
+		return conn, resp, nil, err
+	}
+
+	dial := gorillaDialer.NetDialContext
+	if dial == nil && gorillaDialer.NetDial != nil {
+		netDial := gorillaDialer.NetDial
+		dial = func(_ context.Context, network, addr string) (net.Conn, error) {
+			return netDial(network, addr)
+		}
+	}
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+
+	wire := &websocketWire{}
+
+	dialerCopy := *gorillaDialer
+	dialerCopy.NetDial = nil
+	dialerCopy.NetDialContext = func(
+		ctx context.Context, network, addr string,
+	) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return &websocketWireConn{conn, wire}, nil
+	}
+
+	conn, resp, err := dialerCopy.Dial(u, header)
+
+	return conn, resp, wire, err
+}
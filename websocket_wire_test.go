@@ -0,0 +1,172 @@
+package httpexpect
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebsocketWireFeed(t *testing.T) {
+	var data bytes.Buffer
+
+	// response from proxy, then handshake response
+	data.WriteString("HTTP/1.1 200 Connection established\r\n\r\n")
+	data.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\n\r\n")
+
+	// compressed text message
+	data.Write([]byte{0xc1, 0x03, 'a', 'b', 'c'})
+
+	// ping, not a data message
+	data.Write([]byte{0x89, 0x01, 'p'})
+
+	// fragmented binary message with masked continuation and 16-bit length
+	data.Write([]byte{0x02, 0x02, 'x', 'y'})
+	data.Write([]byte{0x80, 0xfe, 0x01, 0x00, 1, 2, 3, 4})
+	data.Write(make([]byte, 256))
+
+	// empty text message
+	data.Write([]byte{0x81, 0x00})
+
+	for _, chunk := range []int{1, 3, 7, data.Len()} {
+		wire := &websocketWire{}
+
+		b := data.Bytes()
+		for len(b) != 0 {
+			n := chunk
+			if n > len(b) {
+				n = len(b)
+			}
+			wire.feed(b[:n])
+			b = b[n:]
+		}
+
+		require.False(t, wire.failed)
+
+		m, ok := wire.next()
+		require.True(t, ok)
+		assert.Equal(t, websocketWireMessage{compressed: true, size: 3, frames: []int{3}}, m)
+
+		m, ok = wire.next()
+		require.True(t, ok)
+		assert.Equal(t, websocketWireMessage{
+			compressed: false, size: 258, frames: []int{2, 256},
+		}, m)
+
+		m, ok = wire.next()
+		require.True(t, ok)
+		assert.Equal(t, websocketWireMessage{compressed: false, size: 0, frames: []int{0}}, m)
+
+		_, ok = wire.next()
+		assert.False(t, ok)
+	}
+}
+
+func TestWebsocketWireSplit(t *testing.T) {
+	content := []byte("hello")
+
+	cases := []struct {
+		name     string
+		message  websocketWireMessage
+		expected [][]byte
+	}{
+		{
+			name:     "fragmented",
+			message:  websocketWireMessage{size: 5, frames: []int{3, 0, 2}},
+			expected: [][]byte{[]byte("hel"), []byte(""), []byte("lo")},
+		},
+		{
+			name:     "single frame",
+			message:  websocketWireMessage{size: 5, frames: []int{5}},
+			expected: nil,
+		},
+		{
+			name:     "compressed",
+			message:  websocketWireMessage{compressed: true, size: 5, frames: []int{3, 2}},
+			expected: nil,
+		},
+		{
+			name:     "size mismatch",
+			message:  websocketWireMessage{size: 4, frames: []int{2, 2}},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.message.split(content))
+		})
+	}
+}
+
+func TestWebsocketWireWriteFrames(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go func() {
+		_ = writeWebsocketFrames(client, websocket.BinaryMessage,
+			[][]byte{[]byte("hel"), make([]byte, 200)})
+	}()
+
+	wire := &websocketWire{handshake: true}
+
+	var received []byte
+
+	buf := make([]byte, 1024)
+	for len(received) < 2+4+3+4+4+200 {
+		n, err := server.Read(buf)
+		require.NoError(t, err)
+		received = append(received, buf[:n]...)
+		wire.feed(buf[:n])
+	}
+
+	// first frame: binary, not fin, masked
+	assert.Equal(t, byte(0x02), received[0])
+	assert.Equal(t, byte(0x80|3), received[1])
+
+	key := received[2:6]
+	payload := make([]byte, 3)
+	for i := range payload {
+		payload[i] = received[6+i] ^ key[i%4]
+	}
+	assert.Equal(t, []byte("hel"), payload)
+
+	// second frame: continuation, fin, masked, 16-bit length
+	assert.Equal(t, byte(0x80), received[9])
+	assert.Equal(t, byte(0x80|126), received[10])
+
+	m, ok := wire.next()
+	require.True(t, ok)
+	assert.Equal(t, []int{3, 200}, m.frames)
+}
+
+func TestWebsocketWireFailed(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		var wire *websocketWire
+
+		_, ok := wire.next()
+		assert.False(t, ok)
+	})
+
+	t.Run("large header", func(t *testing.T) {
+		wire := &websocketWire{}
+
+		wire.feed(bytes.Repeat([]byte("x"), maxWireHeaderSize+1))
+		assert.True(t, wire.failed)
+	})
+
+	t.Run("unexpected continuation", func(t *testing.T) {
+		wire := &websocketWire{}
+
+		wire.feed([]byte("HTTP/1.1 101 Switching Protocols\r\n\r\n"))
+		wire.feed([]byte{0x80, 0x00})
+		assert.True(t, wire.failed)
+
+		_, ok := wire.next()
+		assert.False(t, ok)
+	})
+}
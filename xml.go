@@ -0,0 +1,361 @@
+package httpexpect
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// XML provides methods to inspect parsed XML document.
+//
+// XML instance holds a set of nodes: initially it's the document itself,
+// and XPath returns a new instance with nodes selected by the query.
+// Methods like String, Number, and Attribute inspect the first node of
+// the set.
+type XML struct {
+	chain *chain
+	root  *xmlNode
+	nodes []*xmlNode
+}
+
+// NewXML returns a new XML instance.
+//
+// reporter should not be nil. data should be a well-formed XML document;
+// if it is not, failure is reported.
+//
+// Example:
+//
+//	doc := NewXML(t, []byte(`<order><id>42</id></order>`))
+//	doc.XPath("/order/id").String().Equal("42")
+func NewXML(reporter Reporter, data []byte) *XML {
+	return newXML(newChainWithDefaults("XML()", reporter), data)
+}
+
+func newXML(parent *chain, data []byte) *XML {
+	x := &XML{chain: parent.clone()}
+
+	if x.chain.failed() {
+		return x
+	}
+
+	root, err := parseXML(data)
+	if err != nil {
+		x.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{string(data)},
+			Errors: []error{
+				errors.New("failed to decode xml"),
+				err,
+			},
+		})
+		return x
+	}
+
+	x.root = root
+	x.nodes = []*xmlNode{root}
+
+	return x
+}
+
+func (x *XML) derive(nodes []*xmlNode) *XML {
+	return &XML{chain: x.chain.clone(), root: x.root, nodes: nodes}
+}
+
+// Raw returns string values of nodes attached to XML.
+//
+// String value of element is concatenation of all its descendant text
+// nodes, and string value of attribute is its value.
+//
+// Example:
+//
+//	doc := NewXML(t, []byte(`<a><b>1</b><b>2</b></a>`))
+//	assert.Equal(t, []string{"1", "2"}, doc.XPath("//b").Raw())
+func (x *XML) Raw() []string {
+	values := make([]string, 0, len(x.nodes))
+	for _, node := range x.nodes {
+		values = append(values, node.stringValue())
+	}
+	return values
+}
+
+// XPath returns a new XML instance with nodes selected by given XPath
+// query, evaluated against every node of the current set.
+//
+// Supported subset of XPath 1.0:
+//   - absolute and relative paths: "/a/b", "a/b", "//b", "a//b"
+//   - steps: "name", "*", "@attr", "@*", "text()", "node()", ".", ".."
+//   - predicates: "[2]", "[last()]", "[@attr]", "[@attr='value']",
+//     "[child='value']", "[text()='value']"
+//
+// Namespace prefixes in names are ignored, i.e. "soap:Body" matches
+// element with local name "Body" in any namespace.
+//
+// Empty result is not a failure; use Exists or Length to check it.
+//
+// Example:
+//
+//	doc := NewXML(t, data)
+//	doc.XPath("//Order[@status='new']/Id").String().Equal("42")
+func (x *XML) XPath(query string) *XML {
+	x.chain.enter("XPath(%q)", query)
+	defer x.chain.leave()
+
+	if x.chain.failed() {
+		return x.derive(nil)
+	}
+
+	expr, err := parseXPath(query)
+	if err != nil {
+		x.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{query},
+			Errors: []error{
+				errors.New("invalid xpath expression"),
+				err,
+			},
+		})
+		return x.derive(nil)
+	}
+
+	return x.derive(expr.eval(x.root, x.nodes))
+}
+
+// Length returns a new Number instance with number of nodes.
+//
+// Example:
+//
+//	doc := NewXML(t, data)
+//	doc.XPath("//Item").Length().Equal(3)
+func (x *XML) Length() *Number {
+	x.chain.enter("Length()")
+	defer x.chain.leave()
+
+	if x.chain.failed() {
+		return newNumber(x.chain, 0)
+	}
+
+	return newNumber(x.chain, float64(len(x.nodes)))
+}
+
+// Element returns a new XML instance with node at given index.
+//
+// If index is out of bounds, Element reports failure and returns empty
+// (but non-nil) instance.
+//
+// Example:
+//
+//	doc := NewXML(t, data)
+//	doc.XPath("//Item").Element(1).Attribute("id").Equal("2")
+func (x *XML) Element(index int) *XML {
+	x.chain.enter("Element(%d)", index)
+	defer x.chain.leave()
+
+	if x.chain.failed() {
+		return x.derive(nil)
+	}
+
+	if index < 0 || index >= len(x.nodes) {
+		x.chain.fail(AssertionFailure{
+			Type:   AssertInRange,
+			Actual: &AssertionValue{index},
+			Expected: &AssertionValue{AssertionRange{
+				Min: 0,
+				Max: len(x.nodes) - 1,
+			}},
+			Errors: []error{
+				errors.New("expected: valid node index"),
+			},
+		})
+		return x.derive(nil)
+	}
+
+	return x.derive(x.nodes[index : index+1])
+}
+
+// Exists succeeds if node set is not empty.
+//
+// Example:
+//
+//	doc := NewXML(t, data)
+//	doc.XPath("//soap:Fault").Exists()
+func (x *XML) Exists() *XML {
+	x.chain.enter("Exists()")
+	defer x.chain.leave()
+
+	if x.chain.failed() {
+		return x
+	}
+
+	if len(x.nodes) == 0 {
+		x.chain.fail(AssertionFailure{
+			Type:   AssertNotEmpty,
+			Actual: &AssertionValue{x.Raw()},
+			Errors: []error{
+				errors.New("expected: xml node exists"),
+			},
+		})
+	}
+
+	return x
+}
+
+// NotExists succeeds if node set is empty.
+//
+// Example:
+//
+//	doc := NewXML(t, data)
+//	doc.XPath("//soap:Fault").NotExists()
+func (x *XML) NotExists() *XML {
+	x.chain.enter("NotExists()")
+	defer x.chain.leave()
+
+	if x.chain.failed() {
+		return x
+	}
+
+	if len(x.nodes) != 0 {
+		x.chain.fail(AssertionFailure{
+			Type:   AssertEmpty,
+			Actual: &AssertionValue{x.Raw()},
+			Errors: []error{
+				errors.New("expected: xml node does not exist"),
+			},
+		})
+	}
+
+	return x
+}
+
+// Name returns a new String instance with local name of the first node.
+//
+// Example:
+//
+//	doc := NewXML(t, data)
+//	doc.XPath("/*").Name().Equal("Envelope")
+func (x *XML) Name() *String {
+	x.chain.enter("Name()")
+	defer x.chain.leave()
+
+	node := x.first()
+	if node == nil {
+		return newString(x.chain, "")
+	}
+
+	return newString(x.chain, node.name.Local)
+}
+
+// String returns a new String instance with string value of the first node.
+//
+// String value of element is concatenation of all its descendant text
+// nodes, and string value of attribute is its value.
+//
+// Example:
+//
+//	doc := NewXML(t, data)
+//	doc.XPath("//Order/Id").String().Equal("42")
+func (x *XML) String() *String {
+	x.chain.enter("String()")
+	defer x.chain.leave()
+
+	node := x.first()
+	if node == nil {
+		return newString(x.chain, "")
+	}
+
+	return newString(x.chain, node.stringValue())
+}
+
+// Number returns a new Number instance with string value of the first node
+// parsed as float.
+//
+// Leading and trailing whitespace is ignored.
+//
+// Example:
+//
+//	doc := NewXML(t, data)
+//	doc.XPath("//Order/Total").Number().Equal(9.99)
+func (x *XML) Number() *Number {
+	x.chain.enter("Number()")
+	defer x.chain.leave()
+
+	node := x.first()
+	if node == nil {
+		return newNumber(x.chain, 0)
+	}
+
+	str := strings.TrimSpace(node.stringValue())
+
+	num, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		x.chain.fail(AssertionFailure{
+			Type:   AssertValid,
+			Actual: &AssertionValue{str},
+			Errors: []error{
+				errors.New("expected: xml node value is number"),
+				err,
+			},
+		})
+		return newNumber(x.chain, 0)
+	}
+
+	return newNumber(x.chain, num)
+}
+
+// Attribute returns a new String instance with value of given attribute
+// of the first node.
+//
+// Namespace prefix in name is ignored.
+//
+// Example:
+//
+//	doc := NewXML(t, data)
+//	doc.XPath("//Order").Attribute("status").Equal("new")
+func (x *XML) Attribute(name string) *String {
+	x.chain.enter("Attribute(%q)", name)
+	defer x.chain.leave()
+
+	node := x.first()
+	if node == nil {
+		return newString(x.chain, "")
+	}
+
+	step := xpathStep{axis: xpathAttribute, test: name}
+
+	attrs := step.eval(x.root, node)
+	if len(attrs) == 0 {
+		x.chain.fail(AssertionFailure{
+			Type:   AssertContainsKey,
+			Actual: &AssertionValue{node.name.Local},
+			Expected: &AssertionValue{
+				name,
+			},
+			Errors: []error{
+				fmt.Errorf("expected: xml element has attribute %q", name),
+			},
+		})
+		return newString(x.chain, "")
+	}
+
+	return newString(x.chain, attrs[0].text)
+}
+
+// returns first node or reports failure
+func (x *XML) first() *xmlNode {
+	if x.chain.failed() {
+		return nil
+	}
+
+	if len(x.nodes) == 0 {
+		x.chain.fail(AssertionFailure{
+			Type:   AssertNotEmpty,
+			Actual: &AssertionValue{x.Raw()},
+			Errors: []error{
+				errors.New("expected: xpath query selected at least one node"),
+			},
+		})
+		return nil
+	}
+
+	return x.nodes[0]
+}
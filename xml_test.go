@@ -0,0 +1,99 @@
+package httpexpect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestXMLFailed(t *testing.T) {
+	chain := newMockChain(t)
+	chain.fail(AssertionFailure{})
+
+	value := newXML(chain, []byte(`<a/>`))
+
+	value.chain.assertFailed(t)
+
+	assert.NotNil(t, value.XPath("/a"))
+	assert.NotNil(t, value.Length())
+	assert.NotNil(t, value.Element(0))
+	assert.NotNil(t, value.Name())
+	assert.NotNil(t, value.String())
+	assert.NotNil(t, value.Number())
+	assert.NotNil(t, value.Attribute("id"))
+
+	value.Exists()
+	value.NotExists()
+}
+
+func TestXMLInvalid(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	NewXML(reporter, []byte(`<a>`)).chain.assertFailed(t)
+	NewXML(reporter, nil).chain.assertFailed(t)
+
+	value := NewXML(reporter, []byte(`<a/>`))
+	value.XPath("a[").chain.assertFailed(t)
+}
+
+func TestXMLGetters(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewXML(reporter, []byte(xpathTestDocument))
+	value.chain.assertOK(t)
+
+	value.XPath("/*").Name().Equal("Envelope").chain.assertOK(t)
+
+	orders := value.XPath("//Order")
+	orders.chain.assertOK(t)
+
+	orders.Length().Equal(3).chain.assertOK(t)
+	orders.Exists().chain.assertOK(t)
+
+	assert.Equal(t, []string{"1", "2", "3"}, orders.XPath("@id").Raw())
+
+	order := orders.Element(1)
+	order.chain.assertOK(t)
+
+	order.Attribute("status").Equal("done").chain.assertOK(t)
+	order.XPath("Id").String().Equal("42").chain.assertOK(t)
+	order.XPath("Total").Number().Equal(2.5).chain.assertOK(t)
+
+	value.XPath("//Order[@id='2']/Id").Number().Equal(42).chain.assertOK(t)
+	value.XPath("//Fault").NotExists().chain.assertOK(t)
+	value.XPath("//Fault").Length().Equal(0).chain.assertOK(t)
+}
+
+func TestXMLFailures(t *testing.T) {
+	reporter := newMockReporter(t)
+
+	value := NewXML(reporter, []byte(xpathTestDocument))
+
+	check := func(name string, fn func(*XML) *chain) {
+		t.Run(name, func(t *testing.T) {
+			fn(value.XPath("/")).assertFailed(t)
+		})
+	}
+
+	check("exists", func(x *XML) *chain {
+		return x.XPath("//Fault").Exists().chain
+	})
+	check("not exists", func(x *XML) *chain {
+		return x.XPath("//Order").NotExists().chain
+	})
+	check("element", func(x *XML) *chain {
+		return x.XPath("//Order").Element(3).chain
+	})
+	check("string", func(x *XML) *chain {
+		return x.XPath("//Fault").String().chain
+	})
+	check("name", func(x *XML) *chain {
+		return x.XPath("//Fault").Name().chain
+	})
+	check("number", func(x *XML) *chain {
+		return x.XPath("//Order[1]/@status").Number().chain
+	})
+	check("attribute", func(x *XML) *chain {
+		return x.XPath("//Order[1]").Attribute("missing").chain
+	})
+}
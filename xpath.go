@@ -0,0 +1,491 @@
+package httpexpect
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type xmlNodeKind int
+
+const (
+	xmlDocumentNode xmlNodeKind = iota
+	xmlElementNode
+	xmlAttributeNode
+	xmlTextNode
+)
+
+// node of parsed XML document
+type xmlNode struct {
+	kind     xmlNodeKind
+	name     xml.Name
+	text     string
+	attrs    []*xmlNode
+	children []*xmlNode
+	parent   *xmlNode
+}
+
+// parses XML document into tree; comments, processing instructions,
+// and directives are skipped
+func parseXML(data []byte) (*xmlNode, error) {
+	doc := &xmlNode{kind: xmlDocumentNode}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	cur := doc
+	hasRoot := false
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if cur == doc {
+				if hasRoot {
+					return nil, errors.New("multiple root elements")
+				}
+				hasRoot = true
+			}
+
+			elem := &xmlNode{
+				kind:   xmlElementNode,
+				name:   t.Name,
+				parent: cur,
+			}
+			for _, attr := range t.Attr {
+				elem.attrs = append(elem.attrs, &xmlNode{
+					kind:   xmlAttributeNode,
+					name:   attr.Name,
+					text:   attr.Value,
+					parent: elem,
+				})
+			}
+
+			cur.children = append(cur.children, elem)
+			cur = elem
+
+		case xml.EndElement:
+			cur = cur.parent
+
+		case xml.CharData:
+			if cur == doc {
+				continue
+			}
+			cur.children = append(cur.children, &xmlNode{
+				kind:   xmlTextNode,
+				text:   string(t),
+				parent: cur,
+			})
+		}
+	}
+
+	if !hasRoot {
+		return nil, errors.New("missing root element")
+	}
+
+	return doc, nil
+}
+
+// returns XPath string value of node
+func (n *xmlNode) stringValue() string {
+	switch n.kind {
+	case xmlAttributeNode, xmlTextNode:
+		return n.text
+	}
+
+	var buf strings.Builder
+	n.appendText(&buf)
+
+	return buf.String()
+}
+
+func (n *xmlNode) appendText(buf *strings.Builder) {
+	for _, child := range n.children {
+		if child.kind == xmlTextNode {
+			buf.WriteString(child.text)
+		} else {
+			child.appendText(buf)
+		}
+	}
+}
+
+func (n *xmlNode) appendDescendants(nodes []*xmlNode) []*xmlNode {
+	for _, child := range n.children {
+		nodes = append(nodes, child)
+		nodes = child.appendDescendants(nodes)
+	}
+	return nodes
+}
+
+// XPath subset:
+//   - absolute and relative location paths: /a/b, a/b, //b, a//b
+//   - steps: name, prefix:name, *, @name, @*, text(), node(), ., ..
+//   - predicates: [n], [last()], [path], [path='literal']
+//
+// Name prefixes are ignored, i.e. "soap:Body" matches any element with
+// local name "Body", regardless of its namespace.
+type xpathExpr struct {
+	absolute bool
+	steps    []xpathStep
+}
+
+type xpathAxis int
+
+const (
+	xpathChild xpathAxis = iota
+	xpathDescendantOrSelf
+	xpathSelf
+	xpathParent
+	xpathAttribute
+)
+
+type xpathStep struct {
+	axis  xpathAxis
+	test  string
+	preds []xpathPred
+}
+
+type xpathPred struct {
+	index int // 1-based position, or -1 for last()
+	expr  *xpathExpr
+	value *string
+}
+
+type xpathParser struct {
+	s   string
+	pos int
+}
+
+func parseXPath(s string) (*xpathExpr, error) {
+	p := &xpathParser{s: strings.TrimSpace(s)}
+
+	if p.s == "" {
+		return nil, errors.New("empty expression")
+	}
+
+	expr, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos != len(p.s) {
+		return nil, p.errorf("unexpected character %q", p.s[p.pos])
+	}
+
+	return expr, nil
+}
+
+func (p *xpathParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("at position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *xpathParser) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(p.s[p.pos:], prefix)
+}
+
+func (p *xpathParser) skipSpaces() {
+	for p.pos < len(p.s) && p.s[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+var descendantOrSelfStep = xpathStep{axis: xpathDescendantOrSelf, test: "node()"}
+
+func (p *xpathParser) parsePath() (*xpathExpr, error) {
+	expr := &xpathExpr{}
+
+	switch {
+	case p.hasPrefix("//"):
+		expr.absolute = true
+		expr.steps = append(expr.steps, descendantOrSelfStep)
+		p.pos += 2
+
+	case p.hasPrefix("/"):
+		expr.absolute = true
+		p.pos++
+		if p.pos == len(p.s) || !p.atStepStart() {
+			return expr, nil
+		}
+	}
+
+	for {
+		step, err := p.parseStep()
+		if err != nil {
+			return nil, err
+		}
+		expr.steps = append(expr.steps, step)
+
+		switch {
+		case p.hasPrefix("//"):
+			expr.steps = append(expr.steps, descendantOrSelfStep)
+			p.pos += 2
+
+		case p.hasPrefix("/"):
+			p.pos++
+
+		default:
+			return expr, nil
+		}
+	}
+}
+
+func (p *xpathParser) atStepStart() bool {
+	if p.pos == len(p.s) {
+		return false
+	}
+	c := p.s[p.pos]
+	return c == '.' || c == '@' || c == '*' || isXPathNameChar(c)
+}
+
+func isXPathNameChar(c byte) bool {
+	return c == '_' || c == '-' || c == '.' || c == ':' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (p *xpathParser) parseName() string {
+	start := p.pos
+	for p.pos < len(p.s) && isXPathNameChar(p.s[p.pos]) {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *xpathParser) parseStep() (xpathStep, error) {
+	var step xpathStep
+
+	switch {
+	case p.hasPrefix(".."):
+		p.pos += 2
+		return xpathStep{axis: xpathParent, test: "node()"}, nil
+
+	case p.hasPrefix(".") && !p.hasPrefix(".."):
+		p.pos++
+		return xpathStep{axis: xpathSelf, test: "node()"}, nil
+
+	case p.hasPrefix("@"):
+		p.pos++
+		step.axis = xpathAttribute
+	}
+
+	switch {
+	case p.hasPrefix("*"):
+		p.pos++
+		step.test = "*"
+
+	default:
+		name := p.parseName()
+		if name == "" {
+			if p.pos == len(p.s) {
+				return step, p.errorf("unexpected end of expression")
+			}
+			return step, p.errorf("unexpected character %q", p.s[p.pos])
+		}
+		if p.hasPrefix("()") {
+			if step.axis == xpathAttribute || (name != "text" && name != "node") {
+				return step, p.errorf("unsupported function %s()", name)
+			}
+			p.pos += 2
+			name += "()"
+		}
+		step.test = name
+	}
+
+	for p.hasPrefix("[") {
+		p.pos++
+		p.skipSpaces()
+
+		pred, err := p.parsePredicate()
+		if err != nil {
+			return step, err
+		}
+
+		p.skipSpaces()
+		if !p.hasPrefix("]") {
+			return step, p.errorf("expected ']'")
+		}
+		p.pos++
+
+		step.preds = append(step.preds, pred)
+	}
+
+	return step, nil
+}
+
+func (p *xpathParser) parsePredicate() (xpathPred, error) {
+	var pred xpathPred
+
+	if p.hasPrefix("last()") {
+		p.pos += len("last()")
+		pred.index = -1
+		return pred, nil
+	}
+
+	if p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		start := p.pos
+		for p.pos < len(p.s) && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		n, err := strconv.Atoi(p.s[start:p.pos])
+		if err != nil || n == 0 {
+			return pred, p.errorf("invalid position %q", p.s[start:p.pos])
+		}
+		pred.index = n
+		return pred, nil
+	}
+
+	expr, err := p.parsePath()
+	if err != nil {
+		return pred, err
+	}
+	pred.expr = expr
+
+	p.skipSpaces()
+	if !p.hasPrefix("=") {
+		return pred, nil
+	}
+	p.pos++
+	p.skipSpaces()
+
+	if p.pos == len(p.s) || (p.s[p.pos] != '\'' && p.s[p.pos] != '"') {
+		return pred, p.errorf("expected string literal")
+	}
+
+	quote := p.s[p.pos]
+	end := strings.IndexByte(p.s[p.pos+1:], quote)
+	if end < 0 {
+		return pred, p.errorf("unterminated string literal")
+	}
+
+	value := p.s[p.pos+1 : p.pos+1+end]
+	pred.value = &value
+	p.pos += end + 2
+
+	return pred, nil
+}
+
+// evaluates expression with given context nodes; root is document node
+// used for absolute paths
+func (e *xpathExpr) eval(root *xmlNode, context []*xmlNode) []*xmlNode {
+	nodes := context
+	if e.absolute {
+		nodes = []*xmlNode{root}
+	}
+
+	for _, step := range e.steps {
+		seen := map[*xmlNode]bool{}
+
+		var next []*xmlNode
+
+		for _, node := range nodes {
+			for _, match := range step.eval(root, node) {
+				if !seen[match] {
+					seen[match] = true
+					next = append(next, match)
+				}
+			}
+		}
+
+		nodes = next
+	}
+
+	return nodes
+}
+
+func (s *xpathStep) eval(root *xmlNode, node *xmlNode) []*xmlNode {
+	var candidates []*xmlNode
+
+	switch s.axis {
+	case xpathChild:
+		candidates = node.children
+	case xpathDescendantOrSelf:
+		candidates = node.appendDescendants([]*xmlNode{node})
+	case xpathSelf:
+		candidates = []*xmlNode{node}
+	case xpathParent:
+		if node.parent != nil {
+			candidates = []*xmlNode{node.parent}
+		}
+	case xpathAttribute:
+		candidates = node.attrs
+	}
+
+	var matches []*xmlNode
+	for _, c := range candidates {
+		if s.matches(c) {
+			matches = append(matches, c)
+		}
+	}
+
+	for _, pred := range s.preds {
+		matches = pred.filter(root, matches)
+	}
+
+	return matches
+}
+
+func (s *xpathStep) matches(node *xmlNode) bool {
+	switch s.test {
+	case "node()":
+		return true
+	case "text()":
+		return node.kind == xmlTextNode
+	}
+
+	if s.axis == xpathAttribute {
+		if node.kind != xmlAttributeNode {
+			return false
+		}
+	} else if node.kind != xmlElementNode {
+		return false
+	}
+
+	if s.test == "*" {
+		return true
+	}
+
+	name := s.test
+	if i := strings.LastIndexByte(name, ':'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return node.name.Local == name
+}
+
+func (p *xpathPred) filter(root *xmlNode, nodes []*xmlNode) []*xmlNode {
+	switch {
+	case p.index == -1:
+		if len(nodes) == 0 {
+			return nil
+		}
+		return nodes[len(nodes)-1:]
+
+	case p.index > 0:
+		if p.index > len(nodes) {
+			return nil
+		}
+		return nodes[p.index-1 : p.index]
+	}
+
+	var result []*xmlNode
+
+	for _, node := range nodes {
+		for _, match := range p.expr.eval(root, []*xmlNode{node}) {
+			if p.value == nil || match.stringValue() == *p.value {
+				result = append(result, node)
+				break
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,111 @@
+package httpexpect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const xpathTestDocument = `<?xml version="1.0"?>
+<!-- orders -->
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <Orders xmlns="http://example.com/orders">
+      <Order id="1" status="new"><Id>41</Id><Total>1.5</Total></Order>
+      <Order id="2" status="done"><Id>42</Id><Total>2.5</Total></Order>
+      <Order id="3" status="new"><Id>43</Id><Total>3.5</Total></Order>
+    </Orders>
+  </soap:Body>
+</soap:Envelope>`
+
+func TestXPathParseXML(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		ok   bool
+	}{
+		{"valid", `<a><b/></a>`, true},
+		{"header", `<?xml version="1.0"?><a/>`, true},
+		{"empty", ``, false},
+		{"no root", `<?xml version="1.0"?>`, false},
+		{"multiple roots", `<a/><b/>`, false},
+		{"unclosed", `<a>`, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseXML([]byte(tc.data))
+			if tc.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestXPathParseErrors(t *testing.T) {
+	cases := []string{
+		"",
+		"a/",
+		"a[",
+		"a[1",
+		"a[0]",
+		"a[@id=1]",
+		"a[@id='1]",
+		"count(a)",
+		"a|b",
+		"@text()",
+	}
+
+	for _, expr := range cases {
+		t.Run(expr, func(t *testing.T) {
+			_, err := parseXPath(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestXPathEval(t *testing.T) {
+	root, err := parseXML([]byte(xpathTestDocument))
+	require.NoError(t, err)
+
+	cases := []struct {
+		expr   string
+		result []string
+	}{
+		{"//Order/Id", []string{"41", "42", "43"}},
+		{"/soap:Envelope/soap:Body/Orders/Order[2]/Id", []string{"42"}},
+		{"/Envelope/Body/Orders/Order[last()]/Id", []string{"43"}},
+		{"//Order[@status='new']/@id", []string{"1", "3"}},
+		{`//Order[Id="42"]/@status`, []string{"done"}},
+		{"//Order[@status = 'new'][2]/Id", []string{"43"}},
+		{"//Id[text()='41']/../Total", []string{"1.5"}},
+		{"//Order[@missing]", nil},
+		{"//Order[@id]/Id", []string{"41", "42", "43"}},
+		{"//Order[1]/*", []string{"41", "1.5"}},
+		{"//Order[1]/@*", []string{"1", "new"}},
+		{"//Order[3]/Id/text()", []string{"43"}},
+		{"//Order[3]/Id/.", []string{"43"}},
+		{"//Orders//Total", []string{"1.5", "2.5", "3.5"}},
+		{"//Id[1]", []string{"41", "42", "43"}},
+		{"//Order[5]", nil},
+		{"Envelope/Body/Orders/Order/Id", []string{"41", "42", "43"}},
+		{"/", []string{"\n  \n    \n      411.5\n      422.5\n      433.5\n    \n  \n"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.expr, func(t *testing.T) {
+			expr, err := parseXPath(tc.expr)
+			require.NoError(t, err)
+
+			var result []string
+			for _, node := range expr.eval(root, []*xmlNode{root}) {
+				result = append(result, node.stringValue())
+			}
+
+			assert.Equal(t, tc.result, result)
+		})
+	}
+}